@@ -9,23 +9,58 @@ import (
 )
 
 var (
-	databaseURL           string
-	redisURL              string
-	supabaseURL           string
-	jwtSecret             string
-	logLevel              string
-	logJSON               bool
-	battleNetClientID     string
-	battleNetClientSecret string
-	battleNetRedirectURI  string
-	stripeSecretKey       string
-	stripeWebhookSecret   string
-	stripePriceID         string
-	stripeSuccessURL      string
-	stripeCancelURL       string
-	stripePriceIDUSD      string
-	stripePriceIDEUR      string
-	stripePriceIDBRL      string
+	databaseURL              string
+	redisURL                 string
+	supabaseURL              string
+	jwtSecret                string
+	logLevel                 string
+	logJSON                  bool
+	battleNetClientID        string
+	battleNetClientSecret    string
+	battleNetRedirectURI     string
+	stripeSecretKey          string
+	stripeWebhookSecret      string
+	stripePriceID            string
+	stripeSuccessURL         string
+	stripeCancelURL          string
+	stripePriceIDUSD         string
+	stripePriceIDEUR         string
+	stripePriceIDBRL         string
+	premiumGiftExtends       bool
+	supabaseAnonKey          string
+	requireEmailVerified     bool
+	wishlistMatchWorkers     int
+	wishlistMatchGroupSecs   int
+	pauseListingOnAccept     bool
+	welcomeNotification      bool
+	relistCooldownHours      int
+	maxActiveServices        int
+	maxActiveServicesPremium int
+	abuseThrottleThreshold   int
+	abuseThrottleDelayHours  int
+	abuseChurnLimit          int
+	itemImageCheck           bool
+	itemImagePlaceholderURL  string
+	marketEvents             bool
+	maxNotesLength           int
+	maxDescriptionLength     int
+	maxCommentLength         int
+	chatArchiveGraceHours    int
+	featureFlags             string
+	runeValues               string
+	duplicateListings        string
+	listingLifetimeDays      int
+	premiumListingDays       int
+	expiryWarningHours       int
+	freeDailyBumps           int
+	premiumDailyBumps        int
+	sandboxMode              bool
+	notificationWebhookURL   string
+	notificationWebhookKey   string
+	viewFlushIntervalSeconds int
+	apiTokenRateLimit        int
+	imageWebPConversion      bool
+	responseTimeWindowDays   int
 )
 
 var rootCmd = &cobra.Command{
@@ -60,6 +95,41 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&stripePriceIDUSD, "stripe-price-id-usd", getEnvOrDefault("STRIPE_PRICE_ID_USD", ""), "Stripe price ID for USD")
 	rootCmd.PersistentFlags().StringVar(&stripePriceIDEUR, "stripe-price-id-eur", getEnvOrDefault("STRIPE_PRICE_ID_EUR", ""), "Stripe price ID for EUR")
 	rootCmd.PersistentFlags().StringVar(&stripePriceIDBRL, "stripe-price-id-brl", getEnvOrDefault("STRIPE_PRICE_ID_BRL", ""), "Stripe price ID for BRL")
+	rootCmd.PersistentFlags().BoolVar(&premiumGiftExtends, "premium-gift-extends", getEnvOrDefaultBool("PREMIUM_GIFT_EXTENDS", false), "Allow gifting premium to a user who already has it, starting the gift when their current period ends")
+	rootCmd.PersistentFlags().StringVar(&supabaseAnonKey, "supabase-anon-key", getEnvOrDefault("SUPABASE_ANON_KEY", ""), "Supabase anon key for auth API calls")
+	rootCmd.PersistentFlags().BoolVar(&requireEmailVerified, "require-email-verification", getEnvOrDefaultBool("REQUIRE_EMAIL_VERIFICATION", false), "Require a verified email to create listings and offers")
+	rootCmd.PersistentFlags().IntVar(&wishlistMatchWorkers, "wishlist-match-concurrency", getEnvOrDefaultInt("WISHLIST_MATCH_CONCURRENCY", 4), "Max listings matched against wishlists at once")
+	rootCmd.PersistentFlags().IntVar(&wishlistMatchGroupSecs, "wishlist-match-group-seconds", getEnvOrDefaultInt("WISHLIST_MATCH_GROUP_WINDOW_SECONDS", 60), "Seconds a user's wishlist matches are collected into one notification (0 sends each match)")
+	rootCmd.PersistentFlags().BoolVar(&pauseListingOnAccept, "pause-listing-on-accept", getEnvOrDefaultBool("PAUSE_LISTING_ON_ACCEPT", true), "Move a listing to pending while the trade from an accepted offer is active")
+	rootCmd.PersistentFlags().BoolVar(&imageWebPConversion, "image-webp-conversion", getEnvOrDefaultBool("IMAGE_WEBP_CONVERSION", false), "Convert uploaded PNG and JPEG avatars and listing images to WebP")
+	rootCmd.PersistentFlags().IntVar(&responseTimeWindowDays, "response-time-window-days", getEnvOrDefaultInt("SELLER_RESPONSE_TIME_WINDOW_DAYS", 30), "Days of offers counted toward a seller's median response time (0 stops refreshing it)")
+	rootCmd.PersistentFlags().BoolVar(&welcomeNotification, "welcome-notification", getEnvOrDefaultBool("WELCOME_NOTIFICATION_ENABLED", true), "Send new users a welcome notification on first login")
+	rootCmd.PersistentFlags().IntVar(&relistCooldownHours, "relist-cooldown-hours", getEnvOrDefaultInt("RELIST_COOLDOWN_HOURS", 0), "Hours a seller must wait to relist an item identical to one they sold (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&maxActiveServices, "max-active-services", getEnvOrDefaultInt("MAX_ACTIVE_SERVICES", 10), "Max active services per free provider")
+	rootCmd.PersistentFlags().IntVar(&maxActiveServicesPremium, "max-active-services-premium", getEnvOrDefaultInt("MAX_ACTIVE_SERVICES_PREMIUM", 30), "Max active services per premium provider")
+	rootCmd.PersistentFlags().IntVar(&abuseThrottleThreshold, "abuse-throttle-threshold", getEnvOrDefaultInt("ABUSE_THROTTLE_THRESHOLD", 10), "Abuse score at which a seller's listings are shadow-throttled (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&abuseThrottleDelayHours, "abuse-throttle-delay-hours", getEnvOrDefaultInt("ABUSE_THROTTLE_DELAY_HOURS", 24), "Hours a shadow-throttled seller's new listings are held out of recent listings")
+	rootCmd.PersistentFlags().IntVar(&abuseChurnLimit, "abuse-churn-limit", getEnvOrDefaultInt("ABUSE_CHURN_LIMIT", 10), "Listings a seller may cancel within an hour of creating them per day before each further one raises their abuse score (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&itemImageCheck, "item-image-check", getEnvOrDefaultBool("ITEM_IMAGE_CHECK_ENABLED", false), "Check generated item image URLs against storage and use a placeholder for missing ones")
+	rootCmd.PersistentFlags().StringVar(&itemImagePlaceholderURL, "item-image-placeholder-url", getEnvOrDefault("ITEM_IMAGE_PLACEHOLDER_URL", ""), "Image URL used for items missing from storage (default: d2-items/placeholder.png in Supabase storage)")
+	rootCmd.PersistentFlags().BoolVar(&marketEvents, "market-events", getEnvOrDefaultBool("MARKET_EVENTS_ENABLED", true), "Log a market value event for every completed trade and service run")
+	rootCmd.PersistentFlags().IntVar(&maxNotesLength, "max-notes-length", getEnvOrDefaultInt("MAX_NOTES_LENGTH", 500), "Max characters in listing and service notes")
+	rootCmd.PersistentFlags().IntVar(&maxDescriptionLength, "max-description-length", getEnvOrDefaultInt("MAX_DESCRIPTION_LENGTH", 2000), "Max characters in service descriptions")
+	rootCmd.PersistentFlags().IntVar(&maxCommentLength, "max-comment-length", getEnvOrDefaultInt("MAX_COMMENT_LENGTH", 500), "Max characters in rating comments")
+	rootCmd.PersistentFlags().IntVar(&chatArchiveGraceHours, "chat-archive-grace-hours", getEnvOrDefaultInt("CHAT_ARCHIVE_GRACE_HOURS", 24), "Hours a resolved trade or service run chat stays in the inbox before it is archived (0 archives immediately, negative never archives)")
+	rootCmd.PersistentFlags().StringVar(&featureFlags, "feature-flags", getEnvOrDefault("FEATURE_FLAGS", ""), "Feature rollout percentages, e.g. fuzzy_search=100,realtime_chat=25 (default: fuzzy_search=100)")
+	rootCmd.PersistentFlags().IntVar(&listingLifetimeDays, "listing-lifetime-days", getEnvOrDefaultInt("LISTING_LIFETIME_DAYS", 30), "Days a listing stays up before it expires")
+	rootCmd.PersistentFlags().IntVar(&premiumListingDays, "premium-listing-lifetime-days", getEnvOrDefaultInt("PREMIUM_LISTING_LIFETIME_DAYS", 60), "Days a premium seller's listing stays up before it expires")
+	rootCmd.PersistentFlags().IntVar(&expiryWarningHours, "listing-expiry-warning-hours", getEnvOrDefaultInt("LISTING_EXPIRY_WARNING_HOURS", 24), "Hours before expiry that sellers are warned a listing is about to expire")
+	rootCmd.PersistentFlags().IntVar(&freeDailyBumps, "free-daily-bumps", getEnvOrDefaultInt("FREE_DAILY_BUMPS", 1), "Listing refreshes a free seller gets per UTC day before spending bump credits")
+	rootCmd.PersistentFlags().IntVar(&premiumDailyBumps, "premium-daily-bumps", getEnvOrDefaultInt("PREMIUM_DAILY_BUMPS", 5), "Listing refreshes a premium seller gets per UTC day before spending bump credits")
+	rootCmd.PersistentFlags().BoolVar(&sandboxMode, "sandbox", getEnvOrDefaultBool("SANDBOX_MODE", false), "Fake Stripe calls, store uploads on local disk and skip push/email sends")
+	rootCmd.PersistentFlags().StringVar(&notificationWebhookURL, "notification-webhook-url", getEnvOrDefault("NOTIFICATION_WEBHOOK_URL", ""), "URL of the push/email relay notifications are posted to (empty disables out-of-app delivery)")
+	rootCmd.PersistentFlags().StringVar(&notificationWebhookKey, "notification-webhook-secret", getEnvOrDefault("NOTIFICATION_WEBHOOK_SECRET", ""), "Bearer token sent to the notification webhook")
+	rootCmd.PersistentFlags().IntVar(&viewFlushIntervalSeconds, "view-flush-interval", getEnvOrDefaultInt("VIEW_FLUSH_INTERVAL_SECONDS", 30), "Seconds between flushes of Redis-buffered listing views to the database (0 writes every view directly)")
+	rootCmd.PersistentFlags().IntVar(&apiTokenRateLimit, "api-token-rate-limit", getEnvOrDefaultInt("API_TOKEN_RATE_LIMIT", 60), "Requests per minute given to new personal access tokens")
+	rootCmd.PersistentFlags().StringVar(&runeValues, "rune-values", getEnvOrDefault("RUNE_VALUES", ""), "Rune value overrides in Ist, e.g. ber=9,jah=8 (unset runes keep the shipped values)")
+	rootCmd.PersistentFlags().StringVar(&duplicateListings, "duplicate-listings", getEnvOrDefault("DUPLICATE_LISTINGS", "allow"), "What creating a listing identical to one of the seller's active listings does: allow, reject or reuse")
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -201,6 +271,146 @@ func GetStripeAllowedPriceIDs() []string {
 	return ids
 }
 
+func GetSupabaseAnonKey() string {
+	return supabaseAnonKey
+}
+
+func GetRequireEmailVerification() bool {
+	return requireEmailVerified
+}
+
+func GetWishlistMatchConcurrency() int {
+	return wishlistMatchWorkers
+}
+
+func GetWishlistMatchGroupWindowSeconds() int {
+	return wishlistMatchGroupSecs
+}
+
+func GetPauseListingOnAccept() bool {
+	return pauseListingOnAccept
+}
+
+func GetPremiumGiftExtends() bool {
+	return premiumGiftExtends
+}
+
+func GetImageWebPConversion() bool {
+	return imageWebPConversion
+}
+
+func GetResponseTimeWindowDays() int {
+	return responseTimeWindowDays
+}
+
+func GetWelcomeNotificationEnabled() bool {
+	return welcomeNotification
+}
+
+func GetRelistCooldownHours() int {
+	return relistCooldownHours
+}
+
+func GetMaxActiveServices() int {
+	return maxActiveServices
+}
+
+func GetMaxActiveServicesPremium() int {
+	return maxActiveServicesPremium
+}
+
+func GetAbuseThrottleThreshold() int {
+	return abuseThrottleThreshold
+}
+
+func GetAbuseThrottleDelayHours() int {
+	return abuseThrottleDelayHours
+}
+
+func GetAbuseChurnLimit() int {
+	return abuseChurnLimit
+}
+
+func GetItemImageCheckEnabled() bool {
+	return itemImageCheck
+}
+
+func GetItemImagePlaceholderURL() string {
+	return itemImagePlaceholderURL
+}
+
+func GetMarketEventsEnabled() bool {
+	return marketEvents
+}
+
+func GetMaxNotesLength() int {
+	return maxNotesLength
+}
+
+func GetMaxDescriptionLength() int {
+	return maxDescriptionLength
+}
+
+func GetMaxCommentLength() int {
+	return maxCommentLength
+}
+
+func GetChatArchiveGraceHours() int {
+	return chatArchiveGraceHours
+}
+
+func GetFeatureFlags() string {
+	return featureFlags
+}
+
+func GetRuneValues() string {
+	return runeValues
+}
+
+func GetDuplicateListings() string {
+	return duplicateListings
+}
+
+func GetListingLifetimeDays() int {
+	return listingLifetimeDays
+}
+
+func GetPremiumListingLifetimeDays() int {
+	return premiumListingDays
+}
+
+func GetListingExpiryWarningHours() int {
+	return expiryWarningHours
+}
+
+func GetFreeDailyBumps() int {
+	return freeDailyBumps
+}
+
+func GetPremiumDailyBumps() int {
+	return premiumDailyBumps
+}
+
+func GetSandboxMode() bool {
+	return sandboxMode
+}
+
+func GetNotificationWebhookURL() string {
+	return notificationWebhookURL
+}
+
+func GetNotificationWebhookSecret() string {
+	return notificationWebhookKey
+}
+
+func GetViewFlushIntervalSeconds() int {
+	return viewFlushIntervalSeconds
+}
+
+func GetAPITokenRateLimit() int {
+	return apiTokenRateLimit
+}
+
 func PrintSuccess(msg string) {
 	fmt.Printf("✓ %s\n", msg)
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
@@ -39,6 +41,7 @@ Public Endpoints:
 Authenticated Endpoints:
   GET    /api/v1/me                    - Get current user profile
   PATCH  /api/v1/me                    - Update current user profile
+  POST   /api/v1/me/verification/resend - Resend email verification
   POST   /api/v1/listings              - Create listing
   PATCH  /api/v1/listings/:id          - Update listing
   DELETE /api/v1/listings/:id          - Cancel listing
@@ -118,7 +121,22 @@ func runServe(cmd *cobra.Command, args []string) error {
 	s3AccessKey := os.Getenv("SUPABASE_S3_ACCESS_KEY")
 	s3SecretKey := os.Getenv("SUPABASE_S3_SECRET_KEY")
 	var avatarStorage storage.Storage
-	if s3AccessKey != "" && s3SecretKey != "" {
+	var listingImageStorage storage.Storage
+	if GetSandboxMode() {
+		// Uploads stay on local disk so sandbox runs never touch the real buckets
+		sandboxDir := filepath.Join(os.TempDir(), "lootstash-sandbox")
+		avatarStorage, err = storage.NewLocalStorage(filepath.Join(sandboxDir, "avatars"), "")
+		if err != nil {
+			log.Error("failed to initialize sandbox storage", "error", err)
+			avatarStorage = nil
+		}
+		listingImageStorage, err = storage.NewLocalStorage(filepath.Join(sandboxDir, "listing-images"), "")
+		if err != nil {
+			log.Error("failed to initialize sandbox listing image storage", "error", err)
+			listingImageStorage = nil
+		}
+		log.Warn("sandbox mode enabled, external side effects are faked", "storage_dir", sandboxDir)
+	} else if s3AccessKey != "" && s3SecretKey != "" {
 		s3Endpoint := supabaseURL + "/storage/v1/s3"
 		s3Region := os.Getenv("SUPABASE_S3_REGION")
 		if s3Region == "" {
@@ -131,34 +149,88 @@ func runServe(cmd *cobra.Command, args []string) error {
 		} else {
 			log.Info("avatar storage initialized (S3)", "bucket", "avatars")
 		}
+
+		listingBucket := os.Getenv("SUPABASE_LISTING_IMAGES_BUCKET")
+		if listingBucket == "" {
+			listingBucket = "listing-images"
+		}
+		listingStorage, err := storage.NewS3Storage(s3Endpoint, s3AccessKey, s3SecretKey, s3Region, listingBucket, supabaseURL)
+		if err != nil {
+			log.Error("failed to initialize listing image storage", "error", err)
+		} else {
+			listingImageStorage = listingStorage
+			log.Info("listing image storage initialized (S3)", "bucket", listingBucket)
+		}
 	} else {
-		log.Warn("SUPABASE_S3_ACCESS_KEY or SUPABASE_S3_SECRET_KEY not set, avatar uploads will be disabled")
+		log.Warn("SUPABASE_S3_ACCESS_KEY or SUPABASE_S3_SECRET_KEY not set, avatar and listing image uploads will be disabled")
+	}
+
+	// Item images missing from storage fall back to a placeholder in the same bucket
+	itemImagePlaceholder := GetItemImagePlaceholderURL()
+	if itemImagePlaceholder == "" {
+		itemImagePlaceholder = supabaseURL + "/storage/v1/object/public/d2-items/placeholder.png"
 	}
 
 	// Create server config
 	authDebug := strings.ToLower(os.Getenv("AUTH_DEBUG")) == "true"
 	config := &api.Config{
-		Port:                  port,
-		AllowedOrigins:        allowedOrigins,
-		JWTSecret:             GetJWTSecret(),
-		JWKSURL:               supabaseURL + "/auth/v1/.well-known/jwks.json",
-		JWTAudience:           "authenticated",
-		JWTIssuer:             supabaseURL + "/auth/v1",
-		AuthDebug:             authDebug,
-		SupabaseURL:           supabaseURL,
-		BattleNetClientID:     GetBattleNetClientID(),
-		BattleNetClientSecret: GetBattleNetClientSecret(),
-		BattleNetRedirectURI:  GetBattleNetRedirectURI(),
-		StripeSecretKey:       GetStripeSecretKey(),
-		StripeWebhookSecret:   GetStripeWebhookSecret(),
-		StripePriceID:         GetStripePriceID(),
-		StripeSuccessURL:      GetStripeSuccessURL(),
-		StripeCancelURL:       GetStripeCancelURL(),
-		StripeAllowedPriceIDs: GetStripeAllowedPriceIDs(),
+		Port:                     port,
+		AllowedOrigins:           allowedOrigins,
+		JWTSecret:                GetJWTSecret(),
+		JWKSURL:                  supabaseURL + "/auth/v1/.well-known/jwks.json",
+		JWTAudience:              "authenticated",
+		JWTIssuer:                supabaseURL + "/auth/v1",
+		AuthDebug:                authDebug,
+		SupabaseURL:              supabaseURL,
+		BattleNetClientID:        GetBattleNetClientID(),
+		BattleNetClientSecret:    GetBattleNetClientSecret(),
+		BattleNetRedirectURI:     GetBattleNetRedirectURI(),
+		StripeSecretKey:          GetStripeSecretKey(),
+		StripeWebhookSecret:      GetStripeWebhookSecret(),
+		StripePriceID:            GetStripePriceID(),
+		StripeSuccessURL:         GetStripeSuccessURL(),
+		StripeCancelURL:          GetStripeCancelURL(),
+		StripeAllowedPriceIDs:    GetStripeAllowedPriceIDs(),
+		PremiumGiftExtends:       GetPremiumGiftExtends(),
+		SupabaseAnonKey:          GetSupabaseAnonKey(),
+		RequireEmailVerified:     GetRequireEmailVerification(),
+		WishlistMatchWorkers:     GetWishlistMatchConcurrency(),
+		WishlistMatchGroupWindow: time.Duration(GetWishlistMatchGroupWindowSeconds()) * time.Second,
+		PauseListingOnAccept:     GetPauseListingOnAccept(),
+		ImageWebPConversion:      GetImageWebPConversion(),
+		ResponseTimeWindow:       time.Duration(GetResponseTimeWindowDays()) * 24 * time.Hour,
+		WelcomeNotification:      GetWelcomeNotificationEnabled(),
+		RelistCooldown:           time.Duration(GetRelistCooldownHours()) * time.Hour,
+		MaxActiveServices:        GetMaxActiveServices(),
+		MaxActiveServicesPremium: GetMaxActiveServicesPremium(),
+		AbuseThrottleThreshold:   GetAbuseThrottleThreshold(),
+		AbuseThrottleDelay:       time.Duration(GetAbuseThrottleDelayHours()) * time.Hour,
+		AbuseChurnLimit:          GetAbuseChurnLimit(),
+		ItemImageCheck:           GetItemImageCheckEnabled(),
+		ItemImagePlaceholderURL:  itemImagePlaceholder,
+		MarketEvents:             GetMarketEventsEnabled(),
+		MaxNotesLength:           GetMaxNotesLength(),
+		MaxDescriptionLength:     GetMaxDescriptionLength(),
+		MaxCommentLength:         GetMaxCommentLength(),
+		ChatArchive:              GetChatArchiveGraceHours() >= 0,
+		ChatArchiveGrace:         time.Duration(GetChatArchiveGraceHours()) * time.Hour,
+		FeatureFlags:             GetFeatureFlags(),
+		RuneValues:               GetRuneValues(),
+		DuplicateListings:        GetDuplicateListings(),
+		ListingLifetime:          time.Duration(GetListingLifetimeDays()) * 24 * time.Hour,
+		PremiumListingLifetime:   time.Duration(GetPremiumListingLifetimeDays()) * 24 * time.Hour,
+		ExpiryWarningHours:       GetListingExpiryWarningHours(),
+		FreeDailyBumps:           GetFreeDailyBumps(),
+		PremiumDailyBumps:        GetPremiumDailyBumps(),
+		SandboxMode:              GetSandboxMode(),
+		DeliveryWebhookURL:       GetNotificationWebhookURL(),
+		DeliveryWebhookSecret:    GetNotificationWebhookSecret(),
+		ViewFlushInterval:        time.Duration(GetViewFlushIntervalSeconds()) * time.Second,
+		APITokenRateLimit:        GetAPITokenRateLimit(),
 	}
 
 	// Create and start server
-	server := api.NewServer(db, redisClient, avatarStorage, config)
+	server := api.NewServer(db, redisClient, avatarStorage, listingImageStorage, config)
 
 	// Handle graceful shutdown
 	shutdown := make(chan os.Signal, 1)
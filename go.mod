@@ -1,8 +1,9 @@
 module github.com/ruanpelissoli/lootstash-marketplace-api
 
-go 1.22
+go 1.22.2
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/alicebob/miniredis/v2 v2.36.1
 	github.com/aws/aws-sdk-go v1.55.8
 	github.com/go-playground/validator/v10 v10.22.1
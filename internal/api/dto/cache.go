@@ -0,0 +1,12 @@
+package dto
+
+// PurgeCacheRequest represents a request to purge every cache key matching a pattern
+type PurgeCacheRequest struct {
+	Pattern string `json:"pattern" validate:"required"`
+}
+
+// PurgeCacheResponse summarizes a pattern purge
+type PurgeCacheResponse struct {
+	Pattern string `json:"pattern"`
+	Purged  int64  `json:"purged"`
+}
@@ -9,6 +9,17 @@ type ChatResponse struct {
 	ServiceRunID string    `json:"serviceRunId,omitempty"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Archived chats are out of the inbox; still readable but closed to new messages.
+	// ArchivedAt is set once the trade or service run resolves and may be in the future.
+	Archived   bool       `json:"archived"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+}
+
+// ChatsFilterRequest represents filter parameters for the chat inbox
+type ChatsFilterRequest struct {
+	Archived bool `query:"archived"` // list archived chats instead of the inbox
+	Pagination
 }
 
 // ChatDetailResponse includes additional details for a single chat
@@ -27,3 +38,11 @@ type SendChatMessageRequest struct {
 type MarkChatMessagesReadRequest struct {
 	MessageIDs []string `json:"messageIds" validate:"required,min=1,dive,uuid"`
 }
+
+// ChatStreamEvent is an ephemeral event published on a chat's events channel
+type ChatStreamEvent struct {
+	Type   string    `json:"type"`
+	ChatID string    `json:"chatId"`
+	UserID string    `json:"userId"`
+	At     time.Time `json:"at"`
+}
@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
 // ErrorResponse represents an API error
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -7,34 +14,56 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 }
 
+// ValidationErrorResponse is an ErrorResponse that lists every invalid field
+type ValidationErrorResponse struct {
+	Error   string            `json:"error"`
+	Message string            `json:"message"`
+	Code    int               `json:"code"`
+	Fields  map[string]string `json:"fields"`
+}
+
+// Pagination defaults shared by every paginated list endpoint
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
 // Pagination contains pagination parameters
 type Pagination struct {
 	Page    int `json:"page" query:"page"`
 	PerPage int `json:"perPage" query:"perPage"`
 }
 
-// GetOffset returns the SQL offset for pagination
-func (p *Pagination) GetOffset() int {
+// Normalize clamps the parameters in place: pages below 1 become page 1, a
+// missing or non-positive perPage becomes DefaultPerPage, and anything above
+// MaxPerPage is capped
+func (p *Pagination) Normalize() {
 	if p.Page < 1 {
 		p.Page = 1
 	}
 	if p.PerPage < 1 {
-		p.PerPage = 20
+		p.PerPage = DefaultPerPage
 	}
-	if p.PerPage > 100 {
-		p.PerPage = 100
+	if p.PerPage > MaxPerPage {
+		p.PerPage = MaxPerPage
 	}
+}
+
+// GetPage returns the normalized page number
+func (p *Pagination) GetPage() int {
+	p.Normalize()
+	return p.Page
+}
+
+// GetOffset returns the SQL offset for pagination
+func (p *Pagination) GetOffset() int {
+	p.Normalize()
 	return (p.Page - 1) * p.PerPage
 }
 
 // GetLimit returns the SQL limit for pagination
 func (p *Pagination) GetLimit() int {
-	if p.PerPage < 1 {
-		return 20
-	}
-	if p.PerPage > 100 {
-		return 100
-	}
+	p.Normalize()
 	return p.PerPage
 }
 
@@ -66,6 +95,48 @@ func NewPaginatedResponse[T any](data []T, page, perPage, totalCount int) Pagina
 	}
 }
 
+// CursorResponse wraps cursor-paginated results
+type CursorResponse[T any] struct {
+	Data       []T    `json:"data"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewCursorResponse creates a new cursor response
+func NewCursorResponse[T any](data []T, hasMore bool, nextCursor string) CursorResponse[T] {
+	if !hasMore {
+		nextCursor = ""
+	}
+	return CursorResponse[T]{
+		Data:       data,
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+	}
+}
+
+// EncodeCursor builds an opaque keyset cursor from a row's created_at and id
+func EncodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor built by EncodeCursor
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", errors.New("malformed cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return createdAt, id, nil
+}
+
 // SuccessResponse represents a generic success response
 type SuccessResponse struct {
 	Success bool   `json:"success"`
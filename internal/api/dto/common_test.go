@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination_ClampsPerPage(t *testing.T) {
+	p := Pagination{Page: 2, PerPage: 100000}
+
+	assert.Equal(t, MaxPerPage, p.GetLimit())
+	assert.Equal(t, MaxPerPage, p.GetOffset())
+}
+
+func TestPagination_DefaultsPerPage(t *testing.T) {
+	for _, perPage := range []int{0, -5} {
+		p := Pagination{Page: 1, PerPage: perPage}
+
+		assert.Equal(t, DefaultPerPage, p.GetLimit(), perPage)
+	}
+}
+
+func TestPagination_NormalizesPage(t *testing.T) {
+	for _, page := range []int{0, -1, -100} {
+		p := Pagination{Page: page, PerPage: 10}
+
+		assert.Equal(t, 1, p.GetPage(), page)
+		assert.Equal(t, 0, p.GetOffset(), page)
+	}
+}
+
+func TestPagination_LimitWithoutOffset(t *testing.T) {
+	// Cursor endpoints only call GetLimit, which must clamp on its own
+	p := Pagination{PerPage: 500}
+
+	assert.Equal(t, MaxPerPage, p.GetLimit())
+	assert.Equal(t, 1, p.GetPage())
+}
+
+func TestNewPaginatedResponse_TotalPages(t *testing.T) {
+	resp := NewPaginatedResponse([]int{1, 2}, 1, 20, 41)
+
+	assert.Equal(t, 3, resp.TotalPages)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.FixedZone("BRT", -3*3600))
+
+	cursor := EncodeCursor(createdAt, "a1b2c3")
+	gotTime, gotID, err := DecodeCursor(cursor)
+
+	assert.NoError(t, err)
+	assert.True(t, createdAt.Equal(gotTime))
+	assert.Equal(t, "a1b2c3", gotID)
+	assert.NotContains(t, cursor, "a1b2c3", "cursor should be opaque")
+}
+
+func TestCursor_RejectsGarbage(t *testing.T) {
+	for _, cursor := range []string{"%%%", "bm9waXBl", EncodeCursor(time.Now(), "")} {
+		_, _, err := DecodeCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// GrantDelegateRequest sets the permissions a delegate holds over the owner's shop
+type GrantDelegateRequest struct {
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,oneof=manage_listings respond_offers"`
+}
+
+// DelegateResponse represents a permission grant to a delegate
+type DelegateResponse struct {
+	DelegateID  string    `json:"delegateId"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
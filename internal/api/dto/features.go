@@ -0,0 +1,6 @@
+package dto
+
+// FeatureFlagsResponse lists the feature flags that are on for the caller
+type FeatureFlagsResponse struct {
+	Flags []string `json:"flags"`
+}
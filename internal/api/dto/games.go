@@ -0,0 +1,15 @@
+package dto
+
+// CanonicalItem is a user-entered item name resolved against the game catalog.
+// When Found is false the other fields are empty; when LowConfidence is true the
+// name was matched fuzzily and should be confirmed before it's relied on.
+type CanonicalItem struct {
+	Query         string `json:"query"`
+	Found         bool   `json:"found"`
+	LowConfidence bool   `json:"lowConfidence"`
+	Name          string `json:"name,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Category      string `json:"category,omitempty"`
+	Rarity        string `json:"rarity,omitempty"`
+	ImageURL      string `json:"imageUrl,omitempty"`
+}
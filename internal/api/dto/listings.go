@@ -7,63 +7,133 @@ import (
 
 // ListingCardResponse represents a listing in card/list view (lightweight)
 type ListingCardResponse struct {
-	ID             string           `json:"id"`
-	SellerID       string           `json:"sellerId"`
-	Seller         *ProfileResponse `json:"seller,omitempty"`
-	Name           string           `json:"name"`
-	ItemType       string           `json:"itemType,omitempty"`
-	Rarity         string           `json:"rarity,omitempty"`
-	ImageURL       string           `json:"imageUrl,omitempty"`
-	Stats          []ItemStat       `json:"stats,omitempty"`
-	CatalogItemID  string           `json:"catalogItemId,omitempty"`
-	AskingFor      json.RawMessage  `json:"askingFor,omitempty"`
-	AskingPrice    string           `json:"askingPrice,omitempty"`
-	Amount         int              `json:"amount"`
-	Game           string           `json:"game"`
-	Ladder         bool             `json:"ladder"`
-	Hardcore       bool             `json:"hardcore"`
-	IsNonRotw      bool             `json:"isNonRotw"`
-	Platforms      []string         `json:"platforms"`
-	Region         string           `json:"region"`
-	SellerTimezone string           `json:"sellerTimezone,omitempty"`
-	Views          int              `json:"views"`
-	IsBoosted      bool             `json:"isBoosted"`
-	CreatedAt      time.Time        `json:"createdAt"`
+	ID               string           `json:"id"`
+	SellerID         string           `json:"sellerId"`
+	Seller           *ProfileResponse `json:"seller,omitempty"`
+	Name             string           `json:"name"`
+	ItemType         string           `json:"itemType,omitempty"`
+	Rarity           string           `json:"rarity,omitempty"`
+	ImageURL         string           `json:"imageUrl,omitempty"`
+	Stats            []ItemStat       `json:"stats,omitempty"`
+	StatsHidden      bool             `json:"statsHidden,omitempty"`
+	CatalogItemID    string           `json:"catalogItemId,omitempty"`
+	AskingFor        json.RawMessage  `json:"askingFor,omitempty"`
+	AskingPrice      string           `json:"askingPrice,omitempty"`
+	Amount           int              `json:"amount"`
+	Game             string           `json:"game"`
+	Ladder           bool             `json:"ladder"`
+	Hardcore         bool             `json:"hardcore"`
+	IsNonRotw        bool             `json:"isNonRotw"`
+	Platforms        []string         `json:"platforms"`
+	Region           string           `json:"region"`
+	SellerTimezone   string           `json:"sellerTimezone,omitempty"`
+	SellerOnline     bool             `json:"sellerOnline"`
+	SellerLastActive *time.Time       `json:"sellerLastActive,omitempty"`
+	Views            int              `json:"views"`
+	IsBoosted        bool             `json:"isBoosted"`
+	CreatedAt        time.Time        `json:"createdAt"`
+
+	// Item condition, when the seller gave it
+	Ethereal *bool   `json:"ethereal,omitempty"`
+	Sockets  *int    `json:"sockets,omitempty"`
+	Quality  *string `json:"quality,omitempty"`
+}
+
+// CreateListingResponse is the card of a newly created listing plus how long it stays up,
+// which is longer for premium sellers
+type CreateListingResponse struct {
+	ListingCardResponse
+	ExpiresAt    time.Time `json:"expiresAt"`
+	LifetimeDays int       `json:"lifetimeDays"`
+}
+
+// MyListingsResponse is a page of the seller's own listing cards plus their bump quota,
+// which is omitted when quotas aren't tracked
+type MyListingsResponse struct {
+	PaginatedResponse[ListingCardResponse]
+	Bumps *BumpQuotaResponse `json:"bumps,omitempty"`
+}
+
+// BumpQuotaResponse is how many listing refreshes a seller has left today, plus any
+// bump credits spent once the daily quota is used up
+type BumpQuotaResponse struct {
+	DailyLimit     int       `json:"dailyLimit"`
+	RemainingToday int       `json:"remainingToday"`
+	Credits        int       `json:"credits"`
+	ResetsAt       time.Time `json:"resetsAt"`
+}
+
+// GrantBumpCreditsRequest is an admin grant of extra listing bumps
+type GrantBumpCreditsRequest struct {
+	Amount int `json:"amount" validate:"required,min=1,max=50"`
+}
+
+// BumpCreditsResponse is a user's bump credit balance after a grant
+type BumpCreditsResponse struct {
+	UserID  string `json:"userId"`
+	Credits int    `json:"credits"`
+}
+
+// ListingSearchResponse is a page of listing cards plus any affix filters that were
+// ignored because they can't roll on the selected categories. Fuzzy is set when the
+// query matched nothing exactly and the cards are close ("did you mean") matches.
+type ListingSearchResponse struct {
+	PaginatedResponse[ListingCardResponse]
+	IgnoredFilters []string `json:"ignoredFilters,omitempty"`
+	Fuzzy          bool     `json:"fuzzy,omitempty"`
 }
 
 // ListingResponse represents a listing with full details
 type ListingResponse struct {
-	ID             string           `json:"id"`
-	SellerID       string           `json:"sellerId"`
-	Seller         *ProfileResponse `json:"seller,omitempty"`
-	Name           string           `json:"name"`
-	ItemType       string           `json:"itemType,omitempty"`
-	Rarity         string           `json:"rarity,omitempty"`
-	ImageURL       string           `json:"imageUrl,omitempty"`
-	Category       string           `json:"category,omitempty"`
-	Stats          []ItemStat       `json:"stats,omitempty"`
-	Suffixes       json.RawMessage  `json:"suffixes,omitempty"`
-	Runes          []RuneInfo       `json:"runes,omitempty"`
-	RuneOrder      string           `json:"runeOrder,omitempty"`
-	BaseItemCode   string           `json:"baseItemCode,omitempty"`
-	BaseItemName   string           `json:"baseItemName,omitempty"`
-	CatalogItemID  string           `json:"catalogItemId,omitempty"`
-	AskingFor      json.RawMessage  `json:"askingFor,omitempty"`
-	AskingPrice    string           `json:"askingPrice,omitempty"`
-	Amount         int              `json:"amount"`
-	Notes          string           `json:"notes,omitempty"`
-	Game           string           `json:"game"`
-	Ladder         bool             `json:"ladder"`
-	Hardcore       bool             `json:"hardcore"`
-	IsNonRotw      bool             `json:"isNonRotw"`
-	Platforms      []string         `json:"platforms"`
-	Region         string           `json:"region"`
-	SellerTimezone string           `json:"sellerTimezone,omitempty"`
-	Status         string           `json:"status"`
-	Views          int              `json:"views"`
-	IsBoosted      bool             `json:"isBoosted"`
-	CreatedAt      time.Time        `json:"createdAt"`
-	ExpiresAt      time.Time        `json:"expiresAt,omitempty"`
+	ID              string           `json:"id"`
+	SellerID        string           `json:"sellerId"`
+	Seller          *ProfileResponse `json:"seller,omitempty"`
+	Name            string           `json:"name"`
+	ItemType        string           `json:"itemType,omitempty"`
+	Rarity          string           `json:"rarity,omitempty"`
+	ImageURL        string           `json:"imageUrl,omitempty"`
+	Category        string           `json:"category,omitempty"`
+	Stats           []ItemStat       `json:"stats,omitempty"`
+	Suffixes        json.RawMessage  `json:"suffixes,omitempty"`
+	Runes           []RuneInfo       `json:"runes,omitempty"`
+	RuneOrder       string           `json:"runeOrder,omitempty"`
+	BaseItemCode    string           `json:"baseItemCode,omitempty"`
+	BaseItemName    string           `json:"baseItemName,omitempty"`
+	CatalogItemID   string           `json:"catalogItemId,omitempty"`
+	AskingFor       json.RawMessage  `json:"askingFor,omitempty"`
+	AskingPrice     string           `json:"askingPrice,omitempty"`
+	Amount          int              `json:"amount"`
+	Notes           string           `json:"notes,omitempty"`
+	Game            string           `json:"game"`
+	Ladder          bool             `json:"ladder"`
+	Hardcore        bool             `json:"hardcore"`
+	IsNonRotw       bool             `json:"isNonRotw"`
+	Platforms       []string         `json:"platforms"`
+	Region          string           `json:"region"`
+	SellerTimezone  string           `json:"sellerTimezone,omitempty"`
+	Status          string           `json:"status"`
+	Views           int              `json:"views"`
+	IsBoosted       bool             `json:"isBoosted"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	ExpiresAt       time.Time        `json:"expiresAt,omitempty"`
+	ReservedFor     string           `json:"reservedFor,omitempty"`
+	ReservedUntil   *time.Time       `json:"reservedUntil,omitempty"`
+	HideStatsOnCard bool             `json:"hideStatsOnCard"`
+
+	// BuyerRequirements is set when the seller only takes offers from buyers with enough reputation
+	BuyerRequirements *BuyerRequirementsResponse `json:"buyerRequirements,omitempty"`
+
+	// Item condition, when the seller gave it
+	Ethereal *bool   `json:"ethereal,omitempty"`
+	Sockets  *int    `json:"sockets,omitempty"`
+	Quality  *string `json:"quality,omitempty"`
+}
+
+// BuyerRequirementsResponse is the reputation a buyer needs before they can make an offer
+type BuyerRequirementsResponse struct {
+	MinRating    *float64 `json:"minRating,omitempty"`
+	MinTrades    *int     `json:"minTrades,omitempty"`
+	AllowUnrated bool     `json:"allowUnrated"`
 }
 
 // ListingDetailResponse represents a listing with full details
@@ -71,6 +141,15 @@ type ListingDetailResponse struct {
 	ListingResponse
 	UpdatedAt  time.Time `json:"updatedAt"`
 	TradeCount int       `json:"tradeCount"`
+	// MaxPendingOffers is the listing's pending offer cap; PendingOffersRemaining is the free
+	// capacity. Both are null for listings without a cap.
+	MaxPendingOffers       *int `json:"maxPendingOffers"`
+	PendingOffersRemaining *int `json:"pendingOffersRemaining"`
+
+	// Viewer flags, only populated for authenticated viewers
+	IsOwner         bool `json:"isOwner"`
+	HasPendingOffer bool `json:"hasPendingOffer"`
+	CanOffer        bool `json:"canOffer"`
 }
 
 // CreateListingRequest represents a request to create a listing
@@ -90,21 +169,51 @@ type CreateListingRequest struct {
 	AskingFor     json.RawMessage `json:"askingFor,omitempty"`
 	AskingPrice   string          `json:"askingPrice,omitempty" validate:"omitempty,max=100"`
 	Amount        *int            `json:"amount,omitempty" validate:"omitempty,min=1"`
-	Notes         string          `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Notes         string          `json:"notes,omitempty"`
 	Game          string          `json:"game" validate:"required,min=1,max=20"`
 	Ladder        bool            `json:"ladder"`
 	Hardcore      bool            `json:"hardcore"`
 	IsNonRotw     bool            `json:"isNonRotw"`
 	Platforms     []string        `json:"platforms" validate:"required,min=1,dive,oneof=pc xbox playstation switch"`
-	Region        string          `json:"region" validate:"required,oneof=americas europe asia"`
+	Region        string          `json:"region" validate:"required,max=50"`
+	// MaxPendingOffers caps pending offers on the listing; DefaultMaxPendingOffers is stored when omitted
+	MaxPendingOffers *int `json:"maxPendingOffers,omitempty" validate:"omitempty,min=1,max=100"`
+	// HideStatsOnCard leaves variable stats off the card view so buyers have to open the listing
+	HideStatsOnCard bool `json:"hideStatsOnCard"`
+
+	// Optional buyer reputation thresholds; AllowUnratedBuyers lets users with no ratings yet through
+	MinBuyerRating     *float64 `json:"minBuyerRating,omitempty" validate:"omitempty,min=1,max=5"`
+	MinBuyerTrades     *int     `json:"minBuyerTrades,omitempty" validate:"omitempty,min=1,max=10000"`
+	AllowUnratedBuyers bool     `json:"allowUnratedBuyers"`
+
+	// Optional item condition
+	Ethereal *bool   `json:"ethereal,omitempty"`
+	Sockets  *int    `json:"sockets,omitempty" validate:"omitempty,min=0,max=6"`
+	Quality  *string `json:"quality,omitempty" validate:"omitempty,oneof=inferior normal superior"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"omitempty,max=255"`
 }
 
 // UpdateListingRequest represents a request to update a listing
 type UpdateListingRequest struct {
 	AskingFor   json.RawMessage `json:"askingFor,omitempty"`
 	AskingPrice *string         `json:"askingPrice,omitempty" validate:"omitempty,max=100"`
-	Notes       *string         `json:"notes,omitempty" validate:"omitempty,max=500"`
-	Status      *string         `json:"status,omitempty" validate:"omitempty,oneof=active cancelled"`
+	Notes       *string         `json:"notes,omitempty"`
+	Status      *string         `json:"status,omitempty" validate:"omitempty,oneof=active paused cancelled"`
+
+	MaxPendingOffers *int  `json:"maxPendingOffers,omitempty" validate:"omitempty,min=1,max=100"`
+	HideStatsOnCard  *bool `json:"hideStatsOnCard,omitempty"`
+
+	// Buyer reputation thresholds; 0 removes a threshold
+	MinBuyerRating     *float64 `json:"minBuyerRating,omitempty" validate:"omitempty,min=0,max=5"`
+	MinBuyerTrades     *int     `json:"minBuyerTrades,omitempty" validate:"omitempty,min=0,max=10000"`
+	AllowUnratedBuyers *bool    `json:"allowUnratedBuyers,omitempty"`
+
+	// Item condition; changing it makes pending offers show listingChanged
+	Ethereal *bool   `json:"ethereal,omitempty"`
+	Sockets  *int    `json:"sockets,omitempty" validate:"omitempty,min=0,max=6"`
+	Quality  *string `json:"quality,omitempty" validate:"omitempty,oneof=inferior normal superior"`
 }
 
 // RefreshListingRequest represents a request to refresh (bump) a listing
@@ -112,24 +221,58 @@ type RefreshListingRequest struct {
 	AskingFor json.RawMessage `json:"askingFor,omitempty"`
 }
 
+// ReserveListingRequest represents a request to hold a listing for a specific buyer
+type ReserveListingRequest struct {
+	UserID string    `json:"userId" validate:"required,uuid"`
+	Until  time.Time `json:"until" validate:"required"`
+}
+
+// AttachListingImageRequest represents a request to set a listing image from a remote URL
+type AttachListingImageRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048"`
+}
+
+// WishlistMatchPreviewResponse reports how many buyers a draft listing would match
+type WishlistMatchPreviewResponse struct {
+	Count int `json:"count"`
+}
+
+// ExpireStaleListingsResponse summarizes an expire-stale run
+type ExpireStaleListingsResponse struct {
+	Expired  int `json:"expired"`
+	Released int `json:"released"`
+}
+
+// NotifyExpiringListingsResponse summarizes a notify-expiring run
+type NotifyExpiringListingsResponse struct {
+	Warned int `json:"warned"`
+}
+
 // ListingFilterRequest represents listing filter parameters
 type ListingFilterRequest struct {
-	SellerID         string `query:"sellerId"`
-	Q                string `query:"q"`
-	CatalogItemID    string `query:"catalogItemId"`
-	Game             string `query:"game"`
-	Ladder           *bool  `query:"ladder"`
-	Hardcore         *bool  `query:"hardcore"`
-	IsNonRotw        *bool  `query:"isNonRotw"`
-	Platforms        string `query:"platforms"`
-	Region           string `query:"region"`
-	Categories       string `query:"categories"`
-	Rarity           string `query:"rarity"`
-	AffixFilters     string `query:"affixFilters"`
-	AskingForFilters string `query:"askingForFilters"`
-	SortBy           string `query:"sortBy"`
-	SortOrder        string `query:"sortOrder"`
+	SellerID          string `query:"sellerId"`
+	Q                 string `query:"q"`
+	CatalogItemID     string `query:"catalogItemId"`
+	Game              string `query:"game"`
+	Ladder            *bool  `query:"ladder"`
+	Hardcore          *bool  `query:"hardcore"`
+	IsNonRotw         *bool  `query:"isNonRotw"`
+	Platforms         string `query:"platforms"`
+	Region            string `query:"region"`
+	Categories        string `query:"categories"`
+	Rarity            string `query:"rarity"`
+	AffixFilters      string `query:"affixFilters"`
+	AskingForFilters  string `query:"askingForFilters"`
+	ActiveWithinHours int    `query:"activeWithinHours"`
+	SortBy            string `query:"sortBy"`
+	SortOrder         string `query:"sortOrder"`
 	Pagination
+
+	// Item condition filters
+	Ethereal   *bool  `query:"ethereal"`
+	MinSockets *int   `query:"minSockets"`
+	MaxSockets *int   `query:"maxSockets"`
+	Quality    string `query:"quality"`
 }
 
 // AffixFilter represents a filter for item affixes
@@ -184,6 +327,12 @@ type SearchListingsRequest struct {
 	SortOrder       string           `json:"sortOrder"`
 	Page            int              `json:"page"`
 	PerPage         int              `json:"perPage"`
+
+	// Item condition filters
+	Ethereal   *bool  `json:"ethereal"`
+	MinSockets *int   `json:"minSockets"`
+	MaxSockets *int   `json:"maxSockets"`
+	Quality    string `json:"quality"`
 }
 
 // MyListingsFilterRequest represents filter parameters for user's own listings
@@ -10,13 +10,23 @@ type MessageResponse struct {
 	Sender      *ProfileResponse `json:"sender,omitempty"`
 	Content     string           `json:"content"`
 	MessageType string           `json:"messageType"`
-	ReadAt      *time.Time       `json:"readAt,omitempty"`
-	CreatedAt   time.Time        `json:"createdAt"`
+	// AttachmentURL and AttachmentType are set when the message is an image
+	AttachmentURL  *string    `json:"attachmentUrl,omitempty"`
+	AttachmentType *string    `json:"attachmentType,omitempty"`
+	ReadAt         *time.Time `json:"readAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
 }
 
 // MessagesFilterRequest represents filter parameters for messages
 type MessagesFilterRequest struct {
-	After  *time.Time `query:"after"`
-	Before *time.Time `query:"before"`
+	After    *time.Time `query:"after"`
+	Before   *time.Time `query:"before"`
+	BeforeID string     `query:"beforeId" validate:"omitempty,uuid"`
+	Latest   bool       `query:"latest"`
 	Pagination
 }
+
+// IsCursor returns true if the request uses cursor paging instead of page offsets
+func (f *MessagesFilterRequest) IsCursor() bool {
+	return f.BeforeID != "" || f.Latest
+}
@@ -23,7 +23,10 @@ type TradeResponse struct {
 	Seller        *ProfileResponse      `json:"seller,omitempty"`
 	BuyerID       string                `json:"buyerId"`
 	Buyer         *ProfileResponse      `json:"buyer,omitempty"`
+	Role          string                `json:"role,omitempty"`         // viewer's side of the trade: seller or buyer
+	Counterparty  *ProfileResponse      `json:"counterparty,omitempty"` // the other party, from the viewer's side
 	OfferedItems  []OfferedItemResponse `json:"offeredItems,omitempty"`
+	RequestedAddition []OfferedItemResponse `json:"requestedAddition,omitempty"` // extra items the buyer asked to have included
 	Status        string                `json:"status"`
 	CancelReason  string           `json:"cancelReason,omitempty"`
 	CancelledBy   string           `json:"cancelledBy,omitempty"`
@@ -36,23 +39,55 @@ type TradeResponse struct {
 	CancelledAt   *time.Time       `json:"cancelledAt,omitempty"`
 }
 
+// TradeChecklistItemResponse is one offered item on a trade's received checklist
+type TradeChecklistItemResponse struct {
+	Index           int    `json:"index"`
+	Name            string `json:"name"`
+	Quantity        int    `json:"quantity"`
+	SellerConfirmed bool   `json:"sellerConfirmed"`
+	BuyerConfirmed  bool   `json:"buyerConfirmed"`
+}
+
 // TradeDetailResponse includes additional details for a single trade
 type TradeDetailResponse struct {
 	TradeResponse
-	CanComplete bool `json:"canComplete"`
-	CanCancel   bool `json:"canCancel"`
-	CanMessage  bool `json:"canMessage"`
+	CanComplete       bool                         `json:"canComplete"`
+	CanCancel         bool                         `json:"canCancel"`
+	CanMessage        bool                         `json:"canMessage"`
+	Checklist         []TradeChecklistItemResponse `json:"checklist,omitempty"`
+	ChecklistComplete bool                         `json:"checklistComplete"` // both parties ticked every item; suggest completing
 }
 
 // TradesFilterRequest represents filter parameters for trades
 type TradesFilterRequest struct {
-	Status string `query:"status"` // active, completed, cancelled
+	Status         string `query:"status"`         // active, completed, cancelled
+	Role           string `query:"role"`           // buyer, seller, all
+	CounterpartyID string `query:"counterpartyId"` // Filter by the other party's profile ID
 	Pagination
 }
 
 // CancelTradeRequest represents a request to cancel a trade
 type CancelTradeRequest struct {
 	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
+	// Relist lets the seller put the listing back as a fresh listing (new expiry, top of recent)
+	// instead of just reactivating it. Ignored when the buyer cancels.
+	Relist bool `json:"relist,omitempty"`
+}
+
+// ToggleItemReceivedRequest ticks or unticks an offered item on the trade checklist
+type ToggleItemReceivedRequest struct {
+	Received bool `json:"received"`
+}
+
+// CompleteTradeRequest represents a confirmed request to complete a trade
+type CompleteTradeRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
+
+// CompletionTokenResponse contains a short-lived token confirming a trade completion
+type CompletionTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 // CompleteTradeResponse represents the response when completing a trade
@@ -60,3 +95,13 @@ type CompleteTradeResponse struct {
 	Trade         *TradeResponse `json:"trade"`
 	TransactionID string         `json:"transactionId"`
 }
+
+// ReconcileTransactionsRequest represents parameters for the transaction reconciliation job
+type ReconcileTransactionsRequest struct {
+	Since string `query:"since" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // RFC3339, defaults to 7 days ago
+}
+
+// ReconcileTransactionsResponse represents the result of a reconciliation run
+type ReconcileTransactionsResponse struct {
+	Created int `json:"created"`
+}
@@ -29,9 +29,68 @@ type MarkNotificationsReadRequest struct {
 	NotificationIDs []string `json:"notificationIds" validate:"required,min=1,dive,uuid"`
 }
 
+// MarkNotificationsReadByReferenceRequest represents a request to mark every notification about one entity as read
+type MarkNotificationsReadByReferenceRequest struct {
+	ReferenceType string `json:"referenceType" validate:"required,oneof=offer trade chat listing service_run transaction"`
+	ReferenceID   string `json:"referenceId" validate:"required,uuid"`
+}
+
+// MarkNotificationsReadByReferenceResponse reports how many notifications were marked as read
+type MarkNotificationsReadByReferenceResponse struct {
+	Marked int `json:"marked"`
+}
+
+// NotificationPreferencesResponse lists each notification type a user can turn off and whether it is on
+type NotificationPreferencesResponse struct {
+	Types map[string]bool `json:"types"`
+}
+
+// UpdateNotificationPreferencesRequest turns notification types on or off; types left out are unchanged
+type UpdateNotificationPreferencesRequest struct {
+	Types map[string]bool `json:"types" validate:"required,min=1"`
+}
+
 // NotificationsFilterRequest represents filter parameters for notifications
 type NotificationsFilterRequest struct {
 	Unread *bool `query:"unread"`
 	Type   string `query:"type"`
+	Cursor string `query:"cursor"`
+	Latest bool   `query:"latest"`
 	Pagination
 }
+
+// IsCursor returns true if the request uses cursor paging instead of page offsets
+func (f *NotificationsFilterRequest) IsCursor() bool {
+	return f.Cursor != "" || f.Latest
+}
+
+// BroadcastNotificationRequest represents an admin announcement sent to an audience
+type BroadcastNotificationRequest struct {
+	Audience string `json:"audience" validate:"required,oneof=all premium active-sellers"`
+	Title    string `json:"title" validate:"required,min=1,max=100"`
+	Body     string `json:"body" validate:"required,min=1,max=1000"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"omitempty,max=255"`
+}
+
+// BroadcastJobResponse is an announcement broadcast and its progress
+type BroadcastJobResponse struct {
+	ID         string     `json:"id"`
+	Audience   string     `json:"audience"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	Targeted   int        `json:"targeted"`
+	Sent       int        `json:"sent"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// NotificationStreamEvent is a live event pushed over the notification stream
+type NotificationStreamEvent struct {
+	Type         string                `json:"type"`
+	UnreadCount  *int                  `json:"unreadCount,omitempty"`
+	Notification *NotificationResponse `json:"notification,omitempty"`
+}
@@ -7,24 +7,32 @@ import (
 
 // OfferResponse represents an offer
 type OfferResponse struct {
-	ID            string                 `json:"id"`
-	Type          string                 `json:"type"`
-	ListingID     string                 `json:"listingId,omitempty"`
-	Listing       *ListingResponse       `json:"listing,omitempty"`
-	ServiceID     string                 `json:"serviceId,omitempty"`
-	Service       *ServiceResponse       `json:"service,omitempty"`
-	RequesterID   string                 `json:"requesterId"`
-	Requester     *ProfileResponse       `json:"requester,omitempty"`
-	OfferedItems  json.RawMessage        `json:"offeredItems"`
-	Message       string                 `json:"message,omitempty"`
-	Status        string                 `json:"status"`
-	DeclineReason *DeclineReasonResponse `json:"declineReason,omitempty"`
-	DeclineNote   string                 `json:"declineNote,omitempty"`
-	TradeID       *string                `json:"tradeId,omitempty"`
-	ServiceRunID  *string                `json:"serviceRunId,omitempty"`
-	CreatedAt     time.Time              `json:"createdAt"`
-	UpdatedAt     time.Time              `json:"updatedAt"`
-	AcceptedAt    *time.Time             `json:"acceptedAt,omitempty"`
+	ID             string                 `json:"id"`
+	Type           string                 `json:"type"`
+	ListingID      string                 `json:"listingId,omitempty"`
+	Listing        *ListingResponse       `json:"listing,omitempty"`
+	ServiceID      string                 `json:"serviceId,omitempty"`
+	Service        *ServiceResponse       `json:"service,omitempty"`
+	RequesterID    string                 `json:"requesterId"`
+	Requester      *ProfileResponse       `json:"requester,omitempty"`
+	OfferedItems   json.RawMessage        `json:"offeredItems"`
+	EstimatedValue *float64               `json:"estimatedValue,omitempty"` // Offered items value in Ist equivalents
+	Message        string                 `json:"message,omitempty"`
+	Status         string                 `json:"status"`
+	DeclineReason  *DeclineReasonResponse `json:"declineReason,omitempty"`
+	DeclineNote    string                 `json:"declineNote,omitempty"`
+	TradeID        *string                `json:"tradeId,omitempty"`
+	ServiceRunID   *string                `json:"serviceRunId,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	AcceptedAt     *time.Time             `json:"acceptedAt,omitempty"`
+	ListingChanged bool                   `json:"listingChanged,omitempty"` // Pending offer's listing was edited to a different item
+
+	// RequestedAddition lists extra items the buyer asked the seller to include
+	RequestedAddition json.RawMessage `json:"requestedAddition,omitempty"`
+
+	// ParentOfferID is set on counteroffers: the offer the seller/provider countered
+	ParentOfferID *string `json:"parentOfferId,omitempty"`
 }
 
 // OfferDetailResponse includes additional details for a single offer
@@ -39,12 +47,96 @@ type CreateOfferRequest struct {
 	ServiceID    *string         `json:"serviceId,omitempty" validate:"omitempty,uuid"`
 	OfferedItems json.RawMessage `json:"offeredItems" validate:"required"`
 	Message      string          `json:"message,omitempty" validate:"omitempty,max=500"`
+
+	// RequestedAddition optionally lists extra items the buyer wants included (item offers only)
+	RequestedAddition json.RawMessage `json:"requestedAddition,omitempty"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"omitempty,max=255"`
 }
 
-// RejectOfferRequest represents a request to reject an offer
+// QuickOfferRequest represents a request to make an offer from a saved offer template.
+// TargetID is the listing or service the offer is for.
+type QuickOfferRequest struct {
+	TemplateID string `json:"templateId" validate:"required,uuid"`
+	TargetID   string `json:"targetId" validate:"required,uuid"`
+}
+
+// RejectOfferRequest represents a request to reject an offer.
+// DeclineTemplateID fills DeclineNote from one of the seller's saved templates.
 type RejectOfferRequest struct {
-	DeclineReasonID int    `json:"declineReasonId" validate:"required,min=1"`
-	DeclineNote     string `json:"declineNote,omitempty" validate:"omitempty,max=200"`
+	DeclineReasonID   int     `json:"declineReasonId" validate:"required,min=1"`
+	DeclineNote       string  `json:"declineNote,omitempty" validate:"omitempty,max=200,excluded_with=DeclineTemplateID"`
+	DeclineTemplateID *string `json:"declineTemplateId,omitempty" validate:"omitempty,uuid"`
+}
+
+// BulkRejectOffersRequest rejects several offers with the same decline reason and note
+type BulkRejectOffersRequest struct {
+	OfferIDs []string `json:"offerIds" validate:"required,min=1,max=50,dive,uuid"`
+	RejectOfferRequest
+}
+
+// BulkRejectResult reports what happened to one offer of a bulk reject
+type BulkRejectResult struct {
+	OfferID string         `json:"offerId"`
+	Status  string         `json:"status"` // rejected, not_found, forbidden, not_pending, failed
+	Offer   *OfferResponse `json:"offer,omitempty"`
+}
+
+// BulkRejectOffersResponse summarizes a bulk reject
+type BulkRejectOffersResponse struct {
+	Rejected int                `json:"rejected"`
+	Failed   int                `json:"failed"`
+	Results  []BulkRejectResult `json:"results"`
+}
+
+// CounterOfferRequest represents a seller/provider's counter to a pending offer.
+// OfferedItems is what they would accept from the requester instead.
+type CounterOfferRequest struct {
+	OfferedItems json.RawMessage `json:"offeredItems" validate:"required"`
+	Message      string          `json:"message,omitempty" validate:"omitempty,max=500"`
+}
+
+// CreateDeclineTemplateRequest represents a request to save a decline template
+type CreateDeclineTemplateRequest struct {
+	Name    string `json:"name" validate:"required,min=1,max=50"`
+	Message string `json:"message" validate:"required,min=1,max=200"`
+}
+
+// UpdateDeclineTemplateRequest represents a request to update a decline template
+type UpdateDeclineTemplateRequest struct {
+	Name    *string `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
+	Message *string `json:"message,omitempty" validate:"omitempty,min=1,max=200"`
+}
+
+// DeclineTemplateResponse represents a seller's saved decline template
+type DeclineTemplateResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CreateOfferTemplateRequest represents a request to save an offer template
+type CreateOfferTemplateRequest struct {
+	Name         string          `json:"name" validate:"required,min=1,max=50"`
+	OfferedItems json.RawMessage `json:"offeredItems" validate:"required"`
+}
+
+// UpdateOfferTemplateRequest represents a request to update an offer template
+type UpdateOfferTemplateRequest struct {
+	Name         *string         `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
+	OfferedItems json.RawMessage `json:"offeredItems,omitempty"`
+}
+
+// OfferTemplateResponse represents a buyer's saved offer template
+type OfferTemplateResponse struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	OfferedItems json.RawMessage `json:"offeredItems"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
 }
 
 // DeclineReasonResponse represents a decline reason
@@ -56,14 +148,29 @@ type DeclineReasonResponse struct {
 
 // OffersFilterRequest represents filter parameters for offers
 type OffersFilterRequest struct {
-	Status    string `query:"status"`    // pending, accepted, rejected, cancelled
+	Status    string `query:"status"`    // pending, accepted, rejected, countered, cancelled
 	Role      string `query:"role"`      // buyer, seller, all
 	Type      string `query:"type"`      // item, service, all
 	ListingID string `query:"listingId"` // Filter by listing ID
 	ServiceID string `query:"serviceId"` // Filter by service ID
+	SortBy    string `query:"sortBy"`    // value (seller role only); default newest first
+	Cursor    string `query:"cursor"`    // Opaque cursor from a previous page's nextCursor
+	Latest    bool   `query:"latest"`    // Start cursor paging from the newest offer
 	Pagination
 }
 
+// IsCursor returns true if the request uses cursor paging instead of page offsets
+func (f *OffersFilterRequest) IsCursor() bool {
+	return f.Cursor != "" || f.Latest
+}
+
+// ListingWithOffers is one of the seller's listings with the offers made on it
+type ListingWithOffers struct {
+	Listing    ListingCardResponse `json:"listing"`
+	OfferCount int                 `json:"offerCount"`
+	Offers     []OfferResponse     `json:"offers"`
+}
+
 // AcceptOfferResponse represents the response when accepting an offer
 type AcceptOfferResponse struct {
 	Offer        *OfferResponse `json:"offer"`
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UpdateFlairRequest represents a request to update profile flair
 type UpdateFlairRequest struct {
 	Flair string `json:"flair" validate:"required,oneof=none gold flame ice necro royal"`
@@ -10,9 +12,12 @@ type UpdateUsernameColorRequest struct {
 	Color string `json:"color" validate:"required"`
 }
 
-// ListingCountResponse contains the count of active listings
+// ListingCountResponse contains the count of active listings.
+// Limit and RemainingListings are omitted for premium users, who have no limit.
 type ListingCountResponse struct {
-	Count int `json:"count"`
+	Count             int  `json:"count"`
+	Limit             *int `json:"limit,omitempty"`
+	RemainingListings *int `json:"remainingListings,omitempty"`
 }
 
 // PriceHistoryTrade represents a single trade's offered items
@@ -30,3 +35,20 @@ type PriceHistoryDay struct {
 type PriceHistoryResponse struct {
 	Data []PriceHistoryDay `json:"data"`
 }
+
+// PriceSummaryBundle is a set of offered items and the number of trades paid with it
+type PriceSummaryBundle struct {
+	Items []OfferedItemResponse `json:"items"`
+	Count int                   `json:"count"`
+}
+
+// ItemPriceSummaryResponse is a coarse typical-price summary for an item
+type ItemPriceSummaryResponse struct {
+	ItemName     string                `json:"itemName"`
+	Game         string                `json:"game,omitempty"`
+	Days         int                   `json:"days"`
+	SampleSize   int                   `json:"sampleSize"`
+	MedianBundle []OfferedItemResponse `json:"medianBundle"`
+	ModeBundle   *PriceSummaryBundle   `json:"modeBundle,omitempty"`
+	LastUpdated  time.Time             `json:"lastUpdated"`
+}
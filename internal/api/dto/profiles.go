@@ -17,9 +17,20 @@ type ProfileResponse struct {
 	ProfileFlair  string    `json:"profileFlair,omitempty"`
 	UsernameColor string    `json:"usernameColor,omitempty"`
 	Timezone      string    `json:"timezone,omitempty"`
+	ResponseTime  string    `json:"responseTime"` // Median time to answer offers (45m, 3h, 2d) or "new"
 	CreatedAt     time.Time `json:"createdAt"`
 }
 
+// BatchProfilesRequest asks for many public profiles by ID
+type BatchProfilesRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// BatchProfilesResponse lists profiles in request order; unknown IDs are left out
+type BatchProfilesResponse struct {
+	Profiles []*ProfileResponse `json:"profiles"`
+}
+
 // MyProfileResponse represents the current user's profile with additional fields
 type MyProfileResponse struct {
 	ProfileResponse
@@ -30,9 +41,31 @@ type MyProfileResponse struct {
 	PreferredPlatforms []string   `json:"preferredPlatforms,omitempty"`
 	PreferredRegion    string     `json:"preferredRegion,omitempty"`
 	PreferredNonRotw   *bool      `json:"preferredNonRotw,omitempty"`
+	QuietHoursStart    string     `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd      string     `json:"quietHoursEnd,omitempty"`
+	EmailVerified      bool       `json:"emailVerified"`
 	UpdatedAt          time.Time  `json:"updatedAt"`
 }
 
+// AdjustAbuseScoreRequest represents an admin adjustment to a user's abuse score
+type AdjustAbuseScoreRequest struct {
+	Delta  int    `json:"delta" validate:"required,min=-100,max=100"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// AbuseScoreResponse reports a user's abuse score after an adjustment
+type AbuseScoreResponse struct {
+	ProfileID       string `json:"profileId"`
+	AbuseScore      int    `json:"abuseScore"`
+	ShadowThrottled bool   `json:"shadowThrottled"`
+}
+
+// BadgeCounts holds the current user's unread counts for the navbar badges
+type BadgeCounts struct {
+	Notifications int `json:"notifications"`
+	Messages      int `json:"messages"`
+}
+
 // UpdateProfileRequest represents a profile update request
 type UpdateProfileRequest struct {
 	DisplayName        *string  `json:"displayName" validate:"omitempty,min=1,max=50"`
@@ -43,6 +76,8 @@ type UpdateProfileRequest struct {
 	PreferredPlatforms []string `json:"preferredPlatforms" validate:"omitempty,dive,oneof=pc xbox playstation switch"`
 	PreferredRegion    *string  `json:"preferredRegion" validate:"omitempty,oneof=americas europe asia"`
 	PreferredNonRotw   *bool    `json:"preferredNonRotw"`
+	QuietHoursStart    *string  `json:"quietHoursStart" validate:"omitempty,datetime=15:04"`
+	QuietHoursEnd      *string  `json:"quietHoursEnd" validate:"omitempty,datetime=15:04"`
 }
 
 // UploadPictureResponse represents the response after uploading a profile picture
@@ -131,3 +166,32 @@ func (r *SalesFilterRequest) GetOffset() int {
 	}
 	return r.Offset
 }
+
+// SalesExportRequest represents the date range for a CSV sales export
+type SalesExportRequest struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"` // inclusive, open when empty
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`   // inclusive, open when empty
+}
+
+// CreateAPITokenRequest represents a request to create a personal access token
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=50"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=listings:read stats:read"`
+}
+
+// APITokenResponse represents a personal access token without its secret
+type APITokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rateLimit"` // requests per minute
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPITokenResponse carries the token itself, which is only ever shown here
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
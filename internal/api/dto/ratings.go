@@ -9,6 +9,7 @@ type RatingResponse struct {
 	RaterID       string           `json:"raterId"`
 	Rater         *ProfileResponse `json:"rater,omitempty"`
 	RatedID       string           `json:"ratedId"`
+	Rated         *ProfileResponse `json:"rated,omitempty"`
 	Stars         int              `json:"stars"`
 	Comment       string           `json:"comment,omitempty"`
 	CreatedAt     time.Time        `json:"createdAt"`
@@ -18,7 +19,7 @@ type RatingResponse struct {
 type CreateRatingRequest struct {
 	TransactionID string `json:"transactionId" validate:"required,uuid"`
 	Stars         int    `json:"stars" validate:"required,min=1,max=5"`
-	Comment       string `json:"comment,omitempty" validate:"omitempty,max=500"`
+	Comment       string `json:"comment,omitempty"`
 }
 
 // RatingsFilterRequest represents filter parameters for ratings
@@ -34,6 +34,7 @@ type ServiceRunDetailResponse struct {
 	CanComplete bool `json:"canComplete"`
 	CanCancel   bool `json:"canCancel"`
 	CanMessage  bool `json:"canMessage"`
+	CanDispute  bool `json:"canDispute"`
 }
 
 // ServiceRunsFilterRequest represents filter parameters for service runs
@@ -47,3 +48,41 @@ type ServiceRunsFilterRequest struct {
 type CancelServiceRunRequest struct {
 	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
 }
+
+// OpenDisputeRequest represents a participant disputing a service run
+type OpenDisputeRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=1000"`
+}
+
+// ResolveDisputeRequest represents an admin settling a dispute
+type ResolveDisputeRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=completed cancelled"`
+	Note    string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// DisputesFilterRequest represents filter parameters for the admin dispute queue
+type DisputesFilterRequest struct {
+	Status     string `query:"status"`     // open, resolved
+	TargetType string `query:"targetType"` // trade, service_run
+	Pagination
+}
+
+// DisputeResponse represents a dispute on a trade or service run
+type DisputeResponse struct {
+	ID             string     `json:"id"`
+	TargetType     string     `json:"targetType"`
+	TargetID       string     `json:"targetId"`
+	OpenedBy       string     `json:"openedBy"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	Outcome        string     `json:"outcome,omitempty"`
+	ResolutionNote string     `json:"resolutionNote,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// ResolveDisputeResponse is the settled dispute and the run it settled
+type ResolveDisputeResponse struct {
+	Dispute    *DisputeResponse    `json:"dispute"`
+	ServiceRun *ServiceRunResponse `json:"serviceRun"`
+}
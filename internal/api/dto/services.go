@@ -23,6 +23,9 @@ type ServiceResponse struct {
 	Status      string          `json:"status"`
 	CreatedAt   time.Time       `json:"createdAt"`
 	UpdatedAt   time.Time       `json:"updatedAt"`
+
+	// BuyerRequirements is set when the provider only takes offers from buyers with enough reputation
+	BuyerRequirements *BuyerRequirementsResponse `json:"buyerRequirements,omitempty"`
 }
 
 // ProviderCardResponse represents a provider with all their services
@@ -35,27 +38,40 @@ type ProviderCardResponse struct {
 type CreateServiceRequest struct {
 	ServiceType string          `json:"serviceType" validate:"required,oneof=rush crush grush sockets waypoints ubers colossal_ancients"`
 	Name        string          `json:"name" validate:"required,min=1,max=100"`
-	Description string          `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Description string          `json:"description,omitempty"`
 	AskingPrice string          `json:"askingPrice,omitempty" validate:"omitempty,max=100"`
 	AskingFor   json.RawMessage `json:"askingFor,omitempty"`
-	Notes       string          `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Notes       string          `json:"notes,omitempty"`
 	Game        string          `json:"game" validate:"required,min=1,max=20"`
 	Ladder      bool            `json:"ladder"`
 	Hardcore    bool            `json:"hardcore"`
 	IsNonRotw   bool            `json:"isNonRotw"`
 	Platforms   []string        `json:"platforms" validate:"required,min=1,dive,oneof=pc xbox playstation switch"`
-	Region      string          `json:"region" validate:"required,oneof=americas europe asia"`
+	Region      string          `json:"region" validate:"required,max=50"`
+
+	// Optional buyer reputation thresholds; AllowUnratedBuyers lets users with no ratings yet through
+	MinBuyerRating     *float64 `json:"minBuyerRating,omitempty" validate:"omitempty,min=1,max=5"`
+	MinBuyerTrades     *int     `json:"minBuyerTrades,omitempty" validate:"omitempty,min=1,max=10000"`
+	AllowUnratedBuyers bool     `json:"allowUnratedBuyers"`
+
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"omitempty,max=255"`
 }
 
 // UpdateServiceRequest represents a request to update a service
 type UpdateServiceRequest struct {
 	Name        *string         `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
-	Description *string         `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Description *string         `json:"description,omitempty"`
 	AskingPrice *string         `json:"askingPrice,omitempty" validate:"omitempty,max=100"`
 	AskingFor   json.RawMessage `json:"askingFor,omitempty"`
-	Notes       *string         `json:"notes,omitempty" validate:"omitempty,max=500"`
+	Notes       *string         `json:"notes,omitempty"`
 	Platforms   []string        `json:"platforms,omitempty" validate:"omitempty,min=1,dive,oneof=pc xbox playstation switch"`
-	Region      *string         `json:"region,omitempty" validate:"omitempty,oneof=americas europe asia"`
+	Region      *string         `json:"region,omitempty" validate:"omitempty,max=50"`
+
+	// Buyer reputation thresholds; 0 removes a threshold
+	MinBuyerRating     *float64 `json:"minBuyerRating,omitempty" validate:"omitempty,min=0,max=5"`
+	MinBuyerTrades     *int     `json:"minBuyerTrades,omitempty" validate:"omitempty,min=0,max=10000"`
+	AllowUnratedBuyers *bool    `json:"allowUnratedBuyers,omitempty"`
 }
 
 // SearchServicesRequest represents service search/filter parameters via JSON body
@@ -7,6 +7,12 @@ type CheckoutRequest struct {
 	PriceID string `json:"priceId"`
 }
 
+// GiftCheckoutRequest contains the request body for gifting premium to another user
+type GiftCheckoutRequest struct {
+	Recipient string `json:"recipient"` // user ID or username
+	PriceID   string `json:"priceId"`
+}
+
 // SubscriptionInfoResponse represents the user's subscription status
 type SubscriptionInfoResponse struct {
 	IsPremium          bool       `json:"isPremium"`
@@ -22,16 +28,24 @@ type CheckoutResponse struct {
 	CheckoutURL string `json:"checkoutUrl"`
 }
 
-// BillingHistoryEntry represents a single billing event
+// BillingHistoryEntry represents a single billing event. Amount is formatted for
+// display; AmountCents (in the currency's minor unit) and Currency let clients format it themselves.
 type BillingHistoryEntry struct {
 	ID          string `json:"id"`
 	Date        string `json:"date"`
 	Description string `json:"description"`
 	Amount      string `json:"amount"`
+	AmountCents *int   `json:"amountCents,omitempty"`
+	Currency    string `json:"currency,omitempty"` // uppercase ISO 4217 code
 	Status      string `json:"status"`
 	InvoiceURL  string `json:"invoiceUrl,omitempty"`
 }
 
+// BillingHistoryRequest represents the query parameters for billing history
+type BillingHistoryRequest struct {
+	Locale string `query:"locale"` // e.g. en, pt-BR, de; unknown locales use English
+}
+
 // BillingHistoryResponse contains the user's billing history
 type BillingHistoryResponse struct {
 	Data []BillingHistoryEntry `json:"data"`
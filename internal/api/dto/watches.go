@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// CreateWatchRequest represents a request to watch an item name
+type CreateWatchRequest struct {
+	ItemName string `json:"itemName" validate:"required,min=1,max=100"`
+	Game     string `json:"game" validate:"required,min=1,max=20"`
+}
+
+// WatchResponse represents an item watch in API responses
+type WatchResponse struct {
+	ID        string    `json:"id"`
+	ItemName  string    `json:"itemName"`
+	Game      string    `json:"game"`
+	CreatedAt time.Time `json:"createdAt"`
+}
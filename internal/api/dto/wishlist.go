@@ -65,3 +65,30 @@ type WishlistItemResponse struct {
 type WishlistFilterRequest struct {
 	Pagination
 }
+
+// ImportWishlistRequest represents a bulk wishlist import
+type ImportWishlistRequest struct {
+	Items []CreateWishlistItemRequest `json:"items"`
+}
+
+// WishlistImportResult reports what happened to one item of an import
+type WishlistImportResult struct {
+	Index  int               `json:"index"`
+	Name   string            `json:"name"`
+	Status string            `json:"status"` // created, duplicate, invalid, limit_reached, failed
+	ID     *string           `json:"id,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// WishlistImportResponse summarizes a bulk wishlist import
+type WishlistImportResponse struct {
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"`
+	Results []WishlistImportResult `json:"results"`
+}
+
+// WishlistExportResponse holds every wishlist item of a user; Items can be posted back to the import endpoint
+type WishlistExportResponse struct {
+	ExportedAt time.Time              `json:"exportedAt"`
+	Items      []WishlistItemResponse `json:"items"`
+}
@@ -91,7 +91,7 @@ func (h *BugReportHandler) List(c *fiber.Ctx) error {
 		responses = append(responses, *h.service.ToAdminResponse(report))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(responses, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(responses, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // UpdateStatus handles PATCH /api/v1/bug-reports/:id (admin only)
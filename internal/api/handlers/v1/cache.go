@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// CacheHandler handles admin cache purges
+type CacheHandler struct {
+	service   *service.CacheService
+	validator *validator.Validate
+}
+
+// NewCacheHandler creates a new cache handler
+func NewCacheHandler(service *service.CacheService) *CacheHandler {
+	return &CacheHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// PurgeListing handles DELETE /api/v1/admin/cache/listings/:id
+func (h *CacheHandler) PurgeListing(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.PurgeListing(c.Context(), id); err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to purge listing cache",
+			"error", err.Error(),
+			"listing_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to purge listing cache",
+			Code:    500,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PurgeProfile handles DELETE /api/v1/admin/cache/profiles/:id
+func (h *CacheHandler) PurgeProfile(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.service.PurgeProfile(c.Context(), id); err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to purge profile cache",
+			"error", err.Error(),
+			"profile_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to purge profile cache",
+			Code:    500,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PurgeAll handles POST /api/v1/admin/cache/purge
+func (h *CacheHandler) PurgeAll(c *fiber.Ctx) error {
+	var req dto.PurgeCacheRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	purged, err := h.service.PurgeAll(c.Context(), req.Pattern)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCachePattern) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "invalid_pattern",
+				Message: "Pattern must be one of: " + strings.Join(service.PurgeablePatterns(), ", "),
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to purge cache",
+			"error", err.Error(),
+			"pattern", req.Pattern,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to purge cache",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.PurgeCacheResponse{
+		Pattern: req.Pattern,
+		Purged:  purged,
+	})
+}
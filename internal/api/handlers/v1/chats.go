@@ -1,14 +1,17 @@
 package v1
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"io"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 )
 
@@ -26,6 +29,40 @@ func NewChatHandler(service *service.ChatService) *ChatHandler {
 	}
 }
 
+// List handles GET /api/v1/chats
+func (h *ChatHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var filter dto.ChatsFilterRequest
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	chats, count, err := h.service.List(c.Context(), userID, filter.Archived, filter.GetOffset(), filter.GetLimit())
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list chats",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list chats",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.ChatResponse, 0, len(chats))
+	for _, chat := range chats {
+		items = append(items, *h.service.ToChatResponse(chat))
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
+}
+
 // GetByID handles GET /api/v1/chats/:id
 func (h *ChatHandler) GetByID(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -62,6 +99,42 @@ func (h *ChatHandler) GetByID(c *fiber.Ctx) error {
 	return c.JSON(h.service.ToChatResponse(chat))
 }
 
+// GetByOfferID handles GET /api/v1/offers/:id/chat
+func (h *ChatHandler) GetByOfferID(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	offerID := c.Params("id")
+
+	chat, err := h.service.GetByOfferID(c.Context(), offerID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "No chat exists for this offer",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You don't have access to this chat",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to get chat by offer",
+			"error", err.Error(),
+			"offer_id", offerID,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get chat",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToChatResponse(chat))
+}
+
 // GetMessages handles GET /api/v1/chats/:id/messages
 func (h *ChatHandler) GetMessages(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -76,7 +149,25 @@ func (h *ChatHandler) GetMessages(c *fiber.Ctx) error {
 		})
 	}
 
-	messages, count, err := h.service.GetMessages(c.Context(), chatID, userID, filter.GetOffset(), filter.GetLimit())
+	if err := h.validator.Struct(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	var (
+		messages []*models.Message
+		count    int
+		hasMore  bool
+		err      error
+	)
+	if filter.IsCursor() {
+		messages, hasMore, err = h.service.GetMessagesBefore(c.Context(), chatID, userID, filter.BeforeID, filter.GetLimit())
+	} else {
+		messages, count, err = h.service.GetMessages(c.Context(), chatID, userID, filter.GetOffset(), filter.GetLimit())
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
@@ -110,7 +201,16 @@ func (h *ChatHandler) GetMessages(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToMessageResponse(message))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	if filter.IsCursor() {
+		// Oldest message on the page is the cursor for scrolling further back
+		nextCursor := ""
+		if len(items) > 0 {
+			nextCursor = items[0].ID
+		}
+		return c.JSON(dto.NewCursorResponse(items, hasMore, nextCursor))
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // SendMessage handles POST /api/v1/chats/:id/messages
@@ -173,6 +273,109 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(h.service.ToMessageResponse(message))
 }
 
+// SendAttachment handles POST /api/v1/chats/:id/attachments
+func (h *ChatHandler) SendAttachment(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	chatID := c.Params("id")
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "No image file provided",
+			Code:    400,
+		})
+	}
+
+	if file.Size > service.MaxChatAttachmentSize {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "File too large. Maximum size is 2MB",
+			Code:    400,
+		})
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if !isValidImageType(contentType) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid file type. Allowed: PNG, JPEG, WebP",
+			Code:    400,
+		})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to open uploaded file",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to process file",
+			Code:    500,
+		})
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to read uploaded file",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to process file",
+			Code:    500,
+		})
+	}
+
+	message, err := h.service.SendAttachment(c.Context(), chatID, userID, data, contentType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Chat not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not a participant in this chat",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Messaging is only available for active trades",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidImage) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Image must be a PNG, JPEG or WebP of at most 2MB",
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to send attachment",
+			"error", err.Error(),
+			"user_id", userID,
+			"chat_id", chatID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to send attachment",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToMessageResponse(message))
+}
+
 // MarkRead handles POST /api/v1/chats/:id/read
 // If no messageIds provided in body, marks all unread messages in the chat as read
 func (h *ChatHandler) MarkRead(c *fiber.Ctx) error {
@@ -213,3 +416,104 @@ func (h *ChatHandler) MarkRead(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse{Success: true})
 }
+
+// Typing handles POST /api/v1/chats/:id/typing
+// Shows the other participant a typing indicator for a few seconds
+func (h *ChatHandler) Typing(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	chatID := c.Params("id")
+
+	if err := h.service.PublishTyping(c.Context(), chatID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Chat not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not a participant in this chat",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Messaging is only available for active trades",
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to set typing",
+			"error", err.Error(),
+			"user_id", userID,
+			"chat_id", chatID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to set typing",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true})
+}
+
+// Stream handles GET /api/v1/chats/:id/stream
+// Pushes typing, seen and read events for the chat as Server-Sent Events.
+// Messages themselves still arrive through Realtime.
+func (h *ChatHandler) Stream(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	chatID := c.Params("id")
+
+	// The stream outlives the handler, so it can't use the request context
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := h.service.SubscribePresence(ctx, chatID, userID)
+	if err != nil {
+		cancel()
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Chat not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not a participant in this chat",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrStreamUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
+				Error:   "stream_unavailable",
+				Message: "Chat presence is unavailable",
+				Code:    503,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to open chat stream",
+			"error", err.Error(),
+			"user_id", userID,
+			"chat_id", chatID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to open chat stream",
+			Code:    500,
+		})
+	}
+
+	return writeSSE(c, sseStream{
+		Initial:  stream.Initial,
+		Messages: stream.Messages(),
+		// Only a client still reading keeps the participant "seen"
+		OnHeartbeat: func() { stream.Touch(ctx) },
+		Close: func() {
+			stream.Close()
+			cancel()
+		},
+	})
+}
@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// DeclineTemplateHandler handles a seller's saved decline templates
+type DeclineTemplateHandler struct {
+	service   *service.DeclineTemplateService
+	validator *validator.Validate
+}
+
+// NewDeclineTemplateHandler creates a new decline template handler
+func NewDeclineTemplateHandler(service *service.DeclineTemplateService) *DeclineTemplateHandler {
+	return &DeclineTemplateHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List handles GET /api/v1/decline-templates
+func (h *DeclineTemplateHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	templates, err := h.service.List(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list decline templates",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list decline templates",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.DeclineTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		items = append(items, *h.service.ToResponse(template))
+	}
+
+	return c.JSON(items)
+}
+
+// Create handles POST /api/v1/decline-templates
+func (h *DeclineTemplateHandler) Create(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.CreateDeclineTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	template, err := h.service.Create(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrDeclineTemplateLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "decline_template_limit_reached",
+				Message: fmt.Sprintf("You can have at most %d decline templates.", service.MaxDeclineTemplates),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to create decline template",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create decline template",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(template))
+}
+
+// Update handles PATCH /api/v1/decline-templates/:id
+func (h *DeclineTemplateHandler) Update(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.UpdateDeclineTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	template, err := h.service.Update(c.Context(), id, userID, &req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Decline template not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only update your own decline templates",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to update decline template",
+			"error", err.Error(),
+			"decline_template_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update decline template",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(template))
+}
+
+// Delete handles DELETE /api/v1/decline-templates/:id
+func (h *DeclineTemplateHandler) Delete(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	err := h.service.Delete(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Decline template not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only delete your own decline templates",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to delete decline template",
+			"error", err.Error(),
+			"decline_template_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to delete decline template",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Decline template deleted"})
+}
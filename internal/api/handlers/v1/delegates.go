@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// DelegateHandler handles the permissions an owner grants to delegates
+type DelegateHandler struct {
+	service   *service.DelegateService
+	validator *validator.Validate
+}
+
+// NewDelegateHandler creates a new delegate handler
+func NewDelegateHandler(service *service.DelegateService) *DelegateHandler {
+	return &DelegateHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List handles GET /api/v1/delegates
+func (h *DelegateHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	delegates, err := h.service.List(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list delegates",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list delegates",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.DelegateResponse, 0, len(delegates))
+	for _, delegate := range delegates {
+		items = append(items, *h.service.ToResponse(delegate))
+	}
+
+	return c.JSON(items)
+}
+
+// Grant handles PUT /api/v1/delegates/:userId
+func (h *DelegateHandler) Grant(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	delegateID := c.Params("userId")
+
+	var req dto.GrantDelegateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	delegate, err := h.service.Grant(c.Context(), userID, delegateID, req.Permissions)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrSelfAction) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "You cannot make yourself a delegate",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "User not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrDelegateLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "delegate_limit_reached",
+				Message: fmt.Sprintf("You can have at most %d delegates.", service.MaxDelegates),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to grant delegate",
+			"error", err.Error(),
+			"user_id", userID,
+			"delegate_id", delegateID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to grant delegate",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(delegate))
+}
+
+// Revoke handles DELETE /api/v1/delegates/:userId
+func (h *DelegateHandler) Revoke(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	delegateID := c.Params("userId")
+
+	if err := h.service.Revoke(c.Context(), userID, delegateID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Delegate not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to revoke delegate",
+			"error", err.Error(),
+			"user_id", userID,
+			"delegate_id", delegateID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to revoke delegate",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Delegate revoked"})
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// FavoriteHandler handles a buyer's favorite listings
+type FavoriteHandler struct {
+	service *service.FavoriteService
+}
+
+// NewFavoriteHandler creates a new favorite handler
+func NewFavoriteHandler(service *service.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{service: service}
+}
+
+// List handles GET /api/v1/favorites
+func (h *FavoriteHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var filter dto.Pagination
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	items, count, err := h.service.List(c.Context(), userID, filter.GetOffset(), filter.GetLimit())
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list favorites",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list favorites",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
+}
+
+// Add handles POST /api/v1/favorites/:listingId
+func (h *FavoriteHandler) Add(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	listingID := c.Params("listingId")
+
+	if err := h.service.Add(c.Context(), userID, listingID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Only active listings can be favorited",
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to add favorite",
+			"error", err.Error(),
+			"listing_id", listingID,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to add favorite",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Listing added to favorites"})
+}
+
+// Remove handles DELETE /api/v1/favorites/:listingId
+func (h *FavoriteHandler) Remove(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	listingID := c.Params("listingId")
+
+	if err := h.service.Remove(c.Context(), userID, listingID); err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to remove favorite",
+			"error", err.Error(),
+			"listing_id", listingID,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to remove favorite",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Listing removed from favorites"})
+}
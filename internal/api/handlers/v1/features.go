@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// FeatureFlagHandler handles feature flag endpoints
+type FeatureFlagHandler struct {
+	service *service.FeatureFlagService
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(service *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{service: service}
+}
+
+// List handles GET /api/v1/features
+func (h *FeatureFlagHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	// Rollouts and overrides differ per user
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	return c.JSON(dto.FeatureFlagsResponse{Flags: h.service.Enabled(c.Context(), userID)})
+}
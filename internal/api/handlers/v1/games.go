@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// GamesHandler handles game catalog endpoints
+type GamesHandler struct {
+	service *service.GamesService
+}
+
+// NewGamesHandler creates a new games handler
+func NewGamesHandler(service *service.GamesService) *GamesHandler {
+	return &GamesHandler{service: service}
+}
+
+// ResolveItem handles GET /api/v1/games/:game/items/resolve?name=
+func (h *GamesHandler) ResolveItem(c *fiber.Ctx) error {
+	game := c.Params("game")
+
+	item, err := h.service.ResolveItem(c.Context(), game, c.Query("name"))
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Game not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to resolve item",
+			"error", err.Error(),
+			"game", game,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resolve item",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(item)
+}
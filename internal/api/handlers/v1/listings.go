@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -12,6 +13,7 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 )
@@ -41,8 +43,20 @@ func (h *ListingHandler) List(c *fiber.Ctx) error {
 		})
 	}
 
-	listings, count, err := h.service.List(c.Context(), &filter)
+	listingFilter := h.service.ToFilter(&filter)
+	ignored := service.PruneAffixFilters(&listingFilter)
+	viewerID := middleware.GetUserID(c)
+	service.ExcludeViewer(&listingFilter, viewerID)
+
+	listings, count, fuzzy, err := h.service.ListByFilter(c.Context(), listingFilter, viewerID)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRegion) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Unknown region",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to list listings",
 			"error", err.Error(),
 		)
@@ -59,7 +73,16 @@ func (h *ListingHandler) List(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToCardResponse(listing))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	// Results without the viewer's own listings mustn't be shared by caches
+	if viewerID != "" {
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+	}
+
+	return c.JSON(dto.ListingSearchResponse{
+		PaginatedResponse: dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count),
+		IgnoredFilters:    ignored,
+		Fuzzy:             fuzzy,
+	})
 }
 
 // Search handles POST /api/v1/listings/search
@@ -115,10 +138,25 @@ func (h *ListingHandler) Search(c *fiber.Ctx) error {
 		SortOrder:       req.SortOrder,
 		Offset:          pag.GetOffset(),
 		Limit:           pag.GetLimit(),
+		Ethereal:        req.Ethereal,
+		MinSockets:      req.MinSockets,
+		MaxSockets:      req.MaxSockets,
+		Quality:         req.Quality,
 	}
 
-	listings, count, err := h.service.ListByFilter(c.Context(), filter)
+	ignored := service.PruneAffixFilters(&filter)
+	viewerID := middleware.GetUserID(c)
+	service.ExcludeViewer(&filter, viewerID)
+
+	listings, count, fuzzy, err := h.service.ListByFilter(c.Context(), filter, viewerID)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRegion) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Unknown region",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to search listings",
 			"error", err.Error(),
 		)
@@ -134,7 +172,16 @@ func (h *ListingHandler) Search(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToCardResponse(listing))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, pag.Page, pag.GetLimit(), count))
+	// Results without the viewer's own listings mustn't be shared by caches
+	if viewerID != "" {
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+	}
+
+	return c.JSON(dto.ListingSearchResponse{
+		PaginatedResponse: dto.NewPaginatedResponse(items, pag.GetPage(), pag.GetLimit(), count),
+		IgnoredFilters:    ignored,
+		Fuzzy:             fuzzy,
+	})
 }
 
 // GetByID handles GET /api/v1/listings/:id
@@ -148,6 +195,10 @@ func (h *ListingHandler) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
+	if viewerID := middleware.GetUserID(c); viewerID != "" {
+		return h.getByIDForViewer(c, id, viewerID)
+	}
+
 	listing, err := h.service.GetByID(c.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -168,14 +219,60 @@ func (h *ListingHandler) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
+	// Reserved listings are only visible to the seller and the reserved buyer,
+	// so they must not land in a shared cache
+	if listing.Status == "reserved" {
+		if !listing.IsVisibleTo(middleware.GetUserID(c)) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+	}
+
 	// Increment view count asynchronously (don't block response)
 	go func() {
-		_ = h.service.IncrementViews(context.Background(), id)
+		_ = h.service.IncrementViews(context.Background(), id, "")
 	}()
 
 	return c.JSON(h.service.ToDetailResponse(c.Context(), listing))
 }
 
+// getByIDForViewer serves the listing detail with the viewer's relationship flags.
+// The response is per-user, so it must never land in a shared cache.
+func (h *ListingHandler) getByIDForViewer(c *fiber.Ctx, id, viewerID string) error {
+	resp, err := h.service.GetByIDForViewer(c.Context(), id, viewerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to get listing",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", viewerID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get listing",
+			Code:    500,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	go func() {
+		_ = h.service.IncrementViews(context.Background(), id, viewerID)
+	}()
+
+	return c.JSON(resp)
+}
+
 // Create handles POST /api/v1/listings
 func (h *ListingHandler) Create(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -189,16 +286,34 @@ func (h *ListingHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.validator.Struct(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-			Code:    400,
-		})
+	req.IdempotencyKey = c.Get("Idempotency-Key")
+
+	verrs := collectValidationErrors(h.validator, &req)
+	verrs.Merge(h.service.ValidateCreate(&req))
+	if len(verrs) > 0 {
+		return validationFailed(c, verrs)
 	}
 
 	listing, err := h.service.Create(c.Context(), userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrRequestInProgress) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "request_in_progress",
+				Message: "A request with this idempotency key is still being processed",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "email_not_verified",
+				Message: "Verify your email address before creating listings",
+				Code:    403,
+			})
+		}
 		if errors.Is(err, service.ErrListingLimitReached) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "listing_limit_reached",
@@ -206,6 +321,20 @@ func (h *ListingHandler) Create(c *fiber.Ctx) error {
 				Code:    403,
 			})
 		}
+		if errors.Is(err, service.ErrAlreadyExists) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "duplicate_listing",
+				Message: "You already have an active listing for this exact item",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "relist_cooldown",
+				Message: "You recently sold an identical item. Please wait before listing it again.",
+				Code:    409,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to create listing",
 			"error", err.Error(),
 			"user_id", userID,
@@ -217,7 +346,44 @@ func (h *ListingHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(h.service.ToCardResponse(listing))
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToCreateResponse(listing))
+}
+
+// PreviewWishlistMatches handles POST /api/v1/listings/wishlist-matches
+func (h *ListingHandler) PreviewWishlistMatches(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.CreateListingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	count, err := h.service.CountPotentialWishlistMatches(c.Context(), userID, &req)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to preview wishlist matches",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to preview wishlist matches",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.WishlistMatchPreviewResponse{Count: count})
 }
 
 // Update handles PATCH /api/v1/listings/:id
@@ -244,6 +410,10 @@ func (h *ListingHandler) Update(c *fiber.Ctx) error {
 
 	listing, err := h.service.Update(c.Context(), id, userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "not_found",
@@ -258,6 +428,20 @@ func (h *ListingHandler) Update(c *fiber.Ctx) error {
 				Code:    403,
 			})
 		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Listing status cannot be changed to the requested value",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrListingLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "listing_limit_reached",
+				Message: fmt.Sprintf("Free users can have at most %d active listings. Upgrade to premium for unlimited listings.", service.FreeListingLimit),
+				Code:    403,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to update listing",
 			"error", err.Error(),
 			"listing_id", id,
@@ -294,6 +478,13 @@ func (h *ListingHandler) Delete(c *fiber.Ctx) error {
 				Code:    403,
 			})
 		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only active, paused or expired listings can be cancelled",
+				Code:    409,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to delete listing",
 			"error", err.Error(),
 			"listing_id", id,
@@ -309,6 +500,345 @@ func (h *ListingHandler) Delete(c *fiber.Ctx) error {
 	return c.JSON(dto.SuccessResponse{Success: true, Message: "Listing cancelled"})
 }
 
+// Pause handles POST /api/v1/listings/:id/pause
+func (h *ListingHandler) Pause(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	listing, err := h.service.Pause(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only pause your own listings",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only active listings can be paused",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to pause listing",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to pause listing",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(listing))
+}
+
+// Resume handles POST /api/v1/listings/:id/resume
+func (h *ListingHandler) Resume(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	listing, err := h.service.Resume(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only resume your own listings",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only paused listings can be resumed",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrListingLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "listing_limit_reached",
+				Message: fmt.Sprintf("Free users can have at most %d active listings. Upgrade to premium for unlimited listings.", service.FreeListingLimit),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to resume listing",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resume listing",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(listing))
+}
+
+// UploadImage handles POST /api/v1/listings/:id/image
+// Accepts either a multipart "image" file or a JSON body with a URL to fetch server-side.
+func (h *ListingHandler) UploadImage(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var listing *models.Listing
+	var err error
+
+	if file, formErr := c.FormFile("image"); formErr == nil {
+		if file.Size > service.MaxListingImageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "File too large. Maximum size is 2MB",
+				Code:    400,
+			})
+		}
+
+		f, openErr := file.Open()
+		if openErr != nil {
+			logger.FromContext(c.UserContext()).Error("failed to open uploaded file",
+				"error", openErr.Error(),
+				"user_id", userID,
+			)
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to process file",
+				Code:    500,
+			})
+		}
+		defer f.Close()
+
+		data, readErr := io.ReadAll(f)
+		if readErr != nil {
+			logger.FromContext(c.UserContext()).Error("failed to read uploaded file",
+				"error", readErr.Error(),
+				"user_id", userID,
+			)
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to process file",
+				Code:    500,
+			})
+		}
+
+		listing, err = h.service.UploadImage(c.Context(), id, userID, data)
+	} else {
+		var req dto.AttachListingImageRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Provide an image file or an image URL",
+				Code:    400,
+			})
+		}
+
+		if err := h.validator.Struct(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+		}
+
+		listing, err = h.service.AttachImageFromURL(c.Context(), id, userID, req.URL)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only change images on your own listings",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Images can't be changed on closed listings",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidImage) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Image must be a PNG, JPEG or WebP of at most 2MB",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidImageURL) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Image URL must be a public http(s) address",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrImageFetchFailed) {
+			return c.Status(fiber.StatusBadGateway).JSON(dto.ErrorResponse{
+				Error:   "image_fetch_failed",
+				Message: "Could not download the image from that URL",
+				Code:    502,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to set listing image",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to set listing image",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(listing))
+}
+
+// Reserve handles POST /api/v1/listings/:id/reserve
+func (h *ListingHandler) Reserve(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.ReserveListingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	listing, err := h.service.Reserve(c.Context(), id, userID, req.UserID, req.Until)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing or user not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only reserve your own listings",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrSelfAction) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "You cannot reserve a listing for yourself",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidReservation) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: fmt.Sprintf("Reservation must end in the future and within %d days", int(service.MaxReservationDuration.Hours()/24)),
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only active listings can be reserved",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to reserve listing",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to reserve listing",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(listing))
+}
+
+// ExpireStale handles POST /api/v1/admin/listings/expire-stale
+func (h *ListingHandler) ExpireStale(c *fiber.Ctx) error {
+	result, err := h.service.ExpireStale(c.Context())
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to expire stale listings",
+			"error", err.Error(),
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to expire stale listings",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.ExpireStaleListingsResponse{
+		Expired:  result.Expired,
+		Released: result.Released,
+	})
+}
+
+// NotifyExpiring handles POST /api/v1/admin/listings/notify-expiring
+func (h *ListingHandler) NotifyExpiring(c *fiber.Ctx) error {
+	withinHours := c.QueryInt("withinHours", 0)
+	if withinHours < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "withinHours must not be negative",
+			Code:    400,
+		})
+	}
+
+	warned, err := h.service.NotifyExpiringSoon(c.Context(), withinHours)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to notify expiring listings",
+			"error", err.Error(),
+			"warned", warned,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to notify expiring listings",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.NotifyExpiringListingsResponse{Warned: warned})
+}
+
 // Refresh handles POST /api/v1/listings/:id/refresh
 func (h *ListingHandler) Refresh(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -355,6 +885,13 @@ func (h *ListingHandler) Refresh(c *fiber.Ctx) error {
 				Code:    429,
 			})
 		}
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+				Error:   "bump_quota_exceeded",
+				Message: "You have no bumps left today",
+				Code:    429,
+			})
+		}
 		if errors.Is(err, service.ErrPremiumRequired) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "premium_required",
@@ -377,6 +914,63 @@ func (h *ListingHandler) Refresh(c *fiber.Ctx) error {
 	return c.JSON(h.service.ToCardResponse(listing))
 }
 
+// Renew handles POST /api/v1/listings/:id/renew
+func (h *ListingHandler) Renew(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	listing, err := h.service.Renew(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only renew your own listings",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only active or expired listings can be renewed",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrRenewTooEarly) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "renew_too_early",
+				Message: "This listing isn't expiring soon yet",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrListingLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "listing_limit_reached",
+				Message: fmt.Sprintf("Free users can have at most %d active listings. Upgrade to premium for unlimited listings.", service.FreeListingLimit),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to renew listing",
+			"error", err.Error(),
+			"listing_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to renew listing",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(listing))
+}
+
 // ListMy handles GET /api/v1/my/listings
 func (h *ListingHandler) ListMy(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -409,7 +1003,130 @@ func (h *ListingHandler) ListMy(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToCardResponse(listing))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	bumps, err := h.service.RemainingBumps(c.Context(), userID)
+	if err != nil {
+		// The quota is informational here; the listings still go out
+		logger.FromContext(c.UserContext()).Warn("failed to read bump quota",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+	}
+
+	return c.JSON(dto.MyListingsResponse{
+		PaginatedResponse: dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count),
+		Bumps:             bumps,
+	})
+}
+
+// GrantBumpCredits handles POST /api/v1/admin/profiles/:id/bump-credits
+func (h *ListingHandler) GrantBumpCredits(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.GrantBumpCreditsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	credits, err := h.service.GrantBumpCredits(c.Context(), id, req.Amount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Profile not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
+				Error:   "unavailable",
+				Message: "Bump credits need Redis",
+				Code:    503,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to grant bump credits",
+			"error", err.Error(),
+			"profile_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to grant bump credits",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.BumpCreditsResponse{UserID: id, Credits: credits})
+}
+
+// ListRecentlyViewed handles GET /api/v1/my/recently-viewed
+func (h *ListingHandler) ListRecentlyViewed(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	listings, err := h.service.GetRecentlyViewed(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to get recently viewed listings",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve recently viewed listings",
+			Code:    500,
+		})
+	}
+
+	if listings == nil {
+		listings = []dto.ListingCardResponse{}
+	}
+
+	return c.JSON(listings)
+}
+
+// GetSimilar handles GET /api/v1/listings/:id/similar
+func (h *ListingHandler) GetSimilar(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	limit := c.QueryInt("limit", service.DefaultSimilarListings)
+	if limit < 1 || limit > service.MaxSimilarListings {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: fmt.Sprintf("limit must be between 1 and %d", service.MaxSimilarListings),
+			Code:    400,
+		})
+	}
+
+	listings, err := h.service.GetSimilar(c.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Listing not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to get similar listings",
+			"error", err.Error(),
+			"listing_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve similar listings",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(listings)
 }
 
 // parsePlatformsFromString splits a comma-separated platform string into a slice
@@ -3,6 +3,7 @@ package v1
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -39,7 +40,7 @@ func (h *TradeHandlerNew) List(c *fiber.Ctx) error {
 		})
 	}
 
-	trades, count, err := h.service.List(c.Context(), userID, filter.Status, filter.GetOffset(), filter.GetLimit())
+	trades, count, err := h.service.List(c.Context(), userID, filter.Role, filter.Status, filter.CounterpartyID, filter.GetOffset(), filter.GetLimit())
 	if err != nil {
 		logger.FromContext(c.UserContext()).Error("failed to list trades",
 			"error", err.Error(),
@@ -58,7 +59,7 @@ func (h *TradeHandlerNew) List(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToResponseWithUser(c.Context(), trade, userID))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // GetByID handles GET /api/v1/trades/:id
@@ -102,7 +103,24 @@ func (h *TradeHandlerNew) Complete(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	id := c.Params("id")
 
-	trade, transaction, err := h.service.Complete(c.Context(), id, userID)
+	var req dto.CompleteTradeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "confirmation_required",
+			Message: "A confirmation token from POST /trades/:id/complete/request is required",
+			Code:    400,
+		})
+	}
+
+	trade, transaction, err := h.service.Complete(c.Context(), id, userID, req.Token)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
@@ -125,6 +143,20 @@ func (h *TradeHandlerNew) Complete(c *fiber.Ctx) error {
 				Code:    400,
 			})
 		}
+		if errors.Is(err, service.ErrInvalidConfirmation) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "invalid_confirmation",
+				Message: "Confirmation token is invalid or expired. Request a new one.",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrConflict) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "completion_in_progress",
+				Message: "The other party is completing this trade. Try again shortly.",
+				Code:    409,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to complete trade",
 			"error", err.Error(),
 			"trade_id", id,
@@ -143,6 +175,60 @@ func (h *TradeHandlerNew) Complete(c *fiber.Ctx) error {
 	})
 }
 
+// RequestComplete handles POST /api/v1/trades/:id/complete/request
+func (h *TradeHandlerNew) RequestComplete(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	token, expiresAt, err := h.service.RequestComplete(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Trade not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not a participant in this trade",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Trade is not active",
+				Code:    400,
+			})
+		}
+		if errors.Is(err, service.ErrConfirmationUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
+				Error:   "confirmation_unavailable",
+				Message: "Trade confirmation is temporarily unavailable, try again shortly",
+				Code:    503,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to request trade completion",
+			"error", err.Error(),
+			"trade_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to request trade completion",
+			Code:    500,
+		})
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(dto.CompletionTokenResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
 // Cancel handles POST /api/v1/trades/:id/cancel
 func (h *TradeHandlerNew) Cancel(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -154,7 +240,7 @@ func (h *TradeHandlerNew) Cancel(c *fiber.Ctx) error {
 		req = dto.CancelTradeRequest{}
 	}
 
-	trade, err := h.service.Cancel(c.Context(), id, userID, req.Reason)
+	trade, err := h.service.Cancel(c.Context(), id, userID, &req)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
@@ -191,3 +277,112 @@ func (h *TradeHandlerNew) Cancel(c *fiber.Ctx) error {
 
 	return c.JSON(h.service.ToResponse(trade))
 }
+
+// ToggleItemReceived handles POST /api/v1/trades/:id/items/:index/received
+func (h *TradeHandlerNew) ToggleItemReceived(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	index, err := c.ParamsInt("index")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid item index",
+			Code:    400,
+		})
+	}
+
+	var req dto.ToggleItemReceivedRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	trade, err := h.service.ToggleItemReceived(c.Context(), id, userID, index, req.Received)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Trade not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Offered item not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not a participant in this trade",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Only active trades have a checklist",
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to update trade checklist",
+			"error", err.Error(),
+			"trade_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update trade checklist",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToDetailResponse(c.Context(), trade, userID))
+}
+
+// ReconcileTransactions handles POST /api/v1/admin/trades/reconcile
+// Creates missing transactions for completed trades (admin only)
+func (h *TradeHandlerNew) ReconcileTransactions(c *fiber.Ctx) error {
+	var req dto.ReconcileTransactionsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	since := time.Now().AddDate(0, 0, -7)
+	if req.Since != "" {
+		since, _ = time.Parse(time.RFC3339, req.Since)
+	}
+
+	created, err := h.service.ReconcileTransactions(c.Context(), since)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to reconcile transactions",
+			"error", err.Error(),
+			"created", created,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to reconcile transactions",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.ReconcileTransactionsResponse{Created: created})
+}
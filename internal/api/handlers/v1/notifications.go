@@ -1,11 +1,16 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 )
 
@@ -41,8 +46,25 @@ func (h *NotificationHandler) List(c *fiber.Ctx) error {
 		unreadOnly = true
 	}
 
-	notifications, count, err := h.service.GetByUserID(c.Context(), userID, unreadOnly, filter.Type, filter.GetOffset(), filter.GetLimit())
+	var (
+		notifications []*models.Notification
+		count         int
+		hasMore       bool
+		err           error
+	)
+	if filter.IsCursor() {
+		notifications, hasMore, err = h.service.GetByUserIDAfter(c.Context(), userID, unreadOnly, filter.Type, filter.Cursor, filter.GetLimit())
+	} else {
+		notifications, count, err = h.service.GetByUserID(c.Context(), userID, unreadOnly, filter.Type, filter.GetOffset(), filter.GetLimit())
+	}
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "Invalid cursor",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to list notifications",
 			"error", err.Error(),
 			"user_id", userID,
@@ -60,7 +82,15 @@ func (h *NotificationHandler) List(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToResponse(notification))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	if filter.IsCursor() {
+		nextCursor := ""
+		if n := len(notifications); n > 0 {
+			nextCursor = dto.EncodeCursor(notifications[n-1].CreatedAt, notifications[n-1].ID)
+		}
+		return c.JSON(dto.NewCursorResponse(items, hasMore, nextCursor))
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // Count handles GET /api/v1/notifications/count
@@ -83,6 +113,52 @@ func (h *NotificationHandler) Count(c *fiber.Ctx) error {
 	return c.JSON(dto.NotificationCountResponse{Count: count})
 }
 
+// Stream handles GET /api/v1/notifications/stream
+// Pushes unread-count and new-notification events as Server-Sent Events.
+// Clients that can't connect (503) should keep polling /notifications/count.
+func (h *NotificationHandler) Stream(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	// The stream outlives the handler, so it can't use the request context
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := h.service.Subscribe(ctx, userID)
+	if err != nil {
+		cancel()
+		if errors.Is(err, service.ErrStreamUnavailable) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
+				Error:   "stream_unavailable",
+				Message: "Live notifications are unavailable, poll /notifications/count instead",
+				Code:    503,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to open notification stream",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to open notification stream",
+			Code:    500,
+		})
+	}
+
+	// Seed the badge so the client doesn't need a separate count request
+	var initial []string
+	if count, err := h.service.CountUnread(ctx, userID); err == nil {
+		initial = append(initial, fmt.Sprintf(`{"type":%q,"unreadCount":%d}`, service.StreamEventUnreadCount, count))
+	}
+
+	return writeSSE(c, sseStream{
+		Initial:  initial,
+		Messages: sub.Messages(),
+		Close: func() {
+			sub.Close()
+			cancel()
+		},
+	})
+}
+
 // MarkRead handles POST /api/v1/notifications/read
 func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -118,3 +194,201 @@ func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse{Success: true})
 }
+
+// MarkReadByReference handles POST /api/v1/notifications/read-by-reference
+// Marks every notification about one offer, trade, chat etc. as read
+func (h *NotificationHandler) MarkReadByReference(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.MarkNotificationsReadByReferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	marked, err := h.service.MarkReadByReference(c.Context(), userID, req.ReferenceType, req.ReferenceID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to mark notifications as read by reference",
+			"error", err.Error(),
+			"user_id", userID,
+			"reference_type", req.ReferenceType,
+			"reference_id", req.ReferenceID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to mark notifications as read",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.MarkNotificationsReadByReferenceResponse{Marked: marked})
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	prefs, err := h.service.GetPreferences(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to get notification preferences",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get notification preferences",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToPreferencesResponse(prefs))
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	prefs, err := h.service.UpdatePreferences(c.Context(), userID, req.Types)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		logger.FromContext(c.UserContext()).Error("failed to update notification preferences",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update notification preferences",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToPreferencesResponse(prefs))
+}
+
+// Broadcast handles POST /api/v1/admin/notifications/broadcast
+// Queues the announcement and returns the job; sending happens in the background
+func (h *NotificationHandler) Broadcast(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.BroadcastNotificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	req.IdempotencyKey = c.Get("Idempotency-Key")
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	job, err := h.service.StartBroadcast(c.Context(), userID, req.Audience, req.Title, req.Body, req.IdempotencyKey)
+	if err != nil {
+		switch err {
+		case service.ErrForbidden:
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Admin access required",
+				Code:    403,
+			})
+		case service.ErrInvalidAudience:
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid audience",
+				Code:    400,
+			})
+		case service.ErrRequestInProgress:
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "request_in_progress",
+				Message: "This broadcast is already being queued",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to broadcast announcement",
+			"error", err.Error(),
+			"user_id", userID,
+			"audience", req.Audience,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to broadcast announcement",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(h.service.ToBroadcastResponse(job))
+}
+
+// GetBroadcast handles GET /api/v1/admin/notifications/broadcast/:id
+func (h *NotificationHandler) GetBroadcast(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	job, err := h.service.GetBroadcast(c.Context(), userID, id)
+	if err != nil {
+		switch err {
+		case service.ErrForbidden:
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Admin access required",
+				Code:    403,
+			})
+		case service.ErrNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Broadcast not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to get broadcast",
+			"error", err.Error(),
+			"user_id", userID,
+			"broadcast_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get broadcast",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToBroadcastResponse(job))
+}
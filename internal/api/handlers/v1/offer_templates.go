@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// OfferTemplateHandler handles a user's saved offer templates
+type OfferTemplateHandler struct {
+	service   *service.OfferTemplateService
+	validator *validator.Validate
+}
+
+// NewOfferTemplateHandler creates a new offer template handler
+func NewOfferTemplateHandler(service *service.OfferTemplateService) *OfferTemplateHandler {
+	return &OfferTemplateHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List handles GET /api/v1/offer-templates
+func (h *OfferTemplateHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	templates, err := h.service.List(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list offer templates",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list offer templates",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.OfferTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		items = append(items, *h.service.ToResponse(template))
+	}
+
+	return c.JSON(items)
+}
+
+// Create handles POST /api/v1/offer-templates
+func (h *OfferTemplateHandler) Create(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.CreateOfferTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	template, err := h.service.Create(c.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrOfferTemplateLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "offer_template_limit_reached",
+				Message: fmt.Sprintf("You can have at most %d offer templates.", service.MaxOfferTemplates),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to create offer template",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create offer template",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(template))
+}
+
+// Update handles PATCH /api/v1/offer-templates/:id
+func (h *OfferTemplateHandler) Update(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.UpdateOfferTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	template, err := h.service.Update(c.Context(), id, userID, &req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Offer template not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only update your own offer templates",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to update offer template",
+			"error", err.Error(),
+			"offer_template_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update offer template",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(h.service.ToResponse(template))
+}
+
+// Delete handles DELETE /api/v1/offer-templates/:id
+func (h *OfferTemplateHandler) Delete(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	err := h.service.Delete(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Offer template not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only delete your own offer templates",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to delete offer template",
+			"error", err.Error(),
+			"offer_template_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to delete offer template",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Offer template deleted"})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 )
 
@@ -39,8 +40,36 @@ func (h *OfferHandler) List(c *fiber.Ctx) error {
 		})
 	}
 
-	offers, count, err := h.service.List(c.Context(), userID, filter.Role, filter.Status, filter.Type, filter.ListingID, filter.ServiceID, filter.GetOffset(), filter.GetLimit())
+	sortByValue := filter.SortBy == "value" && filter.Role == "seller"
+	if sortByValue && filter.IsCursor() {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Cursor paging is not supported with sortBy=value",
+			Code:    400,
+		})
+	}
+
+	var (
+		offers  []*models.Offer
+		count   int
+		hasMore bool
+		err     error
+	)
+	if filter.IsCursor() {
+		offers, hasMore, err = h.service.ListAfter(c.Context(), userID, filter.Role, filter.Status, filter.Type, filter.ListingID, filter.ServiceID, filter.Cursor, filter.GetLimit())
+	} else if sortByValue {
+		offers, count, err = h.service.ListByValue(c.Context(), userID, filter.Status, filter.Type, filter.ListingID, filter.ServiceID, filter.GetOffset(), filter.GetLimit())
+	} else {
+		offers, count, err = h.service.List(c.Context(), userID, filter.Role, filter.Status, filter.Type, filter.ListingID, filter.ServiceID, filter.GetOffset(), filter.GetLimit())
+	}
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "Invalid cursor",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to list offers",
 			"error", err.Error(),
 			"user_id", userID,
@@ -58,7 +87,55 @@ func (h *OfferHandler) List(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToResponse(offer))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	if filter.IsCursor() {
+		nextCursor := ""
+		if n := len(offers); n > 0 {
+			nextCursor = dto.EncodeCursor(offers[n-1].CreatedAt, offers[n-1].ID)
+		}
+		return c.JSON(dto.NewCursorResponse(items, hasMore, nextCursor))
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
+}
+
+// ListGrouped handles GET /api/v1/offers/by-listing
+func (h *OfferHandler) ListGrouped(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	status := c.Query("status")
+	switch status {
+	case "", "pending", "accepted", "rejected", "cancelled":
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "status must be one of pending, accepted, rejected, cancelled",
+			Code:    400,
+		})
+	}
+
+	var pag dto.Pagination
+	if err := c.QueryParser(&pag); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	items, count, err := h.service.ListGroupedBySeller(c.Context(), userID, status, pag.GetOffset(), pag.GetLimit())
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list offers by listing",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list offers",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, pag.GetPage(), pag.GetLimit(), count))
 }
 
 // GetByID handles GET /api/v1/offers/:id
@@ -110,6 +187,8 @@ func (h *OfferHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
+	req.IdempotencyKey = c.Get("Idempotency-Key")
+
 	if err := h.validator.Struct(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 			Error:   "validation_error",
@@ -119,37 +198,117 @@ func (h *OfferHandler) Create(c *fiber.Ctx) error {
 	}
 
 	offer, err := h.service.Create(c.Context(), userID, &req)
+	if err != nil {
+		return h.createError(c, userID, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(offer))
+}
+
+// createError maps an offer creation error to its response
+func (h *OfferHandler) createError(c *fiber.Ctx, userID string, err error) error {
+	var errs service.ValidationErrors
+	if errors.As(err, &errs) {
+		return validationFailed(c, errs)
+	}
+	if errors.Is(err, service.ErrRequestInProgress) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   "request_in_progress",
+			Message: "A request with this idempotency key is still being processed",
+			Code:    409,
+		})
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "not_found",
+			Message: "Listing or service not found",
+			Code:    404,
+		})
+	}
+	if errors.Is(err, service.ErrEmailNotVerified) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "email_not_verified",
+			Message: "Verify your email address before making offers",
+			Code:    403,
+		})
+	}
+	if errors.Is(err, service.ErrSelfAction) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "You cannot make an offer on your own listing or service",
+			Code:    400,
+		})
+	}
+	if errors.Is(err, service.ErrInvalidState) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Not available for offers",
+			Code:    400,
+		})
+	}
+	if errors.Is(err, service.ErrReputationTooLow) {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "reputation_too_low",
+			Message: "You don't meet the seller's rating or trade count requirements",
+			Code:    403,
+		})
+	}
+	if errors.Is(err, service.ErrOfferQueueFull) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error:   "offer_queue_full",
+			Message: "This listing is not accepting more offers right now",
+			Code:    409,
+		})
+	}
+	logger.FromContext(c.UserContext()).Error("failed to create offer",
+		"error", err.Error(),
+		"user_id", userID,
+	)
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error:   "internal_error",
+		Message: "Failed to create offer",
+		Code:    500,
+	})
+}
+
+// QuickCreate handles POST /api/v1/offers/quick
+func (h *OfferHandler) QuickCreate(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.QuickOfferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	offer, err := h.service.CreateFromTemplate(c.Context(), userID, req.TargetID, req.TemplateID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "not_found",
-				Message: "Listing or service not found",
+				Message: "Offer template, listing or service not found",
 				Code:    404,
 			})
 		}
-		if errors.Is(err, service.ErrSelfAction) {
-			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-				Error:   "bad_request",
-				Message: "You cannot make an offer on your own listing or service",
-				Code:    400,
-			})
-		}
-		if errors.Is(err, service.ErrInvalidState) {
-			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-				Error:   "bad_request",
-				Message: "Not available for offers",
-				Code:    400,
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only use your own offer templates",
+				Code:    403,
 			})
 		}
-		logger.FromContext(c.UserContext()).Error("failed to create offer",
-			"error", err.Error(),
-			"user_id", userID,
-		)
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create offer",
-			Code:    500,
-		})
+		return h.createError(c, userID, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(offer))
@@ -172,10 +331,17 @@ func (h *OfferHandler) Accept(c *fiber.Ctx) error {
 		if errors.Is(err, service.ErrForbidden) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "forbidden",
-				Message: "Only the owner can accept offers",
+				Message: "Only the owner can accept offers, or the requester a counteroffer",
 				Code:    403,
 			})
 		}
+		if errors.Is(err, service.ErrSelfAction) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "You cannot accept your own offer",
+				Code:    400,
+			})
+		}
 		if errors.Is(err, service.ErrInvalidState) {
 			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 				Error:   "bad_request",
@@ -183,6 +349,13 @@ func (h *OfferHandler) Accept(c *fiber.Ctx) error {
 				Code:    400,
 			})
 		}
+		if errors.Is(err, service.ErrListingChanged) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "listing_changed",
+				Message: "The listing was changed after this offer was made; the buyer needs to make a new offer",
+				Code:    409,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to accept offer",
 			"error", err.Error(),
 			"offer_id", id,
@@ -237,17 +410,24 @@ func (h *OfferHandler) Reject(c *fiber.Ctx) error {
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "not_found",
-				Message: "Offer or decline reason not found",
+				Message: "Offer, decline reason or decline template not found",
 				Code:    404,
 			})
 		}
 		if errors.Is(err, service.ErrForbidden) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "forbidden",
-				Message: "Only the owner can reject offers",
+				Message: "Only the owner (or the requester, for a counteroffer) can reject offers, using their own decline templates",
 				Code:    403,
 			})
 		}
+		if errors.Is(err, service.ErrSelfAction) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "You cannot reject your own offer",
+				Code:    400,
+			})
+		}
 		if errors.Is(err, service.ErrInvalidState) {
 			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 				Error:   "bad_request",
@@ -270,6 +450,118 @@ func (h *OfferHandler) Reject(c *fiber.Ctx) error {
 	return c.JSON(h.service.ToResponse(offer))
 }
 
+// BulkReject handles POST /api/v1/offers/bulk-reject
+func (h *OfferHandler) BulkReject(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.BulkRejectOffersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	result, err := h.service.BulkReject(c.Context(), userID, req.OfferIDs, &req.RejectOfferRequest)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Decline reason or decline template not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to bulk reject offers",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to reject offers",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// Counter handles POST /api/v1/offers/:id/counter
+func (h *OfferHandler) Counter(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.CounterOfferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	counter, err := h.service.Counter(c.Context(), id, userID, &req)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Offer not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Only the owner can counter offers",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "Only pending offers on available listings can be countered, and counters can't be countered again",
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to counter offer",
+			"error", err.Error(),
+			"offer_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to counter offer",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(counter))
+}
+
 // Cancel handles POST /api/v1/offers/:id/cancel
 func (h *OfferHandler) Cancel(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -287,7 +579,7 @@ func (h *OfferHandler) Cancel(c *fiber.Ctx) error {
 		if errors.Is(err, service.ErrForbidden) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "forbidden",
-				Message: "Only the requester can cancel their offer",
+				Message: "Only the requester can cancel their offer, or the owner their counteroffer",
 				Code:    403,
 			})
 		}
@@ -187,5 +187,24 @@ func (h *PremiumHandler) ListingCount(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(dto.ListingCountResponse{Count: len(listings)})
+	remaining, err := h.listingService.RemainingListings(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to compute remaining listings",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to count listings",
+			Code:    500,
+		})
+	}
+
+	resp := dto.ListingCountResponse{Count: len(listings), RemainingListings: remaining}
+	if remaining != nil {
+		limit := service.FreeListingLimit
+		resp.Limit = &limit
+	}
+
+	return c.JSON(resp)
 }
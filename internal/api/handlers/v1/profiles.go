@@ -3,6 +3,8 @@ package v1
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -60,6 +62,40 @@ func (h *ProfileHandler) GetByID(c *fiber.Ctx) error {
 	return c.JSON(h.service.ToResponse(profile))
 }
 
+// GetBatch handles POST /api/v1/profiles/batch
+func (h *ProfileHandler) GetBatch(c *fiber.Ctx) error {
+	var req dto.BatchProfilesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	profiles, err := h.service.GetByIDs(c.Context(), req.IDs)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to get profiles",
+			"error", err.Error(),
+			"count", len(req.IDs),
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get profiles",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.BatchProfilesResponse{Profiles: profiles})
+}
+
 // GetMe handles GET /api/v1/me
 func (h *ProfileHandler) GetMe(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -84,9 +120,31 @@ func (h *ProfileHandler) GetMe(c *fiber.Ctx) error {
 		})
 	}
 
+	h.service.EnsureOnboarded(c.Context(), profile)
+
 	return c.JSON(h.service.ToMyProfileResponse(profile))
 }
 
+// GetBadges handles GET /api/v1/me/badges
+func (h *ProfileHandler) GetBadges(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	counts, err := h.service.GetBadgeCounts(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to get badge counts",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to get badge counts",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(counts)
+}
+
 // UpdateMe handles PATCH /api/v1/me
 func (h *ProfileHandler) UpdateMe(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -131,6 +189,42 @@ func (h *ProfileHandler) UpdateMe(c *fiber.Ctx) error {
 	return c.JSON(h.service.ToMyProfileResponse(profile))
 }
 
+// ResendVerification handles POST /api/v1/me/verification/resend
+func (h *ProfileHandler) ResendVerification(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	if err := h.service.ResendVerification(c.Context(), userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Profile not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "already_verified",
+				Message: "Email is already verified",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to resend verification email",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resend verification email",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Success: true,
+		Message: "Verification email sent",
+	})
+}
+
 // UploadPicture handles POST /api/v1/me/picture
 func (h *ProfileHandler) UploadPicture(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -286,3 +380,223 @@ func (h *ProfileHandler) GetSales(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// ExportSales handles GET /api/v1/me/sales/export
+// Streams the current user's sales as a CSV attachment
+func (h *ProfileHandler) ExportSales(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.SalesExportRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	var from, to time.Time
+	if req.From != "" {
+		from, _ = time.Parse(time.DateOnly, req.From)
+	}
+	if req.To != "" {
+		// The range is inclusive of the whole "to" day
+		to, _ = time.Parse(time.DateOnly, req.To)
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "from must not be after to",
+			Code:    400,
+		})
+	}
+
+	reader, err := h.service.ExportSalesCSV(c.UserContext(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrExportTooLarge) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse{
+				Error:   "export_too_large",
+				Message: fmt.Sprintf("Too many sales to export at once (max %d), narrow the from/to range", service.MaxSalesExportRows),
+				Code:    422,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to export sales",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to export sales",
+			Code:    500,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="sales-%s.csv"`, time.Now().UTC().Format("20060102")))
+	return c.SendStream(reader)
+}
+
+// AdjustAbuseScore handles POST /api/v1/admin/profiles/:id/abuse-score
+func (h *ProfileHandler) AdjustAbuseScore(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req dto.AdjustAbuseScoreRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	score, err := h.service.AdjustAbuseScore(c.Context(), id, req.Delta, req.Reason)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Profile not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to adjust abuse score",
+			"error", err.Error(),
+			"profile_id", id,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to adjust abuse score",
+			Code:    500,
+		})
+	}
+
+	threshold := h.service.ShadowThrottleScore()
+	return c.JSON(dto.AbuseScoreResponse{
+		ProfileID:       id,
+		AbuseScore:      score,
+		ShadowThrottled: threshold > 0 && score >= threshold,
+	})
+}
+
+// ListAPITokens handles GET /api/v1/me/api-tokens
+func (h *ProfileHandler) ListAPITokens(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	tokens, err := h.service.ListTokens(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list api tokens",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list API tokens",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		items = append(items, *h.service.ToAPITokenResponse(token))
+	}
+
+	return c.JSON(items)
+}
+
+// CreateAPIToken handles POST /api/v1/me/api-tokens
+func (h *ProfileHandler) CreateAPIToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.CreateAPITokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	token, raw, err := h.service.CreateToken(c.Context(), userID, req.Name, req.Scopes)
+	if err != nil {
+		if errors.Is(err, service.ErrAPITokenLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "api_token_limit_reached",
+				Message: fmt.Sprintf("You can have at most %d active API tokens.", service.MaxAPITokens),
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to create api token",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create API token",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreateAPITokenResponse{
+		APITokenResponse: *h.service.ToAPITokenResponse(token),
+		Token:            raw,
+	})
+}
+
+// RevokeAPIToken handles DELETE /api/v1/me/api-tokens/:id
+func (h *ProfileHandler) RevokeAPIToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	if err := h.service.RevokeToken(c.Context(), userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "API token not found",
+				Code:    404,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to revoke api token",
+			"error", err.Error(),
+			"api_token_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to revoke API token",
+			Code:    500,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 )
 
@@ -49,6 +51,10 @@ func (h *RatingHandler) Create(c *fiber.Ctx) error {
 
 	rating, err := h.service.Create(c.Context(), userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "not_found",
@@ -70,6 +76,13 @@ func (h *RatingHandler) Create(c *fiber.Ctx) error {
 				Code:    409,
 			})
 		}
+		if errors.Is(err, service.ErrDisputeOpen) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "dispute_open",
+				Message: "This transaction can't be rated until its dispute is resolved",
+				Code:    409,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to create rating",
 			"error", err.Error(),
 			"user_id", userID,
@@ -87,6 +100,21 @@ func (h *RatingHandler) Create(c *fiber.Ctx) error {
 
 // GetByProfileID handles GET /api/v1/profiles/:id/ratings
 func (h *RatingHandler) GetByProfileID(c *fiber.Ctx) error {
+	return h.listRatings(c, h.service.GetByUserID)
+}
+
+// GetReceived handles GET /api/v1/profiles/:id/ratings/received
+func (h *RatingHandler) GetReceived(c *fiber.Ctx) error {
+	return h.listRatings(c, h.service.GetReceived)
+}
+
+// GetGiven handles GET /api/v1/profiles/:id/ratings/given
+func (h *RatingHandler) GetGiven(c *fiber.Ctx) error {
+	return h.listRatings(c, h.service.GetGiven)
+}
+
+// listRatings pages a profile's ratings loaded by load
+func (h *RatingHandler) listRatings(c *fiber.Ctx, load func(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error)) error {
 	profileID := c.Params("id")
 
 	var filter dto.RatingsFilterRequest
@@ -98,7 +126,7 @@ func (h *RatingHandler) GetByProfileID(c *fiber.Ctx) error {
 		})
 	}
 
-	ratings, count, err := h.service.GetByUserID(c.Context(), profileID, filter.GetOffset(), filter.GetLimit())
+	ratings, count, err := load(c.Context(), profileID, filter.GetOffset(), filter.GetLimit())
 	if err != nil {
 		logger.FromContext(c.UserContext()).Error("failed to get ratings",
 			"error", err.Error(),
@@ -117,5 +145,5 @@ func (h *RatingHandler) GetByProfileID(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToResponse(rating))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
 }
@@ -57,7 +57,7 @@ func (h *ServiceRunHandler) List(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToResponseWithUser(c.Context(), run, userID))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // GetByID handles GET /api/v1/service-runs/:id
@@ -184,3 +184,161 @@ func (h *ServiceRunHandler) Cancel(c *fiber.Ctx) error {
 
 	return c.JSON(h.service.ToDetailResponse(c.Context(), run, userID))
 }
+
+// OpenDispute handles POST /api/v1/service-runs/:id/dispute
+func (h *ServiceRunHandler) OpenDispute(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.OpenDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	dispute, err := h.service.OpenDispute(c.Context(), id, userID, req.Reason)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Service run not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Only participants can dispute a service run",
+				Code:    403,
+			})
+		}
+		if errors.Is(err, service.ErrAlreadyExists) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "conflict",
+				Message: "This service run is already disputed",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Only active or completed service runs can be disputed",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to open service run dispute",
+			"error", err.Error(),
+			"service_run_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to open dispute",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToDisputeResponse(dispute))
+}
+
+// ListDisputes handles GET /api/v1/admin/disputes
+func (h *ServiceRunHandler) ListDisputes(c *fiber.Ctx) error {
+	var filter dto.DisputesFilterRequest
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	disputes, count, err := h.service.ListDisputes(c.Context(), filter.Status, filter.TargetType, filter.GetOffset(), filter.GetLimit())
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list disputes",
+			"error", err.Error(),
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list disputes",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.DisputeResponse, 0, len(disputes))
+	for _, dispute := range disputes {
+		items = append(items, *h.service.ToDisputeResponse(dispute))
+	}
+
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
+}
+
+// ResolveDispute handles POST /api/v1/admin/disputes/:id/resolve
+func (h *ServiceRunHandler) ResolveDispute(c *fiber.Ctx) error {
+	adminID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	var req dto.ResolveDisputeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+	}
+
+	dispute, run, err := h.service.ResolveDispute(c.Context(), id, adminID, req.Outcome, req.Note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Dispute not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrInvalidState) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "invalid_state",
+				Message: "Dispute is not open or is not about a service run",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to resolve dispute",
+			"error", err.Error(),
+			"dispute_id", id,
+			"admin_id", adminID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to resolve dispute",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.ResolveDisputeResponse{
+		Dispute:    h.service.ToDisputeResponse(dispute),
+		ServiceRun: h.service.ToResponse(run),
+	})
+}
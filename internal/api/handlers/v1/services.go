@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -56,6 +57,13 @@ func (h *ServiceHandler) Search(c *fiber.Ctx) error {
 
 	providers, count, err := h.service.ListProviders(c.Context(), repoFilter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRegion) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Unknown region",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to search services",
 			"error", err.Error(),
 		)
@@ -66,7 +74,7 @@ func (h *ServiceHandler) Search(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(providers, pag.Page, pag.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(providers, pag.GetPage(), pag.GetLimit(), count))
 }
 
 // ListProviders handles GET /api/v1/services
@@ -99,6 +107,13 @@ func (h *ServiceHandler) ListProviders(c *fiber.Ctx) error {
 
 	providers, count, err := h.service.ListProviders(c.Context(), repoFilter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRegion) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Unknown region",
+				Code:    400,
+			})
+		}
 		logger.FromContext(c.UserContext()).Error("failed to list service providers",
 			"error", err.Error(),
 		)
@@ -109,7 +124,7 @@ func (h *ServiceHandler) ListProviders(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(providers, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(providers, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // GetProviderDetail handles GET /api/v1/services/providers/:id
@@ -159,16 +174,27 @@ func (h *ServiceHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.validator.Struct(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-			Code:    400,
-		})
+	req.IdempotencyKey = c.Get("Idempotency-Key")
+
+	verrs := collectValidationErrors(h.validator, &req)
+	verrs.Merge(h.service.ValidateCreate(&req))
+	if len(verrs) > 0 {
+		return validationFailed(c, verrs)
 	}
 
 	svc, err := h.service.Create(c.Context(), userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrRequestInProgress) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "request_in_progress",
+				Message: "A request with this idempotency key is still being processed",
+				Code:    409,
+			})
+		}
 		if errors.Is(err, service.ErrAlreadyExists) {
 			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
 				Error:   "already_exists",
@@ -176,6 +202,9 @@ func (h *ServiceHandler) Create(c *fiber.Ctx) error {
 				Code:    409,
 			})
 		}
+		if errors.Is(err, service.ErrServiceLimitReached) {
+			return h.serviceLimitReached(c)
+		}
 		logger.FromContext(c.UserContext()).Error("failed to create service",
 			"error", err.Error(),
 			"user_id", userID,
@@ -214,6 +243,17 @@ func (h *ServiceHandler) Update(c *fiber.Ctx) error {
 
 	svc, err := h.service.Update(c.Context(), id, userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrInvalidRegion) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Unknown region",
+				Code:    400,
+			})
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "not_found",
@@ -350,6 +390,9 @@ func (h *ServiceHandler) Resume(c *fiber.Ctx) error {
 				Code:    409,
 			})
 		}
+		if errors.Is(err, service.ErrServiceLimitReached) {
+			return h.serviceLimitReached(c)
+		}
 		logger.FromContext(c.UserContext()).Error("failed to resume service",
 			"error", err.Error(),
 			"service_id", id,
@@ -396,5 +439,15 @@ func (h *ServiceHandler) ListMy(c *fiber.Ctx) error {
 		items = append(items, *h.service.ToServiceResponse(svc))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(items, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(items, filter.GetPage(), filter.GetLimit(), count))
+}
+
+// serviceLimitReached responds with 403 and the provider's active service caps
+func (h *ServiceHandler) serviceLimitReached(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+		Error: "service_limit_reached",
+		Message: fmt.Sprintf("Free providers can have at most %d active services (premium: %d). Pause or delete a service first.",
+			h.service.ServiceLimit(false), h.service.ServiceLimit(true)),
+		Code: 403,
+	})
 }
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"bufio"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamHeartbeatInterval is how often a comment is written to keep the stream
+// open through proxies and to detect disconnected clients
+const streamHeartbeatInterval = 25 * time.Second
+
+// sseStream is the event source behind a Server-Sent Events response
+type sseStream struct {
+	// Initial events are written before any live ones
+	Initial []string
+	// Messages delivers live events; the stream ends when it is closed
+	Messages <-chan string
+	// OnHeartbeat, if set, runs after each heartbeat the client received
+	OnHeartbeat func()
+	// Close releases the subscription once the stream ends
+	Close func()
+}
+
+// writeSSE sets the event-stream headers and streams s to the client: a retry hint,
+// the initial events, then live events with a heartbeat comment in between, until
+// either side goes away. s.Close runs when the stream ends.
+func writeSSE(c *fiber.Ctx, s sseStream) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache, no-store")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer s.Close()
+
+		write := func(frame string) error {
+			// Push the write deadline forward so the server write timeout doesn't end the stream
+			if conn != nil {
+				_ = conn.SetWriteDeadline(time.Now().Add(2 * streamHeartbeatInterval))
+			}
+			if _, err := w.WriteString(frame); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		if err := write("retry: 5000\n\n"); err != nil {
+			return
+		}
+		for _, event := range s.Initial {
+			if err := write("data: " + event + "\n\n"); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case msg, ok := <-s.Messages:
+				if !ok {
+					return
+				}
+				if err := write("data: " + msg + "\n\n"); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if err := write(": ping\n\n"); err != nil {
+					return
+				}
+				if s.OnHeartbeat != nil {
+					s.OnHeartbeat()
+				}
+			}
+		}
+	})
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
@@ -73,4 +75,35 @@ func (h *StatsHandler) GetRecentServices(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(services)
-}
\ No newline at end of file
+}
+
+// GetItemPriceSummary returns what an item typically sells for
+// GET /api/v1/marketplace/price-summary
+func (h *StatsHandler) GetItemPriceSummary(c *fiber.Ctx) error {
+	itemName := c.Query("item")
+	if itemName == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "item query parameter is required",
+			Code:    400,
+		})
+	}
+
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil {
+			days = parsed
+		}
+	}
+
+	summary, err := h.service.GetItemPriceSummary(c.UserContext(), itemName, c.Query("game"), days)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve price summary",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(summary)
+}
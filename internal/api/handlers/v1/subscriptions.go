@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
@@ -106,7 +109,16 @@ func (h *SubscriptionHandler) Cancel(c *fiber.Ctx) error {
 func (h *SubscriptionHandler) BillingHistory(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 
-	resp, err := h.service.GetBillingHistory(c.Context(), userID)
+	var req dto.BillingHistoryRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid query parameters",
+			Code:    400,
+		})
+	}
+
+	resp, err := h.service.GetBillingHistory(c.Context(), userID, req.Locale)
 	if err != nil {
 		logger.FromContext(c.UserContext()).Error("failed to get billing history",
 			"error", err.Error(),
@@ -121,3 +133,65 @@ func (h *SubscriptionHandler) BillingHistory(c *fiber.Ctx) error {
 
 	return c.JSON(resp)
 }
+
+// Gift handles POST /api/v1/subscriptions/gift
+func (h *SubscriptionHandler) Gift(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.GiftCheckoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+	if req.Recipient == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "recipient is required",
+			Code:    400,
+		})
+	}
+
+	resp, err := h.service.CreateGiftCheckout(c.Context(), userID, req.Recipient, req.PriceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPriceID):
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "invalid_price_id",
+				Message: "The provided price ID is not valid",
+				Code:    400,
+			})
+		case errors.Is(err, sql.ErrNoRows):
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Recipient not found",
+				Code:    404,
+			})
+		case errors.Is(err, service.ErrSelfAction):
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "bad_request",
+				Message: "You cannot gift premium to yourself",
+				Code:    400,
+			})
+		case errors.Is(err, service.ErrAlreadyPremium):
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "already_premium",
+				Message: "The recipient already has premium",
+				Code:    409,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to create gift checkout session",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create checkout session",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// collectValidationErrors runs struct validation on req and returns every
+// failure keyed by the request's JSON field name
+func collectValidationErrors(v *validator.Validate, req any) service.ValidationErrors {
+	errs := service.ValidationErrors{}
+
+	var fieldErrs validator.ValidationErrors
+	if err := v.Struct(req); !errors.As(err, &fieldErrs) {
+		return errs
+	}
+
+	reqType := reflect.TypeOf(req)
+	if reqType.Kind() == reflect.Pointer {
+		reqType = reqType.Elem()
+	}
+	for _, fe := range fieldErrs {
+		errs.Add(jsonFieldName(reqType, fe), validationMessage(fe))
+	}
+	return errs
+}
+
+// jsonFieldName maps a validator field error back to the JSON path the client
+// sent, keeping the index of slice elements (e.g. platforms[1])
+func jsonFieldName(reqType reflect.Type, fe validator.FieldError) string {
+	field, index := fe.StructField(), ""
+	if i := strings.Index(field, "["); i >= 0 {
+		field, index = field[:i], field[i:]
+	}
+	f, ok := reqType.FieldByName(field)
+	if !ok {
+		return fe.Field()
+	}
+	tag := strings.Split(f.Tag.Get("json"), ",")[0]
+	if tag == "" || tag == "-" {
+		return fe.Field()
+	}
+	return tag + index
+}
+
+// validationMessage turns a validator tag into a short client-facing message
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "url":
+		return "must be a valid URL"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+// validationFailed responds with 422 and every field problem at once
+func validationFailed(c *fiber.Ctx, errs service.ValidationErrors) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ValidationErrorResponse{
+		Error:   "validation_error",
+		Message: "One or more fields are invalid",
+		Code:    422,
+		Fields:  errs,
+	})
+}
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/middleware"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+// WatchHandler handles item watch endpoints
+type WatchHandler struct {
+	service   *service.WatchService
+	validator *validator.Validate
+}
+
+// NewWatchHandler creates a new watch handler
+func NewWatchHandler(service *service.WatchService) *WatchHandler {
+	return &WatchHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List handles GET /api/v1/watches
+func (h *WatchHandler) List(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	watches, err := h.service.List(c.Context(), userID)
+	if err != nil {
+		logger.FromContext(c.UserContext()).Error("failed to list item watches",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list watches",
+			Code:    500,
+		})
+	}
+
+	items := make([]dto.WatchResponse, 0, len(watches))
+	for _, watch := range watches {
+		items = append(items, *h.service.ToResponse(watch))
+	}
+
+	return c.JSON(items)
+}
+
+// Create handles POST /api/v1/watches
+func (h *WatchHandler) Create(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.CreateWatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	if verrs := collectValidationErrors(h.validator, &req); len(verrs) > 0 {
+		return validationFailed(c, verrs)
+	}
+
+	watch, err := h.service.Create(c.Context(), userID, &req)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrAlreadyExists) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "already_watching",
+				Message: "You are already watching this item",
+				Code:    409,
+			})
+		}
+		if errors.Is(err, service.ErrWatchLimitReached) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "watch_limit_reached",
+				Message: fmt.Sprintf("Free accounts can watch at most %d items. Upgrade to premium for unlimited watches.", service.FreeWatchLimit),
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to create item watch",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create watch",
+			Code:    500,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(watch))
+}
+
+// Delete handles DELETE /api/v1/watches/:id
+func (h *WatchHandler) Delete(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	id := c.Params("id")
+
+	err := h.service.Delete(c.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+				Error:   "not_found",
+				Message: "Watch not found",
+				Code:    404,
+			})
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only delete your own watches",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to delete item watch",
+			"error", err.Error(),
+			"item_watch_id", id,
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to delete watch",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(dto.SuccessResponse{Success: true, Message: "Watch deleted"})
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -39,16 +41,18 @@ func (h *WishlistHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.validator.Struct(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-			Code:    400,
-		})
+	verrs := collectValidationErrors(h.validator, &req)
+	verrs.Merge(h.service.ValidateCreate(&req))
+	if len(verrs) > 0 {
+		return validationFailed(c, verrs)
 	}
 
 	item, err := h.service.Create(c.Context(), userID, &req)
 	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
 		if errors.Is(err, service.ErrPremiumRequired) {
 			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
 				Error:   "premium_required",
@@ -77,6 +81,92 @@ func (h *WishlistHandler) Create(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(h.service.ToResponse(item))
 }
 
+// Import handles POST /api/v1/wishlist/import
+func (h *WishlistHandler) Import(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	var req dto.ImportWishlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "bad_request",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	// Malformed items reject the whole import; business rules are reported per item
+	verrs := service.ValidationErrors{}
+	for i := range req.Items {
+		for field, message := range collectValidationErrors(h.validator, &req.Items[i]) {
+			verrs.Add(fmt.Sprintf("items[%d].%s", i, field), message)
+		}
+	}
+	if len(verrs) > 0 {
+		return validationFailed(c, verrs)
+	}
+
+	result, err := h.service.ImportBatch(c.Context(), userID, req.Items)
+	if err != nil {
+		var errs service.ValidationErrors
+		if errors.As(err, &errs) {
+			return validationFailed(c, errs)
+		}
+		if errors.Is(err, service.ErrPremiumRequired) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "premium_required",
+				Message: "Wishlist is a premium feature. Upgrade to premium to use it.",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to import wishlist items",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to import wishlist items",
+			Code:    500,
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// Export handles GET /api/v1/wishlist/export
+func (h *WishlistHandler) Export(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+
+	items, err := h.service.ExportAll(c.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrPremiumRequired) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "premium_required",
+				Message: "Wishlist is a premium feature. Upgrade to premium to use it.",
+				Code:    403,
+			})
+		}
+		logger.FromContext(c.UserContext()).Error("failed to export wishlist items",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to export wishlist items",
+			Code:    500,
+		})
+	}
+
+	responses := make([]dto.WishlistItemResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, *h.service.ToResponse(item))
+	}
+
+	return c.JSON(dto.WishlistExportResponse{
+		ExportedAt: time.Now().UTC(),
+		Items:      responses,
+	})
+}
+
 // List handles GET /api/v1/wishlist
 func (h *WishlistHandler) List(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
@@ -115,7 +205,7 @@ func (h *WishlistHandler) List(c *fiber.Ctx) error {
 		responses = append(responses, *h.service.ToResponse(item))
 	}
 
-	return c.JSON(dto.NewPaginatedResponse(responses, filter.Page, filter.GetLimit(), count))
+	return c.JSON(dto.NewPaginatedResponse(responses, filter.GetPage(), filter.GetLimit(), count))
 }
 
 // Update handles PATCH /api/v1/wishlist/:id
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
+)
+
+const (
+	// APITokenHeader carries a personal access token from a third-party integration
+	APITokenHeader = "X-API-Key"
+	// APITokenIDKey is the key used to store the authenticated API token ID in fiber context
+	APITokenIDKey = "api_token_id"
+)
+
+// APITokenMiddleware authenticates personal access tokens on public read endpoints.
+// Requests without an X-API-Key header pass through untouched. A token must be valid,
+// hold the scope and be within its per-minute limit. It never sets a user ID, so a
+// token can't reach anything tied to the owner's session.
+func APITokenMiddleware(profileService *service.ProfileService, scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := c.Get(APITokenHeader)
+		if raw == "" {
+			return c.Next()
+		}
+
+		token, err := profileService.AuthenticateToken(c.Context(), raw)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAPIToken) {
+				return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+					Error:   "invalid_api_token",
+					Message: "API token is invalid or has been revoked",
+					Code:    401,
+				})
+			}
+			logger.FromContext(c.UserContext()).Error("failed to authenticate api token",
+				"error", err.Error(),
+			)
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to verify API token",
+				Code:    500,
+			})
+		}
+
+		if !token.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "insufficient_scope",
+				Message: "API token lacks the " + scope + " scope",
+				Code:    403,
+			})
+		}
+
+		remaining, err := profileService.ConsumeTokenRequest(c.Context(), token)
+		c.Set("X-RateLimit-Limit", strconv.Itoa(token.RateLimit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if errors.Is(err, service.ErrRateLimited) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+				Error:   "rate_limit_exceeded",
+				Message: "API token request limit reached. Please try again later.",
+				Code:    429,
+			})
+		}
+		if err != nil {
+			// Don't fail reads because the limiter is unreachable
+			logger.FromContext(c.UserContext()).Warn("failed to count api token request",
+				"error", err.Error(),
+				"api_token_id", token.ID,
+			)
+		}
+
+		c.Locals(APITokenIDKey, token.ID)
+		return c.Next()
+	}
+}
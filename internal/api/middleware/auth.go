@@ -286,6 +286,7 @@ func NewAuthMiddleware(config AuthConfig) fiber.Handler {
 
 		// Store user ID in context for handlers to use
 		c.Locals(UserIDKey, userID)
+		setLogUserID(c, userID)
 
 		return c.Next()
 	}
@@ -373,6 +374,7 @@ func OptionalAuthMiddleware(config AuthConfig) fiber.Handler {
 		if ok && userID != "" {
 			debugLog(config, "[Optional] Authentication successful for user: %s", userID)
 			c.Locals(UserIDKey, userID)
+			setLogUserID(c, userID)
 		} else {
 			debugLog(config, "[Optional] Missing or invalid 'sub' claim, continuing without auth")
 		}
@@ -7,10 +7,14 @@ import (
 )
 
 // CacheControl returns middleware that sets Cache-Control headers on successful responses.
+// A Cache-Control header set by the handler itself is left untouched.
 func CacheControl(maxAgeSeconds int) fiber.Handler {
 	value := fmt.Sprintf("public, max-age=%d, s-maxage=%d", maxAgeSeconds, maxAgeSeconds)
 	return func(c *fiber.Ctx) error {
 		err := c.Next()
+		if len(c.Response().Header.Peek(fiber.HeaderCacheControl)) > 0 {
+			return err
+		}
 		if c.Response().StatusCode() >= 200 && c.Response().StatusCode() < 300 {
 			c.Set("Cache-Control", value)
 		}
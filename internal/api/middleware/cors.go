@@ -18,7 +18,7 @@ func NewCORSMiddleware(config CORSConfig) fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins:     config.AllowedOrigins,
 		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID,Idempotency-Key",
 		AllowCredentials: allowCredentials,
 		ExposeHeaders:    "X-Request-ID,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset",
 		MaxAge:           86400, // 24 hours
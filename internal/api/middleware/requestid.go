@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
@@ -9,25 +12,35 @@ import (
 const (
 	// RequestIDHeader is the HTTP header for request ID
 	RequestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLength bounds client-supplied request IDs before they reach the logs
+	maxRequestIDLength = 128
 )
 
-// RequestID middleware generates a unique request ID for each request
+// RequestID middleware generates a unique request ID for each request, attaches it
+// to the logging context and adds it to JSON error responses as "requestId"
 func RequestID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Check if request ID was provided in header
 		requestID := c.Get(RequestIDHeader)
-		if requestID == "" {
+		if !validRequestID(requestID) {
 			requestID = uuid.New().String()
 		}
 
 		// Add request ID to response header
 		c.Set(RequestIDHeader, requestID)
 
-		// Store request ID in context for logging
+		// Store request ID in context for logging. Handlers pass c.Context() to
+		// services as often as c.UserContext(), so it's set on both.
+		c.Locals(logger.RequestIDKey, requestID)
 		ctx := logger.WithRequestID(c.Context(), requestID)
 		c.SetUserContext(ctx)
 
-		return c.Next()
+		err := c.Next()
+		if err == nil {
+			addRequestIDToError(c, requestID)
+		}
+		return err
 	}
 }
 
@@ -38,3 +51,51 @@ func GetRequestID(c *fiber.Ctx) string {
 	}
 	return ""
 }
+
+// setLogUserID attaches the authenticated user to the logging context
+func setLogUserID(c *fiber.Ctx, userID string) {
+	c.Locals(logger.UserIDKey, userID)
+	c.SetUserContext(logger.WithUserID(c.UserContext(), userID))
+}
+
+// validRequestID accepts a client-supplied request ID only if it is short and
+// printable ASCII, so it can't forge log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// addRequestIDToError appends "requestId" to a JSON error body so users can quote
+// it to support. Other responses are left untouched.
+func addRequestIDToError(c *fiber.Ctx, requestID string) {
+	resp := c.Response()
+	if resp.StatusCode() < fiber.StatusBadRequest ||
+		!strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return
+	}
+
+	body := resp.Body()
+	var errBody struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errBody) != nil || errBody.Error == "" {
+		return
+	}
+
+	id, err := json.Marshal(requestID)
+	if err != nil {
+		return
+	}
+	trimmed := strings.TrimRight(string(body), " \t\r\n")
+	if !strings.HasSuffix(trimmed, "}") {
+		return
+	}
+	resp.SetBodyString(trimmed[:len(trimmed)-1] + `,"requestId":` + string(id) + "}")
+}
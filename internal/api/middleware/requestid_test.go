@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+)
+
+func newRequestIDTestApp(handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Get("/", handler)
+	return app
+}
+
+func TestRequestID_PropagatesToBothContexts(t *testing.T) {
+	var fromCtx, fromUserCtx any
+	app := newRequestIDTestApp(func(c *fiber.Ctx) error {
+		setLogUserID(c, "user-1")
+		fromCtx = c.Context().Value(logger.RequestIDKey)
+		fromUserCtx = c.UserContext().Value(logger.RequestIDKey)
+		assert.Equal(t, "user-1", c.Context().Value(logger.UserIDKey))
+		assert.Equal(t, "user-1", c.UserContext().Value(logger.UserIDKey))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "client-req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "client-req-1", resp.Header.Get(RequestIDHeader))
+	assert.Equal(t, "client-req-1", fromCtx)
+	assert.Equal(t, "client-req-1", fromUserCtx)
+}
+
+func TestRequestID_ReplacesInvalidClientID(t *testing.T) {
+	app := newRequestIDTestApp(func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, id := range []string{"has space", strings.Repeat("a", maxRequestIDLength+1)} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, id)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		got := resp.Header.Get(RequestIDHeader)
+		assert.NotEqual(t, id, got)
+		assert.Len(t, got, 36)
+	}
+}
+
+func TestRequestID_AddedToErrorResponses(t *testing.T) {
+	app := newRequestIDTestApp(func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error:   "not_found",
+			Message: "Listing not found",
+			Code:    404,
+		})
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "req-404")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "not_found", body["error"])
+	assert.Equal(t, "req-404", body["requestId"])
+}
+
+func TestRequestID_SuccessBodyUntouched(t *testing.T) {
+	app := newRequestIDTestApp(func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"error": "not really"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "requestId")
+}
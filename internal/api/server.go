@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,6 +16,7 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
 	applogger "github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/service"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage"
@@ -26,7 +28,13 @@ type Server struct {
 	db      *database.BunDB
 	redis   *cache.RedisClient
 	storage storage.Storage
-	config  *Config
+	// listingStorage holds listing images, separate from avatars
+	listingStorage storage.Storage
+	config         *Config
+	// jobs is cancelled on shutdown to stop background loops; jobsDone waits for them
+	jobs     context.Context
+	stopJobs context.CancelFunc
+	jobsDone sync.WaitGroup
 }
 
 // Config holds server configuration
@@ -52,6 +60,72 @@ type Config struct {
 	StripeSuccessURL      string
 	StripeCancelURL       string
 	StripeAllowedPriceIDs []string
+	// PremiumGiftExtends allows gifting premium to a user who already has it
+	PremiumGiftExtends bool
+	// Email verification configuration
+	SupabaseAnonKey      string
+	RequireEmailVerified bool
+	// WishlistMatchWorkers caps concurrent wishlist matching runs (0 uses the service default)
+	WishlistMatchWorkers int
+	// PauseListingOnAccept moves a listing to "pending" while a trade from an accepted offer runs
+	PauseListingOnAccept bool
+	// ImageWebPConversion stores uploaded PNG and JPEG avatars and listing images as WebP
+	ImageWebPConversion bool
+	// ResponseTimeWindow is how far back offers count toward a seller's median response time (0 disables)
+	ResponseTimeWindow time.Duration
+	// WishlistMatchGroupWindow collapses a user's wishlist matches within the window into one notification (0 disables)
+	WishlistMatchGroupWindow time.Duration
+	// WelcomeNotification sends new users a welcome notification on first login
+	WelcomeNotification bool
+	// RelistCooldown blocks relisting an item identical to a recent sale (0 disables)
+	RelistCooldown time.Duration
+	// MaxActiveServices and MaxActiveServicesPremium cap active services per provider (0 uses the service defaults)
+	MaxActiveServices        int
+	MaxActiveServicesPremium int
+	// AbuseThrottleThreshold shadow-throttles sellers at or above this abuse score (0 disables)
+	AbuseThrottleThreshold int
+	// AbuseThrottleDelay holds a throttled seller's new listings out of home:recent
+	AbuseThrottleDelay time.Duration
+	// AbuseChurnLimit is how many listings a seller may cancel within an hour of creating them per day
+	// before each further one raises their abuse score (0 disables)
+	AbuseChurnLimit int
+	// ItemImageCheck swaps item images missing from storage for ItemImagePlaceholderURL
+	ItemImageCheck          bool
+	ItemImagePlaceholderURL string
+	// MarketEvents logs a value event for every completed trade and service run
+	MarketEvents bool
+	// MaxNotesLength, MaxDescriptionLength and MaxCommentLength cap listing/service notes,
+	// service descriptions and rating comments in characters (0 keeps the defaults)
+	MaxNotesLength       int
+	MaxDescriptionLength int
+	MaxCommentLength     int
+	// ChatArchive archives trade and service run chats ChatArchiveGrace after they resolve
+	ChatArchive      bool
+	ChatArchiveGrace time.Duration
+	// FeatureFlags is the rollout spec, e.g. "fuzzy_search=100,realtime_chat=25" (empty uses the defaults)
+	FeatureFlags string
+	// RuneValues overrides shipped rune values in Ist, e.g. "ber=9,jah=8" (empty uses the shipped values)
+	RuneValues string
+	// DuplicateListings is what creating a listing identical to an active one does: allow, reject or reuse
+	DuplicateListings string
+	// ListingLifetime and PremiumListingLifetime are how long free and premium listings stay up (0 uses the service defaults)
+	ListingLifetime        time.Duration
+	PremiumListingLifetime time.Duration
+	// ExpiryWarningHours is how long before expiry the notify-expiring job warns sellers by default
+	ExpiryWarningHours int
+	// FreeDailyBumps and PremiumDailyBumps are how many listing refreshes a seller gets per UTC day (0 uses the service defaults)
+	FreeDailyBumps    int
+	PremiumDailyBumps int
+	// SandboxMode fakes Stripe calls and skips push/email sends; storage is swapped by the caller
+	SandboxMode bool
+	// DeliveryWebhookURL is the push/email relay notifications are posted to (empty disables
+	// out-of-app delivery); DeliveryWebhookSecret is sent to it as a bearer token
+	DeliveryWebhookURL    string
+	DeliveryWebhookSecret string
+	// APITokenRateLimit is the per-minute request limit given to new personal access tokens (0 uses the service default)
+	APITokenRateLimit int
+	// ViewFlushInterval buffers listing views in Redis and flushes them this often (0 writes every view directly)
+	ViewFlushInterval time.Duration
 }
 
 // DefaultConfig returns default server configuration
@@ -65,7 +139,7 @@ func DefaultConfig() *Config {
 }
 
 // NewServer creates a new HTTP server
-func NewServer(db *database.BunDB, redis *cache.RedisClient, stor storage.Storage, config *Config) *Server {
+func NewServer(db *database.BunDB, redis *cache.RedisClient, stor storage.Storage, listingStor storage.Storage, config *Config) *Server {
 	if config == nil {
 		config = DefaultConfig()
 	}
@@ -76,12 +150,16 @@ func NewServer(db *database.BunDB, redis *cache.RedisClient, stor storage.Storag
 		AppName:      "LootStash Marketplace API",
 	})
 
+	jobs, stopJobs := context.WithCancel(context.Background())
 	server := &Server{
-		app:     app,
-		db:      db,
-		redis:   redis,
-		storage: stor,
-		config:  config,
+		app:            app,
+		db:             db,
+		redis:          redis,
+		storage:        stor,
+		listingStorage: listingStor,
+		config:         config,
+		jobs:           jobs,
+		stopJobs:       stopJobs,
 	}
 
 	server.setupMiddleware()
@@ -96,7 +174,7 @@ func (s *Server) setupMiddleware() {
 
 	// Logger middleware - simple format like catalog-api
 	s.app.Use(logger.New(logger.Config{
-		Format:     "${time} ${status} ${method} ${path}\t${latency}\n",
+		Format:     "${time} ${status} ${method} ${path}\t${latency}\t${respHeader:X-Request-ID}\n",
 		TimeFormat: "2006-01-02 15:04:05",
 	}))
 
@@ -122,28 +200,107 @@ func (s *Server) setupRoutes() {
 	chatRepo := repository.NewChatRepository(s.db)
 	messageRepo := repository.NewMessageRepository(s.db)
 	notificationRepo := repository.NewNotificationRepository(s.db)
+	notificationPrefsRepo := repository.NewNotificationPreferencesRepository(s.db)
 	transactionRepo := repository.NewTransactionRepository(s.db)
 	ratingRepo := repository.NewRatingRepository(s.db)
 	statsRepo := repository.NewStatsRepository(s.db)
 	billingEventRepo := repository.NewBillingEventRepository(s.db)
 	serviceRepo := repository.NewServiceRepository(s.db)
 	serviceRunRepo := repository.NewServiceRunRepository(s.db)
+	disputeRepo := repository.NewDisputeRepository(s.db)
+	marketEventRepo := repository.NewMarketEventRepository(s.db)
 
 	// Create repositories (wishlist, bug reports)
 	wishlistRepo := repository.NewWishlistRepository(s.db)
 	bugReportRepo := repository.NewBugReportRepository(s.db)
+	declineTemplateRepo := repository.NewDeclineTemplateRepository(s.db)
+	offerTemplateRepo := repository.NewOfferTemplateRepository(s.db)
+	apiTokenRepo := repository.NewAPITokenRepository(s.db)
+	watchRepo := repository.NewWatchRepository(s.db)
+	favoriteRepo := repository.NewFavoriteRepository(s.db)
+	delegateRepo := repository.NewDelegateRepository(s.db)
 
 	// Create services
+	textLimits := service.TextLimits{
+		Notes:       s.config.MaxNotesLength,
+		Description: s.config.MaxDescriptionLength,
+		Comment:     s.config.MaxCommentLength,
+	}
 	profileService := service.NewProfileService(profileRepo, s.redis, s.storage)
 	profileService.SetTransactionRepository(transactionRepo)
+	profileService.SetEmailVerificationConfig(service.EmailVerificationConfig{
+		Required:    s.config.RequireEmailVerified,
+		SupabaseURL: s.config.SupabaseURL,
+		APIKey:      s.config.SupabaseAnonKey,
+	})
 	notificationService := service.NewNotificationService(notificationRepo, s.redis)
+	notificationService.SetProfileService(profileService)
+	notificationService.SetPreferencesRepository(notificationPrefsRepo)
+	notificationService.SetSandboxMode(s.config.SandboxMode)
+	if s.config.DeliveryWebhookURL != "" {
+		notificationService.SetDeliverer(service.NewWebhookDeliverer(s.config.DeliveryWebhookURL, s.config.DeliveryWebhookSecret))
+		if s.redis.IsAvailable() {
+			s.runJob(func(ctx context.Context) {
+				notificationService.RunDigestFlusher(ctx, time.Minute)
+			})
+		}
+	}
+	profileService.SetSandboxMode(s.config.SandboxMode)
+	profileService.SetWelcomeNotifications(notificationService, s.config.WelcomeNotification)
+	profileService.SetBadgeCountRepositories(notificationRepo, messageRepo)
+	profileService.SetWebPConversion(s.config.ImageWebPConversion)
+	profileService.SetResponseTimeWindow(s.config.ResponseTimeWindow)
+	profileService.SetAPITokens(apiTokenRepo, s.config.APITokenRateLimit)
+	profileService.SetAbuseThrottle(service.AbuseThrottleConfig{
+		Threshold:  s.config.AbuseThrottleThreshold,
+		Delay:      s.config.AbuseThrottleDelay,
+		ChurnLimit: s.config.AbuseChurnLimit,
+	})
 	listingService := service.NewListingService(listingRepo, profileService, s.redis)
 	wishlistService := service.NewWishlistService(wishlistRepo, profileService, notificationService)
+	wishlistService.SetMatchConcurrency(s.config.WishlistMatchWorkers)
+	wishlistService.SetMatchGrouping(s.redis, s.config.WishlistMatchGroupWindow)
+	if s.config.WishlistMatchGroupWindow > 0 && s.redis.IsAvailable() {
+		s.runJob(func(ctx context.Context) {
+			wishlistService.RunMatchFlusher(ctx, 5*time.Second)
+		})
+	}
+	wishlistService.SetGameRegistry(registry)
 	listingService.SetWishlistService(wishlistService)
+	listingService.SetRelistCooldown(transactionRepo, s.config.RelistCooldown)
+	watchService := service.NewWatchService(watchRepo, profileService, notificationService)
+	listingService.SetWatchService(watchService)
+	favoriteService := service.NewFavoriteService(favoriteRepo, listingRepo, listingService, s.redis)
 	statsService := service.NewStatsService(statsRepo, s.redis)
 	listingService.SetStatsService(statsService)
+	statsService.SetTransactionRepository(transactionRepo)
+	listingService.SetNotificationService(notificationService)
+	listingService.SetGameRegistry(registry)
+	listingService.SetStorage(s.listingStorage)
+	listingService.SetWebPConversion(s.config.ImageWebPConversion)
+	listingService.SetTextLimits(textLimits)
+	listingService.SetDuplicateListingMode(s.config.DuplicateListings)
+	listingService.SetDelegateRepository(delegateRepo)
+	listingService.SetListingLifetimes(s.config.ListingLifetime, s.config.PremiumListingLifetime)
+	listingService.SetExpiryWarningHours(s.config.ExpiryWarningHours)
+	listingService.SetDailyBumps(s.config.FreeDailyBumps, s.config.PremiumDailyBumps)
+	if s.config.ViewFlushInterval > 0 && s.redis.IsAvailable() {
+		listingService.SetViewBuffering(true)
+		s.runJob(func(ctx context.Context) {
+			listingService.RunViewFlusher(ctx, s.config.ViewFlushInterval)
+		})
+	}
+	if s.config.AbuseThrottleThreshold > 0 && s.redis.IsAvailable() {
+		s.runJob(func(ctx context.Context) {
+			listingService.RunRecentReleaser(ctx, time.Minute)
+		})
+	}
 	serviceService := service.NewServiceService(serviceRepo, profileService, s.redis)
+	serviceService.SetGameRegistry(registry)
+	serviceService.SetServiceLimits(s.config.MaxActiveServices, s.config.MaxActiveServicesPremium)
+	serviceService.SetTextLimits(textLimits)
 	serviceRunService := service.NewServiceRunService(serviceRunRepo, transactionRepo, ratingRepo, chatRepo, notificationService, profileService, serviceService, s.redis)
+	serviceRunService.SetDisputeRepository(disputeRepo)
 	offerService := service.NewOfferService(
 		s.db,
 		offerRepo,
@@ -173,9 +330,53 @@ func (s *Server) setupRoutes() {
 		s.config.SupabaseURL,
 	)
 	offerService.SetStatsService(statsService)
+	offerService.SetDeclineTemplateRepository(declineTemplateRepo)
+	offerService.SetOfferTemplateRepository(offerTemplateRepo)
+	offerService.SetPauseListingOnAccept(s.config.PauseListingOnAccept)
+	offerService.SetDelegateRepository(delegateRepo)
 	tradeService.SetStatsService(statsService)
+	runeValues, err := d2.ParseRuneValues(s.config.RuneValues)
+	if err != nil {
+		applogger.Log.Warn("invalid rune values, using defaults", "error", err.Error())
+		runeValues = d2.DefaultRuneValues()
+	}
+	valueEstimator := runeValues
+	offerService.SetValueEstimator(valueEstimator)
+	if s.config.MarketEvents {
+		marketEvents := service.NewMarketEventRecorder(s.db, marketEventRepo, valueEstimator)
+		tradeService.SetMarketEventRecorder(marketEvents)
+		serviceRunService.SetMarketEventRecorder(marketEvents)
+	}
+	featureFlagSpec := s.config.FeatureFlags
+	if featureFlagSpec == "" {
+		featureFlagSpec = service.DefaultFeatureFlags
+	}
+	rollouts, err := service.ParseFeatureFlags(featureFlagSpec)
+	if err != nil {
+		applogger.Log.Warn("invalid feature flags, using defaults", "error", err.Error())
+		rollouts, _ = service.ParseFeatureFlags(service.DefaultFeatureFlags)
+	}
+	featureFlags := service.NewFeatureFlagService(rollouts, profileService)
+	listingService.SetFeatureFlags(featureFlags)
+	gamesService := service.NewGamesService(registry, s.config.SupabaseURL)
+	if s.config.ChatArchive {
+		chatArchiver := service.NewChatArchiver(chatRepo, s.config.ChatArchiveGrace)
+		tradeService.SetChatArchiver(chatArchiver)
+		serviceRunService.SetChatArchiver(chatArchiver)
+	}
+	if s.config.ItemImageCheck {
+		imageChecker := service.NewItemImageChecker(s.redis, s.config.ItemImagePlaceholderURL)
+		listingService.SetImageChecker(imageChecker)
+		tradeService.SetImageChecker(imageChecker)
+		gamesService.SetImageChecker(imageChecker)
+		profileService.SetImageChecker(imageChecker)
+	}
 	chatService := service.NewChatService(chatRepo, messageRepo, tradeRepo, profileService, notificationService)
+	chatService.SetRedis(s.redis)
+	chatService.SetStorage(s.storage)
 	ratingService := service.NewRatingService(ratingRepo, transactionRepo, profileService, notificationService)
+	ratingService.SetTextLimits(textLimits)
+	ratingService.SetDisputeRepository(disputeRepo)
 	battleNetService := service.NewBattleNetService(
 		service.BattleNetConfig{
 			ClientID:     s.config.BattleNetClientID,
@@ -193,21 +394,34 @@ func (s *Server) setupRoutes() {
 		listingRepo,
 		s.redis,
 		service.StripeConfig{
-			SecretKey:       s.config.StripeSecretKey,
-			WebhookSecret:   s.config.StripeWebhookSecret,
-			PriceID:         s.config.StripePriceID,
-			SuccessURL:      s.config.StripeSuccessURL,
-			CancelURL:       s.config.StripeCancelURL,
-			AllowedPriceIDs: s.config.StripeAllowedPriceIDs,
+			SecretKey:          s.config.StripeSecretKey,
+			WebhookSecret:      s.config.StripeWebhookSecret,
+			PriceID:            s.config.StripePriceID,
+			SuccessURL:         s.config.StripeSuccessURL,
+			CancelURL:          s.config.StripeCancelURL,
+			AllowedPriceIDs:    s.config.StripeAllowedPriceIDs,
+			GiftExtendsPremium: s.config.PremiumGiftExtends,
 		},
 	)
+	subscriptionService.SetProfileService(profileService)
+	subscriptionService.SetFreeListingLifetime(s.config.ListingLifetime)
+	subscriptionService.SetSandboxMode(s.config.SandboxMode)
+	subscriptionService.SetNotificationService(notificationService)
 
 	bugReportService := service.NewBugReportService(bugReportRepo)
+	declineTemplateService := service.NewDeclineTemplateService(declineTemplateRepo)
+	offerTemplateService := service.NewOfferTemplateService(offerTemplateRepo)
+	delegateService := service.NewDelegateService(delegateRepo, profileService)
+	cacheService := service.NewCacheService(s.redis, profileRepo)
+	cacheService.SetHomeWarmers(statsService.WarmHomeStats, listingService.WarmRecentListings, serviceService.WarmRecentServices)
 
 	// Create handlers
 	profileHandler := v1.NewProfileHandler(profileService)
 	listingHandler := v1.NewListingHandler(listingService)
 	offerHandler := v1.NewOfferHandler(offerService)
+	declineTemplateHandler := v1.NewDeclineTemplateHandler(declineTemplateService)
+	offerTemplateHandler := v1.NewOfferTemplateHandler(offerTemplateService)
+	delegateHandler := v1.NewDelegateHandler(delegateService)
 	tradeHandler := v1.NewTradeHandlerNew(tradeService)
 	chatHandler := v1.NewChatHandler(chatService)
 	notificationHandler := v1.NewNotificationHandler(notificationService)
@@ -217,10 +431,15 @@ func (s *Server) setupRoutes() {
 	subscriptionHandler := v1.NewSubscriptionHandler(subscriptionService)
 	webhookHandler := v1.NewWebhookHandler(subscriptionService)
 	wishlistHandler := v1.NewWishlistHandler(wishlistService)
+	watchHandler := v1.NewWatchHandler(watchService)
+	favoriteHandler := v1.NewFavoriteHandler(favoriteService)
 	premiumHandler := v1.NewPremiumHandler(subscriptionService, listingService)
 	bugReportHandler := v1.NewBugReportHandler(bugReportService)
 	serviceHandler := v1.NewServiceHandler(serviceService)
 	serviceRunHandler := v1.NewServiceRunHandler(serviceRunService)
+	cacheHandler := v1.NewCacheHandler(cacheService)
+	gamesHandler := v1.NewGamesHandler(gamesService)
+	featureFlagHandler := v1.NewFeatureFlagHandler(featureFlags)
 
 	// Auth middleware config
 	authConfig := middleware.AuthConfig{
@@ -234,7 +453,7 @@ func (s *Server) setupRoutes() {
 	authOptional := middleware.OptionalAuthMiddleware(authConfig)
 
 	// Activity tracking middleware (updates last_active_at for online sellers count)
-	activityTracker := middleware.ActivityTracker(profileRepo, s.redis)
+	activityTracker := middleware.ActivityTracker(profileService)
 
 	// Health check
 	s.app.Get("/health", func(c *fiber.Ctx) error {
@@ -248,17 +467,26 @@ func (s *Server) setupRoutes() {
 	api := s.app.Group("/api")
 	apiV1 := api.Group("/v1")
 
+	// Integrations may send a personal access token (X-API-Key) on public read routes
+	listingsToken := middleware.APITokenMiddleware(profileService, models.APITokenScopeListingsRead)
+	statsToken := middleware.APITokenMiddleware(profileService, models.APITokenScopeStatsRead)
+
 	// Public routes (with Cache-Control headers)
-	apiV1.Post("/listings/search", authOptional, listingHandler.Search)
-	apiV1.Get("/listings", middleware.CacheControl(15), authOptional, listingHandler.List)
-	apiV1.Get("/listings/:id", middleware.CacheControl(300), authOptional, listingHandler.GetByID)
+	apiV1.Post("/listings/search", listingsToken, authOptional, listingHandler.Search)
+	apiV1.Get("/listings", middleware.CacheControl(15), listingsToken, authOptional, listingHandler.List)
+	apiV1.Get("/listings/:id", middleware.CacheControl(300), listingsToken, authOptional, listingHandler.GetByID)
+	apiV1.Get("/listings/:id/similar", middleware.CacheControl(300), listingsToken, listingHandler.GetSimilar)
+	apiV1.Post("/profiles/batch", profileHandler.GetBatch)
 	apiV1.Get("/profiles/:id", middleware.CacheControl(60), profileHandler.GetByID)
 	apiV1.Get("/profiles/:id/ratings", middleware.CacheControl(60), ratingHandler.GetByProfileID)
+	apiV1.Get("/profiles/:id/ratings/received", middleware.CacheControl(60), ratingHandler.GetReceived)
+	apiV1.Get("/profiles/:id/ratings/given", middleware.CacheControl(60), ratingHandler.GetGiven)
 	apiV1.Get("/profiles/:id/sales", middleware.CacheControl(60), profileHandler.GetSales)
 	apiV1.Get("/decline-reasons", middleware.CacheControl(3600), offerHandler.GetDeclineReasons)
-	apiV1.Get("/marketplace/stats", middleware.CacheControl(300), statsHandler.GetMarketplaceStats)
-	apiV1.Get("/marketplace/recent", middleware.CacheControl(15), statsHandler.GetRecentListings)
-	apiV1.Get("/marketplace/recent-services", middleware.CacheControl(15), statsHandler.GetRecentServices)
+	apiV1.Get("/marketplace/stats", middleware.CacheControl(300), statsToken, statsHandler.GetMarketplaceStats)
+	apiV1.Get("/marketplace/recent", middleware.CacheControl(15), statsToken, statsHandler.GetRecentListings)
+	apiV1.Get("/marketplace/recent-services", middleware.CacheControl(15), statsToken, statsHandler.GetRecentServices)
+	apiV1.Get("/marketplace/price-summary", middleware.CacheControl(300), statsToken, statsHandler.GetItemPriceSummary)
 
 	// Stripe webhook (no auth required)
 	apiV1.Post("/webhooks/stripe", webhookHandler.StripeWebhook)
@@ -293,6 +521,20 @@ func (s *Server) setupRoutes() {
 		}
 		return c.JSON(serviceTypes)
 	})
+	apiV1.Get("/games/:game/regions", middleware.CacheControl(3600), func(c *fiber.Ctx) error {
+		gameCode := c.Params("game")
+		regions, err := registry.GetRegions(gameCode)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not_found",
+				"message": "Game not found",
+				"code":    404,
+			})
+		}
+		return c.JSON(regions)
+	})
+	apiV1.Get("/features", authOptional, featureFlagHandler.List)
+	apiV1.Get("/games/:game/items/resolve", middleware.CacheControl(3600), gamesHandler.ResolveItem)
 
 	// Authenticated routes (with activity tracking for online sellers count)
 	authenticated := apiV1.Group("", authRequired, activityTracker)
@@ -300,7 +542,13 @@ func (s *Server) setupRoutes() {
 	// Profile routes
 	authenticated.Get("/me", profileHandler.GetMe)
 	authenticated.Patch("/me", profileHandler.UpdateMe)
+	authenticated.Get("/me/badges", profileHandler.GetBadges)
+	authenticated.Get("/me/sales/export", profileHandler.ExportSales)
 	authenticated.Post("/me/picture", profileHandler.UploadPicture)
+	authenticated.Post("/me/verification/resend", profileHandler.ResendVerification)
+	authenticated.Get("/me/api-tokens", profileHandler.ListAPITokens)
+	authenticated.Post("/me/api-tokens", profileHandler.CreateAPIToken)
+	authenticated.Delete("/me/api-tokens/:id", profileHandler.RevokeAPIToken)
 
 	// Battle.net OAuth routes
 	authenticated.Post("/me/battlenet/link", battleNetHandler.Link)
@@ -309,15 +557,22 @@ func (s *Server) setupRoutes() {
 
 	// My listings
 	authenticated.Get("/my/listings", listingHandler.ListMy)
+	authenticated.Get("/my/recently-viewed", listingHandler.ListRecentlyViewed)
 
 	// My services
 	authenticated.Get("/my/services", serviceHandler.ListMy)
 
 	// Listing management
 	authenticated.Post("/listings", listingHandler.Create)
+	authenticated.Post("/listings/wishlist-matches", listingHandler.PreviewWishlistMatches)
 	authenticated.Patch("/listings/:id", listingHandler.Update)
 	authenticated.Delete("/listings/:id", listingHandler.Delete)
 	authenticated.Post("/listings/:id/refresh", listingHandler.Refresh)
+	authenticated.Post("/listings/:id/renew", listingHandler.Renew)
+	authenticated.Post("/listings/:id/pause", listingHandler.Pause)
+	authenticated.Post("/listings/:id/resume", listingHandler.Resume)
+	authenticated.Post("/listings/:id/reserve", listingHandler.Reserve)
+	authenticated.Post("/listings/:id/image", listingHandler.UploadImage)
 
 	// Service management
 	authenticated.Post("/services", serviceHandler.Create)
@@ -331,31 +586,64 @@ func (s *Server) setupRoutes() {
 	authenticated.Get("/service-runs/:id", serviceRunHandler.GetByID)
 	authenticated.Post("/service-runs/:id/complete", serviceRunHandler.Complete)
 	authenticated.Post("/service-runs/:id/cancel", serviceRunHandler.Cancel)
+	authenticated.Post("/service-runs/:id/dispute", serviceRunHandler.OpenDispute)
 
 	// Offer routes
 	authenticated.Get("/offers", offerHandler.List)
 	authenticated.Post("/offers", offerHandler.Create)
+	authenticated.Post("/offers/quick", offerHandler.QuickCreate)
+	authenticated.Post("/offers/bulk-reject", offerHandler.BulkReject)
+	authenticated.Get("/offers/by-listing", offerHandler.ListGrouped)
 	authenticated.Get("/offers/:id", offerHandler.GetByID)
 	authenticated.Post("/offers/:id/accept", offerHandler.Accept)
 	authenticated.Post("/offers/:id/reject", offerHandler.Reject)
+	authenticated.Post("/offers/:id/counter", offerHandler.Counter)
 	authenticated.Post("/offers/:id/cancel", offerHandler.Cancel)
+	authenticated.Get("/offers/:id/chat", chatHandler.GetByOfferID)
+
+	// Decline template routes (seller's saved reject notes)
+	authenticated.Get("/decline-templates", declineTemplateHandler.List)
+	authenticated.Post("/decline-templates", declineTemplateHandler.Create)
+	authenticated.Patch("/decline-templates/:id", declineTemplateHandler.Update)
+	authenticated.Delete("/decline-templates/:id", declineTemplateHandler.Delete)
+
+	// Offer template routes (buyer's saved offered-item bundles)
+	authenticated.Get("/offer-templates", offerTemplateHandler.List)
+	authenticated.Post("/offer-templates", offerTemplateHandler.Create)
+	authenticated.Patch("/offer-templates/:id", offerTemplateHandler.Update)
+	authenticated.Delete("/offer-templates/:id", offerTemplateHandler.Delete)
+
+	// Delegate routes (owner's grants to shop managers)
+	authenticated.Get("/delegates", delegateHandler.List)
+	authenticated.Put("/delegates/:userId", delegateHandler.Grant)
+	authenticated.Delete("/delegates/:userId", delegateHandler.Revoke)
 
 	// Trade routes
 	authenticated.Get("/trades", tradeHandler.List)
 	authenticated.Get("/trades/:id", tradeHandler.GetByID)
+	authenticated.Post("/trades/:id/complete/request", tradeHandler.RequestComplete)
 	authenticated.Post("/trades/:id/complete", tradeHandler.Complete)
 	authenticated.Post("/trades/:id/cancel", tradeHandler.Cancel)
+	authenticated.Post("/trades/:id/items/:index/received", tradeHandler.ToggleItemReceived)
 
 	// Chat routes
+	authenticated.Get("/chats", chatHandler.List)
 	authenticated.Get("/chats/:id", chatHandler.GetByID)
 	authenticated.Get("/chats/:id/messages", chatHandler.GetMessages)
 	authenticated.Post("/chats/:id/messages", chatHandler.SendMessage)
+	authenticated.Post("/chats/:id/attachments", chatHandler.SendAttachment)
 	authenticated.Post("/chats/:id/read", chatHandler.MarkRead)
+	authenticated.Post("/chats/:id/typing", chatHandler.Typing)
+	authenticated.Get("/chats/:id/stream", chatHandler.Stream)
 
 	// Notification routes
 	authenticated.Get("/notifications", notificationHandler.List)
 	authenticated.Get("/notifications/count", notificationHandler.Count)
+	authenticated.Get("/notifications/stream", notificationHandler.Stream)
+	authenticated.Get("/notifications/preferences", notificationHandler.GetPreferences)
+	authenticated.Put("/notifications/preferences", notificationHandler.UpdatePreferences)
 	authenticated.Post("/notifications/read", notificationHandler.MarkRead)
+	authenticated.Post("/notifications/read-by-reference", notificationHandler.MarkReadByReference)
 
 	// Rating routes
 	authenticated.Post("/ratings", ratingHandler.Create)
@@ -364,14 +652,27 @@ func (s *Server) setupRoutes() {
 	authenticated.Get("/subscriptions/me", subscriptionHandler.GetMe)
 	authenticated.Post("/subscriptions/checkout", subscriptionHandler.Checkout)
 	authenticated.Post("/subscriptions/cancel", subscriptionHandler.Cancel)
+	authenticated.Post("/subscriptions/gift", subscriptionHandler.Gift)
 	authenticated.Get("/subscriptions/billing-history", subscriptionHandler.BillingHistory)
 
 	// Wishlist routes
 	authenticated.Get("/wishlist", wishlistHandler.List)
 	authenticated.Post("/wishlist", wishlistHandler.Create)
+	authenticated.Post("/wishlist/import", wishlistHandler.Import)
+	authenticated.Get("/wishlist/export", wishlistHandler.Export)
 	authenticated.Patch("/wishlist/:id", wishlistHandler.Update)
 	authenticated.Delete("/wishlist/:id", wishlistHandler.Delete)
 
+	// Item watch routes
+	authenticated.Get("/watches", watchHandler.List)
+	authenticated.Post("/watches", watchHandler.Create)
+	authenticated.Delete("/watches/:id", watchHandler.Delete)
+
+	// Favorite listing routes
+	authenticated.Get("/favorites", favoriteHandler.List)
+	authenticated.Post("/favorites/:listingId", favoriteHandler.Add)
+	authenticated.Delete("/favorites/:listingId", favoriteHandler.Remove)
+
 	// Bug reports - any authenticated user can submit
 	authenticated.Post("/bug-reports", bugReportHandler.Create)
 
@@ -379,6 +680,18 @@ func (s *Server) setupRoutes() {
 	adminRequired := middleware.AdminMiddleware(profileService)
 	authenticated.Get("/bug-reports", adminRequired, bugReportHandler.List)
 	authenticated.Patch("/bug-reports/:id", adminRequired, bugReportHandler.UpdateStatus)
+	authenticated.Post("/admin/trades/reconcile", adminRequired, tradeHandler.ReconcileTransactions)
+	authenticated.Post("/admin/notifications/broadcast", adminRequired, notificationHandler.Broadcast)
+	authenticated.Get("/admin/notifications/broadcast/:id", adminRequired, notificationHandler.GetBroadcast)
+	authenticated.Post("/admin/listings/expire-stale", adminRequired, listingHandler.ExpireStale)
+	authenticated.Post("/admin/listings/notify-expiring", adminRequired, listingHandler.NotifyExpiring)
+	authenticated.Delete("/admin/cache/listings/:id", adminRequired, cacheHandler.PurgeListing)
+	authenticated.Delete("/admin/cache/profiles/:id", adminRequired, cacheHandler.PurgeProfile)
+	authenticated.Post("/admin/cache/purge", adminRequired, cacheHandler.PurgeAll)
+	authenticated.Post("/admin/profiles/:id/abuse-score", adminRequired, profileHandler.AdjustAbuseScore)
+	authenticated.Post("/admin/profiles/:id/bump-credits", adminRequired, listingHandler.GrantBumpCredits)
+	authenticated.Get("/admin/disputes", adminRequired, serviceRunHandler.ListDisputes)
+	authenticated.Post("/admin/disputes/:id/resolve", adminRequired, serviceRunHandler.ResolveDispute)
 
 	// Premium feature routes
 	authenticated.Patch("/me/flair", premiumHandler.UpdateFlair)
@@ -405,7 +718,20 @@ func (s *Server) Start() error {
 	return s.app.Listen(addr)
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server, then stops background jobs and waits
+// for them to finish their last run
 func (s *Server) Shutdown() error {
-	return s.app.Shutdown()
+	err := s.app.Shutdown()
+	s.stopJobs()
+	s.jobsDone.Wait()
+	return err
+}
+
+// runJob starts a background loop that runs until the server shuts down
+func (s *Server) runJob(job func(ctx context.Context)) {
+	s.jobsDone.Add(1)
+	go func() {
+		defer s.jobsDone.Done()
+		job(s.jobs)
+	}()
 }
@@ -46,6 +46,14 @@ func (i *Invalidator) InvalidateNotificationCount(ctx context.Context, userID st
 	return i.redis.Del(ctx, NotificationCountKey(userID))
 }
 
+// InvalidateMessageCount removes unread message count from cache
+func (i *Invalidator) InvalidateMessageCount(ctx context.Context, userID string) error {
+	if i == nil || i.redis == nil {
+		return nil
+	}
+	return i.redis.Del(ctx, MessageCountKey(userID))
+}
+
 // InvalidateDeclineReasons removes decline reasons from cache
 func (i *Invalidator) InvalidateDeclineReasons(ctx context.Context) error {
 	if i == nil || i.redis == nil {
@@ -87,6 +95,15 @@ func (i *Invalidator) InvalidateService(ctx context.Context, id string) error {
 	return i.redis.Del(ctx, ServiceDTOKey(id))
 }
 
+// InvalidateOffer removes a specific offer and its DTO from cache
+func (i *Invalidator) InvalidateOffer(ctx context.Context, id string) error {
+	if i == nil || i.redis == nil {
+		return nil
+	}
+	_ = i.redis.Del(ctx, OfferKey(id))
+	return i.redis.Del(ctx, OfferDTOKey(id))
+}
+
 // InvalidateServiceProviders removes service providers cache for a game
 func (i *Invalidator) InvalidateServiceProviders(ctx context.Context, game string) error {
 	if i == nil || i.redis == nil {
@@ -95,6 +112,14 @@ func (i *Invalidator) InvalidateServiceProviders(ctx context.Context, game strin
 	return i.redis.Del(ctx, ServiceProvidersKey(game))
 }
 
+// InvalidateFavorites removes every cached page of a user's favorite listings
+func (i *Invalidator) InvalidateFavorites(ctx context.Context, userID string) error {
+	if i == nil || i.redis == nil {
+		return nil
+	}
+	return i.redis.Del(ctx, FavoritesKey(userID))
+}
+
 // InvalidateFilterResults removes all cached filter result entries
 func (i *Invalidator) InvalidateFilterResults(ctx context.Context) error {
 	if i == nil || i.redis == nil {
@@ -1,6 +1,9 @@
 package cache
 
-import "fmt"
+import (
+	"crypto/sha256"
+	"fmt"
+)
 
 const (
 	// Key prefixes
@@ -9,16 +12,45 @@ const (
 	prefixListing           = "listing"
 	prefixListingDTO        = "listing:dto"
 	prefixNotificationCount = "notification:count"
+	prefixNotificationDigest = "notification:digest"
+	prefixNotificationStream = "notification:stream"
+	prefixNotificationDedup  = "notification:dedup"
+	prefixNotificationPrefs  = "notification:prefs"
+	prefixNotificationBatch  = "notification:batch"
+	prefixBroadcastJob       = "broadcast:job"
+	prefixWishlistMatchBuffer = "wishlist:matches"
+	prefixWishlistNotified    = "wishlist:notified"
+	prefixChatPresence        = "chat:presence"
+	prefixChatTyping          = "chat:typing"
+	prefixMessageCount       = "message:count"
+	prefixItemImage          = "item:image"
 	prefixDeclineReasons    = "decline:reasons"
 	prefixRateLimit         = "ratelimit"
 	prefixMarketplaceStats   = "marketplace:stats"
 	prefixHomeStats          = "home:stats"
 	prefixHomeRecent         = "home:recent"
 	prefixHomeRecentServices = "home:recent:services"
+	prefixRecentlyViewed     = "recent:viewed"
+	prefixPriceSummary       = "price:summary"
 	prefixService            = "service"
 	prefixServiceDTO         = "service:dto"
 	prefixServiceProviders   = "service:providers"
+	prefixOffer              = "offer"
+	prefixOfferDTO           = "offer:dto"
+	prefixTradeCompletion    = "trade:complete"
 	prefixFilterResults      = "filter:results"
+	prefixIdempotency        = "idempotency"
+	prefixActivity           = "activity"
+	prefixBumpsUsed          = "bump:used"
+	prefixBumpCredits        = "bump:credits"
+	prefixViewsPending       = "views:pending"
+	keyViewsDirty            = "views:dirty"
+	prefixAPIToken           = "apitoken"
+	prefixFavorites          = "favorites"
+	prefixSimilarListings    = "similar"
+	keyRecentDelayed         = "delayed:home:recent"
+	keyWishlistMatchDue      = "wishlist:matches:due"
+	keyNotificationDigestDue = "notification:digest:due"
 )
 
 // Profile cache keys
@@ -52,6 +84,88 @@ func NotificationCountPattern() string {
 	return fmt.Sprintf("%s:*", prefixNotificationCount)
 }
 
+// MessageCountKey returns the key for a user's unread chat message count
+func MessageCountKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixMessageCount, userID)
+}
+
+// NotificationDigestKey returns the key for notifications held back during quiet hours
+func NotificationDigestKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixNotificationDigest, userID)
+}
+
+// NotificationDigestDueKey returns the sorted set of users with a held digest, scored by
+// when their quiet hours end
+func NotificationDigestDueKey() string {
+	return keyNotificationDigestDue
+}
+
+// NotificationStreamChannel returns the pub/sub channel for a user's live notification events
+func NotificationStreamChannel(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixNotificationStream, userID)
+}
+
+// NotificationPrefsKey returns the key caching a user's notification preferences
+func NotificationPrefsKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixNotificationPrefs, userID)
+}
+
+// NotificationBatchKey returns the key holding the notification a batch of similar
+// notifications is coalesced into
+func NotificationBatchKey(userID, notificationType, referenceType string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", prefixNotificationBatch, userID, notificationType, referenceType)
+}
+
+// NotificationBatchCountKey returns the key counting the notifications in a batch
+func NotificationBatchCountKey(userID, notificationType, referenceType string) string {
+	return NotificationBatchKey(userID, notificationType, referenceType) + ":count"
+}
+
+// NotificationDedupKey returns the key marking a notification event as already sent
+func NotificationDedupKey(dedupKey string) string {
+	return fmt.Sprintf("%s:%s", prefixNotificationDedup, dedupKey)
+}
+
+// BroadcastJobKey returns the key holding an announcement broadcast's progress
+func BroadcastJobKey(id string) string {
+	return fmt.Sprintf("%s:%s", prefixBroadcastJob, id)
+}
+
+// WishlistMatchBufferKey returns the key for wishlist matches waiting to be grouped into one notification
+func WishlistMatchBufferKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixWishlistMatchBuffer, userID)
+}
+
+// WishlistMatchDueKey returns the sorted set of users with buffered matches, scored by
+// when their buffer is due to be flushed
+func WishlistMatchDueKey() string {
+	return keyWishlistMatchDue
+}
+
+// WishlistNotifiedKey returns the key marking that a wishlist item's owner was already told about a listing
+func WishlistNotifiedKey(wishlistItemID, listingID string) string {
+	return fmt.Sprintf("%s:%s:%s", prefixWishlistNotified, wishlistItemID, listingID)
+}
+
+// ChatEventsChannel returns the pub/sub channel for a chat's ephemeral events
+// (typing, seen, read). Gateways can follow every chat with ChatEventsPattern.
+func ChatEventsChannel(chatID string) string {
+	return fmt.Sprintf("chat:%s:events", chatID)
+}
+
+// ChatEventsPattern matches the events channel of every chat, for PSUBSCRIBE
+const ChatEventsPattern = "chat:*:events"
+
+// ChatPresenceKey returns the key holding when a participant last had the chat open
+func ChatPresenceKey(chatID, userID string) string {
+	return fmt.Sprintf("%s:%s:%s", prefixChatPresence, chatID, userID)
+}
+
+// ChatTypingKey returns the key marking a participant as typing in a chat
+func ChatTypingKey(chatID, userID string) string {
+	return fmt.Sprintf("%s:%s:%s", prefixChatTyping, chatID, userID)
+}
+
 // Decline reasons cache key (single key for all reasons)
 func DeclineReasonsKey() string {
 	return prefixDeclineReasons
@@ -77,6 +191,22 @@ func ListingDTOKey(id string) string {
 	return fmt.Sprintf("%s:%s", prefixListingDTO, id)
 }
 
+// HomePattern returns the pattern for all home page cache keys
+func HomePattern() string {
+	return "home:*"
+}
+
+// PriceSummaryPattern returns the pattern for all price summary keys
+func PriceSummaryPattern() string {
+	return fmt.Sprintf("%s:*", prefixPriceSummary)
+}
+
+// ItemImageKey returns the key holding whether an item image URL exists in storage
+func ItemImageKey(imageURL string) string {
+	sum := sha256.Sum256([]byte(imageURL))
+	return fmt.Sprintf("%s:%x", prefixItemImage, sum[:16])
+}
+
 // HomeStatsKey returns the home stats cache key
 func HomeStatsKey() string {
 	return prefixHomeStats
@@ -87,11 +217,27 @@ func HomeRecentKey() string {
 	return prefixHomeRecent
 }
 
+// RecentDelayedKey returns the sorted set of listing IDs held out of home:recent, scored by
+// the unix time they may be pushed. It lives outside home:* so home purges keep it.
+func RecentDelayedKey() string {
+	return keyRecentDelayed
+}
+
 // HomeRecentServicesKey returns the home recent services cache key
 func HomeRecentServicesKey() string {
 	return prefixHomeRecentServices
 }
 
+// RecentlyViewedKey returns the key of a user's recently viewed listings list
+func RecentlyViewedKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixRecentlyViewed, userID)
+}
+
+// PriceSummaryKey returns the cache key for an item's price summary
+func PriceSummaryKey(game string, days int, itemName string) string {
+	return fmt.Sprintf("%s:%s:%d:%s", prefixPriceSummary, game, days, itemName)
+}
+
 // ServiceKey returns the service cache key
 func ServiceKey(id string) string {
 	return fmt.Sprintf("%s:%s", prefixService, id)
@@ -107,6 +253,31 @@ func ServiceProvidersKey(game string) string {
 	return fmt.Sprintf("%s:%s", prefixServiceProviders, game)
 }
 
+// ServicePattern returns the pattern for all service keys, including DTOs and providers
+func ServicePattern() string {
+	return fmt.Sprintf("%s:*", prefixService)
+}
+
+// OfferKey returns the offer cache key
+func OfferKey(id string) string {
+	return fmt.Sprintf("%s:%s", prefixOffer, id)
+}
+
+// OfferDTOKey returns the offer DTO cache key
+func OfferDTOKey(id string) string {
+	return fmt.Sprintf("%s:%s", prefixOfferDTO, id)
+}
+
+// OfferPattern returns the pattern for all offer keys, including DTOs
+func OfferPattern() string {
+	return fmt.Sprintf("%s:*", prefixOffer)
+}
+
+// TradeCompletionKey returns the key holding a user's pending trade completion token
+func TradeCompletionKey(tradeID, userID string) string {
+	return fmt.Sprintf("%s:%s:%s", prefixTradeCompletion, tradeID, userID)
+}
+
 // FilterResultsKey returns the cache key for a filter result hash
 func FilterResultsKey(hash string) string {
 	return fmt.Sprintf("%s:%s", prefixFilterResults, hash)
@@ -116,3 +287,58 @@ func FilterResultsKey(hash string) string {
 func FilterResultsPattern() string {
 	return fmt.Sprintf("%s:*", prefixFilterResults)
 }
+
+// IdempotencyKey returns the key mapping a client-supplied request ID to the created resource
+func IdempotencyKey(scope, userID, requestID string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", prefixIdempotency, scope, userID, requestID)
+}
+
+// ActivityKey returns the key throttling last_active_at writes for a user
+func ActivityKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixActivity, userID)
+}
+
+// BumpsUsedKey returns the key counting a user's listing bumps on a UTC day (YYYYMMDD)
+func BumpsUsedKey(userID, day string) string {
+	return fmt.Sprintf("%s:%s:%s", prefixBumpsUsed, userID, day)
+}
+
+// BumpCreditsKey returns the key holding a user's extra bumps beyond the daily quota
+func BumpCreditsKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixBumpCredits, userID)
+}
+
+// ViewsPendingKey returns the counter buffering a listing's views until the next flush
+func ViewsPendingKey(listingID string) string {
+	return fmt.Sprintf("%s:%s", prefixViewsPending, listingID)
+}
+
+// ViewsDirtyKey returns the set of listing IDs with buffered views waiting to be flushed
+func ViewsDirtyKey() string {
+	return keyViewsDirty
+}
+
+// APITokenKey returns the cache key for the API token with the given hash
+func APITokenKey(hash string) string {
+	return fmt.Sprintf("%s:%s", prefixAPIToken, hash)
+}
+
+// APITokenUsedKey returns the key throttling last_used_at writes for an API token
+func APITokenUsedKey(tokenID string) string {
+	return fmt.Sprintf("%s:used:%s", prefixAPIToken, tokenID)
+}
+
+// APITokenRateKey returns the request counter for an API token in the given minute
+func APITokenRateKey(tokenID string, minute int64) string {
+	return fmt.Sprintf("%s:rate:%s:%d", prefixAPIToken, tokenID, minute)
+}
+
+// FavoritesKey returns the hash caching a user's favorite listing pages, one field per page
+func FavoritesKey(userID string) string {
+	return fmt.Sprintf("%s:%s", prefixFavorites, userID)
+}
+
+// SimilarListingsKey returns the cache key of the similar listings shown for a listing
+func SimilarListingsKey(listingID string, limit int) string {
+	return fmt.Sprintf("%s:%s:%d", prefixSimilarListings, listingID, limit)
+}
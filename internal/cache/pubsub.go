@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnavailable indicates Redis isn't configured, so pub/sub can't be used
+var ErrUnavailable = errors.New("redis unavailable")
+
+// Subscription is a live pub/sub subscription delivering message payloads
+type Subscription struct {
+	pubsub    *redis.PubSub
+	messages  chan string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Publish sends a message to a pub/sub channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// HasSubscribers reports whether any client, on any instance, is subscribed to a channel
+func (r *RedisClient) HasSubscribers(ctx context.Context, channel string) bool {
+	if r == nil || r.client == nil {
+		return false
+	}
+	counts, err := r.client.PubSubNumSub(ctx, channel).Result()
+	if err != nil {
+		return false
+	}
+	return counts[channel] > 0
+}
+
+// Subscribe opens a subscription to a pub/sub channel.
+// Callers must Close the subscription when done.
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) (*Subscription, error) {
+	if r == nil || r.client == nil {
+		return nil, ErrUnavailable
+	}
+
+	pubsub := r.client.Subscribe(ctx, channel)
+	// Wait for the subscription to be confirmed so no message published afterwards is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	sub := &Subscription{
+		pubsub:   pubsub,
+		messages: make(chan string),
+		done:     make(chan struct{}),
+	}
+	go sub.forward()
+	return sub, nil
+}
+
+// Messages returns the channel of received message payloads.
+// It is closed when the subscription is closed.
+func (s *Subscription) Messages() <-chan string {
+	return s.messages
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.pubsub.Close()
+	})
+	return err
+}
+
+// forward relays payloads until the subscription is closed
+func (s *Subscription) forward() {
+	defer close(s.messages)
+	ch := s.pubsub.Channel()
+	for {
+		select {
+		case <-s.done:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			select {
+			case s.messages <- msg.Payload:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -66,6 +67,25 @@ func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, key).Result()
 }
 
+// MGet retrieves several values in one round trip. The result lines up with keys,
+// with an empty string for each key that isn't cached.
+func (r *RedisClient) MGet(ctx context.Context, keys ...string) ([]string, error) {
+	if r == nil || r.client == nil {
+		return nil, redis.Nil
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			result[i] = s
+		}
+	}
+	return result, nil
+}
+
 // Set stores a value in cache with TTL
 func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	if r == nil || r.client == nil {
@@ -74,6 +94,14 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, tt
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetNX sets a key only if it does not already exist, reporting whether it was set
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if r == nil || r.client == nil {
+		return true, nil
+	}
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // Del deletes one or more keys
 func (r *RedisClient) Del(ctx context.Context, keys ...string) error {
 	if r == nil || r.client == nil {
@@ -99,6 +127,63 @@ func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 	return r.client.Incr(ctx, key).Result()
 }
 
+// IncrBy adds n (which may be negative) to a counter
+func (r *RedisClient) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	return r.client.IncrBy(ctx, key, n).Result()
+}
+
+// GetDel returns a key's value and deletes it atomically ("" when missing)
+func (r *RedisClient) GetDel(ctx context.Context, key string) (string, error) {
+	if r == nil || r.client == nil {
+		return "", nil
+	}
+	val, err := r.client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// HGet returns a hash field's value (redis.Nil when the key or field is missing)
+func (r *RedisClient) HGet(ctx context.Context, key, field string) (string, error) {
+	if r == nil || r.client == nil {
+		return "", redis.Nil
+	}
+	return r.client.HGet(ctx, key, field).Result()
+}
+
+// HSet sets a hash field and (re)sets the TTL of the whole hash
+func (r *RedisClient) HSet(ctx context.Context, key, field string, value interface{}, ttl time.Duration) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, field, value)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// SAdd adds members to a set
+func (r *RedisClient) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.SAdd(ctx, key, members...).Err()
+}
+
+// SPopN removes and returns up to count random members of a set
+func (r *RedisClient) SPopN(ctx context.Context, key string, count int64) ([]string, error) {
+	if r == nil || r.client == nil {
+		return nil, nil
+	}
+	return r.client.SPopN(ctx, key, count).Result()
+}
+
 // Expire sets expiration on a key
 func (r *RedisClient) Expire(ctx context.Context, key string, ttl time.Duration) error {
 	if r == nil || r.client == nil {
@@ -131,6 +216,24 @@ func (r *RedisClient) LRange(ctx context.Context, key string, start, stop int64)
 	return r.client.LRange(ctx, key, start, stop).Result()
 }
 
+// LDrain returns every element of a list and deletes it in one transaction,
+// so values pushed concurrently are either returned or left for the next drain
+func (r *RedisClient) LDrain(ctx context.Context, key string) ([]string, error) {
+	if r == nil || r.client == nil {
+		return nil, nil
+	}
+	var values *redis.StringSliceCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.LRange(ctx, key, 0, -1)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values.Val(), nil
+}
+
 // LRem removes elements from a list matching the given value
 func (r *RedisClient) LRem(ctx context.Context, key string, count int64, value interface{}) error {
 	if r == nil || r.client == nil {
@@ -139,6 +242,47 @@ func (r *RedisClient) LRem(ctx context.Context, key string, count int64, value i
 	return r.client.LRem(ctx, key, count, value).Err()
 }
 
+// ZAdd adds a member to a sorted set with the given score, updating the score if
+// the member is already there
+func (r *RedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	if r == nil || r.client == nil {
+		return nil
+	}
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZAddNX adds member to a sorted set only if it isn't already in it, keeping an
+// existing member's score. Returns whether the member was added.
+func (r *RedisClient) ZAddNX(ctx context.Context, key string, score float64, member string) (bool, error) {
+	if r == nil || r.client == nil {
+		return false, nil
+	}
+	n, err := r.client.ZAddNX(ctx, key, redis.Z{Score: score, Member: member}).Result()
+	return n > 0, err
+}
+
+// ZRangeByScoreMax returns up to count members of a sorted set scored at or below max,
+// lowest score first
+func (r *RedisClient) ZRangeByScoreMax(ctx context.Context, key string, max float64, count int64) ([]string, error) {
+	if r == nil || r.client == nil {
+		return nil, nil
+	}
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatFloat(max, 'f', -1, 64),
+		Count: count,
+	}).Result()
+}
+
+// ZRem removes members from a sorted set and returns how many were removed, so
+// concurrent callers can use it to claim a member
+func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	return r.client.ZRem(ctx, key, members...).Result()
+}
+
 // DeleteByPattern deletes all keys matching a pattern
 func (r *RedisClient) DeleteByPattern(ctx context.Context, pattern string) error {
 	if r == nil || r.client == nil {
@@ -157,3 +301,35 @@ func (r *RedisClient) DeleteByPattern(ctx context.Context, pattern string) error
 	}
 	return nil
 }
+
+// purgeBatchSize is how many keys PurgeByPattern asks for per SCAN and deletes per DEL
+const purgeBatchSize = 500
+
+// PurgeByPattern deletes all keys matching a pattern in SCAN-sized batches so a
+// large prefix never blocks Redis, returning how many keys were removed
+func (r *RedisClient) PurgeByPattern(ctx context.Context, pattern string) (int64, error) {
+	if r == nil || r.client == nil {
+		return 0, nil
+	}
+	var (
+		cursor  uint64
+		deleted int64
+	)
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, purgeBatchSize).Result()
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) > 0 {
+			n, err := r.client.Del(ctx, keys...).Result()
+			if err != nil {
+				return deleted, err
+			}
+			deleted += n
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
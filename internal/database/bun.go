@@ -83,3 +83,28 @@ func (b *BunDB) Close() error {
 func (b *BunDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (bun.Tx, error) {
 	return b.db.BeginTx(ctx, opts)
 }
+
+type txKey struct{}
+
+// RunInTx runs fn inside a transaction. Repository calls made with the ctx handed to fn
+// join it through Conn; a nested call reuses the outer transaction. A nil BunDB runs fn
+// directly so services built without a database (tests) keep working.
+func (b *BunDB) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return fn(ctx)
+	}
+	if _, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return fn(ctx)
+	}
+	return b.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction started by RunInTx for ctx, or the database itself
+func (b *BunDB) Conn(ctx context.Context) bun.IDB {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return b.db
+}
@@ -0,0 +1,99 @@
+package games
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ItemMatch is the result of resolving a user-entered item name. Found is false
+// when nothing was close enough; Exact is false when the match was fuzzy and the
+// caller should confirm it rather than trust it.
+type ItemMatch struct {
+	Item     CatalogItem
+	Found    bool
+	Exact    bool
+	Distance int // edits between the input and the matched name or alias
+}
+
+// ResolveItem maps a raw item name to the game's canonical catalog item.
+// Case, spacing and punctuation are ignored. Otherwise the closest name or alias
+// is taken if it is within a few edits and no other item is as close; anything
+// else is reported as not found instead of guessed.
+func (r *Registry) ResolveItem(code string, rawName string) (ItemMatch, error) {
+	handler, err := r.Get(code)
+	if err != nil {
+		return ItemMatch{}, err
+	}
+
+	key := catalogKey(rawName)
+	if key == "" {
+		return ItemMatch{}, nil
+	}
+
+	best := ItemMatch{Distance: -1}
+	ambiguous := false
+	for _, item := range handler.GetCatalogItems() {
+		distance := -1
+		for _, candidate := range append([]string{item.Name}, item.Aliases...) {
+			d := editDistance(key, catalogKey(candidate))
+			if distance < 0 || d < distance {
+				distance = d
+			}
+		}
+		if distance == 0 {
+			return ItemMatch{Item: item, Found: true, Exact: true}, nil
+		}
+		switch {
+		case best.Distance < 0 || distance < best.Distance:
+			best = ItemMatch{Item: item, Distance: distance}
+			ambiguous = false
+		case distance == best.Distance:
+			ambiguous = true
+		}
+	}
+
+	if best.Distance < 0 || ambiguous || best.Distance > maxFuzzyEdits(key) {
+		return ItemMatch{}, nil
+	}
+	best.Found = true
+	return best, nil
+}
+
+// maxFuzzyEdits allows one edit per four characters, so names under four
+// characters (most runes) only ever match exactly
+func maxFuzzyEdits(key string) int {
+	return len([]rune(key)) / 4
+}
+
+// catalogKey lowercases name and drops everything but letters and digits
+func catalogKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
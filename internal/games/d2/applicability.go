@@ -0,0 +1,63 @@
+package d2
+
+// statlessCategories hold items whose stats are fixed, so affix filters never apply
+var statlessCategories = map[string]bool{
+	"rune": true,
+	"gem":  true,
+}
+
+// categoryExcludedStats lists canonical stat codes that cannot roll on items of a category.
+// Categories not listed here accept any stat.
+var categoryExcludedStats = map[string]map[string]bool{
+	"ring":   setOf("ias", "ed", "crushing_blow", "deadly_strike", "open_wounds"),
+	"amulet": setOf("ias", "ed", "crushing_blow", "deadly_strike", "open_wounds"),
+	"belt":   setOf("ias", "fcr", "frw", "ed", "crushing_blow", "deadly_strike", "open_wounds"),
+	"boots":  setOf("ias", "fcr", "ed", "life_steal", "mana_steal", "crushing_blow", "deadly_strike", "open_wounds"),
+	"charm":  setOf("ias", "fcr", "ed", "life_steal", "mana_steal", "crushing_blow", "deadly_strike", "open_wounds"),
+	"jewel":  setOf("fcr", "frw", "mf", "gf", "life_steal", "mana_steal", "crushing_blow", "deadly_strike", "open_wounds"),
+}
+
+// skillTabCategories are the categories that can roll skill tree bonuses
+// (circlets and class helms, class shields, class weapons, amazon gloves and grand charms)
+var skillTabCategories = map[string]bool{
+	"helm":   true,
+	"shield": true,
+	"weapon": true,
+	"gloves": true,
+	"charm":  true,
+}
+
+func setOf(codes ...string) map[string]bool {
+	m := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		m[c] = true
+	}
+	return m
+}
+
+// IsStatApplicable reports whether an affix filter code can match items of the given category.
+// Unknown categories and codes are assumed applicable.
+func IsStatApplicable(category, code string) bool {
+	if statlessCategories[category] {
+		return false
+	}
+
+	if GetSkillTabParam(code) != "" {
+		if !isKnownCategory(category) {
+			return true
+		}
+		return skillTabCategories[category]
+	}
+
+	return !categoryExcludedStats[category][NormalizeStatCode(code)]
+}
+
+// isKnownCategory reports whether code is one of the D2 categories
+func isKnownCategory(code string) bool {
+	for _, c := range Categories {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
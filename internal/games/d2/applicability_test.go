@@ -0,0 +1,30 @@
+package d2
+
+import "testing"
+
+func TestIsStatApplicable(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		code     string
+		expected bool
+	}{
+		{name: "ias on weapon", category: "weapon", code: "ias", expected: true},
+		{name: "ias on ring", category: "ring", code: "ias", expected: false},
+		{name: "game code variant on ring", category: "ring", code: "swing2", expected: false},
+		{name: "fcr on amulet", category: "amulet", code: "fcr", expected: true},
+		{name: "any stat on rune", category: "rune", code: "all_res", expected: false},
+		{name: "skill tab on helm", category: "helm", code: "sor-fire", expected: true},
+		{name: "skill tab on amulet", category: "amulet", code: "sor-fire", expected: false},
+		{name: "skill tab on unknown category", category: "relic", code: "sor-fire", expected: true},
+		{name: "unknown code", category: "ring", code: "some_new_stat", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStatApplicable(tt.category, tt.code); got != tt.expected {
+				t.Errorf("IsStatApplicable(%q, %q) = %v, want %v", tt.category, tt.code, got, tt.expected)
+			}
+		})
+	}
+}
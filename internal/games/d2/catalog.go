@@ -0,0 +1,77 @@
+package d2
+
+import (
+	"sort"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+)
+
+// gemTypes and gemGrades combine into every gem name, e.g. "Perfect Amethyst"
+var (
+	gemTypes  = []string{"Amethyst", "Diamond", "Emerald", "Ruby", "Sapphire", "Topaz", "Skull"}
+	gemGrades = []string{"Chipped", "Flawed", "", "Flawless", "Perfect"}
+)
+
+// miscCatalogItems are the non-rune, non-gem items commonly offered as currency
+var miscCatalogItems = []games.CatalogItem{
+	{Name: "Key of Terror", Aliases: []string{"Terror Key"}},
+	{Name: "Key of Hate", Aliases: []string{"Hate Key"}},
+	{Name: "Key of Destruction", Aliases: []string{"Destruction Key"}},
+	{Name: "Twisted Essence of Suffering"},
+	{Name: "Charged Essence of Hatred"},
+	{Name: "Burning Essence of Terror"},
+	{Name: "Festering Essence of Destruction"},
+	{Name: "Token of Absolution", Aliases: []string{"Token"}},
+	{Name: "Mephisto's Brain"},
+	{Name: "Diablo's Horn"},
+	{Name: "Baal's Eye"},
+	{Name: "Standard of Heroes"},
+}
+
+// CatalogItems lists the items with canonical names known to this service:
+// runes, gems and common misc currency. Uniques, sets and bases are looked up
+// in catalog-api instead.
+var CatalogItems = buildCatalogItems()
+
+func buildCatalogItems() []games.CatalogItem {
+	runeCodes := make([]string, 0, len(RuneCodes))
+	for code := range RuneCodes {
+		runeCodes = append(runeCodes, code)
+	}
+	sort.Strings(runeCodes)
+
+	items := make([]games.CatalogItem, 0, len(runeCodes)+len(gemTypes)*len(gemGrades)+len(miscCatalogItems))
+	for _, code := range runeCodes {
+		r := RuneCodes[code]
+		items = append(items, games.CatalogItem{
+			Name:     r.Name,
+			Type:     "rune",
+			Category: "rune",
+			Rarity:   "normal",
+			Aliases:  []string{r.Name + " Rune", r.Code},
+		})
+	}
+
+	for _, gem := range gemTypes {
+		for _, grade := range gemGrades {
+			name := gem
+			if grade != "" {
+				name = grade + " " + gem
+			}
+			items = append(items, games.CatalogItem{
+				Name:     name,
+				Type:     "gem",
+				Category: "gem",
+				Rarity:   "normal",
+			})
+		}
+	}
+
+	for _, item := range miscCatalogItems {
+		item.Type = "misc"
+		item.Category = "misc"
+		item.Rarity = "normal"
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,72 @@
+package d2
+
+import (
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+)
+
+func TestResolveItem(t *testing.T) {
+	registry := games.NewRegistry()
+	Register(registry)
+
+	tests := []struct {
+		name      string
+		input     string
+		wantFound bool
+		wantExact bool
+		wantName  string
+		wantType  string
+	}{
+		{name: "canonical rune", input: "Ber", wantFound: true, wantExact: true, wantName: "Ber", wantType: "rune"},
+		{name: "rune suffix and case", input: "  jah RUNE ", wantFound: true, wantExact: true, wantName: "Jah", wantType: "rune"},
+		{name: "rune code", input: "r24", wantFound: true, wantExact: true, wantName: "Ist", wantType: "rune"},
+		{name: "gem", input: "perfect amethyst", wantFound: true, wantExact: true, wantName: "Perfect Amethyst", wantType: "gem"},
+		{name: "punctuation ignored", input: "mephistos brain", wantFound: true, wantExact: true, wantName: "Mephisto's Brain", wantType: "misc"},
+		{name: "misc alias", input: "Terror Key", wantFound: true, wantExact: true, wantName: "Key of Terror", wantType: "misc"},
+		{name: "typo is fuzzy", input: "Perfect Amethist", wantFound: true, wantExact: false, wantName: "Perfect Amethyst", wantType: "gem"},
+		{name: "short typo is not guessed", input: "Bir", wantFound: false},
+		{name: "unknown item", input: "Harlequin Crest", wantFound: false},
+		{name: "blank", input: "  ", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := registry.ResolveItem("diablo2", tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match.Found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", match.Found, tt.wantFound)
+			}
+			if !tt.wantFound {
+				return
+			}
+			if match.Exact != tt.wantExact {
+				t.Errorf("exact = %v, want %v", match.Exact, tt.wantExact)
+			}
+			if match.Item.Name != tt.wantName || match.Item.Type != tt.wantType {
+				t.Errorf("got %s (%s), want %s (%s)", match.Item.Name, match.Item.Type, tt.wantName, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestResolveItem_UnknownGame(t *testing.T) {
+	registry := games.NewRegistry()
+	Register(registry)
+
+	if _, err := registry.ResolveItem("poe", "Ber"); err == nil {
+		t.Fatal("expected an error for an unknown game")
+	}
+}
+
+func TestCatalogItems_UniqueNames(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, item := range CatalogItems {
+		if seen[item.Name] {
+			t.Errorf("duplicate catalog item %q", item.Name)
+		}
+		seen[item.Name] = true
+	}
+}
@@ -17,9 +17,38 @@ var Categories = []games.Category{
 	{Code: "ring", Name: "Rings"},
 	{Code: "charm", Name: "Charms"},
 	{Code: "jewel", Name: "Jewels"},
-	{Code: "rune", Name: "Runes"},
-	{Code: "gem", Name: "Gems"},
-	{Code: "misc", Name: "Miscellaneous"},
+	{Code: "rune", Name: "Runes", DefaultSort: "created_at", DefaultSortOrder: "desc"},
+	{Code: "gem", Name: "Gems", DefaultSort: "created_at", DefaultSortOrder: "desc"},
+	{Code: "misc", Name: "Miscellaneous", DefaultSort: "name", DefaultSortOrder: "asc"},
+}
+
+// StatDisplayOrder is the order item stats are shown in, by canonical code:
+// defense, then resistances, then skills, damage, speed and utility. Stats not
+// listed keep their stored order after these.
+var StatDisplayOrder = []string{
+	"ac%", "ac",
+	"all_res", "fire_res", "cold_res", "light_res", "poison_res",
+	"allskills", "skilltab", "skill", "oskill",
+	"ed", "ar",
+	"fcr", "ias", "fhr", "frw",
+	"life_steal", "mana_steal",
+	"crushing_blow", "deadly_strike", "open_wounds",
+	"mf", "gf",
+}
+
+// statDisplayRanks maps every variant of each StatDisplayOrder code to its position
+var statDisplayRanks = buildStatDisplayRanks()
+
+func buildStatDisplayRanks() map[string]int {
+	ranks := make(map[string]int)
+	for i, code := range StatDisplayOrder {
+		for _, variant := range ExpandStatCode(code) {
+			if _, exists := ranks[variant]; !exists {
+				ranks[variant] = i
+			}
+		}
+	}
+	return ranks
 }
 
 // categoryAliases maps parent category codes to additional stored values
@@ -40,6 +69,7 @@ func GetSubcategories(category string) []string {
 // Rarities for Diablo 2 items
 var Rarities = []string{
 	"normal",
+	"superior",
 	"magic",
 	"rare",
 	"unique",
@@ -57,3 +87,10 @@ var ServiceTypes = []games.ServiceType{
 	{Code: "ubers", Name: "Ubers"},
 	{Code: "colossal_ancients", Name: "Colossal Ancients"},
 }
+
+// Regions for Diablo 2 realms
+var Regions = []games.Region{
+	{Code: "americas", Name: "Americas", Aliases: []string{"us", "na", "america", "us-east", "us-west"}},
+	{Code: "europe", Name: "Europe", Aliases: []string{"eu"}},
+	{Code: "asia", Name: "Asia", Aliases: []string{"kr", "tw", "as"}},
+}
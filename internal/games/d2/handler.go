@@ -62,6 +62,22 @@ func (h *Handler) GetServiceTypes() []games.ServiceType {
 	return ServiceTypes
 }
 
+// GetRegions returns the D2R realm regions
+func (h *Handler) GetRegions() []games.Region {
+	return Regions
+}
+
+// StatDisplayRank returns the stat's position in StatDisplayOrder
+func (h *Handler) StatDisplayRank(code string) (int, bool) {
+	rank, ok := statDisplayRanks[code]
+	return rank, ok
+}
+
+// GetCatalogItems returns the runes, gems and misc items with canonical names
+func (h *Handler) GetCatalogItems() []games.CatalogItem {
+	return CatalogItems
+}
+
 // ItemStat represents a single stat on an item
 type ItemStat struct {
 	Code  string `json:"code"`
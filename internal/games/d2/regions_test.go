@@ -0,0 +1,46 @@
+package d2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+)
+
+func TestNormalizeRegion(t *testing.T) {
+	registry := games.NewRegistry()
+	Register(registry)
+
+	tests := []struct {
+		name     string
+		game     string
+		region   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "canonical code", game: "diablo2", region: "europe", expected: "europe"},
+		{name: "mixed case and spaces", game: "diablo2", region: "  Americas ", expected: "americas"},
+		{name: "alias", game: "diablo2", region: "EU", expected: "europe"},
+		{name: "empty", game: "diablo2", region: "", expected: ""},
+		{name: "unknown region", game: "diablo2", region: "mars", wantErr: true},
+		{name: "unregistered game passes through", game: "poe", region: "Mars", expected: "mars"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.NormalizeRegion(tt.game, tt.region)
+			if tt.wantErr {
+				if !errors.Is(err, games.ErrUnknownRegion) {
+					t.Errorf("NormalizeRegion(%q, %q) error = %v, want ErrUnknownRegion", tt.game, tt.region, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeRegion(%q, %q) unexpected error: %v", tt.game, tt.region, err)
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizeRegion(%q, %q) = %q, want %q", tt.game, tt.region, got, tt.expected)
+			}
+		})
+	}
+}
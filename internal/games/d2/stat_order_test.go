@@ -0,0 +1,27 @@
+package d2
+
+import "testing"
+
+func TestStatDisplayRank(t *testing.T) {
+	h := NewHandler()
+
+	defense, ok := h.StatDisplayRank("ac%")
+	if !ok {
+		t.Fatal("ac% should be ranked")
+	}
+	fireRes, ok := h.StatDisplayRank("res-fire")
+	if !ok {
+		t.Fatal("game code variant res-fire should share fire_res's rank")
+	}
+	skills, _ := h.StatDisplayRank("allskills")
+
+	if !(defense < fireRes && fireRes < skills) {
+		t.Errorf("expected defense < resistances < skills, got %d, %d, %d", defense, fireRes, skills)
+	}
+	if canonical, _ := h.StatDisplayRank("fire_res"); canonical != fireRes {
+		t.Errorf("fire_res rank %d differs from res-fire rank %d", canonical, fireRes)
+	}
+	if _, ok := h.StatDisplayRank("some_new_stat"); ok {
+		t.Error("unknown code should not be ranked")
+	}
+}
@@ -0,0 +1,90 @@
+package d2
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultRuneValuesJSON holds the shipped rune values, keyed by lowercase rune name.
+// Low runes are nearly worthless in trade but still count toward an offer's total.
+//
+//go:embed rune_values.json
+var defaultRuneValuesJSON []byte
+
+// RuneValues maps lowercase rune names to approximate trade values in Ist equivalents
+type RuneValues map[string]float64
+
+var defaultRuneValues = mustLoadRuneValues(defaultRuneValuesJSON)
+
+func mustLoadRuneValues(data []byte) RuneValues {
+	var values RuneValues
+	if err := json.Unmarshal(data, &values); err != nil {
+		panic(fmt.Sprintf("d2: invalid rune_values.json: %v", err))
+	}
+	return values
+}
+
+// DefaultRuneValues returns a copy of the shipped rune values
+func DefaultRuneValues() RuneValues {
+	values := make(RuneValues, len(defaultRuneValues))
+	for name, value := range defaultRuneValues {
+		values[name] = value
+	}
+	return values
+}
+
+// ParseRuneValues applies a comma-separated override spec such as "ber=9,jah=8.5"
+// on top of the shipped values. Names are rune names, case-insensitive.
+func ParseRuneValues(spec string) (RuneValues, error) {
+	values := DefaultRuneValues()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, raw, _ := strings.Cut(entry, "=")
+		name = runeValueKey(name)
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("rune value %q is not a rune", entry)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("rune value %q needs a non-negative number", entry)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// EstimateItemValue returns the estimated value of an offered item in Ist equivalents.
+// Only runes have a known value; ok is false for anything else.
+func (v RuneValues) EstimateItemValue(itemType, name string, quantity int) (float64, bool) {
+	if itemType != "" && itemType != "rune" {
+		return 0, false
+	}
+
+	value, ok := v[runeValueKey(name)]
+	if !ok {
+		return 0, false
+	}
+
+	if quantity < 1 {
+		quantity = 1
+	}
+	return value * float64(quantity), true
+}
+
+// EstimateItemValue values an offered item with the shipped rune values
+func EstimateItemValue(itemType, name string, quantity int) (float64, bool) {
+	return defaultRuneValues.EstimateItemValue(itemType, name, quantity)
+}
+
+// runeValueKey normalizes "Ber", " ber " and "Ber Rune" to "ber"
+func runeValueKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.TrimSuffix(key, " rune")
+}
@@ -0,0 +1,40 @@
+package d2
+
+import "testing"
+
+func TestDefaultRuneValues_CoverEveryRune(t *testing.T) {
+	values := DefaultRuneValues()
+	for code, r := range RuneCodes {
+		if _, ok := values[runeValueKey(r.Name)]; !ok {
+			t.Errorf("rune %s (%s) has no value in rune_values.json", r.Name, code)
+		}
+	}
+}
+
+func TestParseRuneValues(t *testing.T) {
+	values, err := ParseRuneValues(" Ber = 10, jah rune=8.5,,")
+	if err != nil {
+		t.Fatalf("ParseRuneValues unexpected error: %v", err)
+	}
+
+	if got, _ := values.EstimateItemValue("rune", "Ber", 2); got != 20 {
+		t.Errorf("Ber x2 = %v, want 20", got)
+	}
+	if got, _ := values.EstimateItemValue("rune", "Jah Rune", 1); got != 8.5 {
+		t.Errorf("Jah = %v, want 8.5", got)
+	}
+	if got, _ := values.EstimateItemValue("rune", "Ist", 1); got != 1 {
+		t.Errorf("Ist = %v, want the shipped value 1", got)
+	}
+	if got, _ := EstimateItemValue("rune", "Ber", 1); got != 9 {
+		t.Errorf("overrides changed the shipped Ber value to %v", got)
+	}
+}
+
+func TestParseRuneValues_Invalid(t *testing.T) {
+	for _, spec := range []string{"shako=5", "ber=lots", "ber=-1", "ber"} {
+		if _, err := ParseRuneValues(spec); err == nil {
+			t.Errorf("ParseRuneValues(%q) expected an error", spec)
+		}
+	}
+}
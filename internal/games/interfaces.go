@@ -19,12 +19,25 @@ type GameHandler interface {
 
 	// GetServiceTypes returns the available service types for this game
 	GetServiceTypes() []ServiceType
+
+	// GetRegions returns the canonical regions listings and services can be posted in
+	GetRegions() []Region
+
+	// StatDisplayRank returns where a stat code sorts when item stats are displayed.
+	// ok is false for codes without a configured position.
+	StatDisplayRank(code string) (rank int, ok bool)
+
+	// GetCatalogItems returns the tradeable items the game knows canonical names for
+	GetCatalogItems() []CatalogItem
 }
 
-// Category represents an item category
+// Category represents an item category. DefaultSort and DefaultSortOrder, when
+// set, order browse results for the category if the client doesn't pick a sort.
 type Category struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	Code             string `json:"code"`
+	Name             string `json:"name"`
+	DefaultSort      string `json:"defaultSort,omitempty"`
+	DefaultSortOrder string `json:"defaultSortOrder,omitempty"`
 }
 
 // ServiceType represents a type of in-game service
@@ -32,3 +45,21 @@ type ServiceType struct {
 	Code string `json:"code"`
 	Name string `json:"name"`
 }
+
+// Region represents a game server region. Aliases are alternate spellings
+// that normalize to Code.
+type Region struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// CatalogItem is an item with a canonical name. Aliases are alternate spellings
+// that resolve to Name.
+type CatalogItem struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Category string   `json:"category"`
+	Rarity   string   `json:"rarity"`
+	Aliases  []string `json:"aliases,omitempty"`
+}
@@ -1,10 +1,15 @@
 package games
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 )
 
+// ErrUnknownRegion indicates a region that isn't in the game's region set
+var ErrUnknownRegion = errors.New("unknown region")
+
 // Registry holds all registered game handlers
 type Registry struct {
 	handlers map[string]GameHandler
@@ -16,12 +21,17 @@ var (
 	once            sync.Once
 )
 
+// NewRegistry creates an empty game registry
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[string]GameHandler),
+	}
+}
+
 // GetRegistry returns the default game registry
 func GetRegistry() *Registry {
 	once.Do(func() {
-		defaultRegistry = &Registry{
-			handlers: make(map[string]GameHandler),
-		}
+		defaultRegistry = NewRegistry()
 	})
 	return defaultRegistry
 }
@@ -66,6 +76,31 @@ func (r *Registry) GetCategories(code string) ([]Category, error) {
 	return handler.GetCategories(), nil
 }
 
+// CategoryDefaultSort returns the configured default sort for a game's category,
+// or empty strings when the game or category is unknown or has no default
+func (r *Registry) CategoryDefaultSort(code string, category string) (sortBy string, sortOrder string) {
+	handler, err := r.Get(code)
+	if err != nil {
+		return "", ""
+	}
+	for _, c := range handler.GetCategories() {
+		if c.Code == category {
+			return c.DefaultSort, c.DefaultSortOrder
+		}
+	}
+	return "", ""
+}
+
+// StatDisplayRank returns a stat code's display position for a game. Unknown
+// games and unranked codes report ok=false.
+func (r *Registry) StatDisplayRank(code string, statCode string) (int, bool) {
+	handler, err := r.Get(code)
+	if err != nil {
+		return 0, false
+	}
+	return handler.StatDisplayRank(statCode)
+}
+
 // GetServiceTypes returns service types for a specific game
 func (r *Registry) GetServiceTypes(code string) ([]ServiceType, error) {
 	handler, err := r.Get(code)
@@ -74,3 +109,43 @@ func (r *Registry) GetServiceTypes(code string) ([]ServiceType, error) {
 	}
 	return handler.GetServiceTypes(), nil
 }
+
+// GetRegions returns regions for a specific game
+func (r *Registry) GetRegions(code string) ([]Region, error) {
+	handler, err := r.Get(code)
+	if err != nil {
+		return nil, err
+	}
+	return handler.GetRegions(), nil
+}
+
+// NormalizeRegion maps a region code or alias to the game's canonical region code.
+// Games that aren't registered or don't define regions accept any value as-is.
+func (r *Registry) NormalizeRegion(code string, region string) (string, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region == "" {
+		return "", nil
+	}
+
+	handler, err := r.Get(code)
+	if err != nil {
+		return region, nil
+	}
+
+	regions := handler.GetRegions()
+	if len(regions) == 0 {
+		return region, nil
+	}
+
+	for _, candidate := range regions {
+		if candidate.Code == region {
+			return candidate.Code, nil
+		}
+		for _, alias := range candidate.Aliases {
+			if alias == region {
+				return candidate.Code, nil
+			}
+		}
+	}
+	return "", ErrUnknownRegion
+}
@@ -0,0 +1,15 @@
+package games
+
+// ValueEstimator estimates the trade value of an offered item. ok is false when
+// the item can't be valued.
+type ValueEstimator interface {
+	EstimateItemValue(itemType, name string, quantity int) (value float64, ok bool)
+}
+
+// ValueEstimatorFunc adapts a plain function to ValueEstimator
+type ValueEstimatorFunc func(itemType, name string, quantity int) (float64, bool)
+
+// EstimateItemValue calls f
+func (f ValueEstimatorFunc) EstimateItemValue(itemType, name string, quantity int) (float64, bool) {
+	return f(itemType, name, quantity)
+}
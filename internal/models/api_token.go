@@ -0,0 +1,46 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// API token scopes. Tokens are read-only for now.
+const (
+	// APITokenScopeListingsRead allows browsing listings and reading listing details
+	APITokenScopeListingsRead = "listings:read"
+	// APITokenScopeStatsRead allows reading marketplace stats, recent activity and price summaries
+	APITokenScopeStatsRead = "stats:read"
+)
+
+// APITokenScopes lists every scope a token can be granted
+var APITokenScopes = []string{APITokenScopeListingsRead, APITokenScopeStatsRead}
+
+// APIToken is a personal access token a user creates for a third-party integration.
+// Only the SHA-256 hash of the token is stored; the token itself is shown once.
+type APIToken struct {
+	bun.BaseModel `bun:"table:d2.api_tokens,alias:apt"`
+
+	ID         string     `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	UserID     string     `bun:"user_id,type:uuid,notnull"`
+	Name       string     `bun:"name,notnull"`
+	TokenHash  string     `bun:"token_hash,notnull,unique"`
+	Prefix     string     `bun:"prefix,notnull"`
+	Scopes     []string   `bun:"scopes,array"`
+	RateLimit  int        `bun:"rate_limit,notnull"`
+	LastUsedAt *time.Time `bun:"last_used_at"`
+	RevokedAt  *time.Time `bun:"revoked_at"`
+	CreatedAt  time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// HasScope reports whether the token was granted the scope
+func (t *APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,35 @@
+package models
+
+// BuyerRequirements is the reputation a seller or provider asks of anyone making an
+// offer. A nil threshold means no restriction.
+type BuyerRequirements struct {
+	MinRating *float64
+	MinTrades *int
+	// AllowUnrated lets users with no ratings yet through regardless of the thresholds
+	AllowUnrated bool
+}
+
+// IsSet reports whether any threshold is configured
+func (r BuyerRequirements) IsSet() bool {
+	return r.MinRating != nil || r.MinTrades != nil
+}
+
+// Allows reports whether the profile meets the requirements
+func (r BuyerRequirements) Allows(p *Profile) bool {
+	if !r.IsSet() {
+		return true
+	}
+	if p == nil {
+		return false
+	}
+	if p.RatingCount == 0 && r.AllowUnrated {
+		return true
+	}
+	if r.MinRating != nil && (p.RatingCount == 0 || p.AverageRating < *r.MinRating) {
+		return false
+	}
+	if r.MinTrades != nil && p.TotalTrades < *r.MinTrades {
+		return false
+	}
+	return true
+}
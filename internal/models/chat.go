@@ -15,6 +15,9 @@ type Chat struct {
 	ServiceRunID *string   `bun:"service_run_id,type:uuid"`
 	CreatedAt    time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt    time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	// ArchivedAt is when the chat leaves the inbox after its trade or service run
+	// resolved. It may be in the future while the grace period runs.
+	ArchivedAt *time.Time `bun:"archived_at"`
 
 	// Relations
 	Trade      *Trade       `bun:"rel:belongs-to,join:trade_id=id"`
@@ -47,3 +50,8 @@ func (c *Chat) IsTradeChat() bool {
 func (c *Chat) IsServiceRunChat() bool {
 	return c.ServiceRunID != nil && *c.ServiceRunID != ""
 }
+
+// IsArchived returns true if the chat was archived at or before now
+func (c *Chat) IsArchived(now time.Time) bool {
+	return c.ArchivedAt != nil && !c.ArchivedAt.After(now)
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DeclineTemplate is a seller's saved decline note, reusable when rejecting offers
+type DeclineTemplate struct {
+	bun.BaseModel `bun:"table:d2.decline_templates,alias:dtp"`
+
+	ID        string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	SellerID  string    `bun:"seller_id,type:uuid,notnull"`
+	Name      string    `bun:"name,notnull"`
+	Message   string    `bun:"message,notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
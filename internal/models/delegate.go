@@ -0,0 +1,36 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Delegate permissions
+const (
+	// DelegatePermissionManageListings lets a delegate edit, pause, reserve and cancel the owner's listings
+	DelegatePermissionManageListings = "manage_listings"
+	// DelegatePermissionRespondOffers lets a delegate view, accept and reject offers on the owner's listings and services
+	DelegatePermissionRespondOffers = "respond_offers"
+)
+
+// DelegatePermissions lists every permission an owner can grant
+var DelegatePermissions = []string{DelegatePermissionManageListings, DelegatePermissionRespondOffers}
+
+// Delegate lets another user (a "shop manager") act on an owner's behalf within the
+// granted permissions
+type Delegate struct {
+	bun.BaseModel `bun:"table:d2.delegates,alias:dg"`
+
+	OwnerID     string    `bun:"owner_id,pk,type:uuid"`
+	DelegateID  string    `bun:"delegate_id,pk,type:uuid"`
+	Permissions []string  `bun:"permissions,array"`
+	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// Can reports whether the delegate holds the permission
+func (d *Delegate) Can(permission string) bool {
+	return slices.Contains(d.Permissions, permission)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// What a dispute is about; target_id points into the matching table
+const (
+	DisputeTargetTrade      = "trade"
+	DisputeTargetServiceRun = "service_run"
+)
+
+// Dispute statuses
+const (
+	DisputeStatusOpen     = "open"
+	DisputeStatusResolved = "resolved"
+)
+
+// How an admin settled a dispute: the run or trade ends completed or cancelled
+const (
+	DisputeOutcomeCompleted = "completed"
+	DisputeOutcomeCancelled = "cancelled"
+)
+
+// Dispute is a participant's complaint about a trade or service run, settled by an admin
+type Dispute struct {
+	bun.BaseModel `bun:"table:d2.disputes,alias:dp"`
+
+	ID         string `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	TargetType string `bun:"target_type,notnull"`
+	TargetID   string `bun:"target_id,type:uuid,notnull"`
+	OpenedBy   string `bun:"opened_by,type:uuid,notnull"`
+	Reason     string `bun:"reason,notnull"`
+	// PreviousStatus is the target's status before the dispute, e.g. active or completed
+	PreviousStatus string     `bun:"previous_status,notnull"`
+	Status         string     `bun:"status,notnull,default:'open'"`
+	Outcome        *string    `bun:"outcome"`
+	ResolutionNote *string    `bun:"resolution_note"`
+	ResolvedBy     *string    `bun:"resolved_by,type:uuid"`
+	CreatedAt      time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	ResolvedAt     *time.Time `bun:"resolved_at"`
+}
+
+// IsOpen returns true while the dispute awaits an admin
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeStatusOpen
+}
+
+// GetOutcome returns the outcome or empty string
+func (d *Dispute) GetOutcome() string {
+	if d.Outcome != nil {
+		return *d.Outcome
+	}
+	return ""
+}
+
+// GetResolutionNote returns the admin's note or empty string
+func (d *Dispute) GetResolutionNote() string {
+	if d.ResolutionNote != nil {
+		return *d.ResolutionNote
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Favorite is a listing a user bookmarked to come back to without making an offer
+type Favorite struct {
+	bun.BaseModel `bun:"table:d2.favorites,alias:fav"`
+
+	UserID    string    `bun:"user_id,pk,type:uuid"`
+	ListingID string    `bun:"listing_id,pk,type:uuid"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+
+	// Relations
+	Listing *Listing `bun:"rel:belongs-to,join:listing_id=id"`
+}
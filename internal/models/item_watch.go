@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ItemWatch is a user's "tell me when this item is listed" alert, matched by name and game only
+type ItemWatch struct {
+	bun.BaseModel `bun:"table:d2.item_watches,alias:iw"`
+
+	ID        string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	UserID    string    `bun:"user_id,type:uuid,notnull"`
+	ItemName  string    `bun:"item_name,notnull"`
+	Game      string    `bun:"game,notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -37,11 +41,32 @@ type Listing struct {
 	Region         string          `bun:"region,default:'americas'"`
 	SellerTimezone *string         `bun:"seller_timezone"`
 	Status         string          `bun:"status,notnull,default:'active'"`
+	ReservedFor    *string         `bun:"reserved_for,type:uuid"`
+	ReservedUntil  *time.Time      `bun:"reserved_until"`
+	MaxPendingOffers *int          `bun:"max_pending_offers"`
+	HideStatsOnCard  bool          `bun:"hide_stats_on_card,default:false"`
 	Views       int             `bun:"views,default:0"`
 	CreatedAt   time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt   time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 	ExpiresAt   time.Time       `bun:"expires_at,nullzero"`
 
+	// Optional reputation a buyer needs before they can make an offer
+	MinBuyerRating     *float64 `bun:"min_buyer_rating"`
+	MinBuyerTrades     *int     `bun:"min_buyer_trades"`
+	AllowUnratedBuyers bool     `bun:"allow_unrated_buyers,default:false"`
+
+	// Fingerprint identifies the item for duplicate detection; see Listing.ComputeFingerprint
+	Fingerprint *string `bun:"fingerprint"`
+
+	// ExpiryWarnedAt is when the seller was told the listing is about to expire; cleared when
+	// the expiry moves
+	ExpiryWarnedAt *time.Time `bun:"expiry_warned_at"`
+
+	// Optional item condition: ethereal, socket count and quality (inferior, normal, superior)
+	Ethereal *bool   `bun:"ethereal"`
+	Sockets  *int    `bun:"sockets"`
+	Quality  *string `bun:"quality"`
+
 	// Relations
 	Seller *Profile `bun:"rel:belongs-to,join:seller_id=id"`
 }
@@ -51,6 +76,30 @@ func (l *Listing) IsActive() bool {
 	return l.Status == "active"
 }
 
+// IsReservedFor returns true if the listing is currently held for the given user
+func (l *Listing) IsReservedFor(userID string) bool {
+	return l.Status == "reserved" &&
+		l.ReservedFor != nil && *l.ReservedFor == userID &&
+		l.ReservedUntil != nil && l.ReservedUntil.After(time.Now())
+}
+
+// IsVisibleTo returns true if the user may view the listing; reserved listings
+// are only visible to the seller and the buyer they are held for
+func (l *Listing) IsVisibleTo(userID string) bool {
+	if l.Status != "reserved" {
+		return true
+	}
+	return userID != "" && (userID == l.SellerID || (l.ReservedFor != nil && *l.ReservedFor == userID))
+}
+
+// GetReservedFor returns the reserved buyer ID or empty string
+func (l *Listing) GetReservedFor() string {
+	if l.ReservedFor != nil {
+		return *l.ReservedFor
+	}
+	return ""
+}
+
 // GetImageURL returns the image URL or empty string
 func (l *Listing) GetImageURL() string {
 	if l.ImageURL != nil {
@@ -115,3 +164,59 @@ func (l *Listing) GetSellerTimezone() string {
 	return ""
 }
 
+// ContentHash fingerprints what is being traded: the item name, stats, runes and condition.
+// JSON is re-encoded before hashing so formatting and key order don't matter.
+func (l *Listing) ContentHash() string {
+	data, _ := json.Marshal(l.withCondition([]any{l.Name, canonicalJSON(l.Stats), canonicalJSON(l.Runes)}))
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%x", sum[:16])
+}
+
+// ComputeFingerprint identifies the exact item on offer: name, stats, runes, condition,
+// game and platforms. Two active listings of a seller with the same fingerprint are a double post.
+func (l *Listing) ComputeFingerprint() string {
+	platforms := slices.Clone(l.Platforms)
+	slices.Sort(platforms)
+	data, _ := json.Marshal(l.withCondition([]any{
+		strings.ToLower(strings.TrimSpace(l.Name)),
+		canonicalJSON(l.Stats),
+		canonicalJSON(l.Runes),
+		l.Game,
+		platforms,
+	}))
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%x", sum[:16])
+}
+
+// HasCondition reports whether any of ethereal, sockets or quality is set
+func (l *Listing) HasCondition() bool {
+	return l.Ethereal != nil || l.Sockets != nil || l.Quality != nil
+}
+
+// withCondition adds the item condition to hashed parts when it is set, so listings
+// without one keep the hashes they were stored with
+func (l *Listing) withCondition(parts []any) []any {
+	if !l.HasCondition() {
+		return parts
+	}
+	return append(parts, l.Ethereal, l.Sockets, l.Quality)
+}
+
+// canonicalJSON decodes raw JSON so it re-encodes with sorted keys, treating
+// missing or invalid values as an empty list
+func canonicalJSON(raw json.RawMessage) any {
+	var v any
+	if len(raw) == 0 || json.Unmarshal(raw, &v) != nil || v == nil {
+		return []any{}
+	}
+	return v
+}
+
+// BuyerRequirements returns the reputation required to make an offer on this listing
+func (l *Listing) BuyerRequirements() BuyerRequirements {
+	return BuyerRequirements{
+		MinRating:    l.MinBuyerRating,
+		MinTrades:    l.MinBuyerTrades,
+		AllowUnrated: l.AllowUnratedBuyers,
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Market event sources
+const (
+	MarketEventSourceTrade      = "trade"
+	MarketEventSourceServiceRun = "service_run"
+)
+
+// MarketEvent is an append-only record of a completed trade or service run,
+// normalized for market analytics
+type MarketEvent struct {
+	bun.BaseModel `bun:"table:d2.market_events,alias:me"`
+
+	ID            string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	TransactionID string    `bun:"transaction_id,type:uuid,notnull"`
+	Source        string    `bun:"source,notnull"`
+	Game          string    `bun:"game,notnull"`
+	ItemName      string    `bun:"item_name,notnull"`
+	ItemType      string    `bun:"item_type"`
+	OfferedValue  *float64  `bun:"offered_value"` // Offered items value in Ist equivalents, nil when none could be valued
+	OccurredAt    time.Time `bun:"occurred_at,notnull"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
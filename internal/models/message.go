@@ -18,6 +18,10 @@ type Message struct {
 	ReadAt      *time.Time `bun:"read_at"`
 	CreatedAt   time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 
+	// Image shared in the chat; set on "attachment" messages
+	AttachmentURL  *string `bun:"attachment_url"`
+	AttachmentType *string `bun:"attachment_type"`
+
 	// Denormalized participant IDs for Realtime RLS (simpler policy evaluation)
 	SellerID *string `bun:"seller_id,type:uuid"`
 	BuyerID  *string `bun:"buyer_id,type:uuid"`
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -20,6 +21,18 @@ const (
 	NotificationTypeServiceRunCreated      NotificationType = "service_run_created"
 	NotificationTypeServiceRunCompleted    NotificationType = "service_run_completed"
 	NotificationTypeServiceRunCancelled    NotificationType = "service_run_cancelled"
+	NotificationTypeDigest                 NotificationType = "digest"
+	NotificationTypeAnnouncement           NotificationType = "announcement"
+	NotificationTypeListingReserved        NotificationType = "listing_reserved"
+	NotificationTypeWelcome                NotificationType = "welcome"
+	NotificationTypeItemWatch              NotificationType = "item_watch"
+	NotificationTypePremiumGifted          NotificationType = "premium_gifted"
+	NotificationTypeOfferListingChanged    NotificationType = "offer_listing_changed"
+	NotificationTypeOfferCountered         NotificationType = "offer_countered"
+	NotificationTypeListingExpiring        NotificationType = "listing_expiring"
+	NotificationTypeServiceRunDisputed     NotificationType = "service_run_disputed"
+	NotificationTypeDisputeResolved        NotificationType = "dispute_resolved"
+	NotificationTypeDisputeOpened          NotificationType = "dispute_opened"
 )
 
 // Notification represents a user notification
@@ -38,6 +51,11 @@ type Notification struct {
 	Metadata      json.RawMessage `bun:"metadata,type:jsonb,default:'{}'"`
 	CreatedAt     time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 
+	// EventID tells apart events that legitimately repeat for one reference (a second
+	// gift from the same gifter, a new reservation of the same listing). Only used for
+	// deduplication; not stored.
+	EventID string `bun:"-"`
+
 	// Relations
 	User *Profile `bun:"rel:belongs-to,join:user_id=id"`
 }
@@ -65,3 +83,17 @@ func (n *Notification) GetReferenceID() string {
 	}
 	return ""
 }
+
+// DedupKey identifies the event a notification is about, so a retried request doesn't
+// alert the same user twice. Empty when the notification shouldn't be deduplicated:
+// it has no reference, or (like chat messages) repeats legitimately for one reference.
+// The EventID, when set, is appended so distinct events about one reference all notify.
+func (n *Notification) DedupKey() string {
+	if n.ReferenceID == nil || n.Type == NotificationTypeNewMessage {
+		return ""
+	}
+	if n.EventID != "" {
+		return fmt.Sprintf("%s:%s:%s:%s", n.Type, *n.ReferenceID, n.UserID, n.EventID)
+	}
+	return fmt.Sprintf("%s:%s:%s", n.Type, *n.ReferenceID, n.UserID)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MutableNotificationTypes are the notification types a user can turn off. Announcements,
+// premium gifts, welcomes, digests and dispute updates are always delivered.
+var MutableNotificationTypes = []NotificationType{
+	NotificationTypeTradeRequestReceived,
+	NotificationTypeTradeRequestAccepted,
+	NotificationTypeTradeRequestRejected,
+	NotificationTypeOfferCountered,
+	NotificationTypeOfferListingChanged,
+	NotificationTypeNewMessage,
+	NotificationTypeRatingReceived,
+	NotificationTypeWishlistMatch,
+	NotificationTypeItemWatch,
+	NotificationTypeListingReserved,
+	NotificationTypeListingExpiring,
+	NotificationTypeServiceRunCreated,
+	NotificationTypeServiceRunCompleted,
+	NotificationTypeServiceRunCancelled,
+}
+
+// IsMutable returns true if users can turn this notification type off
+func (t NotificationType) IsMutable() bool {
+	for _, mutable := range MutableNotificationTypes {
+		if t == mutable {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationPreferences records which notification types a user turned on or off.
+// Types missing from Types are enabled, so users without a row get everything.
+type NotificationPreferences struct {
+	bun.BaseModel `bun:"table:d2.notification_preferences,alias:np"`
+
+	UserID    string                    `bun:"user_id,pk,type:uuid"`
+	Types     map[NotificationType]bool `bun:"types,type:jsonb,notnull,default:'{}'"`
+	UpdatedAt time.Time                 `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// IsEnabled returns true if the user gets notifications of this type
+func (p *NotificationPreferences) IsEnabled(t NotificationType) bool {
+	if p == nil || !t.IsMutable() {
+		return true
+	}
+	enabled, ok := p.Types[t]
+	return !ok || enabled
+}
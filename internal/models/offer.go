@@ -24,6 +24,13 @@ type Offer struct {
 	CreatedAt       time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt       time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 	AcceptedAt      *time.Time      `bun:"accepted_at"`
+	// ListingHash is the listing's ContentHash when the offer was made (nil for older offers)
+	ListingHash *string `bun:"listing_hash"`
+	// RequestedAddition lists extra items the buyer wants included with the listing (nil when none)
+	RequestedAddition json.RawMessage `bun:"requested_addition,type:jsonb"`
+	// ParentOfferID is the offer this one counters (nil for offers made by the requester).
+	// A counter keeps the original requester, so accepting it still makes them the buyer.
+	ParentOfferID *string `bun:"parent_offer_id,type:uuid"`
 
 	// Relations
 	Listing       *Listing       `bun:"rel:belongs-to,join:listing_id=id"`
@@ -76,6 +83,12 @@ func (o *Offer) GetDeclineNote() string {
 	return ""
 }
 
+// ListingChanged returns true if the listing's item no longer matches what the
+// offer was made on. Offers made before hashes were recorded never report a change.
+func (o *Offer) ListingChanged() bool {
+	return o.ListingHash != nil && o.Listing != nil && *o.ListingHash != o.Listing.ContentHash()
+}
+
 // IsPending returns true if the offer is pending
 func (o *Offer) IsPending() bool {
 	return o.Status == "pending"
@@ -91,6 +104,17 @@ func (o *Offer) IsRejected() bool {
 	return o.Status == "rejected"
 }
 
+// IsCountered returns true if the seller/provider answered the offer with a counteroffer
+func (o *Offer) IsCountered() bool {
+	return o.Status == "countered"
+}
+
+// IsCounter returns true if the offer is a seller/provider's counter to another offer.
+// Counters are answered by the requester instead of the seller/provider.
+func (o *Offer) IsCounter() bool {
+	return o.ParentOfferID != nil
+}
+
 // IsCancelled returns true if the offer is cancelled
 func (o *Offer) IsCancelled() bool {
 	return o.Status == "cancelled"
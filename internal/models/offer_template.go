@@ -0,0 +1,20 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// OfferTemplate is a buyer's saved bundle of offered items, reusable for quick offers
+type OfferTemplate struct {
+	bun.BaseModel `bun:"table:d2.offer_templates,alias:oft"`
+
+	ID           string          `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	UserID       string          `bun:"user_id,type:uuid,notnull"`
+	Name         string          `bun:"name,notnull"`
+	OfferedItems json.RawMessage `bun:"offered_items,type:jsonb,notnull,default:'[]'"`
+	CreatedAt    time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt    time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -35,9 +35,18 @@ type Profile struct {
 	PreferredPlatforms             []string   `bun:"preferred_platforms,array"`
 	PreferredRegion                *string    `bun:"preferred_region"`
 	PreferredNonRotw               *bool      `bun:"preferred_non_rotw"`
+	QuietHoursStart                *string    `bun:"quiet_hours_start"`
+	QuietHoursEnd                  *string    `bun:"quiet_hours_end"`
 	LastActiveAt                   time.Time  `bun:"last_active_at,nullzero,default:current_timestamp"`
+	Onboarded                      bool       `bun:"onboarded,default:false"`
+	AbuseScore                     int        `bun:"abuse_score,default:0"`
+	ResponseTimeMinutes            *int       `bun:"response_time_minutes"`
+	EmailVerified                  bool       `bun:"email_verified,scanonly"`
 	CreatedAt                      time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt                      time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	// FeatureFlags overrides feature flag rollouts for this user (flag name -> on/off)
+	FeatureFlags map[string]bool `bun:"feature_flags,type:jsonb"`
 }
 
 // GetDisplayName returns the display name or username if not set
@@ -93,6 +102,82 @@ func (p *Profile) GetTimezone() string {
 	return ""
 }
 
+// GetQuietHoursStart returns the quiet hours start (HH:MM) or empty string
+func (p *Profile) GetQuietHoursStart() string {
+	if p.QuietHoursStart != nil {
+		return *p.QuietHoursStart
+	}
+	return ""
+}
+
+// GetQuietHoursEnd returns the quiet hours end (HH:MM) or empty string
+func (p *Profile) GetQuietHoursEnd() string {
+	if p.QuietHoursEnd != nil {
+		return *p.QuietHoursEnd
+	}
+	return ""
+}
+
+// InQuietHours returns true if t falls within the profile's quiet hours,
+// evaluated in the profile timezone (UTC when unset). Windows may wrap midnight.
+func (p *Profile) InQuietHours(t time.Time) bool {
+	from, to, loc, ok := p.quietHoursWindow()
+	if !ok {
+		return false
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+
+	if from < to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+// QuietHoursEndAfter returns the first end of the profile's quiet hours after t, in
+// the profile timezone. Returns false when no quiet hours are set.
+func (p *Profile) QuietHoursEndAfter(t time.Time) (time.Time, bool) {
+	_, to, loc, ok := p.quietHoursWindow()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	local := t.In(loc)
+	end := time.Date(local.Year(), local.Month(), local.Day(), to/60, to%60, 0, 0, loc)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+// quietHoursWindow returns the quiet hours as minutes since midnight and the timezone
+// they're in. Returns false when they're unset, malformed or empty.
+func (p *Profile) quietHoursWindow() (int, int, *time.Location, bool) {
+	start, err := time.Parse("15:04", p.GetQuietHoursStart())
+	if err != nil {
+		return 0, 0, nil, false
+	}
+	end, err := time.Parse("15:04", p.GetQuietHoursEnd())
+	if err != nil {
+		return 0, 0, nil, false
+	}
+
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	if from == to {
+		return 0, 0, nil, false
+	}
+
+	loc := time.UTC
+	if tz := p.GetTimezone(); tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	return from, to, loc, true
+}
+
 // GetPreferredRegion returns the preferred region or empty string
 func (p *Profile) GetPreferredRegion() string {
 	if p.PreferredRegion != nil {
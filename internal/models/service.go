@@ -29,6 +29,11 @@ type Service struct {
 	CreatedAt   time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt   time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 
+	// Optional reputation a buyer needs before they can make an offer
+	MinBuyerRating     *float64 `bun:"min_buyer_rating"`
+	MinBuyerTrades     *int     `bun:"min_buyer_trades"`
+	AllowUnratedBuyers bool     `bun:"allow_unrated_buyers,default:false"`
+
 	// Relations
 	Provider *Profile `bun:"rel:belongs-to,join:provider_id=id"`
 }
@@ -66,3 +71,12 @@ func (s *Service) GetNotes() string {
 	}
 	return ""
 }
+
+// BuyerRequirements returns the reputation required to make an offer on this service
+func (s *Service) BuyerRequirements() BuyerRequirements {
+	return BuyerRequirements{
+		MinRating:    s.MinBuyerRating,
+		MinTrades:    s.MinBuyerTrades,
+		AllowUnrated: s.AllowUnratedBuyers,
+	}
+}
@@ -23,6 +23,10 @@ type Trade struct {
 	CompletedAt  *time.Time `bun:"completed_at"`
 	CancelledAt  *time.Time `bun:"cancelled_at"`
 
+	// Indexes into the offer's offered items each party has ticked as received
+	SellerConfirmedItems []int `bun:"seller_confirmed_items,type:jsonb,default:'[]'"`
+	BuyerConfirmedItems  []int `bun:"buyer_confirmed_items,type:jsonb,default:'[]'"`
+
 	// Relations
 	Offer   *Offer   `bun:"rel:belongs-to,join:offer_id=id"`
 	Listing *Listing `bun:"rel:belongs-to,join:listing_id=id"`
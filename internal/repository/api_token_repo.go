@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type apiTokenRepository struct {
+	db *database.BunDB
+}
+
+// NewAPITokenRepository creates a new API token repository
+func NewAPITokenRepository(db *database.BunDB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	_, err := r.db.DB().NewInsert().
+		Model(token).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create api token",
+			"error", err.Error(),
+			"user_id", token.UserID,
+		)
+	}
+	return err
+}
+
+func (r *apiTokenRepository) GetByID(ctx context.Context, id string) (*models.APIToken, error) {
+	token := new(models.APIToken)
+	err := r.db.DB().NewSelect().
+		Model(token).
+		Where("apt.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) GetByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	token := new(models.APIToken)
+	err := r.db.DB().NewSelect().
+		Model(token).
+		Where("apt.token_hash = ?", hash).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *apiTokenRepository) ListByUserID(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	err := r.db.DB().NewSelect().
+		Model(&tokens).
+		Where("apt.user_id = ?", userID).
+		Where("apt.revoked_at IS NULL").
+		Order("apt.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) CountActiveByUserID(ctx context.Context, userID string) (int, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.APIToken)(nil)).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Count(ctx)
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.APIToken)(nil)).
+		Set("revoked_at = ?", at).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to revoke api token",
+			"error", err.Error(),
+			"api_token_id", id,
+		)
+	}
+	return err
+}
+
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.APIToken)(nil)).
+		Set("last_used_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/uptrace/bun"
 )
 
 type chatRepository struct {
@@ -18,7 +20,7 @@ func NewChatRepository(db *database.BunDB) ChatRepository {
 }
 
 func (r *chatRepository) Create(ctx context.Context, chat *models.Chat) error {
-	_, err := r.db.DB().NewInsert().
+	_, err := r.db.Conn(ctx).NewInsert().
 		Model(chat).
 		Exec(ctx)
 	if err != nil {
@@ -86,6 +88,30 @@ func (r *chatRepository) GetByServiceRunID(ctx context.Context, serviceRunID str
 	return chat, nil
 }
 
+// GetByOfferID loads the chat of the trade or service run created when the offer
+// was accepted, with the same relations as GetByIDWithContext
+func (r *chatRepository) GetByOfferID(ctx context.Context, offerID string) (*models.Chat, error) {
+	chat := new(models.Chat)
+	err := r.db.DB().NewSelect().
+		Model(chat).
+		Relation("Trade").
+		Relation("Trade.Seller").
+		Relation("Trade.Buyer").
+		Relation("Trade.Listing").
+		Relation("ServiceRun").
+		Relation("ServiceRun.Provider").
+		Relation("ServiceRun.Client").
+		Relation("ServiceRun.Service").
+		Where("trade.offer_id = ? OR service_run.offer_id = ?", offerID, offerID).
+		Order("c.created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
+
 func (r *chatRepository) Update(ctx context.Context, chat *models.Chat) error {
 	_, err := r.db.DB().NewUpdate().
 		Model(chat).
@@ -99,3 +125,86 @@ func (r *chatRepository) Update(ctx context.Context, chat *models.Chat) error {
 	}
 	return err
 }
+
+// ListByParticipant lists the chats of the user's trades and service runs, newest
+// first. archived picks archived chats instead of the inbox;
+// chats whose archive time is still ahead count as not archived yet.
+func (r *chatRepository) ListByParticipant(ctx context.Context, userID string, archived bool, offset, limit int) ([]*models.Chat, int, error) {
+	var chats []*models.Chat
+
+	query := r.db.DB().NewSelect().
+		Model(&chats).
+		Relation("Trade").
+		Relation("Trade.Seller").
+		Relation("Trade.Buyer").
+		Relation("Trade.Listing").
+		Relation("ServiceRun").
+		Relation("ServiceRun.Provider").
+		Relation("ServiceRun.Client").
+		Relation("ServiceRun.Service").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("trade.seller_id = ? OR trade.buyer_id = ?", userID, userID).
+				WhereOr("service_run.provider_id = ? OR service_run.client_id = ?", userID, userID)
+		})
+
+	if archived {
+		query = query.Where("c.archived_at <= now()")
+	} else {
+		query = query.Where("c.archived_at IS NULL OR c.archived_at > now()")
+	}
+
+	count, err := query.Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count chats",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return nil, 0, err
+	}
+
+	query = query.Order("c.created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		logger.FromContext(ctx).Error("failed to list chats",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return nil, 0, err
+	}
+
+	return chats, count, nil
+}
+
+// ArchiveByTradeID schedules the trade's chat to be archived at archivedAt.
+// Chats that already have an archive time keep it.
+func (r *chatRepository) ArchiveByTradeID(ctx context.Context, tradeID string, archivedAt time.Time) error {
+	return r.archive(ctx, "trade_id", tradeID, archivedAt)
+}
+
+// ArchiveByServiceRunID schedules the service run's chat to be archived at archivedAt.
+// Chats that already have an archive time keep it.
+func (r *chatRepository) ArchiveByServiceRunID(ctx context.Context, serviceRunID string, archivedAt time.Time) error {
+	return r.archive(ctx, "service_run_id", serviceRunID, archivedAt)
+}
+
+// archive sets archived_at on the chat whose column matches id
+func (r *chatRepository) archive(ctx context.Context, column string, id string, archivedAt time.Time) error {
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.Chat)(nil)).
+		Set("archived_at = ?", archivedAt).
+		Where(column+" = ?", id).
+		Where("archived_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to archive chat",
+			"error", err.Error(),
+			column, id,
+		)
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type declineTemplateRepository struct {
+	db *database.BunDB
+}
+
+// NewDeclineTemplateRepository creates a new decline template repository
+func NewDeclineTemplateRepository(db *database.BunDB) DeclineTemplateRepository {
+	return &declineTemplateRepository{db: db}
+}
+
+func (r *declineTemplateRepository) Create(ctx context.Context, template *models.DeclineTemplate) error {
+	_, err := r.db.DB().NewInsert().
+		Model(template).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create decline template",
+			"error", err.Error(),
+			"seller_id", template.SellerID,
+		)
+	}
+	return err
+}
+
+func (r *declineTemplateRepository) GetByID(ctx context.Context, id string) (*models.DeclineTemplate, error) {
+	template := new(models.DeclineTemplate)
+	err := r.db.DB().NewSelect().
+		Model(template).
+		Where("dtp.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (r *declineTemplateRepository) Update(ctx context.Context, template *models.DeclineTemplate) error {
+	template.UpdatedAt = time.Now()
+	_, err := r.db.DB().NewUpdate().
+		Model(template).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update decline template",
+			"error", err.Error(),
+			"decline_template_id", template.ID,
+		)
+	}
+	return err
+}
+
+func (r *declineTemplateRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.DB().NewDelete().
+		Model((*models.DeclineTemplate)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to delete decline template",
+			"error", err.Error(),
+			"decline_template_id", id,
+		)
+	}
+	return err
+}
+
+func (r *declineTemplateRepository) ListBySellerID(ctx context.Context, sellerID string) ([]*models.DeclineTemplate, error) {
+	var templates []*models.DeclineTemplate
+	err := r.db.DB().NewSelect().
+		Model(&templates).
+		Where("dtp.seller_id = ?", sellerID).
+		Order("dtp.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *declineTemplateRepository) CountBySellerID(ctx context.Context, sellerID string) (int, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.DeclineTemplate)(nil)).
+		Where("seller_id = ?", sellerID).
+		Count(ctx)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type delegateRepository struct {
+	db *database.BunDB
+}
+
+// NewDelegateRepository creates a new delegate repository
+func NewDelegateRepository(db *database.BunDB) DelegateRepository {
+	return &delegateRepository{db: db}
+}
+
+func (r *delegateRepository) GetByOwnerAndDelegate(ctx context.Context, ownerID, delegateID string) (*models.Delegate, error) {
+	delegate := new(models.Delegate)
+	err := r.db.DB().NewSelect().
+		Model(delegate).
+		Where("dg.owner_id = ?", ownerID).
+		Where("dg.delegate_id = ?", delegateID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return delegate, nil
+}
+
+func (r *delegateRepository) ListByOwnerID(ctx context.Context, ownerID string) ([]*models.Delegate, error) {
+	var delegates []*models.Delegate
+	err := r.db.DB().NewSelect().
+		Model(&delegates).
+		Where("dg.owner_id = ?", ownerID).
+		Order("dg.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return delegates, nil
+}
+
+// Upsert grants the delegate's permissions, replacing any the owner granted before
+func (r *delegateRepository) Upsert(ctx context.Context, delegate *models.Delegate) error {
+	_, err := r.db.DB().NewInsert().
+		Model(delegate).
+		On("CONFLICT (owner_id, delegate_id) DO UPDATE").
+		Set("permissions = EXCLUDED.permissions").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("created_at").
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to save delegate",
+			"error", err.Error(),
+			"owner_id", delegate.OwnerID,
+			"delegate_id", delegate.DelegateID,
+		)
+	}
+	return err
+}
+
+func (r *delegateRepository) Delete(ctx context.Context, ownerID, delegateID string) error {
+	_, err := r.db.DB().NewDelete().
+		Model((*models.Delegate)(nil)).
+		Where("owner_id = ?", ownerID).
+		Where("delegate_id = ?", delegateID).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to delete delegate",
+			"error", err.Error(),
+			"owner_id", ownerID,
+			"delegate_id", delegateID,
+		)
+	}
+	return err
+}
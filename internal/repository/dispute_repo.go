@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type disputeRepository struct {
+	db *database.BunDB
+}
+
+// NewDisputeRepository creates a new dispute repository
+func NewDisputeRepository(db *database.BunDB) DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+func (r *disputeRepository) Create(ctx context.Context, dispute *models.Dispute) error {
+	_, err := r.db.DB().NewInsert().
+		Model(dispute).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create dispute",
+			"error", err.Error(),
+			"target_type", dispute.TargetType,
+			"target_id", dispute.TargetID,
+		)
+	}
+	return err
+}
+
+func (r *disputeRepository) GetByID(ctx context.Context, id string) (*models.Dispute, error) {
+	dispute := new(models.Dispute)
+	err := r.db.DB().NewSelect().
+		Model(dispute).
+		Where("dp.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetOpenByTarget returns the open dispute on a trade or service run, or nil if there is none
+func (r *disputeRepository) GetOpenByTarget(ctx context.Context, targetType, targetID string) (*models.Dispute, error) {
+	dispute := new(models.Dispute)
+	err := r.db.DB().NewSelect().
+		Model(dispute).
+		Where("dp.target_type = ?", targetType).
+		Where("dp.target_id = ?", targetID).
+		Where("dp.status = ?", models.DisputeStatusOpen).
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (r *disputeRepository) Update(ctx context.Context, dispute *models.Dispute) error {
+	dispute.UpdatedAt = time.Now()
+	_, err := r.db.DB().NewUpdate().
+		Model(dispute).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update dispute",
+			"error", err.Error(),
+			"dispute_id", dispute.ID,
+		)
+	}
+	return err
+}
+
+func (r *disputeRepository) List(ctx context.Context, status, targetType string, offset, limit int) ([]*models.Dispute, int, error) {
+	var disputes []*models.Dispute
+
+	q := r.db.DB().NewSelect().
+		Model(&disputes).
+		OrderExpr("dp.created_at ASC")
+
+	if status != "" {
+		q = q.Where("dp.status = ?", status)
+	}
+	if targetType != "" {
+		q = q.Where("dp.target_type = ?", targetType)
+	}
+
+	count, err := q.Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count disputes",
+			"error", err.Error(),
+		)
+		return nil, 0, err
+	}
+
+	err = q.Offset(offset).Limit(limit).Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list disputes",
+			"error", err.Error(),
+		)
+		return nil, 0, err
+	}
+
+	return disputes, count, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type favoriteRepository struct {
+	db *database.BunDB
+}
+
+// NewFavoriteRepository creates a new favorite listings repository
+func NewFavoriteRepository(db *database.BunDB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+// Add bookmarks a listing for the user; adding one that is already there does nothing
+func (r *favoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	_, err := r.db.DB().NewInsert().
+		Model(favorite).
+		On("CONFLICT (user_id, listing_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to add favorite",
+			"error", err.Error(),
+			"user_id", favorite.UserID,
+			"listing_id", favorite.ListingID,
+		)
+	}
+	return err
+}
+
+// Remove drops a bookmark; removing one that isn't there does nothing
+func (r *favoriteRepository) Remove(ctx context.Context, userID, listingID string) error {
+	_, err := r.db.DB().NewDelete().
+		Model((*models.Favorite)(nil)).
+		Where("user_id = ?", userID).
+		Where("listing_id = ?", listingID).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to remove favorite",
+			"error", err.Error(),
+			"user_id", userID,
+			"listing_id", listingID,
+		)
+	}
+	return err
+}
+
+// ListListings returns the user's favorited listings that are still active, with their
+// sellers, most recently favorited first, plus the total count
+func (r *favoriteRepository) ListListings(ctx context.Context, userID string, offset, limit int) ([]*models.Listing, int, error) {
+	var listings []*models.Listing
+	count, err := r.db.DB().NewSelect().
+		Model(&listings).
+		Relation("Seller").
+		Join("JOIN d2.favorites AS fav ON fav.listing_id = l.id").
+		Where("fav.user_id = ?", userID).
+		Where("l.status = ?", "active").
+		OrderExpr("fav.created_at DESC, l.id").
+		Offset(offset).
+		Limit(limit).
+		ScanAndCount(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list favorite listings",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return nil, 0, err
+	}
+	return listings, count, nil
+}
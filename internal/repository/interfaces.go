@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 )
@@ -9,14 +11,28 @@ import (
 // ProfileRepository defines the interface for profile data access
 type ProfileRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Profile, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*models.Profile, error)
 	GetByUsername(ctx context.Context, username string) (*models.Profile, error)
 	GetByStripeCustomerID(ctx context.Context, customerID string) (*models.Profile, error)
 	GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*models.Profile, error)
 	Update(ctx context.Context, profile *models.Profile) error
 	GetEmailByID(ctx context.Context, id string) (string, error)
 	UpdateLastActiveAt(ctx context.Context, userID string) error
+	MarkOnboarded(ctx context.Context, userID string) (bool, error)
+	AdjustAbuseScore(ctx context.Context, userID string, delta int) (int, error)
+	RefreshResponseTime(ctx context.Context, userID string, since time.Time) (*int, error)
+	ListIDsByAudience(ctx context.Context, audience string, afterID string, limit int) ([]string, error)
 }
 
+// Announcement audiences
+const (
+	AudienceAll           = "all"
+	AudiencePremium       = "premium"
+	AudienceActiveSellers = "active-sellers"
+	// AudienceAdmins is used for internal alerts such as new disputes, not announcements
+	AudienceAdmins = "admins"
+)
+
 // ListingRepository defines the interface for listing data access
 type ListingRepository interface {
 	Create(ctx context.Context, listing *models.Listing) error
@@ -27,10 +43,20 @@ type ListingRepository interface {
 	List(ctx context.Context, filter ListingFilter) ([]*models.Listing, int, error)
 	ListBySellerID(ctx context.Context, sellerID string, status string, offset, limit int) ([]*models.Listing, int, error)
 	CountByListingID(ctx context.Context, listingID string) (int, error)
+	CountPendingOffers(ctx context.Context, listingID string) (int, error)
+	HasPendingOfferFrom(ctx context.Context, listingID, requesterID string) (bool, error)
+	ExistsActiveByFingerprint(ctx context.Context, sellerID, fingerprint string) (string, bool, error)
 	CountActiveBySellerID(ctx context.Context, sellerID string) (int, error)
+	CountChurnedBySeller(ctx context.Context, sellerID string, since time.Time, maxAge time.Duration) (int, error)
 	IncrementViews(ctx context.Context, id string) error
+	AddViews(ctx context.Context, id string, n int64) error
 	CountActive(ctx context.Context) (int, error)
 	CancelOldestActiveListings(ctx context.Context, sellerID string, keepCount int) (int, error)
+	ExpireStale(ctx context.Context, now time.Time, limit int) ([]string, error)
+	CapExpiry(ctx context.Context, sellerID string, lifetime time.Duration, now time.Time) (int, error)
+	ReleaseExpiredReservations(ctx context.Context, now time.Time) ([]string, error)
+	ListExpiringUnwarned(ctx context.Context, before time.Time, afterID string, limit int) ([]*models.Listing, error)
+	MarkExpiryWarned(ctx context.Context, ids []string, now time.Time) error
 }
 
 // StatsRepository defines the interface for marketplace stats data access
@@ -61,10 +87,28 @@ type ListingFilter struct {
 	Rarity          string
 	AffixFilters    []AffixFilter
 	AskingForFilter *AskingForFilter
+	ActiveWithin    *time.Duration
 	SortBy          string
 	SortOrder       string
 	Offset          int
 	Limit           int
+	// ShadowThrottleScore ranks sellers at or above this abuse score last (0 disables)
+	ShadowThrottleScore int
+	// Fuzzy matches Query by pg_trgm similarity instead of substring, closest names first
+	Fuzzy bool
+	// ExcludeSellerIDs hides listings from these sellers (e.g. the viewer's own)
+	ExcludeSellerIDs []string
+	// ExcludeIDs hides these listings (e.g. the one similar listings are shown for)
+	ExcludeIDs []string
+	// BaseItemName matches the listing's base item name exactly, ignoring case
+	BaseItemName string
+
+	// Item condition filters. Ethereal false also matches listings that don't say;
+	// the socket range only matches listings with a socket count.
+	Ethereal   *bool
+	MinSockets *int
+	MaxSockets *int
+	Quality    string
 }
 
 // AffixFilter represents an affix filter for JSONB queries
@@ -89,10 +133,20 @@ type OfferRepository interface {
 	GetByIDWithRelations(ctx context.Context, id string) (*models.Offer, error)
 	Update(ctx context.Context, offer *models.Offer) error
 	List(ctx context.Context, filter OfferFilter) ([]*models.Offer, int, error)
+	ListBySellerListings(ctx context.Context, sellerID, status string, offset, limit int) ([]*models.Offer, int, error)
+	ListAfter(ctx context.Context, filter OfferFilter, after *PageCursor) ([]*models.Offer, bool, error)
+	RejectPendingForListing(ctx context.Context, listingID, exceptOfferID, note string, at time.Time) ([]*models.Offer, error)
 	GetDeclineReasons(ctx context.Context) ([]*models.DeclineReason, error)
 	GetDeclineReasonByID(ctx context.Context, id int) (*models.DeclineReason, error)
 }
 
+// PageCursor is a keyset position for newest-first lists. Rows strictly older than
+// (CreatedAt, ID) come next, so rows inserted while paging never shift a page.
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
 // OfferFilter represents offer query parameters
 type OfferFilter struct {
 	UserID    string // Required for permission filtering
@@ -112,16 +166,21 @@ type TradeRepository interface {
 	GetByIDWithRelations(ctx context.Context, id string) (*models.Trade, error)
 	GetByOfferID(ctx context.Context, offerID string) (*models.Trade, error)
 	Update(ctx context.Context, trade *models.Trade) error
+	UpdateLocked(ctx context.Context, id string, fn func(trade *models.Trade) error) (*models.Trade, error)
+	UpdateConfirmedItems(ctx context.Context, trade *models.Trade, role string) error
 	List(ctx context.Context, filter TradeFilter) ([]*models.Trade, int, error)
 	HasActiveTradeForListing(ctx context.Context, listingID string) (bool, error)
+	ListCompletedSince(ctx context.Context, since time.Time) ([]*models.Trade, error)
 }
 
 // TradeFilter represents trade query parameters
 type TradeFilter struct {
-	UserID string // Required for permission filtering
-	Status string // active, completed, cancelled
-	Offset int
-	Limit  int
+	UserID         string // Required for permission filtering
+	Role           string // buyer, seller, all
+	Status         string // active, completed, cancelled
+	CounterpartyID string // Only trades with this other party
+	Offset         int
+	Limit          int
 }
 
 // ServiceRepository defines the interface for service data access
@@ -135,6 +194,7 @@ type ServiceRepository interface {
 	ListProviders(ctx context.Context, filter ServiceProviderFilter) ([]ProviderWithServices, int, error)
 	GetProviderServices(ctx context.Context, providerID string) ([]*models.Service, error)
 	ExistsByProviderAndType(ctx context.Context, providerID string, serviceType string, game string) (bool, error)
+	CountActiveByProviderID(ctx context.Context, providerID string) (int, error)
 }
 
 // ServiceProviderFilter represents service provider query parameters
@@ -181,13 +241,18 @@ type ChatRepository interface {
 	GetByIDWithContext(ctx context.Context, id string) (*models.Chat, error)
 	GetByTradeID(ctx context.Context, tradeID string) (*models.Chat, error)
 	GetByServiceRunID(ctx context.Context, serviceRunID string) (*models.Chat, error)
+	GetByOfferID(ctx context.Context, offerID string) (*models.Chat, error)
 	Update(ctx context.Context, chat *models.Chat) error
+	ListByParticipant(ctx context.Context, userID string, archived bool, offset, limit int) ([]*models.Chat, int, error)
+	ArchiveByTradeID(ctx context.Context, tradeID string, archivedAt time.Time) error
+	ArchiveByServiceRunID(ctx context.Context, serviceRunID string, archivedAt time.Time) error
 }
 
 // MessageRepository defines the interface for message data access
 type MessageRepository interface {
 	Create(ctx context.Context, message *models.Message) error
 	GetByChatID(ctx context.Context, chatID string, offset, limit int) ([]*models.Message, int, error)
+	GetByChatIDBefore(ctx context.Context, chatID string, beforeMessageID string, limit int) ([]*models.Message, bool, error)
 	MarkAsRead(ctx context.Context, messageIDs []string, userID string) error
 	MarkAllAsReadInChat(ctx context.Context, chatID string, userID string) error
 	CountUnread(ctx context.Context, userID string) (int, error)
@@ -196,9 +261,19 @@ type MessageRepository interface {
 // NotificationRepository defines the interface for notification data access
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *models.Notification) error
+	CreateBatch(ctx context.Context, notifications []*models.Notification) error
 	GetByUserID(ctx context.Context, userID string, unreadOnly bool, notificationType string, offset, limit int) ([]*models.Notification, int, error)
+	GetByUserIDAfter(ctx context.Context, userID string, unreadOnly bool, notificationType string, after *PageCursor, limit int) ([]*models.Notification, bool, error)
 	CountUnread(ctx context.Context, userID string) (int, error)
 	MarkAsRead(ctx context.Context, notificationIDs []string, userID string) error
+	MarkReadByReference(ctx context.Context, userID, referenceType, referenceID string) (int, error)
+	SummarizeUnread(ctx context.Context, id, userID, body string) (bool, error)
+}
+
+// NotificationPreferencesRepository defines the interface for per-type notification preferences
+type NotificationPreferencesRepository interface {
+	GetByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	Upsert(ctx context.Context, prefs *models.NotificationPreferences) error
 }
 
 // TransactionRepository defines the interface for transaction data access
@@ -207,8 +282,12 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Transaction, error)
 	GetByTradeID(ctx context.Context, tradeID string) (*models.Transaction, error)
 	GetByServiceRunID(ctx context.Context, serviceRunID string) (*models.Transaction, error)
-	GetPriceHistory(ctx context.Context, itemName string, days int) ([]PriceHistoryRecord, error)
+	GetPriceHistory(ctx context.Context, itemName string, game string, days int) ([]PriceHistoryRecord, error)
 	GetSalesBySeller(ctx context.Context, sellerID string, offset, limit int) ([]SaleRecord, int, error)
+	GetSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time, offset, limit int) ([]SaleRecord, error)
+	CountSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time) (int, error)
+	ListTradeTransactionsSince(ctx context.Context, since time.Time) ([]*models.Transaction, error)
+	HasRecentSale(ctx context.Context, sellerID, itemName string, itemDetails json.RawMessage, since time.Time) (bool, error)
 }
 
 // SaleRecord represents a completed sale with all related data
@@ -243,6 +322,12 @@ type BillingEventRepository interface {
 	ExistsByStripeEventID(ctx context.Context, stripeEventID string) (bool, error)
 }
 
+// MarketEventRepository defines the interface for the append-only market event log
+type MarketEventRepository interface {
+	Create(ctx context.Context, event *models.MarketEvent) error
+	ListByItem(ctx context.Context, game, itemName string, since time.Time) ([]*models.MarketEvent, error)
+}
+
 // WishlistRepository defines the interface for wishlist data access
 type WishlistRepository interface {
 	Create(ctx context.Context, item *models.WishlistItem) error
@@ -255,6 +340,63 @@ type WishlistRepository interface {
 	FindMatchingItems(ctx context.Context, listing *models.Listing) ([]*models.WishlistItem, error)
 }
 
+// DelegateRepository defines the interface for owner -> delegate permission grants
+type DelegateRepository interface {
+	GetByOwnerAndDelegate(ctx context.Context, ownerID, delegateID string) (*models.Delegate, error)
+	ListByOwnerID(ctx context.Context, ownerID string) ([]*models.Delegate, error)
+	Upsert(ctx context.Context, delegate *models.Delegate) error
+	Delete(ctx context.Context, ownerID, delegateID string) error
+}
+
+// DeclineTemplateRepository defines the interface for seller decline template data access
+type DeclineTemplateRepository interface {
+	Create(ctx context.Context, template *models.DeclineTemplate) error
+	GetByID(ctx context.Context, id string) (*models.DeclineTemplate, error)
+	Update(ctx context.Context, template *models.DeclineTemplate) error
+	Delete(ctx context.Context, id string) error
+	ListBySellerID(ctx context.Context, sellerID string) ([]*models.DeclineTemplate, error)
+	CountBySellerID(ctx context.Context, sellerID string) (int, error)
+}
+
+// OfferTemplateRepository defines the interface for buyer offer template data access
+type OfferTemplateRepository interface {
+	Create(ctx context.Context, template *models.OfferTemplate) error
+	GetByID(ctx context.Context, id string) (*models.OfferTemplate, error)
+	Update(ctx context.Context, template *models.OfferTemplate) error
+	Delete(ctx context.Context, id string) error
+	ListByUserID(ctx context.Context, userID string) ([]*models.OfferTemplate, error)
+	CountByUserID(ctx context.Context, userID string) (int, error)
+}
+
+// APITokenRepository defines the interface for personal access token data access
+type APITokenRepository interface {
+	Create(ctx context.Context, token *models.APIToken) error
+	GetByID(ctx context.Context, id string) (*models.APIToken, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIToken, error)
+	ListByUserID(ctx context.Context, userID string) ([]*models.APIToken, error)
+	CountActiveByUserID(ctx context.Context, userID string) (int, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// WatchRepository defines the interface for item watch data access
+type WatchRepository interface {
+	Create(ctx context.Context, watch *models.ItemWatch) error
+	GetByID(ctx context.Context, id string) (*models.ItemWatch, error)
+	Delete(ctx context.Context, id string) error
+	ListByUserID(ctx context.Context, userID string) ([]*models.ItemWatch, error)
+	CountByUserID(ctx context.Context, userID string) (int, error)
+	Exists(ctx context.Context, userID, itemName, game string) (bool, error)
+	FindWatchersForListing(ctx context.Context, listing *models.Listing) ([]*models.ItemWatch, error)
+}
+
+// FavoriteRepository defines the interface for users' favorite (bookmarked) listings
+type FavoriteRepository interface {
+	Add(ctx context.Context, favorite *models.Favorite) error
+	Remove(ctx context.Context, userID, listingID string) error
+	ListListings(ctx context.Context, userID string, offset, limit int) ([]*models.Listing, int, error)
+}
+
 // BugReportRepository defines the interface for bug report data access
 type BugReportRepository interface {
 	Create(ctx context.Context, report *models.BugReport) error
@@ -263,10 +405,22 @@ type BugReportRepository interface {
 	List(ctx context.Context, status string, offset, limit int) ([]*models.BugReport, int, error)
 }
 
+// DisputeRepository defines the interface for dispute data access. Disputes on trades
+// and service runs share one table, told apart by target type.
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *models.Dispute) error
+	GetByID(ctx context.Context, id string) (*models.Dispute, error)
+	GetOpenByTarget(ctx context.Context, targetType, targetID string) (*models.Dispute, error)
+	Update(ctx context.Context, dispute *models.Dispute) error
+	List(ctx context.Context, status, targetType string, offset, limit int) ([]*models.Dispute, int, error)
+}
+
 // RatingRepository defines the interface for rating data access
 type RatingRepository interface {
 	Create(ctx context.Context, rating *models.Rating) error
 	GetByTransactionID(ctx context.Context, transactionID string) ([]*models.Rating, error)
 	GetByUserID(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error)
+	GetReceived(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error)
+	GetGiven(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error)
 	Exists(ctx context.Context, transactionID, raterID string) (bool, error)
 }
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
@@ -65,7 +68,7 @@ func (r *listingRepository) GetByIDWithSeller(ctx context.Context, id string) (*
 }
 
 func (r *listingRepository) Update(ctx context.Context, listing *models.Listing) error {
-	_, err := r.db.DB().NewUpdate().
+	_, err := r.db.Conn(ctx).NewUpdate().
 		Model(listing).
 		WherePK().
 		Exec(ctx)
@@ -92,15 +95,23 @@ func (r *listingRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// browsable limits a listing query to what public browse shows: active listings that
+// haven't expired and aren't held by a trade ("pending", or an active trade row)
+func browsable(query *bun.SelectQuery) *bun.SelectQuery {
+	return query.
+		Where("l.status = ?", "active").
+		// Listings past their expiry stay hidden until the expire-stale sweep flips them
+		Where("(l.expires_at IS NULL OR l.expires_at > ?)", time.Now()).
+		// Exclude listings that have an active trade
+		Where("NOT EXISTS (SELECT 1 FROM d2.trades t WHERE t.listing_id = l.id AND t.status = ?)", "active")
+}
+
 func (r *listingRepository) List(ctx context.Context, filter ListingFilter) ([]*models.Listing, int, error) {
 	var listings []*models.Listing
 
-	query := r.db.DB().NewSelect().
+	query := browsable(r.db.DB().NewSelect().
 		Model(&listings).
-		Relation("Seller").
-		Where("l.status = ?", "active").
-		// Exclude listings that have an active trade
-		Where("NOT EXISTS (SELECT 1 FROM d2.trades t WHERE t.listing_id = l.id AND t.status = ?)", "active")
+		Relation("Seller"))
 
 	// Apply filters
 	query = r.applyFilters(query, filter)
@@ -172,14 +183,69 @@ func (r *listingRepository) CountByListingID(ctx context.Context, listingID stri
 	return count, err
 }
 
+func (r *listingRepository) CountPendingOffers(ctx context.Context, listingID string) (int, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.Offer)(nil)).
+		Where("listing_id = ?", listingID).
+		Where("status = ?", "pending").
+		Count(ctx)
+}
+
+func (r *listingRepository) HasPendingOfferFrom(ctx context.Context, listingID, requesterID string) (bool, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.Offer)(nil)).
+		Where("listing_id = ?", listingID).
+		Where("requester_id = ?", requesterID).
+		Where("status = ?", "pending").
+		Exists(ctx)
+}
+
+// ExistsActiveByFingerprint looks for an active listing of the seller with the given
+// content fingerprint and returns its ID
+func (r *listingRepository) ExistsActiveByFingerprint(ctx context.Context, sellerID, fingerprint string) (string, bool, error) {
+	var id string
+	err := r.db.DB().NewSelect().
+		Model((*models.Listing)(nil)).
+		Column("id").
+		Where("seller_id = ?", sellerID).
+		Where("fingerprint = ?", fingerprint).
+		Where("status = ?", "active").
+		Limit(1).
+		Scan(ctx, &id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return id, true, nil
+}
+
 func (r *listingRepository) applyFilters(query *bun.SelectQuery, filter ListingFilter) *bun.SelectQuery {
 	if filter.SellerID != "" {
 		query = query.Where("l.seller_id = ?", filter.SellerID)
 	}
+	if len(filter.ExcludeSellerIDs) > 0 {
+		query = query.Where("l.seller_id NOT IN (?)", bun.In(filter.ExcludeSellerIDs))
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		query = query.Where("l.id NOT IN (?)", bun.In(filter.ExcludeIDs))
+	}
+	if filter.BaseItemName != "" {
+		query = query.Where("LOWER(l.base_item_name) = LOWER(?)", filter.BaseItemName)
+	}
 
 	if filter.Query != "" {
-		searchPattern := "%" + strings.ToLower(filter.Query) + "%"
-		query = query.Where("LOWER(l.name) LIKE ?", searchPattern)
+		if filter.Fuzzy {
+			// % uses pg_trgm.similarity_threshold (0.3 by default)
+			query = query.Where("l.name % ?", filter.Query)
+		} else {
+			// Every word must appear in the name or the base item name
+			for _, token := range queryTokens(filter.Query) {
+				pattern := "%" + escapeLike(token) + "%"
+				query = query.Where("(LOWER(l.name) LIKE ? OR LOWER(COALESCE(l.base_item_name, '')) LIKE ?)", pattern, pattern)
+			}
+		}
 	}
 
 	game := filter.Game
@@ -220,6 +286,23 @@ func (r *listingRepository) applyFilters(query *bun.SelectQuery, filter ListingF
 		query = query.Where("l.catalog_item_id = ?", filter.CatalogItemID)
 	}
 
+	if filter.Ethereal != nil {
+		if *filter.Ethereal {
+			query = query.Where("l.ethereal = TRUE")
+		} else {
+			query = query.Where("l.ethereal IS NOT TRUE")
+		}
+	}
+	if filter.MinSockets != nil {
+		query = query.Where("l.sockets >= ?", *filter.MinSockets)
+	}
+	if filter.MaxSockets != nil {
+		query = query.Where("l.sockets <= ?", *filter.MaxSockets)
+	}
+	if filter.Quality != "" {
+		query = query.Where("l.quality = ?", filter.Quality)
+	}
+
 	// Apply affix filters (JSONB queries)
 	for _, af := range filter.AffixFilters {
 		query = r.applyAffixFilter(query, af)
@@ -230,6 +313,14 @@ func (r *listingRepository) applyFilters(query *bun.SelectQuery, filter ListingF
 		query = r.applyAskingForFilter(query, *filter.AskingForFilter)
 	}
 
+	// Only sellers seen within the window
+	if filter.ActiveWithin != nil {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM d2.profiles sp WHERE sp.id = l.seller_id AND sp.last_active_at >= ?)",
+			time.Now().Add(-*filter.ActiveWithin),
+		)
+	}
+
 	return query
 }
 
@@ -308,24 +399,42 @@ func (r *listingRepository) applyAskingForFilter(query *bun.SelectQuery, af Aski
 	return query.Where(clause, args...)
 }
 
+// queryTokens splits a search query into lowercase words
+func queryTokens(q string) []string {
+	return strings.Fields(strings.ToLower(q))
+}
+
+// likeEscaper escapes LIKE wildcards so user input only matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes s for use inside a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// SortByRelevance orders text searches by match quality. It is not a column, so it
+// isn't in ListingSortFields, and without a query it falls back to newest first.
+const SortByRelevance = "relevance"
+
 // PremiumBoostMinutes is the duration (in minutes) that premium listings
 // appear at the top of results after creation or refresh.
 const PremiumBoostMinutes = 120
 
+// ListingSortFields whitelists the sortBy values listings can be ordered by,
+// mapped to their columns so user input never reaches the ORDER BY directly
+var ListingSortFields = map[string]string{
+	"created_at":   "l.created_at",
+	"name":         "l.name",
+	"asking_price": "l.asking_price",
+}
+
 func (r *listingRepository) applySorting(query *bun.SelectQuery, filter ListingFilter) *bun.SelectQuery {
 	sortBy := filter.SortBy
 	if sortBy == "" {
 		sortBy = "created_at"
 	}
 
-	// Validate sort field to prevent SQL injection
-	validSortFields := map[string]string{
-		"created_at":   "l.created_at",
-		"name":         "l.name",
-		"asking_price": "l.asking_price",
-	}
-
-	sortField, ok := validSortFields[sortBy]
+	sortField, ok := ListingSortFields[sortBy]
 	if !ok {
 		sortField = "l.created_at"
 	}
@@ -338,6 +447,20 @@ func (r *listingRepository) applySorting(query *bun.SelectQuery, filter ListingF
 	// JOIN profiles so we can check premium status for boost sorting
 	query = query.Join("JOIN d2.profiles AS p ON p.id = l.seller_id")
 
+	// Shadow-throttled sellers sink below everyone else, premium boost included
+	if filter.ShadowThrottleScore > 0 {
+		query = query.OrderExpr("CASE WHEN p.abuse_score >= ? THEN 1 ELSE 0 END", filter.ShadowThrottleScore)
+	}
+
+	// Closest names first for "did you mean" results
+	if filter.Fuzzy && filter.Query != "" {
+		query = query.OrderExpr("similarity(l.name, ?) DESC", filter.Query)
+	} else if sortBy == SortByRelevance && strings.TrimSpace(filter.Query) != "" {
+		// Best matches first, newest first among equally good ones
+		query = applyRelevance(query, filter.Query)
+		sortOrder = "DESC"
+	}
+
 	// Premium listings created/refreshed within the boost window appear first.
 	// After the boost expires, they sort normally alongside free listings.
 	return query.OrderExpr(fmt.Sprintf(
@@ -346,15 +469,54 @@ func (r *listingRepository) applySorting(query *bun.SelectQuery, filter ListingF
 	))
 }
 
+// applyRelevance ranks text search matches: exact name, then name prefix, then every
+// word in the name, then matches that lean on the base item name. Ties go to the
+// closest name by trigram similarity.
+func applyRelevance(query *bun.SelectQuery, q string) *bun.SelectQuery {
+	normalized := strings.Join(queryTokens(q), " ")
+
+	var allInName []string
+	args := []any{normalized, escapeLike(normalized) + "%"}
+	for _, token := range queryTokens(q) {
+		allInName = append(allInName, "LOWER(l.name) LIKE ?")
+		args = append(args, "%"+escapeLike(token)+"%")
+	}
+
+	rank := "CASE WHEN LOWER(l.name) = ? THEN 0 WHEN LOWER(l.name) LIKE ? THEN 1 WHEN " +
+		strings.Join(allInName, " AND ") + " THEN 2 ELSE 3 END"
+	return query.
+		OrderExpr(rank, args...).
+		OrderExpr("similarity(LOWER(l.name), ?) DESC", normalized)
+}
+
 func (r *listingRepository) CountActiveBySellerID(ctx context.Context, sellerID string) (int, error) {
 	count, err := r.db.DB().NewSelect().
 		Model((*models.Listing)(nil)).
 		Where("seller_id = ?", sellerID).
-		Where("status = ?", "active").
+		Where("status IN (?)", bun.In([]string{"active", "reserved", "pending"})).
 		Count(ctx)
 	return count, err
 }
 
+// CountChurnedBySeller counts the seller's listings cancelled since the given time
+// that were cancelled less than maxAge after they were created
+func (r *listingRepository) CountChurnedBySeller(ctx context.Context, sellerID string, since time.Time, maxAge time.Duration) (int, error) {
+	count, err := r.db.DB().NewSelect().
+		Model((*models.Listing)(nil)).
+		Where("seller_id = ?", sellerID).
+		Where("status = ?", "cancelled").
+		Where("updated_at >= ?", since).
+		Where("updated_at - created_at < make_interval(secs => ?)", maxAge.Seconds()).
+		Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count churned listings",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+	}
+	return count, err
+}
+
 func (r *listingRepository) IncrementViews(ctx context.Context, id string) error {
 	_, err := r.db.DB().NewUpdate().
 		Model((*models.Listing)(nil)).
@@ -370,6 +532,22 @@ func (r *listingRepository) IncrementViews(ctx context.Context, id string) error
 	return err
 }
 
+func (r *listingRepository) AddViews(ctx context.Context, id string, n int64) error {
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.Listing)(nil)).
+		Set("views = views + ?", n).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to add listing views",
+			"error", err.Error(),
+			"listing_id", id,
+			"views", n,
+		)
+	}
+	return err
+}
+
 func (r *listingRepository) CountActive(ctx context.Context) (int, error) {
 	count, err := r.db.DB().NewSelect().
 		Model((*models.Listing)(nil)).
@@ -420,3 +598,122 @@ func (r *listingRepository) CancelOldestActiveListings(ctx context.Context, sell
 	rowsAffected, _ := res.RowsAffected()
 	return int(rowsAffected), nil
 }
+
+// ExpireStale expires up to limit active listings whose expires_at has passed, oldest
+// expiry first, and returns their IDs. Rows locked by another sweep are skipped.
+func (r *listingRepository) ExpireStale(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	batch := r.db.DB().NewSelect().
+		Model((*models.Listing)(nil)).
+		Column("id").
+		Where("status = ?", "active").
+		Where("expires_at < ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var ids []string
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.Listing)(nil)).
+		Set("status = ?", "expired").
+		Set("updated_at = ?", now).
+		Where("id IN (?)", batch).
+		Returning("id").
+		Exec(ctx, &ids)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to expire stale listings",
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CapExpiry shortens the seller's open listings that expire more than lifetime after they
+// were created down to that window, or to now if the window has already passed. Expiry
+// is never extended.
+func (r *listingRepository) CapExpiry(ctx context.Context, sellerID string, lifetime time.Duration, now time.Time) (int, error) {
+	res, err := r.db.DB().NewUpdate().
+		Model((*models.Listing)(nil)).
+		Set("expires_at = GREATEST(created_at + make_interval(secs => ?), ?)", lifetime.Seconds(), now).
+		Set("expiry_warned_at = NULL").
+		Where("seller_id = ?", sellerID).
+		Where("status IN (?)", bun.In([]string{"active", "paused", "reserved"})).
+		Where("expires_at > created_at + make_interval(secs => ?)", lifetime.Seconds()).
+		Where("expires_at > ?", now).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to cap listing expiry",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return 0, err
+	}
+	rowsAffected, _ := res.RowsAffected()
+	return int(rowsAffected), nil
+}
+
+// ReleaseExpiredReservations returns reserved listings whose hold has lapsed to active
+// and returns their IDs
+func (r *listingRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.Listing)(nil)).
+		Set("status = ?", "active").
+		Set("reserved_for = NULL").
+		Set("reserved_until = NULL").
+		Set("updated_at = ?", now).
+		Where("status = ?", "reserved").
+		Where("reserved_until < ?", now).
+		Returning("id").
+		Exec(ctx, &ids)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to release expired reservations",
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListExpiringUnwarned returns up to limit active listings expiring before the given time
+// whose seller hasn't been warned yet, ordered by ID after afterID
+func (r *listingRepository) ListExpiringUnwarned(ctx context.Context, before time.Time, afterID string, limit int) ([]*models.Listing, error) {
+	var listings []*models.Listing
+	query := r.db.DB().NewSelect().
+		Model(&listings).
+		Where("l.status = ?", "active").
+		Where("l.expires_at < ?", before).
+		Where("l.expiry_warned_at IS NULL").
+		Order("l.id ASC").
+		Limit(limit)
+	if afterID != "" {
+		query = query.Where("l.id > ?", afterID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		logger.FromContext(ctx).Error("failed to list expiring listings",
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	return listings, nil
+}
+
+// MarkExpiryWarned records that the sellers of the given listings were warned about expiry
+func (r *listingRepository) MarkExpiryWarned(ctx context.Context, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.DB().NewUpdate().
+		Model((*models.Listing)(nil)).
+		Set("expiry_warned_at = ?", now).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to mark listings expiry warned",
+			"error", err.Error(),
+			"count", len(ids),
+		)
+	}
+	return err
+}
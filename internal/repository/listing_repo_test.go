@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// filterSQL renders the listing query built for filter without touching a database
+func filterSQL(t *testing.T, filter ListingFilter) string {
+	t.Helper()
+	sqldb := sql.OpenDB(pgdriver.NewConnector())
+	t.Cleanup(func() { _ = sqldb.Close() })
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	query := db.NewSelect().Model((*models.Listing)(nil))
+	return (&listingRepository{}).applyFilters(query, filter).String()
+}
+
+func TestApplyFilters_EtherealTrue(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{Ethereal: boolPtr(true)})
+
+	assert.Contains(t, sql, "l.ethereal = TRUE")
+}
+
+func TestApplyFilters_EtherealFalseIncludesUnspecified(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{Ethereal: boolPtr(false)})
+
+	assert.Contains(t, sql, "l.ethereal IS NOT TRUE")
+}
+
+func TestApplyFilters_SocketRange(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{MinSockets: intPtr(4), MaxSockets: intPtr(5)})
+
+	assert.Contains(t, sql, "l.sockets >= 4")
+	assert.Contains(t, sql, "l.sockets <= 5")
+}
+
+func TestApplyFilters_MinSocketsOnly(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{MinSockets: intPtr(4)})
+
+	assert.Contains(t, sql, "l.sockets >= 4")
+	assert.NotContains(t, sql, "l.sockets <=")
+}
+
+func TestApplyFilters_EtherealWithSocketsAndQuality(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{Ethereal: boolPtr(true), MinSockets: intPtr(3), Quality: "superior"})
+
+	assert.Contains(t, sql, "l.ethereal = TRUE")
+	assert.Contains(t, sql, "l.sockets >= 3")
+	assert.Contains(t, sql, "l.quality = 'superior'")
+}
+
+func TestApplyFilters_NoConditionFilters(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{})
+
+	assert.NotContains(t, sql, "l.ethereal")
+	assert.NotContains(t, sql, "l.sockets")
+	assert.NotContains(t, sql, "l.quality")
+}
+
+// sortSQL renders the listing query ordered for filter without touching a database
+func sortSQL(t *testing.T, filter ListingFilter) string {
+	t.Helper()
+	sqldb := sql.OpenDB(pgdriver.NewConnector())
+	t.Cleanup(func() { _ = sqldb.Close() })
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	query := db.NewSelect().Model((*models.Listing)(nil))
+	return (&listingRepository{}).applySorting(query, filter).String()
+}
+
+func TestApplyFilters_QueryMatchesEveryWord(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{Query: "Enhanced  Defense shako"})
+
+	for _, word := range []string{"enhanced", "defense", "shako"} {
+		assert.Contains(t, sql, "(LOWER(l.name) LIKE '%"+word+"%' OR LOWER(COALESCE(l.base_item_name, '')) LIKE '%"+word+"%')")
+	}
+}
+
+func TestApplyFilters_QueryEscapesWildcards(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{Query: "100%_"})
+
+	assert.Contains(t, sql, `LIKE '%100\%\_%'`)
+}
+
+func TestApplySorting_RelevanceWithQuery(t *testing.T) {
+	sql := sortSQL(t, ListingFilter{Query: "Harlequin Crest", SortBy: SortByRelevance, SortOrder: "asc"})
+
+	assert.Contains(t, sql, "CASE WHEN LOWER(l.name) = 'harlequin crest' THEN 0 WHEN LOWER(l.name) LIKE 'harlequin crest%' THEN 1")
+	assert.Contains(t, sql, "similarity(LOWER(l.name), 'harlequin crest') DESC")
+	assert.Contains(t, sql, "l.created_at DESC")
+}
+
+func TestApplySorting_RelevanceWithoutQueryFallsBack(t *testing.T) {
+	sql := sortSQL(t, ListingFilter{SortBy: SortByRelevance})
+
+	assert.NotContains(t, sql, "similarity")
+	assert.Contains(t, sql, "l.created_at DESC")
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func intPtr(v int) *int { return &v }
+
+func TestApplyFilters_ExcludeIDsAndBaseItemName(t *testing.T) {
+	sql := filterSQL(t, ListingFilter{ExcludeIDs: []string{"a", "b"}, BaseItemName: "Shako"})
+
+	assert.Contains(t, sql, "l.id NOT IN ('a', 'b')")
+	assert.Contains(t, sql, "LOWER(l.base_item_name) = LOWER('Shako')")
+}
+
+func TestBrowsable_HidesListingsHeldForATrade(t *testing.T) {
+	sqldb := sql.OpenDB(pgdriver.NewConnector())
+	t.Cleanup(func() { _ = sqldb.Close() })
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	sql := browsable(db.NewSelect().Model((*models.Listing)(nil))).String()
+
+	// A "pending" listing fails the status check, and one with an active trade the NOT EXISTS
+	assert.Contains(t, sql, "l.status = 'active'")
+	assert.Contains(t, sql, "NOT EXISTS (SELECT 1 FROM d2.trades t WHERE t.listing_id = l.id AND t.status = 'active')")
+}
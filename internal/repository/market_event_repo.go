@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type marketEventRepository struct {
+	db *database.BunDB
+}
+
+// NewMarketEventRepository creates a new market event repository
+func NewMarketEventRepository(db *database.BunDB) MarketEventRepository {
+	return &marketEventRepository{db: db}
+}
+
+func (r *marketEventRepository) Create(ctx context.Context, event *models.MarketEvent) error {
+	_, err := r.db.Conn(ctx).NewInsert().
+		Model(event).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create market event",
+			"error", err.Error(),
+			"transaction_id", event.TransactionID,
+		)
+	}
+	return err
+}
+
+func (r *marketEventRepository) ListByItem(ctx context.Context, game, itemName string, since time.Time) ([]*models.MarketEvent, error) {
+	var events []*models.MarketEvent
+	err := r.db.DB().NewSelect().
+		Model(&events).
+		Where("me.game = ?", game).
+		Where("LOWER(me.item_name) = LOWER(?)", itemName).
+		Where("me.occurred_at >= ?", since).
+		Order("me.occurred_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
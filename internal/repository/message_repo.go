@@ -68,6 +68,65 @@ func (r *messageRepository) GetByChatID(ctx context.Context, chatID string, offs
 	return messages, count, nil
 }
 
+// GetByChatIDBefore returns up to limit messages older than beforeMessageID (or the
+// latest messages when beforeMessageID is empty) in chronological order, plus whether
+// older messages remain. System messages are paged like any other message so the
+// cursor never skips or repeats entries.
+func (r *messageRepository) GetByChatIDBefore(ctx context.Context, chatID string, beforeMessageID string, limit int) ([]*models.Message, bool, error) {
+	var messages []*models.Message
+
+	query := r.db.DB().NewSelect().
+		Model(&messages).
+		Relation("Sender").
+		Where("m.chat_id = ?", chatID)
+
+	if beforeMessageID != "" {
+		cursor := new(models.Message)
+		err := r.db.DB().NewSelect().
+			Model(cursor).
+			Column("m.id", "m.created_at").
+			Where("m.id = ?", beforeMessageID).
+			Where("m.chat_id = ?", chatID).
+			Scan(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Debug("message cursor not found",
+				"chat_id", chatID,
+				"before_message_id", beforeMessageID,
+				"error", err.Error(),
+			)
+			return nil, false, err
+		}
+
+		// Tie-break on id so messages sharing a timestamp are not lost between pages
+		query = query.Where("(m.created_at, m.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	err := query.
+		Order("m.created_at DESC", "m.id DESC").
+		Limit(limit + 1).
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get messages before cursor",
+			"error", err.Error(),
+			"chat_id", chatID,
+			"before_message_id", beforeMessageID,
+		)
+		return nil, false, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	// Reverse into chronological order for display
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, hasMore, nil
+}
+
 func (r *messageRepository) MarkAsRead(ctx context.Context, messageIDs []string, userID string) error {
 	now := time.Now()
 	_, err := r.db.DB().NewUpdate().
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
@@ -21,6 +23,14 @@ func (m *MockProfileRepository) GetByID(ctx context.Context, id string) (*models
 	return args.Get(0).(*models.Profile), args.Error(1)
 }
 
+func (m *MockProfileRepository) GetByIDs(ctx context.Context, ids []string) ([]*models.Profile, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Profile), args.Error(1)
+}
+
 func (m *MockProfileRepository) GetByUsername(ctx context.Context, username string) (*models.Profile, error) {
 	args := m.Called(ctx, username)
 	if args.Get(0) == nil {
@@ -60,6 +70,32 @@ func (m *MockProfileRepository) UpdateLastActiveAt(ctx context.Context, userID s
 	return args.Error(0)
 }
 
+func (m *MockProfileRepository) MarkOnboarded(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProfileRepository) AdjustAbuseScore(ctx context.Context, userID string, delta int) (int, error) {
+	args := m.Called(ctx, userID, delta)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProfileRepository) RefreshResponseTime(ctx context.Context, userID string, since time.Time) (*int, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*int), args.Error(1)
+}
+
+func (m *MockProfileRepository) ListIDsByAudience(ctx context.Context, audience string, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, audience, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // MockListingRepository is a mock implementation of repository.ListingRepository
 type MockListingRepository struct {
 	mock.Mock
@@ -117,16 +153,41 @@ func (m *MockListingRepository) CountByListingID(ctx context.Context, listingID
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockListingRepository) CountPendingOffers(ctx context.Context, listingID string) (int, error) {
+	args := m.Called(ctx, listingID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockListingRepository) ExistsActiveByFingerprint(ctx context.Context, sellerID, fingerprint string) (string, bool, error) {
+	args := m.Called(ctx, sellerID, fingerprint)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (m *MockListingRepository) HasPendingOfferFrom(ctx context.Context, listingID, requesterID string) (bool, error) {
+	args := m.Called(ctx, listingID, requesterID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockListingRepository) CountActiveBySellerID(ctx context.Context, sellerID string) (int, error) {
 	args := m.Called(ctx, sellerID)
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockListingRepository) CountChurnedBySeller(ctx context.Context, sellerID string, since time.Time, maxAge time.Duration) (int, error) {
+	args := m.Called(ctx, sellerID, since, maxAge)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockListingRepository) IncrementViews(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockListingRepository) AddViews(ctx context.Context, id string, n int64) error {
+	args := m.Called(ctx, id, n)
+	return args.Error(0)
+}
+
 func (m *MockListingRepository) CountActive(ctx context.Context) (int, error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Error(1)
@@ -137,6 +198,40 @@ func (m *MockListingRepository) CancelOldestActiveListings(ctx context.Context,
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockListingRepository) CapExpiry(ctx context.Context, sellerID string, lifetime time.Duration, now time.Time) (int, error) {
+	args := m.Called(ctx, sellerID, lifetime, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockListingRepository) ListExpiringUnwarned(ctx context.Context, before time.Time, afterID string, limit int) ([]*models.Listing, error) {
+	args := m.Called(ctx, before, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Listing), args.Error(1)
+}
+
+func (m *MockListingRepository) MarkExpiryWarned(ctx context.Context, ids []string, now time.Time) error {
+	args := m.Called(ctx, ids, now)
+	return args.Error(0)
+}
+
+func (m *MockListingRepository) ExpireStale(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockListingRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // MockStatsRepository is a mock implementation of repository.StatsRepository
 type MockStatsRepository struct {
 	mock.Mock
@@ -189,6 +284,30 @@ func (m *MockOfferRepository) List(ctx context.Context, filter repository.OfferF
 	return args.Get(0).([]*models.Offer), args.Int(1), args.Error(2)
 }
 
+func (m *MockOfferRepository) ListBySellerListings(ctx context.Context, sellerID, status string, offset, limit int) ([]*models.Offer, int, error) {
+	args := m.Called(ctx, sellerID, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Offer), args.Int(1), args.Error(2)
+}
+
+func (m *MockOfferRepository) RejectPendingForListing(ctx context.Context, listingID, exceptOfferID, note string, at time.Time) ([]*models.Offer, error) {
+	args := m.Called(ctx, listingID, exceptOfferID, note, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Offer), args.Error(1)
+}
+
+func (m *MockOfferRepository) ListAfter(ctx context.Context, filter repository.OfferFilter, after *repository.PageCursor) ([]*models.Offer, bool, error) {
+	args := m.Called(ctx, filter, after)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Offer), args.Bool(1), args.Error(2)
+}
+
 func (m *MockOfferRepository) GetDeclineReasons(ctx context.Context) ([]*models.DeclineReason, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -244,6 +363,24 @@ func (m *MockTradeRepository) Update(ctx context.Context, trade *models.Trade) e
 	return args.Error(0)
 }
 
+// UpdateLocked returns the mocked trade, or delegates to a function of the same
+// signature when one is given to Return so tests can simulate the row lock
+func (m *MockTradeRepository) UpdateLocked(ctx context.Context, id string, fn func(trade *models.Trade) error) (*models.Trade, error) {
+	args := m.Called(ctx, id, fn)
+	if impl, ok := args.Get(0).(func(context.Context, string, func(*models.Trade) error) (*models.Trade, error)); ok {
+		return impl(ctx, id, fn)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Trade), args.Error(1)
+}
+
+func (m *MockTradeRepository) UpdateConfirmedItems(ctx context.Context, trade *models.Trade, role string) error {
+	args := m.Called(ctx, trade, role)
+	return args.Error(0)
+}
+
 func (m *MockTradeRepository) List(ctx context.Context, filter repository.TradeFilter) ([]*models.Trade, int, error) {
 	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
@@ -257,6 +394,14 @@ func (m *MockTradeRepository) HasActiveTradeForListing(ctx context.Context, list
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockTradeRepository) ListCompletedSince(ctx context.Context, since time.Time) ([]*models.Trade, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Trade), args.Error(1)
+}
+
 // MockServiceRepository is a mock implementation of repository.ServiceRepository
 type MockServiceRepository struct {
 	mock.Mock
@@ -322,6 +467,11 @@ func (m *MockServiceRepository) ExistsByProviderAndType(ctx context.Context, pro
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockServiceRepository) CountActiveByProviderID(ctx context.Context, providerID string) (int, error) {
+	args := m.Called(ctx, providerID)
+	return args.Int(0), args.Error(1)
+}
+
 // MockServiceRunRepository is a mock implementation of repository.ServiceRunRepository
 type MockServiceRunRepository struct {
 	mock.Mock
@@ -403,11 +553,37 @@ func (m *MockChatRepository) GetByServiceRunID(ctx context.Context, serviceRunID
 	return args.Get(0).(*models.Chat), args.Error(1)
 }
 
+func (m *MockChatRepository) GetByOfferID(ctx context.Context, offerID string) (*models.Chat, error) {
+	args := m.Called(ctx, offerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Chat), args.Error(1)
+}
+
 func (m *MockChatRepository) Update(ctx context.Context, chat *models.Chat) error {
 	args := m.Called(ctx, chat)
 	return args.Error(0)
 }
 
+func (m *MockChatRepository) ListByParticipant(ctx context.Context, userID string, archived bool, offset, limit int) ([]*models.Chat, int, error) {
+	args := m.Called(ctx, userID, archived, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Chat), args.Int(1), args.Error(2)
+}
+
+func (m *MockChatRepository) ArchiveByTradeID(ctx context.Context, tradeID string, archivedAt time.Time) error {
+	args := m.Called(ctx, tradeID, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockChatRepository) ArchiveByServiceRunID(ctx context.Context, serviceRunID string, archivedAt time.Time) error {
+	args := m.Called(ctx, serviceRunID, archivedAt)
+	return args.Error(0)
+}
+
 // MockMessageRepository is a mock implementation of repository.MessageRepository
 type MockMessageRepository struct {
 	mock.Mock
@@ -426,6 +602,14 @@ func (m *MockMessageRepository) GetByChatID(ctx context.Context, chatID string,
 	return args.Get(0).([]*models.Message), args.Int(1), args.Error(2)
 }
 
+func (m *MockMessageRepository) GetByChatIDBefore(ctx context.Context, chatID string, beforeMessageID string, limit int) ([]*models.Message, bool, error) {
+	args := m.Called(ctx, chatID, beforeMessageID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Message), args.Bool(1), args.Error(2)
+}
+
 func (m *MockMessageRepository) MarkAsRead(ctx context.Context, messageIDs []string, userID string) error {
 	args := m.Called(ctx, messageIDs, userID)
 	return args.Error(0)
@@ -451,6 +635,11 @@ func (m *MockNotificationRepository) Create(ctx context.Context, notification *m
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) CreateBatch(ctx context.Context, notifications []*models.Notification) error {
+	args := m.Called(ctx, notifications)
+	return args.Error(0)
+}
+
 func (m *MockNotificationRepository) GetByUserID(ctx context.Context, userID string, unreadOnly bool, notificationType string, offset, limit int) ([]*models.Notification, int, error) {
 	args := m.Called(ctx, userID, unreadOnly, notificationType, offset, limit)
 	if args.Get(0) == nil {
@@ -459,6 +648,14 @@ func (m *MockNotificationRepository) GetByUserID(ctx context.Context, userID str
 	return args.Get(0).([]*models.Notification), args.Int(1), args.Error(2)
 }
 
+func (m *MockNotificationRepository) GetByUserIDAfter(ctx context.Context, userID string, unreadOnly bool, notificationType string, after *repository.PageCursor, limit int) ([]*models.Notification, bool, error) {
+	args := m.Called(ctx, userID, unreadOnly, notificationType, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Notification), args.Bool(1), args.Error(2)
+}
+
 func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
 	args := m.Called(ctx, userID)
 	return args.Int(0), args.Error(1)
@@ -469,6 +666,16 @@ func (m *MockNotificationRepository) MarkAsRead(ctx context.Context, notificatio
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) MarkReadByReference(ctx context.Context, userID, referenceType, referenceID string) (int, error) {
+	args := m.Called(ctx, userID, referenceType, referenceID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SummarizeUnread(ctx context.Context, id, userID, body string) (bool, error) {
+	args := m.Called(ctx, id, userID, body)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockTransactionRepository is a mock implementation of repository.TransactionRepository
 type MockTransactionRepository struct {
 	mock.Mock
@@ -503,8 +710,8 @@ func (m *MockTransactionRepository) GetByServiceRunID(ctx context.Context, servi
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetPriceHistory(ctx context.Context, itemName string, days int) ([]repository.PriceHistoryRecord, error) {
-	args := m.Called(ctx, itemName, days)
+func (m *MockTransactionRepository) GetPriceHistory(ctx context.Context, itemName string, game string, days int) ([]repository.PriceHistoryRecord, error) {
+	args := m.Called(ctx, itemName, game, days)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -519,6 +726,32 @@ func (m *MockTransactionRepository) GetSalesBySeller(ctx context.Context, seller
 	return args.Get(0).([]repository.SaleRecord), args.Int(1), args.Error(2)
 }
 
+func (m *MockTransactionRepository) GetSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time, offset, limit int) ([]repository.SaleRecord, error) {
+	args := m.Called(ctx, sellerID, from, to, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.SaleRecord), args.Error(1)
+}
+
+func (m *MockTransactionRepository) CountSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, sellerID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ListTradeTransactionsSince(ctx context.Context, since time.Time) ([]*models.Transaction, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) HasRecentSale(ctx context.Context, sellerID, itemName string, itemDetails json.RawMessage, since time.Time) (bool, error) {
+	args := m.Called(ctx, sellerID, itemName, itemDetails, since)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockWishlistRepository is a mock implementation of repository.WishlistRepository
 type MockWishlistRepository struct {
 	mock.Mock
@@ -573,6 +806,258 @@ func (m *MockWishlistRepository) FindMatchingItems(ctx context.Context, listing
 	return args.Get(0).([]*models.WishlistItem), args.Error(1)
 }
 
+// MockDelegateRepository is a mock implementation of repository.DelegateRepository
+type MockDelegateRepository struct {
+	mock.Mock
+}
+
+func (m *MockDelegateRepository) GetByOwnerAndDelegate(ctx context.Context, ownerID, delegateID string) (*models.Delegate, error) {
+	args := m.Called(ctx, ownerID, delegateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Delegate), args.Error(1)
+}
+
+func (m *MockDelegateRepository) ListByOwnerID(ctx context.Context, ownerID string) ([]*models.Delegate, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Delegate), args.Error(1)
+}
+
+func (m *MockDelegateRepository) Upsert(ctx context.Context, delegate *models.Delegate) error {
+	args := m.Called(ctx, delegate)
+	return args.Error(0)
+}
+
+func (m *MockDelegateRepository) Delete(ctx context.Context, ownerID, delegateID string) error {
+	args := m.Called(ctx, ownerID, delegateID)
+	return args.Error(0)
+}
+
+// MockDeclineTemplateRepository is a mock implementation of repository.DeclineTemplateRepository
+type MockDeclineTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeclineTemplateRepository) Create(ctx context.Context, template *models.DeclineTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockDeclineTemplateRepository) GetByID(ctx context.Context, id string) (*models.DeclineTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeclineTemplate), args.Error(1)
+}
+
+func (m *MockDeclineTemplateRepository) Update(ctx context.Context, template *models.DeclineTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockDeclineTemplateRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDeclineTemplateRepository) ListBySellerID(ctx context.Context, sellerID string) ([]*models.DeclineTemplate, error) {
+	args := m.Called(ctx, sellerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DeclineTemplate), args.Error(1)
+}
+
+func (m *MockDeclineTemplateRepository) CountBySellerID(ctx context.Context, sellerID string) (int, error) {
+	args := m.Called(ctx, sellerID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockOfferTemplateRepository is a mock implementation of repository.OfferTemplateRepository
+type MockOfferTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOfferTemplateRepository) Create(ctx context.Context, template *models.OfferTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockOfferTemplateRepository) GetByID(ctx context.Context, id string) (*models.OfferTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OfferTemplate), args.Error(1)
+}
+
+func (m *MockOfferTemplateRepository) Update(ctx context.Context, template *models.OfferTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockOfferTemplateRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOfferTemplateRepository) ListByUserID(ctx context.Context, userID string) ([]*models.OfferTemplate, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OfferTemplate), args.Error(1)
+}
+
+func (m *MockOfferTemplateRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockAPITokenRepository is a mock implementation of repository.APITokenRepository
+type MockAPITokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) GetByID(ctx context.Context, id string) (*models.APIToken, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) GetByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) ListByUserID(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) CountActiveByUserID(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAPITokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// MockFavoriteRepository is a mock implementation of repository.FavoriteRepository
+type MockFavoriteRepository struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	args := m.Called(ctx, favorite)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) Remove(ctx context.Context, userID, listingID string) error {
+	args := m.Called(ctx, userID, listingID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) ListListings(ctx context.Context, userID string, offset, limit int) ([]*models.Listing, int, error) {
+	args := m.Called(ctx, userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Listing), args.Int(1), args.Error(2)
+}
+
+// MockNotificationPreferencesRepository is a mock implementation of repository.NotificationPreferencesRepository
+type MockNotificationPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferencesRepository) Upsert(ctx context.Context, prefs *models.NotificationPreferences) error {
+	args := m.Called(ctx, prefs)
+	return args.Error(0)
+}
+
+// MockWatchRepository is a mock implementation of repository.WatchRepository
+type MockWatchRepository struct {
+	mock.Mock
+}
+
+func (m *MockWatchRepository) Create(ctx context.Context, watch *models.ItemWatch) error {
+	args := m.Called(ctx, watch)
+	return args.Error(0)
+}
+
+func (m *MockWatchRepository) GetByID(ctx context.Context, id string) (*models.ItemWatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ItemWatch), args.Error(1)
+}
+
+func (m *MockWatchRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWatchRepository) ListByUserID(ctx context.Context, userID string) ([]*models.ItemWatch, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ItemWatch), args.Error(1)
+}
+
+func (m *MockWatchRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWatchRepository) Exists(ctx context.Context, userID, itemName, game string) (bool, error) {
+	args := m.Called(ctx, userID, itemName, game)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWatchRepository) FindWatchersForListing(ctx context.Context, listing *models.Listing) ([]*models.ItemWatch, error) {
+	args := m.Called(ctx, listing)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ItemWatch), args.Error(1)
+}
+
 // MockBugReportRepository is a mock implementation of repository.BugReportRepository
 type MockBugReportRepository struct {
 	mock.Mock
@@ -604,6 +1089,45 @@ func (m *MockBugReportRepository) List(ctx context.Context, status string, offse
 	return args.Get(0).([]*models.BugReport), args.Int(1), args.Error(2)
 }
 
+// MockDisputeRepository is a mock implementation of repository.DisputeRepository
+type MockDisputeRepository struct {
+	mock.Mock
+}
+
+func (m *MockDisputeRepository) Create(ctx context.Context, dispute *models.Dispute) error {
+	args := m.Called(ctx, dispute)
+	return args.Error(0)
+}
+
+func (m *MockDisputeRepository) GetByID(ctx context.Context, id string) (*models.Dispute, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Dispute), args.Error(1)
+}
+
+func (m *MockDisputeRepository) GetOpenByTarget(ctx context.Context, targetType, targetID string) (*models.Dispute, error) {
+	args := m.Called(ctx, targetType, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Dispute), args.Error(1)
+}
+
+func (m *MockDisputeRepository) Update(ctx context.Context, dispute *models.Dispute) error {
+	args := m.Called(ctx, dispute)
+	return args.Error(0)
+}
+
+func (m *MockDisputeRepository) List(ctx context.Context, status, targetType string, offset, limit int) ([]*models.Dispute, int, error) {
+	args := m.Called(ctx, status, targetType, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Dispute), args.Int(1), args.Error(2)
+}
+
 // MockRatingRepository is a mock implementation of repository.RatingRepository
 type MockRatingRepository struct {
 	mock.Mock
@@ -630,11 +1154,45 @@ func (m *MockRatingRepository) GetByUserID(ctx context.Context, userID string, o
 	return args.Get(0).([]*models.Rating), args.Int(1), args.Error(2)
 }
 
+func (m *MockRatingRepository) GetReceived(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	args := m.Called(ctx, userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Rating), args.Int(1), args.Error(2)
+}
+
+func (m *MockRatingRepository) GetGiven(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	args := m.Called(ctx, userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Rating), args.Int(1), args.Error(2)
+}
+
 func (m *MockRatingRepository) Exists(ctx context.Context, transactionID, raterID string) (bool, error) {
 	args := m.Called(ctx, transactionID, raterID)
 	return args.Bool(0), args.Error(1)
 }
 
+// MockMarketEventRepository is a mock implementation of repository.MarketEventRepository
+type MockMarketEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockMarketEventRepository) Create(ctx context.Context, event *models.MarketEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockMarketEventRepository) ListByItem(ctx context.Context, game, itemName string, since time.Time) ([]*models.MarketEvent, error) {
+	args := m.Called(ctx, game, itemName, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MarketEvent), args.Error(1)
+}
+
 // MockBillingEventRepository is a mock implementation of repository.BillingEventRepository
 type MockBillingEventRepository struct {
 	mock.Mock
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
@@ -18,7 +19,7 @@ func NewTradeRepositoryNew(db *database.BunDB) TradeRepository {
 }
 
 func (r *tradeRepositoryNew) Create(ctx context.Context, trade *models.Trade) error {
-	_, err := r.db.DB().NewInsert().
+	_, err := r.db.Conn(ctx).NewInsert().
 		Model(trade).
 		Exec(ctx)
 	if err != nil {
@@ -88,6 +89,55 @@ func (r *tradeRepositoryNew) Update(ctx context.Context, trade *models.Trade) er
 	return err
 }
 
+// UpdateLocked re-reads the trade with SELECT ... FOR UPDATE inside a transaction,
+// lets fn re-validate and change it, and saves it before the row lock is released.
+// Concurrent callers run one after the other, each seeing the previous one's write.
+// Inside a RunInTx the lock is held until the caller's transaction commits.
+// An error from fn rolls the transaction back and is returned as-is.
+func (r *tradeRepositoryNew) UpdateLocked(ctx context.Context, id string, fn func(trade *models.Trade) error) (*models.Trade, error) {
+	trade := new(models.Trade)
+	err := r.db.RunInTx(ctx, func(ctx context.Context) error {
+		tx := r.db.Conn(ctx)
+		if err := tx.NewSelect().
+			Model(trade).
+			Where("t.id = ?", id).
+			For("UPDATE").
+			Scan(ctx); err != nil {
+			return err
+		}
+		if err := fn(trade); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().
+			Model(trade).
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// UpdateConfirmedItems writes only the given side's ("seller" or "buyer") item
+// checklist, so both parties can tick items at the same time
+func (r *tradeRepositoryNew) UpdateConfirmedItems(ctx context.Context, trade *models.Trade, role string) error {
+	_, err := r.db.DB().NewUpdate().
+		Model(trade).
+		Column(role+"_confirmed_items", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update trade checklist",
+			"error", err.Error(),
+			"trade_id", trade.ID,
+			"role", role,
+		)
+	}
+	return err
+}
+
 func (r *tradeRepositoryNew) List(ctx context.Context, filter TradeFilter) ([]*models.Trade, int, error) {
 	var trades []*models.Trade
 
@@ -101,9 +151,20 @@ func (r *tradeRepositoryNew) List(ctx context.Context, filter TradeFilter) ([]*m
 		Relation("Buyer").
 		Relation("Chat")
 
-	// Filter by user (must be seller or buyer)
+	// Filter by user (must be seller or buyer), narrowed by role
 	if filter.UserID != "" {
-		query = query.Where("t.seller_id = ? OR t.buyer_id = ?", filter.UserID, filter.UserID)
+		switch filter.Role {
+		case "buyer":
+			query = query.Where("t.buyer_id = ?", filter.UserID)
+		case "seller":
+			query = query.Where("t.seller_id = ?", filter.UserID)
+		default:
+			query = query.Where("t.seller_id = ? OR t.buyer_id = ?", filter.UserID, filter.UserID)
+		}
+	}
+
+	if filter.CounterpartyID != "" {
+		query = query.Where("t.seller_id = ? OR t.buyer_id = ?", filter.CounterpartyID, filter.CounterpartyID)
 	}
 
 	if filter.Status != "" {
@@ -138,7 +199,7 @@ func (r *tradeRepositoryNew) List(ctx context.Context, filter TradeFilter) ([]*m
 }
 
 func (r *tradeRepositoryNew) HasActiveTradeForListing(ctx context.Context, listingID string) (bool, error) {
-	count, err := r.db.DB().NewSelect().
+	count, err := r.db.Conn(ctx).NewSelect().
 		Model((*models.Trade)(nil)).
 		Where("listing_id = ?", listingID).
 		Where("status = ?", "active").
@@ -152,3 +213,22 @@ func (r *tradeRepositoryNew) HasActiveTradeForListing(ctx context.Context, listi
 	}
 	return count > 0, nil
 }
+
+func (r *tradeRepositoryNew) ListCompletedSince(ctx context.Context, since time.Time) ([]*models.Trade, error) {
+	var trades []*models.Trade
+	err := r.db.DB().NewSelect().
+		Model(&trades).
+		Relation("Offer").
+		Relation("Listing").
+		Where("t.status = ?", "completed").
+		Where("t.completed_at >= ?", since).
+		Order("t.completed_at ASC").
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list completed trades",
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	return trades, nil
+}
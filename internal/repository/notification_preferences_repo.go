@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type notificationPreferencesRepository struct {
+	db *database.BunDB
+}
+
+// NewNotificationPreferencesRepository creates a new notification preferences repository
+func NewNotificationPreferencesRepository(db *database.BunDB) NotificationPreferencesRepository {
+	return &notificationPreferencesRepository{db: db}
+}
+
+// GetByUserID returns the user's preferences, or sql.ErrNoRows if they never changed any
+func (r *notificationPreferencesRepository) GetByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	prefs := new(models.NotificationPreferences)
+	err := r.db.DB().NewSelect().
+		Model(prefs).
+		Where("np.user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// Upsert stores the user's preferences, replacing any previous ones
+func (r *notificationPreferencesRepository) Upsert(ctx context.Context, prefs *models.NotificationPreferences) error {
+	_, err := r.db.DB().NewInsert().
+		Model(prefs).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("types = EXCLUDED.types").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to save notification preferences",
+			"error", err.Error(),
+			"user_id", prefs.UserID,
+		)
+	}
+	return err
+}
@@ -33,20 +33,26 @@ func (r *notificationRepository) Create(ctx context.Context, notification *model
 	return err
 }
 
-func (r *notificationRepository) GetByUserID(ctx context.Context, userID string, unreadOnly bool, notificationType string, offset, limit int) ([]*models.Notification, int, error) {
-	var notifications []*models.Notification
-
-	query := r.db.DB().NewSelect().
+func (r *notificationRepository) CreateBatch(ctx context.Context, notifications []*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	_, err := r.db.DB().NewInsert().
 		Model(&notifications).
-		Where("n.user_id = ?", userID)
-
-	if unreadOnly {
-		query = query.Where("n.read = ?", false)
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create notification batch",
+			"error", err.Error(),
+			"count", len(notifications),
+		)
 	}
+	return err
+}
 
-	if notificationType != "" {
-		query = query.Where("n.type = ?", notificationType)
-	}
+func (r *notificationRepository) GetByUserID(ctx context.Context, userID string, unreadOnly bool, notificationType string, offset, limit int) ([]*models.Notification, int, error) {
+	var notifications []*models.Notification
+
+	query := r.filterByUser(r.db.DB().NewSelect().Model(&notifications), userID, unreadOnly, notificationType)
 
 	count, err := query.Count(ctx)
 	if err != nil {
@@ -75,6 +81,52 @@ func (r *notificationRepository) GetByUserID(ctx context.Context, userID string,
 	return notifications, count, nil
 }
 
+// GetByUserIDAfter returns up to limit notifications older than after (or the newest
+// when after is nil), newest first, plus whether more remain
+func (r *notificationRepository) GetByUserIDAfter(ctx context.Context, userID string, unreadOnly bool, notificationType string, after *PageCursor, limit int) ([]*models.Notification, bool, error) {
+	var notifications []*models.Notification
+
+	query := r.filterByUser(r.db.DB().NewSelect().Model(&notifications), userID, unreadOnly, notificationType)
+
+	if after != nil {
+		// Tie-break on id so notifications sharing a timestamp are not lost between pages
+		query = query.Where("(n.created_at, n.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	err := query.
+		Order("n.created_at DESC", "n.id DESC").
+		Limit(limit + 1).
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get notifications after cursor",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return nil, false, err
+	}
+
+	hasMore := len(notifications) > limit
+	if hasMore {
+		notifications = notifications[:limit]
+	}
+	return notifications, hasMore, nil
+}
+
+// filterByUser narrows a notification query to a user's notifications
+func (r *notificationRepository) filterByUser(query *bun.SelectQuery, userID string, unreadOnly bool, notificationType string) *bun.SelectQuery {
+	query = query.Where("n.user_id = ?", userID)
+
+	if unreadOnly {
+		query = query.Where("n.read = ?", false)
+	}
+
+	if notificationType != "" {
+		query = query.Where("n.type = ?", notificationType)
+	}
+
+	return query
+}
+
 func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
 	count, err := r.db.DB().NewSelect().
 		Model((*models.Notification)(nil)).
@@ -108,3 +160,63 @@ func (r *notificationRepository) MarkAsRead(ctx context.Context, notificationIDs
 	}
 	return err
 }
+
+// SummarizeUnread replaces an unread notification's body with a summary and drops its
+// single-entity reference and metadata. Returns false if the notification is gone or
+// already read.
+func (r *notificationRepository) SummarizeUnread(ctx context.Context, id, userID, body string) (bool, error) {
+	res, err := summarizeUnread(r.db.DB().NewUpdate(), id, userID, body).Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to summarize notification",
+			"error", err.Error(),
+			"notification_id", id,
+			"user_id", userID,
+		)
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// summarizeUnread builds the update behind SummarizeUnread. The reference and metadata
+// described one entity, so neither is kept once the notification stands for several;
+// metadata goes back to the column default, as a notification inserted without any.
+func summarizeUnread(query *bun.UpdateQuery, id, userID, body string) *bun.UpdateQuery {
+	return query.
+		Model((*models.Notification)(nil)).
+		Set("body = ?", body).
+		Set("reference_id = NULL").
+		Set("metadata = '{}'").
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Where("read = ?", false)
+}
+
+func (r *notificationRepository) MarkReadByReference(ctx context.Context, userID, referenceType, referenceID string) (int, error) {
+	res, err := r.db.DB().NewUpdate().
+		Model((*models.Notification)(nil)).
+		Set("read = ?", true).
+		Set("read_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("reference_type = ?", referenceType).
+		Where("reference_id = ?", referenceID).
+		Where("read = ?", false).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to mark notifications as read by reference",
+			"error", err.Error(),
+			"user_id", userID,
+			"reference_type", referenceType,
+			"reference_id", referenceID,
+		)
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestSummarizeUnread_ClearsReferenceAndMetadata(t *testing.T) {
+	sqldb := sql.OpenDB(pgdriver.NewConnector())
+	t.Cleanup(func() { _ = sqldb.Close() })
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	sql := summarizeUnread(db.NewUpdate(), "notif-1", "user-1", "2 new messages").String()
+
+	assert.Contains(t, sql, "body = '2 new messages'")
+	assert.Contains(t, sql, "reference_id = NULL")
+	assert.Contains(t, sql, "metadata = '{}'")
+	assert.Contains(t, sql, "read = FALSE")
+}
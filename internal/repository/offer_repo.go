@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/uptrace/bun"
 )
 
 type offerRepository struct {
@@ -17,8 +19,9 @@ func NewOfferRepository(db *database.BunDB) OfferRepository {
 	return &offerRepository{db: db}
 }
 
+// Create inserts the offer. It joins the transaction on ctx, if any.
 func (r *offerRepository) Create(ctx context.Context, offer *models.Offer) error {
-	_, err := r.db.DB().NewInsert().
+	_, err := r.db.Conn(ctx).NewInsert().
 		Model(offer).
 		Exec(ctx)
 	if err != nil {
@@ -63,7 +66,7 @@ func (r *offerRepository) GetByIDWithRelations(ctx context.Context, id string) (
 }
 
 func (r *offerRepository) Update(ctx context.Context, offer *models.Offer) error {
-	_, err := r.db.DB().NewUpdate().
+	_, err := r.db.Conn(ctx).NewUpdate().
 		Model(offer).
 		WherePK().
 		Exec(ctx)
@@ -90,6 +93,168 @@ func (r *offerRepository) List(ctx context.Context, filter OfferFilter) ([]*mode
 		Relation("Trade").
 		Relation("ServiceRun")
 
+	query = r.applyFilter(query, filter)
+
+	count, err := query.Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count offers",
+			"error", err.Error(),
+			"user_id", filter.UserID,
+		)
+		return nil, 0, err
+	}
+
+	query = query.Order("o.created_at DESC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	err = query.Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list offers",
+			"error", err.Error(),
+			"user_id", filter.UserID,
+		)
+		return nil, 0, err
+	}
+
+	return offers, count, nil
+}
+
+// ListBySellerListings returns a page of the seller's listings that have item offers in
+// the given status, as those offers with each listing joined in, grouped by listing
+// (newest listing first) and oldest offer first within a listing. Cancelled listings are
+// left out, and deleted ones drop out of the join. The count is the number of listings.
+func (r *offerRepository) ListBySellerListings(ctx context.Context, sellerID, status string, offset, limit int) ([]*models.Offer, int, error) {
+	listings := sellerListingsWithOffers(r.db.DB().NewSelect(), sellerID, status)
+
+	count, err := listings.Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count seller listings with offers",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return nil, 0, err
+	}
+
+	var listingIDs []string
+	err = listings.
+		Column("l.id").
+		OrderExpr("l.created_at DESC, l.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx, &listingIDs)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list seller listings with offers",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return nil, 0, err
+	}
+	if len(listingIDs) == 0 {
+		return []*models.Offer{}, count, nil
+	}
+
+	var offers []*models.Offer
+	err = r.db.DB().NewSelect().
+		Model(&offers).
+		Relation("Listing").
+		Relation("Requester").
+		Where("o.type = ?", "item").
+		Where("o.listing_id IN (?)", bun.In(listingIDs)).
+		Where("o.status = ?", status).
+		OrderExpr(`"listing"."created_at" DESC, "listing"."id", o.created_at ASC`).
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list offers by seller listings",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return nil, 0, err
+	}
+	return offers, count, nil
+}
+
+// sellerListingsWithOffers selects the seller's listings, other than cancelled ones,
+// that have item offers in status
+func sellerListingsWithOffers(query *bun.SelectQuery, sellerID, status string) *bun.SelectQuery {
+	return query.
+		Model((*models.Listing)(nil)).
+		Where("l.seller_id = ?", sellerID).
+		Where("l.status != ?", "cancelled").
+		Where("EXISTS (SELECT 1 FROM d2.offers o WHERE o.listing_id = l.id AND o.type = 'item' AND o.status = ?)", status)
+}
+
+// RejectPendingForListing rejects every pending offer on the listing except exceptOfferID
+// with the given decline note and returns the rejected offers' IDs and requesters. It
+// joins the transaction on ctx, if any.
+func (r *offerRepository) RejectPendingForListing(ctx context.Context, listingID, exceptOfferID, note string, at time.Time) ([]*models.Offer, error) {
+	var offers []*models.Offer
+	_, err := r.db.Conn(ctx).NewUpdate().
+		Model((*models.Offer)(nil)).
+		Set("status = ?", "rejected").
+		Set("decline_note = ?", note).
+		Set("updated_at = ?", at).
+		Where("listing_id = ?", listingID).
+		Where("status = ?", "pending").
+		Where("id <> ?", exceptOfferID).
+		Returning("id, requester_id").
+		Exec(ctx, &offers)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to reject pending offers for listing",
+			"error", err.Error(),
+			"listing_id", listingID,
+		)
+		return nil, err
+	}
+	return offers, nil
+}
+
+// ListAfter returns up to filter.Limit offers older than after (or the newest offers
+// when after is nil), newest first, plus whether more remain. Offset is ignored.
+func (r *offerRepository) ListAfter(ctx context.Context, filter OfferFilter, after *PageCursor) ([]*models.Offer, bool, error) {
+	var offers []*models.Offer
+
+	query := r.db.DB().NewSelect().
+		Model(&offers).
+		Relation("Listing").
+		Relation("Listing.Seller").
+		Relation("Service").
+		Relation("Service.Provider").
+		Relation("Requester").
+		Relation("DeclineReason").
+		Relation("Trade").
+		Relation("ServiceRun")
+
+	query = r.applyFilter(query, filter)
+
+	if after != nil {
+		// Tie-break on id so offers sharing a timestamp are not lost between pages
+		query = query.Where("(o.created_at, o.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	err := query.
+		Order("o.created_at DESC", "o.id DESC").
+		Limit(filter.Limit + 1).
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list offers after cursor",
+			"error", err.Error(),
+			"user_id", filter.UserID,
+		)
+		return nil, false, err
+	}
+
+	hasMore := len(offers) > filter.Limit
+	if hasMore {
+		offers = offers[:filter.Limit]
+	}
+	return offers, hasMore, nil
+}
+
+// applyFilter narrows an offer query to the filter's type, owner and status
+func (r *offerRepository) applyFilter(query *bun.SelectQuery, filter OfferFilter) *bun.SelectQuery {
 	// Filter by offer type
 	switch filter.Type {
 	case "item":
@@ -134,31 +299,7 @@ func (r *offerRepository) List(ctx context.Context, filter OfferFilter) ([]*mode
 		query = query.Where("o.status = ?", filter.Status)
 	}
 
-	count, err := query.Count(ctx)
-	if err != nil {
-		logger.FromContext(ctx).Error("failed to count offers",
-			"error", err.Error(),
-			"user_id", filter.UserID,
-		)
-		return nil, 0, err
-	}
-
-	query = query.Order("o.created_at DESC")
-
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit).Offset(filter.Offset)
-	}
-
-	err = query.Scan(ctx)
-	if err != nil {
-		logger.FromContext(ctx).Error("failed to list offers",
-			"error", err.Error(),
-			"user_id", filter.UserID,
-		)
-		return nil, 0, err
-	}
-
-	return offers, count, nil
+	return query
 }
 
 func (r *offerRepository) GetDeclineReasons(ctx context.Context) ([]*models.DeclineReason, error) {
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestSellerListingsWithOffers_SkipsCancelledListings(t *testing.T) {
+	sqldb := sql.OpenDB(pgdriver.NewConnector())
+	t.Cleanup(func() { _ = sqldb.Close() })
+	db := bun.NewDB(sqldb, pgdialect.New())
+
+	sql := sellerListingsWithOffers(db.NewSelect(), "seller-1", "pending").String()
+
+	assert.Contains(t, sql, "l.seller_id = 'seller-1'")
+	assert.Contains(t, sql, "l.status != 'cancelled'")
+	assert.Contains(t, sql, "EXISTS (SELECT 1 FROM d2.offers o WHERE o.listing_id = l.id AND o.type = 'item' AND o.status = 'pending')")
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type offerTemplateRepository struct {
+	db *database.BunDB
+}
+
+// NewOfferTemplateRepository creates a new offer template repository
+func NewOfferTemplateRepository(db *database.BunDB) OfferTemplateRepository {
+	return &offerTemplateRepository{db: db}
+}
+
+func (r *offerTemplateRepository) Create(ctx context.Context, template *models.OfferTemplate) error {
+	_, err := r.db.DB().NewInsert().
+		Model(template).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create offer template",
+			"error", err.Error(),
+			"user_id", template.UserID,
+		)
+	}
+	return err
+}
+
+func (r *offerTemplateRepository) GetByID(ctx context.Context, id string) (*models.OfferTemplate, error) {
+	template := new(models.OfferTemplate)
+	err := r.db.DB().NewSelect().
+		Model(template).
+		Where("oft.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (r *offerTemplateRepository) Update(ctx context.Context, template *models.OfferTemplate) error {
+	template.UpdatedAt = time.Now()
+	_, err := r.db.DB().NewUpdate().
+		Model(template).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update offer template",
+			"error", err.Error(),
+			"offer_template_id", template.ID,
+		)
+	}
+	return err
+}
+
+func (r *offerTemplateRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.DB().NewDelete().
+		Model((*models.OfferTemplate)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to delete offer template",
+			"error", err.Error(),
+			"offer_template_id", id,
+		)
+	}
+	return err
+}
+
+func (r *offerTemplateRepository) ListByUserID(ctx context.Context, userID string) ([]*models.OfferTemplate, error) {
+	var templates []*models.OfferTemplate
+	err := r.db.DB().NewSelect().
+		Model(&templates).
+		Where("oft.user_id = ?", userID).
+		Order("oft.created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *offerTemplateRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.OfferTemplate)(nil)).
+		Where("user_id = ?", userID).
+		Count(ctx)
+}
@@ -2,13 +2,18 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/uptrace/bun"
 )
 
+// emailVerifiedExpr resolves the email verification flag from Supabase auth
+const emailVerifiedExpr = "EXISTS (SELECT 1 FROM auth.users u WHERE u.id = p.id AND u.email_confirmed_at IS NOT NULL) AS email_verified"
+
 type profileRepository struct {
 	db *database.BunDB
 }
@@ -22,7 +27,9 @@ func (r *profileRepository) GetByID(ctx context.Context, id string) (*models.Pro
 	profile := new(models.Profile)
 	err := r.db.DB().NewSelect().
 		Model(profile).
-		Where("id = ?", id).
+		ColumnExpr("p.*").
+		ColumnExpr(emailVerifiedExpr).
+		Where("p.id = ?", id).
 		Scan(ctx)
 	if err != nil {
 		logger.FromContext(ctx).Debug("profile not found or error",
@@ -34,11 +41,36 @@ func (r *profileRepository) GetByID(ctx context.Context, id string) (*models.Pro
 	return profile, nil
 }
 
+// GetByIDs loads the profiles with the given IDs in one query. Unknown IDs are
+// skipped, and the result is in no particular order.
+func (r *profileRepository) GetByIDs(ctx context.Context, ids []string) ([]*models.Profile, error) {
+	var profiles []*models.Profile
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+	err := r.db.DB().NewSelect().
+		Model(&profiles).
+		ColumnExpr("p.*").
+		ColumnExpr(emailVerifiedExpr).
+		Where("p.id IN (?)", bun.In(ids)).
+		Scan(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get profiles by ids",
+			"error", err.Error(),
+			"count", len(ids),
+		)
+		return nil, err
+	}
+	return profiles, nil
+}
+
 func (r *profileRepository) GetByUsername(ctx context.Context, username string) (*models.Profile, error) {
 	profile := new(models.Profile)
 	err := r.db.DB().NewSelect().
 		Model(profile).
-		Where("LOWER(username) = LOWER(?)", username).
+		ColumnExpr("p.*").
+		ColumnExpr(emailVerifiedExpr).
+		Where("LOWER(p.username) = LOWER(?)", username).
 		Scan(ctx)
 	if err != nil {
 		logger.FromContext(ctx).Debug("profile not found by username or error",
@@ -113,3 +145,115 @@ func (r *profileRepository) UpdateLastActiveAt(ctx context.Context, userID strin
 		Exec(ctx)
 	return err
 }
+
+// MarkOnboarded sets the onboarded flag, reporting whether this call flipped it.
+// Only one of several concurrent callers sees true.
+func (r *profileRepository) MarkOnboarded(ctx context.Context, userID string) (bool, error) {
+	res, err := r.db.DB().NewUpdate().
+		Model((*models.Profile)(nil)).
+		Set("onboarded = ?", true).
+		Where("id = ?", userID).
+		Where("onboarded = ?", false).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// AdjustAbuseScore atomically adds delta to a profile's abuse score, flooring it at
+// zero, and returns the new score
+func (r *profileRepository) AdjustAbuseScore(ctx context.Context, userID string, delta int) (int, error) {
+	var score int
+	err := r.db.DB().NewUpdate().
+		Model((*models.Profile)(nil)).
+		Set("abuse_score = GREATEST(abuse_score + ?, 0)", delta).
+		Where("id = ?", userID).
+		Returning("abuse_score").
+		Scan(ctx, &score)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to adjust abuse score",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return 0, err
+	}
+	return score, nil
+}
+
+// RefreshResponseTime recomputes the seller's median minutes from an offer on their
+// listings or services to their accept/reject/counter, over offers created since, and
+// stores it on the profile. Rejections and counters have no own timestamp, so updated_at
+// stands in. Counteroffers are answered by the buyer and don't count.
+// Returns nil when the seller has no answered offers in the window.
+func (r *profileRepository) RefreshResponseTime(ctx context.Context, userID string, since time.Time) (*int, error) {
+	var minutes sql.NullInt64
+	err := r.db.DB().NewUpdate().
+		Model((*models.Profile)(nil)).
+		Set(`response_time_minutes = (
+			SELECT ROUND(percentile_cont(0.5) WITHIN GROUP (
+				ORDER BY EXTRACT(EPOCH FROM (COALESCE(o.accepted_at, o.updated_at) - o.created_at)) / 60
+			))::int
+			FROM d2.offers o
+			LEFT JOIN d2.listings l ON l.id = o.listing_id
+			LEFT JOIN d2.services s ON s.id = o.service_id
+			WHERE COALESCE(l.seller_id, s.provider_id) = ?
+			AND o.status IN ('accepted', 'rejected', 'countered')
+			AND o.parent_offer_id IS NULL
+			AND o.created_at >= ?
+		)`, userID, since).
+		Where("id = ?", userID).
+		Returning("response_time_minutes").
+		Scan(ctx, &minutes)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to refresh response time",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return nil, err
+	}
+	if !minutes.Valid {
+		return nil, nil
+	}
+	m := int(minutes.Int64)
+	return &m, nil
+}
+
+// ListIDsByAudience returns profile IDs in the given announcement audience, ordered
+// by ID so callers can page through with afterID
+func (r *profileRepository) ListIDsByAudience(ctx context.Context, audience string, afterID string, limit int) ([]string, error) {
+	var ids []string
+	query := r.db.DB().NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("p.id")
+
+	switch audience {
+	case AudiencePremium:
+		query = query.Where("p.is_premium = ?", true)
+	case AudienceActiveSellers:
+		query = query.Where("EXISTS (SELECT 1 FROM d2.listings l WHERE l.seller_id = p.id AND l.status = ?)", "active")
+	case AudienceAdmins:
+		query = query.Where("p.is_admin = ?", true)
+	}
+
+	if afterID != "" {
+		query = query.Where("p.id > ?", afterID)
+	}
+
+	err := query.
+		Order("p.id ASC").
+		Limit(limit).
+		Scan(ctx, &ids)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to list profile ids by audience",
+			"error", err.Error(),
+			"audience", audience,
+		)
+		return nil, err
+	}
+	return ids, nil
+}
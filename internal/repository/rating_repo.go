@@ -44,20 +44,38 @@ func (r *ratingRepository) GetByTransactionID(ctx context.Context, transactionID
 	return ratings, nil
 }
 
+// GetByUserID returns the ratings a user received; same as GetReceived
 func (r *ratingRepository) GetByUserID(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	return r.GetReceived(ctx, userID, offset, limit)
+}
+
+// GetReceived returns ratings other users gave userID, with the rater loaded
+func (r *ratingRepository) GetReceived(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	return r.listByParticipant(ctx, "r.rated_id", "Rater", userID, offset, limit)
+}
+
+// GetGiven returns ratings userID gave other users, with the rated user loaded
+func (r *ratingRepository) GetGiven(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	return r.listByParticipant(ctx, "r.rater_id", "Rated", userID, offset, limit)
+}
+
+// listByParticipant pages ratings where column (a fixed column name, never user
+// input) matches userID, newest first, loading the counterparty and transaction
+func (r *ratingRepository) listByParticipant(ctx context.Context, column string, counterparty string, userID string, offset, limit int) ([]*models.Rating, int, error) {
 	var ratings []*models.Rating
 
 	query := r.db.DB().NewSelect().
 		Model(&ratings).
-		Relation("Rater").
+		Relation(counterparty).
 		Relation("Transaction").
-		Where("r.rated_id = ?", userID)
+		Where(column+" = ?", userID)
 
 	count, err := query.Count(ctx)
 	if err != nil {
 		logger.FromContext(ctx).Error("failed to count ratings",
 			"error", err.Error(),
-			"rated_id", userID,
+			"column", column,
+			"user_id", userID,
 		)
 		return nil, 0, err
 	}
@@ -72,7 +90,8 @@ func (r *ratingRepository) GetByUserID(ctx context.Context, userID string, offse
 	if err != nil {
 		logger.FromContext(ctx).Error("failed to get ratings",
 			"error", err.Error(),
-			"rated_id", userID,
+			"column", column,
+			"user_id", userID,
 		)
 		return nil, 0, err
 	}
@@ -248,3 +248,12 @@ func (r *serviceRepository) ExistsByProviderAndType(ctx context.Context, provide
 		Exists(ctx)
 	return exists, err
 }
+
+func (r *serviceRepository) CountActiveByProviderID(ctx context.Context, providerID string) (int, error) {
+	count, err := r.db.DB().NewSelect().
+		Model((*models.Service)(nil)).
+		Where("provider_id = ?", providerID).
+		Where("status = ?", "active").
+		Count(ctx)
+	return count, err
+}
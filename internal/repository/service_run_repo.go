@@ -18,7 +18,7 @@ func NewServiceRunRepository(db *database.BunDB) ServiceRunRepository {
 }
 
 func (r *serviceRunRepository) Create(ctx context.Context, serviceRun *models.ServiceRun) error {
-	_, err := r.db.DB().NewInsert().
+	_, err := r.db.Conn(ctx).NewInsert().
 		Model(serviceRun).
 		Exec(ctx)
 	if err != nil {
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/uptrace/bun"
 )
 
 type transactionRepository struct {
@@ -18,7 +21,7 @@ func NewTransactionRepository(db *database.BunDB) TransactionRepository {
 }
 
 func (r *transactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
-	_, err := r.db.DB().NewInsert().
+	_, err := r.db.Conn(ctx).NewInsert().
 		Model(transaction).
 		Exec(ctx)
 	if err != nil {
@@ -72,16 +75,24 @@ func (r *transactionRepository) GetByServiceRunID(ctx context.Context, serviceRu
 	return transaction, nil
 }
 
-func (r *transactionRepository) GetPriceHistory(ctx context.Context, itemName string, days int) ([]PriceHistoryRecord, error) {
+func (r *transactionRepository) GetPriceHistory(ctx context.Context, itemName string, game string, days int) ([]PriceHistoryRecord, error) {
 	var results []PriceHistoryRecord
-	err := r.db.DB().NewSelect().
+	query := r.db.DB().NewSelect().
 		ColumnExpr("DATE(tx.created_at) AS date").
 		ColumnExpr("tx.offered_items").
 		TableExpr("d2.transactions AS tx").
 		Join("INNER JOIN d2.trades AS t ON t.id = tx.trade_id").
-		Where("tx.item_name ILIKE ?", itemName).
+		Where("LOWER(tx.item_name) = LOWER(?)", itemName).
 		Where("tx.created_at >= NOW() - INTERVAL '1 day' * ?", days).
-		Where("t.status = ?", "completed").
+		Where("t.status = ?", "completed")
+
+	if game != "" {
+		query = query.
+			Join("INNER JOIN d2.listings AS l ON l.id = tx.listing_id").
+			Where("l.game = ?", game)
+	}
+
+	err := query.
 		OrderExpr("date ASC, tx.created_at ASC").
 		Scan(ctx, &results)
 	if err != nil {
@@ -106,7 +117,73 @@ func (r *transactionRepository) GetSalesBySeller(ctx context.Context, sellerID s
 
 	// Fetch sales with related data
 	var results []SaleRecord
-	err = r.db.DB().NewSelect().
+	err = r.saleRecordsQuery(sellerID).
+		Order("t.completed_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx, &results)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get sales",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return nil, 0, err
+	}
+
+	return results, count, nil
+}
+
+func (r *transactionRepository) GetSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time, offset, limit int) ([]SaleRecord, error) {
+	var results []SaleRecord
+	err := saleCompletedBetween(r.saleRecordsQuery(sellerID), from, to).
+		OrderExpr("COALESCE(t.completed_at, tx.created_at) ASC, tx.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx, &results)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get sales in range",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *transactionRepository) CountSalesBySellerBetween(ctx context.Context, sellerID string, from, to time.Time) (int, error) {
+	query := r.db.DB().NewSelect().
+		TableExpr("d2.transactions AS tx").
+		Join("LEFT JOIN d2.trades AS t ON t.id = tx.trade_id").
+		Where("tx.seller_id = ?", sellerID)
+
+	count, err := saleCompletedBetween(query, from, to).Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to count sales in range",
+			"error", err.Error(),
+			"seller_id", sellerID,
+		)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// saleCompletedBetween limits a sales query to sales completed in [from, to). A zero bound
+// leaves that side open. Sales without a trade fall back to the transaction's creation time.
+func saleCompletedBetween(query *bun.SelectQuery, from, to time.Time) *bun.SelectQuery {
+	if !from.IsZero() {
+		query = query.Where("COALESCE(t.completed_at, tx.created_at) >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("COALESCE(t.completed_at, tx.created_at) < ?", to)
+	}
+	return query
+}
+
+// saleRecordsQuery selects a seller's sales joined with the trade, listing, buyer and buyer's rating
+func (r *transactionRepository) saleRecordsQuery(sellerID string) *bun.SelectQuery {
+	return r.db.DB().NewSelect().
 		ColumnExpr("tx.id AS transaction_id").
 		ColumnExpr("t.completed_at").
 		ColumnExpr("tx.item_name").
@@ -127,18 +204,42 @@ func (r *transactionRepository) GetSalesBySeller(ctx context.Context, sellerID s
 		Join("LEFT JOIN d2.listings AS l ON l.id = tx.listing_id").
 		Join("INNER JOIN d2.profiles AS buyer ON buyer.id = tx.buyer_id").
 		Join("LEFT JOIN d2.ratings AS r ON r.transaction_id = tx.id AND r.rater_id = tx.buyer_id").
-		Where("tx.seller_id = ?", sellerID).
-		Order("t.completed_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Scan(ctx, &results)
+		Where("tx.seller_id = ?", sellerID)
+}
+
+func (r *transactionRepository) ListTradeTransactionsSince(ctx context.Context, since time.Time) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.DB().NewSelect().
+		Model(&transactions).
+		Relation("Trade").
+		Where("tx.trade_id IS NOT NULL").
+		Where("tx.created_at >= ?", since).
+		Order("tx.created_at ASC").
+		Scan(ctx)
 	if err != nil {
-		logger.FromContext(ctx).Error("failed to get sales",
+		logger.FromContext(ctx).Error("failed to list trade transactions",
 			"error", err.Error(),
-			"seller_id", sellerID,
 		)
-		return nil, 0, err
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// HasRecentSale reports whether the seller completed a listing trade for an item
+// with the same name and identical stats (compared as JSONB) since the given time
+func (r *transactionRepository) HasRecentSale(ctx context.Context, sellerID, itemName string, itemDetails json.RawMessage, since time.Time) (bool, error) {
+	q := r.db.DB().NewSelect().
+		Model((*models.Transaction)(nil)).
+		Where("seller_id = ?", sellerID).
+		Where("trade_id IS NOT NULL").
+		Where("LOWER(item_name) = LOWER(?)", itemName).
+		Where("created_at >= ?", since)
+
+	if len(itemDetails) == 0 || string(itemDetails) == "null" {
+		q = q.Where("(item_details IS NULL OR item_details = 'null'::jsonb)")
+	} else {
+		q = q.Where("item_details = ?::jsonb", string(itemDetails))
 	}
 
-	return results, count, nil
+	return q.Exists(ctx)
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+type watchRepository struct {
+	db *database.BunDB
+}
+
+// NewWatchRepository creates a new item watch repository
+func NewWatchRepository(db *database.BunDB) WatchRepository {
+	return &watchRepository{db: db}
+}
+
+func (r *watchRepository) Create(ctx context.Context, watch *models.ItemWatch) error {
+	_, err := r.db.DB().NewInsert().
+		Model(watch).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to create item watch",
+			"error", err.Error(),
+			"user_id", watch.UserID,
+		)
+	}
+	return err
+}
+
+func (r *watchRepository) GetByID(ctx context.Context, id string) (*models.ItemWatch, error) {
+	watch := new(models.ItemWatch)
+	err := r.db.DB().NewSelect().
+		Model(watch).
+		Where("iw.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return watch, nil
+}
+
+func (r *watchRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.DB().NewDelete().
+		Model((*models.ItemWatch)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to delete item watch",
+			"error", err.Error(),
+			"item_watch_id", id,
+		)
+	}
+	return err
+}
+
+func (r *watchRepository) ListByUserID(ctx context.Context, userID string) ([]*models.ItemWatch, error) {
+	var watches []*models.ItemWatch
+	err := r.db.DB().NewSelect().
+		Model(&watches).
+		Where("iw.user_id = ?", userID).
+		Order("iw.created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return watches, nil
+}
+
+func (r *watchRepository) CountByUserID(ctx context.Context, userID string) (int, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.ItemWatch)(nil)).
+		Where("user_id = ?", userID).
+		Count(ctx)
+}
+
+func (r *watchRepository) Exists(ctx context.Context, userID, itemName, game string) (bool, error) {
+	return r.db.DB().NewSelect().
+		Model((*models.ItemWatch)(nil)).
+		Where("user_id = ?", userID).
+		Where("LOWER(item_name) = LOWER(?)", itemName).
+		Where("game = ?", game).
+		Exists(ctx)
+}
+
+// FindWatchersForListing returns the watches whose item name and game match the
+// listing, skipping the seller's own watches
+func (r *watchRepository) FindWatchersForListing(ctx context.Context, listing *models.Listing) ([]*models.ItemWatch, error) {
+	var watches []*models.ItemWatch
+	err := r.db.DB().NewSelect().
+		Model(&watches).
+		Where("iw.game = ?", listing.Game).
+		Where("LOWER(iw.item_name) = LOWER(?)", listing.Name).
+		Where("iw.user_id != ?", listing.SellerID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return watches, nil
+}
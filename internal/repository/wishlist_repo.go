@@ -166,7 +166,8 @@ func (r *wishlistRepository) FindMatchingItems(ctx context.Context, listing *mod
 	}
 
 	// NULL filter fields act as wildcards — only filter when the wishlist field is NOT NULL
-	query = query.Where("(wi.rarity IS NULL OR wi.rarity = ?)", listing.Rarity)
+	query = query.Where("(wi.rarity IS NULL OR wi.rarity = '' OR LOWER(wi.rarity) = LOWER(?))", listing.Rarity)
+	query = query.Where("(wi.category IS NULL OR wi.category = '' OR LOWER(wi.category) = LOWER(?))", listing.Category)
 	query = query.Where("(wi.ladder IS NULL OR wi.ladder = ?)", listing.Ladder)
 	query = query.Where("(wi.hardcore IS NULL OR wi.hardcore = ?)", listing.Hardcore)
 	query = query.Where("(wi.is_non_rotw IS NULL OR wi.is_non_rotw = ?)", listing.IsNonRotw)
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+const (
+	// MaxAPITokens is how many active API tokens a user can hold
+	MaxAPITokens = 10
+	// DefaultAPITokenRateLimit is how many requests per minute a token gets by default
+	DefaultAPITokenRateLimit = 60
+	// apiTokenPrefix marks LootStash tokens so they are easy to spot in leaked-secret scans
+	apiTokenPrefix = "lsk_"
+	// apiTokenDisplayLength is how much of the token is kept in clear to tell tokens apart
+	apiTokenDisplayLength = len(apiTokenPrefix) + 8
+	// apiTokenCacheTTL bounds how long a revoked token can outlive a racing cache fill
+	apiTokenCacheTTL = 1 * time.Minute
+	// apiTokenTouchInterval is how often last_used_at is written per token
+	apiTokenTouchInterval = 5 * time.Minute
+)
+
+// SetAPITokens enables personal access tokens. rateLimit is the per-minute request
+// limit given to new tokens; zero uses DefaultAPITokenRateLimit.
+func (s *ProfileService) SetAPITokens(repo repository.APITokenRepository, rateLimit int) {
+	s.apiTokenRepo = repo
+	s.apiTokenRateLimit = rateLimit
+}
+
+// hashAPIToken returns the hex SHA-256 of a token. Tokens are random, so a fast hash
+// is enough to keep them useless if the table leaks.
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken creates a personal access token for the user and returns it along with
+// the token itself, which isn't stored and can't be shown again
+func (s *ProfileService) CreateToken(ctx context.Context, userID, name string, scopes []string) (*models.APIToken, string, error) {
+	if s.apiTokenRepo == nil {
+		return nil, "", ErrInvalidState
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(models.APITokenScopes, scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidState, scope)
+		}
+	}
+
+	count, err := s.apiTokenRepo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= MaxAPITokens {
+		return nil, "", ErrAPITokenLimitReached
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	raw := apiTokenPrefix + hex.EncodeToString(secret)
+
+	rateLimit := s.apiTokenRateLimit
+	if rateLimit <= 0 {
+		rateLimit = DefaultAPITokenRateLimit
+	}
+
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	token := &models.APIToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		TokenHash: hashAPIToken(raw),
+		Prefix:    raw[:apiTokenDisplayLength],
+		Scopes:    slices.Compact(scopes),
+		RateLimit: rateLimit,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiTokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, raw, nil
+}
+
+// ListTokens returns the user's active API tokens, oldest first
+func (s *ProfileService) ListTokens(ctx context.Context, userID string) ([]*models.APIToken, error) {
+	if s.apiTokenRepo == nil {
+		return []*models.APIToken{}, nil
+	}
+	return s.apiTokenRepo.ListByUserID(ctx, userID)
+}
+
+// RevokeToken revokes one of the user's API tokens. Revoking twice is a no-op.
+func (s *ProfileService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	if s.apiTokenRepo == nil {
+		return sql.ErrNoRows
+	}
+
+	token, err := s.apiTokenRepo.GetByID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return ErrForbidden
+	}
+	if token.IsRevoked() {
+		return nil
+	}
+
+	if err := s.apiTokenRepo.Revoke(ctx, token.ID, time.Now()); err != nil {
+		return err
+	}
+	_ = s.redis.Del(ctx, cache.APITokenKey(token.TokenHash))
+
+	logger.FromContext(ctx).Info("api token revoked",
+		"api_token_id", token.ID,
+		"user_id", userID,
+	)
+	return nil
+}
+
+// AuthenticateToken resolves a raw API token to its record, returning ErrInvalidAPIToken
+// for unknown, malformed or revoked tokens. Lookups are cached briefly by hash.
+func (s *ProfileService) AuthenticateToken(ctx context.Context, raw string) (*models.APIToken, error) {
+	if s.apiTokenRepo == nil || !strings.HasPrefix(raw, apiTokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	hash := hashAPIToken(raw)
+	key := cache.APITokenKey(hash)
+
+	var token *models.APIToken
+	if cached, err := s.redis.Get(ctx, key); err == nil && cached != "" {
+		var t models.APIToken
+		if json.Unmarshal([]byte(cached), &t) == nil {
+			token = &t
+		}
+	}
+	if token == nil {
+		t, err := s.apiTokenRepo.GetByHash(ctx, hash)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrInvalidAPIToken
+			}
+			return nil, err
+		}
+		token = t
+		if data, err := json.Marshal(token); err == nil {
+			_ = s.redis.Set(ctx, key, string(data), apiTokenCacheTTL)
+		}
+	}
+
+	if token.IsRevoked() {
+		return nil, ErrInvalidAPIToken
+	}
+
+	s.touchAPIToken(ctx, token.ID)
+	return token, nil
+}
+
+// touchAPIToken records that the token was used, at most once per apiTokenTouchInterval
+func (s *ProfileService) touchAPIToken(ctx context.Context, tokenID string) {
+	key := cache.APITokenUsedKey(tokenID)
+	acquired, err := s.redis.SetNX(ctx, key, "1", apiTokenTouchInterval)
+	if err == nil && !acquired {
+		return
+	}
+	if err := s.apiTokenRepo.TouchLastUsed(ctx, tokenID, time.Now()); err != nil {
+		_ = s.redis.Del(ctx, key)
+	}
+}
+
+// ConsumeTokenRequest counts a request against the token's per-minute limit and
+// returns how many requests are left in the window, or ErrRateLimited once the limit
+// is used up. Without Redis requests aren't counted.
+func (s *ProfileService) ConsumeTokenRequest(ctx context.Context, token *models.APIToken) (int, error) {
+	if !s.redis.IsAvailable() {
+		return token.RateLimit, nil
+	}
+
+	key := cache.APITokenRateKey(token.ID, time.Now().Unix()/60)
+	used, err := s.redis.Incr(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if used == 1 {
+		_ = s.redis.Expire(ctx, key, 2*time.Minute)
+	}
+	if used > int64(token.RateLimit) {
+		return 0, ErrRateLimited
+	}
+	return token.RateLimit - int(used), nil
+}
+
+// ToAPITokenResponse converts an API token to its DTO, without the secret
+func (s *ProfileService) ToAPITokenResponse(token *models.APIToken) *dto.APITokenResponse {
+	return &dto.APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		RateLimit:  token.RateLimit,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func newAPITokenTestService(t *testing.T) (*ProfileService, *mocks.MockAPITokenRepository) {
+	redis, _ := newTestRedisReal(t)
+	tokenRepo := new(mocks.MockAPITokenRepository)
+	svc := NewProfileService(new(mocks.MockProfileRepository), redis, nil)
+	svc.SetAPITokens(tokenRepo, 2)
+	return svc, tokenRepo
+}
+
+func TestCreateToken_StoresOnlyTheHash(t *testing.T) {
+	svc, tokenRepo := newAPITokenTestService(t)
+
+	tokenRepo.On("CountActiveByUserID", mock.Anything, testUserID).Return(0, nil)
+	tokenRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.APIToken")).Return(nil)
+
+	token, raw, err := svc.CreateToken(context.Background(), testUserID, "Discord bot",
+		[]string{models.APITokenScopeStatsRead, models.APITokenScopeListingsRead, models.APITokenScopeStatsRead})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(raw, apiTokenPrefix))
+	assert.Equal(t, hashAPIToken(raw), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, raw)
+	assert.Equal(t, raw[:apiTokenDisplayLength], token.Prefix)
+	assert.Equal(t, []string{models.APITokenScopeListingsRead, models.APITokenScopeStatsRead}, token.Scopes)
+	assert.Equal(t, 2, token.RateLimit)
+}
+
+func TestCreateToken_LimitReached(t *testing.T) {
+	svc, tokenRepo := newAPITokenTestService(t)
+
+	tokenRepo.On("CountActiveByUserID", mock.Anything, testUserID).Return(MaxAPITokens, nil)
+
+	_, _, err := svc.CreateToken(context.Background(), testUserID, "One too many", []string{models.APITokenScopeListingsRead})
+	assert.ErrorIs(t, err, ErrAPITokenLimitReached)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateToken_RevokedTokenRejected(t *testing.T) {
+	svc, tokenRepo := newAPITokenTestService(t)
+	ctx := context.Background()
+	raw := apiTokenPrefix + "abc123"
+
+	token := &models.APIToken{ID: "token-1", UserID: testUserID, TokenHash: hashAPIToken(raw), RateLimit: 2}
+	tokenRepo.On("GetByHash", mock.Anything, token.TokenHash).Return(token, nil).Once()
+	tokenRepo.On("TouchLastUsed", mock.Anything, "token-1", mock.Anything).Return(nil)
+	tokenRepo.On("GetByID", mock.Anything, "token-1").Return(token, nil)
+	tokenRepo.On("Revoke", mock.Anything, "token-1", mock.Anything).Return(nil)
+
+	got, err := svc.AuthenticateToken(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", got.ID)
+
+	require.NoError(t, svc.RevokeToken(ctx, testUserID, "token-1"))
+
+	now := time.Now()
+	revoked := *token
+	revoked.RevokedAt = &now
+	tokenRepo.On("GetByHash", mock.Anything, token.TokenHash).Return(&revoked, nil).Once()
+
+	_, err = svc.AuthenticateToken(ctx, raw)
+	assert.ErrorIs(t, err, ErrInvalidAPIToken)
+}
+
+func TestAuthenticateToken_UnknownOrMalformed(t *testing.T) {
+	svc, tokenRepo := newAPITokenTestService(t)
+	raw := apiTokenPrefix + "unknown"
+
+	tokenRepo.On("GetByHash", mock.Anything, hashAPIToken(raw)).Return(nil, sql.ErrNoRows)
+
+	_, err := svc.AuthenticateToken(context.Background(), raw)
+	assert.ErrorIs(t, err, ErrInvalidAPIToken)
+
+	_, err = svc.AuthenticateToken(context.Background(), "eyJhbGciOiJIUzI1NiJ9.session")
+	assert.ErrorIs(t, err, ErrInvalidAPIToken)
+}
+
+func TestRevokeToken_OtherUsersToken(t *testing.T) {
+	svc, tokenRepo := newAPITokenTestService(t)
+
+	tokenRepo.On("GetByID", mock.Anything, "token-1").Return(&models.APIToken{ID: "token-1", UserID: "someone-else"}, nil)
+
+	err := svc.RevokeToken(context.Background(), testUserID, "token-1")
+	assert.ErrorIs(t, err, ErrForbidden)
+	tokenRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConsumeTokenRequest_PerMinuteLimit(t *testing.T) {
+	svc, _ := newAPITokenTestService(t)
+	ctx := context.Background()
+	token := &models.APIToken{ID: "token-1", RateLimit: 2}
+
+	remaining, err := svc.ConsumeTokenRequest(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+
+	remaining, err = svc.ConsumeTokenRequest(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+
+	_, err = svc.ConsumeTokenRequest(ctx, token)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
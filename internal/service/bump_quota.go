@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+const (
+	// DefaultFreeDailyBumps and DefaultPremiumDailyBumps are how many listing refreshes a
+	// seller gets per UTC day before spending bump credits
+	DefaultFreeDailyBumps    = 1
+	DefaultPremiumDailyBumps = 5
+	// MaxBumpCredits caps the extra bumps a user can hold
+	MaxBumpCredits = 50
+	// bumpCounterTTL keeps a day's counter until well after the day is over
+	bumpCounterTTL = 48 * time.Hour
+)
+
+// SetDailyBumps sets the free and premium daily bump quotas; zero keeps the defaults
+func (s *ListingService) SetDailyBumps(free, premium int) {
+	s.freeDailyBumps = free
+	s.premiumDailyBumps = premium
+}
+
+// dailyBumpsFor returns the seller's daily bump quota
+func (s *ListingService) dailyBumpsFor(profile *models.Profile) int {
+	if profile.IsPremium {
+		if s.premiumDailyBumps > 0 {
+			return s.premiumDailyBumps
+		}
+		return DefaultPremiumDailyBumps
+	}
+	if s.freeDailyBumps > 0 {
+		return s.freeDailyBumps
+	}
+	return DefaultFreeDailyBumps
+}
+
+// bumpDay is the UTC day a bump counts toward
+func bumpDay(now time.Time) string {
+	return now.UTC().Format("20060102")
+}
+
+// consumeBump spends one of the seller's bumps, today's quota first and then a credit.
+// The returned func gives the bump back if the refresh fails afterwards. Without Redis
+// quotas aren't tracked and only the refresh cooldown applies.
+func (s *ListingService) consumeBump(ctx context.Context, profile *models.Profile) (func(), error) {
+	if !s.redis.IsAvailable() {
+		return func() {}, nil
+	}
+
+	usedKey := cache.BumpsUsedKey(profile.ID, bumpDay(time.Now()))
+	used, err := s.redis.Incr(ctx, usedKey)
+	if err != nil {
+		return nil, err
+	}
+	if used == 1 {
+		_ = s.redis.Expire(ctx, usedKey, bumpCounterTTL)
+	}
+	if int(used) <= s.dailyBumpsFor(profile) {
+		return func() { _, _ = s.redis.IncrBy(context.Background(), usedKey, -1) }, nil
+	}
+	_, _ = s.redis.IncrBy(ctx, usedKey, -1)
+
+	creditsKey := cache.BumpCreditsKey(profile.ID)
+	left, err := s.redis.IncrBy(ctx, creditsKey, -1)
+	if err != nil {
+		return nil, err
+	}
+	if left < 0 {
+		_, _ = s.redis.IncrBy(ctx, creditsKey, 1)
+		return nil, ErrQuotaExceeded
+	}
+	return func() { _, _ = s.redis.IncrBy(context.Background(), creditsKey, 1) }, nil
+}
+
+// RemainingBumps reports the seller's bumps left today and their credits. It returns
+// nil without Redis, since quotas aren't tracked then.
+func (s *ListingService) RemainingBumps(ctx context.Context, sellerID string) (*dto.BumpQuotaResponse, error) {
+	if !s.redis.IsAvailable() {
+		return nil, nil
+	}
+
+	profile, err := s.profileService.GetByID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	used, err := s.readCounter(ctx, cache.BumpsUsedKey(sellerID, bumpDay(now)))
+	if err != nil {
+		return nil, err
+	}
+	credits, err := s.readCounter(ctx, cache.BumpCreditsKey(sellerID))
+	if err != nil {
+		return nil, err
+	}
+
+	daily := s.dailyBumpsFor(profile)
+	day := now.UTC()
+	return &dto.BumpQuotaResponse{
+		DailyLimit:     daily,
+		RemainingToday: max(daily-used, 0),
+		Credits:        max(credits, 0),
+		ResetsAt:       time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+// GrantBumpCredits adds bump credits to a user, capped at MaxBumpCredits, and returns
+// the new balance
+func (s *ListingService) GrantBumpCredits(ctx context.Context, userID string, amount int) (int, error) {
+	if !s.redis.IsAvailable() {
+		return 0, ErrInvalidState
+	}
+	if _, err := s.profileService.GetByID(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	key := cache.BumpCreditsKey(userID)
+	credits, err := s.redis.IncrBy(ctx, key, int64(amount))
+	if err != nil {
+		return 0, err
+	}
+	if credits > MaxBumpCredits {
+		credits = MaxBumpCredits
+		if err := s.redis.Set(ctx, key, credits, 0); err != nil {
+			return 0, err
+		}
+	}
+	return int(credits), nil
+}
+
+// readCounter reads an integer counter, treating a missing key as zero
+func (s *ListingService) readCounter(ctx context.Context, key string) (int, error) {
+	raw, err := s.redis.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(raw)
+}
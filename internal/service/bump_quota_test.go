@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+// refreshableListing returns an active listing old enough to be past any refresh cooldown
+func refreshableListing(id string) *models.Listing {
+	l := testListing(id, testSellerID)
+	l.CreatedAt = time.Now().Add(-25 * time.Hour)
+	return l
+}
+
+func TestRefresh_FreeUserOutOfDailyBumps(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("GetByID", mock.Anything, "listing-1").Return(refreshableListing("listing-1"), nil)
+	listingRepo.On("GetByID", mock.Anything, "listing-2").Return(refreshableListing("listing-2"), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil).Once()
+
+	_, err := svc.Refresh(context.Background(), "listing-1", testSellerID, nil)
+	require.NoError(t, err)
+
+	_, err = svc.Refresh(context.Background(), "listing-2", testSellerID, nil)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	listingRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestRefresh_SpendsCreditsAfterDailyQuota(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	for _, id := range []string{"listing-1", "listing-2", "listing-3"} {
+		listingRepo.On("GetByID", mock.Anything, id).Return(refreshableListing(id), nil)
+	}
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	credits, err := svc.GrantBumpCredits(context.Background(), testSellerID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, credits)
+
+	_, err = svc.Refresh(context.Background(), "listing-1", testSellerID, nil)
+	require.NoError(t, err)
+	_, err = svc.Refresh(context.Background(), "listing-2", testSellerID, nil)
+	require.NoError(t, err)
+	_, err = svc.Refresh(context.Background(), "listing-3", testSellerID, nil)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	quota, err := svc.RemainingBumps(context.Background(), testSellerID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, quota.RemainingToday)
+	assert.Equal(t, 0, quota.Credits)
+}
+
+func TestRefresh_FailedUpdateRefundsBump(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(refreshableListing(testListingID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(errors.New("db down"))
+
+	_, err := svc.Refresh(context.Background(), testListingID, testSellerID, nil)
+	require.Error(t, err)
+
+	used, _ := mr.Get(cache.BumpsUsedKey(testSellerID, bumpDay(time.Now())))
+	assert.Equal(t, "0", used)
+}
+
+func TestRemainingBumps_Premium(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+	svc.SetDailyBumps(2, 8)
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(refreshableListing(testListingID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	_, err := svc.Refresh(context.Background(), testListingID, testSellerID, nil)
+	require.NoError(t, err)
+
+	quota, err := svc.RemainingBumps(context.Background(), testSellerID)
+	require.NoError(t, err)
+	assert.Equal(t, 8, quota.DailyLimit)
+	assert.Equal(t, 7, quota.RemainingToday)
+	assert.True(t, quota.ResetsAt.After(time.Now()))
+}
+
+func TestRemainingBumps_NoRedis(t *testing.T) {
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), new(mocks.MockListingRepository), newTestRedis())
+
+	quota, err := svc.RemainingBumps(context.Background(), testSellerID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, quota)
+}
+
+func TestGrantBumpCredits_Capped(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	svc, _ := setupListingService(profileRepo, new(mocks.MockListingRepository), redis)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+
+	_, err := svc.GrantBumpCredits(context.Background(), testSellerID, 40)
+	require.NoError(t, err)
+	credits, err := svc.GrantBumpCredits(context.Background(), testSellerID, 40)
+
+	require.NoError(t, err)
+	assert.Equal(t, MaxBumpCredits, credits)
+}
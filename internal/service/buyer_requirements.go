@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+// checkBuyerRequirements returns ErrReputationTooLow when the user doesn't have the
+// reputation a listing or service asks for. Unrestricted targets skip the profile lookup.
+func checkBuyerRequirements(ctx context.Context, profiles *ProfileService, reqs models.BuyerRequirements, userID string) error {
+	if !reqs.IsSet() {
+		return nil
+	}
+	profile, err := profiles.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !reqs.Allows(profile) {
+		return ErrReputationTooLow
+	}
+	return nil
+}
+
+// toBuyerRequirementsResponse converts requirements for a response, nil when none are set
+func toBuyerRequirementsResponse(reqs models.BuyerRequirements) *dto.BuyerRequirementsResponse {
+	if !reqs.IsSet() {
+		return nil
+	}
+	return &dto.BuyerRequirementsResponse{
+		MinRating:    reqs.MinRating,
+		MinTrades:    reqs.MinTrades,
+		AllowUnrated: reqs.AllowUnrated,
+	}
+}
+
+// updatedThreshold applies a threshold from an update request: nil keeps the current
+// value and zero removes it
+func updatedThreshold[T float64 | int](current, requested *T) *T {
+	if requested == nil {
+		return current
+	}
+	if *requested == 0 {
+		return nil
+	}
+	v := *requested
+	return &v
+}
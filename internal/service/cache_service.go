@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// purgeablePatterns are the key patterns an admin may bulk purge. They cover
+// read-through caches only; rate limits, idempotency keys, trade completion
+// tokens and notification state are deliberately excluded.
+var purgeablePatterns = map[string]bool{
+	cache.ProfilePattern():       true,
+	cache.ListingPattern():       true,
+	cache.ServicePattern():       true,
+	cache.OfferPattern():         true,
+	cache.FilterResultsPattern(): true,
+	cache.HomePattern():          true,
+	cache.PriceSummaryPattern():  true,
+}
+
+// PurgeablePatterns returns the allowed PurgeAll patterns, sorted
+func PurgeablePatterns() []string {
+	patterns := make([]string, 0, len(purgeablePatterns))
+	for pattern := range purgeablePatterns {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// CacheService lets admins drop stale Redis entries after a data fix
+type CacheService struct {
+	redis       *cache.RedisClient
+	invalidator *cache.Invalidator
+	profileRepo repository.ProfileRepository
+	homeWarmers []func(ctx context.Context)
+}
+
+// NewCacheService creates a new cache service
+func NewCacheService(redis *cache.RedisClient, profileRepo repository.ProfileRepository) *CacheService {
+	return &CacheService{
+		redis:       redis,
+		invalidator: cache.NewInvalidator(redis),
+		profileRepo: profileRepo,
+	}
+}
+
+// SetHomeWarmers sets the functions that rebuild the home page keys. home:recent and
+// home:recent:services are only filled by pushes and warming, so PurgeAll runs these
+// after purging the home pattern instead of leaving the feeds empty until a restart.
+func (s *CacheService) SetHomeWarmers(warmers ...func(ctx context.Context)) {
+	s.homeWarmers = warmers
+}
+
+// PurgeListing removes a listing and its DTO from cache
+func (s *CacheService) PurgeListing(ctx context.Context, id string) error {
+	if err := s.invalidator.InvalidateListing(ctx, id); err != nil {
+		return err
+	}
+	return s.invalidator.InvalidateListingDTO(ctx, id)
+}
+
+// PurgeProfile removes a profile, its DTO and its by-username entry from cache.
+// The username entry is only dropped when the profile still exists.
+func (s *CacheService) PurgeProfile(ctx context.Context, id string) error {
+	if err := s.invalidator.InvalidateProfile(ctx, id); err != nil {
+		return err
+	}
+	if err := s.invalidator.InvalidateProfileDTO(ctx, id); err != nil {
+		return err
+	}
+
+	profile, err := s.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return s.invalidator.InvalidateProfileByUsername(ctx, strings.ToLower(profile.Username))
+}
+
+// PurgeAll removes every key matching pattern, which must be one of
+// PurgeablePatterns. Keys are found with SCAN, never KEYS. Purging the home
+// pattern re-warms the home page keys afterwards.
+func (s *CacheService) PurgeAll(ctx context.Context, pattern string) (int64, error) {
+	if !purgeablePatterns[pattern] {
+		return 0, ErrInvalidCachePattern
+	}
+
+	purged, err := s.redis.PurgeByPattern(ctx, pattern)
+	if err != nil {
+		return purged, err
+	}
+
+	if pattern == cache.HomePattern() {
+		for _, warm := range s.homeWarmers {
+			warm(ctx)
+		}
+	}
+
+	return purged, nil
+}
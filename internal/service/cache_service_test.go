@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func TestCachePurgeListing_RemovesListingAndDTO(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	svc := NewCacheService(redis, new(mocks.MockProfileRepository))
+	ctx := context.Background()
+
+	mr.Set(cache.ListingKey(testListingID), "{}")
+	mr.Set(cache.ListingDTOKey(testListingID), "{}")
+	mr.Set(cache.ListingKey("other"), "{}")
+
+	err := svc.PurgeListing(ctx, testListingID)
+
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists(cache.ListingKey(testListingID)))
+	assert.False(t, mr.Exists(cache.ListingDTOKey(testListingID)))
+	assert.True(t, mr.Exists(cache.ListingKey("other")))
+}
+
+func TestCachePurgeProfile_RemovesUsernameEntry(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewCacheService(redis, profileRepo)
+	ctx := context.Background()
+
+	profile := testProfile(testUserID)
+	profile.Username = "Trader"
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+
+	mr.Set(cache.ProfileKey(testUserID), "{}")
+	mr.Set(cache.ProfileDTOKey(testUserID), "{}")
+	mr.Set(cache.ProfileUsernameKey("trader"), "{}")
+
+	err := svc.PurgeProfile(ctx, testUserID)
+
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists(cache.ProfileKey(testUserID)))
+	assert.False(t, mr.Exists(cache.ProfileDTOKey(testUserID)))
+	assert.False(t, mr.Exists(cache.ProfileUsernameKey("trader")))
+}
+
+func TestCachePurgeProfile_DeletedProfile(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewCacheService(redis, profileRepo)
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return((*models.Profile)(nil), sql.ErrNoRows)
+	mr.Set(cache.ProfileKey(testUserID), "{}")
+
+	err := svc.PurgeProfile(ctx, testUserID)
+
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists(cache.ProfileKey(testUserID)))
+}
+
+func TestCachePurgeAll_DeletesMatchingKeysAcrossScanBatches(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	svc := NewCacheService(redis, new(mocks.MockProfileRepository))
+	ctx := context.Background()
+
+	for i := 0; i < 1200; i++ {
+		mr.Set(cache.FilterResultsKey(fmt.Sprintf("hash-%d", i)), "[]")
+	}
+	mr.Set(cache.ListingKey(testListingID), "{}")
+	mr.Set(cache.RateLimitKey("127.0.0.1", "offers"), "1")
+
+	purged, err := svc.PurgeAll(ctx, cache.FilterResultsPattern())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1200), purged)
+	assert.True(t, mr.Exists(cache.ListingKey(testListingID)))
+	assert.True(t, mr.Exists(cache.RateLimitKey("127.0.0.1", "offers")))
+}
+
+func TestCachePurgeAll_HomePatternRewarmsRecentFeeds(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	svc := NewCacheService(redis, new(mocks.MockProfileRepository))
+	ctx := context.Background()
+
+	warmed := 0
+	svc.SetHomeWarmers(func(ctx context.Context) {
+		warmed++
+		_ = redis.LPush(ctx, cache.HomeRecentKey(), `{"id":"fresh"}`)
+	})
+	mr.Set(cache.HomeStatsKey(), "{}")
+	_, _ = mr.Lpush(cache.HomeRecentKey(), `{"id":"stale"}`)
+
+	purged, err := svc.PurgeAll(ctx, cache.HomePattern())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.Equal(t, 1, warmed)
+	assert.False(t, mr.Exists(cache.HomeStatsKey()))
+	recent, err := mr.List(cache.HomeRecentKey())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"id":"fresh"}`}, recent)
+
+	// Other patterns leave the home feeds alone
+	_, err = svc.PurgeAll(ctx, cache.ListingPattern())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+}
+
+func TestCachePurgeAll_RejectsPatternOutsideAllowList(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	svc := NewCacheService(redis, new(mocks.MockProfileRepository))
+	ctx := context.Background()
+
+	mr.Set(cache.RateLimitKey("127.0.0.1", "offers"), "1")
+
+	for _, pattern := range []string{"*", "ratelimit:*", "idempotency:*", "listing*", ""} {
+		purged, err := svc.PurgeAll(ctx, pattern)
+
+		assert.ErrorIs(t, err, ErrInvalidCachePattern, pattern)
+		assert.Zero(t, purged)
+	}
+	assert.True(t, mr.Exists(cache.RateLimitKey("127.0.0.1", "offers")))
+}
+
+func TestCachePurgeAll_NoRedis(t *testing.T) {
+	svc := NewCacheService(newTestRedis(), new(mocks.MockProfileRepository))
+
+	purged, err := svc.PurgeAll(context.Background(), cache.ListingPattern())
+
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// ChatArchiver archives the chat of a trade or service run once it completes or
+// is cancelled, after a grace period so the parties can still find it in their
+// inbox for a while. Archived chats stay readable but take no new messages.
+// A nil archiver leaves chats in the inbox.
+type ChatArchiver struct {
+	chatRepo repository.ChatRepository
+	grace    time.Duration
+}
+
+// NewChatArchiver creates an archiver that archives chats grace after resolution.
+// A grace of zero or less archives them immediately.
+func NewChatArchiver(chatRepo repository.ChatRepository, grace time.Duration) *ChatArchiver {
+	if grace < 0 {
+		grace = 0
+	}
+	return &ChatArchiver{chatRepo: chatRepo, grace: grace}
+}
+
+// ArchiveTradeChat schedules the trade's chat to be archived. Failures are logged
+// by the repository and don't fail the resolution.
+func (a *ChatArchiver) ArchiveTradeChat(ctx context.Context, tradeID string, resolvedAt time.Time) {
+	if a == nil {
+		return
+	}
+	_ = a.chatRepo.ArchiveByTradeID(ctx, tradeID, resolvedAt.Add(a.grace))
+}
+
+// ArchiveServiceRunChat schedules the service run's chat to be archived. Failures
+// are logged by the repository and don't fail the resolution.
+func (a *ChatArchiver) ArchiveServiceRunChat(ctx context.Context, serviceRunID string, resolvedAt time.Time) {
+	if a == nil {
+		return
+	}
+	_ = a.chatRepo.ArchiveByServiceRunID(ctx, serviceRunID, resolvedAt.Add(a.grace))
+}
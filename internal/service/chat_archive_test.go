@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// archivedWithin matches an archive time grace after a resolution that happened during the test
+func archivedWithin(start time.Time, grace time.Duration) interface{} {
+	return mock.MatchedBy(func(at time.Time) bool {
+		return !at.Before(start.Add(grace)) && !at.After(time.Now().Add(grace))
+	})
+}
+
+func TestTradeComplete_ArchivesChatAfterGrace(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	h.svc.SetChatArchiver(NewChatArchiver(h.chatRepo, 24*time.Hour))
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	start := time.Now()
+	h.chatRepo.On("ArchiveByTradeID", ctx, testTradeID, archivedWithin(start, 24*time.Hour)).Return(nil)
+
+	h.confirmCompletion(t, testSellerID)
+	_, _, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
+
+	require.NoError(t, err)
+	h.chatRepo.AssertExpectations(t)
+}
+
+func TestTradeCancel_ArchivesChatImmediately(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	h.svc.SetChatArchiver(NewChatArchiver(h.chatRepo, 0))
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	start := time.Now()
+	h.chatRepo.On("ArchiveByTradeID", ctx, testTradeID, archivedWithin(start, 0)).Return(nil)
+
+	result, err := h.svc.Cancel(ctx, testTradeID, testBuyerID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", result.Status)
+	h.chatRepo.AssertExpectations(t)
+}
+
+func TestServiceRunCancel_ArchivesChat(t *testing.T) {
+	runRepo := new(mocks.MockServiceRunRepository)
+	chatRepo := new(mocks.MockChatRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	profileService := NewProfileService(nil, nil, nil)
+	svc := NewServiceRunService(
+		runRepo,
+		new(mocks.MockTransactionRepository),
+		new(mocks.MockRatingRepository),
+		chatRepo,
+		NewNotificationService(notifRepo, nil),
+		profileService,
+		NewServiceService(nil, profileService, nil),
+		nil, // redis
+	)
+	svc.SetChatArchiver(NewChatArchiver(chatRepo, time.Hour))
+	ctx := context.Background()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	run.Service = testServiceModel(testServiceID, testProviderID)
+
+	runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(run, nil)
+	runRepo.On("Update", ctx, run).Return(nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	start := time.Now()
+	chatRepo.On("ArchiveByServiceRunID", ctx, testServiceRunID, archivedWithin(start, time.Hour)).Return(nil)
+
+	_, err := svc.Cancel(ctx, testServiceRunID, testClientID, "")
+
+	require.NoError(t, err)
+	chatRepo.AssertExpectations(t)
+}
+
+func TestChatArchiver_NilIsNoop(t *testing.T) {
+	var archiver *ChatArchiver
+
+	archiver.ArchiveTradeChat(context.Background(), testTradeID, time.Now())
+	archiver.ArchiveServiceRunChat(context.Background(), testServiceRunID, time.Now())
+}
+
+func TestChatIsArchived(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&models.Chat{}).IsArchived(now))
+	assert.True(t, (&models.Chat{ArchivedAt: &past}).IsArchived(now))
+	assert.False(t, (&models.Chat{ArchivedAt: &future}).IsArchived(now), "still in its grace period")
+}
+
+func TestSendMessage_ArchivedChat(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	// The trade is still active but its chat was archived
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+	archivedAt := time.Now().Add(-time.Minute)
+	chat.ArchivedAt = &archivedAt
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+
+	_, err := svc.SendMessage(ctx, testChatID, testSellerID, "Hello?")
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestChatList_PassesArchivedFilter(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID, withTradeStatus("completed"))
+	chat := testChatWithTrade(testChatID, trade)
+	archivedAt := time.Now().Add(-time.Hour)
+	chat.ArchivedAt = &archivedAt
+	chatRepo.On("ListByParticipant", ctx, testBuyerID, true, 0, 20).Return([]*models.Chat{chat}, 1, nil)
+
+	chats, count, err := svc.List(ctx, testBuyerID, true, 0, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, chats, 1)
+	resp := svc.ToChatResponse(chats[0])
+	assert.True(t, resp.Archived)
+	assert.Equal(t, &archivedAt, resp.ArchivedAt)
+}
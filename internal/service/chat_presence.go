@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+)
+
+const (
+	// chatTypingTTL is how long a typing indicator lasts; repeats within it aren't republished
+	chatTypingTTL = 4 * time.Second
+	// chatPresenceTTL outlives a few stream heartbeats so an open chat stays "seen"
+	chatPresenceTTL = 2 * time.Minute
+)
+
+// Chat stream event types
+const (
+	ChatEventTyping = "typing"
+	ChatEventSeen   = "seen"
+	ChatEventRead   = "read"
+)
+
+// ChatPresenceStream is a participant's live view of a chat's presence events
+type ChatPresenceStream struct {
+	*cache.Subscription
+	// Initial holds events to send before live ones, such as when the other participant was last seen
+	Initial []string
+
+	service *ChatService
+	chatID  string
+	userID  string
+}
+
+// SubscribePresence opens a participant's presence stream for a chat and marks them
+// as seen. Returns ErrStreamUnavailable when Redis isn't configured.
+func (s *ChatService) SubscribePresence(ctx context.Context, chatID string, userID string) (*ChatPresenceStream, error) {
+	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isParticipant(chat, userID) {
+		return nil, ErrForbidden
+	}
+
+	sub, err := s.redis.Subscribe(ctx, cache.ChatEventsChannel(chatID))
+	if errors.Is(err, cache.ErrUnavailable) {
+		return nil, ErrStreamUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &ChatPresenceStream{
+		Subscription: sub,
+		service:      s,
+		chatID:       chatID,
+		userID:       userID,
+	}
+
+	// Tell the newcomer when the other participant last had the chat open
+	participantA, participantB := s.getParticipants(chat)
+	otherID := participantA
+	if otherID == userID {
+		otherID = participantB
+	}
+	if seen, err := s.redis.Get(ctx, cache.ChatPresenceKey(chatID, otherID)); err == nil {
+		if at, err := time.Parse(time.RFC3339Nano, seen); err == nil {
+			if data, err := json.Marshal(dto.ChatStreamEvent{Type: ChatEventSeen, ChatID: chatID, UserID: otherID, At: at}); err == nil {
+				stream.Initial = append(stream.Initial, string(data))
+			}
+		}
+	}
+
+	stream.Touch(ctx)
+	s.publishPresence(ctx, chatID, ChatEventSeen, userID)
+
+	return stream, nil
+}
+
+// Touch refreshes the participant's last-seen time while their stream stays open
+func (p *ChatPresenceStream) Touch(ctx context.Context) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	_ = p.service.redis.Set(ctx, cache.ChatPresenceKey(p.chatID, p.userID), now, chatPresenceTTL)
+}
+
+// PublishTyping tells the other participant that userID is typing. Calls within
+// chatTypingTTL of the last published one are absorbed, so clients can call it
+// on every keystroke. Nothing is stored as a message.
+func (s *ChatService) PublishTyping(ctx context.Context, chatID string, userID string) error {
+	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if !s.isParticipant(chat, userID) {
+		return ErrForbidden
+	}
+
+	if !s.isChatActive(chat) {
+		return ErrInvalidState
+	}
+
+	first, err := s.redis.SetNX(ctx, cache.ChatTypingKey(chatID, userID), "1", chatTypingTTL)
+	if err != nil || !first {
+		return nil
+	}
+
+	s.publishPresence(ctx, chatID, ChatEventTyping, userID)
+	return nil
+}
+
+// PublishRead tells the other participant that userID has read the chat.
+// MarkMessagesAsRead already does this; use it when reads are recorded elsewhere.
+func (s *ChatService) PublishRead(ctx context.Context, chatID string, userID string) error {
+	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if !s.isParticipant(chat, userID) {
+		return ErrForbidden
+	}
+
+	s.publishPresence(ctx, chatID, ChatEventRead, userID)
+	return nil
+}
+
+// publishPresence pushes an ephemeral event to the chat's events channel. It
+// publishes even without channel subscribers, since a gateway following every
+// chat through ChatEventsPattern doesn't count as one.
+func (s *ChatService) publishPresence(ctx context.Context, chatID string, eventType string, userID string) {
+	channel := cache.ChatEventsChannel(chatID)
+	data, err := json.Marshal(dto.ChatStreamEvent{Type: eventType, ChatID: chatID, UserID: userID, At: time.Now().UTC()})
+	if err != nil {
+		return
+	}
+	if err := s.redis.Publish(ctx, channel, string(data)); err != nil {
+		logger.FromContext(ctx).Warn("failed to publish chat presence event",
+			"error", err.Error(),
+			"chat_id", chatID,
+			"type", eventType,
+		)
+	}
+}
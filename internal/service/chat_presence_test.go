@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveChatEvent waits for the next event on a presence subscription
+func receiveChatEvent(t *testing.T, sub *cache.Subscription) dto.ChatStreamEvent {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		var event dto.ChatStreamEvent
+		require.NoError(t, json.Unmarshal([]byte(msg), &event))
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no chat event received")
+	}
+	return dto.ChatStreamEvent{}
+}
+
+// assertNoChatEvent checks nothing else was published
+func assertNoChatEvent(t *testing.T, sub *cache.Subscription) {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		t.Fatalf("unexpected chat event: %s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// ---------------------------------------------------------------------------
+// SubscribePresence
+// ---------------------------------------------------------------------------
+
+func TestSubscribePresence_SendsPeerLastSeen(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	redisClient, mr := newTestRedisReal(t)
+	svc.SetRedis(redisClient)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	seenAt := time.Now().Add(-time.Minute).UTC()
+	require.NoError(t, mr.Set(cache.ChatPresenceKey(testChatID, testSellerID), seenAt.Format(time.RFC3339Nano)))
+
+	stream, err := svc.SubscribePresence(ctx, testChatID, testBuyerID)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	require.Len(t, stream.Initial, 1)
+	var event dto.ChatStreamEvent
+	require.NoError(t, json.Unmarshal([]byte(stream.Initial[0]), &event))
+	assert.Equal(t, ChatEventSeen, event.Type)
+	assert.Equal(t, testSellerID, event.UserID)
+	assert.True(t, seenAt.Equal(event.At))
+
+	// Opening the stream marks the buyer as seen
+	assert.True(t, mr.Exists(cache.ChatPresenceKey(testChatID, testBuyerID)))
+	assert.Equal(t, ChatEventSeen, receiveChatEvent(t, stream.Subscription).Type)
+}
+
+func TestSubscribePresence_NonParticipant(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetRedis(redisClient)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	stream, err := svc.SubscribePresence(ctx, testChatID, "stranger-999")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Nil(t, stream)
+}
+
+func TestSubscribePresence_NoRedis(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	stream, err := svc.SubscribePresence(ctx, testChatID, testBuyerID)
+
+	assert.ErrorIs(t, err, ErrStreamUnavailable)
+	assert.Nil(t, stream)
+}
+
+// ---------------------------------------------------------------------------
+// PublishTyping
+// ---------------------------------------------------------------------------
+
+func TestPublishTyping_PublishesOncePerWindow(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetRedis(redisClient)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	sub, err := redisClient.Subscribe(ctx, cache.ChatEventsChannel(testChatID))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NoError(t, svc.PublishTyping(ctx, testChatID, testSellerID))
+	require.NoError(t, svc.PublishTyping(ctx, testChatID, testSellerID))
+
+	event := receiveChatEvent(t, sub)
+	assert.Equal(t, ChatEventTyping, event.Type)
+	assert.Equal(t, testSellerID, event.UserID)
+	assertNoChatEvent(t, sub)
+}
+
+func TestPublishTyping_NonParticipant(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	err := svc.PublishTyping(ctx, testChatID, "stranger-999")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestPublishTyping_InactiveChat(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID, withTradeStatus("completed"))
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	err := svc.PublishTyping(ctx, testChatID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+// ---------------------------------------------------------------------------
+// Read receipts
+// ---------------------------------------------------------------------------
+
+func TestMarkMessagesAsRead_PublishesReadReceipt(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetRedis(redisClient)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+	messageRepo.On("MarkAllAsReadInChat", ctx, testChatID, testBuyerID).Return(nil)
+
+	sub, err := redisClient.Subscribe(ctx, cache.ChatEventsChannel(testChatID))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NoError(t, svc.MarkMessagesAsRead(ctx, testChatID, testBuyerID, nil))
+
+	event := receiveChatEvent(t, sub)
+	assert.Equal(t, ChatEventRead, event.Type)
+	assert.Equal(t, testBuyerID, event.UserID)
+}
+
+func TestPublishRead_ParticipantOnly(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetRedis(redisClient)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(testChatWithTrade(testChatID, trade), nil)
+
+	sub, err := redisClient.Subscribe(ctx, "chat:"+testChatID+":events")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	assert.ErrorIs(t, svc.PublishRead(ctx, testChatID, "stranger-999"), ErrForbidden)
+	assertNoChatEvent(t, sub)
+
+	require.NoError(t, svc.PublishRead(ctx, testChatID, testSellerID))
+
+	event := receiveChatEvent(t, sub)
+	assert.Equal(t, ChatEventRead, event.Type)
+	assert.Equal(t, testChatID, event.ChatID)
+	assert.Equal(t, testSellerID, event.UserID)
+	messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -2,14 +2,22 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage"
 )
 
+// MaxChatAttachmentSize is the largest image accepted as a chat attachment
+const MaxChatAttachmentSize = 2 * 1024 * 1024
+
 // ChatService handles chat business logic
 type ChatService struct {
 	chatRepo            repository.ChatRepository
@@ -17,6 +25,8 @@ type ChatService struct {
 	tradeRepo           repository.TradeRepository
 	profileService      *ProfileService
 	notificationService *NotificationService
+	redis               *cache.RedisClient
+	storage             storage.Storage
 }
 
 // NewChatService creates a new chat service
@@ -36,6 +46,16 @@ func NewChatService(
 	}
 }
 
+// SetRedis sets the Redis client backing typing indicators, last-seen and read receipts
+func (s *ChatService) SetRedis(redis *cache.RedisClient) {
+	s.redis = redis
+}
+
+// SetStorage sets the storage used for chat attachments
+func (s *ChatService) SetStorage(stor storage.Storage) {
+	s.storage = stor
+}
+
 // getParticipants returns the two participant IDs for a chat
 func (s *ChatService) getParticipants(chat *models.Chat) (string, string) {
 	if chat.IsTradeChat() && chat.Trade != nil {
@@ -78,6 +98,30 @@ func (s *ChatService) GetByID(ctx context.Context, id string, userID string) (*m
 	return chat, nil
 }
 
+// GetByOfferID retrieves the chat opened when an offer was accepted. Returns
+// ErrNotFound if the offer has no chat (not accepted, or doesn't exist).
+func (s *ChatService) GetByOfferID(ctx context.Context, offerID string, userID string) (*models.Chat, error) {
+	chat, err := s.chatRepo.GetByOfferID(ctx, offerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if !s.isParticipant(chat, userID) {
+		return nil, ErrForbidden
+	}
+
+	return chat, nil
+}
+
+// List returns the user's chats. The inbox leaves out archived chats; archived
+// lists only those instead.
+func (s *ChatService) List(ctx context.Context, userID string, archived bool, offset, limit int) ([]*models.Chat, int, error) {
+	return s.chatRepo.ListByParticipant(ctx, userID, archived, offset, limit)
+}
+
 // SendMessage sends a message in a chat
 func (s *ChatService) SendMessage(ctx context.Context, chatID string, senderID string, content string) (*models.Message, error) {
 	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
@@ -89,24 +133,72 @@ func (s *ChatService) SendMessage(ctx context.Context, chatID string, senderID s
 		return nil, ErrForbidden
 	}
 
-	if !s.isChatActive(chat) {
+	if !s.isChatActive(chat) || chat.IsArchived(time.Now()) {
 		return nil, ErrInvalidState
 	}
 
-	participantA, participantB := s.getParticipants(chat)
-
-	message := &models.Message{
+	return s.postMessage(ctx, chat, &models.Message{
 		ID:          uuid.New().String(),
 		ChatID:      chatID,
 		SenderID:    senderID,
 		Content:     content,
 		MessageType: "text",
 		CreatedAt:   time.Now(),
-		// Denormalized for Realtime RLS
-		SellerID: &participantA,
-		BuyerID:  &participantB,
+	})
+}
+
+// SendAttachment uploads an image and sends it as a message in a chat. Accepts the
+// same image types as profile pictures, up to MaxChatAttachmentSize.
+func (s *ChatService) SendAttachment(ctx context.Context, chatID string, senderID string, data []byte, contentType string) (*models.Message, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("storage not configured")
 	}
 
+	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isParticipant(chat, senderID) {
+		return nil, ErrForbidden
+	}
+
+	if !s.isChatActive(chat) || chat.IsArchived(time.Now()) {
+		return nil, ErrInvalidState
+	}
+
+	ext, ok := imageExtension(contentType)
+	if !ok || len(data) == 0 || len(data) > MaxChatAttachmentSize {
+		return nil, ErrInvalidImage
+	}
+
+	messageID := uuid.New().String()
+	storagePath := fmt.Sprintf("chats/%s/%s.%s", chatID, messageID, ext)
+	attachmentURL, err := s.storage.UploadImage(ctx, storagePath, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	return s.postMessage(ctx, chat, &models.Message{
+		ID:             messageID,
+		ChatID:         chatID,
+		SenderID:       senderID,
+		MessageType:    "attachment",
+		AttachmentURL:  &attachmentURL,
+		AttachmentType: &contentType,
+		CreatedAt:      time.Now(),
+	})
+}
+
+// postMessage stores a participant's message and notifies the other participant
+func (s *ChatService) postMessage(ctx context.Context, chat *models.Chat, message *models.Message) (*models.Message, error) {
+	senderID := message.SenderID
+	participantA, participantB := s.getParticipants(chat)
+
+	// Denormalized for Realtime RLS
+	message.SellerID = &participantA
+	message.BuyerID = &participantB
+
 	if err := s.messageRepo.Create(ctx, message); err != nil {
 		return nil, err
 	}
@@ -126,7 +218,8 @@ func (s *ChatService) SendMessage(ctx context.Context, chatID string, senderID s
 		senderName = sender.GetDisplayName()
 	}
 
-	_ = s.notificationService.NotifyNewMessage(ctx, recipientID, chatID, senderName)
+	s.profileService.InvalidateMessageCount(ctx, recipientID)
+	_ = s.notificationService.NotifyNewMessage(ctx, recipientID, chat.ID, senderName)
 
 	return message, nil
 }
@@ -145,6 +238,21 @@ func (s *ChatService) GetMessages(ctx context.Context, chatID string, userID str
 	return s.messageRepo.GetByChatID(ctx, chatID, offset, limit)
 }
 
+// GetMessagesBefore retrieves a page of messages older than beforeMessageID using cursor paging.
+// An empty beforeMessageID returns the most recent page.
+func (s *ChatService) GetMessagesBefore(ctx context.Context, chatID string, userID string, beforeMessageID string, limit int) ([]*models.Message, bool, error) {
+	chat, err := s.chatRepo.GetByIDWithContext(ctx, chatID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !s.isParticipant(chat, userID) {
+		return nil, false, ErrForbidden
+	}
+
+	return s.messageRepo.GetByChatIDBefore(ctx, chatID, beforeMessageID, limit)
+}
+
 // MarkMessagesAsRead marks messages as read
 // If messageIDs is empty, marks all unread messages in the chat as read
 func (s *ChatService) MarkMessagesAsRead(ctx context.Context, chatID string, userID string, messageIDs []string) error {
@@ -159,10 +267,17 @@ func (s *ChatService) MarkMessagesAsRead(ctx context.Context, chatID string, use
 
 	// If no specific messageIDs provided, mark all unread messages in chat
 	if len(messageIDs) == 0 {
-		return s.messageRepo.MarkAllAsReadInChat(ctx, chatID, userID)
+		err = s.messageRepo.MarkAllAsReadInChat(ctx, chatID, userID)
+	} else {
+		err = s.messageRepo.MarkAsRead(ctx, messageIDs, userID)
+	}
+	if err != nil {
+		return err
 	}
 
-	return s.messageRepo.MarkAsRead(ctx, messageIDs, userID)
+	s.profileService.InvalidateMessageCount(ctx, userID)
+	s.publishPresence(ctx, chatID, ChatEventRead, userID)
+	return nil
 }
 
 // ToChatResponse converts a chat model to a DTO response
@@ -173,6 +288,8 @@ func (s *ChatService) ToChatResponse(chat *models.Chat) *dto.ChatResponse {
 		ServiceRunID: chat.GetServiceRunID(),
 		CreatedAt:    chat.CreatedAt,
 		UpdatedAt:    chat.UpdatedAt,
+		Archived:     chat.IsArchived(time.Now()),
+		ArchivedAt:   chat.ArchivedAt,
 	}
 
 	return resp
@@ -181,13 +298,15 @@ func (s *ChatService) ToChatResponse(chat *models.Chat) *dto.ChatResponse {
 // ToMessageResponse converts a message model to a DTO response
 func (s *ChatService) ToMessageResponse(message *models.Message) *dto.MessageResponse {
 	resp := &dto.MessageResponse{
-		ID:          message.ID,
-		ChatID:      message.ChatID,
-		SenderID:    message.SenderID,
-		Content:     message.Content,
-		MessageType: message.MessageType,
-		ReadAt:      message.ReadAt,
-		CreatedAt:   message.CreatedAt,
+		ID:             message.ID,
+		ChatID:         message.ChatID,
+		SenderID:       message.SenderID,
+		Content:        message.Content,
+		MessageType:    message.MessageType,
+		AttachmentURL:  message.AttachmentURL,
+		AttachmentType: message.AttachmentType,
+		ReadAt:         message.ReadAt,
+		CreatedAt:      message.CreatedAt,
 	}
 
 	if message.Sender != nil {
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	storageMocks "github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -72,6 +75,50 @@ func TestChatGetByID_NonParticipant(t *testing.T) {
 	chatRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// GetByOfferID
+// ---------------------------------------------------------------------------
+
+func TestChatGetByOfferID_Participant_Success(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByOfferID", ctx, testOfferID).Return(chat, nil)
+
+	result, err := svc.GetByOfferID(ctx, testOfferID, testBuyerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, testChatID, result.ID)
+}
+
+func TestChatGetByOfferID_NotAccepted(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	chatRepo.On("GetByOfferID", ctx, testOfferID).Return(nil, sql.ErrNoRows)
+
+	_, err := svc.GetByOfferID(ctx, testOfferID, testBuyerID)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChatGetByOfferID_NonParticipant(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByOfferID", ctx, testOfferID).Return(chat, nil)
+
+	_, err := svc.GetByOfferID(ctx, testOfferID, "stranger-999")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
 // ---------------------------------------------------------------------------
 // SendMessage
 // ---------------------------------------------------------------------------
@@ -158,6 +205,80 @@ func TestSendMessage_DenormalizesParticipantIDs(t *testing.T) {
 	messageRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// SendAttachment
+// ---------------------------------------------------------------------------
+
+func TestSendAttachment_Success(t *testing.T) {
+	svc, chatRepo, messageRepo, _, profileRepo, notifRepo := newChatTestService()
+	stor := new(storageMocks.MockStorage)
+	svc.SetStorage(stor)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+	stor.On("UploadImage", ctx, mock.MatchedBy(func(path string) bool {
+		return strings.HasPrefix(path, "chats/"+testChatID+"/") && strings.HasSuffix(path, ".png")
+	}), testPNG, "image/png").Return("https://cdn.example.com/chats/shot.png", nil)
+	messageRepo.On("Create", ctx, mock.AnythingOfType("*models.Message")).Return(nil)
+	profileRepo.On("GetByID", ctx, testBuyerID).Return(testProfile(testBuyerID), nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	msg, err := svc.SendAttachment(ctx, testChatID, testBuyerID, testPNG, "image/png")
+
+	require.NoError(t, err)
+	assert.Equal(t, "attachment", msg.MessageType)
+	require.NotNil(t, msg.AttachmentURL)
+	assert.Equal(t, "https://cdn.example.com/chats/shot.png", *msg.AttachmentURL)
+	require.NotNil(t, msg.AttachmentType)
+	assert.Equal(t, "image/png", *msg.AttachmentType)
+	require.NotNil(t, msg.SellerID)
+	assert.Equal(t, testSellerID, *msg.SellerID)
+
+	resp := svc.ToMessageResponse(msg)
+	assert.Equal(t, msg.AttachmentURL, resp.AttachmentURL)
+
+	stor.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestSendAttachment_NonParticipant(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	stor := new(storageMocks.MockStorage)
+	svc.SetStorage(stor)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+
+	_, err := svc.SendAttachment(ctx, testChatID, "stranger-999", testPNG, "image/png")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	stor.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSendAttachment_RejectsUnsupportedType(t *testing.T) {
+	svc, chatRepo, _, _, _, _ := newChatTestService()
+	stor := new(storageMocks.MockStorage)
+	svc.SetStorage(stor)
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+
+	_, err := svc.SendAttachment(ctx, testChatID, testSellerID, []byte("GIF89a"), "image/gif")
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	stor.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // ---------------------------------------------------------------------------
 // GetMessages
 // ---------------------------------------------------------------------------
@@ -202,6 +323,47 @@ func TestGetMessages_NonParticipant(t *testing.T) {
 	chatRepo.AssertExpectations(t)
 }
 
+func TestGetMessagesBefore_Participant_Success(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	olderMessages := []*models.Message{
+		{ID: "msg-1", ChatID: testChatID, SenderID: testSellerID, Content: "Hello"},
+		{ID: "msg-2", ChatID: testChatID, SenderID: testBuyerID, Content: "Hi there"},
+	}
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+	messageRepo.On("GetByChatIDBefore", ctx, testChatID, testMessageID, 2).Return(olderMessages, true, nil)
+
+	messages, hasMore, err := svc.GetMessagesBefore(ctx, testChatID, testBuyerID, testMessageID, 2)
+
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "msg-1", messages[0].ID)
+
+	chatRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestGetMessagesBefore_NonParticipant(t *testing.T) {
+	svc, chatRepo, messageRepo, _, _, _ := newChatTestService()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	chat := testChatWithTrade(testChatID, trade)
+
+	chatRepo.On("GetByIDWithContext", ctx, testChatID).Return(chat, nil)
+
+	_, _, err := svc.GetMessagesBefore(ctx, testChatID, "stranger-999", "", 20)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	messageRepo.AssertNotCalled(t, "GetByChatIDBefore", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // ---------------------------------------------------------------------------
 // MarkMessagesAsRead
 // ---------------------------------------------------------------------------
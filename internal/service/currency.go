@@ -0,0 +1,127 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// currencySymbols maps lowercase ISO 4217 codes to display symbols.
+// Currencies not listed here are shown with their uppercase code.
+var currencySymbols = map[string]string{
+	"usd": "$",
+	"eur": "€",
+	"brl": "R$",
+	"gbp": "£",
+	"jpy": "¥",
+	"krw": "₩",
+	"inr": "₹",
+	"cad": "CA$",
+	"aud": "A$",
+	"mxn": "MX$",
+}
+
+// zeroDecimalCurrencies are the currencies Stripe bills in whole units, so the
+// stored amount is not in cents and must not be divided by 100
+var zeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true,
+	"krw": true, "mga": true, "pyg": true, "rwf": true, "ugx": true, "vnd": true,
+	"vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+// currencyLocale describes how a locale writes money amounts
+type currencyLocale struct {
+	thousands    string
+	decimal      string
+	symbolBefore bool
+	symbolSpace  bool
+}
+
+var (
+	localeEnglish = currencyLocale{thousands: ",", decimal: ".", symbolBefore: true}
+	localeBrazil  = currencyLocale{thousands: ".", decimal: ",", symbolBefore: true, symbolSpace: true}
+	localeEurope  = currencyLocale{thousands: ".", decimal: ",", symbolSpace: true}
+	localeFrench  = currencyLocale{thousands: " ", decimal: ",", symbolSpace: true}
+)
+
+// currencyLocales maps language tags (and bare languages) to their formatting.
+// Unknown locales fall back to English.
+var currencyLocales = map[string]currencyLocale{
+	"en":    localeEnglish,
+	"pt":    localeBrazil,
+	"pt-br": localeBrazil,
+	"pt-pt": localeEurope,
+	"de":    localeEurope,
+	"es":    localeEurope,
+	"it":    localeEurope,
+	"nl":    localeEurope,
+	"fr":    localeFrench,
+}
+
+func lookupCurrencyLocale(locale string) currencyLocale {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if l, ok := currencyLocales[locale]; ok {
+		return l
+	}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		if l, ok := currencyLocales[lang]; ok {
+			return l
+		}
+	}
+	return localeEnglish
+}
+
+// formatCurrency renders a Stripe amount (in the currency's minor unit) for
+// display, e.g. "$9.99", "R$ 49,90", "9,99 €" or "¥1,000"
+func formatCurrency(amount int, currency string, locale string) string {
+	currency = strings.ToLower(currency)
+	loc := lookupCurrencyLocale(locale)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	var number string
+	if zeroDecimalCurrencies[currency] {
+		number = groupThousands(strconv.Itoa(amount), loc.thousands)
+	} else {
+		number = groupThousands(strconv.Itoa(amount/100), loc.thousands) +
+			loc.decimal + strconv.Itoa(amount%100/10) + strconv.Itoa(amount%10)
+	}
+
+	symbol, ok := currencySymbols[currency]
+	space := loc.symbolSpace
+	if !ok {
+		symbol = strings.ToUpper(currency)
+		space = true
+	}
+
+	sep := ""
+	if space {
+		sep = " "
+	}
+	if loc.symbolBefore {
+		return sign + symbol + sep + number
+	}
+	return sign + number + sep + symbol
+}
+
+// groupThousands inserts sep between every group of three digits
+func groupThousands(digits string, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
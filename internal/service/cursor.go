@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// decodePageCursor turns an opaque cursor into a repository keyset position.
+// An empty cursor starts from the newest row.
+func decodePageCursor(cursor string) (*repository.PageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	createdAt, id, err := dto.DecodeCursor(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.PageCursor{CreatedAt: createdAt, ID: id}, nil
+}
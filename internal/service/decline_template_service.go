@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// MaxDeclineTemplates is how many decline templates a seller can save
+const MaxDeclineTemplates = 20
+
+// DeclineTemplateService handles a seller's saved decline templates
+type DeclineTemplateService struct {
+	repo repository.DeclineTemplateRepository
+}
+
+// NewDeclineTemplateService creates a new decline template service
+func NewDeclineTemplateService(repo repository.DeclineTemplateRepository) *DeclineTemplateService {
+	return &DeclineTemplateService{repo: repo}
+}
+
+// Create saves a new decline template for the seller
+func (s *DeclineTemplateService) Create(ctx context.Context, sellerID string, req *dto.CreateDeclineTemplateRequest) (*models.DeclineTemplate, error) {
+	count, err := s.repo.CountBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxDeclineTemplates {
+		return nil, ErrDeclineTemplateLimitReached
+	}
+
+	now := time.Now()
+	template := &models.DeclineTemplate{
+		ID:        uuid.New().String(),
+		SellerID:  sellerID,
+		Name:      req.Name,
+		Message:   req.Message,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// List returns the seller's decline templates, oldest first
+func (s *DeclineTemplateService) List(ctx context.Context, sellerID string) ([]*models.DeclineTemplate, error) {
+	return s.repo.ListBySellerID(ctx, sellerID)
+}
+
+// Update changes the name or message of a seller's decline template
+func (s *DeclineTemplateService) Update(ctx context.Context, id string, sellerID string, req *dto.UpdateDeclineTemplateRequest) (*models.DeclineTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.SellerID != sellerID {
+		return nil, ErrForbidden
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Message != nil {
+		template.Message = *req.Message
+	}
+
+	if err := s.repo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// Delete removes a seller's decline template. Offers already rejected with it keep their note.
+func (s *DeclineTemplateService) Delete(ctx context.Context, id string, sellerID string) error {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if template.SellerID != sellerID {
+		return ErrForbidden
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// ToResponse converts a decline template to its DTO
+func (s *DeclineTemplateService) ToResponse(template *models.DeclineTemplate) *dto.DeclineTemplateResponse {
+	return &dto.DeclineTemplateResponse{
+		ID:        template.ID,
+		Name:      template.Name,
+		Message:   template.Message,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testDeclineTemplateID = "11111111-2222-3333-4444-555555555555"
+
+// ---------- helpers ----------
+
+func newDeclineTemplateTestService() (*DeclineTemplateService, *mocks.MockDeclineTemplateRepository) {
+	repo := new(mocks.MockDeclineTemplateRepository)
+	return NewDeclineTemplateService(repo), repo
+}
+
+func testDeclineTemplate(id, sellerID string) *models.DeclineTemplate {
+	return &models.DeclineTemplate{
+		ID:       id,
+		SellerID: sellerID,
+		Name:     "Lowball",
+		Message:  "Too low, looking for at least a Ber",
+	}
+}
+
+// ---------- Create ----------
+
+func TestDeclineTemplateCreate_Success(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("CountBySellerID", ctx, testSellerID).Return(3, nil)
+	repo.On("Create", ctx, mock.AnythingOfType("*models.DeclineTemplate")).Return(nil)
+
+	template, err := svc.Create(ctx, testSellerID, &dto.CreateDeclineTemplateRequest{
+		Name:    "Lowball",
+		Message: "Too low",
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, template.ID)
+	assert.Equal(t, testSellerID, template.SellerID)
+	assert.Equal(t, "Too low", template.Message)
+}
+
+func TestDeclineTemplateCreate_LimitReached(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("CountBySellerID", ctx, testSellerID).Return(MaxDeclineTemplates, nil)
+
+	_, err := svc.Create(ctx, testSellerID, &dto.CreateDeclineTemplateRequest{Name: "x", Message: "y"})
+
+	assert.ErrorIs(t, err, ErrDeclineTemplateLimitReached)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ---------- Update / Delete ----------
+
+func TestDeclineTemplateUpdate_AppliesFields(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testDeclineTemplateID).Return(testDeclineTemplate(testDeclineTemplateID, testSellerID), nil)
+	repo.On("Update", ctx, mock.AnythingOfType("*models.DeclineTemplate")).Return(nil)
+
+	template, err := svc.Update(ctx, testDeclineTemplateID, testSellerID, &dto.UpdateDeclineTemplateRequest{
+		Message: strPtr("Sold elsewhere"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Lowball", template.Name)
+	assert.Equal(t, "Sold elsewhere", template.Message)
+}
+
+func TestDeclineTemplateUpdate_NotOwner(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testDeclineTemplateID).Return(testDeclineTemplate(testDeclineTemplateID, testSellerID), nil)
+
+	_, err := svc.Update(ctx, testDeclineTemplateID, testBuyerID, &dto.UpdateDeclineTemplateRequest{Name: strPtr("mine")})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeclineTemplateDelete_NotOwner(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testDeclineTemplateID).Return(testDeclineTemplate(testDeclineTemplateID, testSellerID), nil)
+
+	err := svc.Delete(ctx, testDeclineTemplateID, testBuyerID)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeclineTemplateDelete_NotFound(t *testing.T) {
+	svc, repo := newDeclineTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testDeclineTemplateID).Return(nil, sql.ErrNoRows)
+
+	err := svc.Delete(ctx, testDeclineTemplateID, testSellerID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
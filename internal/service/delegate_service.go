@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// MaxDelegates is how many delegates an owner can grant permissions to
+const MaxDelegates = 5
+
+// DelegateService handles the permissions owners grant to their delegates
+type DelegateService struct {
+	repo           repository.DelegateRepository
+	profileService *ProfileService
+}
+
+// NewDelegateService creates a new delegate service
+func NewDelegateService(repo repository.DelegateRepository, profileService *ProfileService) *DelegateService {
+	return &DelegateService{repo: repo, profileService: profileService}
+}
+
+// List returns the owner's delegates, oldest grant first
+func (s *DelegateService) List(ctx context.Context, ownerID string) ([]*models.Delegate, error) {
+	return s.repo.ListByOwnerID(ctx, ownerID)
+}
+
+// Grant gives delegateID exactly the listed permissions over the owner's shop, replacing
+// what they held before. The delegate must have a profile.
+func (s *DelegateService) Grant(ctx context.Context, ownerID, delegateID string, permissions []string) (*models.Delegate, error) {
+	if ownerID == delegateID {
+		return nil, ErrSelfAction
+	}
+
+	granted := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !slices.Contains(models.DelegatePermissions, permission) {
+			return nil, ValidationErrors{"permissions": "unknown permission " + permission}
+		}
+		if !slices.Contains(granted, permission) {
+			granted = append(granted, permission)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, ValidationErrors{"permissions": "must grant at least one permission"}
+	}
+	slices.Sort(granted)
+
+	if _, err := s.profileService.GetByID(ctx, delegateID); err != nil {
+		return nil, err
+	}
+
+	// Changing an existing grant doesn't count against the limit
+	_, err := s.repo.GetByOwnerAndDelegate(ctx, ownerID, delegateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		delegates, err := s.repo.ListByOwnerID(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(delegates) >= MaxDelegates {
+			return nil, ErrDelegateLimitReached
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delegate := &models.Delegate{
+		OwnerID:     ownerID,
+		DelegateID:  delegateID,
+		Permissions: granted,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Upsert(ctx, delegate); err != nil {
+		return nil, err
+	}
+
+	return delegate, nil
+}
+
+// Revoke removes every permission the owner granted to delegateID
+func (s *DelegateService) Revoke(ctx context.Context, ownerID, delegateID string) error {
+	if _, err := s.repo.GetByOwnerAndDelegate(ctx, ownerID, delegateID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, ownerID, delegateID)
+}
+
+// ToResponse converts a delegate grant to its DTO
+func (s *DelegateService) ToResponse(delegate *models.Delegate) *dto.DelegateResponse {
+	return &dto.DelegateResponse{
+		DelegateID:  delegate.DelegateID,
+		Permissions: delegate.Permissions,
+		CreatedAt:   delegate.CreatedAt,
+		UpdatedAt:   delegate.UpdatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDelegateTestService() (*DelegateService, *mocks.MockDelegateRepository, *mocks.MockProfileRepository) {
+	repo := new(mocks.MockDelegateRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	return NewDelegateService(repo, NewProfileService(profileRepo, nil, nil)), repo, profileRepo
+}
+
+func TestDelegateGrant_NewDelegate(t *testing.T) {
+	svc, repo, profileRepo := newDelegateTestService()
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+	repo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).Return(nil, sql.ErrNoRows)
+	repo.On("ListByOwnerID", ctx, testSellerID).Return([]*models.Delegate{}, nil)
+	repo.On("Upsert", ctx, mock.AnythingOfType("*models.Delegate")).Return(nil)
+
+	delegate, err := svc.Grant(ctx, testSellerID, testUserID, []string{
+		models.DelegatePermissionRespondOffers,
+		models.DelegatePermissionManageListings,
+		models.DelegatePermissionRespondOffers,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.DelegatePermissionManageListings, models.DelegatePermissionRespondOffers}, delegate.Permissions)
+}
+
+func TestDelegateGrant_Rejections(t *testing.T) {
+	svc, repo, profileRepo := newDelegateTestService()
+	ctx := context.Background()
+
+	_, err := svc.Grant(ctx, testSellerID, testSellerID, []string{models.DelegatePermissionManageListings})
+	assert.ErrorIs(t, err, ErrSelfAction)
+
+	_, err = svc.Grant(ctx, testSellerID, testUserID, []string{"delete_account"})
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "permissions")
+
+	full := make([]*models.Delegate, MaxDelegates)
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+	repo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).Return(nil, sql.ErrNoRows)
+	repo.On("ListByOwnerID", ctx, testSellerID).Return(full, nil)
+
+	_, err = svc.Grant(ctx, testSellerID, testUserID, []string{models.DelegatePermissionManageListings})
+	assert.ErrorIs(t, err, ErrDelegateLimitReached)
+	repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestDelegateRevoke(t *testing.T) {
+	svc, repo, _ := newDelegateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByOwnerAndDelegate", ctx, testSellerID, testClientID).Return(nil, sql.ErrNoRows)
+	repo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).
+		Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testUserID}, nil)
+	repo.On("Delete", ctx, testSellerID, testUserID).Return(nil)
+
+	assert.ErrorIs(t, svc.Revoke(ctx, testSellerID, testClientID), sql.ErrNoRows)
+	require.NoError(t, svc.Revoke(ctx, testSellerID, testUserID))
+	repo.AssertNumberOfCalls(t, "Delete", 1)
+}
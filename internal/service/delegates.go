@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// canActFor reports whether actingUserID may act for ownerID: either they are the owner
+// or the owner granted them permission as a delegate. Delegate actions are logged with
+// both user IDs so they can be attributed. A nil repo means only owners may act.
+func canActFor(ctx context.Context, repo repository.DelegateRepository, ownerID, actingUserID, permission string, attrs ...any) bool {
+	if actingUserID == "" {
+		return false
+	}
+	if ownerID == actingUserID {
+		return true
+	}
+	if repo == nil {
+		return false
+	}
+
+	log := logger.FromContext(ctx)
+	delegate, err := repo.GetByOwnerAndDelegate(ctx, ownerID, actingUserID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Error("failed to look up delegate", "error", err.Error(), "owner_id", ownerID, "delegate_id", actingUserID)
+		}
+		return false
+	}
+	if !delegate.Can(permission) {
+		return false
+	}
+
+	log.Info("delegate acting for owner",
+		append([]any{"owner_id", ownerID, "delegate_id", actingUserID, "permission", permission}, attrs...)...,
+	)
+	return true
+}
+
+// isDelegateOf reports whether userID holds any grant from ownerID. Lookup failures
+// count as a grant so callers that use it to block an action fail closed.
+func isDelegateOf(ctx context.Context, repo repository.DelegateRepository, ownerID, userID string) bool {
+	if repo == nil || ownerID == userID {
+		return false
+	}
+	_, err := repo.GetByOwnerAndDelegate(ctx, ownerID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to look up delegate", "error", err.Error(), "owner_id", ownerID, "delegate_id", userID)
+	}
+	return true
+}
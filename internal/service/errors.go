@@ -24,6 +24,93 @@ var (
 	// ErrInvalidPriceID indicates the provided Stripe price ID is not allowed
 	ErrInvalidPriceID = errors.New("invalid price ID")
 
+	// ErrAlreadyPremium indicates the gift recipient already has premium and gifts can't extend it
+	ErrAlreadyPremium = errors.New("recipient already has premium")
+
 	// ErrRefreshCooldown indicates the listing cannot be refreshed yet
 	ErrRefreshCooldown = errors.New("refresh cooldown not elapsed")
+
+	// ErrRenewTooEarly indicates an active listing isn't close enough to expiring to be renewed
+	ErrRenewTooEarly = errors.New("listing not expiring soon")
+
+	// ErrQuotaExceeded indicates the user has no listing bumps left today and no bump credits
+	ErrQuotaExceeded = errors.New("bump quota exceeded")
+
+	// ErrEmailNotVerified indicates the user must verify their email before this action
+	ErrEmailNotVerified = errors.New("email not verified")
+
+	// ErrInvalidAudience indicates an unknown announcement audience
+	ErrInvalidAudience = errors.New("invalid audience")
+
+	// ErrInvalidReservation indicates a reservation end time that is in the past or too far out
+	ErrInvalidReservation = errors.New("invalid reservation period")
+
+	// ErrInvalidImage indicates an image that is too large or not a PNG, JPEG or WebP
+	ErrInvalidImage = errors.New("invalid image")
+
+	// ErrInvalidImageURL indicates an image URL that isn't http(s) or points at an internal address
+	ErrInvalidImageURL = errors.New("invalid image url")
+
+	// ErrImageFetchFailed indicates the remote image couldn't be downloaded
+	ErrImageFetchFailed = errors.New("image fetch failed")
+
+	// ErrDeclineTemplateLimitReached indicates the seller already has the maximum number of decline templates
+	ErrDeclineTemplateLimitReached = errors.New("decline template limit reached")
+
+	// ErrOfferTemplateLimitReached indicates the user already has the maximum number of offer templates
+	ErrOfferTemplateLimitReached = errors.New("offer template limit reached")
+
+	// ErrServiceLimitReached indicates the provider already has the maximum number of active services
+	ErrServiceLimitReached = errors.New("service limit reached")
+
+	// ErrWatchLimitReached indicates a free user already has the maximum number of item watches
+	ErrWatchLimitReached = errors.New("watch limit reached")
+
+	// ErrOfferQueueFull indicates the listing already has as many pending offers as it accepts
+	ErrOfferQueueFull = errors.New("offer queue full")
+
+	// ErrListingChanged indicates the listing's item was edited after the offer was made
+	ErrListingChanged = errors.New("listing changed since offer was made")
+
+	// ErrInvalidConfirmation indicates a missing, wrong or expired trade completion token
+	ErrInvalidConfirmation = errors.New("invalid confirmation token")
+
+	// ErrStreamUnavailable indicates live notification streaming isn't available
+	ErrStreamUnavailable = errors.New("notification stream unavailable")
+
+	// ErrInvalidRegion indicates a region outside the game's region set
+	ErrInvalidRegion = errors.New("invalid region")
+
+	// ErrInvalidCachePattern indicates a cache purge pattern outside the allow-list
+	ErrInvalidCachePattern = errors.New("invalid cache pattern")
+
+	// ErrInvalidCursor indicates a paging cursor that wasn't issued by this API
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrRequestInProgress indicates a request with the same idempotency key is still being processed
+	ErrRequestInProgress = errors.New("request already in progress")
+
+	// ErrDisputeOpen indicates the trade or service run has a dispute waiting for an admin
+	ErrDisputeOpen = errors.New("dispute open")
+
+	// ErrAPITokenLimitReached indicates the user already has the maximum number of active API tokens
+	ErrAPITokenLimitReached = errors.New("api token limit reached")
+
+	// ErrInvalidAPIToken indicates an API token that is unknown, malformed or revoked
+	ErrInvalidAPIToken = errors.New("invalid api token")
+
+	// ErrConfirmationUnavailable indicates trade completion tokens can't be issued because Redis is down
+	ErrConfirmationUnavailable = errors.New("trade confirmation unavailable")
+
+	// ErrDelegateLimitReached indicates the owner already has the maximum number of delegates
+	ErrDelegateLimitReached = errors.New("delegate limit reached")
+
+	// ErrExportTooLarge indicates an export has more rows than can be streamed in one response
+	ErrExportTooLarge = errors.New("export too large")
+
+	// ErrRateLimited indicates the caller used up its request allowance for the current window
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrReputationTooLow indicates the user doesn't meet a listing's or service's buyer requirements
+	ErrReputationTooLow = errors.New("reputation too low")
 )
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// favoritesCacheTTL bounds how stale a cached favorites page can get when one of the
+// listings on it changes; adding or removing a favorite drops the pages right away
+const favoritesCacheTTL = 2 * time.Minute
+
+// FavoriteService handles buyers' favorite (bookmarked) listings
+type FavoriteService struct {
+	repo           repository.FavoriteRepository
+	listingRepo    repository.ListingRepository
+	listingService *ListingService
+	redis          *cache.RedisClient
+	invalidator    *cache.Invalidator
+}
+
+// NewFavoriteService creates a new favorite service
+func NewFavoriteService(repo repository.FavoriteRepository, listingRepo repository.ListingRepository, listingService *ListingService, redis *cache.RedisClient) *FavoriteService {
+	return &FavoriteService{
+		repo:           repo,
+		listingRepo:    listingRepo,
+		listingService: listingService,
+		redis:          redis,
+		invalidator:    cache.NewInvalidator(redis),
+	}
+}
+
+// favoritesPage is what a cached page of favorites holds
+type favoritesPage struct {
+	Items []dto.ListingCardResponse `json:"items"`
+	Count int                       `json:"count"`
+}
+
+// Add favorites an active listing. Favoriting a listing twice is a no-op.
+func (s *FavoriteService) Add(ctx context.Context, userID, listingID string) error {
+	listing, err := s.listingRepo.GetByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if !listing.IsActive() {
+		return ErrInvalidState
+	}
+
+	favorite := &models.Favorite{
+		UserID:    userID,
+		ListingID: listingID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Add(ctx, favorite); err != nil {
+		return err
+	}
+	_ = s.invalidator.InvalidateFavorites(ctx, userID)
+	return nil
+}
+
+// Remove unfavorites a listing. Removing one that isn't a favorite is a no-op.
+func (s *FavoriteService) Remove(ctx context.Context, userID, listingID string) error {
+	if err := s.repo.Remove(ctx, userID, listingID); err != nil {
+		return err
+	}
+	_ = s.invalidator.InvalidateFavorites(ctx, userID)
+	return nil
+}
+
+// List returns a page of the user's favorite listings that are still active as cards,
+// most recently favorited first, plus the total. Pages are cached per user.
+func (s *FavoriteService) List(ctx context.Context, userID string, offset, limit int) ([]dto.ListingCardResponse, int, error) {
+	key := cache.FavoritesKey(userID)
+	field := fmt.Sprintf("%d:%d", offset, limit)
+	if cached, err := s.redis.HGet(ctx, key, field); err == nil && cached != "" {
+		var page favoritesPage
+		if json.Unmarshal([]byte(cached), &page) == nil {
+			return page.Items, page.Count, nil
+		}
+	}
+
+	listings, count, err := s.repo.ListListings(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page := favoritesPage{Items: make([]dto.ListingCardResponse, 0, len(listings)), Count: count}
+	for _, listing := range listings {
+		page.Items = append(page.Items, *s.listingService.ToCardResponse(listing))
+	}
+
+	if data, err := json.Marshal(page); err == nil {
+		_ = s.redis.HSet(ctx, key, field, string(data), favoritesCacheTTL)
+	}
+
+	return page.Items, page.Count, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func newFavoriteTestService(redis *cache.RedisClient) (*FavoriteService, *mocks.MockFavoriteRepository, *mocks.MockListingRepository) {
+	favoriteRepo := new(mocks.MockFavoriteRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	profileService := NewProfileService(new(mocks.MockProfileRepository), redis, nil)
+	listingService := NewListingService(listingRepo, profileService, redis)
+
+	return NewFavoriteService(favoriteRepo, listingRepo, listingService, redis), favoriteRepo, listingRepo
+}
+
+func TestFavoriteAdd_InactiveListing(t *testing.T) {
+	svc, favoriteRepo, listingRepo := newFavoriteTestService(nil)
+	ctx := context.Background()
+
+	listingRepo.On("GetByID", ctx, testListingID).Return(testListing(testListingID, testSellerID, withListingStatus("completed")), nil)
+
+	err := svc.Add(ctx, testUserID, testListingID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	favoriteRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestFavoriteAdd_TwiceIsIdempotent(t *testing.T) {
+	svc, favoriteRepo, listingRepo := newFavoriteTestService(nil)
+	ctx := context.Background()
+
+	listingRepo.On("GetByID", ctx, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	favoriteRepo.On("Add", ctx, mock.MatchedBy(func(f *models.Favorite) bool {
+		return f.UserID == testUserID && f.ListingID == testListingID
+	})).Return(nil)
+
+	require.NoError(t, svc.Add(ctx, testUserID, testListingID))
+	require.NoError(t, svc.Add(ctx, testUserID, testListingID))
+	favoriteRepo.AssertNumberOfCalls(t, "Add", 2)
+}
+
+func TestFavoriteList_CachedUntilFavoritesChange(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	svc, favoriteRepo, listingRepo := newFavoriteTestService(redis)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Seller = testProfile(testSellerID)
+	favoriteRepo.On("ListListings", ctx, testUserID, 0, 20).Return([]*models.Listing{listing}, 1, nil).Twice()
+
+	items, count, err := svc.List(ctx, testUserID, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, items, 1)
+	assert.Equal(t, testListingID, items[0].ID)
+	require.NotNil(t, items[0].Seller)
+
+	// Served from the cache
+	items, _, err = svc.List(ctx, testUserID, 0, 20)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+	favoriteRepo.AssertNumberOfCalls(t, "ListListings", 1)
+
+	favoriteRepo.On("Remove", ctx, testUserID, "listing-other").Return(nil)
+	require.NoError(t, svc.Remove(ctx, testUserID, "listing-other"))
+	assert.False(t, mr.Exists(cache.FavoritesKey(testUserID)))
+
+	_, _, err = svc.List(ctx, testUserID, 0, 20)
+	require.NoError(t, err)
+	favoriteRepo.AssertNumberOfCalls(t, "ListListings", 2)
+	listingRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
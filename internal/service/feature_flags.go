@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature flags consulted by services
+const (
+	// FlagFuzzySearch lets listing searches that find nothing retry with trigram similarity
+	FlagFuzzySearch = "fuzzy_search"
+)
+
+// DefaultFeatureFlags is the rollout used when FEATURE_FLAGS is unset
+const DefaultFeatureFlags = FlagFuzzySearch + "=100"
+
+// FeatureFlagService decides which features are on for a user. Each flag has a
+// rollout percentage from config; users are bucketed by a stable hash of flag and
+// user ID, so the same users stay in as the percentage grows. Anonymous users only
+// get fully rolled out flags. A profile's FeatureFlags overrides the rollout for
+// that user, and is read through the cached profile.
+type FeatureFlagService struct {
+	rollouts       map[string]int
+	profileService *ProfileService
+}
+
+// NewFeatureFlagService creates a flag service from parsed rollout percentages
+func NewFeatureFlagService(rollouts map[string]int, profileService *ProfileService) *FeatureFlagService {
+	return &FeatureFlagService{rollouts: rollouts, profileService: profileService}
+}
+
+// ParseFeatureFlags parses a comma-separated rollout spec such as
+// "fuzzy_search=100,realtime_chat=25". Values are percentages; on and off mean
+// 100 and 0, and a bare flag name is on.
+func ParseFeatureFlags(spec string) (map[string]int, error) {
+	rollouts := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		value = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%")))
+		if name == "" {
+			return nil, fmt.Errorf("feature flag %q has no name", entry)
+		}
+
+		switch {
+		case !hasValue || value == "on" || value == "true":
+			rollouts[name] = 100
+		case value == "off" || value == "false":
+			rollouts[name] = 0
+		default:
+			pct, err := strconv.Atoi(value)
+			if err != nil || pct < 0 || pct > 100 {
+				return nil, fmt.Errorf("feature flag %q needs a percentage between 0 and 100", name)
+			}
+			rollouts[name] = pct
+		}
+	}
+	return rollouts, nil
+}
+
+// IsEnabled reports whether flag is on for userID (empty for anonymous requests).
+// Unknown flags are off unless the user's profile turns them on.
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, userID string, flag string) bool {
+	if on, ok := s.override(ctx, userID, flag); ok {
+		return on
+	}
+	return s.inRollout(userID, flag)
+}
+
+// Enabled returns the names of every flag that is on for userID, sorted
+func (s *FeatureFlagService) Enabled(ctx context.Context, userID string) []string {
+	overrides := s.overrides(ctx, userID)
+
+	names := make(map[string]bool, len(s.rollouts)+len(overrides))
+	for flag := range s.rollouts {
+		names[flag] = true
+	}
+	for flag := range overrides {
+		names[flag] = true
+	}
+
+	enabled := make([]string, 0, len(names))
+	for flag := range names {
+		on, ok := overrides[flag]
+		if !ok {
+			on = s.inRollout(userID, flag)
+		}
+		if on {
+			enabled = append(enabled, flag)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
+// inRollout reports whether the user falls inside the flag's rollout percentage
+func (s *FeatureFlagService) inRollout(userID string, flag string) bool {
+	pct := s.rollouts[flag]
+	if pct >= 100 {
+		return true
+	}
+	if pct <= 0 || userID == "" {
+		return false
+	}
+	return rolloutBucket(flag, userID) < pct
+}
+
+// override returns the user's profile override for flag, if any
+func (s *FeatureFlagService) override(ctx context.Context, userID string, flag string) (bool, bool) {
+	on, ok := s.overrides(ctx, userID)[flag]
+	return on, ok
+}
+
+// overrides loads the user's per-flag overrides. Lookup failures fall back to
+// the config rollout rather than failing the request.
+func (s *FeatureFlagService) overrides(ctx context.Context, userID string) map[string]bool {
+	if userID == "" || s.profileService == nil {
+		return nil
+	}
+	profile, err := s.profileService.GetByID(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return profile.FeatureFlags
+}
+
+// rolloutBucket maps a user to a stable bucket in [0, 100) for a flag
+func rolloutBucket(flag, userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag + ":" + userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeatureFlags(t *testing.T) {
+	rollouts, err := ParseFeatureFlags(" fuzzy_search=100, realtime_chat = 25% ,beta, legacy=off,,")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"fuzzy_search":  100,
+		"realtime_chat": 25,
+		"beta":          100,
+		"legacy":        0,
+	}, rollouts)
+}
+
+func TestParseFeatureFlags_Invalid(t *testing.T) {
+	for _, spec := range []string{"=50", "chat=150", "chat=-1", "chat=half"} {
+		_, err := ParseFeatureFlags(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestFeatureFlags_Rollout(t *testing.T) {
+	flags := NewFeatureFlagService(map[string]int{"on": 100, "off": 0, "half": 50}, nil)
+	ctx := context.Background()
+
+	assert.True(t, flags.IsEnabled(ctx, testUserID, "on"))
+	assert.True(t, flags.IsEnabled(ctx, "", "on"), "fully rolled out flags reach anonymous users")
+	assert.False(t, flags.IsEnabled(ctx, testUserID, "off"))
+	assert.False(t, flags.IsEnabled(ctx, testUserID, "unknown"))
+	assert.False(t, flags.IsEnabled(ctx, "", "half"), "partial rollouts skip anonymous users")
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		first := flags.IsEnabled(ctx, userID, "half")
+		assert.Equal(t, first, flags.IsEnabled(ctx, userID, "half"), "bucketing is stable")
+		if first {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 500, enabled, 100)
+}
+
+func TestFeatureFlags_ProfileOverride(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	flags := NewFeatureFlagService(map[string]int{FlagFuzzySearch: 100}, NewProfileService(profileRepo, nil, nil))
+	ctx := context.Background()
+
+	profile := testProfile(testUserID)
+	profile.FeatureFlags = map[string]bool{FlagFuzzySearch: false, "realtime_chat": true}
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+
+	assert.False(t, flags.IsEnabled(ctx, testUserID, FlagFuzzySearch))
+	assert.True(t, flags.IsEnabled(ctx, testUserID, "realtime_chat"))
+	assert.Equal(t, []string{"realtime_chat"}, flags.Enabled(ctx, testUserID))
+}
+
+func TestFeatureFlags_ProfileLookupFailureUsesRollout(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	flags := NewFeatureFlagService(map[string]int{FlagFuzzySearch: 100}, NewProfileService(profileRepo, nil, nil))
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(nil, errors.New("db down"))
+
+	assert.True(t, flags.IsEnabled(ctx, testUserID, FlagFuzzySearch))
+	assert.Equal(t, []string{FlagFuzzySearch}, flags.Enabled(ctx, testUserID))
+}
+
+func TestList_NoFuzzyFallbackWhenFlagOff(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetFeatureFlags(NewFeatureFlagService(map[string]int{FlagFuzzySearch: 0}, nil))
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.Query == "enigme" && !f.Fuzzy
+	})).Return([]*models.Listing{}, 0, nil).Once()
+
+	listings, count, fuzzy, err := svc.List(context.Background(), &dto.ListingFilterRequest{Q: "enigme"}, "")
+
+	require.NoError(t, err)
+	assert.False(t, fuzzy)
+	assert.Zero(t, count)
+	assert.Empty(t, listings)
+	listingRepo.AssertNumberOfCalls(t, "List", 1)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+)
+
+// GamesService answers game catalog lookups
+type GamesService struct {
+	registry     *games.Registry
+	supabaseURL  string
+	imageChecker *ItemImageChecker
+}
+
+// NewGamesService creates a new games service
+func NewGamesService(registry *games.Registry, supabaseURL string) *GamesService {
+	return &GamesService{
+		registry:    registry,
+		supabaseURL: supabaseURL,
+	}
+}
+
+// SetImageChecker sets the checker that swaps missing item images for a placeholder
+func (s *GamesService) SetImageChecker(checker *ItemImageChecker) {
+	s.imageChecker = checker
+}
+
+// ResolveItem maps a user-entered item name to its canonical catalog entry.
+// Unmatched names come back with Found=false rather than a guess, and fuzzy
+// matches are flagged LowConfidence. Unknown games return ErrNotFound.
+func (s *GamesService) ResolveItem(ctx context.Context, game, rawName string) (*dto.CanonicalItem, error) {
+	query := strings.TrimSpace(rawName)
+	if query == "" {
+		return nil, ValidationErrors{"name": "is required"}
+	}
+
+	match, err := s.registry.ResolveItem(game, query)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	resp := &dto.CanonicalItem{Query: query}
+	if !match.Found {
+		return resp, nil
+	}
+
+	resp.Found = true
+	resp.LowConfidence = !match.Exact
+	resp.Name = match.Item.Name
+	resp.Type = match.Item.Type
+	resp.Category = match.Item.Category
+	resp.Rarity = match.Item.Rarity
+	resp.ImageURL = s.imageChecker.Resolve(itemImageURL(s.supabaseURL, match.Item.Name, match.Item.Type))
+	return resp, nil
+}
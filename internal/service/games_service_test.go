@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGamesServiceResolveItem_Exact(t *testing.T) {
+	svc := NewGamesService(newTestGameRegistry(), "https://storage.example")
+
+	item, err := svc.ResolveItem(context.Background(), "diablo2", " ber rune ")
+
+	require.NoError(t, err)
+	assert.Equal(t, "ber rune", item.Query)
+	assert.True(t, item.Found)
+	assert.False(t, item.LowConfidence)
+	assert.Equal(t, "Ber", item.Name)
+	assert.Equal(t, "rune", item.Type)
+	assert.Equal(t, "rune", item.Category)
+	assert.Equal(t, "normal", item.Rarity)
+	assert.Equal(t, "https://storage.example/storage/v1/object/public/d2-items/runes/ber.png", item.ImageURL)
+}
+
+func TestGamesServiceResolveItem_FuzzyIsLowConfidence(t *testing.T) {
+	svc := NewGamesService(newTestGameRegistry(), "")
+
+	item, err := svc.ResolveItem(context.Background(), "diablo2", "Flawless Saphire")
+
+	require.NoError(t, err)
+	assert.True(t, item.Found)
+	assert.True(t, item.LowConfidence)
+	assert.Equal(t, "Flawless Sapphire", item.Name)
+	assert.Empty(t, item.ImageURL)
+}
+
+func TestGamesServiceResolveItem_NotFound(t *testing.T) {
+	svc := NewGamesService(newTestGameRegistry(), "https://storage.example")
+
+	item, err := svc.ResolveItem(context.Background(), "diablo2", "Windforce")
+
+	require.NoError(t, err)
+	assert.False(t, item.Found)
+	assert.Equal(t, "Windforce", item.Query)
+	assert.Empty(t, item.Name)
+	assert.Empty(t, item.ImageURL)
+}
+
+func TestGamesServiceResolveItem_Errors(t *testing.T) {
+	svc := NewGamesService(newTestGameRegistry(), "")
+
+	_, err := svc.ResolveItem(context.Background(), "diablo2", "   ")
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "name")
+
+	_, err = svc.ResolveItem(context.Background(), "poe", "Ber")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
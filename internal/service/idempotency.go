@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+)
+
+const (
+	// idempotencyTTL is how long a request ID keeps pointing at the resource it created
+	idempotencyTTL = 10 * time.Minute
+	// idempotencyPending marks a request ID whose create is still running
+	idempotencyPending = "pending"
+)
+
+// createIdempotent runs create at most once per (scope, user, requestID) within idempotencyTTL.
+// A repeated request returns the resource created by the first one via lookup.
+// Without a request ID or Redis, create simply runs.
+func createIdempotent[T any](
+	ctx context.Context,
+	redis *cache.RedisClient,
+	scope, userID, requestID string,
+	create func() (T, error),
+	idOf func(T) string,
+	lookup func(id string) (T, error),
+) (T, error) {
+	if requestID == "" || !redis.IsAvailable() {
+		return create()
+	}
+
+	var zero T
+	key := cache.IdempotencyKey(scope, userID, requestID)
+
+	acquired, err := redis.SetNX(ctx, key, idempotencyPending, idempotencyTTL)
+	if err != nil {
+		// Redis trouble shouldn't block creation
+		return create()
+	}
+	if !acquired {
+		existingID, err := redis.Get(ctx, key)
+		if err != nil || existingID == idempotencyPending {
+			return zero, ErrRequestInProgress
+		}
+		return lookup(existingID)
+	}
+
+	result, err := create()
+	if err != nil {
+		_ = redis.Del(ctx, key)
+		return zero, err
+	}
+
+	_ = redis.Set(ctx, key, idOf(result), idempotencyTTL)
+	return result, nil
+}
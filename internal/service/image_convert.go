@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// maxConvertPixels caps the images convertToWebP decodes, so a small upload that
+// expands into a huge bitmap can't exhaust memory. Bigger images are kept as uploaded.
+const maxConvertPixels = 4096 * 4096
+
+// convertToWebP re-encodes a PNG or JPEG upload as lossless WebP. WebP input is
+// returned as is, and anything that is too large, fails to decode or encode, or
+// would come out bigger than the original (lossless WebP often loses to a JPEG) is
+// kept in its original format so a conversion problem never fails the upload.
+func convertToWebP(data []byte, contentType string) ([]byte, string) {
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return data, contentType
+	}
+
+	// The header gives the dimensions without decoding the pixels
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > maxConvertPixels {
+		return data, contentType
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data, contentType
+	}
+
+	var buf bytes.Buffer
+	if err := nativewebp.Encode(&buf, img, nil); err != nil {
+		return data, contentType
+	}
+	if buf.Len() >= len(data) {
+		return data, contentType
+	}
+	return buf.Bytes(), "image/webp"
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodedPNG returns a decodable PNG, unlike testPNG which only sniffs as one. It is
+// stored uncompressed so the WebP version is always smaller.
+func encodedPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for x := 0; x < 32; x++ {
+		img.Set(x, x, color.NRGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	require.NoError(t, encoder.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestConvertToWebP_ConvertsPNG(t *testing.T) {
+	data, contentType := convertToWebP(encodedPNG(t), "image/png")
+
+	assert.Equal(t, "image/webp", contentType)
+	assert.Equal(t, "image/webp", http.DetectContentType(data))
+}
+
+func TestConvertToWebP_UndecodableKeepsOriginal(t *testing.T) {
+	data, contentType := convertToWebP(testPNG, "image/png")
+
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, testPNG, data)
+}
+
+func TestConvertToWebP_WebPPassesThrough(t *testing.T) {
+	webp, _ := convertToWebP(encodedPNG(t), "image/png")
+
+	data, contentType := convertToWebP(webp, "image/webp")
+
+	assert.Equal(t, "image/webp", contentType)
+	assert.Equal(t, webp, data)
+}
+
+func TestConvertToWebP_TooManyPixelsKeepsOriginal(t *testing.T) {
+	// Compresses to a few KB but would decode to more than maxConvertPixels
+	img := image.NewGray(image.Rect(0, 0, 4097, 4097))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	data, contentType := convertToWebP(buf.Bytes(), "image/png")
+
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, buf.Bytes(), data)
+}
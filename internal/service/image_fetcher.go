@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// MaxListingImageSize caps both uploaded and fetched listing images
+	MaxListingImageSize = 2 * 1024 * 1024
+	// imageFetchTimeout bounds the whole remote fetch, including redirects
+	imageFetchTimeout = 10 * time.Second
+	// maxImageFetchRedirects is how many redirects a remote fetch may follow
+	maxImageFetchRedirects = 3
+)
+
+// errBlockedAddress is returned by the dialer for private and loopback targets
+var errBlockedAddress = errors.New("address not allowed")
+
+// imageFetcher downloads remote images with size and timeout limits.
+// Unless allowPrivate is set it refuses to connect to private, loopback and
+// link-local addresses; the check runs at dial time so redirects and DNS
+// rebinding can't reach internal services.
+type imageFetcher struct {
+	client  *http.Client
+	maxSize int64
+}
+
+// newImageFetcher creates an image fetcher with the default limits
+func newImageFetcher(allowPrivate bool) *imageFetcher {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+				return errBlockedAddress
+			}
+			return nil
+		}
+	}
+
+	return &imageFetcher{
+		client: &http.Client{
+			Timeout: imageFetchTimeout,
+			Transport: &http.Transport{
+				// No proxy: a proxy would make the dial-time address check meaningless
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 5 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxImageFetchRedirects {
+					return errors.New("too many redirects")
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return ErrInvalidImageURL
+				}
+				return nil
+			},
+		},
+		maxSize: MaxListingImageSize,
+	}
+}
+
+// Fetch downloads an image and returns its bytes and sniffed content type
+func (f *imageFetcher) Fetch(ctx context.Context, sourceURL string) ([]byte, string, error) {
+	parsed, err := url.Parse(sourceURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, "", ErrInvalidImageURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, "", ErrInvalidImageURL
+	}
+	req.Header.Set("Accept", "image/png, image/jpeg, image/webp")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errBlockedAddress) || errors.Is(err, ErrInvalidImageURL) {
+			return nil, "", ErrInvalidImageURL
+		}
+		return nil, "", fmt.Errorf("%w: %v", ErrImageFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%w: status %d", ErrImageFetchFailed, resp.StatusCode)
+	}
+	if resp.ContentLength > f.maxSize {
+		return nil, "", ErrInvalidImage
+	}
+
+	// Read one byte past the limit to detect oversize bodies without a Content-Length
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrImageFetchFailed, err)
+	}
+	if int64(len(data)) > f.maxSize {
+		return nil, "", ErrInvalidImage
+	}
+
+	contentType, err := detectImageType(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentType, nil
+}
+
+// isBlockedIP reports whether an address is internal and must not be fetched
+func isBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast()
+}
+
+// detectImageType sniffs the content type from the image bytes, ignoring any
+// client- or server-supplied header, and rejects anything but PNG, JPEG and WebP
+func detectImageType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := imageExtension(contentType); !ok {
+		return "", ErrInvalidImage
+	}
+	return contentType, nil
+}
+
+// imageExtension maps an allowed image content type to its file extension
+func imageExtension(contentType string) (string, bool) {
+	switch contentType {
+	case "image/png":
+		return "png", true
+	case "image/jpeg":
+		return "jpg", true
+	case "image/webp":
+		return "webp", true
+	default:
+		return "", false
+	}
+}
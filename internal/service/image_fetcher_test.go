@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	storageMocks "github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage/mocks"
+)
+
+// testPNG is a PNG signature followed by padding, enough for content sniffing
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+func newImageServer(t *testing.T, contentType string, body []byte) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"10.0.0.5", true},
+		{"172.16.3.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2606:4700::1111", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, isBlockedIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestImageFetcher_RejectsNonHTTPScheme(t *testing.T) {
+	fetcher := newImageFetcher(false)
+
+	for _, u := range []string{"file:///etc/passwd", "ftp://example.com/a.png", "gopher://x", "not a url", "http://"} {
+		_, _, err := fetcher.Fetch(context.Background(), u)
+		assert.ErrorIs(t, err, ErrInvalidImageURL, u)
+	}
+}
+
+func TestImageFetcher_RejectsPrivateAddress(t *testing.T) {
+	srv := newImageServer(t, "image/png", testPNG)
+	fetcher := newImageFetcher(false)
+
+	_, _, err := fetcher.Fetch(context.Background(), srv.URL)
+
+	assert.ErrorIs(t, err, ErrInvalidImageURL)
+}
+
+func TestImageFetcher_Success(t *testing.T) {
+	srv := newImageServer(t, "application/octet-stream", testPNG)
+	fetcher := newImageFetcher(true)
+
+	data, contentType, err := fetcher.Fetch(context.Background(), srv.URL)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, testPNG, data)
+}
+
+func TestImageFetcher_RejectsNonImage(t *testing.T) {
+	srv := newImageServer(t, "image/png", []byte("<html><body>not an image</body></html>"))
+	fetcher := newImageFetcher(true)
+
+	_, _, err := fetcher.Fetch(context.Background(), srv.URL)
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestImageFetcher_RejectsOversize(t *testing.T) {
+	body := append(append([]byte{}, testPNG...), bytes.Repeat([]byte{0}, MaxListingImageSize)...)
+	srv := newImageServer(t, "image/png", body)
+	fetcher := newImageFetcher(true)
+
+	_, _, err := fetcher.Fetch(context.Background(), srv.URL)
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestImageFetcher_RemoteError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	fetcher := newImageFetcher(true)
+
+	_, _, err := fetcher.Fetch(context.Background(), srv.URL)
+
+	assert.ErrorIs(t, err, ErrImageFetchFailed)
+}
+
+// ---------------------------------------------------------------------------
+// Listing images
+// ---------------------------------------------------------------------------
+
+func setupListingImageService(t *testing.T) (*ListingService, *mocks.MockListingRepository, *storageMocks.MockStorage) {
+	t.Helper()
+	listingRepo := new(mocks.MockListingRepository)
+	stor := new(storageMocks.MockStorage)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetStorage(stor)
+	svc.imageFetcher = newImageFetcher(true)
+	return svc, listingRepo, stor
+}
+
+func TestListingAttachImageFromURL_Success(t *testing.T) {
+	svc, listingRepo, stor := setupListingImageService(t)
+	srv := newImageServer(t, "image/png", testPNG)
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	stor.On("UploadImage", mock.Anything, mock.MatchedBy(func(path string) bool {
+		return len(path) > 0 && path[:9] == "listings/"
+	}), testPNG, "image/png").Return("https://cdn.example.com/listing.png", nil)
+
+	listing, err := svc.AttachImageFromURL(context.Background(), testListingID, testSellerID, srv.URL)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/listing.png", listing.GetImageURL())
+	stor.AssertExpectations(t)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingAttachImageFromURL_NotOwnerDoesNotFetch(t *testing.T) {
+	svc, listingRepo, stor := setupListingImageService(t)
+	fetched := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+	}))
+	t.Cleanup(srv.Close)
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	listing, err := svc.AttachImageFromURL(context.Background(), testListingID, testBuyerID, srv.URL)
+
+	assert.Nil(t, listing)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.False(t, fetched)
+	stor.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListingAttachImageFromURL_ClosedListing(t *testing.T) {
+	svc, listingRepo, _ := setupListingImageService(t)
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID, withListingStatus("completed")), nil)
+
+	_, err := svc.AttachImageFromURL(context.Background(), testListingID, testSellerID, "https://example.com/a.png")
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestListingUploadImage_RejectsNonImage(t *testing.T) {
+	svc, listingRepo, stor := setupListingImageService(t)
+
+	_, err := svc.UploadImage(context.Background(), testListingID, testSellerID, []byte("GIF89a not allowed"))
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	listingRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	stor.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListingUploadImage_Success(t *testing.T) {
+	svc, listingRepo, stor := setupListingImageService(t)
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.GetImageURL() == "https://cdn.example.com/listing.png"
+	})).Return(nil)
+	stor.On("UploadImage", mock.Anything, mock.Anything, testPNG, "image/png").Return("https://cdn.example.com/listing.png", nil)
+
+	_, err := svc.UploadImage(context.Background(), testListingID, testSellerID, testPNG)
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingUploadImage_ConvertsToWebP(t *testing.T) {
+	svc, listingRepo, stor := setupListingImageService(t)
+	svc.SetWebPConversion(true)
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	stor.On("UploadImage", mock.Anything, mock.MatchedBy(func(path string) bool {
+		return strings.HasSuffix(path, ".webp")
+	}), mock.Anything, "image/webp").Return("https://cdn.example.com/listing.webp", nil)
+
+	listing, err := svc.UploadImage(context.Background(), testListingID, testSellerID, encodedPNG(t))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/listing.webp", listing.GetImageURL())
+	stor.AssertExpectations(t)
+}
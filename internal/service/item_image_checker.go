@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+)
+
+const (
+	// itemImageFoundTTL is how long an image that exists is trusted without re-checking
+	itemImageFoundTTL = 24 * time.Hour
+	// itemImageMissingTTL is short so newly uploaded images replace the placeholder quickly
+	itemImageMissingTTL = 1 * time.Hour
+	// itemImageCheckTimeout bounds a single HEAD request to storage
+	itemImageCheckTimeout = 5 * time.Second
+	// itemImageMemoryTTL is how long a status read from Redis is reused in memory
+	itemImageMemoryTTL = 10 * time.Minute
+	// maxItemImageChecks caps concurrent background HEAD requests
+	maxItemImageChecks = 8
+	// maxKnownItemImages bounds the in-memory statuses; Redis still holds every result
+	maxKnownItemImages = 10000
+
+	itemImageFound   = "found"
+	itemImageMissing = "missing"
+)
+
+// ItemImageChecker swaps generated item image URLs that are known to 404 for a
+// placeholder. A URL it hasn't seen yet is returned unchanged and HEAD-checked in
+// the background, so DTO builders never wait on storage. Results are shared through
+// Redis and kept in memory per instance, up to maxKnownItemImages of them. A nil
+// checker trusts every URL.
+type ItemImageChecker struct {
+	redis       *cache.RedisClient
+	client      *http.Client
+	placeholder string
+	slots       chan struct{}
+	maxKnown    int
+
+	mu       sync.RWMutex
+	known    map[string]itemImageStatus
+	inFlight map[string]bool
+}
+
+type itemImageStatus struct {
+	missing bool
+	expires time.Time
+}
+
+// NewItemImageChecker creates a checker that substitutes placeholderURL for missing images
+func NewItemImageChecker(redis *cache.RedisClient, placeholderURL string) *ItemImageChecker {
+	return &ItemImageChecker{
+		redis:       redis,
+		client:      &http.Client{Timeout: itemImageCheckTimeout},
+		placeholder: placeholderURL,
+		slots:       make(chan struct{}, maxItemImageChecks),
+		maxKnown:    maxKnownItemImages,
+		known:       make(map[string]itemImageStatus),
+		inFlight:    make(map[string]bool),
+	}
+}
+
+// Resolve returns imageURL, or the placeholder if storage is known not to have it
+func (c *ItemImageChecker) Resolve(imageURL string) string {
+	if c == nil || imageURL == "" {
+		return imageURL
+	}
+
+	c.mu.RLock()
+	status, ok := c.known[imageURL]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(status.expires) {
+		return c.pick(imageURL, status.missing)
+	}
+
+	ctx := context.Background()
+	if cached, err := c.redis.Get(ctx, cache.ItemImageKey(imageURL)); err == nil {
+		missing := cached == itemImageMissing
+		c.remember(imageURL, missing, itemImageMemoryTTL)
+		return c.pick(imageURL, missing)
+	}
+
+	c.checkAsync(imageURL)
+	return imageURL
+}
+
+func (c *ItemImageChecker) pick(imageURL string, missing bool) string {
+	if missing {
+		return c.placeholder
+	}
+	return imageURL
+}
+
+// remember keeps a status in memory for ttl, making room first when the map is full
+func (c *ItemImageChecker) remember(imageURL string, missing bool, ttl time.Duration) {
+	now := time.Now()
+	c.mu.Lock()
+	if _, ok := c.known[imageURL]; !ok && len(c.known) >= c.maxKnown {
+		c.evictLocked(now)
+	}
+	c.known[imageURL] = itemImageStatus{missing: missing, expires: now.Add(ttl)}
+	c.mu.Unlock()
+}
+
+// evictLocked drops expired statuses, or every status when none have expired yet.
+// Dropped URLs are read back from Redis on their next Resolve. Callers hold c.mu.
+func (c *ItemImageChecker) evictLocked(now time.Time) {
+	for imageURL, status := range c.known {
+		if !now.Before(status.expires) {
+			delete(c.known, imageURL)
+		}
+	}
+	if len(c.known) >= c.maxKnown {
+		c.known = make(map[string]itemImageStatus)
+	}
+}
+
+// checkAsync HEAD-checks imageURL in the background. Checks for the same URL are
+// deduplicated, and when every slot is busy the check is skipped until the next request.
+func (c *ItemImageChecker) checkAsync(imageURL string) {
+	c.mu.Lock()
+	if c.inFlight[imageURL] {
+		c.mu.Unlock()
+		return
+	}
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		c.mu.Unlock()
+		return
+	}
+	c.inFlight[imageURL] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			<-c.slots
+			c.mu.Lock()
+			delete(c.inFlight, imageURL)
+			c.mu.Unlock()
+		}()
+		c.check(context.Background(), imageURL)
+	}()
+}
+
+// check records whether imageURL exists. Errors and 5xx responses aren't recorded,
+// so the URL keeps being trusted and is checked again later.
+func (c *ItemImageChecker) check(ctx context.Context, imageURL string) {
+	ctx, cancel := context.WithTimeout(ctx, itemImageCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, imageURL, nil)
+	if err != nil {
+		return
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.FromContext(ctx).Debug("item image check failed", "url", imageURL, "error", err.Error())
+		return
+	}
+	resp.Body.Close()
+
+	var missing bool
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		missing = false
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest:
+		// Supabase storage answers 400 for objects that don't exist
+		missing = true
+	default:
+		return
+	}
+
+	status, ttl := itemImageFound, itemImageFoundTTL
+	if missing {
+		status, ttl = itemImageMissing, itemImageMissingTTL
+	}
+	_ = c.redis.Set(ctx, cache.ItemImageKey(imageURL), status, ttl)
+	c.remember(imageURL, missing, ttl)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlaceholderURL = "https://cdn.example.com/placeholder.png"
+
+// newImageStorage serves HEAD requests with the given status per path and counts hits
+func newImageStorage(t *testing.T, statuses map[string]int) (*httptest.Server, *int32) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		status, ok := statuses[r.URL.Path]
+		if !ok {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestItemImageChecker_NilTrustsURL(t *testing.T) {
+	var checker *ItemImageChecker
+
+	assert.Equal(t, "https://x/runes/ber.png", checker.Resolve("https://x/runes/ber.png"))
+	assert.Equal(t, "", checker.Resolve(""))
+}
+
+func TestItemImageChecker_MissingImageFallsBackAfterCheck(t *testing.T) {
+	redisClient, mr := newTestRedisReal(t)
+	srv, _ := newImageStorage(t, map[string]int{"/runes/zod.png": http.StatusNotFound})
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	imageURL := srv.URL + "/runes/zod.png"
+
+	// First sighting is trusted while the check runs in the background
+	assert.Equal(t, imageURL, checker.Resolve(imageURL))
+
+	require.Eventually(t, func() bool {
+		return checker.Resolve(imageURL) == testPlaceholderURL
+	}, 2*time.Second, 10*time.Millisecond)
+
+	val, err := mr.Get(cache.ItemImageKey(imageURL))
+	require.NoError(t, err)
+	assert.Equal(t, itemImageMissing, val)
+}
+
+func TestItemImageChecker_SupabaseBadRequestCountsAsMissing(t *testing.T) {
+	redisClient, _ := newTestRedisReal(t)
+	srv, _ := newImageStorage(t, map[string]int{"/uniques/shako.png": http.StatusBadRequest})
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	imageURL := srv.URL + "/uniques/shako.png"
+
+	checker.Resolve(imageURL)
+
+	require.Eventually(t, func() bool {
+		return checker.Resolve(imageURL) == testPlaceholderURL
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestItemImageChecker_ExistingImageIsCached(t *testing.T) {
+	redisClient, mr := newTestRedisReal(t)
+	srv, hits := newImageStorage(t, nil)
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	imageURL := srv.URL + "/runes/ber.png"
+
+	assert.Equal(t, imageURL, checker.Resolve(imageURL))
+
+	require.Eventually(t, func() bool {
+		return mr.Exists(cache.ItemImageKey(imageURL))
+	}, 2*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, imageURL, checker.Resolve(imageURL))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+}
+
+func TestItemImageChecker_UsesStatusSharedThroughRedis(t *testing.T) {
+	redisClient, mr := newTestRedisReal(t)
+	srv, hits := newImageStorage(t, nil)
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	imageURL := srv.URL + "/runes/jah.png"
+
+	// Another instance already found the image missing
+	require.NoError(t, mr.Set(cache.ItemImageKey(imageURL), itemImageMissing))
+
+	assert.Equal(t, testPlaceholderURL, checker.Resolve(imageURL))
+	assert.Equal(t, int32(0), atomic.LoadInt32(hits))
+}
+
+func TestItemImageChecker_ServerErrorIsNotRecorded(t *testing.T) {
+	redisClient, mr := newTestRedisReal(t)
+	srv, hits := newImageStorage(t, map[string]int{"/runes/lo.png": http.StatusServiceUnavailable})
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	imageURL := srv.URL + "/runes/lo.png"
+
+	assert.Equal(t, imageURL, checker.Resolve(imageURL))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(hits) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Let the background check finish before asserting nothing was stored
+	require.Eventually(t, func() bool {
+		checker.mu.RLock()
+		defer checker.mu.RUnlock()
+		return len(checker.inFlight) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.False(t, mr.Exists(cache.ItemImageKey(imageURL)))
+	assert.Equal(t, imageURL, checker.Resolve(imageURL))
+}
+
+func TestItemImageChecker_KnownStatusesAreBounded(t *testing.T) {
+	redisClient, mr := newTestRedisReal(t)
+	checker := NewItemImageChecker(redisClient, testPlaceholderURL)
+	checker.maxKnown = 2
+
+	checker.remember("https://x/runes/el.png", false, -time.Second) // already expired
+	checker.remember("https://x/runes/eld.png", false, time.Hour)
+	checker.remember("https://x/runes/tir.png", true, time.Hour)
+	assert.Len(t, checker.known, 2)
+	assert.NotContains(t, checker.known, "https://x/runes/el.png")
+
+	// Nothing has expired, so the map is cleared to make room
+	checker.remember("https://x/runes/nef.png", false, time.Hour)
+	assert.Len(t, checker.known, 1)
+
+	// A dropped status is read back from Redis
+	require.NoError(t, mr.Set(cache.ItemImageKey("https://x/runes/tir.png"), itemImageMissing))
+	assert.Equal(t, testPlaceholderURL, checker.Resolve("https://x/runes/tir.png"))
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,10 +16,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage"
 )
 
 const (
@@ -24,10 +29,41 @@ const (
 	listingDTOCacheTTL     = 1 * time.Hour
 	filterResultCacheTTL   = 20 * time.Second
 	maxRecentListings      = 20
+	recentlyViewedTTL      = 30 * 24 * time.Hour
 	FreeListingLimit       = 10
 	FreeRefreshCooldown    = 24 * time.Hour
 	PremiumRefreshCooldown = 4 * time.Hour
 	PremiumBoostDuration   = 2 * time.Hour
+	SellerOnlineWindow     = 15 * time.Minute
+	maxActiveWithinHours   = 30 * 24
+	MaxReservationDuration = 7 * 24 * time.Hour
+	// DefaultMaxPendingOffers is stored as the pending offer cap of new listings whose
+	// seller didn't set one. Listings created before the cap existed have none.
+	DefaultMaxPendingOffers = 25
+
+	// DefaultListingLifetime and DefaultPremiumListingLifetime are how long new, refreshed
+	// and relisted listings stay up before the expire job cancels them
+	DefaultListingLifetime        = 30 * 24 * time.Hour
+	DefaultPremiumListingLifetime = 60 * 24 * time.Hour
+
+	// DefaultExpiryWarningHours is how far ahead of expiry sellers are warned when no
+	// window is configured
+	DefaultExpiryWarningHours = 24
+	// expiringBatchSize is how many expiring listings are warned per query
+	expiringBatchSize = 200
+
+	// DefaultSimilarListings and MaxSimilarListings bound how many similar listings a
+	// detail page gets
+	DefaultSimilarListings  = 6
+	MaxSimilarListings      = 20
+	similarListingsCacheTTL = 5 * time.Minute
+)
+
+// How ListingService.Create treats a listing identical to one of the seller's active listings
+const (
+	DuplicateListingsAllow  = "allow"
+	DuplicateListingsReject = "reject"
+	DuplicateListingsReuse  = "reuse"
 )
 
 // ListingService handles listing business logic
@@ -37,7 +73,33 @@ type ListingService struct {
 	redis           *cache.RedisClient
 	invalidator     *cache.Invalidator
 	wishlistService *WishlistService
+	watchService    *WatchService
 	statsService    *StatsService
+	notifService    *NotificationService
+	gameRegistry    *games.Registry
+	storage         storage.Storage
+	imageFetcher    *imageFetcher
+	transactionRepo repository.TransactionRepository
+	relistCooldown  time.Duration
+	imageChecker    *ItemImageChecker
+	convertToWebP   bool
+	textLimits      TextLimits
+	featureFlags    *FeatureFlagService
+
+	// duplicateMode is one of the DuplicateListings* modes; empty allows duplicates
+	duplicateMode string
+	// delegateRepo lets sellers' delegates manage their listings; nil limits it to sellers
+	delegateRepo repository.DelegateRepository
+	// lifetime and premiumLifetime set ExpiresAt; zero uses the defaults
+	lifetime        time.Duration
+	premiumLifetime time.Duration
+	// expiryWarningHours is NotifyExpiringSoon's default window; zero uses DefaultExpiryWarningHours
+	expiryWarningHours int
+	// freeDailyBumps and premiumDailyBumps are the daily refresh quotas; zero uses the defaults
+	freeDailyBumps    int
+	premiumDailyBumps int
+	// viewBuffering counts views in Redis and leaves the database writes to FlushViews
+	viewBuffering bool
 }
 
 // NewListingService creates a new listing service
@@ -47,24 +109,139 @@ func NewListingService(repo repository.ListingRepository, profileService *Profil
 		profileService: profileService,
 		redis:          redis,
 		invalidator:    cache.NewInvalidator(redis),
+		imageFetcher:   newImageFetcher(false),
+		textLimits:     TextLimits{}.withDefaults(),
 	}
 }
 
+// SetTextLimits sets the maximum length of listing notes
+func (s *ListingService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
+// SetStorage sets the storage used for listing images
+func (s *ListingService) SetStorage(stor storage.Storage) {
+	s.storage = stor
+}
+
 // SetWishlistService sets the wishlist service for matching on listing creation
 func (s *ListingService) SetWishlistService(ws *WishlistService) {
 	s.wishlistService = ws
 }
 
+// SetWatchService sets the watch service for item watch alerts on listing creation
+func (s *ListingService) SetWatchService(ws *WatchService) {
+	s.watchService = ws
+}
+
+// SetRelistCooldown blocks sellers from relisting an item identical to one they
+// sold (same name and stats) until cooldown has passed since the sale. Zero disables it.
+func (s *ListingService) SetRelistCooldown(repo repository.TransactionRepository, cooldown time.Duration) {
+	s.transactionRepo = repo
+	s.relistCooldown = cooldown
+}
+
+// SetDuplicateListingMode sets what Create does when the seller already has an active
+// listing with the same fingerprint: DuplicateListingsReject fails with ErrAlreadyExists
+// and DuplicateListingsReuse returns the existing listing instead of creating another
+func (s *ListingService) SetDuplicateListingMode(mode string) {
+	s.duplicateMode = mode
+}
+
+// SetDelegateRepository lets delegates with the manage_listings permission act on a seller's listings
+func (s *ListingService) SetDelegateRepository(repo repository.DelegateRepository) {
+	s.delegateRepo = repo
+}
+
+// hasManagePermission reports whether userID may manage the listing: its seller or one
+// of the seller's delegates with the manage_listings permission
+func (s *ListingService) hasManagePermission(ctx context.Context, listing *models.Listing, userID string) bool {
+	return canActFor(ctx, s.delegateRepo, listing.SellerID, userID, models.DelegatePermissionManageListings, "listing_id", listing.ID)
+}
+
+// SetListingLifetimes sets how long listings of free and premium sellers stay up.
+// Zero keeps the default for that tier.
+func (s *ListingService) SetListingLifetimes(free, premium time.Duration) {
+	s.lifetime = free
+	s.premiumLifetime = premium
+}
+
+// lifetimeFor returns how long a listing by the seller stays up
+func (s *ListingService) lifetimeFor(seller *models.Profile) time.Duration {
+	if seller != nil && seller.IsPremium {
+		if s.premiumLifetime > 0 {
+			return s.premiumLifetime
+		}
+		return DefaultPremiumListingLifetime
+	}
+	if s.lifetime > 0 {
+		return s.lifetime
+	}
+	return DefaultListingLifetime
+}
+
+// LifetimeFor returns how long a listing by the seller stays up, falling back to the free
+// lifetime when the profile can't be loaded
+func (s *ListingService) LifetimeFor(ctx context.Context, sellerID string) time.Duration {
+	profile, err := s.profileService.GetByID(ctx, sellerID)
+	if err != nil {
+		return s.lifetimeFor(nil)
+	}
+	return s.lifetimeFor(profile)
+}
+
+// SetExpiryWarningHours sets how many hours before expiry NotifyExpiringSoon warns sellers
+// when it isn't given a window
+func (s *ListingService) SetExpiryWarningHours(hours int) {
+	s.expiryWarningHours = hours
+}
+
 // SetStatsService sets the stats service for cache refresh on listing events
 func (s *ListingService) SetStatsService(ss *StatsService) {
 	s.statsService = ss
 }
 
+// SetWebPConversion stores PNG and JPEG listing images as WebP
+func (s *ListingService) SetWebPConversion(enabled bool) {
+	s.convertToWebP = enabled
+}
+
+// SetGameRegistry sets the game registry used to validate regions
+func (s *ListingService) SetGameRegistry(registry *games.Registry) {
+	s.gameRegistry = registry
+}
+
+// SetNotificationService sets the notification service for reservation notices
+func (s *ListingService) SetNotificationService(ns *NotificationService) {
+	s.notifService = ns
+}
+
+// SetImageChecker sets the checker that replaces missing rune images with a placeholder
+func (s *ListingService) SetImageChecker(checker *ItemImageChecker) {
+	s.imageChecker = checker
+}
+
+// SetFeatureFlags sets the flags that gate the fuzzy search fallback. Without
+// them the fallback is always on.
+func (s *ListingService) SetFeatureFlags(flags *FeatureFlagService) {
+	s.featureFlags = flags
+}
+
 // ErrListingLimitReached indicates a free user has reached their active listing limit
 var ErrListingLimitReached = fmt.Errorf("listing limit reached")
 
-// Create creates a new listing
+// Create creates a new listing.
+// A repeated request with the same idempotency key returns the listing created by the first.
 func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.CreateListingRequest) (*models.Listing, error) {
+	return createIdempotent(ctx, s.redis, "listing", sellerID, req.IdempotencyKey,
+		func() (*models.Listing, error) { return s.create(ctx, sellerID, req) },
+		func(l *models.Listing) string { return l.ID },
+		func(id string) (*models.Listing, error) { return s.repo.GetByID(ctx, id) },
+	)
+}
+
+// create performs the actual listing creation
+func (s *ListingService) create(ctx context.Context, sellerID string, req *dto.CreateListingRequest) (*models.Listing, error) {
 	log := logger.FromContext(ctx)
 	log.Info("creating new listing",
 		"seller_id", sellerID,
@@ -75,23 +252,27 @@ func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.C
 		"game", req.Game,
 	)
 
+	if err := s.profileService.RequireVerifiedEmail(ctx, sellerID); err != nil {
+		return nil, err
+	}
+
 	// Check listing limit for free users
 	profile, err := s.profileService.GetByID(ctx, sellerID)
 	if err != nil {
 		log.Error("failed to get seller profile", "error", err.Error(), "seller_id", sellerID)
 		return nil, err
 	}
-	if !profile.IsPremium {
-		count, err := s.repo.CountActiveBySellerID(ctx, sellerID)
-		if err != nil {
-			log.Error("failed to count active listings", "error", err.Error(), "seller_id", sellerID)
-			return nil, err
-		}
-		log.Debug("checking listing limit for free user", "current_count", count, "limit", FreeListingLimit)
-		if count >= FreeListingLimit {
-			log.Warn("listing limit reached for free user", "seller_id", sellerID, "count", count)
-			return nil, ErrListingLimitReached
-		}
+	if err := s.checkListingLimit(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	region, errs := s.validateCreate(req)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRelistCooldown(ctx, sellerID, req); err != nil {
+		return nil, err
 	}
 
 	// Deduplicate platforms
@@ -104,29 +285,37 @@ func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.C
 		}
 	}
 
+	maxPendingOffers := req.MaxPendingOffers
+	if maxPendingOffers == nil {
+		defaultMax := DefaultMaxPendingOffers
+		maxPendingOffers = &defaultMax
+	}
+
 	listing := &models.Listing{
-		ID:             uuid.New().String(),
-		SellerID:       sellerID,
-		Name:           req.Name,
-		ItemType:       req.ItemType,
-		Rarity:         req.Rarity,
-		Category:       req.Category,
-		Stats:          req.Stats,
-		Suffixes:       req.Suffixes,
-		Runes:          req.Runes,
-		AskingFor:      req.AskingFor,
-		Amount:         1,
-		Game:           req.Game,
-		Ladder:         req.Ladder,
-		Hardcore:       req.Hardcore,
-		IsNonRotw:      req.IsNonRotw,
-		Platforms:      uniquePlatforms,
-		Region:         req.Region,
-		SellerTimezone: profile.Timezone,
-		Status:         "active",
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-		ExpiresAt:      time.Now().Add(30 * 24 * time.Hour),
+		ID:               uuid.New().String(),
+		SellerID:         sellerID,
+		Name:             req.Name,
+		ItemType:         req.ItemType,
+		Rarity:           req.Rarity,
+		Category:         req.Category,
+		Stats:            req.Stats,
+		Suffixes:         req.Suffixes,
+		Runes:            req.Runes,
+		AskingFor:        req.AskingFor,
+		Amount:           1,
+		Game:             req.Game,
+		Ladder:           req.Ladder,
+		Hardcore:         req.Hardcore,
+		IsNonRotw:        req.IsNonRotw,
+		Platforms:        uniquePlatforms,
+		Region:           region,
+		SellerTimezone:   profile.Timezone,
+		MaxPendingOffers: maxPendingOffers,
+		HideStatsOnCard:  req.HideStatsOnCard,
+		Status:           "active",
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+		ExpiresAt:        time.Now().Add(s.lifetimeFor(profile)),
 	}
 
 	if req.ImageURL != "" {
@@ -135,12 +324,18 @@ func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.C
 	if req.AskingPrice != "" {
 		listing.AskingPrice = &req.AskingPrice
 	}
-	if req.Notes != "" {
-		listing.Notes = &req.Notes
+	if notes := sanitizePlainText(req.Notes); notes != "" {
+		listing.Notes = &notes
 	}
 	if req.RuneOrder != "" {
 		listing.RuneOrder = &req.RuneOrder
 	}
+	listing.MinBuyerRating = req.MinBuyerRating
+	listing.MinBuyerTrades = req.MinBuyerTrades
+	listing.AllowUnratedBuyers = req.AllowUnratedBuyers
+	listing.Ethereal = req.Ethereal
+	listing.Sockets = req.Sockets
+	listing.Quality = req.Quality
 	if req.BaseItemCode != "" {
 		listing.BaseItemCode = &req.BaseItemCode
 	}
@@ -154,6 +349,12 @@ func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.C
 		listing.Amount = *req.Amount
 	}
 
+	fingerprint := listing.ComputeFingerprint()
+	listing.Fingerprint = &fingerprint
+	if existing, err := s.findDuplicate(ctx, listing); err != nil || existing != nil {
+		return existing, err
+	}
+
 	if err := s.repo.Create(ctx, listing); err != nil {
 		log.Error("failed to create listing in database", "error", err.Error(), "listing_id", listing.ID)
 		return nil, err
@@ -208,9 +409,183 @@ func (s *ListingService) Create(ctx context.Context, sellerID string, req *dto.C
 		fmt.Printf("[LISTING] WARNING: wishlist service is nil, skipping matching for listing: id=%s\n", listing.ID)
 	}
 
+	// Trigger async item watch alerts
+	if s.watchService != nil {
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Error("panic in item watch alerts",
+						"error", fmt.Sprintf("%v", r),
+						"listing_id", listing.ID,
+					)
+				}
+			}()
+			s.watchService.NotifyWatchers(context.Background(), listing)
+		}()
+	}
+
 	return listing, nil
 }
 
+// CountPotentialWishlistMatches returns how many buyers have a wishlist item the draft
+// listing would match. Nothing is saved and no one is notified.
+func (s *ListingService) CountPotentialWishlistMatches(ctx context.Context, sellerID string, draft *dto.CreateListingRequest) (int, error) {
+	if s.wishlistService == nil {
+		return 0, nil
+	}
+
+	listing := &models.Listing{
+		SellerID:  sellerID,
+		Name:      draft.Name,
+		Rarity:    draft.Rarity,
+		Category:  draft.Category,
+		Stats:     draft.Stats,
+		Game:      draft.Game,
+		Ladder:    draft.Ladder,
+		Hardcore:  draft.Hardcore,
+		IsNonRotw: draft.IsNonRotw,
+		Platforms: draft.Platforms,
+	}
+	if draft.CatalogItemID != "" {
+		listing.CatalogItemID = &draft.CatalogItemID
+	}
+
+	return s.wishlistService.CountMatches(ctx, listing)
+}
+
+// pendingOfferLimit returns how many pending offers the listing accepts at once, or 0
+// when it has no cap (listings created before the cap was introduced)
+func pendingOfferLimit(listing *models.Listing) int {
+	if listing.MaxPendingOffers != nil {
+		return *listing.MaxPendingOffers
+	}
+	return 0
+}
+
+// ValidateCreate reports every field problem in a create request at once
+func (s *ListingService) ValidateCreate(req *dto.CreateListingRequest) ValidationErrors {
+	_, errs := s.validateCreate(req)
+	return errs
+}
+
+// validateCreate checks a create request against the game catalog and returns
+// the normalized region along with any field problems
+func (s *ListingService) validateCreate(req *dto.CreateListingRequest) (string, ValidationErrors) {
+	errs := ValidationErrors{}
+	validateRequired(errs, "name", req.Name)
+	validateRequired(errs, "itemType", req.ItemType)
+	validateRequired(errs, "rarity", req.Rarity)
+	validateRequired(errs, "game", req.Game)
+
+	game := newGameValidator(s.gameRegistry, req.Game, errs)
+	if req.Rarity != "" && !game.validRarity(req.Rarity) {
+		errs.Add("rarity", "unknown rarity")
+	}
+	if req.Category != "" && !game.validCategory(req.Category) {
+		errs.Add("category", "unknown category")
+	}
+	validatePlatforms(errs, req.Platforms, true)
+	region := validateRegion(errs, s.gameRegistry, req.Game, req.Region)
+
+	validateJSONField(errs, "stats", req.Stats)
+	validateJSONField(errs, "suffixes", req.Suffixes)
+	validateJSONField(errs, "runes", req.Runes)
+	validateJSONField(errs, "askingFor", req.AskingFor)
+	validateText(errs, "notes", req.Notes, s.textLimits.Notes)
+
+	return region, errs
+}
+
+// checkListingLimit returns ErrListingLimitReached when a free seller already has the maximum active listings
+func (s *ListingService) checkListingLimit(ctx context.Context, profile *models.Profile) error {
+	if profile.IsPremium {
+		return nil
+	}
+
+	log := logger.FromContext(ctx)
+	count, err := s.repo.CountActiveBySellerID(ctx, profile.ID)
+	if err != nil {
+		log.Error("failed to count active listings", "error", err.Error(), "seller_id", profile.ID)
+		return err
+	}
+	log.Debug("checking listing limit for free user", "current_count", count, "limit", FreeListingLimit)
+	if count >= FreeListingLimit {
+		log.Warn("listing limit reached for free user", "seller_id", profile.ID, "count", count)
+		return ErrListingLimitReached
+	}
+	return nil
+}
+
+// RemainingListings returns how many more active listings a seller can create before
+// hitting the free limit, so clients can warn ahead of ErrListingLimitReached.
+// Premium sellers have no limit and get nil.
+func (s *ListingService) RemainingListings(ctx context.Context, sellerID string) (*int, error) {
+	profile, err := s.profileService.GetByID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.IsPremium {
+		return nil, nil
+	}
+
+	count, err := s.repo.CountActiveBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := FreeListingLimit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining, nil
+}
+
+// checkRelistCooldown rejects a new listing identical to an item the seller sold
+// within the relist cooldown, so a sold unique can't be immediately relisted
+func (s *ListingService) checkRelistCooldown(ctx context.Context, sellerID string, req *dto.CreateListingRequest) error {
+	if s.relistCooldown <= 0 || s.transactionRepo == nil {
+		return nil
+	}
+
+	sold, err := s.transactionRepo.HasRecentSale(ctx, sellerID, req.Name, req.Stats, time.Now().Add(-s.relistCooldown))
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to check recent sales", "error", err.Error(), "seller_id", sellerID)
+		return err
+	}
+	if sold {
+		return fmt.Errorf("%w: an identical item was sold less than %s ago", ErrInvalidState, s.relistCooldown)
+	}
+	return nil
+}
+
+// findDuplicate applies the duplicate listing mode to a listing about to be created.
+// It returns the seller's identical active listing in reuse mode, ErrAlreadyExists in
+// reject mode, and nil when duplicates are allowed or there is none.
+func (s *ListingService) findDuplicate(ctx context.Context, listing *models.Listing) (*models.Listing, error) {
+	if s.duplicateMode != DuplicateListingsReject && s.duplicateMode != DuplicateListingsReuse {
+		return nil, nil
+	}
+
+	id, found, err := s.repo.ExistsActiveByFingerprint(ctx, listing.SellerID, *listing.Fingerprint)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to check duplicate listings", "error", err.Error(), "seller_id", listing.SellerID)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	logger.FromContext(ctx).Info("duplicate listing detected",
+		"seller_id", listing.SellerID,
+		"existing_listing_id", id,
+		"mode", s.duplicateMode,
+	)
+	if s.duplicateMode == DuplicateListingsReject {
+		return nil, fmt.Errorf("%w: an identical listing is already active", ErrAlreadyExists)
+	}
+	return s.repo.GetByIDWithSeller(ctx, id)
+}
+
 // GetByID retrieves a listing by ID with caching
 func (s *ListingService) GetByID(ctx context.Context, id string) (*models.Listing, error) {
 	// Try cache first
@@ -219,63 +594,486 @@ func (s *ListingService) GetByID(ctx context.Context, id string) (*models.Listin
 	if err == nil && cached != "" {
 		var listing models.Listing
 		if json.Unmarshal([]byte(cached), &listing) == nil {
-			return &listing, nil
+			return s.withPendingViews(ctx, &listing), nil
+		}
+	}
+
+	// Fetch from database
+	listing, err := s.repo.GetByIDWithSeller(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(listing); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), listingCacheTTL)
+	}
+
+	// Cache DTO version for frontend direct access
+	s.cacheListingDTO(ctx, listing)
+
+	return s.withPendingViews(ctx, listing), nil
+}
+
+// GetByIDForViewer returns the listing detail along with the viewer's relationship
+// to it. The listing itself comes from the cache; viewer flags are always computed fresh.
+func (s *ListingService) GetByIDForViewer(ctx context.Context, id, viewerID string) (*dto.ListingDetailResponse, error) {
+	listing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !listing.IsVisibleTo(viewerID) {
+		return nil, sql.ErrNoRows
+	}
+
+	resp := s.ToDetailResponse(ctx, listing)
+	resp.IsOwner = listing.SellerID == viewerID
+	if resp.IsOwner {
+		return resp, nil
+	}
+
+	hasPending, err := s.repo.HasPendingOfferFrom(ctx, listing.ID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	resp.HasPendingOffer = hasPending
+	resp.CanOffer = !hasPending &&
+		(resp.PendingOffersRemaining == nil || *resp.PendingOffersRemaining > 0) &&
+		(listing.IsActive() || listing.IsReservedFor(viewerID))
+
+	if resp.CanOffer && viewerID != "" && !listing.IsReservedFor(viewerID) {
+		if err := checkBuyerRequirements(ctx, s.profileService, listing.BuyerRequirements(), viewerID); err != nil {
+			resp.CanOffer = false
+		}
+	}
+
+	return resp, nil
+}
+
+// Update updates a listing
+func (s *ListingService) Update(ctx context.Context, id string, userID string, req *dto.UpdateListingRequest) (*models.Listing, error) {
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify ownership
+	if !s.hasManagePermission(ctx, listing, userID) {
+		return nil, ErrForbidden
+	}
+
+	// Apply updates
+	if req.AskingFor != nil {
+		listing.AskingFor = req.AskingFor
+	}
+	if req.AskingPrice != nil {
+		listing.AskingPrice = req.AskingPrice
+	}
+	if req.Notes != nil {
+		notes, err := cleanText("notes", *req.Notes, s.textLimits.Notes)
+		if err != nil {
+			return nil, err
+		}
+		listing.Notes = &notes
+	}
+	if req.MaxPendingOffers != nil {
+		listing.MaxPendingOffers = req.MaxPendingOffers
+	}
+	if req.HideStatsOnCard != nil {
+		listing.HideStatsOnCard = *req.HideStatsOnCard
+	}
+	listing.MinBuyerRating = updatedThreshold(listing.MinBuyerRating, req.MinBuyerRating)
+	listing.MinBuyerTrades = updatedThreshold(listing.MinBuyerTrades, req.MinBuyerTrades)
+	if req.AllowUnratedBuyers != nil {
+		listing.AllowUnratedBuyers = *req.AllowUnratedBuyers
+	}
+	if req.Ethereal != nil || req.Sockets != nil || req.Quality != nil {
+		if req.Ethereal != nil {
+			listing.Ethereal = req.Ethereal
+		}
+		if req.Sockets != nil {
+			listing.Sockets = req.Sockets
+		}
+		if req.Quality != nil {
+			listing.Quality = req.Quality
+		}
+		fingerprint := listing.ComputeFingerprint()
+		listing.Fingerprint = &fingerprint
+	}
+
+	statusChanged := req.Status != nil && *req.Status != listing.Status
+	if statusChanged {
+		if err := s.applyStatusChange(ctx, listing, *req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	_ = s.invalidator.InvalidateListing(ctx, id)
+	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	if statusChanged {
+		s.syncRecentListings(ctx, listing)
+	}
+
+	return listing, nil
+}
+
+// Pause hides an active listing from public search without cancelling it
+func (s *ListingService) Pause(ctx context.Context, id string, userID string) (*models.Listing, error) {
+	return s.setStatus(ctx, id, userID, "active", "paused")
+}
+
+// Resume makes a paused listing visible in public search again
+func (s *ListingService) Resume(ctx context.Context, id string, userID string) (*models.Listing, error) {
+	return s.setStatus(ctx, id, userID, "paused", "active")
+}
+
+// Reserve holds an active listing for a single buyer until the given time. The listing
+// drops out of public search and only the reserved buyer can make offers on it.
+func (s *ListingService) Reserve(ctx context.Context, id string, userID string, forUserID string, until time.Time) (*models.Listing, error) {
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.hasManagePermission(ctx, listing, userID) {
+		return nil, ErrForbidden
+	}
+
+	if forUserID == listing.SellerID || forUserID == userID {
+		return nil, ErrSelfAction
+	}
+
+	now := time.Now()
+	if !until.After(now) || until.Sub(now) > MaxReservationDuration {
+		return nil, ErrInvalidReservation
+	}
+
+	if !listing.IsActive() {
+		return nil, ErrInvalidState
+	}
+
+	if _, err := s.profileService.GetByID(ctx, forUserID); err != nil {
+		return nil, err
+	}
+
+	listing.Status = "reserved"
+	listing.ReservedFor = &forUserID
+	listing.ReservedUntil = &until
+	listing.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	_ = s.invalidator.InvalidateListing(ctx, id)
+	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	s.syncRecentListings(ctx, listing)
+
+	if s.notifService != nil {
+		if err := s.notifService.NotifyListingReserved(ctx, forUserID, listing.ID, listing.Name, until); err != nil {
+			logger.FromContext(ctx).Warn("failed to notify reserved buyer",
+				"error", err.Error(),
+				"listing_id", listing.ID,
+				"user_id", forUserID,
+			)
+		}
+	}
+
+	return listing, nil
+}
+
+// setStatus moves a listing owned by userID from one status to another
+func (s *ListingService) setStatus(ctx context.Context, id string, userID string, from, to string) (*models.Listing, error) {
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.hasManagePermission(ctx, listing, userID) {
+		return nil, ErrForbidden
+	}
+
+	if listing.Status != from {
+		return nil, ErrInvalidState
+	}
+
+	if err := s.applyStatusChange(ctx, listing, to); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	_ = s.invalidator.InvalidateListing(ctx, id)
+	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	s.syncRecentListings(ctx, listing)
+
+	return listing, nil
+}
+
+// listingTransitions lists the statuses a seller may move a listing to from each status.
+// "completed" is only reached through a trade and "expired" through expiry, never directly.
+// "reserved" is only entered through Reserve.
+var listingTransitions = map[string][]string{
+	"active":   {"paused", "cancelled"},
+	"paused":   {"active", "cancelled"},
+	"reserved": {"active", "cancelled"},
+	"expired":  {"cancelled"},
+}
+
+// canTransitionListing reports whether a seller may move a listing between the given statuses
+func canTransitionListing(from, to string) bool {
+	for _, allowed := range listingTransitions[from] {
+		if allowed == to {
+			return true
 		}
 	}
+	return false
+}
+
+// applyStatusChange validates a seller-driven status change and applies it to the listing.
+// Reactivating counts against the free listing limit like creating a new listing does;
+// releasing a reservation doesn't, since reserved listings already count toward it.
+func (s *ListingService) applyStatusChange(ctx context.Context, listing *models.Listing, status string) error {
+	if !canTransitionListing(listing.Status, status) {
+		return ErrInvalidState
+	}
+
+	if status == "active" && listing.Status != "reserved" {
+		profile, err := s.profileService.GetByID(ctx, listing.SellerID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkListingLimit(ctx, profile); err != nil {
+			return err
+		}
+	}
+
+	if listing.Status == "reserved" {
+		listing.ReservedFor = nil
+		listing.ReservedUntil = nil
+	}
+
+	listing.Status = status
+	listing.UpdatedAt = time.Now()
+	return nil
+}
+
+// syncRecentListings keeps the home:recent cache and home stats in line with a listing's status
+func (s *ListingService) syncRecentListings(ctx context.Context, listing *models.Listing) {
+	if listing.IsActive() {
+		if listing.Seller == nil {
+			listing.Seller, _ = s.profileService.GetByID(ctx, listing.SellerID)
+		}
+		s.pushToRecentListings(ctx, listing)
+	} else {
+		s.removeFromRecentListings(ctx, listing.ID)
+	}
+
+	// Refresh home stats (activeListings changed)
+	if s.statsService != nil {
+		go s.statsService.RefreshHomeStats(context.Background())
+	}
+}
+
+// UploadImage stores an uploaded image and sets it as the listing image.
+// The content type is sniffed from the bytes rather than trusted from the client.
+func (s *ListingService) UploadImage(ctx context.Context, id string, userID string, data []byte) (*models.Listing, error) {
+	if len(data) == 0 || len(data) > MaxListingImageSize {
+		return nil, ErrInvalidImage
+	}
+	contentType, err := detectImageType(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.setImage(ctx, id, userID, func() ([]byte, string, error) {
+		return data, contentType, nil
+	})
+}
+
+// AttachImageFromURL fetches a remote image server-side, stores it and sets it
+// as the listing image. Only public http(s) URLs are fetched.
+func (s *ListingService) AttachImageFromURL(ctx context.Context, id string, userID string, sourceURL string) (*models.Listing, error) {
+	return s.setImage(ctx, id, userID, func() ([]byte, string, error) {
+		return s.imageFetcher.Fetch(ctx, sourceURL)
+	})
+}
+
+// setImage checks ownership before loading the image, so other users can't make
+// the server fetch URLs on their behalf, then stores it and updates the listing
+func (s *ListingService) setImage(ctx context.Context, id string, userID string, load func() ([]byte, string, error)) (*models.Listing, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("storage not configured")
+	}
+
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.hasManagePermission(ctx, listing, userID) {
+		return nil, ErrForbidden
+	}
+	switch listing.Status {
+	case "active", "paused", "reserved":
+	default:
+		return nil, ErrInvalidState
+	}
 
-	// Fetch from database
-	listing, err := s.repo.GetByIDWithSeller(ctx, id)
+	data, contentType, err := load()
 	if err != nil {
 		return nil, err
 	}
+	if s.convertToWebP {
+		data, contentType = convertToWebP(data, contentType)
+	}
+	ext, _ := imageExtension(contentType)
 
-	// Cache the result
-	if data, err := json.Marshal(listing); err == nil {
-		_ = s.redis.Set(ctx, cacheKey, string(data), listingCacheTTL)
+	// Versioned path so CDNs and browsers don't serve the previous image
+	storagePath := fmt.Sprintf("listings/%s-%d.%s", listing.ID, time.Now().Unix(), ext)
+	imageURL, err := s.storage.UploadImage(ctx, storagePath, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %w", err)
 	}
 
-	// Cache DTO version for frontend direct access
-	s.cacheListingDTO(ctx, listing)
+	listing.ImageURL = &imageURL
+	listing.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	_ = s.invalidator.InvalidateListing(ctx, id)
+	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
 
 	return listing, nil
 }
 
-// Update updates a listing
-func (s *ListingService) Update(ctx context.Context, id string, userID string, req *dto.UpdateListingRequest) (*models.Listing, error) {
-	listing, err := s.repo.GetByID(ctx, id)
+// ExpireStaleResult summarizes an expire-stale run
+type ExpireStaleResult struct {
+	Expired  int
+	Released int
+}
+
+// expireStaleBatchSize is how many listings one expire-stale step flips at a time
+const expireStaleBatchSize = 500
+
+// ExpireStale expires active listings past their expiry date and releases
+// reservations whose hold has lapsed. Listings are expired in batches so a large
+// backlog never loads every ID at once.
+func (s *ListingService) ExpireStale(ctx context.Context) (*ExpireStaleResult, error) {
+	log := logger.FromContext(ctx)
+	now := time.Now()
+
+	expired := 0
+	for {
+		ids, err := s.repo.ExpireStale(ctx, now, expireStaleBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			_ = s.invalidator.InvalidateListing(ctx, id)
+			_ = s.invalidator.InvalidateListingDTO(ctx, id)
+			s.removeFromRecentListings(ctx, id)
+		}
+		expired += len(ids)
+		if len(ids) < expireStaleBatchSize {
+			break
+		}
+	}
+
+	released, err := s.repo.ReleaseExpiredReservations(ctx, now)
 	if err != nil {
 		return nil, err
 	}
-
-	// Verify ownership
-	if listing.SellerID != userID {
-		return nil, ErrForbidden
+	for _, id := range released {
+		_ = s.invalidator.InvalidateListing(ctx, id)
+		_ = s.invalidator.InvalidateListingDTO(ctx, id)
 	}
 
-	// Apply updates
-	if req.AskingFor != nil {
-		listing.AskingFor = req.AskingFor
+	if expired > 0 || len(released) > 0 {
+		_ = s.invalidator.InvalidateFilterResults(ctx)
+		if s.statsService != nil {
+			go s.statsService.RefreshHomeStats(context.Background())
+		}
 	}
-	if req.AskingPrice != nil {
-		listing.AskingPrice = req.AskingPrice
+
+	log.Info("expire-stale listings finished",
+		"expired", expired,
+		"released", len(released),
+	)
+
+	return &ExpireStaleResult{Expired: expired, Released: len(released)}, nil
+}
+
+// expiryWindowHours is the configured expiry warning window, or the default
+func (s *ListingService) expiryWindowHours() int {
+	if s.expiryWarningHours > 0 {
+		return s.expiryWarningHours
 	}
-	if req.Notes != nil {
-		listing.Notes = req.Notes
+	return DefaultExpiryWarningHours
+}
+
+// NotifyExpiringSoon warns the sellers of active listings expiring within the given
+// number of hours (the configured window when zero or less) and marks each listing warned
+// so later runs skip it. Listings are processed in batches. Returns how many were warned.
+func (s *ListingService) NotifyExpiringSoon(ctx context.Context, withinHours int) (int, error) {
+	if s.notifService == nil {
+		return 0, nil
 	}
-	if req.Status != nil {
-		listing.Status = *req.Status
+	if withinHours <= 0 {
+		withinHours = s.expiryWindowHours()
 	}
 
-	if err := s.repo.Update(ctx, listing); err != nil {
-		return nil, err
+	log := logger.FromContext(ctx)
+	now := time.Now()
+	before := now.Add(time.Duration(withinHours) * time.Hour)
+	warned := 0
+	afterID := ""
+
+	for {
+		listings, err := s.repo.ListExpiringUnwarned(ctx, before, afterID, expiringBatchSize)
+		if err != nil {
+			return warned, err
+		}
+		if len(listings) == 0 {
+			break
+		}
+
+		s.notifService.NotifyListingsExpiring(ctx, listings)
+
+		ids := make([]string, 0, len(listings))
+		for _, listing := range listings {
+			ids = append(ids, listing.ID)
+		}
+		if err := s.repo.MarkExpiryWarned(ctx, ids, now); err != nil {
+			return warned, err
+		}
+		warned += len(ids)
+
+		if len(listings) < expiringBatchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
 	}
 
-	// Invalidate cache
-	_ = s.invalidator.InvalidateListing(ctx, id)
-	_ = s.invalidator.InvalidateListingDTO(ctx, id)
-	_ = s.invalidator.InvalidateFilterResults(ctx)
+	log.Info("expiring listings warned",
+		"within_hours", withinHours,
+		"warned", warned,
+	)
 
-	return listing, nil
+	return warned, nil
 }
 
 // Refresh bumps a listing to the top by resetting created_at to now
@@ -286,7 +1084,7 @@ func (s *ListingService) Refresh(ctx context.Context, id string, userID string,
 	}
 
 	// Verify ownership
-	if listing.SellerID != userID {
+	if !s.hasManagePermission(ctx, listing, userID) {
 		return nil, ErrForbidden
 	}
 
@@ -296,7 +1094,7 @@ func (s *ListingService) Refresh(ctx context.Context, id string, userID string,
 	}
 
 	// Get profile for premium check
-	profile, err := s.profileService.GetByID(ctx, userID)
+	profile, err := s.profileService.GetByID(ctx, listing.SellerID)
 	if err != nil {
 		return nil, err
 	}
@@ -315,11 +1113,17 @@ func (s *ListingService) Refresh(ctx context.Context, id string, userID string,
 		return nil, ErrRefreshCooldown
 	}
 
+	refund, err := s.consumeBump(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apply refresh
 	now := time.Now()
 	listing.CreatedAt = now
 	listing.UpdatedAt = now
-	listing.ExpiresAt = now.AddDate(0, 0, 30)
+	listing.ExpiresAt = now.Add(s.lifetimeFor(profile))
+	listing.ExpiryWarnedAt = nil
 
 	// Premium users can update asking price
 	if req != nil && req.AskingFor != nil && profile.IsPremium {
@@ -327,6 +1131,7 @@ func (s *ListingService) Refresh(ctx context.Context, id string, userID string,
 	}
 
 	if err := s.repo.Update(ctx, listing); err != nil {
+		refund()
 		return nil, err
 	}
 
@@ -343,6 +1148,61 @@ func (s *ListingService) Refresh(ctx context.Context, id string, userID string,
 	return listing, nil
 }
 
+// Renew pushes a listing's expiry a full lifetime out and puts it back in home:recent,
+// without moving it in browse order or spending a bump. Expired listings come back as
+// active, which counts against the free listing limit. Active listings can only be
+// renewed once they are inside the expiry warning window, so renewing isn't a free bump.
+func (s *ListingService) Renew(ctx context.Context, id string, userID string) (*models.Listing, error) {
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.hasManagePermission(ctx, listing, userID) {
+		return nil, ErrForbidden
+	}
+
+	if listing.Status != "active" && listing.Status != "expired" {
+		return nil, ErrInvalidState
+	}
+
+	profile, err := s.profileService.GetByID(ctx, listing.SellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if listing.Status == "active" && !listing.ExpiresAt.IsZero() &&
+		listing.ExpiresAt.After(now.Add(time.Duration(s.expiryWindowHours())*time.Hour)) {
+		return nil, ErrRenewTooEarly
+	}
+
+	if listing.Status == "expired" {
+		if err := s.checkListingLimit(ctx, profile); err != nil {
+			return nil, err
+		}
+		listing.Status = "active"
+	}
+
+	listing.ExpiresAt = now.Add(s.lifetimeFor(profile))
+	listing.ExpiryWarnedAt = nil
+	listing.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	_ = s.invalidator.InvalidateListing(ctx, id)
+	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	s.removeFromRecentListings(ctx, listing.ID)
+	listing.Seller = profile
+	s.pushToRecentListings(ctx, listing)
+
+	return listing, nil
+}
+
 // Delete cancels a listing
 func (s *ListingService) Delete(ctx context.Context, id string, userID string) error {
 	listing, err := s.repo.GetByID(ctx, id)
@@ -351,16 +1211,24 @@ func (s *ListingService) Delete(ctx context.Context, id string, userID string) e
 	}
 
 	// Verify ownership
-	if listing.SellerID != userID {
+	if !s.hasManagePermission(ctx, listing, userID) {
 		return ErrForbidden
 	}
 
+	// Completed and already cancelled listings can't be cancelled
+	if !canTransitionListing(listing.Status, "cancelled") {
+		return ErrInvalidState
+	}
+
 	// Soft delete by setting status to cancelled
 	listing.Status = "cancelled"
+	listing.UpdatedAt = time.Now()
 	if err := s.repo.Update(ctx, listing); err != nil {
 		return err
 	}
 
+	s.checkListingChurn(ctx, listing)
+
 	// Invalidate cache
 	_ = s.invalidator.InvalidateListing(ctx, id)
 	_ = s.invalidator.InvalidateListingDTO(ctx, id)
@@ -377,8 +1245,48 @@ func (s *ListingService) Delete(ctx context.Context, id string, userID string) e
 	return nil
 }
 
-// List retrieves listings with filters
-func (s *ListingService) List(ctx context.Context, req *dto.ListingFilterRequest) ([]*models.Listing, int, error) {
+// List retrieves listings with filters. A non-empty viewerID hides the viewer's
+// own listings; anonymous browse is unchanged.
+func (s *ListingService) List(ctx context.Context, req *dto.ListingFilterRequest, viewerID string) ([]*models.Listing, int, bool, error) {
+	filter := s.ToFilter(req)
+	ExcludeViewer(&filter, viewerID)
+	return s.ListByFilter(ctx, filter, viewerID)
+}
+
+// ExcludeViewer adds the viewer's own listings to the filter's exclusion set, unless
+// the filter is already narrowed to the viewer as seller. There is no user blocking
+// yet; blocked sellers belong in the same set once there is.
+func ExcludeViewer(filter *repository.ListingFilter, viewerID string) {
+	if viewerID == "" || filter.SellerID == viewerID {
+		return
+	}
+	filter.ExcludeSellerIDs = append(filter.ExcludeSellerIDs, viewerID)
+}
+
+// applyCategoryDefaultSort orders a single-category browse by the category's
+// configured default when the client didn't ask for a sort. Defaults outside the
+// listing sort whitelist are ignored.
+func (s *ListingService) applyCategoryDefaultSort(filter *repository.ListingFilter) {
+	if s.gameRegistry == nil || filter.SortBy != "" || len(filter.Categories) != 1 {
+		return
+	}
+	game := filter.Game
+	if game == "" {
+		game = defaultGame
+	}
+
+	sortBy, sortOrder := s.gameRegistry.CategoryDefaultSort(game, filter.Categories[0])
+	if _, ok := repository.ListingSortFields[sortBy]; !ok {
+		return
+	}
+	filter.SortBy = sortBy
+	if filter.SortOrder == "" {
+		filter.SortOrder = sortOrder
+	}
+}
+
+// ToFilter converts query parameters into a repository listing filter
+func (s *ListingService) ToFilter(req *dto.ListingFilterRequest) repository.ListingFilter {
 	// Parse affix filters (JSON string from query param)
 	var affixFilters []repository.AffixFilter
 	if req.AffixFilters != "" {
@@ -408,7 +1316,18 @@ func (s *ListingService) List(ctx context.Context, req *dto.ListingFilterRequest
 		}
 	}
 
-	filter := repository.ListingFilter{
+	// Parse seller activity window
+	var activeWithin *time.Duration
+	if req.ActiveWithinHours > 0 {
+		hours := req.ActiveWithinHours
+		if hours > maxActiveWithinHours {
+			hours = maxActiveWithinHours
+		}
+		d := time.Duration(hours) * time.Hour
+		activeWithin = &d
+	}
+
+	return repository.ListingFilter{
 		SellerID:        req.SellerID,
 		Query:           req.Q,
 		CatalogItemID:   req.CatalogItemID,
@@ -422,18 +1341,73 @@ func (s *ListingService) List(ctx context.Context, req *dto.ListingFilterRequest
 		Rarity:          req.Rarity,
 		AffixFilters:    affixFilters,
 		AskingForFilter: askingForFilter,
+		ActiveWithin:    activeWithin,
 		SortBy:          req.SortBy,
 		SortOrder:       req.SortOrder,
 		Offset:          req.GetOffset(),
 		Limit:           req.GetLimit(),
+		Ethereal:        req.Ethereal,
+		MinSockets:      req.MinSockets,
+		MaxSockets:      req.MaxSockets,
+		Quality:         req.Quality,
+	}
+}
+
+// ListByFilter retrieves listings using a pre-built filter. viewerID (empty when
+// anonymous) picks the feature flags that apply.
+func (s *ListingService) ListByFilter(ctx context.Context, filter repository.ListingFilter, viewerID string) ([]*models.Listing, int, bool, error) {
+	s.applyCategoryDefaultSort(&filter)
+	return s.listWithFallback(ctx, filter, viewerID)
+}
+
+// listWithFallback runs the exact search first. When a text query finds nothing it
+// retries with trigram similarity so misspelled item names still surface close
+// matches; fuzzy reports that the results came from that fallback. The retry only
+// happens when FlagFuzzySearch is on for the viewer.
+func (s *ListingService) listWithFallback(ctx context.Context, filter repository.ListingFilter, viewerID string) ([]*models.Listing, int, bool, error) {
+	listings, count, err := s.listWithCache(ctx, filter)
+	if err != nil || count > 0 || strings.TrimSpace(filter.Query) == "" {
+		return listings, count, false, err
+	}
+	if s.featureFlags != nil && !s.featureFlags.IsEnabled(ctx, viewerID, FlagFuzzySearch) {
+		return listings, count, false, nil
 	}
 
-	return s.listWithCache(ctx, filter)
+	filter.Fuzzy = true
+	listings, count, err = s.listWithCache(ctx, filter)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return listings, count, count > 0, nil
 }
 
-// ListByFilter retrieves listings using a pre-built filter
-func (s *ListingService) ListByFilter(ctx context.Context, filter repository.ListingFilter) ([]*models.Listing, int, error) {
-	return s.listWithCache(ctx, filter)
+// PruneAffixFilters removes affix filters that cannot roll on any of the filter's categories
+// and returns their codes, so a mismatched stat doesn't silently empty the results.
+// Filters are kept as-is when no category is selected.
+func PruneAffixFilters(filter *repository.ListingFilter) []string {
+	if len(filter.Categories) == 0 || len(filter.AffixFilters) == 0 {
+		return nil
+	}
+
+	var kept []repository.AffixFilter
+	var ignored []string
+	for _, af := range filter.AffixFilters {
+		applicable := false
+		for _, category := range filter.Categories {
+			if d2.IsStatApplicable(category, af.Code) {
+				applicable = true
+				break
+			}
+		}
+		if applicable {
+			kept = append(kept, af)
+		} else {
+			ignored = append(ignored, af.Code)
+		}
+	}
+
+	filter.AffixFilters = kept
+	return ignored
 }
 
 // filterCacheResult wraps listings and count for cache serialization
@@ -444,10 +1418,21 @@ type filterCacheResult struct {
 
 // listWithCache wraps repo.List with Redis caching (20s TTL)
 func (s *ListingService) listWithCache(ctx context.Context, filter repository.ListingFilter) ([]*models.Listing, int, error) {
+	PruneAffixFilters(&filter)
+
+	region, err := normalizeRegion(s.gameRegistry, filter.Game, filter.Region)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.Region = region
+	filter.ShadowThrottleScore = s.profileService.ShadowThrottleScore()
+
 	// Build cache key from filter params
 	params := map[string]interface{}{
 		"seller":    filter.SellerID,
+		"exclude":   filter.ExcludeSellerIDs,
 		"q":         filter.Query,
+		"fuzzy":     filter.Fuzzy,
 		"catalog":   filter.CatalogItemID,
 		"game":      filter.Game,
 		"ladder":    filter.Ladder,
@@ -458,7 +1443,11 @@ func (s *ListingService) listWithCache(ctx context.Context, filter repository.Li
 		"cats":      filter.Categories,
 		"rarity":    filter.Rarity,
 		"affixes":   filter.AffixFilters,
+		"ethereal":  filter.Ethereal,
+		"sockets":   []*int{filter.MinSockets, filter.MaxSockets},
+		"quality":   filter.Quality,
 		"askFor":    filter.AskingForFilter,
+		"active":    filter.ActiveWithin,
 		"sortBy":    filter.SortBy,
 		"sortOrd":   filter.SortOrder,
 		"offset":    filter.Offset,
@@ -499,6 +1488,15 @@ func (s *ListingService) GetTradeCount(ctx context.Context, listingID string) (i
 	return s.repo.CountByListingID(ctx, listingID)
 }
 
+// ToCreateResponse converts a newly created listing to its card plus its expiry
+func (s *ListingService) ToCreateResponse(listing *models.Listing) *dto.CreateListingResponse {
+	return &dto.CreateListingResponse{
+		ListingCardResponse: *s.ToCardResponse(listing),
+		ExpiresAt:           listing.ExpiresAt,
+		LifetimeDays:        int(math.Round(listing.ExpiresAt.Sub(listing.CreatedAt).Hours() / 24)),
+	}
+}
+
 // ToCardResponse converts a listing model to a lightweight card DTO for list views
 func (s *ListingService) ToCardResponse(listing *models.Listing) *dto.ListingCardResponse {
 	resp := &dto.ListingCardResponse{
@@ -509,7 +1507,7 @@ func (s *ListingService) ToCardResponse(listing *models.Listing) *dto.ListingCar
 		Rarity:         listing.Rarity,
 		ImageURL:       listing.GetImageURL(),
 		CatalogItemID:  listing.GetCatalogItemID(),
-		Stats:          s.transformCardStats(listing.Stats),
+		Stats:          s.orderStats(listing.Game, s.transformCardStats(listing.Stats)),
 		AskingFor:      listing.AskingFor,
 		AskingPrice:    listing.GetAskingPrice(),
 		Amount:         listing.Amount,
@@ -518,15 +1516,29 @@ func (s *ListingService) ToCardResponse(listing *models.Listing) *dto.ListingCar
 		Hardcore:       listing.Hardcore,
 		IsNonRotw:      listing.IsNonRotw,
 		Platforms:      listing.Platforms,
-		Region:         listing.Region,
+		Region:         displayRegion(s.gameRegistry, listing.Game, listing.Region),
 		SellerTimezone: listing.GetSellerTimezone(),
 		Views:          listing.Views,
 		CreatedAt:      listing.CreatedAt,
+		Ethereal:       listing.Ethereal,
+		Sockets:        listing.Sockets,
+		Quality:        listing.Quality,
+	}
+
+	// Sellers can keep exact rolls off the card; the detail view still has them
+	if listing.HideStatsOnCard {
+		resp.Stats = nil
+		resp.StatsHidden = true
 	}
 
 	if listing.Seller != nil {
 		resp.Seller = s.profileService.ToResponse(listing.Seller)
 		resp.IsBoosted = listing.Seller.IsPremium && time.Since(listing.CreatedAt) < PremiumBoostDuration
+		if !listing.Seller.LastActiveAt.IsZero() {
+			lastActive := listing.Seller.LastActiveAt
+			resp.SellerLastActive = &lastActive
+			resp.SellerOnline = time.Since(lastActive) < SellerOnlineWindow
+		}
 	}
 
 	return resp
@@ -535,35 +1547,43 @@ func (s *ListingService) ToCardResponse(listing *models.Listing) *dto.ListingCar
 // ToResponse converts a listing model to a full DTO response
 func (s *ListingService) ToResponse(listing *models.Listing) *dto.ListingResponse {
 	resp := &dto.ListingResponse{
-		ID:             listing.ID,
-		SellerID:       listing.SellerID,
-		Name:           listing.Name,
-		ItemType:       listing.ItemType,
-		Rarity:         listing.Rarity,
-		ImageURL:       listing.GetImageURL(),
-		Category:       listing.Category,
-		CatalogItemID:  listing.GetCatalogItemID(),
-		Stats:          s.transformAllStats(listing.Stats),
-		Suffixes:       listing.Suffixes,
-		Runes:          s.transformRunes(listing.Runes),
-		RuneOrder:      listing.GetRuneOrder(),
-		BaseItemCode:   listing.GetBaseItemCode(),
-		BaseItemName:   listing.GetBaseItemName(),
-		AskingFor:      listing.AskingFor,
-		AskingPrice:    listing.GetAskingPrice(),
-		Amount:         listing.Amount,
-		Notes:          listing.GetNotes(),
-		Game:           listing.Game,
-		Ladder:         listing.Ladder,
-		Hardcore:       listing.Hardcore,
-		IsNonRotw:      listing.IsNonRotw,
-		Platforms:      listing.Platforms,
-		Region:         listing.Region,
-		SellerTimezone: listing.GetSellerTimezone(),
-		Status:         listing.Status,
-		Views:          listing.Views,
-		CreatedAt:      listing.CreatedAt,
-		ExpiresAt:      listing.ExpiresAt,
+		ID:              listing.ID,
+		SellerID:        listing.SellerID,
+		Name:            listing.Name,
+		ItemType:        listing.ItemType,
+		Rarity:          listing.Rarity,
+		ImageURL:        listing.GetImageURL(),
+		Category:        listing.Category,
+		CatalogItemID:   listing.GetCatalogItemID(),
+		Stats:           s.orderStats(listing.Game, s.transformAllStats(listing.Stats)),
+		Suffixes:        listing.Suffixes,
+		Runes:           s.transformRunes(listing.Runes),
+		RuneOrder:       listing.GetRuneOrder(),
+		BaseItemCode:    listing.GetBaseItemCode(),
+		BaseItemName:    listing.GetBaseItemName(),
+		AskingFor:       listing.AskingFor,
+		AskingPrice:     listing.GetAskingPrice(),
+		Amount:          listing.Amount,
+		Notes:           listing.GetNotes(),
+		Game:            listing.Game,
+		Ladder:          listing.Ladder,
+		Hardcore:        listing.Hardcore,
+		IsNonRotw:       listing.IsNonRotw,
+		Platforms:       listing.Platforms,
+		Region:          displayRegion(s.gameRegistry, listing.Game, listing.Region),
+		SellerTimezone:  listing.GetSellerTimezone(),
+		Status:          listing.Status,
+		Views:           listing.Views,
+		CreatedAt:       listing.CreatedAt,
+		ExpiresAt:       listing.ExpiresAt,
+		ReservedFor:     listing.GetReservedFor(),
+		ReservedUntil:   listing.ReservedUntil,
+		HideStatsOnCard: listing.HideStatsOnCard,
+
+		BuyerRequirements: toBuyerRequirementsResponse(listing.BuyerRequirements()),
+		Ethereal:          listing.Ethereal,
+		Sockets:           listing.Sockets,
+		Quality:           listing.Quality,
 	}
 
 	if listing.Seller != nil {
@@ -623,6 +1643,41 @@ func (s *ListingService) transformAllStats(rawStats json.RawMessage) []dto.ItemS
 	return s.doTransformStats(rawStats, false)
 }
 
+// orderStats sorts stats into the game's display order so every listing shows
+// them the same way. Codes without a configured position keep their stored order
+// after the ranked ones.
+func (s *ListingService) orderStats(game string, stats []dto.ItemStat) []dto.ItemStat {
+	if s.gameRegistry == nil || len(stats) < 2 {
+		return stats
+	}
+	if game == "" {
+		game = defaultGame
+	}
+
+	ranks := make([]int, len(stats))
+	for i, stat := range stats {
+		rank, ok := s.gameRegistry.StatDisplayRank(game, stat.Code)
+		if !ok {
+			rank = math.MaxInt
+		}
+		ranks[i] = rank
+	}
+
+	indexes := make([]int, len(stats))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return ranks[indexes[a]] < ranks[indexes[b]]
+	})
+
+	ordered := make([]dto.ItemStat, len(stats))
+	for i, idx := range indexes {
+		ordered[i] = stats[idx]
+	}
+	return ordered
+}
+
 // doTransformStats converts raw JSON stats to DTOs with display text
 // When variableOnly is true, only stats with isVariable=true are included (for card views)
 // When variableOnly is false, all stats are included with the isVariable flag preserved (for detail views)
@@ -687,33 +1742,152 @@ func (s *ListingService) transformRunes(rawRunes json.RawMessage) []dto.RuneInfo
 		result = append(result, dto.RuneInfo{
 			Code:     code,
 			Name:     d2.GetRuneName(code),
-			ImageURL: d2.GetRuneImageURL(code),
+			ImageURL: s.imageChecker.Resolve(d2.GetRuneImageURL(code)),
 		})
 	}
 
 	return result
 }
 
-// IncrementViews increments the view count for a listing
-func (s *ListingService) IncrementViews(ctx context.Context, id string) error {
+// IncrementViews increments the view count for a listing and, when the viewer is
+// known, records it in their recently viewed list. With view buffering on, the view
+// is counted in Redis and the cached listing is left alone; GetByID adds the buffer.
+func (s *ListingService) IncrementViews(ctx context.Context, id string, viewerID string) error {
+	if s.buffersViews() && s.bufferView(ctx, id) == nil {
+		if viewerID != "" {
+			s.pushToRecentlyViewed(ctx, id, viewerID)
+		}
+		return nil
+	}
+
 	if err := s.repo.IncrementViews(ctx, id); err != nil {
 		return err
 	}
 	// Invalidate listing cache
 	_ = s.invalidator.InvalidateListing(ctx, id)
 	_ = s.invalidator.InvalidateListingDTO(ctx, id)
+
+	if viewerID != "" {
+		s.pushToRecentlyViewed(ctx, id, viewerID)
+	}
 	return nil
 }
 
+// pushToRecentlyViewed moves the listing's card to the top of the viewer's recently
+// viewed list, capped like home:recent. Sellers viewing their own listings aren't recorded.
+func (s *ListingService) pushToRecentlyViewed(ctx context.Context, id string, viewerID string) {
+	listing, err := s.GetByID(ctx, id)
+	if err != nil || listing.SellerID == viewerID {
+		return
+	}
+
+	data, err := json.Marshal(s.ToCardResponse(listing))
+	if err != nil {
+		return
+	}
+	key := cache.RecentlyViewedKey(viewerID)
+	removeFromRecentCache(s.redis, ctx, key, id)
+	_ = s.redis.LPush(ctx, key, string(data))
+	_ = s.redis.LTrim(ctx, key, 0, int64(maxRecentListings-1))
+	_ = s.redis.Expire(ctx, key, recentlyViewedTTL)
+}
+
+// GetRecentlyViewed returns the listings a user viewed most recently, newest first.
+// Cards are snapshots from the time of the view.
+func (s *ListingService) GetRecentlyViewed(ctx context.Context, userID string) ([]dto.ListingCardResponse, error) {
+	return getRecentFromCache(s.redis, ctx, cache.RecentlyViewedKey(userID))
+}
+
+// GetSimilar returns up to limit active listings like the given one in the same game,
+// newest first, leaving out the listing itself and the rest of its seller's. Listings
+// with the same base item come first; the rest is filled with the same category and
+// rarity. Results are cached briefly per listing.
+func (s *ListingService) GetSimilar(ctx context.Context, listingID string, limit int) ([]dto.ListingCardResponse, error) {
+	if limit <= 0 {
+		limit = DefaultSimilarListings
+	}
+	limit = min(limit, MaxSimilarListings)
+
+	cacheKey := cache.SimilarListingsKey(listingID, limit)
+	if cached, err := s.redis.Get(ctx, cacheKey); err == nil && cached != "" {
+		var cards []dto.ListingCardResponse
+		if json.Unmarshal([]byte(cached), &cards) == nil {
+			return cards, nil
+		}
+	}
+
+	base, err := s.GetByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.ListingFilter{
+		Game:             base.Game,
+		ExcludeIDs:       []string{base.ID},
+		ExcludeSellerIDs: []string{base.SellerID},
+		SortBy:           "created_at",
+		SortOrder:        "desc",
+	}
+
+	var similar []*models.Listing
+	if name := base.GetBaseItemName(); name != "" {
+		byName := filter
+		byName.BaseItemName = name
+		byName.Limit = limit
+		listings, _, err := s.repo.List(ctx, byName)
+		if err != nil {
+			return nil, err
+		}
+		similar = listings
+	}
+
+	if len(similar) < limit && base.Category != "" {
+		byKind := filter
+		byKind.Categories = []string{base.Category}
+		byKind.Rarity = base.Rarity
+		byKind.Limit = limit - len(similar)
+		for _, listing := range similar {
+			byKind.ExcludeIDs = append(byKind.ExcludeIDs, listing.ID)
+		}
+		listings, _, err := s.repo.List(ctx, byKind)
+		if err != nil {
+			return nil, err
+		}
+		similar = append(similar, listings...)
+	}
+
+	cards := make([]dto.ListingCardResponse, 0, len(similar))
+	for _, listing := range similar {
+		cards = append(cards, *s.ToCardResponse(listing))
+	}
+
+	if data, err := json.Marshal(cards); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), similarListingsCacheTTL)
+	}
+
+	return cards, nil
+}
+
 // ToDetailResponse converts a listing model to a detailed DTO response
 func (s *ListingService) ToDetailResponse(ctx context.Context, listing *models.Listing) *dto.ListingDetailResponse {
 	tradeCount, _ := s.GetTradeCount(ctx, listing.ID)
 
-	return &dto.ListingDetailResponse{
+	resp := &dto.ListingDetailResponse{
 		ListingResponse: *s.ToResponse(listing),
 		UpdatedAt:       listing.UpdatedAt,
 		TradeCount:      tradeCount,
 	}
+
+	if maxPending := pendingOfferLimit(listing); maxPending > 0 {
+		remaining := maxPending
+		if pending, err := s.repo.CountPendingOffers(ctx, listing.ID); err == nil {
+			remaining = max(maxPending-pending, 0)
+		}
+		resp.MaxPendingOffers = &maxPending
+		resp.PendingOffersRemaining = &remaining
+	}
+
+	return resp
 }
 
 // cacheListingDTO caches the listing as a DTO (camelCase JSON) for frontend direct access
@@ -724,8 +1898,18 @@ func (s *ListingService) cacheListingDTO(ctx context.Context, listing *models.Li
 	}
 }
 
-// pushToRecentListings adds a listing to the home:recent Redis list
+// pushToRecentListings adds a listing to the home:recent Redis list. Listings from
+// shadow-throttled sellers are held back and pushed once the throttle delay has passed.
 func (s *ListingService) pushToRecentListings(ctx context.Context, listing *models.Listing) {
+	if s.profileService.IsShadowThrottled(listing.Seller, listing.CreatedAt) {
+		s.delayRecentPush(ctx, listing)
+		return
+	}
+	s.pushRecentCard(ctx, listing)
+}
+
+// pushRecentCard puts the listing's card at the top of home:recent
+func (s *ListingService) pushRecentCard(ctx context.Context, listing *models.Listing) {
 	cardResp := s.ToCardResponse(listing)
 	data, err := json.Marshal(cardResp)
 	if err != nil {
@@ -740,6 +1924,22 @@ func (s *ListingService) removeFromRecentListings(ctx context.Context, id string
 	removeFromRecentCache(s.redis, ctx, cache.HomeRecentKey(), id)
 }
 
+// PushRelisted moves a listing that was just relisted, or is back from a trade hold,
+// to the top of the recent cache and drops its stale cache entries
+func (s *ListingService) PushRelisted(ctx context.Context, listing *models.Listing) {
+	_ = s.invalidator.InvalidateListing(ctx, listing.ID)
+	_ = s.invalidator.InvalidateListingDTO(ctx, listing.ID)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	if listing.Seller == nil {
+		if profile, err := s.profileService.GetByID(ctx, listing.SellerID); err == nil {
+			listing.Seller = profile
+		}
+	}
+	s.removeFromRecentListings(ctx, listing.ID)
+	s.pushToRecentListings(ctx, listing)
+}
+
 // RemoveFromRecentByListing removes a listing from the recent cache
 func (s *ListingService) RemoveFromRecentByListing(ctx context.Context, listing *models.Listing) {
 	s.removeFromRecentListings(ctx, listing.ID)
@@ -823,6 +2023,10 @@ func (s *ListingService) WarmRecentListings(ctx context.Context) {
 
 	// Push in reverse order so newest is at index 0
 	for i := len(listings) - 1; i >= 0; i-- {
+		if s.profileService.IsShadowThrottled(listings[i].Seller, listings[i].CreatedAt) {
+			s.delayRecentPush(ctx, listings[i])
+			continue
+		}
 		cardResp := s.ToCardResponse(listings[i])
 		data, err := json.Marshal(cardResp)
 		if err != nil {
@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
@@ -119,6 +124,190 @@ func TestListingCreate_FreeUser_AtLimit(t *testing.T) {
 	listingRepo.AssertExpectations(t)
 }
 
+func TestListingCreate_RelistCooldown_BlocksIdenticalSale(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	txRepo := new(mocks.MockTransactionRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetRelistCooldown(txRepo, 24*time.Hour)
+
+	stats := json.RawMessage(`[{"code":"ed%","value":140}]`)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+	txRepo.On("HasRecentSale", mock.Anything, testSellerID, "Shako", stats, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "americas",
+		Stats:     stats,
+	}
+
+	_, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestListingCreate_RelistCooldown_DisabledByDefault(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	txRepo := new(mocks.MockTransactionRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetRelistCooldown(txRepo, 0)
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+	listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "americas",
+	}
+
+	_, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.NoError(t, err)
+	txRepo.AssertNotCalled(t, "HasRecentSale", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListingCreate_DuplicateListings(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		found     bool
+		wantErr   error
+		wantReuse bool
+	}{
+		{"allowed by default", "", true, nil, false},
+		{"reject mode blocks a duplicate", DuplicateListingsReject, true, ErrAlreadyExists, false},
+		{"reuse mode returns the existing listing", DuplicateListingsReuse, true, nil, true},
+		{"reject mode creates when there is no duplicate", DuplicateListingsReject, false, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profileRepo := new(mocks.MockProfileRepository)
+			listingRepo := new(mocks.MockListingRepository)
+			svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+			svc.SetDuplicateListingMode(tt.mode)
+
+			existing := testListing(testListingID, testSellerID)
+			profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+			listingRepo.On("ExistsActiveByFingerprint", mock.Anything, testSellerID, mock.AnythingOfType("string")).
+				Return(testListingID, tt.found, nil)
+			listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(existing, nil)
+			listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+			req := &dto.CreateListingRequest{
+				Name:      "Shako",
+				ItemType:  "unique",
+				Rarity:    "unique",
+				Category:  "helm",
+				Game:      "diablo2",
+				Platforms: []string{"pc"},
+				Region:    "americas",
+			}
+
+			listing, err := svc.Create(context.Background(), testSellerID, req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantReuse {
+				assert.Same(t, existing, listing)
+				listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NotNil(t, listing.Fingerprint)
+			listingRepo.AssertCalled(t, "Create", mock.Anything, mock.AnythingOfType("*models.Listing"))
+			if tt.mode == "" {
+				listingRepo.AssertNotCalled(t, "ExistsActiveByFingerprint", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListingComputeFingerprint(t *testing.T) {
+	base := &models.Listing{
+		Name:      "Shako",
+		Stats:     json.RawMessage(`[{"code":"ed%","value":140}]`),
+		Game:      "diablo2",
+		Platforms: []string{"pc", "xbox"},
+	}
+	same := &models.Listing{
+		Name:      " shako ",
+		Stats:     json.RawMessage(`[ { "value": 140, "code": "ed%" } ]`),
+		Game:      "diablo2",
+		Platforms: []string{"xbox", "pc"},
+	}
+	otherPlatform := &models.Listing{
+		Name:      "Shako",
+		Stats:     json.RawMessage(`[{"code":"ed%","value":140}]`),
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+	}
+
+	assert.Equal(t, base.ComputeFingerprint(), same.ComputeFingerprint())
+	assert.NotEqual(t, base.ComputeFingerprint(), otherPlatform.ComputeFingerprint())
+}
+
+func TestListingCreate_LifetimeByTier(t *testing.T) {
+	tests := []struct {
+		name         string
+		premium      bool
+		free         time.Duration
+		premiumLife  time.Duration
+		wantLifetime time.Duration
+	}{
+		{"free seller default", false, 0, 0, DefaultListingLifetime},
+		{"premium seller default", true, 0, 0, DefaultPremiumListingLifetime},
+		{"configured free lifetime", false, 20 * 24 * time.Hour, 90 * 24 * time.Hour, 20 * 24 * time.Hour},
+		{"configured premium lifetime", true, 20 * 24 * time.Hour, 90 * 24 * time.Hour, 90 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profileRepo := new(mocks.MockProfileRepository)
+			listingRepo := new(mocks.MockListingRepository)
+			svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+			svc.SetListingLifetimes(tt.free, tt.premiumLife)
+
+			profile := testProfile(testSellerID)
+			profile.IsPremium = tt.premium
+			profileRepo.On("GetByID", mock.Anything, testSellerID).Return(profile, nil)
+			listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(0, nil)
+			listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+			req := &dto.CreateListingRequest{
+				Name:      "Shako",
+				ItemType:  "unique",
+				Rarity:    "unique",
+				Category:  "helm",
+				Game:      "diablo2",
+				Platforms: []string{"pc"},
+				Region:    "americas",
+			}
+
+			listing, err := svc.Create(context.Background(), testSellerID, req)
+
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(tt.wantLifetime), listing.ExpiresAt, time.Minute)
+			assert.Equal(t, int(tt.wantLifetime.Hours()/24), svc.ToCreateResponse(listing).LifetimeDays)
+		})
+	}
+}
+
 func TestListingCreate_DeduplicatesPlatforms(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
@@ -222,6 +411,72 @@ func TestListingCreate_OptionalFieldsEmpty(t *testing.T) {
 	listingRepo.AssertExpectations(t)
 }
 
+func TestListingCreate_IdempotencyKey_ReturnsExisting(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc, _ := setupListingService(profileRepo, listingRepo, redisClient)
+
+	profile := testProfile(testSellerID, withPremium)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(profile, nil)
+	listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil).Once()
+
+	req := &dto.CreateListingRequest{
+		Name:           "Shako",
+		ItemType:       "unique",
+		Rarity:         "unique",
+		Category:       "helm",
+		Game:           "diablo2",
+		Platforms:      []string{"pc"},
+		Region:         "americas",
+		IdempotencyKey: "req-1",
+	}
+
+	first, err := svc.Create(context.Background(), testSellerID, req)
+	assert.NoError(t, err)
+
+	listingRepo.On("GetByID", mock.Anything, first.ID).Return(first, nil)
+
+	second, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	listingRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestListingCreate_IdempotencyKey_InProgress(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redis, _ := newTestRedisReal(t)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	ctx := context.Background()
+	_ = redis.Set(ctx, cache.IdempotencyKey("listing", testSellerID, "req-1"), idempotencyPending, time.Minute)
+
+	_, err := svc.Create(ctx, testSellerID, &dto.CreateListingRequest{Name: "Shako", IdempotencyKey: "req-1"})
+
+	assert.ErrorIs(t, err, ErrRequestInProgress)
+	listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestListingCreate_IdempotencyKey_ReleasedOnFailure(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redis, _ := newTestRedisReal(t)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	profile := testProfile(testSellerID)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(profile, nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit, nil)
+
+	ctx := context.Background()
+	_, err := svc.Create(ctx, testSellerID, &dto.CreateListingRequest{Name: "Shako", IdempotencyKey: "req-1"})
+
+	assert.ErrorIs(t, err, ErrListingLimitReached)
+	exists, _ := redis.Exists(ctx, cache.IdempotencyKey("listing", testSellerID, "req-1"))
+	assert.False(t, exists)
+}
+
 // ---------------------------------------------------------------------------
 // GetByID
 // ---------------------------------------------------------------------------
@@ -324,6 +579,45 @@ func TestListingUpdate_NotOwner(t *testing.T) {
 	listingRepo.AssertExpectations(t)
 }
 
+func TestListingUpdate_Delegate(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		wantErr     error
+	}{
+		{"with manage_listings", []string{models.DelegatePermissionManageListings}, nil},
+		{"with only respond_offers", []string{models.DelegatePermissionRespondOffers}, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profileRepo := new(mocks.MockProfileRepository)
+			listingRepo := new(mocks.MockListingRepository)
+			delegateRepo := new(mocks.MockDelegateRepository)
+			svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+			svc.SetDelegateRepository(delegateRepo)
+
+			existing := testListing(testListingID, testSellerID)
+			listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+			listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+			delegateRepo.On("GetByOwnerAndDelegate", mock.Anything, testSellerID, testUserID).
+				Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testUserID, Permissions: tt.permissions}, nil)
+
+			notes := "Managed by a delegate"
+			_, err := svc.Update(context.Background(), testListingID, testUserID, &dto.UpdateListingRequest{Notes: &notes})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testSellerID, existing.SellerID)
+			listingRepo.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("*models.Listing"))
+		})
+	}
+}
+
 func TestListingUpdate_InvalidatesCache(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
@@ -355,187 +649,752 @@ func TestListingUpdate_InvalidatesCache(t *testing.T) {
 	listingRepo.AssertExpectations(t)
 }
 
-// ---------------------------------------------------------------------------
-// Delete
-// ---------------------------------------------------------------------------
-
-func TestListingDelete_Success(t *testing.T) {
+func TestListingUpdate_StatusActiveToPaused(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
 	existing := testListing(testListingID, testSellerID)
 	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
-	listingRepo.On("Update", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
-		return l.Status == "cancelled"
-	})).Return(nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
 
-	err := svc.Delete(context.Background(), testListingID, testSellerID)
+	status := "paused"
+	result, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Status: &status})
 
 	assert.NoError(t, err)
-	listingRepo.AssertExpectations(t)
+	assert.Equal(t, "paused", result.Status)
 }
 
-func TestListingDelete_NotOwner(t *testing.T) {
+func TestListingUpdate_SetsMaxPendingOffers(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
 	existing := testListing(testListingID, testSellerID)
 	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
 
-	err := svc.Delete(context.Background(), testListingID, "other-user-id")
+	result, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{MaxPendingOffers: intPtr(5)})
 
-	assert.ErrorIs(t, err, ErrForbidden)
-	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
-	listingRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.MaxPendingOffers)
+	assert.Equal(t, 5, *result.MaxPendingOffers)
 }
 
-// ---------------------------------------------------------------------------
-// Stats Transformation
-// ---------------------------------------------------------------------------
+func TestListingUpdate_TogglesHideStatsOnCard(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-func TestTransformCardStats_OnlyVariable(t *testing.T) {
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	hide := true
+	result, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{HideStatsOnCard: &hide})
+
+	assert.NoError(t, err)
+	assert.True(t, result.HideStatsOnCard)
+}
+
+func TestListingToDetailResponse_PendingOfferCapacity(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"ac%","value":163,"displayText":"+163% Enhanced Defense","isVariable":true},
-		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
-		{"code":"dr","value":10,"displayText":"Damage Reduced by 10%","isVariable":true}
-	]`)
+	listing := testListing(testListingID, testSellerID)
+	listing.MaxPendingOffers = intPtr(5)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(2, nil)
+	listingRepo.On("CountPendingOffers", mock.Anything, testListingID).Return(3, nil)
 
-	result := svc.transformCardStats(rawStats)
+	resp := svc.ToDetailResponse(context.Background(), listing)
 
-	assert.Len(t, result, 2)
-	assert.Equal(t, "ac%", result[0].Code)
-	assert.True(t, result[0].IsVariable)
-	assert.Equal(t, "dr", result[1].Code)
-	assert.True(t, result[1].IsVariable)
+	require.NotNil(t, resp.MaxPendingOffers)
+	assert.Equal(t, 5, *resp.MaxPendingOffers)
+	require.NotNil(t, resp.PendingOffersRemaining)
+	assert.Equal(t, 2, *resp.PendingOffersRemaining)
 }
 
-func TestTransformCardStats_EmptyStats(t *testing.T) {
+func TestListingToDetailResponse_CapacityNeverNegative(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	// nil raw message
-	result := svc.transformCardStats(nil)
-	assert.Nil(t, result)
+	listing := testListing(testListingID, testSellerID)
+	listing.MaxPendingOffers = intPtr(DefaultMaxPendingOffers)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(0, nil)
+	listingRepo.On("CountPendingOffers", mock.Anything, testListingID).Return(DefaultMaxPendingOffers+3, nil)
 
-	// empty raw message
-	result2 := svc.transformCardStats(json.RawMessage{})
-	assert.Nil(t, result2)
+	resp := svc.ToDetailResponse(context.Background(), listing)
+
+	require.NotNil(t, resp.PendingOffersRemaining)
+	assert.Equal(t, 0, *resp.PendingOffersRemaining)
 }
 
-func TestTransformCardStats_NoVariableStats(t *testing.T) {
+func TestListingToDetailResponse_LegacyListingHasNoCap(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
-		{"code":"dex","value":5,"displayText":"+5 to Dexterity","isVariable":false}
-	]`)
+	// Listings created before the cap existed have no max_pending_offers
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(0, nil)
 
-	result := svc.transformCardStats(rawStats)
+	resp := svc.ToDetailResponse(context.Background(), listing)
 
-	assert.NotNil(t, result)
-	assert.Empty(t, result)
+	assert.Nil(t, resp.MaxPendingOffers)
+	assert.Nil(t, resp.PendingOffersRemaining)
+	listingRepo.AssertNotCalled(t, "CountPendingOffers", mock.Anything, mock.Anything)
 }
 
-func TestTransformAllStats_AllIncluded(t *testing.T) {
+func TestListingGetByIDForViewer_Owner(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"ac%","value":163,"displayText":"+163% Enhanced Defense","isVariable":true},
-		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
-		{"code":"dr","value":10,"displayText":"Damage Reduced by 10%","isVariable":true}
-	]`)
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(listing, nil)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(0, nil)
+	listingRepo.On("CountPendingOffers", mock.Anything, testListingID).Return(0, nil)
 
-	result := svc.transformAllStats(rawStats)
+	resp, err := svc.GetByIDForViewer(context.Background(), testListingID, testSellerID)
 
-	assert.Len(t, result, 3)
+	assert.NoError(t, err)
+	assert.True(t, resp.IsOwner)
+	assert.False(t, resp.CanOffer)
+	listingRepo.AssertNotCalled(t, "HasPendingOfferFrom", mock.Anything, mock.Anything, mock.Anything)
+}
 
-	// First stat: variable
-	assert.Equal(t, "ac%", result[0].Code)
-	assert.Equal(t, intPtr(163), result[0].Value)
-	assert.Equal(t, "+163% Enhanced Defense", result[0].DisplayText)
-	assert.True(t, result[0].IsVariable)
+func TestListingGetByIDForViewer_BuyerCanOffer(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	// Second stat: not variable
-	assert.Equal(t, "str", result[1].Code)
-	assert.Equal(t, intPtr(2), result[1].Value)
-	assert.False(t, result[1].IsVariable)
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(listing, nil)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(0, nil)
+	listingRepo.On("CountPendingOffers", mock.Anything, testListingID).Return(0, nil)
+	listingRepo.On("HasPendingOfferFrom", mock.Anything, testListingID, testBuyerID).Return(false, nil)
 
-	// Third stat: variable
-	assert.Equal(t, "dr", result[2].Code)
-	assert.True(t, result[2].IsVariable)
+	resp, err := svc.GetByIDForViewer(context.Background(), testListingID, testBuyerID)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.IsOwner)
+	assert.False(t, resp.HasPendingOffer)
+	assert.True(t, resp.CanOffer)
 }
 
-func TestTransformAllStats_DisplayTextFallback(t *testing.T) {
+func TestListingGetByIDForViewer_PendingOfferBlocksNewOffer(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"ac%","value":100,"displayText":""},
-		{"code":"mana","displayText":""}
-	]`)
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(listing, nil)
+	listingRepo.On("CountByListingID", mock.Anything, testListingID).Return(1, nil)
+	listingRepo.On("CountPendingOffers", mock.Anything, testListingID).Return(1, nil)
+	listingRepo.On("HasPendingOfferFrom", mock.Anything, testListingID, testBuyerID).Return(true, nil)
 
-	result := svc.transformAllStats(rawStats)
+	resp, err := svc.GetByIDForViewer(context.Background(), testListingID, testBuyerID)
 
-	assert.Len(t, result, 2)
-	// With value: fallback to "code: value"
-	assert.Equal(t, "ac%: 100", result[0].DisplayText)
-	// Without numeric value: fallback to just code
-	assert.Equal(t, "mana", result[1].DisplayText)
+	assert.NoError(t, err)
+	assert.True(t, resp.HasPendingOffer)
+	assert.False(t, resp.CanOffer)
 }
 
-func TestTransformAllStats_StringValue(t *testing.T) {
+func TestListingGetByIDForViewer_ReservedForOtherBuyer(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"ed%","value":"+163% Enhanced Defense","displayText":"+163% Enhanced Defense","isVariable":true}
-	]`)
+	listing := testListing(testListingID, testSellerID, withReservation(testUserID, time.Now().Add(time.Hour)))
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(listing, nil)
 
-	result := svc.transformAllStats(rawStats)
+	_, err := svc.GetByIDForViewer(context.Background(), testListingID, testBuyerID)
 
-	assert.Len(t, result, 1)
-	assert.Equal(t, "ed%", result[0].Code)
-	// extractNumericValue should extract 163 from the string
-	assert.NotNil(t, result[0].Value)
-	assert.Equal(t, 163, *result[0].Value)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
 }
 
-func TestTransformAllStats_NilValue(t *testing.T) {
+func TestListingUpdate_CompletedToActive_InvalidState(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
 	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
 
-	rawStats := json.RawMessage(`[
-		{"code":"skill","displayText":"All Skills","isVariable":false}
-	]`)
+	existing := testListing(testListingID, testSellerID, withListingStatus("completed"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
 
-	result := svc.transformAllStats(rawStats)
+	status := "active"
+	_, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Status: &status})
 
-	assert.Len(t, result, 1)
-	assert.Equal(t, "skill", result[0].Code)
-	assert.Nil(t, result[0].Value)
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 }
 
-func TestExtractNumericValue(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    interface{}
-		expected *int
-	}{
-		{
+func TestListingUpdate_SetCompleted_InvalidState(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	status := "completed"
+	_, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Status: &status})
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Pause / Resume
+// ---------------------------------------------------------------------------
+
+func TestListingPause_Success(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.Status == "paused"
+	})).Return(nil)
+
+	result, err := svc.Pause(context.Background(), testListingID, testSellerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "paused", result.Status)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingPause_NotActive(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withListingStatus("paused"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	_, err := svc.Pause(context.Background(), testListingID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestListingPause_NotOwner(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	_, err := svc.Pause(context.Background(), testListingID, "other-user-id")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestListingResume_Success(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withListingStatus("paused"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(3, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+
+	result, err := svc.Resume(context.Background(), testListingID, testSellerID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "active", result.Status)
+}
+
+func TestListingResume_FreeUserAtLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withListingStatus("paused"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit, nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+
+	_, err := svc.Resume(context.Background(), testListingID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrListingLimitReached)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Reserve
+// ---------------------------------------------------------------------------
+
+func TestListingReserve_Success(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetNotificationService(NewNotificationService(notifRepo, newTestRedis()))
+
+	until := time.Now().Add(24 * time.Hour)
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	profileRepo.On("GetByID", mock.Anything, testBuyerID).Return(testProfile(testBuyerID), nil)
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testBuyerID && n.Type == models.NotificationTypeListingReserved
+	})).Return(nil)
+
+	result, err := svc.Reserve(context.Background(), testListingID, testSellerID, testBuyerID, until)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "reserved", result.Status)
+	assert.Equal(t, testBuyerID, result.GetReservedFor())
+	assert.True(t, result.IsReservedFor(testBuyerID))
+	notifRepo.AssertExpectations(t)
+}
+
+func TestListingReserve_NotOwner(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	_, err := svc.Reserve(context.Background(), testListingID, testUserID, testBuyerID, time.Now().Add(time.Hour))
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestListingReserve_ForSelf(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	_, err := svc.Reserve(context.Background(), testListingID, testSellerID, testSellerID, time.Now().Add(time.Hour))
+
+	assert.ErrorIs(t, err, ErrSelfAction)
+}
+
+func TestListingReserve_InvalidPeriod(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	_, err := svc.Reserve(context.Background(), testListingID, testSellerID, testBuyerID, time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidReservation)
+
+	_, err = svc.Reserve(context.Background(), testListingID, testSellerID, testBuyerID, time.Now().Add(MaxReservationDuration+time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidReservation)
+}
+
+func TestListingReserve_NotActive(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withListingStatus("paused"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	_, err := svc.Reserve(context.Background(), testListingID, testSellerID, testBuyerID, time.Now().Add(time.Hour))
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestListingUpdate_ReleaseReservation(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(time.Hour)))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+
+	status := "active"
+	result, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Status: &status})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "active", result.Status)
+	assert.Nil(t, result.ReservedFor)
+	assert.Nil(t, result.ReservedUntil)
+	// Reserved listings already count toward the limit, so releasing doesn't re-check it
+	listingRepo.AssertNotCalled(t, "CountActiveBySellerID", mock.Anything, mock.Anything)
+}
+
+func TestListingExpireStale(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("ExpireStale", mock.Anything, mock.AnythingOfType("time.Time"), expireStaleBatchSize).Return([]string{"listing-1", "listing-2"}, nil)
+	listingRepo.On("ReleaseExpiredReservations", mock.Anything, mock.AnythingOfType("time.Time")).Return([]string{testListingID}, nil)
+
+	result, err := svc.ExpireStale(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Expired)
+	assert.Equal(t, 1, result.Released)
+}
+
+func TestListingExpireStale_PagesThroughBacklog(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	full := make([]string, expireStaleBatchSize)
+	for i := range full {
+		full[i] = fmt.Sprintf("listing-%d", i)
+	}
+	listingRepo.On("ExpireStale", mock.Anything, mock.AnythingOfType("time.Time"), expireStaleBatchSize).Return(full, nil).Twice()
+	listingRepo.On("ExpireStale", mock.Anything, mock.AnythingOfType("time.Time"), expireStaleBatchSize).Return([]string{"listing-last"}, nil).Once()
+	listingRepo.On("ReleaseExpiredReservations", mock.Anything, mock.AnythingOfType("time.Time")).Return([]string{}, nil)
+
+	result, err := svc.ExpireStale(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2*expireStaleBatchSize+1, result.Expired)
+	listingRepo.AssertNumberOfCalls(t, "ExpireStale", 3)
+}
+
+func TestListingRenew_ReactivatesExpiredListing(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Status = "expired"
+	listing.ExpiresAt = time.Now().Add(-time.Hour)
+	createdAt := listing.CreatedAt
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(listing, nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit-1, nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	result, err := svc.Renew(context.Background(), testListingID, testSellerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "active", result.Status)
+	assert.WithinDuration(t, time.Now().Add(DefaultListingLifetime), result.ExpiresAt, time.Minute)
+	assert.Equal(t, createdAt, result.CreatedAt)
+}
+
+func TestListingRenew_ExpiredRespectsFreeLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Status = "expired"
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(listing, nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit, nil)
+
+	_, err := svc.Renew(context.Background(), testListingID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrListingLimitReached)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestListingRenew_ActiveOnlyInsideWarningWindow(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(listing, nil)
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	_, err := svc.Renew(context.Background(), testListingID, testSellerID)
+	assert.ErrorIs(t, err, ErrRenewTooEarly)
+
+	listing.ExpiresAt = time.Now().Add(time.Hour)
+	result, err := svc.Renew(context.Background(), testListingID, testSellerID)
+	require.NoError(t, err)
+	assert.True(t, result.ExpiresAt.After(time.Now().Add(24*time.Hour)))
+	listingRepo.AssertNotCalled(t, "CountActiveBySellerID", mock.Anything, mock.Anything)
+}
+
+func TestListingRenew_RejectsClosedAndForeignListings(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	cancelled := testListing("listing-cancelled", testSellerID)
+	cancelled.Status = "cancelled"
+	listingRepo.On("GetByID", mock.Anything, "listing-cancelled").Return(cancelled, nil)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	_, err := svc.Renew(context.Background(), "listing-cancelled", testSellerID)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	_, err = svc.Renew(context.Background(), testListingID, testBuyerID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestListingNotifyExpiringSoon_WarnsAndMarks(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetNotificationService(NewNotificationService(notifRepo, newTestRedis()))
+	svc.SetExpiryWarningHours(48)
+
+	expiring := testListing(testListingID, testSellerID, func(l *models.Listing) {
+		l.ExpiresAt = time.Now().Add(36 * time.Hour)
+	})
+	var before time.Time
+	listingRepo.On("ListExpiringUnwarned", mock.Anything, mock.AnythingOfType("time.Time"), "", expiringBatchSize).
+		Run(func(args mock.Arguments) { before = args.Get(1).(time.Time) }).
+		Return([]*models.Listing{expiring}, nil)
+	listingRepo.On("MarkExpiryWarned", mock.Anything, []string{testListingID}, mock.AnythingOfType("time.Time")).Return(nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 1 &&
+			ns[0].UserID == testSellerID &&
+			ns[0].Type == models.NotificationTypeListingExpiring &&
+			ns[0].GetReferenceID() == testListingID &&
+			strings.Contains(string(ns[0].Metadata), "/api/v1/listings/"+testListingID+"/refresh")
+	})).Return(nil)
+
+	warned, err := svc.NotifyExpiringSoon(context.Background(), 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, warned)
+	// Zero falls back to the configured window
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), before, time.Minute)
+	notifRepo.AssertExpectations(t)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingNotifyExpiringSoon_PagesThroughBatches(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetNotificationService(NewNotificationService(notifRepo, newTestRedis()))
+
+	full := make([]*models.Listing, expiringBatchSize)
+	for i := range full {
+		full[i] = testListing(fmt.Sprintf("listing-%03d", i), testSellerID)
+	}
+	lastID := full[len(full)-1].ID
+	listingRepo.On("ListExpiringUnwarned", mock.Anything, mock.AnythingOfType("time.Time"), "", expiringBatchSize).Return(full, nil)
+	listingRepo.On("ListExpiringUnwarned", mock.Anything, mock.AnythingOfType("time.Time"), lastID, expiringBatchSize).Return([]*models.Listing{}, nil)
+	listingRepo.On("MarkExpiryWarned", mock.Anything, mock.AnythingOfType("[]string"), mock.AnythingOfType("time.Time")).Return(nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+
+	warned, err := svc.NotifyExpiringSoon(context.Background(), 12)
+
+	require.NoError(t, err)
+	assert.Equal(t, expiringBatchSize, warned)
+	listingRepo.AssertNumberOfCalls(t, "ListExpiringUnwarned", 2)
+	listingRepo.AssertNumberOfCalls(t, "MarkExpiryWarned", 1)
+}
+
+// ---------------------------------------------------------------------------
+// Delete
+// ---------------------------------------------------------------------------
+
+func TestListingDelete_Success(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+	listingRepo.On("Update", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.Status == "cancelled"
+	})).Return(nil)
+
+	err := svc.Delete(context.Background(), testListingID, testSellerID)
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingDelete_NotOwner(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	err := svc.Delete(context.Background(), testListingID, "other-user-id")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingDelete_Completed_InvalidState(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	existing := testListing(testListingID, testSellerID, withListingStatus("completed"))
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+
+	err := svc.Delete(context.Background(), testListingID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Stats Transformation
+// ---------------------------------------------------------------------------
+
+func TestTransformCardStats_OnlyVariable(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"ac%","value":163,"displayText":"+163% Enhanced Defense","isVariable":true},
+		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
+		{"code":"dr","value":10,"displayText":"Damage Reduced by 10%","isVariable":true}
+	]`)
+
+	result := svc.transformCardStats(rawStats)
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, "ac%", result[0].Code)
+	assert.True(t, result[0].IsVariable)
+	assert.Equal(t, "dr", result[1].Code)
+	assert.True(t, result[1].IsVariable)
+}
+
+func TestTransformCardStats_EmptyStats(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	// nil raw message
+	result := svc.transformCardStats(nil)
+	assert.Nil(t, result)
+
+	// empty raw message
+	result2 := svc.transformCardStats(json.RawMessage{})
+	assert.Nil(t, result2)
+}
+
+func TestTransformCardStats_NoVariableStats(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
+		{"code":"dex","value":5,"displayText":"+5 to Dexterity","isVariable":false}
+	]`)
+
+	result := svc.transformCardStats(rawStats)
+
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+}
+
+func TestTransformAllStats_AllIncluded(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"ac%","value":163,"displayText":"+163% Enhanced Defense","isVariable":true},
+		{"code":"str","value":2,"displayText":"+2 to Strength","isVariable":false},
+		{"code":"dr","value":10,"displayText":"Damage Reduced by 10%","isVariable":true}
+	]`)
+
+	result := svc.transformAllStats(rawStats)
+
+	assert.Len(t, result, 3)
+
+	// First stat: variable
+	assert.Equal(t, "ac%", result[0].Code)
+	assert.Equal(t, intPtr(163), result[0].Value)
+	assert.Equal(t, "+163% Enhanced Defense", result[0].DisplayText)
+	assert.True(t, result[0].IsVariable)
+
+	// Second stat: not variable
+	assert.Equal(t, "str", result[1].Code)
+	assert.Equal(t, intPtr(2), result[1].Value)
+	assert.False(t, result[1].IsVariable)
+
+	// Third stat: variable
+	assert.Equal(t, "dr", result[2].Code)
+	assert.True(t, result[2].IsVariable)
+}
+
+func TestTransformAllStats_DisplayTextFallback(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"ac%","value":100,"displayText":""},
+		{"code":"mana","displayText":""}
+	]`)
+
+	result := svc.transformAllStats(rawStats)
+
+	assert.Len(t, result, 2)
+	// With value: fallback to "code: value"
+	assert.Equal(t, "ac%: 100", result[0].DisplayText)
+	// Without numeric value: fallback to just code
+	assert.Equal(t, "mana", result[1].DisplayText)
+}
+
+func TestTransformAllStats_StringValue(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"ed%","value":"+163% Enhanced Defense","displayText":"+163% Enhanced Defense","isVariable":true}
+	]`)
+
+	result := svc.transformAllStats(rawStats)
+
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ed%", result[0].Code)
+	// extractNumericValue should extract 163 from the string
+	assert.NotNil(t, result[0].Value)
+	assert.Equal(t, 163, *result[0].Value)
+}
+
+func TestTransformAllStats_NilValue(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[
+		{"code":"skill","displayText":"All Skills","isVariable":false}
+	]`)
+
+	result := svc.transformAllStats(rawStats)
+
+	assert.Len(t, result, 1)
+	assert.Equal(t, "skill", result[0].Code)
+	assert.Nil(t, result[0].Value)
+}
+
+func TestExtractNumericValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected *int
+	}{
+		{
 			name:     "float64 value",
 			input:    float64(163),
 			expected: intPtr(163),
@@ -653,6 +1512,26 @@ func TestToCardResponse_WithSeller(t *testing.T) {
 	assert.True(t, resp.Seller.IsPremium)
 }
 
+func TestToCardResponse_SellerActivity(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	online := testProfile(testSellerID)
+	online.LastActiveAt = time.Now().Add(-5 * time.Minute)
+	resp := svc.ToCardResponse(testListing(testListingID, testSellerID, withSeller(online)))
+
+	assert.True(t, resp.SellerOnline)
+	assert.NotNil(t, resp.SellerLastActive)
+
+	away := testProfile(testSellerID)
+	away.LastActiveAt = time.Now().Add(-3 * time.Hour)
+	resp = svc.ToCardResponse(testListing(testListingID, testSellerID, withSeller(away)))
+
+	assert.False(t, resp.SellerOnline)
+	assert.NotNil(t, resp.SellerLastActive)
+}
+
 func TestToCardResponse_NoSeller(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
@@ -666,6 +1545,29 @@ func TestToCardResponse_NoSeller(t *testing.T) {
 	assert.Nil(t, resp.Seller)
 }
 
+func TestToCardResponse_HideStatsOnCard(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	rawStats := json.RawMessage(`[{"code":"ac%","value":163,"displayText":"+163% Enhanced Defense","isVariable":true}]`)
+
+	shown := svc.ToCardResponse(testListing(testListingID, testSellerID, withStats(rawStats)))
+	assert.Len(t, shown.Stats, 1)
+	assert.False(t, shown.StatsHidden)
+
+	listing := testListing(testListingID, testSellerID, withStats(rawStats))
+	listing.HideStatsOnCard = true
+
+	card := svc.ToCardResponse(listing)
+	assert.Nil(t, card.Stats)
+	assert.True(t, card.StatsHidden)
+
+	detail := svc.ToResponse(listing)
+	assert.Len(t, detail.Stats, 1)
+	assert.True(t, detail.HideStatsOnCard)
+}
+
 func TestToResponse_AllFields(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	listingRepo := new(mocks.MockListingRepository)
@@ -738,7 +1640,7 @@ func TestList_ParsesAffixFilters(t *testing.T) {
 		AffixFilters: `[{"code":"ed%","minValue":150}]`,
 	}
 
-	listings, count, err := svc.List(context.Background(), req)
+	listings, count, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	assert.Empty(t, listings)
@@ -761,7 +1663,7 @@ func TestList_ParsesAskingForFilter(t *testing.T) {
 		AskingForFilters: `{"name":"Ber","type":"rune"}`,
 	}
 
-	_, _, err := svc.List(context.Background(), req)
+	_, _, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	listingRepo.AssertExpectations(t)
@@ -786,7 +1688,7 @@ func TestList_EmptyFilters(t *testing.T) {
 		Pagination: dto.Pagination{Page: 1, PerPage: 20},
 	}
 
-	listings, count, err := svc.List(context.Background(), req)
+	listings, count, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	assert.Empty(t, listings)
@@ -817,7 +1719,7 @@ func TestList_ParsesMultipleGameModeFilters(t *testing.T) {
 		IsNonRotw: &isNonRotw,
 	}
 
-	_, _, err := svc.List(context.Background(), req)
+	_, _, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	listingRepo.AssertExpectations(t)
@@ -838,7 +1740,7 @@ func TestList_ParsesPlatformFilter(t *testing.T) {
 		Platforms: "pc,xbox",
 	}
 
-	_, _, err := svc.List(context.Background(), req)
+	_, _, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	listingRepo.AssertExpectations(t)
@@ -860,7 +1762,7 @@ func TestList_ParsesCategories(t *testing.T) {
 		Categories: "helms,body armor,weapons",
 	}
 
-	_, _, err := svc.List(context.Background(), req)
+	_, _, _, err := svc.List(context.Background(), req, "")
 
 	assert.NoError(t, err)
 	listingRepo.AssertExpectations(t)
@@ -879,12 +1781,163 @@ func TestList_ParsesRarity(t *testing.T) {
 		Rarity: "unique",
 	}
 
-	_, _, err := svc.List(context.Background(), req)
+	_, _, _, err := svc.List(context.Background(), req, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestList_FuzzyFallbackWhenQueryFindsNothing(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.Query == "enigme" && !f.Fuzzy
+	})).Return([]*models.Listing{}, 0, nil).Once()
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.Query == "enigme" && f.Fuzzy
+	})).Return([]*models.Listing{testListing(testListingID, testSellerID)}, 1, nil).Once()
+
+	listings, count, fuzzy, err := svc.List(context.Background(), &dto.ListingFilterRequest{Q: "enigme"}, "")
+
+	require.NoError(t, err)
+	assert.True(t, fuzzy)
+	assert.Equal(t, 1, count)
+	assert.Len(t, listings, 1)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestList_NoFuzzyFallbackWhenExactMatches(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.AnythingOfType("repository.ListingFilter")).
+		Return([]*models.Listing{testListing(testListingID, testSellerID)}, 1, nil).Once()
+
+	_, _, fuzzy, err := svc.List(context.Background(), &dto.ListingFilterRequest{Q: "enigma"}, "")
+
+	require.NoError(t, err)
+	assert.False(t, fuzzy)
+	listingRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestList_NoFuzzyFallbackWithoutQuery(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.AnythingOfType("repository.ListingFilter")).
+		Return([]*models.Listing{}, 0, nil).Once()
+
+	_, _, fuzzy, err := svc.List(context.Background(), &dto.ListingFilterRequest{Rarity: "unique"}, "")
+
+	require.NoError(t, err)
+	assert.False(t, fuzzy)
+	listingRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestToResponse_OrdersStatsForDisplay(t *testing.T) {
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), new(mocks.MockListingRepository), newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Stats = json.RawMessage(`[
+		{"code":"some_new_stat","value":1},
+		{"code":"allskills","value":2},
+		{"code":"res-fire","value":30,"isVariable":true},
+		{"code":"another_new_stat","value":5,"isVariable":true},
+		{"code":"ac%","value":141,"isVariable":true}
+	]`)
+
+	resp := svc.ToResponse(listing)
+
+	codes := make([]string, 0, len(resp.Stats))
+	for _, stat := range resp.Stats {
+		codes = append(codes, stat.Code)
+	}
+	assert.Equal(t, []string{"ac%", "res-fire", "allskills", "some_new_stat", "another_new_stat"}, codes)
+	assert.True(t, resp.Stats[0].IsVariable)
+	assert.False(t, resp.Stats[2].IsVariable)
+
+	card := svc.ToCardResponse(listing)
+	cardCodes := make([]string, 0, len(card.Stats))
+	for _, stat := range card.Stats {
+		cardCodes = append(cardCodes, stat.Code)
+	}
+	assert.Equal(t, []string{"ac%", "res-fire", "another_new_stat"}, cardCodes)
+}
+
+func TestToResponse_KeepsStoredStatOrderWithoutRegistry(t *testing.T) {
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), new(mocks.MockListingRepository), newTestRedis())
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Stats = json.RawMessage(`[{"code":"allskills","value":2},{"code":"ac%","value":141}]`)
+
+	resp := svc.ToResponse(listing)
+
+	require.Len(t, resp.Stats, 2)
+	assert.Equal(t, "allskills", resp.Stats[0].Code)
+	assert.Equal(t, "ac%", resp.Stats[1].Code)
+}
+
+func TestList_CategoryDefaultSortWhenClientOmitsSort(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.SortBy == "name" && f.SortOrder == "asc"
+	})).Return([]*models.Listing{}, 0, nil).Once()
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{Categories: "misc"}, "")
+
+	require.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestList_ExplicitSortBeatsCategoryDefault(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.SortBy == "asking_price" && f.SortOrder == "desc"
+	})).Return([]*models.Listing{}, 0, nil).Once()
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{
+		Categories: "misc",
+		SortBy:     "asking_price",
+		SortOrder:  "desc",
+	}, "")
+
+	require.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestList_NoCategoryDefaultSortAcrossCategories(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.SortBy == ""
+	})).Return([]*models.Listing{}, 0, nil).Once()
 
-	assert.NoError(t, err)
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{Categories: "misc,rune"}, "")
+
+	require.NoError(t, err)
 	listingRepo.AssertExpectations(t)
 }
 
+func TestCategoryDefaultSorts_AreWhitelisted(t *testing.T) {
+	for _, category := range d2.Categories {
+		if category.DefaultSort == "" {
+			continue
+		}
+		assert.Contains(t, repository.ListingSortFields, category.DefaultSort, "category %s", category.Code)
+		assert.Contains(t, []string{"asc", "desc"}, category.DefaultSortOrder, "category %s", category.Code)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // parsePlatforms
 // ---------------------------------------------------------------------------
@@ -930,6 +1983,80 @@ func TestParsePlatforms(t *testing.T) {
 	}
 }
 
+func TestListingList_ActiveWithinHours(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.ActiveWithin != nil && *f.ActiveWithin == 24*time.Hour
+	})).Return([]*models.Listing{}, 0, nil)
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{ActiveWithinHours: 24}, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestPruneAffixFilters_DropsInapplicable(t *testing.T) {
+	filter := repository.ListingFilter{
+		Categories: []string{"ring"},
+		AffixFilters: []repository.AffixFilter{
+			{Code: "fcr"},
+			{Code: "ias"},
+			{Code: "sor-fire"},
+		},
+	}
+
+	ignored := PruneAffixFilters(&filter)
+
+	assert.Equal(t, []string{"ias", "sor-fire"}, ignored)
+	assert.Len(t, filter.AffixFilters, 1)
+	assert.Equal(t, "fcr", filter.AffixFilters[0].Code)
+}
+
+func TestPruneAffixFilters_AnyCategoryApplies(t *testing.T) {
+	filter := repository.ListingFilter{
+		Categories:   []string{"ring", "weapon"},
+		AffixFilters: []repository.AffixFilter{{Code: "ias"}},
+	}
+
+	ignored := PruneAffixFilters(&filter)
+
+	assert.Empty(t, ignored)
+	assert.Len(t, filter.AffixFilters, 1)
+}
+
+func TestPruneAffixFilters_NoCategory(t *testing.T) {
+	filter := repository.ListingFilter{
+		AffixFilters: []repository.AffixFilter{{Code: "ias"}},
+	}
+
+	ignored := PruneAffixFilters(&filter)
+
+	assert.Empty(t, ignored)
+	assert.Len(t, filter.AffixFilters, 1)
+}
+
+func TestListingList_IgnoresInapplicableAffixFilter(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return len(f.AffixFilters) == 0
+	})).Return([]*models.Listing{}, 0, nil)
+
+	req := &dto.ListingFilterRequest{
+		Categories:   "rune",
+		AffixFilters: `[{"code":"all_res","minValue":10}]`,
+	}
+	_, _, _, err := svc.List(context.Background(), req, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
 // ---------------------------------------------------------------------------
 // Refresh
 // ---------------------------------------------------------------------------
@@ -1119,8 +2246,444 @@ func TestIncrementViews_Success(t *testing.T) {
 
 	listingRepo.On("IncrementViews", mock.Anything, testListingID).Return(nil)
 
-	err := svc.IncrementViews(context.Background(), testListingID)
+	err := svc.IncrementViews(context.Background(), testListingID, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestIncrementViews_RecordsRecentlyViewedNewestFirstWithoutDuplicates(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, redis)
+	ctx := context.Background()
+
+	for _, id := range []string{"listing-a", "listing-b"} {
+		listingRepo.On("IncrementViews", mock.Anything, id).Return(nil)
+		listingRepo.On("GetByIDWithSeller", mock.Anything, id).Return(testListing(id, testSellerID), nil)
+	}
+
+	require.NoError(t, svc.IncrementViews(ctx, "listing-a", testBuyerID))
+	require.NoError(t, svc.IncrementViews(ctx, "listing-b", testBuyerID))
+	require.NoError(t, svc.IncrementViews(ctx, "listing-a", testBuyerID))
+
+	viewed, err := svc.GetRecentlyViewed(ctx, testBuyerID)
+
+	require.NoError(t, err)
+	require.Len(t, viewed, 2)
+	assert.Equal(t, "listing-a", viewed[0].ID)
+	assert.Equal(t, "listing-b", viewed[1].ID)
+
+	other, err := svc.GetRecentlyViewed(ctx, testUserID)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestIncrementViews_SkipsOwnListingAndAnonymousViews(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, redis)
+	ctx := context.Background()
+
+	listingRepo.On("IncrementViews", mock.Anything, testListingID).Return(nil)
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	require.NoError(t, svc.IncrementViews(ctx, testListingID, testSellerID))
+	require.NoError(t, svc.IncrementViews(ctx, testListingID, ""))
+
+	viewed, err := svc.GetRecentlyViewed(ctx, testSellerID)
+	require.NoError(t, err)
+	assert.Empty(t, viewed)
+	listingRepo.AssertNumberOfCalls(t, "IncrementViews", 2)
+}
+
+func TestIncrementViews_RecentlyViewedIsCapped(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, redis)
+	ctx := context.Background()
+
+	listingRepo.On("IncrementViews", mock.Anything, mock.Anything).Return(nil)
+	for i := 0; i < maxRecentListings+5; i++ {
+		id := fmt.Sprintf("listing-%d", i)
+		listingRepo.On("GetByIDWithSeller", mock.Anything, id).Return(testListing(id, testSellerID), nil)
+		require.NoError(t, svc.IncrementViews(ctx, id, testBuyerID))
+	}
+
+	viewed, err := svc.GetRecentlyViewed(ctx, testBuyerID)
+	require.NoError(t, err)
+	assert.Len(t, viewed, maxRecentListings)
+	assert.Equal(t, fmt.Sprintf("listing-%d", maxRecentListings+4), viewed[0].ID)
+	assert.Positive(t, mr.TTL(cache.RecentlyViewedKey(testBuyerID)))
+}
+
+// ---------------------------------------------------------------------------
+// CountPotentialWishlistMatches
+// ---------------------------------------------------------------------------
+
+func TestCountPotentialWishlistMatches_UsesDraftWithoutSaving(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, profileService := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	wishlistRepo := new(mocks.MockWishlistRepository)
+	svc.SetWishlistService(NewWishlistService(wishlistRepo, profileService, nil))
+
+	wishlistRepo.On("FindMatchingItems", mock.Anything, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.SellerID == testSellerID && l.Name == "Shako" && l.Game == "diablo2" &&
+			l.CatalogItemID != nil && *l.CatalogItemID == "harlequin-crest"
+	})).Return([]*models.WishlistItem{
+		{ID: "wl-1", UserID: "user-abc"},
+		{ID: "wl-2", UserID: "user-xyz"},
+	}, nil)
+
+	draft := &dto.CreateListingRequest{
+		Name:          "Shako",
+		ItemType:      "unique",
+		Rarity:        "unique",
+		CatalogItemID: "harlequin-crest",
+		Game:          "diablo2",
+		Platforms:     []string{"pc"},
+		Region:        "americas",
+	}
+
+	count, err := svc.CountPotentialWishlistMatches(context.Background(), testSellerID, draft)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCountPotentialWishlistMatches_NoWishlistService(t *testing.T) {
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), new(mocks.MockListingRepository), newTestRedis())
+
+	count, err := svc.CountPotentialWishlistMatches(context.Background(), testSellerID, &dto.CreateListingRequest{Name: "Shako"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+// ---------------------------------------------------------------------------
+// RemainingListings
+// ---------------------------------------------------------------------------
+
+func TestListingRemainingListings_FreeUser(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit-3, nil)
+
+	remaining, err := svc.RemainingListings(context.Background(), testSellerID)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, remaining) {
+		assert.Equal(t, 3, *remaining)
+	}
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingRemainingListings_FreeUserOverLimit_ClampsToZero(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	// e.g. a lapsed premium seller who kept their listings
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID), nil)
+	listingRepo.On("CountActiveBySellerID", mock.Anything, testSellerID).Return(FreeListingLimit+4, nil)
+
+	remaining, err := svc.RemainingListings(context.Background(), testSellerID)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, remaining) {
+		assert.Equal(t, 0, *remaining)
+	}
+}
+
+func TestListingRemainingListings_PremiumUser_Unlimited(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+
+	remaining, err := svc.RemainingListings(context.Background(), testSellerID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, remaining)
+	listingRepo.AssertNotCalled(t, "CountActiveBySellerID", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Shadow throttle
+// ---------------------------------------------------------------------------
+
+func TestListingCreate_ShadowThrottledSeller_DelaysRecent(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc, profileService := setupListingService(profileRepo, listingRepo, redisClient)
+	profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: 24 * time.Hour})
+
+	profile := testProfile(testSellerID, withPremium)
+	profile.AbuseScore = 12
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(profile, nil)
+	listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "americas",
+	}
+
+	listing, err := svc.Create(context.Background(), testSellerID, req)
+
+	require.NoError(t, err)
+	assert.False(t, mr.Exists(cache.HomeRecentKey()))
+
+	// Held back, not dropped: the listing is queued for when the delay ends
+	releaseAt, err := mr.ZScore(cache.RecentDelayedKey(), listing.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(listing.CreatedAt.Add(24*time.Hour).Unix()), releaseAt, 1)
+}
+
+func TestReleaseDelayedRecent_PushesDueActiveListings(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc, profileService := setupListingService(profileRepo, listingRepo, redisClient)
+	profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: time.Hour})
+
+	seller := testProfile(testSellerID)
+	seller.AbuseScore = 12
+	due := testListing("listing-due", testSellerID)
+	due.Seller = seller
+	cancelled := testListing("listing-cancelled", testSellerID, withListingStatus("cancelled"))
+
+	now := float64(time.Now().Unix())
+	_, _ = mr.ZAdd(cache.RecentDelayedKey(), now-60, "listing-due")
+	_, _ = mr.ZAdd(cache.RecentDelayedKey(), now-60, "listing-cancelled")
+	_, _ = mr.ZAdd(cache.RecentDelayedKey(), now+3600, "listing-later")
+	listingRepo.On("GetByIDWithSeller", mock.Anything, "listing-due").Return(due, nil).Once()
+	listingRepo.On("GetByIDWithSeller", mock.Anything, "listing-cancelled").Return(cancelled, nil).Once()
+
+	n, err := svc.ReleaseDelayedRecent(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	items, err := mr.List(cache.HomeRecentKey())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Contains(t, items[0], "listing-due")
+	pending, err := mr.ZMembers(cache.RecentDelayedKey())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"listing-later"}, pending)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingDelete_ChurnPastLimitRaisesAbuseScore(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		churned int
+		raised  bool
+	}{
+		{name: "within limit", churned: 2, raised: false},
+		{name: "past limit", churned: 3, raised: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			profileRepo := new(mocks.MockProfileRepository)
+			listingRepo := new(mocks.MockListingRepository)
+			svc, profileService := setupListingService(profileRepo, listingRepo, newTestRedis())
+			profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: time.Hour, ChurnLimit: 2})
+
+			existing := testListing(testListingID, testSellerID)
+			existing.CreatedAt = time.Now().Add(-10 * time.Minute)
+			listingRepo.On("GetByID", mock.Anything, testListingID).Return(existing, nil)
+			listingRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			listingRepo.On("CountChurnedBySeller", mock.Anything, testSellerID, mock.Anything, churnListingMaxAge).Return(tc.churned, nil)
+			profileRepo.On("AdjustAbuseScore", mock.Anything, testSellerID, 1).Return(1, nil)
+
+			require.NoError(t, svc.Delete(context.Background(), testListingID, testSellerID))
+
+			if tc.raised {
+				profileRepo.AssertCalled(t, "AdjustAbuseScore", mock.Anything, testSellerID, 1)
+			} else {
+				profileRepo.AssertNotCalled(t, "AdjustAbuseScore", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListingDelete_OldListingIsNotChurn(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, profileService := setupListingService(profileRepo, listingRepo, newTestRedis())
+	profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: time.Hour, ChurnLimit: 2})
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, svc.Delete(context.Background(), testListingID, testSellerID))
+
+	listingRepo.AssertNotCalled(t, "CountChurnedBySeller", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListingCreate_BelowThrottleThreshold_PushesRecent(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc, profileService := setupListingService(profileRepo, listingRepo, redisClient)
+	profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: 24 * time.Hour})
+
+	profile := testProfile(testSellerID, withPremium)
+	profile.AbuseScore = 9
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(profile, nil)
+	listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "americas",
+	}
+
+	_, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.NoError(t, err)
+	items, err := mr.List(cache.HomeRecentKey())
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+}
+
+func TestListingList_PassesShadowThrottleScore(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, profileService := setupListingService(profileRepo, listingRepo, newTestRedis())
+	profileService.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: time.Hour})
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.ShadowThrottleScore == 10
+	})).Return([]*models.Listing{}, 0, nil)
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{}, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingList_ViewerExcludesOwnListings(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return len(f.ExcludeSellerIDs) == 1 && f.ExcludeSellerIDs[0] == testBuyerID
+	})).Return([]*models.Listing{}, 0, nil)
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{}, testBuyerID)
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingList_AnonymousExcludesNothing(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return len(f.ExcludeSellerIDs) == 0
+	})).Return([]*models.Listing{}, 0, nil)
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{}, "")
 
 	assert.NoError(t, err)
 	listingRepo.AssertExpectations(t)
 }
+
+func TestExcludeViewer_KeepsOwnSellerFilter(t *testing.T) {
+	filter := repository.ListingFilter{SellerID: testSellerID}
+
+	ExcludeViewer(&filter, testSellerID)
+
+	assert.Empty(t, filter.ExcludeSellerIDs)
+}
+
+func TestListingList_ViewerExclusionIsPartOfCacheKey(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, redis)
+
+	own := testListing(testListingID, testBuyerID)
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return len(f.ExcludeSellerIDs) == 0
+	})).Return([]*models.Listing{own}, 1, nil).Once()
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return len(f.ExcludeSellerIDs) == 1
+	})).Return([]*models.Listing{}, 0, nil).Once()
+
+	_, anonCount, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{}, "")
+	require.NoError(t, err)
+	_, viewerCount, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{}, testBuyerID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, anonCount)
+	assert.Equal(t, 0, viewerCount)
+	listingRepo.AssertExpectations(t)
+}
+
+// ---------------------------------------------------------------------------
+// GetSimilar
+// ---------------------------------------------------------------------------
+
+func TestGetSimilar_SameBaseItemFirstThenCategory(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+	ctx := context.Background()
+
+	baseName := "Shako"
+	base := testListing(testListingID, testSellerID, func(l *models.Listing) { l.BaseItemName = &baseName })
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(base, nil).Once()
+
+	sameItem := testListing("listing-same", "seller-b")
+	sameKind := testListing("listing-kind", "seller-c")
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.BaseItemName == baseName && f.Limit == 2
+	})).Return([]*models.Listing{sameItem}, 1, nil).Once()
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.BaseItemName == "" && f.Limit == 1 &&
+			assert.ObjectsAreEqual([]string{"helm"}, f.Categories) && f.Rarity == "unique" &&
+			assert.ObjectsAreEqual([]string{testListingID, "listing-same"}, f.ExcludeIDs) &&
+			assert.ObjectsAreEqual([]string{testSellerID}, f.ExcludeSellerIDs)
+	})).Return([]*models.Listing{sameKind}, 1, nil).Once()
+
+	cards, err := svc.GetSimilar(ctx, testListingID, 2)
+	require.NoError(t, err)
+	require.Len(t, cards, 2)
+	assert.Equal(t, "listing-same", cards[0].ID)
+	assert.Equal(t, "listing-kind", cards[1].ID)
+
+	// The second call is served from the cache
+	cards, err = svc.GetSimilar(ctx, testListingID, 2)
+	require.NoError(t, err)
+	assert.Len(t, cards, 2)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestGetSimilar_ListingNotFound(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+
+	listingRepo.On("GetByIDWithSeller", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	_, err := svc.GetSimilar(context.Background(), "missing", 0)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	listingRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
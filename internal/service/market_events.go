@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// MarketEventRecorder logs a normalized value event for every completed trade and
+// service run, written in the same database transaction as the completion's
+// transaction row. A nil recorder only writes the transaction.
+type MarketEventRecorder struct {
+	db        *database.BunDB
+	repo      repository.MarketEventRepository
+	estimator games.ValueEstimator
+}
+
+// NewMarketEventRecorder creates a recorder that values offered items with estimator
+func NewMarketEventRecorder(db *database.BunDB, repo repository.MarketEventRepository, estimator games.ValueEstimator) *MarketEventRecorder {
+	return &MarketEventRecorder{db: db, repo: repo, estimator: estimator}
+}
+
+// Record creates the transaction together with its market event. Called inside a
+// RunInTx (e.g. the one completing a trade), both inserts join that transaction.
+func (r *MarketEventRecorder) Record(ctx context.Context, transactionRepo repository.TransactionRepository, transaction *models.Transaction, source, game, itemType string) error {
+	if r == nil {
+		return transactionRepo.Create(ctx, transaction)
+	}
+
+	event := &models.MarketEvent{
+		TransactionID: transaction.ID,
+		Source:        source,
+		Game:          game,
+		ItemName:      transaction.ItemName,
+		ItemType:      itemType,
+		OfferedValue:  estimateOfferedItemsValue(r.estimator, transaction.OfferedItems),
+		OccurredAt:    transaction.CreatedAt,
+	}
+	return r.db.RunInTx(ctx, func(ctx context.Context) error {
+		if err := transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		return r.repo.Create(ctx, event)
+	})
+}
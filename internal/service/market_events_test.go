@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedValueEstimator values every item at 10 per unit
+var fixedValueEstimator = games.ValueEstimatorFunc(func(_, _ string, quantity int) (float64, bool) {
+	return 10 * float64(quantity), true
+})
+
+func TestMarketEventRecorder_NilOnlyCreatesTransaction(t *testing.T) {
+	transactionRepo := new(mocks.MockTransactionRepository)
+	transaction := &models.Transaction{ID: "txn-1", ItemName: "Shako"}
+	transactionRepo.On("Create", mock.Anything, transaction).Return(nil)
+
+	var recorder *MarketEventRecorder
+	err := recorder.Record(context.Background(), transactionRepo, transaction, models.MarketEventSourceTrade, "diablo2", "unique")
+
+	require.NoError(t, err)
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestMarketEventRecorder_WritesEventWithTransaction(t *testing.T) {
+	transactionRepo := new(mocks.MockTransactionRepository)
+	eventRepo := new(mocks.MockMarketEventRepository)
+	recorder := NewMarketEventRecorder(nil, eventRepo, fixedValueEstimator)
+
+	completedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	transaction := &models.Transaction{
+		ID:           "txn-1",
+		ItemName:     "Shako",
+		OfferedItems: makeOfferedItemsJSON(),
+		CreatedAt:    completedAt,
+	}
+	transactionRepo.On("Create", mock.Anything, transaction).Return(nil)
+	eventRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.MarketEvent) bool {
+		return e.TransactionID == "txn-1" &&
+			e.Source == models.MarketEventSourceTrade &&
+			e.Game == "diablo2" &&
+			e.ItemName == "Shako" &&
+			e.ItemType == "unique" &&
+			e.OfferedValue != nil && *e.OfferedValue == 10 &&
+			e.OccurredAt.Equal(completedAt)
+	})).Return(nil)
+
+	err := recorder.Record(context.Background(), transactionRepo, transaction, models.MarketEventSourceTrade, "diablo2", "unique")
+
+	require.NoError(t, err)
+	eventRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestMarketEventRecorder_TransactionFailureSkipsEvent(t *testing.T) {
+	transactionRepo := new(mocks.MockTransactionRepository)
+	eventRepo := new(mocks.MockMarketEventRepository)
+	recorder := NewMarketEventRecorder(nil, eventRepo, fixedValueEstimator)
+
+	transaction := &models.Transaction{ID: "txn-1", ItemName: "Shako"}
+	transactionRepo.On("Create", mock.Anything, transaction).Return(errors.New("db error"))
+
+	err := recorder.Record(context.Background(), transactionRepo, transaction, models.MarketEventSourceTrade, "diablo2", "unique")
+
+	assert.Error(t, err)
+	eventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestMarketEventRecorder_UnvaluedItemsLeaveValueNil(t *testing.T) {
+	transactionRepo := new(mocks.MockTransactionRepository)
+	eventRepo := new(mocks.MockMarketEventRepository)
+	recorder := NewMarketEventRecorder(nil, eventRepo, games.ValueEstimatorFunc(func(string, string, int) (float64, bool) {
+		return 0, false
+	}))
+
+	transaction := &models.Transaction{ID: "txn-1", ItemName: "Normal Rush", OfferedItems: makeOfferedItemsJSON()}
+	transactionRepo.On("Create", mock.Anything, transaction).Return(nil)
+	eventRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.MarketEvent) bool {
+		return e.OfferedValue == nil
+	})).Return(nil)
+
+	err := recorder.Record(context.Background(), transactionRepo, transaction, models.MarketEventSourceServiceRun, "diablo2", "rush")
+
+	require.NoError(t, err)
+	eventRepo.AssertExpectations(t)
+}
+
+func TestTradeComplete_RecordsMarketEvent(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	eventRepo := new(mocks.MockMarketEventRepository)
+	h.svc.SetMarketEventRecorder(NewMarketEventRecorder(nil, eventRepo, fixedValueEstimator))
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	offer.OfferedItems = makeOfferedItemsJSON()
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	eventRepo.On("Create", ctx, mock.MatchedBy(func(e *models.MarketEvent) bool {
+		return e.Source == models.MarketEventSourceTrade &&
+			e.Game == listing.Game &&
+			e.ItemName == listing.Name &&
+			e.ItemType == listing.ItemType &&
+			e.OfferedValue != nil
+	})).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	h.confirmCompletion(t, testSellerID)
+	_, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
+
+	require.NoError(t, err)
+	require.NotNil(t, resultTx)
+	eventRepo.AssertExpectations(t)
+	h.transactionRepo.AssertCalled(t, "Create", ctx, resultTx)
+}
+
+func TestTradeComplete_MarketEventFailureFailsCompletion(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	eventRepo := new(mocks.MockMarketEventRepository)
+	h.svc.SetMarketEventRecorder(NewMarketEventRecorder(nil, eventRepo, fixedValueEstimator))
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	eventRepo.On("Create", ctx, mock.Anything).Return(errors.New("db error"))
+
+	h.confirmCompletion(t, testSellerID)
+	_, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
+
+	assert.Error(t, err)
+	assert.Nil(t, resultTx)
+	h.notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	// The rolled-back completion leaves the offer and listing alone
+	h.offerRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	h.listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestServiceRunComplete_RecordsMarketEvent(t *testing.T) {
+	runRepo := new(mocks.MockServiceRunRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	eventRepo := new(mocks.MockMarketEventRepository)
+	profileService := NewProfileService(nil, nil, nil)
+	svc := NewServiceRunService(
+		runRepo,
+		transactionRepo,
+		new(mocks.MockRatingRepository),
+		new(mocks.MockChatRepository),
+		NewNotificationService(notifRepo, nil),
+		profileService,
+		NewServiceService(nil, profileService, nil),
+		nil, // redis
+	)
+	svc.SetMarketEventRecorder(NewMarketEventRecorder(nil, eventRepo, fixedValueEstimator))
+	ctx := context.Background()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	run.Service = testServiceModel(testServiceID, testProviderID)
+	run.Offer = testOffer(testOfferID, testClientID, nil)
+	run.Offer.OfferedItems = makeOfferedItemsJSON()
+
+	runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(run, nil)
+	runRepo.On("Update", ctx, run).Return(nil)
+	transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	eventRepo.On("Create", ctx, mock.MatchedBy(func(e *models.MarketEvent) bool {
+		return e.Source == models.MarketEventSourceServiceRun &&
+			e.Game == "diablo2" &&
+			e.ItemName == "Normal Rush" &&
+			e.ItemType == "rush" &&
+			e.OfferedValue != nil && *e.OfferedValue == 10
+	})).Return(nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, transaction, err := svc.Complete(ctx, testServiceRunID, testProviderID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Normal Rush", transaction.ItemName)
+	eventRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,10 +16,14 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 )
 
+// TradeCompletionTokenTTL is how long a completion token from RequestComplete stays valid
+const TradeCompletionTokenTTL = 2 * time.Minute
+
 // TradeServiceNew handles trade business logic
 type TradeServiceNew struct {
 	db                  *database.BunDB
@@ -32,6 +40,9 @@ type TradeServiceNew struct {
 	redis               *cache.RedisClient
 	invalidator         *cache.Invalidator
 	supabaseURL         string
+	imageChecker        *ItemImageChecker
+	marketEvents        *MarketEventRecorder
+	chatArchiver        *ChatArchiver
 }
 
 // NewTradeServiceNew creates a new trade service
@@ -71,6 +82,21 @@ func (s *TradeServiceNew) SetStatsService(ss *StatsService) {
 	s.statsService = ss
 }
 
+// SetImageChecker sets the checker that replaces missing item images with a placeholder
+func (s *TradeServiceNew) SetImageChecker(checker *ItemImageChecker) {
+	s.imageChecker = checker
+}
+
+// SetMarketEventRecorder sets the recorder that logs a value event for each completed trade
+func (s *TradeServiceNew) SetMarketEventRecorder(recorder *MarketEventRecorder) {
+	s.marketEvents = recorder
+}
+
+// SetChatArchiver sets the archiver that moves the trade's chat out of the inbox once it resolves
+func (s *TradeServiceNew) SetChatArchiver(archiver *ChatArchiver) {
+	s.chatArchiver = archiver
+}
+
 // offeredItemRaw represents the raw offered item from JSON
 type offeredItemRaw struct {
 	ID       string `json:"id"`
@@ -104,7 +130,7 @@ func (s *TradeServiceNew) transformOfferedItems(rawItems json.RawMessage) []dto.
 		if item.ImageURL != "" {
 			resp.ImageURL = item.ImageURL
 		} else {
-			resp.ImageURL = s.generateItemImageURL(item.Name, item.Type)
+			resp.ImageURL = s.imageChecker.Resolve(s.generateItemImageURL(item.Name, item.Type))
 		}
 
 		result = append(result, resp)
@@ -115,7 +141,12 @@ func (s *TradeServiceNew) transformOfferedItems(rawItems json.RawMessage) []dto.
 
 // generateItemImageURL generates an image URL based on item type and name
 func (s *TradeServiceNew) generateItemImageURL(name, itemType string) string {
-	if s.supabaseURL == "" {
+	return itemImageURL(s.supabaseURL, name, itemType)
+}
+
+// itemImageURL builds the storage URL of an item's image from its name and type
+func itemImageURL(supabaseURL, name, itemType string) string {
+	if supabaseURL == "" {
 		return ""
 	}
 
@@ -150,7 +181,7 @@ func (s *TradeServiceNew) generateItemImageURL(name, itemType string) string {
 		path = fmt.Sprintf("items/%s.png", normalized)
 	}
 
-	return fmt.Sprintf("%s/storage/v1/object/public/d2-items/%s", s.supabaseURL, path)
+	return fmt.Sprintf("%s/storage/v1/object/public/d2-items/%s", supabaseURL, path)
 }
 
 // GetByID retrieves a trade by ID
@@ -169,7 +200,7 @@ func (s *TradeServiceNew) GetByID(ctx context.Context, id string, userID string)
 }
 
 // Complete marks a trade as completed and creates a transaction
-func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string) (*models.Trade, *models.Transaction, error) {
+func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string, token string) (*models.Trade, *models.Transaction, error) {
 	trade, err := s.repo.GetByIDWithRelations(ctx, id)
 	if err != nil {
 		return nil, nil, err
@@ -182,11 +213,7 @@ func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string
 
 	// If already completed, return existing trade and transaction (idempotent)
 	if trade.IsCompleted() {
-		transaction, err := s.transactionRepo.GetByTradeID(ctx, trade.ID)
-		if err != nil {
-			return nil, nil, err
-		}
-		return trade, transaction, nil
+		return s.completedTrade(ctx, trade)
 	}
 
 	// Must be active (not cancelled)
@@ -194,48 +221,65 @@ func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string
 		return nil, nil, ErrInvalidState
 	}
 
+	if err := s.consumeCompletionToken(ctx, trade.ID, userID, token); err != nil {
+		return nil, nil, err
+	}
+
+	// Re-check the state under the row lock so a concurrent complete or cancel
+	// by the other party resolves to whichever got the lock first. The transaction
+	// and its market event are written in the same database transaction, so a trade
+	// is never completed without them.
 	now := time.Now()
-	trade.Status = "completed"
-	trade.CompletedAt = &now
-	trade.UpdatedAt = now
+	var listing *models.Listing
+	var transaction *models.Transaction
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		_, err := s.repo.UpdateLocked(ctx, trade.ID, func(locked *models.Trade) error {
+			if locked.IsCompleted() {
+				return errTradeAlreadyCompleted
+			}
+			if !locked.IsActive() {
+				return ErrInvalidState
+			}
+			locked.Status = "completed"
+			locked.CompletedAt = &now
+			locked.UpdatedAt = now
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 
-	if err := s.repo.Update(ctx, trade); err != nil {
+		listing, err = s.listingRepo.GetByID(ctx, trade.ListingID)
+		if err != nil {
+			return err
+		}
+
+		transaction = newTradeTransaction(trade, listing, now)
+		return s.marketEvents.Record(ctx, s.transactionRepo, transaction, models.MarketEventSourceTrade, listing.Game, listing.ItemType)
+	})
+	if errors.Is(err, errTradeAlreadyCompleted) {
+		trade.Status = "completed"
+		return s.completedTrade(ctx, trade)
+	}
+	if err != nil {
 		return nil, nil, err
 	}
+	trade.Status = "completed"
+	trade.CompletedAt = &now
+	trade.UpdatedAt = now
 
 	// Sync offer status to completed
 	if trade.Offer != nil {
 		trade.Offer.Status = "completed"
 		trade.Offer.UpdatedAt = now
 		_ = s.offerRepo.Update(ctx, trade.Offer)
+		_ = s.invalidator.InvalidateOffer(ctx, trade.Offer.ID)
 	}
 
-	// Update listing status to completed
-	listing, err := s.listingRepo.GetByID(ctx, trade.ListingID)
-	if err != nil {
-		return nil, nil, err
-	}
-	listing.Status = "completed"
-	_ = s.listingRepo.Update(ctx, listing)
-
-	// Create transaction
-	tradeID := trade.ID
-	listingID := trade.ListingID
-	transaction := &models.Transaction{
-		ID:           uuid.New().String(),
-		TradeID:      &tradeID,
-		ListingID:    &listingID,
-		SellerID:     trade.SellerID,
-		BuyerID:      trade.BuyerID,
-		ItemName:     listing.Name,
-		ItemDetails:  listing.Stats,
-		OfferedItems: trade.Offer.OfferedItems,
-		CreatedAt:    now,
-	}
+	// Mark the listing sold so it can't reappear as active
+	s.completeListing(ctx, listing, now)
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return nil, nil, err
-	}
+	s.chatArchiver.ArchiveTradeChat(ctx, trade.ID, now)
 
 	// Notify the other party that trade is completed
 	var recipientID string
@@ -246,12 +290,6 @@ func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string
 	}
 	_ = s.notificationService.NotifyTradeCompleted(ctx, recipientID, trade.ID, listing.Name)
 
-	// Invalidate listing DTO cache (status changed to completed)
-	_ = s.invalidator.InvalidateListingDTO(ctx, trade.ListingID)
-
-	// Remove from the appropriate recent cache
-	s.listingService.RemoveFromRecentByListing(ctx, listing)
-
 	// Refresh home stats (tradesToday + activeListings changed)
 	if s.statsService != nil {
 		go s.statsService.RefreshHomeStats(context.Background())
@@ -260,8 +298,209 @@ func (s *TradeServiceNew) Complete(ctx context.Context, id string, userID string
 	return trade, transaction, nil
 }
 
+// errTradeAlreadyCompleted aborts the locked update when the other party completed the trade first
+var errTradeAlreadyCompleted = errors.New("trade already completed")
+
+// completedTrade returns a completed trade with its transaction. If the party
+// that completed it is still writing the transaction, ErrConflict is returned
+// so the caller can retry.
+func (s *TradeServiceNew) completedTrade(ctx context.Context, trade *models.Trade) (*models.Trade, *models.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByTradeID(ctx, trade.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrConflict
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return trade, transaction, nil
+}
+
+// RequestComplete issues a single-use token that must be passed to Complete within
+// TradeCompletionTokenTTL, so a stray request can't finalize a trade. Tokens live in
+// Redis; without it none can be issued and trades can't be completed.
+func (s *TradeServiceNew) RequestComplete(ctx context.Context, id string, userID string) (string, time.Time, error) {
+	if !s.redis.IsAvailable() {
+		return "", time.Time{}, ErrConfirmationUnavailable
+	}
+
+	trade, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if trade.SellerID != userID && trade.BuyerID != userID {
+		return "", time.Time{}, ErrForbidden
+	}
+	if !trade.IsActive() {
+		return "", time.Time{}, ErrInvalidState
+	}
+
+	token, err := generateState()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.redis.Set(ctx, cache.TradeCompletionKey(trade.ID, userID), token, TradeCompletionTokenTTL); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, time.Now().Add(TradeCompletionTokenTTL), nil
+}
+
+// consumeCompletionToken checks the token issued by RequestComplete and invalidates it.
+// Without Redis no token can be checked, so every token is rejected.
+func (s *TradeServiceNew) consumeCompletionToken(ctx context.Context, tradeID string, userID string, token string) error {
+	if token == "" || !s.redis.IsAvailable() {
+		return ErrInvalidConfirmation
+	}
+
+	// GETDEL claims the token atomically, so two requests can't both spend it
+	expected, err := s.redis.GetDel(ctx, cache.TradeCompletionKey(tradeID, userID))
+	if err != nil || subtle.ConstantTimeCompare([]byte(expected), []byte(token)) != 1 {
+		return ErrInvalidConfirmation
+	}
+	return nil
+}
+
+// completeListing marks a traded listing completed, dropping it from search results,
+// active listing counts and home:recent
+func (s *TradeServiceNew) completeListing(ctx context.Context, listing *models.Listing, now time.Time) {
+	listing.Status = "completed"
+	listing.ReservedFor = nil
+	listing.ReservedUntil = nil
+	listing.UpdatedAt = now
+
+	if err := s.listingRepo.Update(ctx, listing); err != nil {
+		logger.FromContext(ctx).Error("failed to mark listing completed",
+			"error", err.Error(),
+			"listing_id", listing.ID,
+		)
+	}
+
+	_ = s.invalidator.InvalidateListing(ctx, listing.ID)
+	_ = s.invalidator.InvalidateListingDTO(ctx, listing.ID)
+	_ = s.invalidator.InvalidateFilterResults(ctx)
+
+	s.listingService.RemoveFromRecentByListing(ctx, listing)
+}
+
+// newTradeTransaction builds the transaction record for a completed trade
+func newTradeTransaction(trade *models.Trade, listing *models.Listing, createdAt time.Time) *models.Transaction {
+	tradeID := trade.ID
+	listingID := trade.ListingID
+	transaction := &models.Transaction{
+		ID:          uuid.New().String(),
+		TradeID:     &tradeID,
+		ListingID:   &listingID,
+		SellerID:    trade.SellerID,
+		BuyerID:     trade.BuyerID,
+		ItemName:    listing.Name,
+		ItemDetails: listing.Stats,
+		CreatedAt:   createdAt,
+	}
+	if trade.Offer != nil {
+		transaction.OfferedItems = trade.Offer.OfferedItems
+	}
+	return transaction
+}
+
+// reconcileGracePeriod leaves recently completed trades to Complete, which may still be
+// writing their transaction and completing the listing
+const reconcileGracePeriod = 5 * time.Minute
+
+// ReconcileTransactions creates the missing transaction for trades completed since the given
+// time whose transaction was never written (e.g. Complete failed halfway), and notifies both
+// parties since the completion notification was never sent either. Trades completed within
+// reconcileGracePeriod are skipped so a run can't race the Complete call that is writing them.
+// It also logs transactions whose trade is not completed; those are left for manual review.
+func (s *TradeServiceNew) ReconcileTransactions(ctx context.Context, since time.Time) (int, error) {
+	log := logger.FromContext(ctx)
+
+	trades, err := s.repo.ListCompletedSince(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := time.Now().Add(-reconcileGracePeriod)
+	created := 0
+	for _, trade := range trades {
+		if trade.CompletedAt != nil && trade.CompletedAt.After(settled) {
+			continue
+		}
+
+		if trade.Listing != nil && trade.Listing.Status != "completed" {
+			log.Warn("completed trade has a listing that is not completed",
+				"trade_id", trade.ID,
+				"listing_id", trade.ListingID,
+				"listing_status", trade.Listing.Status,
+			)
+			s.completeListing(ctx, trade.Listing, time.Now())
+		}
+
+		if _, err := s.transactionRepo.GetByTradeID(ctx, trade.ID); err == nil {
+			continue
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return created, err
+		}
+
+		log.Warn("completed trade has no transaction",
+			"trade_id", trade.ID,
+			"listing_id", trade.ListingID,
+		)
+
+		listing := trade.Listing
+		if listing == nil {
+			listing, err = s.listingRepo.GetByID(ctx, trade.ListingID)
+			if err != nil {
+				return created, err
+			}
+		}
+
+		createdAt := time.Now()
+		if trade.CompletedAt != nil {
+			createdAt = *trade.CompletedAt
+		}
+
+		transaction := newTradeTransaction(trade, listing, createdAt)
+		if err := s.marketEvents.Record(ctx, s.transactionRepo, transaction, models.MarketEventSourceTrade, listing.Game, listing.ItemType); err != nil {
+			return created, err
+		}
+		created++
+
+		_ = s.notificationService.NotifyTradeCompleted(ctx, trade.SellerID, trade.ID, listing.Name)
+		_ = s.notificationService.NotifyTradeCompleted(ctx, trade.BuyerID, trade.ID, listing.Name)
+	}
+
+	transactions, err := s.transactionRepo.ListTradeTransactionsSince(ctx, since)
+	if err != nil {
+		return created, err
+	}
+	for _, transaction := range transactions {
+		if transaction.Trade == nil || !transaction.Trade.IsCompleted() {
+			status := "missing"
+			if transaction.Trade != nil {
+				status = transaction.Trade.Status
+			}
+			log.Warn("transaction references a trade that is not completed",
+				"transaction_id", transaction.ID,
+				"trade_id", transaction.GetTradeID(),
+				"trade_status", status,
+			)
+		}
+	}
+
+	if created > 0 {
+		log.Info("reconciled trade transactions", "created", created)
+	}
+
+	return created, nil
+}
+
 // Cancel cancels an active trade (either party)
-func (s *TradeServiceNew) Cancel(ctx context.Context, id string, userID string, reason string) (*models.Trade, error) {
+func (s *TradeServiceNew) Cancel(ctx context.Context, id string, userID string, req *dto.CancelTradeRequest) (*models.Trade, error) {
+	if req == nil {
+		req = &dto.CancelTradeRequest{}
+	}
+
 	trade, err := s.repo.GetByIDWithRelations(ctx, id)
 	if err != nil {
 		return nil, err
@@ -277,31 +516,65 @@ func (s *TradeServiceNew) Cancel(ctx context.Context, id string, userID string,
 		return nil, ErrInvalidState
 	}
 
+	// Re-check under the row lock so a concurrent complete can't also succeed
 	now := time.Now()
-	trade.Status = "cancelled"
-	trade.CancelledAt = &now
-	trade.CancelledBy = &userID
-	if reason != "" {
-		trade.CancelReason = &reason
-	}
-	trade.UpdatedAt = now
-
-	if err := s.repo.Update(ctx, trade); err != nil {
+	locked, err := s.repo.UpdateLocked(ctx, trade.ID, func(locked *models.Trade) error {
+		if !locked.IsActive() {
+			return ErrInvalidState
+		}
+		locked.Status = "cancelled"
+		locked.CancelledAt = &now
+		locked.CancelledBy = &userID
+		if req.Reason != "" {
+			reason := req.Reason
+			locked.CancelReason = &reason
+		}
+		locked.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	trade.Status = locked.Status
+	trade.CancelledAt = locked.CancelledAt
+	trade.CancelledBy = locked.CancelledBy
+	trade.CancelReason = locked.CancelReason
+	trade.UpdatedAt = locked.UpdatedAt
+
+	s.chatArchiver.ArchiveTradeChat(ctx, trade.ID, now)
 
 	// Sync offer status to cancelled
 	if trade.Offer != nil {
 		trade.Offer.Status = "cancelled"
 		trade.Offer.UpdatedAt = now
 		_ = s.offerRepo.Update(ctx, trade.Offer)
+		_ = s.invalidator.InvalidateOffer(ctx, trade.Offer.ID)
 	}
 
-	// Listing becomes visible again - ensure it's active
+	// Listing becomes visible again - ensure it's active. A seller can ask for a
+	// fresh relist instead, which also restarts its expiry and bumps it to the top of recent.
+	// A listing held as "pending" for the trade gets back a reservation that is still
+	// running, and otherwise returns to home:recent.
+	relist := req.Relist && trade.SellerID == userID
 	listing, err := s.listingRepo.GetByID(ctx, trade.ListingID)
 	if err == nil && listing.Status != "completed" && listing.Status != "cancelled" {
+		wasHeld := listing.Status == "pending"
 		listing.Status = "active"
-		_ = s.listingRepo.Update(ctx, listing)
+		if wasHeld && !relist && listing.ReservedUntil != nil && listing.ReservedUntil.After(now) {
+			listing.Status = "reserved"
+		} else {
+			listing.ReservedFor = nil
+			listing.ReservedUntil = nil
+		}
+		if relist {
+			listing.CreatedAt = now
+			listing.UpdatedAt = now
+			listing.ExpiresAt = now.Add(s.listingService.LifetimeFor(ctx, listing.SellerID))
+			listing.ExpiryWarnedAt = nil
+		}
+		if s.listingRepo.Update(ctx, listing) == nil && (relist || (wasHeld && listing.IsActive())) {
+			s.listingService.PushRelisted(ctx, listing)
+		}
 	}
 
 	// Notify the other party
@@ -324,17 +597,108 @@ func (s *TradeServiceNew) Cancel(ctx context.Context, id string, userID string,
 	return trade, nil
 }
 
-// List retrieves trades for a user
-func (s *TradeServiceNew) List(ctx context.Context, userID string, status string, offset, limit int) ([]*models.Trade, int, error) {
+// List retrieves trades for a user, optionally narrowed to one role and counterparty.
+// Like offers, the seller view defaults to the trades still waiting on them.
+func (s *TradeServiceNew) List(ctx context.Context, userID string, role string, status string, counterpartyID string, offset, limit int) ([]*models.Trade, int, error) {
+	if role == "seller" && status == "" {
+		status = "active"
+	}
+
 	filter := repository.TradeFilter{
-		UserID: userID,
-		Status: status,
-		Offset: offset,
-		Limit:  limit,
+		UserID:         userID,
+		Role:           role,
+		Status:         status,
+		CounterpartyID: counterpartyID,
+		Offset:         offset,
+		Limit:          limit,
 	}
 	return s.repo.List(ctx, filter)
 }
 
+// ToggleItemReceived ticks or unticks one offered item (by index) on the caller's
+// side of the trade checklist. Once both parties have ticked every item the
+// detail response reports the checklist complete, suggesting the trade be completed.
+func (s *TradeServiceNew) ToggleItemReceived(ctx context.Context, tradeID string, userID string, itemIndex int, received bool) (*models.Trade, error) {
+	trade, err := s.repo.GetByIDWithRelations(ctx, tradeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var role string
+	var confirmed *[]int
+	switch userID {
+	case trade.SellerID:
+		role, confirmed = "seller", &trade.SellerConfirmedItems
+	case trade.BuyerID:
+		role, confirmed = "buyer", &trade.BuyerConfirmedItems
+	default:
+		return nil, ErrForbidden
+	}
+
+	if !trade.IsActive() {
+		return nil, ErrInvalidState
+	}
+
+	if itemIndex < 0 || trade.Offer == nil || itemIndex >= len(s.transformOfferedItems(trade.Offer.OfferedItems)) {
+		return nil, ErrNotFound
+	}
+
+	*confirmed = setConfirmedItem(*confirmed, itemIndex, received)
+	trade.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateConfirmedItems(ctx, trade, role); err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// setConfirmedItem adds or removes index from a checklist, keeping it sorted and unique
+func setConfirmedItem(items []int, index int, received bool) []int {
+	result := make([]int, 0, len(items)+1)
+	for _, i := range items {
+		if i != index {
+			result = append(result, i)
+		}
+	}
+	if received {
+		result = append(result, index)
+		sort.Ints(result)
+	}
+	return result
+}
+
+// tradeChecklist builds the received checklist for the trade's offered items
+func tradeChecklist(trade *models.Trade, items []dto.OfferedItemResponse) ([]dto.TradeChecklistItemResponse, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+
+	seller := make(map[int]bool, len(trade.SellerConfirmedItems))
+	for _, i := range trade.SellerConfirmedItems {
+		seller[i] = true
+	}
+	buyer := make(map[int]bool, len(trade.BuyerConfirmedItems))
+	for _, i := range trade.BuyerConfirmedItems {
+		buyer[i] = true
+	}
+
+	checklist := make([]dto.TradeChecklistItemResponse, 0, len(items))
+	complete := true
+	for i, item := range items {
+		checklist = append(checklist, dto.TradeChecklistItemResponse{
+			Index:           i,
+			Name:            item.Name,
+			Quantity:        item.Quantity,
+			SellerConfirmed: seller[i],
+			BuyerConfirmed:  buyer[i],
+		})
+		complete = complete && seller[i] && buyer[i]
+	}
+
+	return checklist, complete
+}
+
 // ToResponse converts a trade model to a DTO response
 // For completed trades, it fetches the transaction and checks rating status
 func (s *TradeServiceNew) ToResponse(trade *models.Trade) *dto.TradeResponse {
@@ -377,12 +741,23 @@ func (s *TradeServiceNew) toResponseInternal(trade *models.Trade, ctx *context.C
 		resp.Buyer = s.profileService.ToResponse(trade.Buyer)
 	}
 
+	// From the viewer's side, the counterparty is whoever they're trading with
+	switch userID {
+	case trade.SellerID:
+		resp.Role = "seller"
+		resp.Counterparty = resp.Buyer
+	case trade.BuyerID:
+		resp.Role = "buyer"
+		resp.Counterparty = resp.Seller
+	}
+
 	if trade.Chat != nil {
 		resp.ChatID = &trade.Chat.ID
 	}
 
 	if trade.Offer != nil {
 		resp.OfferedItems = s.transformOfferedItems(trade.Offer.OfferedItems)
+		resp.RequestedAddition = s.transformOfferedItems(trade.Offer.RequestedAddition)
 	}
 
 	// For completed trades, fetch transaction and check rating eligibility
@@ -404,10 +779,12 @@ func (s *TradeServiceNew) toResponseInternal(trade *models.Trade, ctx *context.C
 
 // ToDetailResponse converts a trade model to a detailed DTO response
 func (s *TradeServiceNew) ToDetailResponse(ctx context.Context, trade *models.Trade, userID string) *dto.TradeDetailResponse {
-	return &dto.TradeDetailResponse{
+	resp := &dto.TradeDetailResponse{
 		TradeResponse: *s.ToResponseWithUser(ctx, trade, userID),
 		CanComplete:   trade.IsActive() && (trade.SellerID == userID || trade.BuyerID == userID),
 		CanCancel:     trade.IsActive(),
 		CanMessage:    trade.IsActive(),
 	}
+	resp.Checklist, resp.ChecklistComplete = tradeChecklist(trade, resp.OfferedItems)
+	return resp
 }
@@ -2,10 +2,17 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,6 +21,9 @@ import (
 
 const testSupabaseURL = "https://supabase.example.com"
 
+// testCompletionToken is the token confirmCompletion stores for Complete to check
+const testCompletionToken = "confirm-token"
+
 // newTradeServiceForTest creates a TradeServiceNew wired up with mocks for testing.
 // It returns the service and all underlying mocks so callers can set expectations.
 type tradeTestHarness struct {
@@ -32,6 +42,21 @@ type tradeTestHarness struct {
 	listingService  *ListingService
 }
 
+// confirmCompletion stores testCompletionToken for each user as RequestComplete would,
+// attaching Redis to the service first if it has none
+func (h *tradeTestHarness) confirmCompletion(t *testing.T, userIDs ...string) {
+	t.Helper()
+	if !h.svc.redis.IsAvailable() {
+		rc, _ := newTestRedisReal(t)
+		h.svc.redis = rc
+		h.svc.invalidator = cache.NewInvalidator(rc)
+	}
+	for _, userID := range userIDs {
+		err := h.svc.redis.Set(context.Background(), cache.TradeCompletionKey(testTradeID, userID), testCompletionToken, TradeCompletionTokenTTL)
+		require.NoError(t, err)
+	}
+}
+
 func newTradeTestHarness() *tradeTestHarness {
 	tradeRepo := new(mocks.MockTradeRepository)
 	listingRepo := new(mocks.MockListingRepository)
@@ -70,6 +95,23 @@ func newTradeTestHarness() *tradeTestHarness {
 	}
 }
 
+// lockTrade makes UpdateLocked behave like the row lock: callers run one at a
+// time and each sees, and saves into, the stored trade
+func (h *tradeTestHarness) lockTrade(stored *models.Trade) {
+	var mu sync.Mutex
+	h.tradeRepo.On("UpdateLocked", mock.Anything, stored.ID, mock.Anything).Return(
+		func(_ context.Context, _ string, fn func(*models.Trade) error) (*models.Trade, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			locked := *stored
+			if err := fn(&locked); err != nil {
+				return nil, err
+			}
+			*stored = locked
+			return &locked, nil
+		})
+}
+
 func makeOfferedItemsJSON() json.RawMessage {
 	items := []map[string]interface{}{
 		{"name": "Ber", "type": "rune", "quantity": 1},
@@ -145,14 +187,15 @@ func TestTradeComplete_Success(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
 	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
 	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID)
+	h.confirmCompletion(t, testSellerID)
+	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
 
 	require.NoError(t, err)
 	assert.Equal(t, "completed", resultTrade.Status)
@@ -168,6 +211,52 @@ func TestTradeComplete_Success(t *testing.T) {
 	h.transactionRepo.AssertExpectations(t)
 }
 
+func TestTradeComplete_MarksListingCompletedAndRefreshesStats(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	statsRepo := new(mocks.MockStatsRepository)
+	h.svc.SetStatsService(NewStatsService(statsRepo, nil))
+	refreshed := make(chan struct{})
+	statsRepo.On("GetMarketplaceStats", mock.Anything).
+		Run(func(mock.Arguments) { close(refreshed) }).
+		Return(&repository.MarketplaceStats{}, nil).Once()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	offer.OfferedItems = makeOfferedItemsJSON()
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	var updated *models.Listing
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).
+		Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*models.Listing)
+		}).Return(nil)
+	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	h.confirmCompletion(t, testBuyerID)
+	_, _, err := h.svc.Complete(ctx, testTradeID, testBuyerID, testCompletionToken)
+
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	assert.Equal(t, "completed", updated.Status)
+	assert.False(t, updated.IsActive())
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("home stats were not refreshed")
+	}
+}
+
 func TestTradeComplete_Idempotent(t *testing.T) {
 	h := newTradeTestHarness()
 	ctx := context.Background()
@@ -181,14 +270,14 @@ func TestTradeComplete_Idempotent(t *testing.T) {
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
 	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(existingTx, nil)
 
-	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID)
+	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
 
 	require.NoError(t, err)
 	assert.Equal(t, "completed", resultTrade.Status)
 	assert.Equal(t, testTransactionID, resultTx.ID)
 
-	// Should NOT have called Update or Create since it's already completed
-	h.tradeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	// Should NOT have called UpdateLocked or Create since it's already completed
+	h.tradeRepo.AssertNotCalled(t, "UpdateLocked", mock.Anything, mock.Anything, mock.Anything)
 	h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	h.tradeRepo.AssertExpectations(t)
 	h.transactionRepo.AssertExpectations(t)
@@ -205,7 +294,7 @@ func TestTradeComplete_NotActive(t *testing.T) {
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
 
-	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID)
+	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
 
 	assert.Nil(t, resultTrade)
 	assert.Nil(t, resultTx)
@@ -220,7 +309,7 @@ func TestTradeComplete_NotParticipant(t *testing.T) {
 	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
 
-	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, "stranger-999")
+	resultTrade, resultTx, err := h.svc.Complete(ctx, testTradeID, "stranger-999", testCompletionToken)
 
 	assert.Nil(t, resultTrade)
 	assert.Nil(t, resultTx)
@@ -243,7 +332,7 @@ func TestTradeComplete_SellerCompletes_NotifiesBuyer(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
@@ -259,7 +348,8 @@ func TestTradeComplete_SellerCompletes_NotifiesBuyer(t *testing.T) {
 		}).
 		Return(nil)
 
-	_, _, err := h.svc.Complete(ctx, testTradeID, testSellerID)
+	h.confirmCompletion(t, testSellerID)
+	_, _, err := h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
 
 	require.NoError(t, err)
 	h.notifRepo.AssertExpectations(t)
@@ -280,7 +370,7 @@ func TestTradeComplete_BuyerCompletes_NotifiesSeller(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
@@ -296,12 +386,255 @@ func TestTradeComplete_BuyerCompletes_NotifiesSeller(t *testing.T) {
 		}).
 		Return(nil)
 
-	_, _, err := h.svc.Complete(ctx, testTradeID, testBuyerID)
+	h.confirmCompletion(t, testBuyerID)
+	_, _, err := h.svc.Complete(ctx, testTradeID, testBuyerID, testCompletionToken)
 
 	require.NoError(t, err)
 	h.notifRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// ReconcileTransactions
+// ---------------------------------------------------------------------------
+
+// newRaceTradeHarness stubs a trade that two callers load at the same time: each
+// GetByIDWithRelations gets its own active snapshot while UpdateLocked serializes
+// on the stored row
+func newRaceTradeHarness() (*tradeTestHarness, *models.Trade) {
+	h := newTradeTestHarness()
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	offer.OfferedItems = makeOfferedItemsJSON()
+	stored := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	for i := 0; i < 2; i++ {
+		snapshot := *stored
+		h.tradeRepo.On("GetByIDWithRelations", mock.Anything, testTradeID).Return(&snapshot, nil).Once()
+	}
+	h.lockTrade(stored)
+	h.offerRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", mock.Anything, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.transactionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	return h, stored
+}
+
+func TestTradeCompleteAndCancel_ConcurrentResolveToOneOutcome(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		h, stored := newRaceTradeHarness()
+		h.confirmCompletion(t, testSellerID)
+		ctx := context.Background()
+
+		var (
+			wg                     sync.WaitGroup
+			completeErr, cancelErr error
+		)
+		start := make(chan struct{})
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, _, completeErr = h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			_, cancelErr = h.svc.Cancel(ctx, testTradeID, testBuyerID, nil)
+		}()
+		close(start)
+		wg.Wait()
+
+		if completeErr == nil {
+			assert.ErrorIs(t, cancelErr, ErrInvalidState)
+			assert.Equal(t, "completed", stored.Status)
+			assert.Nil(t, stored.CancelledAt)
+			h.transactionRepo.AssertNumberOfCalls(t, "Create", 1)
+		} else {
+			require.NoError(t, cancelErr)
+			assert.ErrorIs(t, completeErr, ErrInvalidState)
+			assert.Equal(t, "cancelled", stored.Status)
+			assert.Nil(t, stored.CompletedAt)
+			h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+		h.notifRepo.AssertNumberOfCalls(t, "Create", 1)
+	}
+}
+
+func TestTradeComplete_ConcurrentCompletesCreateOneTransaction(t *testing.T) {
+	h, stored := newRaceTradeHarness()
+	h.confirmCompletion(t, testSellerID, testBuyerID)
+	ctx := context.Background()
+	h.transactionRepo.On("GetByTradeID", mock.Anything, testTradeID).
+		Return(testTransaction(testTransactionID, testSellerID, testBuyerID), nil)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, userID := range []string{testSellerID, testBuyerID} {
+		wg.Add(1)
+		go func(i int, userID string) {
+			defer wg.Done()
+			_, _, errs[i] = h.svc.Complete(ctx, testTradeID, userID, testCompletionToken)
+		}(i, userID)
+	}
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, "completed", stored.Status)
+	h.transactionRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestTradeComplete_CompletedByOtherPartyBeforeTransactionWritten(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	snapshot := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(snapshot, nil)
+	h.tradeRepo.On("UpdateLocked", ctx, testTradeID, mock.Anything).Return(nil, errTradeAlreadyCompleted)
+	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(nil, sql.ErrNoRows)
+
+	h.confirmCompletion(t, testBuyerID)
+	_, _, err := h.svc.Complete(ctx, testTradeID, testBuyerID, testCompletionToken)
+
+	assert.ErrorIs(t, err, ErrConflict)
+	h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReconcileTransactions_CreatesMissing(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("completed"))
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("completed"))
+	offer.OfferedItems = makeOfferedItemsJSON()
+	completedAt := time.Now().Add(-time.Hour)
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeStatus("completed"),
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+	trade.CompletedAt = &completedAt
+
+	var created *models.Transaction
+	h.tradeRepo.On("ListCompletedSince", ctx, since).Return([]*models.Trade{trade}, nil)
+	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(nil, sql.ErrNoRows)
+	h.transactionRepo.On("Create", ctx, mock.AnythingOfType("*models.Transaction")).
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).(*models.Transaction)
+		}).Return(nil)
+	h.transactionRepo.On("ListTradeTransactionsSince", ctx, since).Return([]*models.Transaction{}, nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	count, err := h.svc.ReconcileTransactions(ctx, since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NotNil(t, created)
+	assert.Equal(t, testTradeID, created.GetTradeID())
+	assert.Equal(t, listing.Name, created.ItemName)
+	assert.JSONEq(t, string(offer.OfferedItems), string(created.OfferedItems))
+	assert.Equal(t, completedAt, created.CreatedAt)
+	h.notifRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestReconcileTransactions_SkipsExisting(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeStatus("completed"),
+	)
+	existingTx := testTransaction(testTransactionID, testSellerID, testBuyerID)
+	existingTx.Trade = trade
+
+	h.tradeRepo.On("ListCompletedSince", ctx, since).Return([]*models.Trade{trade}, nil)
+	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(existingTx, nil)
+	h.transactionRepo.On("ListTradeTransactionsSince", ctx, since).Return([]*models.Transaction{existingTx}, nil)
+
+	count, err := h.svc.ReconcileTransactions(ctx, since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReconcileTransactions_CompletesActiveListing(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	listing := testListing(testListingID, testSellerID)
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeStatus("completed"),
+		withTradeListing(listing),
+	)
+	existingTx := testTransaction(testTransactionID, testSellerID, testBuyerID)
+	existingTx.Trade = trade
+
+	h.tradeRepo.On("ListCompletedSince", ctx, since).Return([]*models.Trade{trade}, nil)
+	h.listingRepo.On("Update", ctx, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.ID == testListingID && l.Status == "completed"
+	})).Return(nil)
+	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(existingTx, nil)
+	h.transactionRepo.On("ListTradeTransactionsSince", ctx, since).Return([]*models.Transaction{existingTx}, nil)
+
+	_, err := h.svc.ReconcileTransactions(ctx, since)
+
+	require.NoError(t, err)
+	h.listingRepo.AssertExpectations(t)
+}
+
+func TestReconcileTransactions_SkipsRecentlyCompleted(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	// Complete may still be writing this trade's transaction and listing
+	completedAt := time.Now().Add(-time.Minute)
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeStatus("completed"),
+		withTradeListing(testListing(testListingID, testSellerID)),
+	)
+	trade.CompletedAt = &completedAt
+
+	h.tradeRepo.On("ListCompletedSince", ctx, since).Return([]*models.Trade{trade}, nil)
+	h.transactionRepo.On("ListTradeTransactionsSince", ctx, since).Return([]*models.Transaction{}, nil)
+
+	count, err := h.svc.ReconcileTransactions(ctx, since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	h.transactionRepo.AssertNotCalled(t, "GetByTradeID", mock.Anything, mock.Anything)
+	h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	h.listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestReconcileTransactions_LookupError(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeStatus("completed"),
+	)
+
+	h.tradeRepo.On("ListCompletedSince", ctx, since).Return([]*models.Trade{trade}, nil)
+	h.transactionRepo.On("GetByTradeID", ctx, testTradeID).Return(nil, errors.New("db down"))
+
+	_, err := h.svc.ReconcileTransactions(ctx, since)
+
+	assert.Error(t, err)
+	h.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 // ---------------------------------------------------------------------------
 // Cancel
 // ---------------------------------------------------------------------------
@@ -319,13 +652,14 @@ func TestTradeCancel_Success(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.profileRepo.On("GetByID", ctx, testSellerID).Return(testProfile(testSellerID), nil)
 	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, "")
+	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, nil)
 
 	require.NoError(t, err)
 	assert.Equal(t, "cancelled", result.Status)
@@ -350,7 +684,7 @@ func TestTradeCancel_NotActive(t *testing.T) {
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
 
-	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, "changed my mind")
+	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, &dto.CancelTradeRequest{Reason: "changed my mind"})
 
 	assert.Nil(t, result)
 	assert.ErrorIs(t, err, ErrInvalidState)
@@ -364,7 +698,7 @@ func TestTradeCancel_NotParticipant(t *testing.T) {
 	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
 
-	result, err := h.svc.Cancel(ctx, testTradeID, "stranger-999", "")
+	result, err := h.svc.Cancel(ctx, testTradeID, "stranger-999", nil)
 
 	assert.Nil(t, result)
 	assert.ErrorIs(t, err, ErrForbidden)
@@ -384,14 +718,14 @@ func TestTradeCancel_WithReason(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
 	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
 
 	cancelReason := "Item was misrepresented"
-	result, err := h.svc.Cancel(ctx, testTradeID, testBuyerID, cancelReason)
+	result, err := h.svc.Cancel(ctx, testTradeID, testBuyerID, &dto.CancelTradeRequest{Reason: cancelReason})
 
 	require.NoError(t, err)
 	assert.Equal(t, "cancelled", result.Status)
@@ -416,12 +750,12 @@ func TestTradeCancel_ListingAlreadyCompleted_NoReactivation(t *testing.T) {
 	)
 
 	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
-	h.tradeRepo.On("Update", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	h.lockTrade(trade)
 	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, "")
+	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, nil)
 
 	require.NoError(t, err)
 	assert.Equal(t, "cancelled", result.Status)
@@ -431,6 +765,128 @@ func TestTradeCancel_ListingAlreadyCompleted_NoReactivation(t *testing.T) {
 	h.tradeRepo.AssertExpectations(t)
 }
 
+func TestTradeCancel_SellerRelist(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	listing.CreatedAt = time.Now().Add(-20 * 24 * time.Hour)
+	listing.ExpiresAt = time.Now().Add(10 * 24 * time.Hour)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.profileRepo.On("GetByID", ctx, testSellerID).Return(testProfile(testSellerID), nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	result, err := h.svc.Cancel(ctx, testTradeID, testSellerID, &dto.CancelTradeRequest{Relist: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", result.Status)
+	assert.Equal(t, "active", listing.Status)
+	assert.WithinDuration(t, time.Now(), listing.CreatedAt, time.Minute)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), listing.ExpiresAt, time.Minute)
+	h.profileRepo.AssertExpectations(t)
+}
+
+func TestTradeCancel_BuyerRelistIgnored(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	createdAt := time.Now().Add(-20 * 24 * time.Hour)
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	listing.CreatedAt = createdAt
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.profileRepo.On("GetByID", ctx, testSellerID).Return(testProfile(testSellerID), nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, err := h.svc.Cancel(ctx, testTradeID, testBuyerID, &dto.CancelTradeRequest{Relist: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "active", listing.Status)
+	assert.Equal(t, createdAt, listing.CreatedAt)
+}
+
+func TestTradeCancel_HeldListingReturnsToRecent(t *testing.T) {
+	h := newTradeTestHarness()
+	redisClient, _ := newTestRedisReal(t)
+	h.svc.listingService = NewListingService(h.listingRepoSvc, h.profileService, redisClient)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.profileRepo.On("GetByID", ctx, testSellerID).Return(testProfile(testSellerID), nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, err := h.svc.Cancel(ctx, testTradeID, testBuyerID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "active", listing.Status)
+	recent, err := h.svc.listingService.GetRecentListings(ctx)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, testListingID, recent[0].ID)
+}
+
+func TestTradeCancel_HeldListingKeepsRunningReservation(t *testing.T) {
+	h := newTradeTestHarness()
+	redisClient, _ := newTestRedisReal(t)
+	h.svc.listingService = NewListingService(h.listingRepoSvc, h.profileService, redisClient)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(time.Hour)))
+	listing.Status = "pending"
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, err := h.svc.Cancel(ctx, testTradeID, testSellerID, nil)
+
+	require.NoError(t, err)
+	assert.True(t, listing.IsReservedFor(testBuyerID))
+	recent, err := h.svc.listingService.GetRecentListings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, recent)
+}
+
 // ---------------------------------------------------------------------------
 // generateItemImageURL
 // ---------------------------------------------------------------------------
@@ -543,6 +999,72 @@ func TestTransformOfferedItems_Empty(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+// ---------------------------------------------------------------------------
+// List
+// ---------------------------------------------------------------------------
+
+func TestTradeList_SellerDefaultsToActive(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	h.tradeRepo.On("List", ctx, mock.MatchedBy(func(f repository.TradeFilter) bool {
+		return f.UserID == testSellerID && f.Role == "seller" && f.Status == "active"
+	})).Return([]*models.Trade{}, 0, nil)
+
+	_, _, err := h.svc.List(ctx, testSellerID, "seller", "", "", 0, 20)
+
+	require.NoError(t, err)
+	h.tradeRepo.AssertExpectations(t)
+}
+
+func TestTradeList_PassesFilters(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	h.tradeRepo.On("List", ctx, mock.MatchedBy(func(f repository.TradeFilter) bool {
+		return f.Role == "buyer" && f.Status == "completed" && f.CounterpartyID == testSellerID &&
+			f.Offset == 20 && f.Limit == 10
+	})).Return([]*models.Trade{}, 0, nil)
+
+	_, _, err := h.svc.List(ctx, testBuyerID, "buyer", "completed", testSellerID, 20, 10)
+
+	require.NoError(t, err)
+	h.tradeRepo.AssertExpectations(t)
+}
+
+func TestTradeList_AllRolesKeepsEmptyStatus(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	h.tradeRepo.On("List", ctx, mock.MatchedBy(func(f repository.TradeFilter) bool {
+		return f.Role == "" && f.Status == ""
+	})).Return([]*models.Trade{}, 0, nil)
+
+	_, _, err := h.svc.List(ctx, testBuyerID, "", "", "", 0, 20)
+
+	require.NoError(t, err)
+	h.tradeRepo.AssertExpectations(t)
+}
+
+func TestTradeToResponseWithUser_Counterparty(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	trade.Seller = testProfile(testSellerID)
+	trade.Buyer = testProfile(testBuyerID)
+
+	asSeller := h.svc.ToResponseWithUser(ctx, trade, testSellerID)
+	assert.Equal(t, "seller", asSeller.Role)
+	require.NotNil(t, asSeller.Counterparty)
+	assert.Equal(t, testBuyerID, asSeller.Counterparty.ID)
+
+	asBuyer := h.svc.ToResponseWithUser(ctx, trade, testBuyerID)
+	assert.Equal(t, "buyer", asBuyer.Role)
+	require.NotNil(t, asBuyer.Counterparty)
+	assert.Equal(t, testSellerID, asBuyer.Counterparty.ID)
+}
+
 // ---------------------------------------------------------------------------
 // ToDetailResponse
 // ---------------------------------------------------------------------------
@@ -563,6 +1085,21 @@ func TestTradeToDetailResponse_ActiveTrade(t *testing.T) {
 	assert.False(t, detail.CanRate, "cannot rate an active trade")
 }
 
+func TestTradeToDetailResponse_RequestedAddition(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID))
+	offer.RequestedAddition = json.RawMessage(`[{"name":"Ist","type":"rune","imageUrl":"ist.png","quantity":2}]`)
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID, withTradeOffer(offer))
+
+	detail := h.svc.ToDetailResponse(ctx, trade, testSellerID)
+
+	require.Len(t, detail.RequestedAddition, 1)
+	assert.Equal(t, "Ist", detail.RequestedAddition[0].Name)
+	assert.Equal(t, 2, detail.RequestedAddition[0].Quantity)
+}
+
 func TestTradeToDetailResponse_CompletedTrade(t *testing.T) {
 	h := newTradeTestHarness()
 	ctx := context.Background()
@@ -591,3 +1128,214 @@ func TestTradeToDetailResponse_CompletedTrade(t *testing.T) {
 	h.transactionRepo.AssertExpectations(t)
 	h.ratingRepo.AssertExpectations(t)
 }
+
+// ---------------------------------------------------------------------------
+// Completion confirmation
+// ---------------------------------------------------------------------------
+
+// newCompletionTestHarness returns a trade harness whose service stores completion tokens in miniredis
+func newCompletionTestHarness(t *testing.T) (*tradeTestHarness, *models.Trade) {
+	h := newTradeTestHarness()
+	rc, _ := newTestRedisReal(t)
+	h.svc.redis = rc
+	h.svc.invalidator = cache.NewInvalidator(rc)
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, &listing.ID, withOfferStatus("accepted"))
+	offer.OfferedItems = makeOfferedItemsJSON()
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID,
+		withTradeOffer(offer),
+		withTradeListing(listing),
+	)
+
+	h.tradeRepo.On("GetByID", mock.Anything, testTradeID).Return(trade, nil)
+	h.tradeRepo.On("GetByIDWithRelations", mock.Anything, testTradeID).Return(trade, nil)
+	h.lockTrade(trade)
+	h.offerRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Offer")).Return(nil)
+	h.listingRepo.On("GetByID", mock.Anything, testListingID).Return(listing, nil)
+	h.listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+	h.transactionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Transaction")).Return(nil)
+	h.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	return h, trade
+}
+
+func TestTradeRequestComplete_TokenCompletesTradeOnce(t *testing.T) {
+	h, trade := newCompletionTestHarness(t)
+	ctx := context.Background()
+
+	token, expiresAt, err := h.svc.RequestComplete(ctx, testTradeID, testSellerID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.WithinDuration(t, time.Now().Add(TradeCompletionTokenTTL), expiresAt, 5*time.Second)
+
+	result, tx, err := h.svc.Complete(ctx, testTradeID, testSellerID, token)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", result.Status)
+	assert.NotNil(t, tx)
+
+	// Token is single-use: a fresh active trade can't be completed with it again
+	trade.Status = "active"
+	_, _, err = h.svc.Complete(ctx, testTradeID, testSellerID, token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+}
+
+func TestTradeComplete_RejectsWrongOrMissingToken(t *testing.T) {
+	h, _ := newCompletionTestHarness(t)
+	ctx := context.Background()
+
+	_, _, err := h.svc.RequestComplete(ctx, testTradeID, testSellerID)
+	require.NoError(t, err)
+
+	_, _, err = h.svc.Complete(ctx, testTradeID, testSellerID, "")
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+
+	_, _, err = h.svc.Complete(ctx, testTradeID, testSellerID, "guessed")
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+
+	h.tradeRepo.AssertNotCalled(t, "UpdateLocked", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTradeComplete_WithoutRedisFailsClosed(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID)
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+
+	_, _, err := h.svc.RequestComplete(ctx, testTradeID, testSellerID)
+	assert.ErrorIs(t, err, ErrConfirmationUnavailable)
+
+	_, _, err = h.svc.Complete(ctx, testTradeID, testSellerID, testCompletionToken)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+	h.tradeRepo.AssertNotCalled(t, "UpdateLocked", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTradeComplete_TokenScopedToRequester(t *testing.T) {
+	h, _ := newCompletionTestHarness(t)
+	ctx := context.Background()
+
+	token, _, err := h.svc.RequestComplete(ctx, testTradeID, testSellerID)
+	require.NoError(t, err)
+
+	_, _, err = h.svc.Complete(ctx, testTradeID, testBuyerID, token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+}
+
+func TestTradeComplete_AlreadyCompletedIgnoresToken(t *testing.T) {
+	h, trade := newCompletionTestHarness(t)
+	ctx := context.Background()
+
+	trade.Status = "completed"
+	existing := testTransaction("tx-1", testSellerID, testBuyerID)
+	h.transactionRepo.On("GetByTradeID", mock.Anything, testTradeID).Return(existing, nil)
+
+	_, tx, err := h.svc.Complete(ctx, testTradeID, testSellerID, "already-used")
+
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", tx.ID)
+}
+
+func TestTradeRequestComplete_NotParticipant(t *testing.T) {
+	h, _ := newCompletionTestHarness(t)
+
+	_, _, err := h.svc.RequestComplete(context.Background(), testTradeID, "stranger-999")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+// ---------------------------------------------------------------------------
+// ToggleItemReceived
+// ---------------------------------------------------------------------------
+
+func newChecklistTrade() *models.Trade {
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferStatus("accepted"))
+	offer.OfferedItems = json.RawMessage(`[{"name":"Ber","type":"rune","quantity":1},{"name":"Jah","type":"rune","quantity":2}]`)
+	return testTrade(testTradeID, testOfferID, testListingID, testSellerID, testBuyerID, withTradeOffer(offer))
+}
+
+func TestTradeToggleItemReceived_UpdatesCallerSide(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := newChecklistTrade()
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.tradeRepo.On("UpdateConfirmedItems", ctx, trade, "buyer").Return(nil)
+
+	result, err := h.svc.ToggleItemReceived(ctx, testTradeID, testBuyerID, 1, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, result.BuyerConfirmedItems)
+	assert.Empty(t, result.SellerConfirmedItems)
+	h.tradeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTradeToggleItemReceived_Untick(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := newChecklistTrade()
+	trade.SellerConfirmedItems = []int{0, 1}
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+	h.tradeRepo.On("UpdateConfirmedItems", ctx, trade, "seller").Return(nil)
+
+	result, err := h.svc.ToggleItemReceived(ctx, testTradeID, testSellerID, 0, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, result.SellerConfirmedItems)
+}
+
+func TestTradeToggleItemReceived_IndexOutOfRange(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(newChecklistTrade(), nil)
+
+	for _, index := range []int{-1, 2} {
+		_, err := h.svc.ToggleItemReceived(ctx, testTradeID, testBuyerID, index, true)
+		assert.ErrorIs(t, err, ErrNotFound, index)
+	}
+}
+
+func TestTradeToggleItemReceived_NotParticipant(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(newChecklistTrade(), nil)
+
+	_, err := h.svc.ToggleItemReceived(ctx, testTradeID, "stranger-999", 0, true)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestTradeToggleItemReceived_InactiveTrade(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := newChecklistTrade()
+	trade.Status = "cancelled"
+	h.tradeRepo.On("GetByIDWithRelations", ctx, testTradeID).Return(trade, nil)
+
+	_, err := h.svc.ToggleItemReceived(ctx, testTradeID, testSellerID, 0, true)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestTradeToDetailResponse_ChecklistComplete(t *testing.T) {
+	h := newTradeTestHarness()
+	ctx := context.Background()
+
+	trade := newChecklistTrade()
+	trade.SellerConfirmedItems = []int{0, 1}
+	trade.BuyerConfirmedItems = []int{0}
+
+	resp := h.svc.ToDetailResponse(ctx, trade, testSellerID)
+	require.Len(t, resp.Checklist, 2)
+	assert.Equal(t, "Jah", resp.Checklist[1].Name)
+	assert.True(t, resp.Checklist[0].BuyerConfirmed)
+	assert.False(t, resp.Checklist[1].BuyerConfirmed)
+	assert.False(t, resp.ChecklistComplete)
+
+	trade.BuyerConfirmedItems = []int{0, 1}
+	assert.True(t, h.svc.ToDetailResponse(ctx, trade, testSellerID).ChecklistComplete)
+}
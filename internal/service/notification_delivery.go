@@ -0,0 +1,87 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+// webhookDeliveryTimeout bounds each delivery call, since delivery runs inline with Create
+const webhookDeliveryTimeout = 5 * time.Second
+
+// webhookNotification is the JSON body posted to the delivery webhook
+type webhookNotification struct {
+	ID            string          `json:"id,omitempty"`
+	UserID        string          `json:"userId"`
+	Type          string          `json:"type"`
+	Title         string          `json:"title"`
+	Body          string          `json:"body,omitempty"`
+	ReferenceType string          `json:"referenceType,omitempty"`
+	ReferenceID   string          `json:"referenceId,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// WebhookDeliverer hands notifications to an external push/email relay by POSTing
+// them as JSON to a configured URL
+type WebhookDeliverer struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewWebhookDeliverer creates a deliverer that posts to url. A non-empty secret is sent
+// as a bearer token so the relay can reject other callers.
+func NewWebhookDeliverer(url, secret string) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: webhookDeliveryTimeout},
+	}
+}
+
+// Deliver posts the notification to the relay. Any non-2xx response is an error.
+func (d *WebhookDeliverer) Deliver(ctx context.Context, notification *models.Notification) error {
+	body, err := json.Marshal(webhookNotification{
+		ID:            notification.ID,
+		UserID:        notification.UserID,
+		Type:          string(notification.Type),
+		Title:         notification.Title,
+		Body:          notification.GetBody(),
+		ReferenceType: notification.GetReferenceType(),
+		ReferenceID:   notification.GetReferenceID(),
+		Metadata:      notification.Metadata,
+		CreatedAt:     notification.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.secret != "" {
+		req.Header.Set("Authorization", "Bearer "+d.secret)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notification delivery failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,13 +20,72 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 )
 
-const notificationCountCacheTTL = 1 * time.Minute
+const (
+	notificationCountCacheTTL = 1 * time.Minute
+	notificationDigestTTL     = 24 * time.Hour
+	// notificationDigestFlushBatchSize is how many due users one FlushDueDigests step claims at a time
+	notificationDigestFlushBatchSize = 100
+	// notificationDedupWindow is how long a repeat of the same notification event is suppressed
+	notificationDedupWindow = 10 * time.Minute
+	// notificationPrefsCacheTTL is how long a user's notification preferences are cached
+	notificationPrefsCacheTTL = 10 * time.Minute
+	// notificationBatchWindow is how long CreateBatched keeps folding similar notifications into one
+	notificationBatchWindow = 60 * time.Second
+
+	// broadcastBatchSize is how many recipients are notified per insert
+	broadcastBatchSize = 500
+	// broadcastBatchDelay spaces out batches so a broadcast doesn't saturate the DB or Redis
+	broadcastBatchDelay = 250 * time.Millisecond
+	// broadcastJobTTL is how long a broadcast's progress stays readable
+	broadcastJobTTL = 7 * 24 * time.Hour
+)
+
+// Notification stream event types
+const (
+	StreamEventNotification = "notification"
+	StreamEventUnreadCount  = "unread_count"
+)
+
+// Broadcast job statuses
+const (
+	BroadcastStatusQueued   = "queued"
+	BroadcastStatusRunning  = "running"
+	BroadcastStatusFinished = "finished"
+	BroadcastStatusFailed   = "failed"
+)
+
+// BroadcastJob is an announcement broadcast running in the background and its progress
+type BroadcastJob struct {
+	ID         string     `json:"id"`
+	AdminID    string     `json:"adminId"`
+	Audience   string     `json:"audience"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	Status     string     `json:"status"`
+	Targeted   int        `json:"targeted"`
+	Sent       int        `json:"sent"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// NotificationDeliverer pushes a notification to an out-of-app channel (push, email)
+type NotificationDeliverer interface {
+	Deliver(ctx context.Context, notification *models.Notification) error
+}
 
 // NotificationService handles notification business logic
 type NotificationService struct {
-	repo        repository.NotificationRepository
-	redis       *cache.RedisClient
-	invalidator *cache.Invalidator
+	repo           repository.NotificationRepository
+	redis          *cache.RedisClient
+	invalidator    *cache.Invalidator
+	profileService *ProfileService
+	deliverer      NotificationDeliverer
+	prefsRepo      repository.NotificationPreferencesRepository
+
+	// sandbox skips out-of-app delivery; notifications are still stored and streamed
+	sandbox bool
 }
 
 // NewNotificationService creates a new notification service
@@ -32,11 +97,43 @@ func NewNotificationService(repo repository.NotificationRepository, redis *cache
 	}
 }
 
+// SetProfileService sets the profile service used to resolve delivery preferences
+func (s *NotificationService) SetProfileService(ps *ProfileService) {
+	s.profileService = ps
+}
+
+// SetDeliverer sets the out-of-app delivery channel for notifications
+func (s *NotificationService) SetDeliverer(d NotificationDeliverer) {
+	s.deliverer = d
+}
+
+// SetPreferencesRepository enables per-type notification preferences. Without it every
+// notification type is delivered.
+func (s *NotificationService) SetPreferencesRepository(repo repository.NotificationPreferencesRepository) {
+	s.prefsRepo = repo
+}
+
+// SetSandboxMode turns push and email delivery into a no-op that only logs
+func (s *NotificationService) SetSandboxMode(enabled bool) {
+	s.sandbox = enabled
+}
+
 // GetByUserID retrieves notifications for a user
 func (s *NotificationService) GetByUserID(ctx context.Context, userID string, unreadOnly bool, notificationType string, offset, limit int) ([]*models.Notification, int, error) {
 	return s.repo.GetByUserID(ctx, userID, unreadOnly, notificationType, offset, limit)
 }
 
+// GetByUserIDAfter retrieves a page of notifications older than cursor using keyset
+// paging, so notifications arriving while the user scrolls don't shift pages.
+// An empty cursor returns the newest page.
+func (s *NotificationService) GetByUserIDAfter(ctx context.Context, userID string, unreadOnly bool, notificationType string, cursor string, limit int) ([]*models.Notification, bool, error) {
+	after, err := decodePageCursor(cursor)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.repo.GetByUserIDAfter(ctx, userID, unreadOnly, notificationType, after, limit)
+}
+
 // CountUnread returns the count of unread notifications with caching
 func (s *NotificationService) CountUnread(ctx context.Context, userID string) (int, error) {
 	// Try cache first
@@ -72,18 +169,54 @@ func (s *NotificationService) MarkAsRead(ctx context.Context, userID string, not
 	// Invalidate count cache
 	_ = s.invalidator.InvalidateNotificationCount(ctx, userID)
 
+	s.publishUnreadCount(ctx, userID)
+
 	return nil
 }
 
-// Create creates a new notification
+// MarkReadByReference marks every unread notification about one entity (an offer,
+// trade, chat...) as read, so opening it clears its badge. Returns how many were marked.
+func (s *NotificationService) MarkReadByReference(ctx context.Context, userID string, referenceType string, referenceID string) (int, error) {
+	marked, err := s.repo.MarkReadByReference(ctx, userID, referenceType, referenceID)
+	if err != nil {
+		return 0, err
+	}
+	if marked == 0 {
+		return 0, nil
+	}
+
+	_ = s.invalidator.InvalidateNotificationCount(ctx, userID)
+	s.publishUnreadCount(ctx, userID)
+
+	return marked, nil
+}
+
+// Create creates a new notification. Types the recipient turned off are skipped, and so
+// is a repeat of an event already notified within notificationDedupWindow (same type,
+// reference and user).
 func (s *NotificationService) Create(ctx context.Context, notification *models.Notification) error {
-	notification.ID = uuid.New().String()
+	if !s.wantsNotification(ctx, notification) {
+		logger.FromContext(ctx).Debug("skipping muted notification",
+			"type", notification.Type,
+			"user_id", notification.UserID,
+		)
+		return nil
+	}
+
+	if s.isDuplicate(ctx, notification) {
+		return nil
+	}
+
+	if notification.ID == "" {
+		notification.ID = uuid.New().String()
+	}
 	notification.CreatedAt = time.Now()
 
 	fmt.Printf("[NOTIFICATION-SVC] Creating notification: id=%s user_id=%s type=%s title=%s\n",
 		notification.ID, notification.UserID, notification.Type, notification.Title)
 
 	if err := s.repo.Create(ctx, notification); err != nil {
+		s.releaseDedup(ctx, notification)
 		fmt.Printf("[NOTIFICATION-SVC] ERROR inserting into database: %v\n", err)
 		logger.FromContext(ctx).Error("failed to create notification",
 			"error", err.Error(),
@@ -99,9 +232,255 @@ func (s *NotificationService) Create(ctx context.Context, notification *models.N
 	// Invalidate count cache
 	_ = s.invalidator.InvalidateNotificationCount(ctx, notification.UserID)
 
+	s.publishNotification(ctx, notification)
+	s.deliver(ctx, notification)
+
 	return nil
 }
 
+// GetPreferences returns the user's notification preferences. Users who never changed
+// them get every type enabled.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	defaults := &models.NotificationPreferences{UserID: userID, Types: map[models.NotificationType]bool{}}
+	if s.prefsRepo == nil {
+		return defaults, nil
+	}
+
+	cacheKey := cache.NotificationPrefsKey(userID)
+	if cached, err := s.redis.Get(ctx, cacheKey); err == nil && cached != "" {
+		var prefs models.NotificationPreferences
+		if json.Unmarshal([]byte(cached), &prefs) == nil {
+			return &prefs, nil
+		}
+	}
+
+	prefs, err := s.prefsRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		prefs = defaults
+	} else if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(prefs); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), notificationPrefsCacheTTL)
+	}
+
+	return prefs, nil
+}
+
+// UpdatePreferences turns notification types on or off. Types left out keep their
+// current setting; only models.MutableNotificationTypes can be changed.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, types map[string]bool) (*models.NotificationPreferences, error) {
+	if s.prefsRepo == nil {
+		return nil, fmt.Errorf("notification preferences not configured")
+	}
+
+	errs := ValidationErrors{}
+	for name := range types {
+		if !models.NotificationType(name).IsMutable() {
+			errs["types."+name] = "can't be turned off"
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	current, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := &models.NotificationPreferences{
+		UserID:    userID,
+		Types:     make(map[models.NotificationType]bool, len(current.Types)+len(types)),
+		UpdatedAt: time.Now(),
+	}
+	for notificationType, enabled := range current.Types {
+		prefs.Types[notificationType] = enabled
+	}
+	for name, enabled := range types {
+		prefs.Types[models.NotificationType(name)] = enabled
+	}
+
+	if err := s.prefsRepo.Upsert(ctx, prefs); err != nil {
+		return nil, err
+	}
+	_ = s.redis.Del(ctx, cache.NotificationPrefsKey(userID))
+
+	return prefs, nil
+}
+
+// wantsNotification reports whether the recipient has the notification's type enabled.
+// Preferences that can't be loaded let the notification through.
+func (s *NotificationService) wantsNotification(ctx context.Context, notification *models.Notification) bool {
+	if s.prefsRepo == nil || !notification.Type.IsMutable() {
+		return true
+	}
+	prefs, err := s.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to load notification preferences",
+			"error", err.Error(),
+			"user_id", notification.UserID,
+		)
+		return true
+	}
+	return prefs.IsEnabled(notification.Type)
+}
+
+// deliver sends the notification through the out-of-app channel, holding it
+// for a digest instead when the recipient is in their quiet hours
+func (s *NotificationService) deliver(ctx context.Context, notification *models.Notification) {
+	if s.deliverer == nil {
+		return
+	}
+	if s.sandbox {
+		logger.FromContext(ctx).Debug("sandbox mode, skipping notification delivery",
+			"notification_id", notification.ID,
+			"type", notification.Type,
+			"user_id", notification.UserID,
+		)
+		return
+	}
+
+	if s.profileService != nil {
+		profile, err := s.profileService.GetByID(ctx, notification.UserID)
+		if err == nil && profile.InQuietHours(time.Now()) {
+			s.queueDigest(ctx, notification, profile)
+			return
+		}
+	}
+
+	s.flushDigest(ctx, notification.UserID)
+
+	if err := s.deliverer.Deliver(ctx, notification); err != nil {
+		logger.FromContext(ctx).Warn("failed to deliver notification",
+			"error", err.Error(),
+			"notification_id", notification.ID,
+			"user_id", notification.UserID,
+		)
+	}
+}
+
+// queueDigest records a notification suppressed during quiet hours. The first one
+// marks the user due when their quiet hours end, for FlushDueDigests to send.
+func (s *NotificationService) queueDigest(ctx context.Context, notification *models.Notification, profile *models.Profile) {
+	key := cache.NotificationDigestKey(notification.UserID)
+	_ = s.redis.LPush(ctx, key, notification.ID)
+	_ = s.redis.Expire(ctx, key, notificationDigestTTL)
+
+	if endsAt, ok := profile.QuietHoursEndAfter(time.Now()); ok {
+		if _, err := s.redis.ZAddNX(ctx, cache.NotificationDigestDueKey(), float64(endsAt.Unix()), notification.UserID); err != nil {
+			logger.FromContext(ctx).Warn("failed to schedule notification digest",
+				"error", err.Error(),
+				"user_id", notification.UserID,
+			)
+		}
+	}
+}
+
+// flushDigest delivers a single summary for notifications held during quiet hours.
+// The queue is drained in one transaction, so concurrent flushes send each held
+// notification once. Returns whether a digest was sent.
+func (s *NotificationService) flushDigest(ctx context.Context, userID string) bool {
+	queued, err := s.redis.LDrain(ctx, cache.NotificationDigestKey(userID))
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to drain notification digest",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return false
+	}
+	if len(queued) == 0 {
+		return false
+	}
+
+	digest := &models.Notification{
+		UserID:    userID,
+		Type:      models.NotificationTypeDigest,
+		Title:     "While You Were Away",
+		Body:      strPtr(fmt.Sprintf("You have %d new notifications", len(queued))),
+		CreatedAt: time.Now(),
+	}
+	if err := s.deliverer.Deliver(ctx, digest); err != nil {
+		logger.FromContext(ctx).Warn("failed to deliver notification digest",
+			"error", err.Error(),
+			"user_id", userID,
+			"count", len(queued),
+		)
+	}
+	return true
+}
+
+// FlushDueDigests sends the held digest of every user whose quiet hours have ended and
+// returns how many digests were sent. Each user is claimed with ZREM, so overlapping
+// runs on several instances send a digest once. A user whose quiet hours were moved
+// and haven't ended yet is scheduled again for the new end.
+func (s *NotificationService) FlushDueDigests(ctx context.Context) (int, error) {
+	if s.deliverer == nil || !s.redis.IsAvailable() {
+		return 0, nil
+	}
+
+	flushed := 0
+	for {
+		now := time.Now()
+		userIDs, err := s.redis.ZRangeByScoreMax(ctx, cache.NotificationDigestDueKey(), float64(now.Unix()), notificationDigestFlushBatchSize)
+		if err != nil {
+			return flushed, err
+		}
+		if len(userIDs) == 0 {
+			return flushed, nil
+		}
+
+		for _, userID := range userIDs {
+			claimed, err := s.redis.ZRem(ctx, cache.NotificationDigestDueKey(), userID)
+			if err != nil {
+				return flushed, err
+			}
+			if claimed == 0 {
+				continue
+			}
+			if s.profileService != nil {
+				profile, err := s.profileService.GetByID(ctx, userID)
+				if err == nil && profile.InQuietHours(now) {
+					if endsAt, ok := profile.QuietHoursEndAfter(now); ok {
+						_ = s.redis.ZAdd(ctx, cache.NotificationDigestDueKey(), float64(endsAt.Unix()), userID)
+					}
+					continue
+				}
+			}
+			if s.flushDigest(ctx, userID) {
+				flushed++
+			}
+		}
+	}
+}
+
+// RunDigestFlusher sends digests whose quiet hours have ended every interval until ctx
+// is cancelled
+func (s *NotificationService) RunDigestFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.FlushDueDigests(ctx)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to flush notification digests",
+					"error", err.Error(),
+					"flushed", n,
+				)
+				continue
+			}
+			if n > 0 {
+				logger.FromContext(ctx).Debug("flushed notification digests", "users", n)
+			}
+		}
+	}
+}
+
 // NotifyOfferReceived notifies a seller of a new offer
 func (s *NotificationService) NotifyOfferReceived(ctx context.Context, userID string, offerID string, itemName string) error {
 	refType := "offer"
@@ -116,6 +495,21 @@ func (s *NotificationService) NotifyOfferReceived(ctx context.Context, userID st
 	return s.Create(ctx, notification)
 }
 
+// NotifyOfferCountered tells a requester the seller/provider answered their offer with
+// a counteroffer; the reference is the counter, which they can accept or reject
+func (s *NotificationService) NotifyOfferCountered(ctx context.Context, userID string, counterOfferID string, itemName string) error {
+	refType := "offer"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeOfferCountered,
+		Title:         "Counteroffer Received",
+		Body:          strPtr(fmt.Sprintf("The seller made a counteroffer on %s", itemName)),
+		ReferenceType: &refType,
+		ReferenceID:   &counterOfferID,
+	}
+	return s.Create(ctx, notification)
+}
+
 // NotifyOfferAccepted notifies a requester their offer was accepted
 func (s *NotificationService) NotifyOfferAccepted(ctx context.Context, userID string, offerID string, itemName string) error {
 	refType := "offer"
@@ -144,6 +538,68 @@ func (s *NotificationService) NotifyOfferRejected(ctx context.Context, userID st
 	return s.Create(ctx, notification)
 }
 
+// NotifyOfferListingChanged tells a requester their offer couldn't be accepted
+// because the listing was edited to a different item after they made it
+func (s *NotificationService) NotifyOfferListingChanged(ctx context.Context, userID string, offerID string, itemName string) error {
+	refType := "offer"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeOfferListingChanged,
+		Title:         "Listing Changed",
+		Body:          strPtr(fmt.Sprintf("The listing you offered on was changed to %s. Review it and make a new offer if you still want it", itemName)),
+		ReferenceType: &refType,
+		ReferenceID:   &offerID,
+	}
+	return s.Create(ctx, notification)
+}
+
+// NotifyListingReserved notifies a buyer that a seller is holding a listing for them.
+// Each reservation (by its end time) notifies, so re-reserving the listing does too.
+func (s *NotificationService) NotifyListingReserved(ctx context.Context, userID string, listingID string, itemName string, until time.Time) error {
+	refType := "listing"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeListingReserved,
+		Title:         "Item Reserved For You",
+		Body:          strPtr(fmt.Sprintf("%s is reserved for you until %s", itemName, until.UTC().Format("Jan 2 15:04 MST"))),
+		ReferenceType: &refType,
+		ReferenceID:   &listingID,
+		EventID:       strconv.FormatInt(until.Unix(), 10),
+	}
+	return s.Create(ctx, notification)
+}
+
+// NotifyListingsExpiring warns each listing's seller that it is about to expire. The
+// metadata carries a renew action pointing at the listing's refresh endpoint. Returns
+// how many notifications were created.
+func (s *NotificationService) NotifyListingsExpiring(ctx context.Context, listings []*models.Listing) int {
+	refType := "listing"
+	notifications := make([]*models.Notification, 0, len(listings))
+	for _, listing := range listings {
+		metadata, err := json.Marshal(map[string]any{
+			"expiresAt": listing.ExpiresAt,
+			"renew": map[string]string{
+				"method": "POST",
+				"path":   fmt.Sprintf("/api/v1/listings/%s/refresh", listing.ID),
+			},
+		})
+		if err != nil {
+			continue
+		}
+		listingID := listing.ID
+		notifications = append(notifications, &models.Notification{
+			UserID:        listing.SellerID,
+			Type:          models.NotificationTypeListingExpiring,
+			Title:         "Listing Expiring Soon",
+			Body:          strPtr(fmt.Sprintf("%s expires on %s. Renew it to keep it up", listing.Name, listing.ExpiresAt.UTC().Format("Jan 2 15:04 MST"))),
+			ReferenceType: &refType,
+			ReferenceID:   &listingID,
+			Metadata:      metadata,
+		})
+	}
+	return s.CreateBatch(ctx, notifications)
+}
+
 // NotifyTradeCompleted notifies a user the trade was completed
 func (s *NotificationService) NotifyTradeCompleted(ctx context.Context, userID string, tradeID string, itemName string) error {
 	refType := "trade"
@@ -228,6 +684,72 @@ func (s *NotificationService) NotifyServiceRunCancelled(ctx context.Context, use
 	return s.Create(ctx, notification)
 }
 
+// NotifyServiceRunDisputed notifies a participant that the other side disputed the run
+func (s *NotificationService) NotifyServiceRunDisputed(ctx context.Context, userID string, serviceRunID string, serviceName string) error {
+	refType := "service_run"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeServiceRunDisputed,
+		Title:         "Service Run Disputed",
+		Body:          strPtr(fmt.Sprintf("The service run for %s has been disputed and is on hold until an admin reviews it.", serviceName)),
+		ReferenceType: &refType,
+		ReferenceID:   &serviceRunID,
+	}
+	return s.Create(ctx, notification)
+}
+
+// NotifyServiceRunDisputeResolved tells a participant how an admin settled the run's dispute
+func (s *NotificationService) NotifyServiceRunDisputeResolved(ctx context.Context, userID string, serviceRunID string, serviceName string, outcome string) error {
+	refType := "service_run"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeDisputeResolved,
+		Title:         "Dispute Resolved",
+		Body:          strPtr(fmt.Sprintf("The dispute over %s was resolved. The service run is now %s.", serviceName, outcome)),
+		ReferenceType: &refType,
+		ReferenceID:   &serviceRunID,
+	}
+	return s.Create(ctx, notification)
+}
+
+// NotifyAdminsDisputeOpened alerts every admin that a dispute is waiting for review
+func (s *NotificationService) NotifyAdminsDisputeOpened(ctx context.Context, dispute *models.Dispute, subject string) {
+	if s.profileService == nil {
+		return
+	}
+
+	refType := "dispute"
+	afterID := ""
+	for {
+		adminIDs, err := s.profileService.ListIDsByAudience(ctx, repository.AudienceAdmins, afterID, broadcastBatchSize)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to list admins for dispute alert",
+				"error", err.Error(),
+				"dispute_id", dispute.ID,
+			)
+			return
+		}
+
+		notifications := make([]*models.Notification, 0, len(adminIDs))
+		for _, adminID := range adminIDs {
+			notifications = append(notifications, &models.Notification{
+				UserID:        adminID,
+				Type:          models.NotificationTypeDisputeOpened,
+				Title:         "New Dispute",
+				Body:          strPtr(fmt.Sprintf("A %s dispute over %s needs review.", strings.ReplaceAll(dispute.TargetType, "_", " "), subject)),
+				ReferenceType: &refType,
+				ReferenceID:   &dispute.ID,
+			})
+		}
+		s.CreateBatch(ctx, notifications)
+
+		if len(adminIDs) < broadcastBatchSize {
+			return
+		}
+		afterID = adminIDs[len(adminIDs)-1]
+	}
+}
+
 // NotifyRatingReceived notifies a user they received a rating
 func (s *NotificationService) NotifyRatingReceived(ctx context.Context, userID string, transactionID string, stars int) error {
 	refType := "transaction"
@@ -242,6 +764,476 @@ func (s *NotificationService) NotifyRatingReceived(ctx context.Context, userID s
 	return s.Create(ctx, notification)
 }
 
+// NotifyPremiumGifted notifies a user that another user gifted them premium. giftID
+// (the checkout session) keeps a second gift from the same gifter from being deduplicated.
+func (s *NotificationService) NotifyPremiumGifted(ctx context.Context, userID string, gifterID string, gifterName string, giftID string) error {
+	refType := "profile"
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypePremiumGifted,
+		Title:         "You Received Premium",
+		Body:          strPtr(fmt.Sprintf("%s gifted you LootStash Premium", gifterName)),
+		ReferenceType: &refType,
+		ReferenceID:   &gifterID,
+		EventID:       giftID,
+	}
+	return s.Create(ctx, notification)
+}
+
+// welcomeLinks are the first steps suggested to new users in the welcome notification
+var welcomeLinks = []map[string]string{
+	{"label": "Create a listing", "path": "/listings/new"},
+	{"label": "Set up a wishlist", "path": "/wishlist"},
+}
+
+// SendWelcome greets a new user and points them at their first listing and wishlist
+func (s *NotificationService) SendWelcome(ctx context.Context, userID string) error {
+	metadata, err := json.Marshal(map[string]any{"links": welcomeLinks})
+	if err != nil {
+		return err
+	}
+	notification := &models.Notification{
+		UserID:   userID,
+		Type:     models.NotificationTypeWelcome,
+		Title:    "Welcome to LootStash",
+		Body:     strPtr("List an item you want to trade, or set up a wishlist to get notified when what you're looking for shows up."),
+		Metadata: metadata,
+	}
+	return s.Create(ctx, notification)
+}
+
+// StartBroadcast queues an announcement to every user in the audience and returns the
+// job right away; the notifications are sent in the background and the job's progress
+// can be read with GetBroadcast. Repeating a request with the same idempotency key
+// (or, without one, the same audience, title and body) within the idempotency window
+// returns the first job instead of broadcasting again.
+func (s *NotificationService) StartBroadcast(ctx context.Context, adminID string, audience string, title string, body string, idempotencyKey string) (*BroadcastJob, error) {
+	if s.profileService == nil {
+		return nil, ErrForbidden
+	}
+
+	isAdmin, err := s.profileService.IsAdmin(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, ErrForbidden
+	}
+
+	switch audience {
+	case repository.AudienceAll, repository.AudiencePremium, repository.AudienceActiveSellers:
+	default:
+		return nil, ErrInvalidAudience
+	}
+
+	if idempotencyKey == "" {
+		sum := sha256.Sum256([]byte(audience + "\x00" + title + "\x00" + body))
+		idempotencyKey = hex.EncodeToString(sum[:])
+	}
+
+	return createIdempotent(ctx, s.redis, "broadcast", adminID, idempotencyKey,
+		func() (*BroadcastJob, error) {
+			job := &BroadcastJob{
+				ID:        uuid.New().String(),
+				AdminID:   adminID,
+				Audience:  audience,
+				Title:     title,
+				Body:      body,
+				Status:    BroadcastStatusQueued,
+				CreatedAt: time.Now(),
+			}
+			s.saveBroadcastJob(ctx, job)
+
+			run := *job
+			go func() {
+				_ = s.runBroadcast(context.Background(), &run)
+			}()
+			return job, nil
+		},
+		func(job *BroadcastJob) string { return job.ID },
+		func(id string) (*BroadcastJob, error) { return s.GetBroadcast(ctx, adminID, id) },
+	)
+}
+
+// GetBroadcast returns a broadcast job started by any admin. Jobs are kept in Redis for
+// broadcastJobTTL, so without Redis every lookup is ErrNotFound.
+func (s *NotificationService) GetBroadcast(ctx context.Context, adminID string, id string) (*BroadcastJob, error) {
+	if s.profileService == nil {
+		return nil, ErrForbidden
+	}
+	isAdmin, err := s.profileService.IsAdmin(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, ErrForbidden
+	}
+
+	data, err := s.redis.Get(ctx, cache.BroadcastJobKey(id))
+	if err != nil || data == "" {
+		return nil, ErrNotFound
+	}
+	var job BroadcastJob
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+// ToBroadcastResponse converts a broadcast job to its response DTO
+func (s *NotificationService) ToBroadcastResponse(job *BroadcastJob) *dto.BroadcastJobResponse {
+	return &dto.BroadcastJobResponse{
+		ID:         job.ID,
+		Audience:   job.Audience,
+		Title:      job.Title,
+		Status:     job.Status,
+		Targeted:   job.Targeted,
+		Sent:       job.Sent,
+		Failed:     job.Failed,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
+
+// saveBroadcastJob stores the job's current state for GetBroadcast
+func (s *NotificationService) saveBroadcastJob(ctx context.Context, job *BroadcastJob) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+	_ = s.redis.Set(ctx, cache.BroadcastJobKey(job.ID), string(data), broadcastJobTTL)
+}
+
+// runBroadcast sends the job's announcement to every user in its audience, saving
+// progress after each batch. Recipients are processed in batches; a failed batch falls
+// back to per-user inserts so one bad row doesn't drop the rest.
+func (s *NotificationService) runBroadcast(ctx context.Context, job *BroadcastJob) error {
+	log := logger.FromContext(ctx)
+	job.Status = BroadcastStatusRunning
+	s.saveBroadcastJob(ctx, job)
+
+	err := s.sendBroadcast(ctx, job)
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		job.Status = BroadcastStatusFailed
+		job.Error = err.Error()
+		s.saveBroadcastJob(ctx, job)
+		log.Error("announcement broadcast failed",
+			"error", err.Error(),
+			"broadcast_id", job.ID,
+			"audience", job.Audience,
+			"sent", job.Sent,
+		)
+		return err
+	}
+
+	job.Status = BroadcastStatusFinished
+	s.saveBroadcastJob(ctx, job)
+	log.Info("announcement broadcast finished",
+		"broadcast_id", job.ID,
+		"admin_id", job.AdminID,
+		"audience", job.Audience,
+		"targeted", job.Targeted,
+		"sent", job.Sent,
+		"failed", job.Failed,
+	)
+	return nil
+}
+
+// sendBroadcast walks the audience in batches and adds each batch's counts to the job
+func (s *NotificationService) sendBroadcast(ctx context.Context, job *BroadcastJob) error {
+	afterID := ""
+	for {
+		userIDs, err := s.profileService.ListIDsByAudience(ctx, job.Audience, afterID, broadcastBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		sent := s.broadcastBatch(ctx, userIDs, job.Title, job.Body)
+		job.Targeted += len(userIDs)
+		job.Sent += sent
+		job.Failed += len(userIDs) - sent
+
+		if len(userIDs) < broadcastBatchSize {
+			return nil
+		}
+		afterID = userIDs[len(userIDs)-1]
+		s.saveBroadcastJob(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(broadcastBatchDelay):
+		}
+	}
+}
+
+// broadcastBatch inserts an announcement for each user and returns how many succeeded
+func (s *NotificationService) broadcastBatch(ctx context.Context, userIDs []string, title string, body string) int {
+	now := time.Now()
+	notifications := make([]*models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, &models.Notification{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			Type:      models.NotificationTypeAnnouncement,
+			Title:     title,
+			Body:      strPtr(body),
+			CreatedAt: now,
+		})
+	}
+
+	created := s.insertBatch(ctx, notifications)
+	for _, notification := range created {
+		_ = s.invalidator.InvalidateNotificationCount(ctx, notification.UserID)
+		s.publishNotification(ctx, notification)
+	}
+
+	return len(created)
+}
+
+// isDuplicate claims the notification's dedup key and reports whether the same event
+// was already notified within notificationDedupWindow
+func (s *NotificationService) isDuplicate(ctx context.Context, notification *models.Notification) bool {
+	dedupKey := notification.DedupKey()
+	if dedupKey == "" {
+		return false
+	}
+	first, err := s.redis.SetNX(ctx, cache.NotificationDedupKey(dedupKey), "1", notificationDedupWindow)
+	if err != nil || first {
+		return false
+	}
+	logger.FromContext(ctx).Debug("skipping duplicate notification",
+		"type", notification.Type,
+		"reference_id", notification.GetReferenceID(),
+		"user_id", notification.UserID,
+	)
+	return true
+}
+
+// releaseDedup drops the dedup claim of a notification that couldn't be stored, so a
+// retry goes through since nothing was sent
+func (s *NotificationService) releaseDedup(ctx context.Context, notification *models.Notification) {
+	if dedupKey := notification.DedupKey(); dedupKey != "" {
+		_ = s.redis.Del(ctx, cache.NotificationDedupKey(dedupKey))
+	}
+}
+
+// CreateBatch creates several notifications with a single insert and delivers each
+// like Create, skipping types their recipients turned off and repeats of events already
+// notified. Returns how many were created.
+func (s *NotificationService) CreateBatch(ctx context.Context, notifications []*models.Notification) int {
+	wanted := make([]*models.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if s.wantsNotification(ctx, notification) && !s.isDuplicate(ctx, notification) {
+			wanted = append(wanted, notification)
+		}
+	}
+	return s.createBatch(ctx, wanted)
+}
+
+// createBatch inserts and delivers notifications the recipients want
+func (s *NotificationService) createBatch(ctx context.Context, notifications []*models.Notification) int {
+	if len(notifications) == 0 {
+		return 0
+	}
+
+	now := time.Now()
+	for _, notification := range notifications {
+		if notification.ID == "" {
+			notification.ID = uuid.New().String()
+		}
+		notification.CreatedAt = now
+	}
+
+	created := s.insertBatch(ctx, notifications)
+	for _, notification := range created {
+		_ = s.invalidator.InvalidateNotificationCount(ctx, notification.UserID)
+		s.publishNotification(ctx, notification)
+		s.deliver(ctx, notification)
+	}
+
+	return len(created)
+}
+
+// CreateBatched creates notifications like CreateBatch, but folds each one into the
+// recipient's unread notification of the same type and reference type created within
+// notificationBatchWindow. The folded notification's body then counts them ("3 new items
+// match your wishlist"). Only the first of a batch is inserted, so the unread count changes
+// and its cache is invalidated once per batch. Without Redis it behaves like CreateBatch.
+// Returns how many notifications were inserted.
+func (s *NotificationService) CreateBatched(ctx context.Context, notifications []*models.Notification) int {
+	var starters []*models.Notification
+	// Batches started by this call, whose notifications aren't inserted yet
+	started := make(map[string]*models.Notification)
+
+	for _, notification := range notifications {
+		if !s.wantsNotification(ctx, notification) {
+			continue
+		}
+
+		key, countKey := notificationBatchKeys(notification)
+		if first, ok := started[key]; ok {
+			if count, err := s.redis.Incr(ctx, countKey); err == nil {
+				first.Body = strPtr(batchSummary(first.Type, int(count)))
+				first.ReferenceID = nil
+				first.Metadata = nil
+				continue
+			}
+		} else if s.joinBatch(ctx, notification, key, countKey) {
+			continue
+		} else {
+			notification.ID = uuid.New().String()
+			claimed, err := s.redis.SetNX(ctx, key, notification.ID, notificationBatchWindow)
+			if err == nil && claimed {
+				_ = s.redis.Set(ctx, countKey, 1, notificationBatchWindow)
+				started[key] = notification
+			} else {
+				// Lost a race for the batch or Redis is down; send it on its own
+				notification.ID = ""
+			}
+		}
+		starters = append(starters, notification)
+	}
+
+	return s.createBatch(ctx, starters)
+}
+
+// notificationBatchKeys returns the keys tracking the batch a notification belongs to
+func notificationBatchKeys(notification *models.Notification) (string, string) {
+	userID, notificationType, refType := notification.UserID, string(notification.Type), notification.GetReferenceType()
+	return cache.NotificationBatchKey(userID, notificationType, refType),
+		cache.NotificationBatchCountKey(userID, notificationType, refType)
+}
+
+// joinBatch folds the notification into an open batch. Returns false when there is no
+// open batch or its notification has been read, so the caller starts a new one.
+func (s *NotificationService) joinBatch(ctx context.Context, notification *models.Notification, key, countKey string) bool {
+	batchID, err := s.redis.Get(ctx, key)
+	if err != nil || batchID == "" {
+		return false
+	}
+	count, err := s.redis.Incr(ctx, countKey)
+	if err != nil {
+		return false
+	}
+
+	updated, err := s.repo.SummarizeUnread(ctx, batchID, notification.UserID, batchSummary(notification.Type, int(count)))
+	if err != nil || !updated {
+		_ = s.redis.Del(ctx, key, countKey)
+		return false
+	}
+	return true
+}
+
+// batchSummary is the body of a notification standing for count similar ones
+func batchSummary(notificationType models.NotificationType, count int) string {
+	switch notificationType {
+	case models.NotificationTypeWishlistMatch:
+		return fmt.Sprintf("%d new items match your wishlist", count)
+	case models.NotificationTypeItemWatch:
+		return fmt.Sprintf("%d watched items were just listed", count)
+	case models.NotificationTypeNewMessage:
+		return fmt.Sprintf("%d new messages", count)
+	case models.NotificationTypeTradeRequestReceived:
+		return fmt.Sprintf("You received %d new offers", count)
+	default:
+		return fmt.Sprintf("%d new notifications", count)
+	}
+}
+
+// insertBatch inserts notifications in one statement, falling back to one insert
+// per notification so a single bad row doesn't drop the rest. Returns those inserted.
+func (s *NotificationService) insertBatch(ctx context.Context, notifications []*models.Notification) []*models.Notification {
+	if err := s.repo.CreateBatch(ctx, notifications); err == nil {
+		return notifications
+	}
+
+	created := make([]*models.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if err := s.repo.Create(ctx, notification); err != nil {
+			s.releaseDedup(ctx, notification)
+			logger.FromContext(ctx).Warn("failed to create notification",
+				"error", err.Error(),
+				"type", notification.Type,
+				"user_id", notification.UserID,
+			)
+			continue
+		}
+		created = append(created, notification)
+	}
+	return created
+}
+
+// Subscribe opens a live event stream scoped to the user's notifications.
+// Returns ErrStreamUnavailable when Redis isn't configured so clients keep polling.
+func (s *NotificationService) Subscribe(ctx context.Context, userID string) (*cache.Subscription, error) {
+	sub, err := s.redis.Subscribe(ctx, cache.NotificationStreamChannel(userID))
+	if errors.Is(err, cache.ErrUnavailable) {
+		return nil, ErrStreamUnavailable
+	}
+	return sub, err
+}
+
+// publishNotification pushes a new notification and the updated unread count to
+// the recipient's stream. Skipped when the recipient has no open stream.
+func (s *NotificationService) publishNotification(ctx context.Context, notification *models.Notification) {
+	channel := cache.NotificationStreamChannel(notification.UserID)
+	if !s.redis.HasSubscribers(ctx, channel) {
+		return
+	}
+
+	s.publish(ctx, channel, dto.NotificationStreamEvent{
+		Type:         StreamEventNotification,
+		Notification: s.ToResponse(notification),
+	})
+	s.publishUnreadCount(ctx, notification.UserID)
+}
+
+// publishUnreadCount pushes the user's current unread count to their stream
+func (s *NotificationService) publishUnreadCount(ctx context.Context, userID string) {
+	channel := cache.NotificationStreamChannel(userID)
+	if !s.redis.HasSubscribers(ctx, channel) {
+		return
+	}
+
+	count, err := s.CountUnread(ctx, userID)
+	if err != nil {
+		return
+	}
+	s.publish(ctx, channel, dto.NotificationStreamEvent{
+		Type:        StreamEventUnreadCount,
+		UnreadCount: &count,
+	})
+}
+
+func (s *NotificationService) publish(ctx context.Context, channel string, event dto.NotificationStreamEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Publish(ctx, channel, string(data)); err != nil {
+		logger.FromContext(ctx).Warn("failed to publish notification event",
+			"error", err.Error(),
+			"channel", channel,
+		)
+	}
+}
+
+// ToPreferencesResponse lists every type the user can turn off with its current setting
+func (s *NotificationService) ToPreferencesResponse(prefs *models.NotificationPreferences) *dto.NotificationPreferencesResponse {
+	types := make(map[string]bool, len(models.MutableNotificationTypes))
+	for _, notificationType := range models.MutableNotificationTypes {
+		types[string(notificationType)] = prefs.IsEnabled(notificationType)
+	}
+	return &dto.NotificationPreferencesResponse{Types: types}
+}
+
 // ToResponse converts a notification model to a DTO response
 func (s *NotificationService) ToResponse(notification *models.Notification) *dto.NotificationResponse {
 	return &dto.NotificationResponse{
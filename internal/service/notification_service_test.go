@@ -2,17 +2,25 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ---------------------------------------------------------------------------
@@ -117,6 +125,60 @@ func TestMarkAsRead_InvalidatesCache(t *testing.T) {
 	notifRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// MarkReadByReference
+// ---------------------------------------------------------------------------
+
+func TestMarkReadByReference_InvalidatesCount(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	cacheKey := cache.NotificationCountKey(testUserID)
+	mr.Set(cacheKey, "5")
+
+	notifRepo.On("MarkReadByReference", ctx, testUserID, "offer", testOfferID).Return(3, nil)
+
+	marked, err := svc.MarkReadByReference(ctx, testUserID, "offer", testOfferID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, marked)
+	assert.False(t, mr.Exists(cacheKey))
+	notifRepo.AssertExpectations(t)
+}
+
+func TestMarkReadByReference_NothingMarkedKeepsCount(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	cacheKey := cache.NotificationCountKey(testUserID)
+	mr.Set(cacheKey, "5")
+
+	notifRepo.On("MarkReadByReference", ctx, testUserID, "trade", testTradeID).Return(0, nil)
+
+	marked, err := svc.MarkReadByReference(ctx, testUserID, "trade", testTradeID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, marked)
+	assert.True(t, mr.Exists(cacheKey))
+}
+
+func TestMarkReadByReference_RepoError(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewNotificationService(notifRepo, newTestRedis())
+	ctx := context.Background()
+
+	notifRepo.On("MarkReadByReference", ctx, testUserID, "chat", testChatID).Return(0, errors.New("db down"))
+
+	marked, err := svc.MarkReadByReference(ctx, testUserID, "chat", testChatID)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, marked)
+}
+
 // ---------------------------------------------------------------------------
 // Create
 // ---------------------------------------------------------------------------
@@ -182,6 +244,673 @@ func TestNotificationCreate_InvalidatesCountCache(t *testing.T) {
 	notifRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// Preferences
+// ---------------------------------------------------------------------------
+
+func newPreferencesTestService(t *testing.T) (*NotificationService, *mocks.MockNotificationRepository, *mocks.MockNotificationPreferencesRepository) {
+	t.Helper()
+	notifRepo := new(mocks.MockNotificationRepository)
+	prefsRepo := new(mocks.MockNotificationPreferencesRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	svc.SetPreferencesRepository(prefsRepo)
+	return svc, notifRepo, prefsRepo
+}
+
+func TestCreate_SkipsMutedType(t *testing.T) {
+	svc, notifRepo, prefsRepo := newPreferencesTestService(t)
+	ctx := context.Background()
+
+	prefsRepo.On("GetByUserID", mock.Anything, testBuyerID).Return(&models.NotificationPreferences{
+		UserID: testBuyerID,
+		Types:  map[models.NotificationType]bool{models.NotificationTypeWishlistMatch: false},
+	}, nil).Once()
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	muted := &models.Notification{UserID: testBuyerID, Type: models.NotificationTypeWishlistMatch, Title: "Wishlist Match Found"}
+	require.NoError(t, svc.Create(ctx, muted))
+	require.NoError(t, svc.NotifyOfferAccepted(ctx, testBuyerID, testOfferID, "Shako"))
+	require.NoError(t, svc.NotifyPremiumGifted(ctx, testBuyerID, testSellerID, "seller", "cs_gift_1"))
+
+	// Only the trade and premium notifications are stored; preferences load once, then come from the cache
+	notifRepo.AssertNumberOfCalls(t, "Create", 2)
+	prefsRepo.AssertExpectations(t)
+}
+
+func TestCreate_NoPreferencesRowEnablesEverything(t *testing.T) {
+	svc, notifRepo, prefsRepo := newPreferencesTestService(t)
+	ctx := context.Background()
+
+	prefsRepo.On("GetByUserID", mock.Anything, testBuyerID).Return(nil, sql.ErrNoRows)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	notification := &models.Notification{UserID: testBuyerID, Type: models.NotificationTypeWishlistMatch, Title: "Wishlist Match Found"}
+	require.NoError(t, svc.Create(ctx, notification))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCreateBatch_SkipsMutedRecipients(t *testing.T) {
+	svc, notifRepo, prefsRepo := newPreferencesTestService(t)
+	ctx := context.Background()
+
+	prefsRepo.On("GetByUserID", mock.Anything, testBuyerID).Return(&models.NotificationPreferences{
+		UserID: testBuyerID,
+		Types:  map[models.NotificationType]bool{models.NotificationTypeItemWatch: false},
+	}, nil)
+	prefsRepo.On("GetByUserID", mock.Anything, testUserID).Return(nil, sql.ErrNoRows)
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 1 && ns[0].UserID == testUserID
+	})).Return(nil)
+
+	sent := svc.CreateBatch(ctx, []*models.Notification{
+		{UserID: testBuyerID, Type: models.NotificationTypeItemWatch, Title: "Watched Item Listed"},
+		{UserID: testUserID, Type: models.NotificationTypeItemWatch, Title: "Watched Item Listed"},
+	})
+
+	assert.Equal(t, 1, sent)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_MergesAndInvalidatesCache(t *testing.T) {
+	svc, _, prefsRepo := newPreferencesTestService(t)
+	ctx := context.Background()
+
+	prefsRepo.On("GetByUserID", mock.Anything, testBuyerID).Return(&models.NotificationPreferences{
+		UserID: testBuyerID,
+		Types:  map[models.NotificationType]bool{models.NotificationTypeNewMessage: false},
+	}, nil).Once()
+	prefsRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*models.NotificationPreferences")).Return(nil)
+
+	_, err := svc.GetPreferences(ctx, testBuyerID)
+	require.NoError(t, err)
+
+	prefs, err := svc.UpdatePreferences(ctx, testBuyerID, map[string]bool{"wishlist_match": false})
+	require.NoError(t, err)
+	assert.False(t, prefs.IsEnabled(models.NotificationTypeWishlistMatch))
+	assert.False(t, prefs.IsEnabled(models.NotificationTypeNewMessage))
+	assert.True(t, prefs.IsEnabled(models.NotificationTypeTradeRequestAccepted))
+
+	// The stale cached copy is gone, so the next read goes back to the repository
+	prefsRepo.On("GetByUserID", mock.Anything, testBuyerID).Return(prefs, nil).Once()
+	got, err := svc.GetPreferences(ctx, testBuyerID)
+	require.NoError(t, err)
+	assert.False(t, got.IsEnabled(models.NotificationTypeWishlistMatch))
+	prefsRepo.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_RejectsUnmutableTypes(t *testing.T) {
+	svc, _, prefsRepo := newPreferencesTestService(t)
+
+	_, err := svc.UpdatePreferences(context.Background(), testBuyerID, map[string]bool{
+		"premium_gifted": false,
+		"not_a_type":     false,
+	})
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "types.premium_gifted")
+	assert.Contains(t, errs, "types.not_a_type")
+	prefsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Batched notifications
+// ---------------------------------------------------------------------------
+
+func wishlistMatchFor(userID string, listingID string) *models.Notification {
+	refType := "listing"
+	return &models.Notification{
+		UserID:        userID,
+		Type:          models.NotificationTypeWishlistMatch,
+		Title:         "Wishlist Match Found",
+		ReferenceType: &refType,
+		ReferenceID:   &listingID,
+		Metadata:      json.RawMessage(`{"listingId":"` + listingID + `"}`),
+	}
+}
+
+func TestCreateBatched_FoldsRepeatsIntoOneNotification(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	var firstID string
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		firstID = ns[0].ID
+		return len(ns) == 1
+	})).Return(nil).Once()
+	notifRepo.On("SummarizeUnread", mock.Anything, mock.Anything, testBuyerID, "2 new items match your wishlist").Return(true, nil).Once()
+	notifRepo.On("SummarizeUnread", mock.Anything, mock.Anything, testBuyerID, "3 new items match your wishlist").Return(true, nil).Once()
+
+	require.NoError(t, mr.Set(cache.NotificationCountKey(testBuyerID), "4"))
+	assert.Equal(t, 1, svc.CreateBatched(ctx, []*models.Notification{wishlistMatchFor(testBuyerID, "listing-1")}))
+	assert.False(t, mr.Exists(cache.NotificationCountKey(testBuyerID)))
+
+	// Later matches only rewrite the first notification; the unread count doesn't change
+	require.NoError(t, mr.Set(cache.NotificationCountKey(testBuyerID), "5"))
+	assert.Equal(t, 0, svc.CreateBatched(ctx, []*models.Notification{wishlistMatchFor(testBuyerID, "listing-2")}))
+	assert.Equal(t, 0, svc.CreateBatched(ctx, []*models.Notification{wishlistMatchFor(testBuyerID, "listing-3")}))
+	assert.True(t, mr.Exists(cache.NotificationCountKey(testBuyerID)))
+
+	notifRepo.AssertNumberOfCalls(t, "CreateBatch", 1)
+	notifRepo.AssertCalled(t, "SummarizeUnread", mock.Anything, firstID, testBuyerID, "3 new items match your wishlist")
+}
+
+func TestCreateBatched_RepeatsInOneCallShareANotification(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 2 &&
+			ns[0].UserID == testBuyerID && ns[0].GetBody() == "2 new items match your wishlist" &&
+			ns[0].ReferenceID == nil && ns[0].Metadata == nil &&
+			ns[1].UserID == testUserID && ns[1].Metadata != nil
+	})).Return(nil).Once()
+
+	sent := svc.CreateBatched(ctx, []*models.Notification{
+		wishlistMatchFor(testBuyerID, "listing-1"),
+		wishlistMatchFor(testUserID, "listing-1"),
+		wishlistMatchFor(testBuyerID, "listing-2"),
+	})
+
+	assert.Equal(t, 2, sent)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestCreateBatched_ReadNotificationStartsNewBatch(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Twice()
+	notifRepo.On("SummarizeUnread", mock.Anything, mock.Anything, testBuyerID, mock.Anything).Return(false, nil).Once()
+
+	assert.Equal(t, 1, svc.CreateBatched(ctx, []*models.Notification{wishlistMatchFor(testBuyerID, "listing-1")}))
+	assert.Equal(t, 1, svc.CreateBatched(ctx, []*models.Notification{wishlistMatchFor(testBuyerID, "listing-2")}))
+
+	notifRepo.AssertExpectations(t)
+}
+
+func TestCreateBatched_WithoutRedisSendsEachNotification(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewNotificationService(notifRepo, nil)
+	ctx := context.Background()
+
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 2
+	})).Return(nil).Once()
+
+	sent := svc.CreateBatched(ctx, []*models.Notification{
+		wishlistMatchFor(testBuyerID, "listing-1"),
+		wishlistMatchFor(testBuyerID, "listing-2"),
+	})
+
+	assert.Equal(t, 2, sent)
+	notifRepo.AssertNotCalled(t, "SummarizeUnread", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// Dedup
+// ---------------------------------------------------------------------------
+
+func TestCreate_SkipsDuplicateEvent(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	// A retried offer create notifies the seller again for the same offer
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, testOfferID, "Shako"))
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, testOfferID, "Shako"))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCreate_DistinctEventsNotDeduplicated(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, "offer-1", "Shako"))
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, "offer-2", "Shako"))
+	// Same offer, different event type
+	assert.NoError(t, svc.NotifyOfferAccepted(ctx, testBuyerID, "offer-1", "Shako"))
+	// Same offer and type, different recipient
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testUserID, "offer-1", "Shako"))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 4)
+}
+
+func TestCreate_NewMessagesNotDeduplicated(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	assert.NoError(t, svc.NotifyNewMessage(ctx, testBuyerID, testChatID, "seller"))
+	assert.NoError(t, svc.NotifyNewMessage(ctx, testBuyerID, testChatID, "seller"))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestCreate_FailedInsertAllowsRetry(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(fmt.Errorf("db down")).Once()
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil).Once()
+
+	assert.Error(t, svc.NotifyOfferReceived(ctx, testSellerID, testOfferID, "Shako"))
+	assert.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, testOfferID, "Shako"))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 2)
+	dedupKey := cache.NotificationDedupKey(fmt.Sprintf("%s:%s:%s", models.NotificationTypeTradeRequestReceived, testOfferID, testSellerID))
+	assert.True(t, mr.Exists(dedupKey))
+}
+
+func TestCreate_RepeatEventsOnSameReferenceNotify(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	// Two gifts from the same gifter, and a retried delivery of the second
+	assert.NoError(t, svc.NotifyPremiumGifted(ctx, testBuyerID, testSellerID, "seller", "cs_gift_1"))
+	assert.NoError(t, svc.NotifyPremiumGifted(ctx, testBuyerID, testSellerID, "seller", "cs_gift_2"))
+	assert.NoError(t, svc.NotifyPremiumGifted(ctx, testBuyerID, testSellerID, "seller", "cs_gift_2"))
+
+	// The same listing reserved again for the same buyer
+	until := time.Now().Add(time.Hour)
+	assert.NoError(t, svc.NotifyListingReserved(ctx, testBuyerID, testListingID, "Shako", until))
+	assert.NoError(t, svc.NotifyListingReserved(ctx, testBuyerID, testListingID, "Shako", until.Add(time.Hour)))
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 4)
+}
+
+func TestCreateBatch_SkipsDuplicateEvent(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+	notifRepo.On("CreateBatch", ctx, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 1 && ns[0].UserID == testUserID
+	})).Return(nil)
+
+	require.NoError(t, svc.NotifyOfferReceived(ctx, testSellerID, testOfferID, "Shako"))
+
+	refType := "offer"
+	offerID := testOfferID
+	sent := svc.CreateBatch(ctx, []*models.Notification{
+		{UserID: testSellerID, Type: models.NotificationTypeTradeRequestReceived, Title: "New Offer", ReferenceType: &refType, ReferenceID: &offerID},
+		{UserID: testUserID, Type: models.NotificationTypeTradeRequestReceived, Title: "New Offer", ReferenceType: &refType, ReferenceID: &offerID},
+	})
+
+	assert.Equal(t, 1, sent)
+	notifRepo.AssertExpectations(t)
+}
+
+// ---------------------------------------------------------------------------
+// Broadcast
+// ---------------------------------------------------------------------------
+
+func newBroadcastTestService(t *testing.T, admin bool) (*NotificationService, *mocks.MockNotificationRepository, *mocks.MockProfileRepository) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, _ := newTestRedisReal(t)
+
+	svc := NewNotificationService(notifRepo, redisClient)
+	svc.SetProfileService(NewProfileService(profileRepo, nil, nil))
+
+	profile := testProfile(testUserID)
+	if admin {
+		withAdmin(profile)
+	}
+	profileRepo.On("GetByID", mock.Anything, testUserID).Return(profile, nil)
+
+	return svc, notifRepo, profileRepo
+}
+
+func TestBroadcast_Success(t *testing.T) {
+	svc, notifRepo, profileRepo := newBroadcastTestService(t, true)
+	recipients := []string{testSellerID, testBuyerID}
+
+	profileRepo.On("ListIDsByAudience", mock.Anything, "premium", "", broadcastBatchSize).Return(recipients, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		if len(ns) != 2 {
+			return false
+		}
+		for _, n := range ns {
+			if n.Type != models.NotificationTypeAnnouncement || n.Title != "Maintenance" {
+				return false
+			}
+		}
+		return true
+	})).Return(nil)
+
+	job := &BroadcastJob{ID: "broadcast-1", Audience: "premium", Title: "Maintenance", Body: "Downtime Sunday"}
+	err := svc.runBroadcast(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, BroadcastStatusFinished, job.Status)
+	assert.Equal(t, 2, job.Targeted)
+	assert.Equal(t, 2, job.Sent)
+	assert.Equal(t, 0, job.Failed)
+	assert.NotNil(t, job.FinishedAt)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestBroadcast_InvalidatesCountCaches(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, mr := newTestRedisReal(t)
+
+	svc := NewNotificationService(notifRepo, redisClient)
+	svc.SetProfileService(NewProfileService(profileRepo, nil, nil))
+	profileRepo.On("GetByID", mock.Anything, testUserID).Return(testProfile(testUserID, withAdmin), nil)
+	profileRepo.On("ListIDsByAudience", mock.Anything, "all", "", broadcastBatchSize).Return([]string{testSellerID}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+
+	cacheKey := cache.NotificationCountKey(testSellerID)
+	mr.Set(cacheKey, "3")
+
+	err := svc.runBroadcast(context.Background(), &BroadcastJob{ID: "broadcast-1", Audience: "all", Title: "Event", Body: "Double drops this weekend"})
+
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists(cacheKey))
+}
+
+func TestBroadcast_BatchFailureFallsBackPerUser(t *testing.T) {
+	svc, notifRepo, profileRepo := newBroadcastTestService(t, true)
+
+	profileRepo.On("ListIDsByAudience", mock.Anything, "active-sellers", "", broadcastBatchSize).Return([]string{testSellerID, testBuyerID}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(fmt.Errorf("batch insert failed"))
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testSellerID
+	})).Return(nil)
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testBuyerID
+	})).Return(fmt.Errorf("fk violation"))
+
+	job := &BroadcastJob{ID: "broadcast-1", Audience: "active-sellers", Title: "Notice", Body: "Body"}
+	err := svc.runBroadcast(context.Background(), job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, job.Targeted)
+	assert.Equal(t, 1, job.Sent)
+	assert.Equal(t, 1, job.Failed)
+}
+
+func TestBroadcast_NotAdmin(t *testing.T) {
+	svc, notifRepo, profileRepo := newBroadcastTestService(t, false)
+
+	_, err := svc.StartBroadcast(context.Background(), testUserID, "all", "Title", "Body", "")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	profileRepo.AssertNotCalled(t, "ListIDsByAudience", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestBroadcast_InvalidAudience(t *testing.T) {
+	svc, _, profileRepo := newBroadcastTestService(t, true)
+
+	_, err := svc.StartBroadcast(context.Background(), testUserID, "everyone", "Title", "Body", "")
+
+	assert.ErrorIs(t, err, ErrInvalidAudience)
+	profileRepo.AssertNotCalled(t, "ListIDsByAudience", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStartBroadcast_RunsInBackgroundOnce(t *testing.T) {
+	svc, notifRepo, profileRepo := newBroadcastTestService(t, true)
+	ctx := context.Background()
+
+	profileRepo.On("ListIDsByAudience", mock.Anything, "all", "", broadcastBatchSize).Return([]string{testSellerID, testBuyerID}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+
+	job, err := svc.StartBroadcast(ctx, testUserID, "all", "Maintenance", "Downtime Sunday", "")
+	require.NoError(t, err)
+	assert.Equal(t, BroadcastStatusQueued, job.Status)
+
+	// A retried request gets the same job instead of a second broadcast
+	retry, err := svc.StartBroadcast(ctx, testUserID, "all", "Maintenance", "Downtime Sunday", "")
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, retry.ID)
+
+	assert.Eventually(t, func() bool {
+		got, err := svc.GetBroadcast(ctx, testUserID, job.ID)
+		return err == nil && got.Status == BroadcastStatusFinished
+	}, time.Second, 10*time.Millisecond)
+
+	got, err := svc.GetBroadcast(ctx, testUserID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Sent)
+	notifRepo.AssertNumberOfCalls(t, "CreateBatch", 1)
+}
+
+func TestGetBroadcast_Unknown(t *testing.T) {
+	svc, _, _ := newBroadcastTestService(t, true)
+
+	_, err := svc.GetBroadcast(context.Background(), testUserID, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// ---------------------------------------------------------------------------
+// Quiet hours delivery
+// ---------------------------------------------------------------------------
+
+// recordingDeliverer captures delivered notifications for assertions
+type recordingDeliverer struct {
+	delivered []*models.Notification
+}
+
+func (d *recordingDeliverer) Deliver(ctx context.Context, notification *models.Notification) error {
+	d.delivered = append(d.delivered, notification)
+	return nil
+}
+
+// quietHoursAround returns a quiet window (UTC) that contains or excludes now
+func quietHoursAround(active bool) func(*models.Profile) {
+	now := time.Now().UTC()
+	var start, end time.Time
+	if active {
+		start, end = now.Add(-1*time.Hour), now.Add(1*time.Hour)
+	} else {
+		start, end = now.Add(1*time.Hour), now.Add(2*time.Hour)
+	}
+	return func(p *models.Profile) {
+		s, e := start.Format("15:04"), end.Format("15:04")
+		p.QuietHoursStart = &s
+		p.QuietHoursEnd = &e
+	}
+}
+
+func newQuietHoursTestService(t *testing.T, profile *models.Profile) (*NotificationService, *mocks.MockNotificationRepository, *recordingDeliverer) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, _ := newTestRedisReal(t)
+
+	svc := NewNotificationService(notifRepo, redisClient)
+	svc.SetProfileService(NewProfileService(profileRepo, nil, nil))
+	deliverer := &recordingDeliverer{}
+	svc.SetDeliverer(deliverer)
+
+	profileRepo.On("GetByID", mock.Anything, profile.ID).Return(profile, nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	return svc, notifRepo, deliverer
+}
+
+func TestNotificationCreate_DeliversOutsideQuietHours(t *testing.T) {
+	profile := testProfile(testUserID, quietHoursAround(false))
+	svc, notifRepo, deliverer := newQuietHoursTestService(t, profile)
+
+	err := svc.NotifyNewMessage(context.Background(), testUserID, testChatID, "Seller")
+	assert.NoError(t, err)
+
+	assert.Len(t, deliverer.delivered, 1)
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestNotificationCreate_SuppressesDuringQuietHours(t *testing.T) {
+	profile := testProfile(testUserID, quietHoursAround(true))
+	svc, notifRepo, deliverer := newQuietHoursTestService(t, profile)
+
+	err := svc.NotifyNewMessage(context.Background(), testUserID, testChatID, "Seller")
+	assert.NoError(t, err)
+
+	// Still persisted, but nothing pushed out
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+	assert.Empty(t, deliverer.delivered)
+}
+
+func TestNotificationCreate_FlushesDigestAfterQuietHours(t *testing.T) {
+	profile := testProfile(testUserID, quietHoursAround(true))
+	svc, _, deliverer := newQuietHoursTestService(t, profile)
+	ctx := context.Background()
+
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+	assert.Empty(t, deliverer.delivered)
+
+	// Quiet hours end
+	profile.QuietHoursStart = nil
+	profile.QuietHoursEnd = nil
+
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+
+	assert.Len(t, deliverer.delivered, 2)
+	assert.Equal(t, models.NotificationTypeDigest, deliverer.delivered[0].Type)
+	assert.Equal(t, "You have 2 new notifications", deliverer.delivered[0].GetBody())
+	assert.Equal(t, models.NotificationTypeNewMessage, deliverer.delivered[1].Type)
+}
+
+func TestFlushDueDigests_SendsDigestWhenQuietHoursEnd(t *testing.T) {
+	profile := testProfile(testUserID, quietHoursAround(true))
+	svc, _, deliverer := newQuietHoursTestService(t, profile)
+	ctx := context.Background()
+
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+
+	// Quiet hours haven't ended yet
+	n, err := svc.FlushDueDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, deliverer.delivered)
+
+	// Quiet hours end with no further notification to trigger the digest
+	profile.QuietHoursStart = nil
+	profile.QuietHoursEnd = nil
+	require.NoError(t, svc.redis.ZAdd(ctx, cache.NotificationDigestDueKey(), float64(time.Now().Add(-time.Second).Unix()), testUserID))
+
+	n, err = svc.FlushDueDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, deliverer.delivered, 1)
+	assert.Equal(t, models.NotificationTypeDigest, deliverer.delivered[0].Type)
+	assert.Equal(t, "You have 2 new notifications", deliverer.delivered[0].GetBody())
+
+	// The user is no longer due, so another run sends nothing
+	n, err = svc.FlushDueDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, deliverer.delivered, 1)
+}
+
+func TestFlushDueDigests_ReschedulesWhileStillQuiet(t *testing.T) {
+	profile := testProfile(testUserID, quietHoursAround(true))
+	svc, _, deliverer := newQuietHoursTestService(t, profile)
+	ctx := context.Background()
+
+	_ = svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller")
+
+	// Due by the old schedule, but the user moved their quiet hours later
+	require.NoError(t, svc.redis.ZAdd(ctx, cache.NotificationDigestDueKey(), float64(time.Now().Add(-time.Second).Unix()), testUserID))
+
+	n, err := svc.FlushDueDigests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, deliverer.delivered)
+
+	due, err := svc.redis.ZRangeByScoreMax(ctx, cache.NotificationDigestDueKey(), float64(time.Now().Add(2*time.Hour).Unix()), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{testUserID}, due)
+	queued, err := svc.redis.LRange(ctx, cache.NotificationDigestKey(testUserID), 0, -1)
+	require.NoError(t, err)
+	assert.Len(t, queued, 1)
+}
+
+func TestNotificationCreate_WebhookDelivererRespectsQuietHours(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []webhookNotification
+	)
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer relay-secret", r.Header.Get("Authorization"))
+		var body webhookNotification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		received = append(received, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer relay.Close()
+	delivered := func() []webhookNotification {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]webhookNotification(nil), received...)
+	}
+
+	profile := testProfile(testUserID, quietHoursAround(true))
+	svc, _, _ := newQuietHoursTestService(t, profile)
+	svc.SetDeliverer(NewWebhookDeliverer(relay.URL, "relay-secret"))
+	ctx := context.Background()
+
+	require.NoError(t, svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller"))
+	assert.Empty(t, delivered())
+
+	profile.QuietHoursStart = nil
+	profile.QuietHoursEnd = nil
+	require.NoError(t, svc.NotifyNewMessage(ctx, testUserID, testChatID, "Seller"))
+
+	got := delivered()
+	require.Len(t, got, 2)
+	assert.Equal(t, string(models.NotificationTypeDigest), got[0].Type)
+	assert.Equal(t, "You have 1 new notifications", got[0].Body)
+	assert.Equal(t, string(models.NotificationTypeNewMessage), got[1].Type)
+	assert.Equal(t, testUserID, got[1].UserID)
+	assert.Equal(t, testChatID, got[1].ReferenceID)
+}
+
+func TestWebhookDeliverer_ErrorStatus(t *testing.T) {
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "relay down", http.StatusBadGateway)
+	}))
+	defer relay.Close()
+
+	err := NewWebhookDeliverer(relay.URL, "").Deliver(context.Background(), &models.Notification{UserID: testUserID, Title: "Hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
+
 // ---------------------------------------------------------------------------
 // Notify methods
 // ---------------------------------------------------------------------------
@@ -215,6 +944,26 @@ func TestNotifyOfferReceived(t *testing.T) {
 	notifRepo.AssertExpectations(t)
 }
 
+func TestSendWelcome(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewNotificationService(notifRepo, newTestRedis())
+
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).
+		Run(func(args mock.Arguments) {
+			n := args.Get(1).(*models.Notification)
+			assert.Equal(t, testUserID, n.UserID)
+			assert.Equal(t, models.NotificationTypeWelcome, n.Type)
+			assert.Nil(t, n.ReferenceID)
+			assert.Contains(t, string(n.Metadata), `"path":"/listings/new"`)
+			assert.Contains(t, string(n.Metadata), `"path":"/wishlist"`)
+		}).Return(nil)
+
+	err := svc.SendWelcome(context.Background(), testUserID)
+	assert.NoError(t, err)
+
+	notifRepo.AssertExpectations(t)
+}
+
 func TestNotifyOfferAccepted(t *testing.T) {
 	notifRepo := new(mocks.MockNotificationRepository)
 	svc := NewNotificationService(notifRepo, newTestRedis())
@@ -461,3 +1210,154 @@ func TestNotificationToResponse(t *testing.T) {
 	assert.Equal(t, metadata, resp.Metadata)
 	assert.Equal(t, now, resp.CreatedAt)
 }
+
+// ---------------------------------------------------------------------------
+// Stream
+// ---------------------------------------------------------------------------
+
+func receiveStreamEvent(t *testing.T, sub *cache.Subscription) dto.NotificationStreamEvent {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		var event dto.NotificationStreamEvent
+		assert.NoError(t, json.Unmarshal([]byte(msg), &event))
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for stream event")
+		return dto.NotificationStreamEvent{}
+	}
+}
+
+func TestSubscribe_NilRedis(t *testing.T) {
+	svc := NewNotificationService(new(mocks.MockNotificationRepository), newTestRedis())
+
+	sub, err := svc.Subscribe(context.Background(), testUserID)
+
+	assert.Nil(t, sub)
+	assert.ErrorIs(t, err, ErrStreamUnavailable)
+}
+
+func TestNotificationCreate_PublishesToStream(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	sub, err := svc.Subscribe(ctx, testUserID)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	notification := &models.Notification{
+		UserID: testUserID,
+		Type:   models.NotificationTypeNewMessage,
+		Title:  "Test",
+	}
+	notifRepo.On("Create", ctx, notification).Return(nil)
+	notifRepo.On("CountUnread", ctx, testUserID).Return(3, nil)
+
+	assert.NoError(t, svc.Create(ctx, notification))
+
+	event := receiveStreamEvent(t, sub)
+	assert.Equal(t, StreamEventNotification, event.Type)
+	assert.Equal(t, notification.ID, event.Notification.ID)
+
+	event = receiveStreamEvent(t, sub)
+	assert.Equal(t, StreamEventUnreadCount, event.Type)
+	assert.Equal(t, 3, *event.UnreadCount)
+}
+
+func TestMarkAsRead_PublishesUnreadCount(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	sub, err := svc.Subscribe(ctx, testUserID)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	notifRepo.On("MarkAsRead", ctx, []string{"notif-1"}, testUserID).Return(nil)
+	notifRepo.On("CountUnread", ctx, testUserID).Return(0, nil)
+
+	assert.NoError(t, svc.MarkAsRead(ctx, testUserID, []string{"notif-1"}))
+
+	event := receiveStreamEvent(t, sub)
+	assert.Equal(t, StreamEventUnreadCount, event.Type)
+	assert.Equal(t, 0, *event.UnreadCount)
+}
+
+func TestNotificationCreate_StreamScopedToRecipient(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewNotificationService(notifRepo, redisClient)
+	ctx := context.Background()
+
+	sub, err := svc.Subscribe(ctx, "other-user")
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	notification := &models.Notification{
+		UserID: testUserID,
+		Type:   models.NotificationTypeNewMessage,
+		Title:  "Test",
+	}
+	notifRepo.On("Create", ctx, notification).Return(nil)
+
+	assert.NoError(t, svc.Create(ctx, notification))
+
+	select {
+	case msg := <-sub.Messages():
+		t.Fatalf("unexpected event for another user: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// No subscriber for the recipient, so the unread count isn't recomputed
+	notifRepo.AssertNotCalled(t, "CountUnread", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// GetByUserIDAfter
+// ---------------------------------------------------------------------------
+
+func TestGetByUserIDAfter_PassesDecodedCursor(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewNotificationService(notifRepo, nil)
+	ctx := context.Background()
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cursor := dto.EncodeCursor(createdAt, "notif-1")
+	page := []*models.Notification{{ID: "notif-2", UserID: testUserID, CreatedAt: createdAt.Add(-time.Minute)}}
+
+	notifRepo.On("GetByUserIDAfter", ctx, testUserID, true, "new_message", mock.MatchedBy(func(after *repository.PageCursor) bool {
+		return after != nil && after.ID == "notif-1" && after.CreatedAt.Equal(createdAt)
+	}), 20).Return(page, false, nil)
+
+	result, hasMore, err := svc.GetByUserIDAfter(ctx, testUserID, true, "new_message", cursor, 20)
+
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, page, result)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestGetByUserIDAfter_InvalidCursor(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewNotificationService(notifRepo, nil)
+
+	_, _, err := svc.GetByUserIDAfter(context.Background(), testUserID, false, "", "bm9waXBl", 20)
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	notifRepo.AssertNotCalled(t, "GetByUserIDAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotificationCreate_SandboxSkipsDelivery(t *testing.T) {
+	profile := testProfile(testUserID)
+	svc, notifRepo, deliverer := newQuietHoursTestService(t, profile)
+	svc.SetSandboxMode(true)
+
+	err := svc.NotifyNewMessage(context.Background(), testUserID, testChatID, "Seller")
+	assert.NoError(t, err)
+
+	// Stored as usual, but never pushed out
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+	assert.Empty(t, deliverer.delivered)
+}
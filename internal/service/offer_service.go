@@ -2,19 +2,41 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/database"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 )
 
+const (
+	offerCacheTTL    = 5 * time.Minute
+	offerDTOCacheTTL = 5 * time.Minute
+
+	// siblingDeclineNote is stored on the other pending offers of a listing when one is accepted
+	siblingDeclineNote = "Item no longer available"
+)
+
+// txRunner runs fn in a database transaction; repository writes made with fn's ctx join it.
+// *database.BunDB satisfies it.
+type txRunner interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // OfferService handles offer business logic
 type OfferService struct {
-	db                  *database.BunDB
+	db                  txRunner
 	repo                repository.OfferRepository
 	listingRepo         repository.ListingRepository
 	serviceRepo         repository.ServiceRepository
@@ -26,8 +48,15 @@ type OfferService struct {
 	listingService      *ListingService
 	serviceService      *ServiceService
 	statsService        *StatsService
+	declineTemplateRepo repository.DeclineTemplateRepository
+	offerTemplateRepo   repository.OfferTemplateRepository
+	valueEstimator      games.ValueEstimator
 	redis               *cache.RedisClient
 	invalidator         *cache.Invalidator
+	// pauseOnAccept moves a listing to "pending" once an offer on it is accepted
+	pauseOnAccept bool
+	// delegateRepo lets sellers' delegates respond to their offers; nil limits it to sellers
+	delegateRepo repository.DelegateRepository
 }
 
 // NewOfferService creates a new offer service
@@ -57,18 +86,102 @@ func NewOfferService(
 		profileService:      profileService,
 		listingService:      listingService,
 		serviceService:      serviceService,
+		valueEstimator:      games.ValueEstimatorFunc(d2.EstimateItemValue),
 		redis:               redis,
 		invalidator:         cache.NewInvalidator(redis),
 	}
 }
 
+// SetDelegateRepository lets delegates with the respond_offers permission view, accept and reject
+// offers on a seller's listings and services
+func (s *OfferService) SetDelegateRepository(repo repository.DelegateRepository) {
+	s.delegateRepo = repo
+}
+
 // SetStatsService sets the stats service for cache refresh on offer events
 func (s *OfferService) SetStatsService(ss *StatsService) {
 	s.statsService = ss
 }
 
-// Create creates a new offer (item or service)
+// SetDeclineTemplateRepository sets the repository used to resolve decline templates on reject
+func (s *OfferService) SetDeclineTemplateRepository(repo repository.DeclineTemplateRepository) {
+	s.declineTemplateRepo = repo
+}
+
+// SetOfferTemplateRepository sets the repository used to resolve offer templates for quick offers
+func (s *OfferService) SetOfferTemplateRepository(repo repository.OfferTemplateRepository) {
+	s.offerTemplateRepo = repo
+}
+
+// SetValueEstimator replaces the estimator used to value offered items (ranking and estimatedValue)
+func (s *OfferService) SetValueEstimator(estimator games.ValueEstimator) {
+	s.valueEstimator = estimator
+}
+
+// SetPauseListingOnAccept makes Accept move the listing to "pending" so it leaves
+// browse and stops taking offers while the trade runs. Cancelling the trade reactivates it.
+func (s *OfferService) SetPauseListingOnAccept(enabled bool) {
+	s.pauseOnAccept = enabled
+}
+
+// Create creates a new offer (item or service).
+// A repeated request with the same idempotency key returns the offer created by the first.
 func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.CreateOfferRequest) (*models.Offer, error) {
+	return createIdempotent(ctx, s.redis, "offer", requesterID, req.IdempotencyKey,
+		func() (*models.Offer, error) { return s.create(ctx, requesterID, req) },
+		func(o *models.Offer) string { return o.ID },
+		func(id string) (*models.Offer, error) { return s.repo.GetByID(ctx, id) },
+	)
+}
+
+// CreateFromTemplate makes an offer with the items of one of the requester's saved
+// templates. The target is looked up as a listing first, then as a service, and the
+// offer then goes through the same validation as Create.
+func (s *OfferService) CreateFromTemplate(ctx context.Context, requesterID string, listingOrServiceID string, templateID string) (*models.Offer, error) {
+	template, err := s.offerTemplateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.UserID != requesterID {
+		return nil, ErrForbidden
+	}
+
+	req := &dto.CreateOfferRequest{OfferedItems: template.OfferedItems}
+
+	_, err = s.listingRepo.GetByID(ctx, listingOrServiceID)
+	switch {
+	case err == nil:
+		req.Type = "item"
+		req.ListingID = &listingOrServiceID
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := s.serviceRepo.GetByID(ctx, listingOrServiceID); err != nil {
+			return nil, err
+		}
+		req.Type = "service"
+		req.ServiceID = &listingOrServiceID
+	default:
+		return nil, err
+	}
+
+	return s.Create(ctx, requesterID, req)
+}
+
+// create performs the actual offer creation
+func (s *OfferService) create(ctx context.Context, requesterID string, req *dto.CreateOfferRequest) (*models.Offer, error) {
+	if err := s.profileService.RequireVerifiedEmail(ctx, requesterID); err != nil {
+		return nil, err
+	}
+
+	errs := ValidationErrors{}
+	validateItemList(errs, "offeredItems", req.OfferedItems)
+	if req.Type == "service" && len(req.RequestedAddition) > 0 {
+		errs.Add("requestedAddition", "only item offers can request additions")
+	}
+	validateItemList(errs, "requestedAddition", req.RequestedAddition)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
 	offer := &models.Offer{
 		ID:           uuid.New().String(),
 		Type:         req.Type,
@@ -83,6 +196,10 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 		offer.Message = &req.Message
 	}
 
+	if hasItems(req.RequestedAddition) {
+		offer.RequestedAddition = req.RequestedAddition
+	}
+
 	if req.Type == "service" {
 		// Service offer
 		if req.ServiceID == nil || *req.ServiceID == "" {
@@ -94,7 +211,8 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 			return nil, err
 		}
 
-		if service.ProviderID == requesterID {
+		// Delegates manage the provider's offers, so they can't make their own
+		if service.ProviderID == requesterID || isDelegateOf(ctx, s.delegateRepo, service.ProviderID, requesterID) {
 			return nil, ErrSelfAction
 		}
 
@@ -102,6 +220,10 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 			return nil, ErrInvalidState
 		}
 
+		if err := checkBuyerRequirements(ctx, s.profileService, service.BuyerRequirements(), requesterID); err != nil {
+			return nil, err
+		}
+
 		offer.ServiceID = req.ServiceID
 	} else {
 		// Item offer
@@ -114,14 +236,23 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 			return nil, err
 		}
 
-		if listing.SellerID == requesterID {
+		// Delegates manage the seller's offers, so they can't make their own
+		if listing.SellerID == requesterID || isDelegateOf(ctx, s.delegateRepo, listing.SellerID, requesterID) {
 			return nil, ErrSelfAction
 		}
 
-		if !listing.IsActive() {
+		// Reserved listings only take offers from the buyer they are held for
+		if !listing.IsActive() && !listing.IsReservedFor(requesterID) {
 			return nil, ErrInvalidState
 		}
 
+		// The seller picked the buyer a listing is reserved for, so their reputation isn't re-checked
+		if !listing.IsReservedFor(requesterID) {
+			if err := checkBuyerRequirements(ctx, s.profileService, listing.BuyerRequirements(), requesterID); err != nil {
+				return nil, err
+			}
+		}
+
 		// Check if listing has an active trade
 		hasActive, err := s.tradeRepo.HasActiveTradeForListing(ctx, *req.ListingID)
 		if err != nil {
@@ -131,7 +262,19 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 			return nil, ErrInvalidState
 		}
 
+		if limit := pendingOfferLimit(listing); limit > 0 {
+			pending, err := s.listingRepo.CountPendingOffers(ctx, listing.ID)
+			if err != nil {
+				return nil, err
+			}
+			if pending >= limit {
+				return nil, ErrOfferQueueFull
+			}
+		}
+
 		offer.ListingID = req.ListingID
+		listingHash := listing.ContentHash()
+		offer.ListingHash = &listingHash
 	}
 
 	if err := s.repo.Create(ctx, offer); err != nil {
@@ -154,21 +297,52 @@ func (s *OfferService) Create(ctx context.Context, requesterID string, req *dto.
 	return offer, nil
 }
 
-// GetByID retrieves an offer by ID
+// GetByID retrieves an offer by ID with caching
 func (s *OfferService) GetByID(ctx context.Context, id string, userID string) (*models.Offer, error) {
-	offer, err := s.repo.GetByIDWithRelations(ctx, id)
+	offer, err := s.getByIDCached(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check user is a participant
-	if !s.isOfferParticipant(offer, userID) {
+	if !s.isOfferParticipant(ctx, offer, userID) {
 		return nil, ErrForbidden
 	}
 
 	return offer, nil
 }
 
+// getByIDCached loads an offer with its relations, going through the offer cache
+func (s *OfferService) getByIDCached(ctx context.Context, id string) (*models.Offer, error) {
+	// Try cache first
+	cacheKey := cache.OfferKey(id)
+	cached, err := s.redis.Get(ctx, cacheKey)
+	if err == nil && cached != "" {
+		var offer models.Offer
+		if json.Unmarshal([]byte(cached), &offer) == nil {
+			return &offer, nil
+		}
+	}
+
+	// Fetch from database
+	offer, err := s.repo.GetByIDWithRelations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(offer); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), offerCacheTTL)
+	}
+
+	// Cache DTO version for frontend direct access
+	if data, err := json.Marshal(s.ToResponse(offer)); err == nil {
+		_ = s.redis.Set(ctx, cache.OfferDTOKey(offer.ID), string(data), offerDTOCacheTTL)
+	}
+
+	return offer, nil
+}
+
 // Accept accepts an offer and creates a Trade+Chat (item) or ServiceRun+Chat (service)
 func (s *OfferService) Accept(ctx context.Context, id string, userID string) (*models.Offer, *models.Trade, *models.ServiceRun, *models.Chat, error) {
 	offer, err := s.repo.GetByIDWithRelations(ctx, id)
@@ -176,8 +350,13 @@ func (s *OfferService) Accept(ctx context.Context, id string, userID string) (*m
 		return nil, nil, nil, nil, err
 	}
 
-	// Only seller/provider can accept
-	if !s.isOfferOwner(offer, userID) {
+	// Nobody answers their own proposal
+	if s.isProposer(ctx, offer, userID) {
+		return nil, nil, nil, nil, ErrSelfAction
+	}
+
+	// Only seller/provider can accept, or the requester when it's a counteroffer
+	if !s.canRespond(ctx, offer, userID) {
 		return nil, nil, nil, nil, ErrForbidden
 	}
 
@@ -185,97 +364,150 @@ func (s *OfferService) Accept(ctx context.Context, id string, userID string) (*m
 		return nil, nil, nil, nil, ErrInvalidState
 	}
 
+	if offer.Listing != nil && offer.Listing.Status == "reserved" && !offer.Listing.IsReservedFor(offer.RequesterID) {
+		return nil, nil, nil, nil, ErrInvalidState
+	}
+
+	// The item was swapped after the buyer offered on it; they have to agree to the new one
+	if offer.ListingChanged() {
+		_ = s.notificationService.NotifyOfferListingChanged(ctx, offer.RequesterID, offer.ID, offer.Listing.Name)
+		return nil, nil, nil, nil, ErrListingChanged
+	}
+
 	now := time.Now()
 	offer.Status = "accepted"
 	offer.AcceptedAt = &now
 	offer.UpdatedAt = now
 
-	if err := s.repo.Update(ctx, offer); err != nil {
-		return nil, nil, nil, nil, err
-	}
+	// The offer update and the trade/service run + chat inserts commit together, so a
+	// failure part-way never leaves an accepted offer without its trade. The listing's
+	// other pending offers are rejected in the same transaction, so it never ends up with
+	// two accepted offers. With pauseOnAccept the listing is held in it too. Cache
+	// invalidation and notifications only run once it has committed.
+	var trade *models.Trade
+	var serviceRun *models.ServiceRun
+	var chat *models.Chat
+	var siblings []*models.Offer
+	var held bool
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, offer); err != nil {
+			return err
+		}
 
-	if offer.IsServiceOffer() {
-		// Create ServiceRun + Chat
-		serviceRun := &models.ServiceRun{
-			ID:         uuid.New().String(),
-			ServiceID:  *offer.ServiceID,
-			OfferID:    offer.ID,
-			ProviderID: offer.Service.ProviderID,
-			ClientID:   offer.RequesterID,
-			Status:     "active",
-			CreatedAt:  now,
-			UpdatedAt:  now,
+		if offer.IsServiceOffer() {
+			serviceRun = &models.ServiceRun{
+				ID:         uuid.New().String(),
+				ServiceID:  *offer.ServiceID,
+				OfferID:    offer.ID,
+				ProviderID: offer.Service.ProviderID,
+				ClientID:   offer.RequesterID,
+				Status:     "active",
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := s.serviceRunRepo.Create(ctx, serviceRun); err != nil {
+				return err
+			}
+
+			serviceRunID := serviceRun.ID
+			chat = &models.Chat{
+				ID:           uuid.New().String(),
+				ServiceRunID: &serviceRunID,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}
+			return s.chatRepo.Create(ctx, chat)
 		}
 
-		if err := s.serviceRunRepo.Create(ctx, serviceRun); err != nil {
-			return nil, nil, nil, nil, err
+		hasActive, err := s.tradeRepo.HasActiveTradeForListing(ctx, *offer.ListingID)
+		if err != nil {
+			return err
+		}
+		if hasActive {
+			return ErrInvalidState
 		}
 
-		serviceRunID := serviceRun.ID
-		chat := &models.Chat{
-			ID:           uuid.New().String(),
-			ServiceRunID: &serviceRunID,
-			CreatedAt:    now,
-			UpdatedAt:    now,
+		trade = &models.Trade{
+			ID:        uuid.New().String(),
+			OfferID:   offer.ID,
+			ListingID: *offer.ListingID,
+			SellerID:  offer.Listing.SellerID,
+			BuyerID:   offer.RequesterID,
+			Status:    "active",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.tradeRepo.Create(ctx, trade); err != nil {
+			return err
 		}
 
+		tradeID := trade.ID
+		chat = &models.Chat{
+			ID:        uuid.New().String(),
+			TradeID:   &tradeID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
 		if err := s.chatRepo.Create(ctx, chat); err != nil {
-			return nil, nil, nil, nil, err
+			return err
 		}
 
-		_ = s.notificationService.NotifyOfferAccepted(ctx, offer.RequesterID, offer.ID, offer.Service.Name)
-		_ = s.notificationService.NotifyServiceRunCreated(ctx, offer.RequesterID, serviceRun.ID, offer.Service.Name)
-
-		if s.statsService != nil {
-			go s.statsService.RefreshHomeStats(context.Background())
+		siblings, err = s.repo.RejectPendingForListing(ctx, *offer.ListingID, offer.ID, siblingDeclineNote, now)
+		if err != nil {
+			return err
 		}
 
-		return offer, nil, serviceRun, chat, nil
-	}
-
-	// Item offer: create Trade + Chat
-	hasActive, err := s.tradeRepo.HasActiveTradeForListing(ctx, *offer.ListingID)
+		if s.pauseOnAccept {
+			held, err = s.holdListingForTrade(ctx, offer.Listing, now)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	if hasActive {
-		return nil, nil, nil, nil, ErrInvalidState
-	}
 
-	trade := &models.Trade{
-		ID:        uuid.New().String(),
-		OfferID:   offer.ID,
-		ListingID: *offer.ListingID,
-		SellerID:  offer.Listing.SellerID,
-		BuyerID:   offer.RequesterID,
-		Status:    "active",
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
+	_ = s.invalidator.InvalidateOffer(ctx, offer.ID)
+	go s.profileService.RefreshResponseTime(context.Background(), s.offerOwnerID(offer))
 
-	if err := s.tradeRepo.Create(ctx, trade); err != nil {
-		return nil, nil, nil, nil, err
-	}
-
-	tradeID := trade.ID
-	chat := &models.Chat{
-		ID:        uuid.New().String(),
-		TradeID:   &tradeID,
-		CreatedAt: now,
-		UpdatedAt: now,
+	if offer.IsServiceOffer() {
+		_ = s.notificationService.NotifyOfferAccepted(ctx, s.proposerID(offer), offer.ID, offer.Service.Name)
+		_ = s.notificationService.NotifyServiceRunCreated(ctx, offer.RequesterID, serviceRun.ID, offer.Service.Name)
+	} else {
+		if held {
+			_ = s.invalidator.InvalidateListing(ctx, offer.Listing.ID)
+			_ = s.invalidator.InvalidateListingDTO(ctx, offer.Listing.ID)
+			_ = s.invalidator.InvalidateFilterResults(ctx)
+			s.listingService.RemoveFromRecentByListing(ctx, offer.Listing)
+		}
+		_ = s.notificationService.NotifyOfferAccepted(ctx, s.proposerID(offer), offer.ID, offer.Listing.Name)
+		for _, sibling := range siblings {
+			_ = s.invalidator.InvalidateOffer(ctx, sibling.ID)
+			_ = s.notificationService.NotifyOfferRejected(ctx, sibling.RequesterID, sibling.ID, offer.Listing.Name)
+		}
 	}
 
-	if err := s.chatRepo.Create(ctx, chat); err != nil {
-		return nil, nil, nil, nil, err
+	if s.statsService != nil {
+		go s.statsService.RefreshHomeStats(context.Background())
 	}
 
-	_ = s.notificationService.NotifyOfferAccepted(ctx, offer.RequesterID, offer.ID, offer.Listing.Name)
+	return offer, trade, serviceRun, chat, nil
+}
 
-	if s.statsService != nil {
-		go s.statsService.RefreshHomeStats(context.Background())
+// holdListingForTrade moves the listing of a freshly created trade to "pending" as part
+// of the accept transaction, and reports whether it did. A reservation is kept, so
+// cancelling the trade can hand the hold back. Caches are dropped by the caller once
+// the transaction has committed.
+func (s *OfferService) holdListingForTrade(ctx context.Context, listing *models.Listing, now time.Time) (bool, error) {
+	if listing == nil || (listing.Status != "active" && listing.Status != "reserved") {
+		return false, nil
 	}
 
-	return offer, trade, nil, chat, nil
+	listing.Status = "pending"
+	listing.UpdatedAt = now
+	if err := s.listingRepo.Update(ctx, listing); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // Reject rejects an offer
@@ -285,7 +517,11 @@ func (s *OfferService) Reject(ctx context.Context, id string, userID string, req
 		return nil, err
 	}
 
-	if !s.isOfferOwner(offer, userID) {
+	if s.isProposer(ctx, offer, userID) {
+		return nil, ErrSelfAction
+	}
+
+	if !s.canRespond(ctx, offer, userID) {
 		return nil, ErrForbidden
 	}
 
@@ -298,31 +534,234 @@ func (s *OfferService) Reject(ctx context.Context, id string, userID string, req
 		return nil, err
 	}
 
+	template, err := s.getDeclineTemplate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canUseDeclineTemplate(template, offer, userID) {
+		return nil, ErrForbidden
+	}
+
+	if err := s.applyRejection(ctx, offer, req, template); err != nil {
+		return nil, err
+	}
+	go s.profileService.RefreshResponseTime(context.Background(), s.offerOwnerID(offer))
+
+	return offer, nil
+}
+
+// MaxBulkRejectOffers caps how many offers one bulk reject may carry
+const MaxBulkRejectOffers = 50
+
+// Per-offer outcomes of a bulk reject
+const (
+	BulkRejectRejected   = "rejected"
+	BulkRejectNotFound   = "not_found"
+	BulkRejectForbidden  = "forbidden"
+	BulkRejectNotPending = "not_pending"
+	BulkRejectFailed     = "failed"
+)
+
+// BulkReject rejects many offers with the same decline reason, reporting what happened
+// to each. The decline reason and template are checked once up front; after that each
+// offer gets the same ownership and pending checks as Reject, and one that fails them
+// is reported without stopping the rest. Repeated IDs are handled once.
+func (s *OfferService) BulkReject(ctx context.Context, sellerID string, offerIDs []string, req *dto.RejectOfferRequest) (*dto.BulkRejectOffersResponse, error) {
+	if len(offerIDs) == 0 || len(offerIDs) > MaxBulkRejectOffers {
+		return nil, ValidationErrors{"offerIds": fmt.Sprintf("must contain between 1 and %d offers", MaxBulkRejectOffers)}
+	}
+
+	if _, err := s.repo.GetDeclineReasonByID(ctx, req.DeclineReasonID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.getDeclineTemplate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.BulkRejectOffersResponse{Results: make([]dto.BulkRejectResult, 0, len(offerIDs))}
+	seen := make(map[string]bool, len(offerIDs))
+	owners := make(map[string]bool)
+	for _, id := range offerIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := dto.BulkRejectResult{OfferID: id}
+		offer, err := s.repo.GetByIDWithRelations(ctx, id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			result.Status = BulkRejectNotFound
+		case err != nil:
+			logger.FromContext(ctx).Warn("failed to load offer for bulk reject",
+				"error", err.Error(),
+				"offer_id", id,
+				"user_id", sellerID,
+			)
+			result.Status = BulkRejectFailed
+		case s.isProposer(ctx, offer, sellerID) || !s.canRespond(ctx, offer, sellerID) || !s.canUseDeclineTemplate(template, offer, sellerID):
+			result.Status = BulkRejectForbidden
+		case !offer.IsPending():
+			result.Status = BulkRejectNotPending
+		default:
+			if err := s.applyRejection(ctx, offer, req, template); err != nil {
+				logger.FromContext(ctx).Warn("failed to reject offer in bulk",
+					"error", err.Error(),
+					"offer_id", id,
+					"user_id", sellerID,
+				)
+				result.Status = BulkRejectFailed
+				break
+			}
+			result.Status = BulkRejectRejected
+			result.Offer = s.ToResponse(offer)
+			owners[s.offerOwnerID(offer)] = true
+			resp.Rejected++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Failed = len(resp.Results) - resp.Rejected
+
+	// One refresh per seller covers every rejection in the batch
+	for ownerID := range owners {
+		go s.profileService.RefreshResponseTime(context.Background(), ownerID)
+	}
+
+	return resp, nil
+}
+
+// getDeclineTemplate loads the decline template a rejection asked for, or nil without one
+func (s *OfferService) getDeclineTemplate(ctx context.Context, req *dto.RejectOfferRequest) (*models.DeclineTemplate, error) {
+	if req.DeclineTemplateID == nil {
+		return nil, nil
+	}
+	return s.declineTemplateRepo.GetByID(ctx, *req.DeclineTemplateID)
+}
+
+// canUseDeclineTemplate checks the template may be used on the offer. Delegates may use
+// their own templates or the seller's.
+func (s *OfferService) canUseDeclineTemplate(template *models.DeclineTemplate, offer *models.Offer, userID string) bool {
+	return template == nil || template.SellerID == userID || template.SellerID == s.offerOwnerID(offer)
+}
+
+// applyRejection stores a checked rejection and notifies whoever proposed the offer
+func (s *OfferService) applyRejection(ctx context.Context, offer *models.Offer, req *dto.RejectOfferRequest, template *models.DeclineTemplate) error {
+	note := req.DeclineNote
+	if template != nil {
+		// Copy the message so later template edits don't rewrite past rejections
+		note = template.Message
+	}
+
 	offer.Status = "rejected"
-	offer.DeclineReasonID = &req.DeclineReasonID
-	if req.DeclineNote != "" {
-		offer.DeclineNote = &req.DeclineNote
+	reasonID := req.DeclineReasonID
+	offer.DeclineReasonID = &reasonID
+	if note != "" {
+		offer.DeclineNote = &note
 	}
 	offer.UpdatedAt = time.Now()
 
 	if err := s.repo.Update(ctx, offer); err != nil {
-		return nil, err
+		return err
 	}
+	_ = s.invalidator.InvalidateOffer(ctx, offer.ID)
 
 	itemName := s.getOfferItemName(offer)
-	_ = s.notificationService.NotifyOfferRejected(ctx, offer.RequesterID, offer.ID, itemName)
+	_ = s.notificationService.NotifyOfferRejected(ctx, s.proposerID(offer), offer.ID, itemName)
+	return nil
+}
 
-	return offer, nil
+// Counter answers a pending offer with the items the seller/provider would take instead.
+// The original offer is marked "countered" and a new pending offer linked to it through
+// ParentOfferID is made for the same requester, who can accept or reject it like any
+// other offer. Counters can't be countered again; the requester rejects and re-offers.
+func (s *OfferService) Counter(ctx context.Context, offerID string, sellerID string, req *dto.CounterOfferRequest) (*models.Offer, error) {
+	offer, err := s.repo.GetByIDWithRelations(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isOfferOwner(ctx, offer, sellerID) {
+		return nil, ErrForbidden
+	}
+
+	if !offer.IsPending() || offer.IsCounter() {
+		return nil, ErrInvalidState
+	}
+
+	if offer.Listing != nil && !offer.Listing.IsActive() && !offer.Listing.IsReservedFor(offer.RequesterID) {
+		return nil, ErrInvalidState
+	}
+
+	errs := ValidationErrors{}
+	validateItemList(errs, "offeredItems", req.OfferedItems)
+	if !hasItems(req.OfferedItems) {
+		errs.Add("offeredItems", "must list at least one item")
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	parentID := offer.ID
+	counter := &models.Offer{
+		ID:            uuid.New().String(),
+		Type:          offer.Type,
+		ListingID:     offer.ListingID,
+		ServiceID:     offer.ServiceID,
+		RequesterID:   offer.RequesterID,
+		OfferedItems:  req.OfferedItems,
+		Status:        "pending",
+		ListingHash:   offer.ListingHash,
+		ParentOfferID: &parentID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if req.Message != "" {
+		counter.Message = &req.Message
+	}
+
+	offer.Status = "countered"
+	offer.UpdatedAt = now
+
+	// The original only leaves "pending" if its counter exists, so an offer is never
+	// countered into nothing
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, offer); err != nil {
+			return err
+		}
+		return s.repo.Create(ctx, counter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.invalidator.InvalidateOffer(ctx, offer.ID)
+	go s.profileService.RefreshResponseTime(context.Background(), s.offerOwnerID(offer))
+
+	counter.Listing = offer.Listing
+	counter.Service = offer.Service
+	counter.Requester = offer.Requester
+
+	_ = s.notificationService.NotifyOfferCountered(ctx, offer.RequesterID, counter.ID, s.getOfferItemName(offer))
+
+	return counter, nil
 }
 
-// Cancel cancels a pending offer (buyer only)
+// Cancel cancels a pending offer (buyer only). Counteroffers are withdrawn by the
+// seller/provider who made them instead.
 func (s *OfferService) Cancel(ctx context.Context, id string, userID string) (*models.Offer, error) {
 	offer, err := s.repo.GetByIDWithRelations(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if offer.RequesterID != userID {
+	if offer.IsCounter() {
+		if !s.isOfferOwner(ctx, offer, userID) {
+			return nil, ErrForbidden
+		}
+	} else if offer.RequesterID != userID {
 		return nil, ErrForbidden
 	}
 
@@ -336,6 +775,7 @@ func (s *OfferService) Cancel(ctx context.Context, id string, userID string) (*m
 	if err := s.repo.Update(ctx, offer); err != nil {
 		return nil, err
 	}
+	_ = s.invalidator.InvalidateOffer(ctx, offer.ID)
 
 	return offer, nil
 }
@@ -359,6 +799,138 @@ func (s *OfferService) List(ctx context.Context, userID string, role string, sta
 	return s.repo.List(ctx, filter)
 }
 
+// ListAfter retrieves a page of offers older than cursor using keyset paging, newest
+// first. An empty cursor returns the newest page.
+func (s *OfferService) ListAfter(ctx context.Context, userID string, role string, status string, offerType string, listingID string, serviceID string, cursor string, limit int) ([]*models.Offer, bool, error) {
+	after, err := decodePageCursor(cursor)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if role == "seller" && status == "" {
+		status = "pending"
+	}
+
+	filter := repository.OfferFilter{
+		UserID:    userID,
+		Role:      role,
+		Status:    status,
+		Type:      offerType,
+		ListingID: listingID,
+		ServiceID: serviceID,
+		Limit:     limit,
+	}
+	return s.repo.ListAfter(ctx, filter, after)
+}
+
+// MaxValueRankedOffers caps how many offers ListByValue loads to rank
+const MaxValueRankedOffers = 500
+
+// ListByValue retrieves a seller's offers ranked by estimated offered-items value (highest first).
+// Offers that cannot be valued sort last, newest first. Values come from the offered items
+// JSON, so ranking happens in memory over the newest MaxValueRankedOffers matching offers;
+// count is still the full number of matches.
+func (s *OfferService) ListByValue(ctx context.Context, userID string, status string, offerType string, listingID string, serviceID string, offset, limit int) ([]*models.Offer, int, error) {
+	offers, count, err := s.List(ctx, userID, "seller", status, offerType, listingID, serviceID, 0, MaxValueRankedOffers)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := make(map[string]*float64, len(offers))
+	for _, offer := range offers {
+		values[offer.ID] = s.estimateOfferedValue(offer.OfferedItems)
+	}
+
+	sort.SliceStable(offers, func(i, j int) bool {
+		vi, vj := values[offers[i].ID], values[offers[j].ID]
+		if vi == nil || vj == nil {
+			return vi != nil && vj == nil
+		}
+		return *vi > *vj
+	})
+
+	if offset >= len(offers) {
+		return []*models.Offer{}, count, nil
+	}
+	end := offset + limit
+	if limit <= 0 || end > len(offers) {
+		end = len(offers)
+	}
+	return offers[offset:end], count, nil
+}
+
+// ListGroupedBySeller returns a page of the seller's listings that have offers in the
+// given status (pending by default), each with those offers nested under it, oldest first.
+// Paging counts listings, not offers; the count is the total number of listings.
+func (s *OfferService) ListGroupedBySeller(ctx context.Context, sellerID, status string, offset, limit int) ([]dto.ListingWithOffers, int, error) {
+	if status == "" {
+		status = "pending"
+	}
+
+	offers, count, err := s.repo.ListBySellerListings(ctx, sellerID, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	groups := make([]dto.ListingWithOffers, 0)
+	index := make(map[string]int)
+	for _, offer := range offers {
+		if offer.Listing == nil {
+			continue
+		}
+		i, ok := index[offer.Listing.ID]
+		if !ok {
+			i = len(groups)
+			index[offer.Listing.ID] = i
+			groups = append(groups, dto.ListingWithOffers{
+				Listing: *s.listingService.ToCardResponse(offer.Listing),
+				Offers:  []dto.OfferResponse{},
+			})
+		}
+
+		resp := s.ToResponse(offer)
+		// The listing is already on the group
+		resp.Listing = nil
+		groups[i].Offers = append(groups[i].Offers, *resp)
+		groups[i].OfferCount++
+	}
+
+	return groups, count, nil
+}
+
+// estimateOfferedValue sums the estimated value of offered items.
+// Returns nil when none of the items can be valued.
+func (s *OfferService) estimateOfferedValue(rawItems json.RawMessage) *float64 {
+	return estimateOfferedItemsValue(s.valueEstimator, rawItems)
+}
+
+// estimateOfferedItemsValue sums what estimator values the raw offered items at,
+// or nil when none of them can be valued
+func estimateOfferedItemsValue(estimator games.ValueEstimator, rawItems json.RawMessage) *float64 {
+	if len(rawItems) == 0 {
+		return nil
+	}
+
+	var items []offeredItemRaw
+	if err := json.Unmarshal(rawItems, &items); err != nil {
+		return nil
+	}
+
+	var total float64
+	valued := false
+	for _, item := range items {
+		if v, ok := estimator.EstimateItemValue(item.Type, item.Name, item.Quantity); ok {
+			total += v
+			valued = true
+		}
+	}
+
+	if !valued {
+		return nil
+	}
+	return &total
+}
+
 // GetDeclineReasons retrieves all active decline reasons
 func (s *OfferService) GetDeclineReasons(ctx context.Context) ([]*models.DeclineReason, error) {
 	return s.repo.GetDeclineReasons(ctx)
@@ -367,22 +939,26 @@ func (s *OfferService) GetDeclineReasons(ctx context.Context) ([]*models.Decline
 // ToResponse converts an offer model to a DTO response
 func (s *OfferService) ToResponse(offer *models.Offer) *dto.OfferResponse {
 	resp := &dto.OfferResponse{
-		ID:           offer.ID,
-		Type:         offer.Type,
-		ListingID:    offer.GetListingID(),
-		ServiceID:    offer.GetServiceID(),
-		RequesterID:  offer.RequesterID,
-		OfferedItems: offer.OfferedItems,
-		Message:      offer.GetMessage(),
-		Status:       offer.Status,
-		DeclineNote:  offer.GetDeclineNote(),
-		CreatedAt:    offer.CreatedAt,
-		UpdatedAt:    offer.UpdatedAt,
-		AcceptedAt:   offer.AcceptedAt,
+		ID:             offer.ID,
+		Type:           offer.Type,
+		ListingID:      offer.GetListingID(),
+		ServiceID:      offer.GetServiceID(),
+		RequesterID:    offer.RequesterID,
+		OfferedItems:   offer.OfferedItems,
+		EstimatedValue: s.estimateOfferedValue(offer.OfferedItems),
+		Message:        offer.GetMessage(),
+		Status:         offer.Status,
+		DeclineNote:    offer.GetDeclineNote(),
+		CreatedAt:      offer.CreatedAt,
+		UpdatedAt:      offer.UpdatedAt,
+		AcceptedAt:     offer.AcceptedAt,
+		ParentOfferID:  offer.ParentOfferID,
 	}
+	resp.RequestedAddition = offer.RequestedAddition
 
 	if offer.Listing != nil {
 		resp.Listing = s.listingService.ToResponse(offer.Listing)
+		resp.ListingChanged = offer.IsPending() && offer.ListingChanged()
 	}
 
 	if offer.Service != nil && s.serviceService != nil {
@@ -419,29 +995,59 @@ func (s *OfferService) ToDetailResponse(offer *models.Offer, userID string) *dto
 	}
 }
 
-// isOfferParticipant checks if the user is a participant in the offer
-func (s *OfferService) isOfferParticipant(offer *models.Offer, userID string) bool {
-	if offer.RequesterID == userID {
-		return true
+// isOfferParticipant checks if the user is a participant in the offer. Counteroffers keep
+// the original requester and listing/service, so both parties of a counter chain qualify.
+func (s *OfferService) isOfferParticipant(ctx context.Context, offer *models.Offer, userID string) bool {
+	return offer.RequesterID == userID || s.isOfferOwner(ctx, offer, userID)
+}
+
+// canRespond checks if the user may accept or reject the offer: the seller/provider (or
+// their delegate) for offers, the requester for counteroffers
+func (s *OfferService) canRespond(ctx context.Context, offer *models.Offer, userID string) bool {
+	if offer.IsCounter() {
+		return offer.RequesterID == userID
 	}
-	if offer.IsItemOffer() && offer.Listing != nil && offer.Listing.SellerID == userID {
-		return true
+	return s.isOfferOwner(ctx, offer, userID)
+}
+
+// isProposer checks if the user is on the side that proposed the offer's terms: the
+// requester for offers, the seller/provider (or their delegate) for counteroffers
+func (s *OfferService) isProposer(ctx context.Context, offer *models.Offer, userID string) bool {
+	if offer.IsCounter() {
+		return s.isOfferOwner(ctx, offer, userID)
 	}
-	if offer.IsServiceOffer() && offer.Service != nil && offer.Service.ProviderID == userID {
-		return true
+	return offer.RequesterID == userID
+}
+
+// proposerID returns who proposed the offer's terms and is told how they were answered:
+// the requester, or the seller/provider for counteroffers
+func (s *OfferService) proposerID(offer *models.Offer) string {
+	if offer.IsCounter() {
+		return s.offerOwnerID(offer)
+	}
+	return offer.RequesterID
+}
+
+// isOfferOwner checks if the user is the seller/provider for this offer, or one of their
+// delegates with the respond_offers permission
+func (s *OfferService) isOfferOwner(ctx context.Context, offer *models.Offer, userID string) bool {
+	ownerID := s.offerOwnerID(offer)
+	if ownerID == "" {
+		return false
 	}
-	return false
+	return canActFor(ctx, s.delegateRepo, ownerID, userID, models.DelegatePermissionRespondOffers, "offer_id", offer.ID)
 }
 
-// isOfferOwner checks if the user is the seller/provider for this offer
-func (s *OfferService) isOfferOwner(offer *models.Offer, userID string) bool {
+// offerOwnerID returns the seller/provider the offer was made to, or "" when the
+// listing/service relation isn't loaded
+func (s *OfferService) offerOwnerID(offer *models.Offer) string {
 	if offer.IsItemOffer() && offer.Listing != nil {
-		return offer.Listing.SellerID == userID
+		return offer.Listing.SellerID
 	}
 	if offer.IsServiceOffer() && offer.Service != nil {
-		return offer.Service.ProviderID == userID
+		return offer.Service.ProviderID
 	}
-	return false
+	return ""
 }
 
 // getOfferItemName returns the name of the item/service for this offer
@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
@@ -67,6 +72,7 @@ func TestCreateItemOffer_Success(t *testing.T) {
 	listing := testListing(testListingID, testSellerID)
 	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
 	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	// Notification: the service re-fetches listing for the name
 	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
@@ -85,9 +91,104 @@ func TestCreateItemOffer_Success(t *testing.T) {
 	assert.Equal(t, "item", offer.Type)
 	assert.Equal(t, testBuyerID, offer.RequesterID)
 	assert.Equal(t, testListingID, *offer.ListingID)
+	require.NotNil(t, offer.ListingHash)
+	assert.Equal(t, listing.ContentHash(), *offer.ListingHash)
 	offerRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Offer"))
 }
 
+func TestCreateItemOffer_WithRequestedAddition(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	addition := json.RawMessage(`[{"name":"Ist","type":"rune","quantity":2}]`)
+	req := &dto.CreateOfferRequest{
+		Type:              "item",
+		ListingID:         strPtr(testListingID),
+		OfferedItems:      json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+		RequestedAddition: addition,
+	}
+
+	offer, err := svc.Create(ctx, testBuyerID, req)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, string(addition), string(offer.RequestedAddition))
+	assert.JSONEq(t, string(addition), string(svc.ToResponse(offer).RequestedAddition))
+}
+
+func TestCreateItemOffer_EmptyRequestedAdditionIsDropped(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:              "item",
+		ListingID:         strPtr(testListingID),
+		OfferedItems:      json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+		RequestedAddition: json.RawMessage(`[]`),
+	}
+
+	offer, err := svc.Create(ctx, testBuyerID, req)
+
+	require.NoError(t, err)
+	assert.Nil(t, offer.RequestedAddition)
+}
+
+func TestCreateOffer_InvalidItemLists(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   *dto.CreateOfferRequest
+		field string
+	}{
+		{
+			name:  "offered items not a list",
+			req:   &dto.CreateOfferRequest{Type: "item", ListingID: strPtr(testListingID), OfferedItems: json.RawMessage(`{"name":"Ber"}`)},
+			field: "offeredItems",
+		},
+		{
+			name:  "addition item without a name",
+			req:   &dto.CreateOfferRequest{Type: "item", ListingID: strPtr(testListingID), OfferedItems: json.RawMessage(`[]`), RequestedAddition: json.RawMessage(`[{"quantity":1}]`)},
+			field: "requestedAddition",
+		},
+		{
+			name:  "addition with negative quantity",
+			req:   &dto.CreateOfferRequest{Type: "item", ListingID: strPtr(testListingID), OfferedItems: json.RawMessage(`[]`), RequestedAddition: json.RawMessage(`[{"name":"Ist","quantity":-1}]`)},
+			field: "requestedAddition",
+		},
+		{
+			name:  "addition on a service offer",
+			req:   &dto.CreateOfferRequest{Type: "service", ServiceID: strPtr(testServiceID), OfferedItems: json.RawMessage(`[]`), RequestedAddition: json.RawMessage(`[{"name":"Ist","quantity":1}]`)},
+			field: "requestedAddition",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, offerRepo, listingRepo, _, _, _, _, _ := newOfferTestService()
+
+			_, err := svc.Create(context.Background(), testBuyerID, tc.req)
+
+			var errs ValidationErrors
+			require.ErrorAs(t, err, &errs)
+			assert.Contains(t, errs, tc.field)
+			listingRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+			offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestCreateItemOffer_MissingListingID(t *testing.T) {
 	svc, _, _, _, _, _, _, _ := newOfferTestService()
 	ctx := context.Background()
@@ -155,6 +256,66 @@ func TestCreateItemOffer_InactiveListing(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidState)
 }
 
+func TestCreateItemOffer_ReservedForAnotherBuyer(t *testing.T) {
+	svc, offerRepo, listingRepo, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withReservation(testUserID, time.Now().Add(time.Hour)))
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	}
+
+	_, err := svc.Create(ctx, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateItemOffer_ReservedForRequester(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(time.Hour)))
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+	}
+
+	offer, err := svc.Create(ctx, testBuyerID, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "pending", offer.Status)
+}
+
+func TestCreateItemOffer_ReservationLapsed(t *testing.T) {
+	svc, _, listingRepo, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(-time.Minute)))
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	}
+
+	_, err := svc.Create(ctx, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
 func TestCreateItemOffer_ActiveTradeExists(t *testing.T) {
 	svc, _, listingRepo, _, tradeRepo, _, _, _ := newOfferTestService()
 	ctx := context.Background()
@@ -174,6 +335,50 @@ func TestCreateItemOffer_ActiveTradeExists(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidState)
 }
 
+func TestCreateItemOffer_QueueFull_DefaultLimit(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listing.MaxPendingOffers = intPtr(DefaultMaxPendingOffers)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(DefaultMaxPendingOffers, nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	}
+
+	_, err := svc.Create(ctx, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrOfferQueueFull)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateItemOffer_QueueFull_SellerLimit(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listing.MaxPendingOffers = intPtr(3)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(3, nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	}
+
+	_, err := svc.Create(ctx, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrOfferQueueFull)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestCreateItemOffer_NotifiesSeller(t *testing.T) {
 	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
 	ctx := context.Background()
@@ -181,6 +386,7 @@ func TestCreateItemOffer_NotifiesSeller(t *testing.T) {
 	listing := testListing(testListingID, testSellerID)
 	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
 	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
@@ -294,181 +500,1033 @@ func TestCreateServiceOffer_InactiveService(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidState)
 }
 
-// ---------- Accept Item Offer ----------
+// ---------- Create From Template ----------
 
-func TestAcceptItemOffer_CreatesTradeAndChat(t *testing.T) {
-	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+func TestCreateFromTemplate_ItemOffer(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	templateRepo := new(mocks.MockOfferTemplateRepository)
+	svc.SetOfferTemplateRepository(templateRepo)
 	ctx := context.Background()
 
+	template := testOfferTemplate(testOfferTemplateID, testBuyerID)
+	templateRepo.On("GetByID", ctx, testOfferTemplateID).Return(template, nil)
 	listing := testListing(testListingID, testSellerID)
-	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
-
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
-	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
-	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
-	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	returnedOffer, trade, serviceRun, chat, err := svc.Accept(ctx, testOfferID, testSellerID)
+	offer, err := svc.CreateFromTemplate(ctx, testBuyerID, testListingID, testOfferTemplateID)
 
 	require.NoError(t, err)
-	assert.Equal(t, "accepted", returnedOffer.Status)
-	assert.NotNil(t, returnedOffer.AcceptedAt)
-	assert.NotNil(t, trade)
-	assert.Equal(t, "active", trade.Status)
-	assert.Equal(t, testSellerID, trade.SellerID)
-	assert.Equal(t, testBuyerID, trade.BuyerID)
-	assert.Nil(t, serviceRun)
-	assert.NotNil(t, chat)
-	tradeRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Trade"))
-	chatRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Chat"))
+	assert.Equal(t, "item", offer.Type)
+	assert.Equal(t, testListingID, *offer.ListingID)
+	assert.JSONEq(t, string(template.OfferedItems), string(offer.OfferedItems))
 }
 
-func TestAcceptItemOffer_NotOwner(t *testing.T) {
-	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+func TestCreateFromTemplate_ServiceOffer(t *testing.T) {
+	svc, offerRepo, listingRepo, serviceRepo, _, _, _, notifRepo := newOfferTestService()
+	templateRepo := new(mocks.MockOfferTemplateRepository)
+	svc.SetOfferTemplateRepository(templateRepo)
 	ctx := context.Background()
 
-	listing := testListing(testListingID, testSellerID)
-	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	templateRepo.On("GetByID", ctx, testOfferTemplateID).Return(testOfferTemplate(testOfferTemplateID, testClientID), nil)
+	listingRepo.On("GetByID", ctx, testServiceID).Return(nil, sql.ErrNoRows)
+	serviceRepo.On("GetByID", ctx, testServiceID).Return(testServiceModel(testServiceID, testProviderID), nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offer, err := svc.CreateFromTemplate(ctx, testClientID, testServiceID, testOfferTemplateID)
 
-	// Someone other than the seller tries to accept
-	_, _, _, _, err := svc.Accept(ctx, testOfferID, "stranger-999")
+	require.NoError(t, err)
+	assert.Equal(t, "service", offer.Type)
+	assert.Equal(t, testServiceID, *offer.ServiceID)
+}
+
+func TestCreateFromTemplate_TemplateOfAnotherUser(t *testing.T) {
+	svc, offerRepo, listingRepo, _, _, _, _, _ := newOfferTestService()
+	templateRepo := new(mocks.MockOfferTemplateRepository)
+	svc.SetOfferTemplateRepository(templateRepo)
+	ctx := context.Background()
+
+	templateRepo.On("GetByID", ctx, testOfferTemplateID).Return(testOfferTemplate(testOfferTemplateID, "other-user"), nil)
+
+	_, err := svc.CreateFromTemplate(ctx, testBuyerID, testListingID, testOfferTemplateID)
 
 	assert.ErrorIs(t, err, ErrForbidden)
+	listingRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }
 
-func TestAcceptItemOffer_NotPending(t *testing.T) {
-	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+func TestCreateFromTemplate_RunsCreateValidation(t *testing.T) {
+	svc, offerRepo, listingRepo, _, _, _, _, _ := newOfferTestService()
+	templateRepo := new(mocks.MockOfferTemplateRepository)
+	svc.SetOfferTemplateRepository(templateRepo)
 	ctx := context.Background()
 
-	listing := testListing(testListingID, testSellerID)
-	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID),
-		withOfferListing(listing),
-		withOfferStatus("rejected"),
-	)
+	templateRepo.On("GetByID", ctx, testOfferTemplateID).Return(testOfferTemplate(testOfferTemplateID, testSellerID), nil)
+	listingRepo.On("GetByID", ctx, testListingID).Return(testListing(testListingID, testSellerID), nil)
 
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	_, err := svc.CreateFromTemplate(ctx, testSellerID, testListingID, testOfferTemplateID)
 
-	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+	assert.ErrorIs(t, err, ErrSelfAction)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
 
-	assert.ErrorIs(t, err, ErrInvalidState)
+// ---------- Buyer Requirements ----------
+
+// withBuyerProfile backs the offer service's profile lookups with the given profile
+func withBuyerProfile(svc *OfferService, profile *models.Profile) {
+	profileRepo := new(mocks.MockProfileRepository)
+	profileRepo.On("GetByID", mock.Anything, profile.ID).Return(profile, nil)
+	svc.profileService = NewProfileService(profileRepo, nil, nil)
 }
 
-func TestAcceptItemOffer_ActiveTradeBlocks(t *testing.T) {
-	svc, offerRepo, _, _, tradeRepo, _, _, _ := newOfferTestService()
+func TestCreateItemOffer_ReputationTooLow(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, _ := newOfferTestService()
 	ctx := context.Background()
 
 	listing := testListing(testListingID, testSellerID)
-	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
-
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
-	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
-	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(true, nil)
+	listing.MinBuyerRating = float64Ptr(4.8)
+	withBuyerProfile(svc, testProfile(testBuyerID)) // 4.5 average
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
 
-	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+	_, err := svc.Create(ctx, testBuyerID, &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+	})
 
-	assert.ErrorIs(t, err, ErrInvalidState)
+	assert.ErrorIs(t, err, ErrReputationTooLow)
+	tradeRepo.AssertNotCalled(t, "HasActiveTradeForListing", mock.Anything, mock.Anything)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 }
 
-// ---------- Accept Service Offer ----------
+func TestCreateItemOffer_UnratedBuyer(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowUnrated bool
+		wantErr      error
+	}{
+		{"rejected by default", false, ErrReputationTooLow},
+		{"allowed when seller opts in", true, nil},
+	}
 
-func TestAcceptServiceOffer_CreatesServiceRunAndChat(t *testing.T) {
-	svc, offerRepo, _, _, _, chatRepo, serviceRunRepo, notifRepo := newOfferTestService()
-	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+			ctx := context.Background()
+
+			listing := testListing(testListingID, testSellerID)
+			listing.MinBuyerRating = float64Ptr(4)
+			listing.MinBuyerTrades = intPtr(10)
+			listing.AllowUnratedBuyers = tt.allowUnrated
+			withBuyerProfile(svc, testProfile(testBuyerID, func(p *models.Profile) {
+				p.AverageRating = 0
+				p.RatingCount = 0
+				p.TotalTrades = 0
+			}))
+			listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+			tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+			listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+			offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+			notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+			_, err := svc.Create(ctx, testBuyerID, &dto.CreateOfferRequest{
+				Type:         "item",
+				ListingID:    strPtr(testListingID),
+				OfferedItems: json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
 
-	service := testServiceModel(testServiceID, testProviderID)
-	offer := testOffer(testOfferID, testClientID, nil, withOfferService(service))
+func TestCreateItemOffer_ReservedBuyerSkipsRequirements(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
 
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
-	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
-	serviceRunRepo.On("Create", ctx, mock.AnythingOfType("*models.ServiceRun")).Return(nil)
-	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	listing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(time.Hour)))
+	listing.MinBuyerTrades = intPtr(100)
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	listingRepo.On("CountPendingOffers", ctx, testListingID).Return(0, nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	returnedOffer, trade, serviceRun, chat, err := svc.Accept(ctx, testOfferID, testProviderID)
+	_, err := svc.Create(ctx, testBuyerID, &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[{"name":"Ber","quantity":1}]`),
+	})
 
 	require.NoError(t, err)
-	assert.Equal(t, "accepted", returnedOffer.Status)
-	assert.NotNil(t, returnedOffer.AcceptedAt)
-	assert.Nil(t, trade)
-	assert.NotNil(t, serviceRun)
-	assert.Equal(t, "active", serviceRun.Status)
-	assert.Equal(t, testProviderID, serviceRun.ProviderID)
-	assert.Equal(t, testClientID, serviceRun.ClientID)
-	assert.NotNil(t, chat)
-	serviceRunRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.ServiceRun"))
-	chatRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Chat"))
 }
 
-// ---------- Reject ----------
+func TestCreateServiceOffer_ReputationTooLow(t *testing.T) {
+	svc, offerRepo, _, serviceRepo, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
 
-func TestRejectOffer_Success(t *testing.T) {
-	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	service := testServiceModel(testServiceID, testProviderID)
+	service.MinBuyerTrades = intPtr(20)
+	withBuyerProfile(svc, testProfile(testClientID)) // 5 trades
+	serviceRepo.On("GetByID", ctx, testServiceID).Return(service, nil)
+
+	_, err := svc.Create(ctx, testClientID, &dto.CreateOfferRequest{
+		Type:         "service",
+		ServiceID:    strPtr(testServiceID),
+		OfferedItems: json.RawMessage(`[]`),
+	})
+
+	assert.ErrorIs(t, err, ErrReputationTooLow)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ---------- Delegates ----------
+
+func TestIsOfferOwner_Delegate(t *testing.T) {
+	svc, _, _, _, _, _, _, _ := newOfferTestService()
+	delegateRepo := new(mocks.MockDelegateRepository)
+	svc.SetDelegateRepository(delegateRepo)
 	ctx := context.Background()
 
 	listing := testListing(testListingID, testSellerID)
 	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
 
-	declineReason := &models.DeclineReason{ID: 1, Code: "low_offer", Message: "Offer too low"}
+	delegateRepo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).
+		Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testUserID, Permissions: []string{models.DelegatePermissionRespondOffers}}, nil)
+	delegateRepo.On("GetByOwnerAndDelegate", ctx, testSellerID, testProviderID).
+		Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testProviderID, Permissions: []string{models.DelegatePermissionManageListings}}, nil)
+	delegateRepo.On("GetByOwnerAndDelegate", ctx, testSellerID, testClientID).Return(nil, sql.ErrNoRows)
+
+	assert.True(t, svc.isOfferOwner(ctx, offer, testSellerID))
+	assert.True(t, svc.isOfferOwner(ctx, offer, testUserID), "delegate with respond_offers")
+	assert.False(t, svc.isOfferOwner(ctx, offer, testProviderID), "delegate without respond_offers")
+	assert.False(t, svc.isOfferOwner(ctx, offer, testClientID), "not a delegate")
+	assert.True(t, svc.isOfferParticipant(ctx, offer, testUserID))
+}
 
-	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+func TestAcceptOffer_RequesterCannotAcceptOwnOffer(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testBuyerID)
+
+	assert.ErrorIs(t, err, ErrSelfAction)
+	offerRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCreateOffer_DelegateCannotOfferOnManagedShop(t *testing.T) {
+	svc, offerRepo, listingRepo, serviceRepo, _, _, _, _ := newOfferTestService()
+	delegateRepo := new(mocks.MockDelegateRepository)
+	svc.SetDelegateRepository(delegateRepo)
+	ctx := context.Background()
+
+	listingRepo.On("GetByID", ctx, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	serviceRepo.On("GetByID", ctx, testServiceID).Return(testServiceModel(testServiceID, testSellerID), nil)
+	delegateRepo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).
+		Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testUserID, Permissions: []string{models.DelegatePermissionManageListings}}, nil)
+
+	_, err := svc.Create(ctx, testUserID, &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	})
+	assert.ErrorIs(t, err, ErrSelfAction)
+
+	_, err = svc.Create(ctx, testUserID, &dto.CreateOfferRequest{
+		Type:         "service",
+		ServiceID:    strPtr(testServiceID),
+		OfferedItems: json.RawMessage(`[]`),
+	})
+	assert.ErrorIs(t, err, ErrSelfAction)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRejectOffer_DelegateCanUseSellerTemplate(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	delegateRepo := new(mocks.MockDelegateRepository)
+	templateRepo := new(mocks.MockDeclineTemplateRepository)
+	svc.SetDelegateRepository(delegateRepo)
+	svc.SetDeclineTemplateRepository(templateRepo)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	template := &models.DeclineTemplate{ID: "tpl-1", SellerID: testSellerID, Message: "Price is firm"}
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("GetDeclineReasonByID", ctx, 1).Return(&models.DeclineReason{ID: 1}, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	templateRepo.On("GetByID", ctx, "tpl-1").Return(template, nil)
+	delegateRepo.On("GetByOwnerAndDelegate", ctx, testSellerID, testUserID).
+		Return(&models.Delegate{OwnerID: testSellerID, DelegateID: testUserID, Permissions: []string{models.DelegatePermissionRespondOffers}}, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	rejected, err := svc.Reject(ctx, testOfferID, testUserID, &dto.RejectOfferRequest{
+		DeclineReasonID:   1,
+		DeclineTemplateID: strPtr("tpl-1"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", rejected.Status)
+	require.NotNil(t, rejected.DeclineNote)
+	assert.Equal(t, "Price is firm", *rejected.DeclineNote)
+}
+
+// ---------- Accept Item Offer ----------
+
+func TestAcceptItemOffer_CreatesTradeAndChat(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	returnedOffer, trade, serviceRun, chat, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", returnedOffer.Status)
+	assert.NotNil(t, returnedOffer.AcceptedAt)
+	assert.NotNil(t, trade)
+	assert.Equal(t, "active", trade.Status)
+	assert.Equal(t, testSellerID, trade.SellerID)
+	assert.Equal(t, testBuyerID, trade.BuyerID)
+	assert.Nil(t, serviceRun)
+	assert.NotNil(t, chat)
+	tradeRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Trade"))
+	chatRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Chat"))
+}
+
+func TestAcceptItemOffer_NotOwner(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	// Someone other than the seller tries to accept
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, "stranger-999")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestAcceptItemOffer_NotPending(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID),
+		withOfferListing(listing),
+		withOfferStatus("rejected"),
+	)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestAcceptItemOffer_ListingChangedBlocks(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	hash := listing.ContentHash()
+	offer.ListingHash = &hash
+
+	// Seller swaps the item after the offer was made
+	listing.Name = "Harlequin Crest"
+	listing.Stats = json.RawMessage(`[{"code":"all_skills","value":1}]`)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	notifRepo.On("Create", ctx, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testBuyerID && n.Type == models.NotificationTypeOfferListingChanged
+	})).Return(nil)
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrListingChanged)
+	assert.Equal(t, "pending", offer.Status)
+	assert.True(t, svc.ToResponse(offer).ListingChanged)
+	offerRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	tradeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestAcceptItemOffer_ListingReformattedStillAccepts(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Stats = json.RawMessage(`[{"code":"ed","value":141}]`)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	hash := listing.ContentHash()
+	offer.ListingHash = &hash
+
+	// Same item, only the JSON formatting and notes differ
+	listing.Stats = json.RawMessage(`[ { "value": 141, "code": "ed" } ]`)
+	listing.Notes = strPtr("price lowered")
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	returnedOffer, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", returnedOffer.Status)
+}
+
+func TestAcceptItemOffer_ActiveTradeBlocks(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(true, nil)
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+// fakeTx stands in for the database transaction: it records whether fn's writes would
+// have been committed or rolled back.
+type fakeTx struct {
+	active     bool
+	committed  bool
+	rolledBack bool
+	// commitErr makes the commit fail after fn succeeded
+	commitErr error
+}
+
+func (f *fakeTx) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.active = true
+	err := fn(ctx)
+	f.active = false
+	if err == nil {
+		err = f.commitErr
+	}
+	if err != nil {
+		f.rolledBack = true
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+func TestAcceptItemOffer_RollsBackWhenChatCreateFails(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{}
+	svc.db = tx
+	svc.SetPauseListingOnAccept(true)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	inTx := func(mock.Arguments) { assert.True(t, tx.active, "write ran outside the transaction") }
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Run(inTx).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Run(inTx).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Run(inTx).Return(errors.New("connection reset"))
+
+	returnedOffer, trade, _, chat, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.Error(t, err)
+	assert.Nil(t, returnedOffer)
+	assert.Nil(t, trade)
+	assert.Nil(t, chat)
+	assert.True(t, tx.rolledBack)
+	assert.False(t, tx.committed)
+	offerRepo.AssertCalled(t, "Update", ctx, mock.AnythingOfType("*models.Offer"))
+	tradeRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Trade"))
+	// Nothing that assumes a committed accept may run
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	assert.Equal(t, "active", listing.Status)
+}
+
+func TestAcceptItemOffer_CommitsBeforeNotifying(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{}
+	svc.db = tx
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).
+		Run(func(mock.Arguments) { assert.True(t, tx.committed, "notified before commit") }).
+		Return(nil)
+
+	_, trade, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.NoError(t, err)
+	assert.NotNil(t, trade)
+	assert.True(t, tx.committed)
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestAcceptItemOffer_RejectsSiblingOffers(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{}
+	svc.db = tx
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	sibling := testOffer("offer-sibling", "buyer-other", strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			assert.True(t, tx.active, "siblings rejected outside the accept transaction")
+			sibling.Status = "rejected"
+			sibling.DeclineNote = strPtr(args.String(3))
+		}).
+		Return([]*models.Offer{{ID: sibling.ID, RequesterID: sibling.RequesterID}}, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	accepted, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", accepted.Status)
+	assert.Equal(t, "rejected", sibling.Status)
+	assert.Equal(t, siblingDeclineNote, sibling.GetDeclineNote())
+	notifRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == "buyer-other" && n.Type == models.NotificationTypeTradeRequestRejected &&
+			n.ReferenceID != nil && *n.ReferenceID == "offer-sibling"
+	}))
+	notifRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestAcceptItemOffer_SiblingRejectFailureRollsBack(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{}
+	svc.db = tx
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).
+		Return(nil, errors.New("deadlock detected"))
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.Error(t, err)
+	assert.True(t, tx.rolledBack)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAcceptItemOffer_PausesListing(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.listingService = NewListingService(listingRepo, svc.profileService, redisClient)
+	svc.SetPauseListingOnAccept(true)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withReservation(testBuyerID, time.Now().Add(time.Hour)))
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	// The listing is on the home page before the offer is accepted
+	svc.listingService.pushToRecentListings(ctx, listing)
+	recent, err := redisClient.LRange(ctx, cache.HomeRecentKey(), 0, -1)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, _, _, _, err = svc.Accept(ctx, testOfferID, testSellerID)
+	require.NoError(t, err)
+
+	// The reservation is kept so a cancelled trade can hand it back
+	listingRepo.AssertCalled(t, "Update", ctx, mock.MatchedBy(func(l *models.Listing) bool {
+		return l.ID == testListingID && l.Status == "pending" && l.GetReservedFor() == testBuyerID && l.ReservedUntil != nil
+	}))
+
+	// Hidden from browse while the trade runs
+	recent, err = redisClient.LRange(ctx, cache.HomeRecentKey(), 0, -1)
+	require.NoError(t, err)
+	assert.Empty(t, recent)
+}
+
+func TestAcceptItemOffer_HoldFailureRollsBack(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.listingService = NewListingService(listingRepo, svc.profileService, redisClient)
+	svc.SetPauseListingOnAccept(true)
+	tx := &fakeTx{}
+	svc.db = tx
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	svc.listingService.pushToRecentListings(ctx, listing)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	listingRepo.On("Update", ctx, mock.AnythingOfType("*models.Listing")).Return(errors.New("db error"))
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.Error(t, err)
+	assert.True(t, tx.rolledBack)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// The rolled-back accept leaves the listing on the home page
+	recent, err := redisClient.LRange(ctx, cache.HomeRecentKey(), 0, -1)
+	require.NoError(t, err)
+	assert.Len(t, recent, 1)
+}
+
+func TestAcceptItemOffer_KeepsListingStatusWhenPauseDisabled(t *testing.T) {
+	svc, offerRepo, listingRepo, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	_, _, _, _, err := svc.Accept(ctx, testOfferID, testSellerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "active", listing.Status)
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCreateItemOffer_PendingListingRejectsOffers(t *testing.T) {
+	svc, offerRepo, listingRepo, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withListingStatus("pending"))
+	listingRepo.On("GetByID", ctx, testListingID).Return(listing, nil)
+
+	req := &dto.CreateOfferRequest{
+		Type:         "item",
+		ListingID:    strPtr(testListingID),
+		OfferedItems: json.RawMessage(`[]`),
+	}
+
+	_, err := svc.Create(ctx, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ---------- Accept Service Offer ----------
+
+func TestAcceptServiceOffer_CreatesServiceRunAndChat(t *testing.T) {
+	svc, offerRepo, _, _, _, chatRepo, serviceRunRepo, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	service := testServiceModel(testServiceID, testProviderID)
+	offer := testOffer(testOfferID, testClientID, nil, withOfferService(service))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	serviceRunRepo.On("Create", ctx, mock.AnythingOfType("*models.ServiceRun")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	returnedOffer, trade, serviceRun, chat, err := svc.Accept(ctx, testOfferID, testProviderID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", returnedOffer.Status)
+	assert.NotNil(t, returnedOffer.AcceptedAt)
+	assert.Nil(t, trade)
+	assert.NotNil(t, serviceRun)
+	assert.Equal(t, "active", serviceRun.Status)
+	assert.Equal(t, testProviderID, serviceRun.ProviderID)
+	assert.Equal(t, testClientID, serviceRun.ClientID)
+	assert.NotNil(t, chat)
+	serviceRunRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.ServiceRun"))
+	chatRepo.AssertCalled(t, "Create", ctx, mock.AnythingOfType("*models.Chat"))
+}
+
+// ---------- Reject ----------
+
+func TestRejectOffer_Success(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	declineReason := &models.DeclineReason{ID: 1, Code: "low_offer", Message: "Offer too low"}
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
 	offerRepo.On("GetDeclineReasonByID", ctx, 1).Return(declineReason, nil)
 	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
 	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
 	req := &dto.RejectOfferRequest{
-		DeclineReasonID: 1,
-		DeclineNote:     "Need more runes",
+		DeclineReasonID: 1,
+		DeclineNote:     "Need more runes",
+	}
+
+	result, err := svc.Reject(ctx, testOfferID, testSellerID, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", result.Status)
+	assert.Equal(t, intPtr(1), result.DeclineReasonID)
+	assert.Equal(t, "Need more runes", *result.DeclineNote)
+}
+
+func TestRejectOffer_NotOwner(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	req := &dto.RejectOfferRequest{DeclineReasonID: 1}
+
+	_, err := svc.Reject(ctx, testOfferID, "stranger-999", req)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	// The requester answering their own offer is a self action, not a permission problem
+	_, err = svc.Reject(ctx, testOfferID, testBuyerID, req)
+	assert.ErrorIs(t, err, ErrSelfAction)
+	offerRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRejectOffer_NotPending(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID),
+		withOfferListing(listing),
+		withOfferStatus("accepted"),
+	)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	req := &dto.RejectOfferRequest{DeclineReasonID: 1}
+
+	_, err := svc.Reject(ctx, testOfferID, testSellerID, req)
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestRejectOffer_InvalidDeclineReason(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("GetDeclineReasonByID", ctx, 999).Return(nil, errors.New("not found"))
+
+	req := &dto.RejectOfferRequest{DeclineReasonID: 999}
+
+	_, err := svc.Reject(ctx, testOfferID, testSellerID, req)
+
+	assert.Error(t, err)
+}
+
+func TestRejectOffer_WithDeclineTemplate(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	templateRepo := new(mocks.MockDeclineTemplateRepository)
+	svc.SetDeclineTemplateRepository(templateRepo)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	template := testDeclineTemplate(testDeclineTemplateID, testSellerID)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("GetDeclineReasonByID", ctx, 1).Return(&models.DeclineReason{ID: 1}, nil)
+	templateRepo.On("GetByID", ctx, testDeclineTemplateID).Return(template, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	req := &dto.RejectOfferRequest{
+		DeclineReasonID:   1,
+		DeclineTemplateID: strPtr(testDeclineTemplateID),
 	}
 
 	result, err := svc.Reject(ctx, testOfferID, testSellerID, req)
 
 	require.NoError(t, err)
 	assert.Equal(t, "rejected", result.Status)
-	assert.Equal(t, intPtr(1), result.DeclineReasonID)
-	assert.Equal(t, "Need more runes", *result.DeclineNote)
+	assert.Equal(t, template.Message, result.GetDeclineNote())
 }
 
-func TestRejectOffer_NotOwner(t *testing.T) {
+func TestRejectOffer_DeclineTemplateOfAnotherSeller(t *testing.T) {
 	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	templateRepo := new(mocks.MockDeclineTemplateRepository)
+	svc.SetDeclineTemplateRepository(templateRepo)
 	ctx := context.Background()
 
 	listing := testListing(testListingID, testSellerID)
 	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
 
 	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("GetDeclineReasonByID", ctx, 1).Return(&models.DeclineReason{ID: 1}, nil)
+	templateRepo.On("GetByID", ctx, testDeclineTemplateID).Return(testDeclineTemplate(testDeclineTemplateID, "other-seller"), nil)
 
-	req := &dto.RejectOfferRequest{DeclineReasonID: 1}
+	req := &dto.RejectOfferRequest{
+		DeclineReasonID:   1,
+		DeclineTemplateID: strPtr(testDeclineTemplateID),
+	}
 
-	// Buyer (the requester) tries to reject -- not the seller
-	_, err := svc.Reject(ctx, testOfferID, testBuyerID, req)
+	_, err := svc.Reject(ctx, testOfferID, testSellerID, req)
 
 	assert.ErrorIs(t, err, ErrForbidden)
+	offerRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 }
 
-func TestRejectOffer_NotPending(t *testing.T) {
+// ---------- Bulk Reject ----------
+
+func TestBulkReject_ReportsEachOffer(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	otherListing := testListing("listing-other", "seller-other")
+	pending := testOffer("offer-a", testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	notMine := testOffer("offer-b", testBuyerID, strPtr("listing-other"), withOfferListing(otherListing))
+	accepted := testOffer("offer-c", testBuyerID, strPtr(testListingID), withOfferListing(listing), withOfferStatus("accepted"))
+
+	offerRepo.On("GetDeclineReasonByID", ctx, 1).Return(&models.DeclineReason{ID: 1, Code: "low_offer"}, nil).Once()
+	offerRepo.On("GetByIDWithRelations", ctx, "offer-a").Return(pending, nil)
+	offerRepo.On("GetByIDWithRelations", ctx, "offer-b").Return(notMine, nil)
+	offerRepo.On("GetByIDWithRelations", ctx, "offer-c").Return(accepted, nil)
+	offerRepo.On("GetByIDWithRelations", ctx, "offer-d").Return(nil, sql.ErrNoRows)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil).Once()
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil).Once()
+
+	req := &dto.RejectOfferRequest{DeclineReasonID: 1, DeclineNote: "Too many offers"}
+	resp, err := svc.BulkReject(ctx, testSellerID, []string{"offer-a", "offer-b", "offer-c", "offer-d", "offer-a"}, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Rejected)
+	assert.Equal(t, 3, resp.Failed)
+	require.Len(t, resp.Results, 4)
+	assert.Equal(t, BulkRejectRejected, resp.Results[0].Status)
+	require.NotNil(t, resp.Results[0].Offer)
+	assert.Equal(t, "Too many offers", resp.Results[0].Offer.DeclineNote)
+	assert.Equal(t, BulkRejectForbidden, resp.Results[1].Status)
+	assert.Equal(t, BulkRejectNotPending, resp.Results[2].Status)
+	assert.Equal(t, BulkRejectNotFound, resp.Results[3].Status)
+	assert.Equal(t, "pending", notMine.Status)
+	offerRepo.AssertExpectations(t)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestBulkReject_InvalidDeclineReasonStopsEarly(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	offerRepo.On("GetDeclineReasonByID", ctx, 99).Return(nil, sql.ErrNoRows)
+
+	_, err := svc.BulkReject(ctx, testSellerID, []string{"offer-a"}, &dto.RejectOfferRequest{DeclineReasonID: 99})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	offerRepo.AssertNotCalled(t, "GetByIDWithRelations", mock.Anything, mock.Anything)
+}
+
+func TestBulkReject_TooManyOffers(t *testing.T) {
 	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+
+	ids := make([]string, MaxBulkRejectOffers+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("offer-%d", i)
+	}
+
+	_, err := svc.BulkReject(context.Background(), testSellerID, ids, &dto.RejectOfferRequest{DeclineReasonID: 1})
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "offerIds")
+	offerRepo.AssertNotCalled(t, "GetDeclineReasonByID", mock.Anything, mock.Anything)
+}
+
+// ---------- Counter ----------
+
+const testCounterOfferID = "offer-counter-1"
+
+func withOfferParent(parentID string) func(*models.Offer) {
+	return func(o *models.Offer) { o.ParentOfferID = &parentID }
+}
+
+func TestCounterOffer_CreatesLinkedCounter(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{}
+	svc.db = tx
 	ctx := context.Background()
 
 	listing := testListing(testListingID, testSellerID)
-	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID),
-		withOfferListing(listing),
-		withOfferStatus("accepted"),
-	)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	inTx := func(mock.Arguments) { assert.True(t, tx.active, "write ran outside the transaction") }
 
 	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Run(inTx).Return(nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Run(inTx).Return(nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
 
-	req := &dto.RejectOfferRequest{DeclineReasonID: 1}
+	req := &dto.CounterOfferRequest{
+		OfferedItems: json.RawMessage(`[{"type":"rune","name":"Ber","quantity":1}]`),
+		Message:      "Ber and it's yours",
+	}
+	counter, err := svc.Counter(ctx, testOfferID, testSellerID, req)
 
-	_, err := svc.Reject(ctx, testOfferID, testSellerID, req)
+	require.NoError(t, err)
+	assert.True(t, tx.committed)
+	assert.Equal(t, "countered", offer.Status)
+	assert.Equal(t, "pending", counter.Status)
+	require.NotNil(t, counter.ParentOfferID)
+	assert.Equal(t, testOfferID, *counter.ParentOfferID)
+	assert.Equal(t, testBuyerID, counter.RequesterID)
+	assert.Equal(t, testListingID, counter.GetListingID())
+	assert.JSONEq(t, string(req.OfferedItems), string(counter.OfferedItems))
+	assert.Equal(t, "Ber and it's yours", counter.GetMessage())
+
+	notifRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testBuyerID && n.Type == models.NotificationTypeOfferCountered &&
+			n.ReferenceID != nil && *n.ReferenceID == counter.ID
+	}))
+}
+
+func TestCounterOffer_FailedCommitLeavesNoCounter(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, notifRepo := newOfferTestService()
+	tx := &fakeTx{commitErr: errors.New("commit failed")}
+	svc.db = tx
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	// Rows written inside the transaction are only kept if it commits
+	var staged, rows []*models.Offer
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	offerRepo.On("Create", ctx, mock.AnythingOfType("*models.Offer")).Run(func(args mock.Arguments) {
+		if tx.active {
+			staged = append(staged, args.Get(1).(*models.Offer))
+		} else {
+			rows = append(rows, args.Get(1).(*models.Offer))
+		}
+	}).Return(nil)
+
+	req := &dto.CounterOfferRequest{OfferedItems: json.RawMessage(`[{"type":"rune","name":"Ber","quantity":1}]`)}
+	_, err := svc.Counter(ctx, testOfferID, testSellerID, req)
+	if tx.committed {
+		rows = append(rows, staged...)
+	}
+
+	require.Error(t, err)
+	assert.True(t, tx.rolledBack)
+	assert.Len(t, staged, 1)
+	assert.Empty(t, rows, "a counter outlived the failed transaction")
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCounterOffer_OnlyOwnerCanCounter(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+
+	req := &dto.CounterOfferRequest{OfferedItems: json.RawMessage(`[{"type":"rune","name":"Ber","quantity":1}]`)}
+	_, err := svc.Counter(ctx, testOfferID, testBuyerID, req)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	offerRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCounterOffer_CounterCannotBeCounteredAgain(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	counter := testOffer(testCounterOfferID, testBuyerID, strPtr(testListingID),
+		withOfferListing(listing),
+		withOfferParent(testOfferID),
+	)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testCounterOfferID).Return(counter, nil)
+
+	req := &dto.CounterOfferRequest{OfferedItems: json.RawMessage(`[{"type":"rune","name":"Jah","quantity":1}]`)}
+	_, err := svc.Counter(ctx, testCounterOfferID, testSellerID, req)
 
 	assert.ErrorIs(t, err, ErrInvalidState)
 }
 
-func TestRejectOffer_InvalidDeclineReason(t *testing.T) {
+func TestCounterOffer_RequiresItems(t *testing.T) {
 	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
 	ctx := context.Background()
 
@@ -476,13 +1534,66 @@ func TestRejectOffer_InvalidDeclineReason(t *testing.T) {
 	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
 
 	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
-	offerRepo.On("GetDeclineReasonByID", ctx, 999).Return(nil, errors.New("not found"))
 
-	req := &dto.RejectOfferRequest{DeclineReasonID: 999}
+	_, err := svc.Counter(ctx, testOfferID, testSellerID, &dto.CounterOfferRequest{OfferedItems: json.RawMessage(`[]`)})
 
-	_, err := svc.Reject(ctx, testOfferID, testSellerID, req)
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "offeredItems")
+	assert.Equal(t, "pending", offer.Status)
+}
 
-	assert.Error(t, err)
+func TestAcceptCounterOffer_RequesterAccepts(t *testing.T) {
+	svc, offerRepo, _, _, tradeRepo, chatRepo, _, notifRepo := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	counter := testOffer(testCounterOfferID, testBuyerID, strPtr(testListingID),
+		withOfferListing(listing),
+		withOfferParent(testOfferID),
+	)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testCounterOfferID).Return(counter, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+	tradeRepo.On("HasActiveTradeForListing", ctx, testListingID).Return(false, nil)
+	tradeRepo.On("Create", ctx, mock.AnythingOfType("*models.Trade")).Return(nil)
+	chatRepo.On("Create", ctx, mock.AnythingOfType("*models.Chat")).Return(nil)
+	offerRepo.On("RejectPendingForListing", ctx, testListingID, testCounterOfferID, siblingDeclineNote, mock.AnythingOfType("time.Time")).Return(nil, nil)
+	notifRepo.On("Create", ctx, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	// The seller proposed these terms, so they can't accept them themselves
+	_, _, _, _, err := svc.Accept(ctx, testCounterOfferID, testSellerID)
+	assert.ErrorIs(t, err, ErrSelfAction)
+
+	_, trade, _, _, err := svc.Accept(ctx, testCounterOfferID, testBuyerID)
+
+	require.NoError(t, err)
+	assert.Equal(t, testSellerID, trade.SellerID)
+	assert.Equal(t, testBuyerID, trade.BuyerID)
+	notifRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testSellerID && n.Type == models.NotificationTypeTradeRequestAccepted
+	}))
+}
+
+func TestCancelCounterOffer_OwnerWithdraws(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	counter := testOffer(testCounterOfferID, testBuyerID, strPtr(testListingID),
+		withOfferListing(listing),
+		withOfferParent(testOfferID),
+	)
+
+	offerRepo.On("GetByIDWithRelations", ctx, testCounterOfferID).Return(counter, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+
+	_, err := svc.Cancel(ctx, testCounterOfferID, testBuyerID)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	result, err := svc.Cancel(ctx, testCounterOfferID, testSellerID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", result.Status)
 }
 
 // ---------- Cancel ----------
@@ -535,6 +1646,48 @@ func TestCancelOffer_NotPending(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidState)
 }
 
+// ---------- GetByID caching ----------
+
+func TestOfferGetByID_CacheHitAndInvalidation(t *testing.T) {
+	offerRepo := new(mocks.MockOfferRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	redisClient, mr := newTestRedisReal(t)
+	profileService := NewProfileService(nil, redisClient, nil)
+	svc := NewOfferService(
+		nil, offerRepo, listingRepo, nil, nil, nil, nil,
+		NewNotificationService(nil, redisClient),
+		profileService,
+		NewListingService(listingRepo, profileService, redisClient),
+		nil,
+		redisClient,
+	)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	offer := testOffer(testOfferID, testBuyerID, strPtr(testListingID), withOfferListing(listing))
+	offerRepo.On("GetByIDWithRelations", ctx, testOfferID).Return(offer, nil)
+	offerRepo.On("Update", ctx, mock.AnythingOfType("*models.Offer")).Return(nil)
+
+	_, err := svc.GetByID(ctx, testOfferID, testBuyerID)
+	require.NoError(t, err)
+	assert.True(t, mr.Exists(cache.OfferKey(testOfferID)))
+	assert.True(t, mr.Exists(cache.OfferDTOKey(testOfferID)))
+
+	// Second read is served from cache, participant check still applies
+	cached, err := svc.GetByID(ctx, testOfferID, testSellerID)
+	require.NoError(t, err)
+	assert.Equal(t, testOfferID, cached.ID)
+	offerRepo.AssertNumberOfCalls(t, "GetByIDWithRelations", 1)
+
+	_, err = svc.GetByID(ctx, testOfferID, testUserID)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = svc.Cancel(ctx, testOfferID, testBuyerID)
+	require.NoError(t, err)
+	assert.False(t, mr.Exists(cache.OfferKey(testOfferID)))
+	assert.False(t, mr.Exists(cache.OfferDTOKey(testOfferID)))
+}
+
 // ---------- List ----------
 
 func TestListOffers_SellerDefaultsPending(t *testing.T) {
@@ -581,6 +1734,191 @@ func TestListOffers_BuyerNoDefault(t *testing.T) {
 	offerRepo.AssertCalled(t, "List", ctx, expectedFilter)
 }
 
+func TestListOffersAfter_DecodesCursorAndDefaultsPending(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC)
+	cursor := dto.EncodeCursor(createdAt, testOfferID)
+
+	expectedFilter := repository.OfferFilter{
+		UserID: testSellerID,
+		Role:   "seller",
+		Status: "pending",
+		Limit:  20,
+	}
+	expectedAfter := &repository.PageCursor{CreatedAt: createdAt, ID: testOfferID}
+
+	offerRepo.On("ListAfter", ctx, expectedFilter, mock.MatchedBy(func(after *repository.PageCursor) bool {
+		return after != nil && after.ID == expectedAfter.ID && after.CreatedAt.Equal(expectedAfter.CreatedAt)
+	})).Return([]*models.Offer{}, true, nil)
+
+	offers, hasMore, err := svc.ListAfter(ctx, testSellerID, "seller", "", "", "", "", cursor, 20)
+
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Empty(t, offers)
+	offerRepo.AssertExpectations(t)
+}
+
+func TestListOffersAfter_EmptyCursorStartsAtNewest(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	offerRepo.On("ListAfter", ctx, mock.Anything, (*repository.PageCursor)(nil)).Return([]*models.Offer{}, false, nil)
+
+	_, _, err := svc.ListAfter(ctx, testBuyerID, "buyer", "", "", "", "", "", 20)
+
+	require.NoError(t, err)
+	offerRepo.AssertExpectations(t)
+}
+
+func TestListOffersAfter_InvalidCursor(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+
+	_, _, err := svc.ListAfter(context.Background(), testBuyerID, "buyer", "", "", "", "", "not-a-cursor!", 20)
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	offerRepo.AssertNotCalled(t, "ListAfter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListOffersByValue_RanksHighestFirst(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listingID := testListingID
+	low := testOffer("offer-low", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"rune","name":"Ist","quantity":2}]`)
+	})
+	unvalued := testOffer("offer-unvalued", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"unique","name":"Shako","quantity":1}]`)
+	})
+	high := testOffer("offer-high", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"rune","name":"Ber","quantity":1}]`)
+	})
+
+	// Only the newest MaxValueRankedOffers are loaded for ranking
+	expectedFilter := repository.OfferFilter{
+		UserID: testSellerID,
+		Role:   "seller",
+		Status: "pending",
+		Limit:  MaxValueRankedOffers,
+	}
+	offerRepo.On("List", ctx, expectedFilter).Return([]*models.Offer{unvalued, low, high}, 3, nil)
+
+	offers, count, err := svc.ListByValue(ctx, testSellerID, "", "", "", "", 0, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, offers, 3)
+	assert.Equal(t, "offer-high", offers[0].ID)
+	assert.Equal(t, "offer-low", offers[1].ID)
+	assert.Equal(t, "offer-unvalued", offers[2].ID)
+}
+
+func TestListOffersByValue_Paginates(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	listingID := testListingID
+	a := testOffer("offer-a", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"rune","name":"Jah"}]`)
+	})
+	b := testOffer("offer-b", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"rune","name":"Lo"}]`)
+	})
+
+	offerRepo.On("List", ctx, mock.AnythingOfType("repository.OfferFilter")).Return([]*models.Offer{b, a}, 2, nil)
+
+	offers, _, err := svc.ListByValue(ctx, testSellerID, "", "", "", "", 1, 1)
+
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	assert.Equal(t, "offer-b", offers[0].ID)
+}
+
+func TestEstimateOfferedValue(t *testing.T) {
+	svc, _, _, _, _, _, _, _ := newOfferTestService()
+
+	value := svc.estimateOfferedValue(json.RawMessage(`[{"type":"rune","name":"Ist","quantity":3},{"type":"unique","name":"Shako"}]`))
+	require.NotNil(t, value)
+	assert.InDelta(t, 3.0, *value, 0.001)
+
+	assert.Nil(t, svc.estimateOfferedValue(json.RawMessage(`[{"type":"unique","name":"Shako"}]`)))
+	assert.Nil(t, svc.estimateOfferedValue(nil))
+}
+
+func TestListOffersByValue_UsesInjectedEstimator(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	// Deterministic estimator: everything is worth 1 except Shako
+	svc.SetValueEstimator(games.ValueEstimatorFunc(func(itemType, name string, quantity int) (float64, bool) {
+		if name == "Shako" {
+			return 50, true
+		}
+		return float64(quantity), true
+	}))
+
+	listingID := testListingID
+	runes := testOffer("offer-runes", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"rune","name":"Ber","quantity":2}]`)
+	})
+	shako := testOffer("offer-shako", testBuyerID, &listingID, func(o *models.Offer) {
+		o.OfferedItems = json.RawMessage(`[{"type":"unique","name":"Shako"}]`)
+	})
+	offerRepo.On("List", ctx, mock.AnythingOfType("repository.OfferFilter")).Return([]*models.Offer{runes, shako}, 2, nil)
+
+	offers, _, err := svc.ListByValue(ctx, testSellerID, "", "", "", "", 0, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, "offer-shako", offers[0].ID)
+	assert.InDelta(t, 2.0, *svc.ToResponse(runes).EstimatedValue, 0.001)
+}
+
+func TestListGroupedBySeller_NestsOffersUnderListings(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	shako := testListing(testListingID, testSellerID)
+	arach := testListing("listing-arach", testSellerID, func(l *models.Listing) { l.Name = "Arachnid Mesh" })
+	shakoID, arachID := shako.ID, arach.ID
+	offers := []*models.Offer{
+		testOffer("offer-1", testBuyerID, &shakoID, withOfferListing(shako)),
+		testOffer("offer-2", "buyer-2", &shakoID, withOfferListing(shako)),
+		testOffer("offer-3", testBuyerID, &arachID, withOfferListing(arach)),
+	}
+	// Status defaults to pending
+	offerRepo.On("ListBySellerListings", ctx, testSellerID, "pending", 0, 20).Return(offers, 5, nil)
+
+	groups, count, err := svc.ListGroupedBySeller(ctx, testSellerID, "", 0, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+	require.Len(t, groups, 2)
+	assert.Equal(t, testListingID, groups[0].Listing.ID)
+	assert.Equal(t, 2, groups[0].OfferCount)
+	assert.Equal(t, "offer-1", groups[0].Offers[0].ID)
+	assert.Equal(t, "offer-2", groups[0].Offers[1].ID)
+	assert.Nil(t, groups[0].Offers[0].Listing)
+	assert.Equal(t, "listing-arach", groups[1].Listing.ID)
+	assert.Equal(t, 1, groups[1].OfferCount)
+}
+
+func TestListGroupedBySeller_NoOffers(t *testing.T) {
+	svc, offerRepo, _, _, _, _, _, _ := newOfferTestService()
+	ctx := context.Background()
+
+	offerRepo.On("ListBySellerListings", ctx, testSellerID, "accepted", 20, 20).Return([]*models.Offer{}, 0, nil)
+
+	groups, count, err := svc.ListGroupedBySeller(ctx, testSellerID, "accepted", 20, 20)
+
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.NotNil(t, groups)
+	assert.Empty(t, groups)
+}
+
 // ---------- isOfferParticipant ----------
 
 func TestIsOfferParticipant(t *testing.T) {
@@ -623,7 +1961,7 @@ func TestIsOfferParticipant(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := svc.isOfferParticipant(tc.offer, tc.userID)
+			result := svc.isOfferParticipant(context.Background(), tc.offer, tc.userID)
 			assert.Equal(t, tc.expected, result)
 		})
 	}
@@ -671,7 +2009,7 @@ func TestIsOfferOwner(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := svc.isOfferOwner(tc.offer, tc.userID)
+			result := svc.isOfferOwner(context.Background(), tc.offer, tc.userID)
 			assert.Equal(t, tc.expected, result)
 		})
 	}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// MaxOfferTemplates is how many offer templates a user can save
+const MaxOfferTemplates = 20
+
+// OfferTemplateService handles a user's saved offer templates
+type OfferTemplateService struct {
+	repo repository.OfferTemplateRepository
+}
+
+// NewOfferTemplateService creates a new offer template service
+func NewOfferTemplateService(repo repository.OfferTemplateRepository) *OfferTemplateService {
+	return &OfferTemplateService{repo: repo}
+}
+
+// Create saves a new offer template for the user
+func (s *OfferTemplateService) Create(ctx context.Context, userID string, req *dto.CreateOfferTemplateRequest) (*models.OfferTemplate, error) {
+	count, err := s.repo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxOfferTemplates {
+		return nil, ErrOfferTemplateLimitReached
+	}
+
+	now := time.Now()
+	template := &models.OfferTemplate{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Name:         req.Name,
+		OfferedItems: req.OfferedItems,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.repo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// List returns the user's offer templates, oldest first
+func (s *OfferTemplateService) List(ctx context.Context, userID string) ([]*models.OfferTemplate, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+// Update changes the name or offered items of a user's offer template
+func (s *OfferTemplateService) Update(ctx context.Context, id string, userID string, req *dto.UpdateOfferTemplateRequest) (*models.OfferTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if len(req.OfferedItems) > 0 {
+		template.OfferedItems = req.OfferedItems
+	}
+
+	if err := s.repo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// Delete removes a user's offer template. Offers already made from it keep their items.
+func (s *OfferTemplateService) Delete(ctx context.Context, id string, userID string) error {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if template.UserID != userID {
+		return ErrForbidden
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// ToResponse converts an offer template to its DTO
+func (s *OfferTemplateService) ToResponse(template *models.OfferTemplate) *dto.OfferTemplateResponse {
+	return &dto.OfferTemplateResponse{
+		ID:           template.ID,
+		Name:         template.Name,
+		OfferedItems: template.OfferedItems,
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
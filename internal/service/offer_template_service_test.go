@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testOfferTemplateID = "22222222-3333-4444-5555-666666666666"
+
+// ---------- helpers ----------
+
+func newOfferTemplateTestService() (*OfferTemplateService, *mocks.MockOfferTemplateRepository) {
+	repo := new(mocks.MockOfferTemplateRepository)
+	return NewOfferTemplateService(repo), repo
+}
+
+func testOfferTemplate(id, userID string) *models.OfferTemplate {
+	return &models.OfferTemplate{
+		ID:           id,
+		UserID:       userID,
+		Name:         "Two Ists",
+		OfferedItems: json.RawMessage(`[{"type":"rune","name":"Ist","quantity":2}]`),
+	}
+}
+
+// ---------- Create ----------
+
+func TestOfferTemplateCreate_Success(t *testing.T) {
+	svc, repo := newOfferTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("CountByUserID", ctx, testBuyerID).Return(2, nil)
+	repo.On("Create", ctx, mock.AnythingOfType("*models.OfferTemplate")).Return(nil)
+
+	template, err := svc.Create(ctx, testBuyerID, &dto.CreateOfferTemplateRequest{
+		Name:         "Two Ists",
+		OfferedItems: json.RawMessage(`[{"type":"rune","name":"Ist","quantity":2}]`),
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, template.ID)
+	assert.Equal(t, testBuyerID, template.UserID)
+	assert.JSONEq(t, `[{"type":"rune","name":"Ist","quantity":2}]`, string(template.OfferedItems))
+}
+
+func TestOfferTemplateCreate_LimitReached(t *testing.T) {
+	svc, repo := newOfferTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("CountByUserID", ctx, testBuyerID).Return(MaxOfferTemplates, nil)
+
+	_, err := svc.Create(ctx, testBuyerID, &dto.CreateOfferTemplateRequest{Name: "x", OfferedItems: json.RawMessage(`[]`)})
+
+	assert.ErrorIs(t, err, ErrOfferTemplateLimitReached)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ---------- Update ----------
+
+func TestOfferTemplateUpdate_KeepsItemsWhenOmitted(t *testing.T) {
+	svc, repo := newOfferTemplateTestService()
+	ctx := context.Background()
+	template := testOfferTemplate(testOfferTemplateID, testBuyerID)
+
+	repo.On("GetByID", ctx, testOfferTemplateID).Return(template, nil)
+	repo.On("Update", ctx, template).Return(nil)
+
+	updated, err := svc.Update(ctx, testOfferTemplateID, testBuyerID, &dto.UpdateOfferTemplateRequest{Name: strPtr("Ist pair")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Ist pair", updated.Name)
+	assert.JSONEq(t, `[{"type":"rune","name":"Ist","quantity":2}]`, string(updated.OfferedItems))
+}
+
+func TestOfferTemplateUpdate_NotOwner(t *testing.T) {
+	svc, repo := newOfferTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testOfferTemplateID).Return(testOfferTemplate(testOfferTemplateID, "other-user"), nil)
+
+	_, err := svc.Update(ctx, testOfferTemplateID, testBuyerID, &dto.UpdateOfferTemplateRequest{Name: strPtr("x")})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// ---------- Delete ----------
+
+func TestOfferTemplateDelete_NotOwner(t *testing.T) {
+	svc, repo := newOfferTemplateTestService()
+	ctx := context.Background()
+
+	repo.On("GetByID", ctx, testOfferTemplateID).Return(testOfferTemplate(testOfferTemplateID, "other-user"), nil)
+
+	err := svc.Delete(ctx, testOfferTemplateID, testBuyerID)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,20 +16,68 @@ import (
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage"
 )
 
-const profileCacheTTL = 1 * time.Hour
+const (
+	profileCacheTTL = 1 * time.Hour
+	// activityFlushInterval is how often last_active_at is written per user
+	activityFlushInterval = 5 * time.Minute
+	// welcomeMaxProfileAge keeps accounts that predate onboarding from being welcomed
+	welcomeMaxProfileAge = 7 * 24 * time.Hour
+	// messageCountCacheTTL bounds how stale the unread message badge can get
+	messageCountCacheTTL = 1 * time.Minute
+)
+
+// EmailVerificationConfig holds email verification settings backed by Supabase Auth
+type EmailVerificationConfig struct {
+	Required    bool   // Gate listing/offer creation behind a verified email
+	SupabaseURL string // Supabase project URL used for the auth resend endpoint
+	APIKey      string // Supabase API key sent with auth requests
+}
+
+// AbuseThrottleConfig controls the shadow-throttle applied to suspected scammers.
+// Affected sellers aren't told; their listings just rank last and stay out of
+// home:recent until they are Delay old.
+type AbuseThrottleConfig struct {
+	Threshold int           // Abuse score at which a seller is throttled (0 disables)
+	Delay     time.Duration // How long a throttled seller's new listings are held out of home:recent
+	// ChurnLimit is how many listings a seller may cancel within an hour of creating them,
+	// per day, before each further one adds a point to their abuse score (0 disables)
+	ChurnLimit int
+}
 
 // ProfileService handles profile business logic
 type ProfileService struct {
-	repo            repository.ProfileRepository
-	transactionRepo repository.TransactionRepository
-	redis           *cache.RedisClient
-	invalidator     *cache.Invalidator
-	storage         storage.Storage
+	repo              repository.ProfileRepository
+	transactionRepo   repository.TransactionRepository
+	redis             *cache.RedisClient
+	invalidator       *cache.Invalidator
+	storage           storage.Storage
+	emailVerification EmailVerificationConfig
+	httpClient        *http.Client
+	notifService      *NotificationService
+	welcomeEnabled    bool
+	notificationRepo  repository.NotificationRepository
+	messageRepo       repository.MessageRepository
+	abuseThrottle     AbuseThrottleConfig
+	convertToWebP     bool
+	// responseTimeWindow is how far back offers count toward a seller's response time (0 disables refreshes)
+	responseTimeWindow time.Duration
+
+	// sandbox skips calls to the auth provider that send email
+	sandbox bool
+
+	// apiTokenRepo stores personal access tokens; nil disables them
+	apiTokenRepo repository.APITokenRepository
+	// apiTokenRateLimit is the per-minute request limit given to new tokens (0 uses the default)
+	apiTokenRateLimit int
+
+	// imageChecker swaps missing sold-for item images for a placeholder; nil trusts every URL
+	imageChecker *ItemImageChecker
 }
 
 // NewProfileService creates a new profile service
@@ -35,6 +87,7 @@ func NewProfileService(repo repository.ProfileRepository, redis *cache.RedisClie
 		redis:       redis,
 		invalidator: cache.NewInvalidator(redis),
 		storage:     stor,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
@@ -43,6 +96,50 @@ func (s *ProfileService) SetTransactionRepository(repo repository.TransactionRep
 	s.transactionRepo = repo
 }
 
+// SetWelcomeNotifications sets the notification service used to welcome new users.
+// When disabled, profiles are still marked onboarded but no notification is sent.
+func (s *ProfileService) SetWelcomeNotifications(ns *NotificationService, enabled bool) {
+	s.notifService = ns
+	s.welcomeEnabled = enabled
+}
+
+// SetBadgeCountRepositories sets the repositories GetBadgeCounts falls back to on a cache miss
+func (s *ProfileService) SetBadgeCountRepositories(notificationRepo repository.NotificationRepository, messageRepo repository.MessageRepository) {
+	s.notificationRepo = notificationRepo
+	s.messageRepo = messageRepo
+}
+
+// SetAbuseThrottle sets the abuse score shadow-throttle settings
+func (s *ProfileService) SetAbuseThrottle(config AbuseThrottleConfig) {
+	s.abuseThrottle = config
+}
+
+// SetResponseTimeWindow sets how far back offers count toward a seller's median
+// response time. Zero stops refreshing it.
+func (s *ProfileService) SetResponseTimeWindow(window time.Duration) {
+	s.responseTimeWindow = window
+}
+
+// SetWebPConversion stores PNG and JPEG profile pictures as WebP
+func (s *ProfileService) SetWebPConversion(enabled bool) {
+	s.convertToWebP = enabled
+}
+
+// SetEmailVerificationConfig sets the email verification settings
+func (s *ProfileService) SetEmailVerificationConfig(config EmailVerificationConfig) {
+	s.emailVerification = config
+}
+
+// SetSandboxMode stops ResendVerification from asking the auth provider to send email
+func (s *ProfileService) SetSandboxMode(enabled bool) {
+	s.sandbox = enabled
+}
+
+// SetImageChecker sets the checker that replaces missing sold-for item images with a placeholder
+func (s *ProfileService) SetImageChecker(checker *ItemImageChecker) {
+	s.imageChecker = checker
+}
+
 // GetByID retrieves a profile by ID with caching
 func (s *ProfileService) GetByID(ctx context.Context, id string) (*models.Profile, error) {
 	// Try cache first
@@ -106,6 +203,65 @@ func (s *ProfileService) GetByIdentifier(ctx context.Context, identifier string)
 	return s.GetByUsername(ctx, identifier)
 }
 
+// GetByIDs returns public profiles for many users at once, in the order the IDs
+// were given with duplicates dropped. Cached DTOs are read in a single MGET and
+// the misses are loaded in one query and cached. Unknown IDs are left out.
+func (s *ProfileService) GetByIDs(ctx context.Context, ids []string) ([]*dto.ProfileResponse, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) == 0 {
+		return []*dto.ProfileResponse{}, nil
+	}
+
+	keys := make([]string, len(unique))
+	for i, id := range unique {
+		keys[i] = cache.ProfileDTOKey(id)
+	}
+	cached, _ := s.redis.MGet(ctx, keys...)
+
+	found := make(map[string]*dto.ProfileResponse, len(unique))
+	var misses []string
+	for i, id := range unique {
+		if i < len(cached) && cached[i] != "" {
+			var resp dto.ProfileResponse
+			if json.Unmarshal([]byte(cached[i]), &resp) == nil {
+				found[id] = &resp
+				continue
+			}
+		}
+		misses = append(misses, id)
+	}
+
+	if len(misses) > 0 {
+		profiles, err := s.repo.GetByIDs(ctx, misses)
+		if err != nil {
+			return nil, err
+		}
+		for _, profile := range profiles {
+			if data, err := json.Marshal(profile); err == nil {
+				_ = s.redis.Set(ctx, cache.ProfileKey(profile.ID), string(data), profileCacheTTL)
+			}
+			s.CacheProfileDTO(ctx, profile)
+			found[profile.ID] = s.ToResponse(profile)
+		}
+	}
+
+	result := make([]*dto.ProfileResponse, 0, len(unique))
+	for _, id := range unique {
+		if resp, ok := found[id]; ok {
+			result = append(result, resp)
+		}
+	}
+	return result, nil
+}
+
 // Update updates a user's profile
 func (s *ProfileService) Update(ctx context.Context, userID string, req *dto.UpdateProfileRequest) (*models.Profile, error) {
 	profile, err := s.repo.GetByID(ctx, userID)
@@ -138,6 +294,13 @@ func (s *ProfileService) Update(ctx context.Context, userID string, req *dto.Upd
 	if req.PreferredNonRotw != nil {
 		profile.PreferredNonRotw = req.PreferredNonRotw
 	}
+	// An empty string clears the quiet hours bound
+	if req.QuietHoursStart != nil {
+		profile.QuietHoursStart = nilIfEmpty(*req.QuietHoursStart)
+	}
+	if req.QuietHoursEnd != nil {
+		profile.QuietHoursEnd = nilIfEmpty(*req.QuietHoursEnd)
+	}
 
 	if err := s.repo.Update(ctx, profile); err != nil {
 		return nil, err
@@ -153,6 +316,105 @@ func (s *ProfileService) Update(ctx context.Context, userID string, req *dto.Upd
 	return profile, nil
 }
 
+// TouchActivity records that the user is active.
+// The last_active_at write happens at most once per activityFlushInterval per user, so readers
+// of last_active_at (online seller counts, listing seller status) may see it lag by that much.
+// If Redis is unavailable, every call writes through.
+func (s *ProfileService) TouchActivity(ctx context.Context, userID string) error {
+	key := cache.ActivityKey(userID)
+
+	acquired, err := s.redis.SetNX(ctx, key, "1", activityFlushInterval)
+	if err == nil && !acquired {
+		return nil
+	}
+
+	if err := s.repo.UpdateLastActiveAt(ctx, userID); err != nil {
+		// Let the next request retry the write
+		_ = s.redis.Del(ctx, key)
+		return err
+	}
+
+	return nil
+}
+
+// EnsureOnboarded sends the welcome notification the first time a user loads
+// their own profile. The onboarded flag is claimed atomically, so concurrent
+// requests send it at most once.
+func (s *ProfileService) EnsureOnboarded(ctx context.Context, profile *models.Profile) {
+	if profile.Onboarded {
+		return
+	}
+
+	log := logger.FromContext(ctx)
+	claimed, err := s.repo.MarkOnboarded(ctx, profile.ID)
+	if err != nil {
+		log.Warn("failed to mark profile onboarded", "error", err.Error(), "user_id", profile.ID)
+		return
+	}
+	profile.Onboarded = true
+	_ = s.invalidator.InvalidateProfile(ctx, profile.ID)
+
+	if !claimed || !s.welcomeEnabled || s.notifService == nil {
+		return
+	}
+	if time.Since(profile.CreatedAt) > welcomeMaxProfileAge {
+		return
+	}
+	if err := s.notifService.SendWelcome(ctx, profile.ID); err != nil {
+		log.Warn("failed to send welcome notification", "error", err.Error(), "user_id", profile.ID)
+	}
+}
+
+// GetBadgeCounts returns the user's unread notification and message counts in one call.
+// Each count is served from its cache and only hits the database on a miss.
+func (s *ProfileService) GetBadgeCounts(ctx context.Context, userID string) (*dto.BadgeCounts, error) {
+	notifications, err := s.cachedCount(ctx, cache.NotificationCountKey(userID), notificationCountCacheTTL, func() (int, error) {
+		return s.notificationRepo.CountUnread(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.cachedCount(ctx, cache.MessageCountKey(userID), messageCountCacheTTL, func() (int, error) {
+		return s.messageRepo.CountUnread(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BadgeCounts{
+		Notifications: notifications,
+		Messages:      messages,
+	}, nil
+}
+
+// InvalidateMessageCount drops the cached unread message count for a user
+func (s *ProfileService) InvalidateMessageCount(ctx context.Context, userID string) {
+	_ = s.invalidator.InvalidateMessageCount(ctx, userID)
+}
+
+// cachedCount reads a JSON-encoded count from cache, loading and caching it on a miss
+func (s *ProfileService) cachedCount(ctx context.Context, key string, ttl time.Duration, load func() (int, error)) (int, error) {
+	cached, err := s.redis.Get(ctx, key)
+	if err == nil && cached != "" {
+		var count int
+		if json.Unmarshal([]byte(cached), &count) == nil {
+			return count, nil
+		}
+	}
+
+	count, err := load()
+	if err != nil {
+		return 0, err
+	}
+
+	if data, err := json.Marshal(count); err == nil {
+		_ = s.redis.Set(ctx, key, string(data), ttl)
+	}
+
+	return count, nil
+}
+
 // ToResponse converts a profile model to a DTO response
 func (s *ProfileService) ToResponse(profile *models.Profile) *dto.ProfileResponse {
 	return &dto.ProfileResponse{
@@ -169,10 +431,26 @@ func (s *ProfileService) ToResponse(profile *models.Profile) *dto.ProfileRespons
 		ProfileFlair:  profile.GetProfileFlair(),
 		UsernameColor: profile.GetUsernameColor(),
 		Timezone:      profile.GetTimezone(),
+		ResponseTime:  formatResponseTime(profile.ResponseTimeMinutes),
 		CreatedAt:     profile.CreatedAt,
 	}
 }
 
+// formatResponseTime renders a median response time as 45m, 3h or 2d, or "new"
+// for sellers who haven't answered any offers yet
+func formatResponseTime(minutes *int) string {
+	switch {
+	case minutes == nil:
+		return "new"
+	case *minutes < 60:
+		return fmt.Sprintf("%dm", *minutes)
+	case *minutes < 24*60:
+		return fmt.Sprintf("%dh", (*minutes+30)/60)
+	default:
+		return fmt.Sprintf("%dd", (*minutes+12*60)/(24*60))
+	}
+}
+
 // ToMyProfileResponse converts a profile model to a my profile DTO response
 func (s *ProfileService) ToMyProfileResponse(profile *models.Profile) *dto.MyProfileResponse {
 	return &dto.MyProfileResponse{
@@ -184,10 +462,77 @@ func (s *ProfileService) ToMyProfileResponse(profile *models.Profile) *dto.MyPro
 		PreferredPlatforms: profile.PreferredPlatforms,
 		PreferredRegion:    profile.GetPreferredRegion(),
 		PreferredNonRotw:   profile.PreferredNonRotw,
+		QuietHoursStart:    profile.GetQuietHoursStart(),
+		QuietHoursEnd:      profile.GetQuietHoursEnd(),
+		EmailVerified:      profile.EmailVerified,
 		UpdatedAt:          profile.UpdatedAt,
 	}
 }
 
+// AdjustAbuseScore adds delta (negative to forgive) to a user's abuse score and
+// returns the new score. The score never drops below zero.
+func (s *ProfileService) AdjustAbuseScore(ctx context.Context, userID string, delta int, reason string) (int, error) {
+	score, err := s.repo.AdjustAbuseScore(ctx, userID, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	// Listing creation reads the cached profile to decide on the throttle
+	_ = s.invalidator.InvalidateProfile(ctx, userID)
+
+	logger.FromContext(ctx).Info("abuse score adjusted",
+		"target_user_id", userID,
+		"delta", delta,
+		"abuse_score", score,
+		"reason", reason,
+	)
+	return score, nil
+}
+
+// RefreshResponseTime recomputes and stores the seller's median offer response time,
+// then drops the cached profile so profiles and listing cards pick it up
+func (s *ProfileService) RefreshResponseTime(ctx context.Context, sellerID string) {
+	if s.responseTimeWindow <= 0 {
+		return
+	}
+
+	if _, err := s.repo.RefreshResponseTime(ctx, sellerID, time.Now().Add(-s.responseTimeWindow)); err != nil {
+		return
+	}
+
+	_ = s.invalidator.InvalidateProfile(ctx, sellerID)
+	_ = s.invalidator.InvalidateProfileDTO(ctx, sellerID)
+	if profile, err := s.GetByID(ctx, sellerID); err == nil {
+		_ = s.invalidator.InvalidateProfileByUsername(ctx, strings.ToLower(profile.Username))
+	}
+}
+
+// ShadowThrottleScore returns the abuse score at which sellers are throttled (0 when disabled)
+func (s *ProfileService) ShadowThrottleScore() int {
+	return s.abuseThrottle.Threshold
+}
+
+// ShadowThrottleReleaseAt returns when a throttled seller's listing created at createdAt
+// may appear in home:recent
+func (s *ProfileService) ShadowThrottleReleaseAt(createdAt time.Time) time.Time {
+	return createdAt.Add(s.abuseThrottle.Delay)
+}
+
+// ListingChurnLimit returns how many quickly cancelled listings a seller may have per
+// day before they count against the abuse score (0 when disabled)
+func (s *ProfileService) ListingChurnLimit() int {
+	return s.abuseThrottle.ChurnLimit
+}
+
+// IsShadowThrottled reports whether a listing created at createdAt by profile
+// should still be held out of home:recent
+func (s *ProfileService) IsShadowThrottled(profile *models.Profile, createdAt time.Time) bool {
+	if profile == nil || s.abuseThrottle.Threshold <= 0 || profile.AbuseScore < s.abuseThrottle.Threshold {
+		return false
+	}
+	return time.Since(createdAt) < s.abuseThrottle.Delay
+}
+
 // IsAdmin checks if a user has admin privileges using the cached profile
 func (s *ProfileService) IsAdmin(ctx context.Context, userID string) (bool, error) {
 	profile, err := s.GetByID(ctx, userID)
@@ -197,12 +542,107 @@ func (s *ProfileService) IsAdmin(ctx context.Context, userID string) (bool, erro
 	return profile.IsAdmin, nil
 }
 
+// ListIDsByAudience returns a page of profile IDs in an announcement audience
+func (s *ProfileService) ListIDsByAudience(ctx context.Context, audience string, afterID string, limit int) ([]string, error) {
+	return s.repo.ListIDsByAudience(ctx, audience, afterID, limit)
+}
+
+// RequireVerifiedEmail returns ErrEmailNotVerified when verification is required
+// and the user has not confirmed their email yet
+func (s *ProfileService) RequireVerifiedEmail(ctx context.Context, userID string) error {
+	if !s.emailVerification.Required {
+		return nil
+	}
+
+	profile, err := s.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile.EmailVerified {
+		return nil
+	}
+
+	// The cached profile may predate the confirmation, so re-check the database
+	profile, err = s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !profile.EmailVerified {
+		return ErrEmailNotVerified
+	}
+
+	_ = s.invalidator.InvalidateProfile(ctx, userID)
+	_ = s.invalidator.InvalidateProfileDTO(ctx, userID)
+	return nil
+}
+
+// ResendVerification asks the auth provider to send a new confirmation email
+func (s *ProfileService) ResendVerification(ctx context.Context, userID string) error {
+	profile, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile.EmailVerified {
+		return ErrInvalidState
+	}
+
+	if s.sandbox {
+		logger.FromContext(ctx).Info("sandbox mode, skipping verification email",
+			"user_id", userID,
+		)
+		return nil
+	}
+
+	if s.emailVerification.SupabaseURL == "" || s.emailVerification.APIKey == "" {
+		return fmt.Errorf("email verification is not configured")
+	}
+
+	email, err := s.repo.GetEmailByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"type":  "signup",
+		"email": email,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(s.emailVerification.SupabaseURL, "/") + "/auth/v1/resend"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("apikey", s.emailVerification.APIKey)
+	req.Header.Set("Authorization", "Bearer "+s.emailVerification.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to resend verification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("resend verification failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
 // UploadProfilePicture uploads a profile picture and updates the profile
 func (s *ProfileService) UploadProfilePicture(ctx context.Context, userID string, data []byte, contentType string) (string, error) {
 	if s.storage == nil {
 		return "", fmt.Errorf("storage not configured")
 	}
 
+	if s.convertToWebP {
+		data, contentType = convertToWebP(data, contentType)
+	}
+
 	// Determine file extension from content type
 	var ext string
 	switch contentType {
@@ -272,6 +712,116 @@ func (s *ProfileService) GetSales(ctx context.Context, sellerID string, offset,
 	}, nil
 }
 
+// salesExportBatchSize is how many sales are loaded per query while streaming an export
+const salesExportBatchSize = 500
+
+// MaxSalesExportRows is the most sales one export may contain. The response is streamed
+// under the server's write timeout, so larger ranges have to be split by from/to.
+const MaxSalesExportRows = 10000
+
+// salesCSVHeader is the first row of a sales export
+var salesCSVHeader = []string{"date", "item", "buyer", "sold_for", "rating"}
+
+// ExportSalesCSV streams a seller's sales completed in [from, to) as CSV, oldest first.
+// A zero from or to leaves that side of the range open. Ranges with more than
+// MaxSalesExportRows sales return ErrExportTooLarge. Sales are loaded in batches
+// and written through a pipe, so large histories are never held in memory. The first
+// batch is loaded before returning so query errors surface to the caller; later
+// failures end the stream with that error.
+func (s *ProfileService) ExportSalesCSV(ctx context.Context, sellerID string, from, to time.Time) (io.Reader, error) {
+	if s.transactionRepo == nil {
+		return nil, fmt.Errorf("transaction repository not configured")
+	}
+
+	count, err := s.transactionRepo.CountSalesBySellerBetween(ctx, sellerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if count > MaxSalesExportRows {
+		return nil, ErrExportTooLarge
+	}
+
+	first, err := s.transactionRepo.GetSalesBySellerBetween(ctx, sellerID, from, to, 0, salesExportBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.writeSalesCSV(ctx, pw, sellerID, from, to, first))
+	}()
+
+	return pr, nil
+}
+
+// writeSalesCSV writes the header, the already loaded first batch and every following batch
+func (s *ProfileService) writeSalesCSV(ctx context.Context, out io.Writer, sellerID string, from, to time.Time, batch []repository.SaleRecord) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(salesCSVHeader); err != nil {
+		return err
+	}
+
+	offset := 0
+	for {
+		for _, record := range batch {
+			if err := w.Write(s.saleRecordToCSV(record)); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+
+		if len(batch) < salesExportBatchSize {
+			return nil
+		}
+		offset += len(batch)
+
+		var err error
+		batch, err = s.transactionRepo.GetSalesBySellerBetween(ctx, sellerID, from, to, offset, salesExportBatchSize)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to stream sales export",
+				"error", err.Error(),
+				"seller_id", sellerID,
+				"offset", offset,
+			)
+			return err
+		}
+	}
+}
+
+// saleRecordToCSV converts a SaleRecord to an export row
+func (s *ProfileService) saleRecordToCSV(record repository.SaleRecord) []string {
+	sale := s.saleRecordToDTO(record)
+
+	var date string
+	if !sale.CompletedAt.IsZero() {
+		date = sale.CompletedAt.UTC().Format(time.RFC3339)
+	}
+
+	soldFor := make([]string, 0, len(sale.SoldFor))
+	for _, item := range sale.SoldFor {
+		soldFor = append(soldFor, fmt.Sprintf("%dx %s", item.Quantity, item.Name))
+	}
+
+	var rating string
+	if sale.Review != nil {
+		rating = strconv.Itoa(sale.Review.Rating)
+	}
+
+	return []string{date, csvSafe(sale.Item.Name), csvSafe(sale.Buyer.DisplayName), csvSafe(strings.Join(soldFor, "; ")), rating}
+}
+
+// csvSafe stops spreadsheet apps from running user-supplied text as a formula by
+// prefixing cells that start with a formula trigger with a single quote
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
 // saleRecordToDTO converts a repository SaleRecord to a DTO SoldItem
 func (s *ProfileService) saleRecordToDTO(record repository.SaleRecord) dto.SoldItem {
 	// Parse completedAt
@@ -437,9 +987,17 @@ func (s *ProfileService) transformOfferedItems(rawItems []byte) []dto.SoldForIte
 			Type:     item.Type,
 			Name:     item.Name,
 			Quantity: quantity,
-			ImageURL: item.ImageURL,
+			ImageURL: s.imageChecker.Resolve(item.ImageURL),
 		})
 	}
 
 	return result
 }
+
+// nilIfEmpty returns nil for an empty string, otherwise a pointer to it
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
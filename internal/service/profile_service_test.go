@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
@@ -14,6 +20,7 @@ import (
 	storageMocks "github.com/ruanpelissoli/lootstash-marketplace-api/internal/storage/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ---------------------------------------------------------------------------
@@ -119,6 +126,74 @@ func TestProfileGetByID_CacheHit(t *testing.T) {
 // GetByUsername
 // ---------------------------------------------------------------------------
 
+func TestProfileGetByIDs_MixesCacheAndDatabaseInOrder(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+	ctx := context.Background()
+
+	cached := testProfile(testSellerID)
+	svc.CacheProfileDTO(ctx, cached)
+	fetched := testProfile(testBuyerID)
+
+	// Only the uncached IDs reach the database, each once
+	profileRepo.On("GetByIDs", ctx, []string{testBuyerID, "missing-000"}).
+		Return([]*models.Profile{fetched}, nil).Once()
+
+	profiles, err := svc.GetByIDs(ctx, []string{testBuyerID, testSellerID, testBuyerID, "missing-000"})
+
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, testBuyerID, profiles[0].ID)
+	assert.Equal(t, testSellerID, profiles[1].ID)
+	assert.Equal(t, cached.Username, profiles[1].Username)
+	assert.True(t, mr.Exists(cache.ProfileDTOKey(testBuyerID)), "misses are backfilled")
+	assert.True(t, mr.Exists(cache.ProfileKey(testBuyerID)))
+	profileRepo.AssertExpectations(t)
+}
+
+func TestProfileGetByIDs_AllCachedSkipsDatabase(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+	ctx := context.Background()
+
+	svc.CacheProfileDTO(ctx, testProfile(testSellerID))
+
+	profiles, err := svc.GetByIDs(ctx, []string{testSellerID})
+
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	profileRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+func TestProfileGetByIDs_WithoutRedis(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	ctx := context.Background()
+
+	profileRepo.On("GetByIDs", ctx, []string{testSellerID, testBuyerID}).
+		Return([]*models.Profile{testProfile(testBuyerID), testProfile(testSellerID)}, nil)
+
+	profiles, err := svc.GetByIDs(ctx, []string{testSellerID, testBuyerID})
+
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, testSellerID, profiles[0].ID)
+	assert.Equal(t, testBuyerID, profiles[1].ID)
+}
+
+func TestProfileGetByIDs_Empty(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	profiles, err := svc.GetByIDs(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+	profileRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
 func TestProfileGetByUsername_Success(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	svc := NewProfileService(profileRepo, newTestRedis(), nil)
@@ -310,6 +385,203 @@ func TestProfileIsAdmin_False(t *testing.T) {
 	profileRepo.AssertExpectations(t)
 }
 
+// ---------------------------------------------------------------------------
+// TouchActivity
+// ---------------------------------------------------------------------------
+
+func TestTouchActivity_ThrottlesWrites(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+
+	profileRepo.On("UpdateLastActiveAt", mock.Anything, testUserID).Return(nil).Once()
+
+	assert.NoError(t, svc.TouchActivity(context.Background(), testUserID))
+	assert.NoError(t, svc.TouchActivity(context.Background(), testUserID))
+
+	profileRepo.AssertNumberOfCalls(t, "UpdateLastActiveAt", 1)
+}
+
+func TestTouchActivity_FailedWriteRetries(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+
+	profileRepo.On("UpdateLastActiveAt", mock.Anything, testUserID).Return(errors.New("db down")).Once()
+	profileRepo.On("UpdateLastActiveAt", mock.Anything, testUserID).Return(nil).Once()
+
+	assert.Error(t, svc.TouchActivity(context.Background(), testUserID))
+	assert.NoError(t, svc.TouchActivity(context.Background(), testUserID))
+
+	profileRepo.AssertNumberOfCalls(t, "UpdateLastActiveAt", 2)
+}
+
+func TestTouchActivity_NoRedis_WritesThrough(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	profileRepo.On("UpdateLastActiveAt", mock.Anything, testUserID).Return(nil)
+
+	assert.NoError(t, svc.TouchActivity(context.Background(), testUserID))
+	assert.NoError(t, svc.TouchActivity(context.Background(), testUserID))
+
+	profileRepo.AssertNumberOfCalls(t, "UpdateLastActiveAt", 2)
+}
+
+// ---------------------------------------------------------------------------
+// EnsureOnboarded
+// ---------------------------------------------------------------------------
+
+func newOnboardingTestService(enabled bool) (*ProfileService, *mocks.MockProfileRepository, *mocks.MockNotificationRepository) {
+	profileRepo := new(mocks.MockProfileRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetWelcomeNotifications(NewNotificationService(notifRepo, nil), enabled)
+	return svc, profileRepo, notifRepo
+}
+
+func TestEnsureOnboarded_NewUserWelcomed(t *testing.T) {
+	svc, profileRepo, notifRepo := newOnboardingTestService(true)
+	profile := testProfile(testUserID)
+	profile.CreatedAt = time.Now()
+
+	profileRepo.On("MarkOnboarded", mock.Anything, testUserID).Return(true, nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	svc.EnsureOnboarded(context.Background(), profile)
+
+	assert.True(t, profile.Onboarded)
+	notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testUserID && n.Type == models.NotificationTypeWelcome
+	}))
+}
+
+func TestEnsureOnboarded_AlreadyOnboarded(t *testing.T) {
+	svc, profileRepo, notifRepo := newOnboardingTestService(true)
+	profile := testProfile(testUserID)
+	profile.Onboarded = true
+
+	svc.EnsureOnboarded(context.Background(), profile)
+
+	profileRepo.AssertNotCalled(t, "MarkOnboarded", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEnsureOnboarded_LostClaimSkipsWelcome(t *testing.T) {
+	svc, profileRepo, notifRepo := newOnboardingTestService(true)
+	profile := testProfile(testUserID)
+	profile.CreatedAt = time.Now()
+
+	profileRepo.On("MarkOnboarded", mock.Anything, testUserID).Return(false, nil)
+
+	svc.EnsureOnboarded(context.Background(), profile)
+
+	assert.True(t, profile.Onboarded)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEnsureOnboarded_ExistingAccountMarkedWithoutWelcome(t *testing.T) {
+	svc, profileRepo, notifRepo := newOnboardingTestService(true)
+	profile := testProfile(testUserID) // created 30 days ago
+
+	profileRepo.On("MarkOnboarded", mock.Anything, testUserID).Return(true, nil)
+
+	svc.EnsureOnboarded(context.Background(), profile)
+
+	profileRepo.AssertCalled(t, "MarkOnboarded", mock.Anything, testUserID)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEnsureOnboarded_Disabled(t *testing.T) {
+	svc, profileRepo, notifRepo := newOnboardingTestService(false)
+	profile := testProfile(testUserID)
+	profile.CreatedAt = time.Now()
+
+	profileRepo.On("MarkOnboarded", mock.Anything, testUserID).Return(true, nil)
+
+	svc.EnsureOnboarded(context.Background(), profile)
+
+	assert.True(t, profile.Onboarded)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ---------------------------------------------------------------------------
+// RequireVerifiedEmail
+// ---------------------------------------------------------------------------
+
+func TestRequireVerifiedEmail_Disabled(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	err := svc.RequireVerifiedEmail(context.Background(), testUserID)
+	assert.NoError(t, err)
+
+	profileRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestRequireVerifiedEmail_Verified(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetEmailVerificationConfig(EmailVerificationConfig{Required: true})
+
+	ctx := context.Background()
+	profile := testProfile(testUserID, func(p *models.Profile) { p.EmailVerified = true })
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil).Once()
+
+	err := svc.RequireVerifiedEmail(ctx, testUserID)
+	assert.NoError(t, err)
+
+	profileRepo.AssertExpectations(t)
+}
+
+func TestRequireVerifiedEmail_NotVerified(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetEmailVerificationConfig(EmailVerificationConfig{Required: true})
+
+	ctx := context.Background()
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+
+	err := svc.RequireVerifiedEmail(ctx, testUserID)
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+}
+
+func TestRequireVerifiedEmail_StaleCacheRechecksDatabase(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+	svc.SetEmailVerificationConfig(EmailVerificationConfig{Required: true})
+
+	ctx := context.Background()
+
+	// Cached profile still says unverified
+	stale := testProfile(testUserID)
+	data, _ := json.Marshal(stale)
+	mr.Set(cache.ProfileKey(testUserID), string(data))
+
+	verified := testProfile(testUserID, func(p *models.Profile) { p.EmailVerified = true })
+	profileRepo.On("GetByID", ctx, testUserID).Return(verified, nil).Once()
+
+	err := svc.RequireVerifiedEmail(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists(cache.ProfileKey(testUserID)))
+
+	profileRepo.AssertExpectations(t)
+}
+
+func TestResendVerification_AlreadyVerified(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	ctx := context.Background()
+	profile := testProfile(testUserID, func(p *models.Profile) { p.EmailVerified = true })
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+
+	err := svc.ResendVerification(ctx, testUserID)
+	assert.ErrorIs(t, err, ErrInvalidState)
+	profileRepo.AssertNotCalled(t, "GetEmailByID", mock.Anything, mock.Anything)
+}
+
 // ---------------------------------------------------------------------------
 // UploadProfilePicture
 // ---------------------------------------------------------------------------
@@ -338,6 +610,45 @@ func TestUploadProfilePicture_Success(t *testing.T) {
 	profileRepo.AssertExpectations(t)
 }
 
+func TestUploadProfilePicture_ConvertsToWebP(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	stor := new(storageMocks.MockStorage)
+	svc := NewProfileService(profileRepo, newTestRedis(), stor)
+	svc.SetWebPConversion(true)
+
+	ctx := context.Background()
+	profile := testProfile(testUserID)
+	expectedURL := "https://storage.example.com/user-111.webp"
+
+	stor.On("UploadImage", ctx, testUserID+".webp", mock.Anything, "image/webp").Return(expectedURL, nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+	profileRepo.On("Update", ctx, profile).Return(nil)
+
+	url, err := svc.UploadProfilePicture(ctx, testUserID, encodedPNG(t), "image/png")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedURL, url)
+	stor.AssertExpectations(t)
+}
+
+func TestUploadProfilePicture_ConversionFallsBackToOriginal(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	stor := new(storageMocks.MockStorage)
+	svc := NewProfileService(profileRepo, newTestRedis(), stor)
+	svc.SetWebPConversion(true)
+
+	ctx := context.Background()
+	profile := testProfile(testUserID)
+	imageData := []byte("fake-image-data")
+
+	stor.On("UploadImage", ctx, testUserID+".png", imageData, "image/png").Return("https://storage.example.com/user-111.png", nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+	profileRepo.On("Update", ctx, profile).Return(nil)
+
+	_, err := svc.UploadProfilePicture(ctx, testUserID, imageData, "image/png")
+	assert.NoError(t, err)
+	stor.AssertExpectations(t)
+}
+
 func TestUploadProfilePicture_UnsupportedContentType(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	stor := new(storageMocks.MockStorage)
@@ -440,6 +751,182 @@ func TestGetSales_NoTransactionRepo(t *testing.T) {
 	assert.Contains(t, err.Error(), "transaction repository not configured")
 }
 
+// ---------------------------------------------------------------------------
+// ExportSalesCSV
+// ---------------------------------------------------------------------------
+
+func TestExportSalesCSV_WritesRows(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC)
+
+	records := []repository.SaleRecord{
+		{
+			TransactionID: testTransactionID,
+			CompletedAt:   completedAt,
+			ItemName:      "Shako",
+			OfferedItems:  json.RawMessage(`[{"type":"rune","name":"Ist Rune","quantity":2},{"type":"rune","name":"Mal Rune"}]`),
+			BuyerID:       testBuyerID,
+			BuyerName:     "Buyer, Jr.",
+			ReviewRating:  intPtr(4),
+		},
+		{
+			TransactionID: "tx-2",
+			CompletedAt:   completedAt.Add(time.Hour),
+			ItemName:      "Ber Rune",
+			BuyerID:       testBuyerID,
+			BuyerName:     "BuyerUser",
+		},
+	}
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(len(records), nil)
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, 0, salesExportBatchSize).Return(records, nil)
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(reader).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, salesCSVHeader, rows[0])
+	assert.Equal(t, []string{"2024-01-15T12:30:00Z", "Shako", "Buyer, Jr.", "2x Ist Rune; 1x Mal Rune", "4"}, rows[1])
+	assert.Equal(t, []string{"2024-01-15T13:30:00Z", "Ber Rune", "BuyerUser", "", ""}, rows[2])
+
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestExportSalesCSV_PagesThroughBatches(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	var from, to time.Time
+
+	fullBatch := make([]repository.SaleRecord, salesExportBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = repository.SaleRecord{ItemName: "Ist Rune", BuyerName: "BuyerUser"}
+	}
+	lastBatch := []repository.SaleRecord{{ItemName: "Zod Rune", BuyerName: "BuyerUser"}}
+
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(salesExportBatchSize+1, nil)
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, 0, salesExportBatchSize).Return(fullBatch, nil).Once()
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, salesExportBatchSize, salesExportBatchSize).Return(lastBatch, nil).Once()
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(reader).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, salesExportBatchSize+2)
+	assert.Equal(t, "Zod Rune", rows[len(rows)-1][1])
+
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestExportSalesCSV_EscapesFormulaCells(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	var from, to time.Time
+
+	records := []repository.SaleRecord{
+		{ItemName: "=HYPERLINK(\"http://evil\")", BuyerName: "@SUM(A1)"},
+		{ItemName: "+cmd", BuyerName: "-1"},
+		{ItemName: "\tTab", BuyerName: "\rReturn"},
+		{ItemName: "Shako", BuyerName: "Buyer=1"},
+	}
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(len(records), nil)
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, 0, salesExportBatchSize).Return(records, nil)
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(reader).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://evil\")", "'@SUM(A1)"}, rows[1][1:3])
+	assert.Equal(t, []string{"'+cmd", "'-1"}, rows[2][1:3])
+	assert.Equal(t, []string{"'\tTab", "'\rReturn"}, rows[3][1:3])
+	assert.Equal(t, []string{"Shako", "Buyer=1"}, rows[4][1:3])
+}
+
+func TestExportSalesCSV_TooManyRows(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	var from, to time.Time
+
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(MaxSalesExportRows+1, nil)
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	assert.ErrorIs(t, err, ErrExportTooLarge)
+	assert.Nil(t, reader)
+	transactionRepo.AssertNotCalled(t, "GetSalesBySellerBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExportSalesCSV_FirstBatchErrorIsReturned(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	var from, to time.Time
+	dbErr := errors.New("connection refused")
+
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(1, nil)
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, 0, salesExportBatchSize).Return(nil, dbErr)
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, reader)
+}
+
+func TestExportSalesCSV_LaterBatchErrorEndsStream(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	transactionRepo := new(mocks.MockTransactionRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	svc.SetTransactionRepository(transactionRepo)
+
+	ctx := context.Background()
+	var from, to time.Time
+	dbErr := errors.New("connection reset")
+
+	fullBatch := make([]repository.SaleRecord, salesExportBatchSize)
+	transactionRepo.On("CountSalesBySellerBetween", ctx, testSellerID, from, to).Return(salesExportBatchSize*2, nil)
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, 0, salesExportBatchSize).Return(fullBatch, nil).Once()
+	transactionRepo.On("GetSalesBySellerBetween", ctx, testSellerID, from, to, salesExportBatchSize, salesExportBatchSize).Return(nil, dbErr).Once()
+
+	reader, err := svc.ExportSalesCSV(ctx, testSellerID, from, to)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestExportSalesCSV_NoTransactionRepo(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	reader, err := svc.ExportSalesCSV(context.Background(), testSellerID, time.Time{}, time.Time{})
+	assert.Error(t, err)
+	assert.Nil(t, reader)
+	assert.Contains(t, err.Error(), "transaction repository not configured")
+}
+
 // ---------------------------------------------------------------------------
 // DTO transformations
 // ---------------------------------------------------------------------------
@@ -607,3 +1094,204 @@ func TestExtractSaleNumericValue(t *testing.T) {
 	result = extractSaleNumericValue("no-digits")
 	assert.Nil(t, result)
 }
+
+// ---------------------------------------------------------------------------
+// GetBadgeCounts
+// ---------------------------------------------------------------------------
+
+func newBadgeCountTestService(t *testing.T) (*ProfileService, *mocks.MockNotificationRepository, *mocks.MockMessageRepository, *miniredis.Miniredis) {
+	redisClient, mr := newTestRedisReal(t)
+	notifRepo := new(mocks.MockNotificationRepository)
+	messageRepo := new(mocks.MockMessageRepository)
+
+	svc := NewProfileService(new(mocks.MockProfileRepository), redisClient, nil)
+	svc.SetBadgeCountRepositories(notifRepo, messageRepo)
+
+	return svc, notifRepo, messageRepo, mr
+}
+
+func TestProfileGetBadgeCounts_CacheMissLoadsAndCaches(t *testing.T) {
+	svc, notifRepo, messageRepo, mr := newBadgeCountTestService(t)
+	ctx := context.Background()
+
+	notifRepo.On("CountUnread", ctx, testUserID).Return(3, nil).Once()
+	messageRepo.On("CountUnread", ctx, testUserID).Return(7, nil).Once()
+
+	counts, err := svc.GetBadgeCounts(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.BadgeCounts{Notifications: 3, Messages: 7}, counts)
+	assert.True(t, mr.Exists(cache.NotificationCountKey(testUserID)))
+	assert.True(t, mr.Exists(cache.MessageCountKey(testUserID)))
+
+	// Second call is served entirely from cache
+	counts, err = svc.GetBadgeCounts(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.BadgeCounts{Notifications: 3, Messages: 7}, counts)
+
+	notifRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestProfileGetBadgeCounts_SharesNotificationCountCache(t *testing.T) {
+	svc, notifRepo, messageRepo, mr := newBadgeCountTestService(t)
+	ctx := context.Background()
+
+	// Written by NotificationService.CountUnread
+	_ = mr.Set(cache.NotificationCountKey(testUserID), "5")
+	messageRepo.On("CountUnread", ctx, testUserID).Return(0, nil).Once()
+
+	counts, err := svc.GetBadgeCounts(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, counts.Notifications)
+	assert.Equal(t, 0, counts.Messages)
+
+	notifRepo.AssertNotCalled(t, "CountUnread", mock.Anything, mock.Anything)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestProfileGetBadgeCounts_InvalidateMessageCountReloads(t *testing.T) {
+	svc, notifRepo, messageRepo, _ := newBadgeCountTestService(t)
+	ctx := context.Background()
+
+	notifRepo.On("CountUnread", ctx, testUserID).Return(0, nil).Once()
+	messageRepo.On("CountUnread", ctx, testUserID).Return(2, nil).Once()
+	messageRepo.On("CountUnread", ctx, testUserID).Return(0, nil).Once()
+
+	counts, err := svc.GetBadgeCounts(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counts.Messages)
+
+	svc.InvalidateMessageCount(ctx, testUserID)
+
+	counts, err = svc.GetBadgeCounts(ctx, testUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, counts.Messages)
+
+	notifRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestProfileGetBadgeCounts_RepositoryError(t *testing.T) {
+	svc, notifRepo, messageRepo, mr := newBadgeCountTestService(t)
+	ctx := context.Background()
+
+	notifRepo.On("CountUnread", ctx, testUserID).Return(1, nil).Once()
+	messageRepo.On("CountUnread", ctx, testUserID).Return(0, errors.New("db down")).Once()
+
+	counts, err := svc.GetBadgeCounts(ctx, testUserID)
+	assert.Error(t, err)
+	assert.Nil(t, counts)
+	assert.False(t, mr.Exists(cache.MessageCountKey(testUserID)))
+}
+
+// ---------------------------------------------------------------------------
+// Abuse score
+// ---------------------------------------------------------------------------
+
+func TestProfileAdjustAbuseScore_InvalidatesCachedProfile(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+	ctx := context.Background()
+
+	_ = mr.Set(cache.ProfileKey(testUserID), `{"ID":"user-stale"}`)
+	profileRepo.On("AdjustAbuseScore", ctx, testUserID, 5).Return(7, nil)
+
+	score, err := svc.AdjustAbuseScore(ctx, testUserID, 5, "scam report confirmed")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, score)
+	assert.False(t, mr.Exists(cache.ProfileKey(testUserID)))
+	profileRepo.AssertExpectations(t)
+}
+
+func TestProfileAdjustAbuseScore_NotFound(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+	ctx := context.Background()
+
+	profileRepo.On("AdjustAbuseScore", ctx, testUserID, -3).Return(0, sql.ErrNoRows)
+
+	_, err := svc.AdjustAbuseScore(ctx, testUserID, -3, "appeal upheld")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestProfileIsShadowThrottled(t *testing.T) {
+	svc := NewProfileService(nil, nil, nil)
+	svc.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 10, Delay: 24 * time.Hour})
+
+	flagged := testProfile(testSellerID)
+	flagged.AbuseScore = 10
+	clean := testProfile(testSellerID)
+	clean.AbuseScore = 9
+
+	assert.True(t, svc.IsShadowThrottled(flagged, time.Now()))
+	assert.False(t, svc.IsShadowThrottled(flagged, time.Now().Add(-25*time.Hour)), "throttle lifts after the delay")
+	assert.False(t, svc.IsShadowThrottled(clean, time.Now()))
+	assert.False(t, svc.IsShadowThrottled(nil, time.Now()))
+
+	svc.SetAbuseThrottle(AbuseThrottleConfig{Threshold: 0, Delay: 24 * time.Hour})
+	assert.False(t, svc.IsShadowThrottled(flagged, time.Now()), "threshold 0 disables the throttle")
+}
+
+// ---------------------------------------------------------------------------
+// Response time
+// ---------------------------------------------------------------------------
+
+func TestProfileRefreshResponseTime_StoresAndInvalidates(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc := NewProfileService(profileRepo, redisClient, nil)
+	svc.SetResponseTimeWindow(30 * 24 * time.Hour)
+	ctx := context.Background()
+
+	profile := testProfile(testSellerID)
+	_ = mr.Set(cache.ProfileKey(testSellerID), `{"ID":"user-stale"}`)
+	_ = mr.Set(cache.ProfileDTOKey(testSellerID), `{}`)
+	_ = mr.Set(cache.ProfileUsernameKey(strings.ToLower(profile.Username)), `{}`)
+
+	minutes := 45
+	profile.ResponseTimeMinutes = &minutes
+	profileRepo.On("RefreshResponseTime", ctx, testSellerID, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 29*24*time.Hour && time.Since(since) < 31*24*time.Hour
+	})).Return(&minutes, nil)
+	profileRepo.On("GetByID", ctx, testSellerID).Return(profile, nil)
+
+	svc.RefreshResponseTime(ctx, testSellerID)
+
+	profileRepo.AssertExpectations(t)
+	// Reloading the profile re-caches it with the new value
+	dtoJSON, err := mr.Get(cache.ProfileDTOKey(testSellerID))
+	require.NoError(t, err)
+	assert.Contains(t, dtoJSON, `"responseTime":"45m"`)
+	assert.False(t, mr.Exists(cache.ProfileUsernameKey(strings.ToLower(profile.Username))))
+}
+
+func TestProfileRefreshResponseTime_DisabledWithoutWindow(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := NewProfileService(profileRepo, newTestRedis(), nil)
+
+	svc.RefreshResponseTime(context.Background(), testSellerID)
+
+	profileRepo.AssertNotCalled(t, "RefreshResponseTime", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFormatResponseTime(t *testing.T) {
+	minutes := func(m int) *int { return &m }
+
+	assert.Equal(t, "new", formatResponseTime(nil))
+	assert.Equal(t, "0m", formatResponseTime(minutes(0)))
+	assert.Equal(t, "45m", formatResponseTime(minutes(45)))
+	assert.Equal(t, "2h", formatResponseTime(minutes(100)))
+	assert.Equal(t, "23h", formatResponseTime(minutes(23*60+10)))
+	assert.Equal(t, "3d", formatResponseTime(minutes(3*24*60+60)))
+}
+
+func TestProfileToResponse_NewSellerResponseTime(t *testing.T) {
+	svc := NewProfileService(nil, nil, nil)
+
+	resp := svc.ToResponse(testProfile(testSellerID))
+
+	assert.Equal(t, "new", resp.ResponseTime)
+}
@@ -16,6 +16,9 @@ type RatingService struct {
 	transactionRepo     repository.TransactionRepository
 	profileService      *ProfileService
 	notificationService *NotificationService
+	textLimits          TextLimits
+	// disputeRepo blocks ratings on disputed trades and runs; nil skips the check
+	disputeRepo repository.DisputeRepository
 }
 
 // NewRatingService creates a new rating service
@@ -30,11 +33,22 @@ func NewRatingService(
 		transactionRepo:     transactionRepo,
 		profileService:      profileService,
 		notificationService: notificationService,
+		textLimits:          TextLimits{}.withDefaults(),
 	}
 }
 
+// SetTextLimits sets the maximum length of rating comments
+func (s *RatingService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
 // Create creates a new rating for a transaction
 func (s *RatingService) Create(ctx context.Context, raterID string, req *dto.CreateRatingRequest) (*models.Rating, error) {
+	comment, err := cleanText("comment", req.Comment, s.textLimits.Comment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the transaction
 	transaction, err := s.transactionRepo.GetByID(ctx, req.TransactionID)
 	if err != nil {
@@ -46,6 +60,10 @@ func (s *RatingService) Create(ctx context.Context, raterID string, req *dto.Cre
 		return nil, ErrForbidden
 	}
 
+	if err := s.checkNoOpenDispute(ctx, transaction); err != nil {
+		return nil, err
+	}
+
 	// Check if already rated
 	exists, err := s.repo.Exists(ctx, req.TransactionID, raterID)
 	if err != nil {
@@ -72,8 +90,8 @@ func (s *RatingService) Create(ctx context.Context, raterID string, req *dto.Cre
 		CreatedAt:     time.Now(),
 	}
 
-	if req.Comment != "" {
-		rating.Comment = &req.Comment
+	if comment != "" {
+		rating.Comment = &comment
 	}
 
 	if err := s.repo.Create(ctx, rating); err != nil {
@@ -86,11 +104,52 @@ func (s *RatingService) Create(ctx context.Context, raterID string, req *dto.Cre
 	return rating, nil
 }
 
-// GetByUserID retrieves ratings for a user
+// SetDisputeRepository blocks rating a trade or service run while it is disputed
+func (s *RatingService) SetDisputeRepository(repo repository.DisputeRepository) {
+	s.disputeRepo = repo
+}
+
+// checkNoOpenDispute returns ErrDisputeOpen when the transaction's trade or service run
+// is disputed
+func (s *RatingService) checkNoOpenDispute(ctx context.Context, transaction *models.Transaction) error {
+	if s.disputeRepo == nil {
+		return nil
+	}
+
+	targetType, targetID := models.DisputeTargetTrade, transaction.GetTradeID()
+	if transaction.ServiceRunID != nil {
+		targetType, targetID = models.DisputeTargetServiceRun, *transaction.ServiceRunID
+	}
+	if targetID == "" {
+		return nil
+	}
+
+	dispute, err := s.disputeRepo.GetOpenByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if dispute != nil {
+		return ErrDisputeOpen
+	}
+	return nil
+}
+
+// GetByUserID retrieves the ratings a user received. Kept for compatibility;
+// new callers should use GetReceived or GetGiven.
 func (s *RatingService) GetByUserID(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
 	return s.repo.GetByUserID(ctx, userID, offset, limit)
 }
 
+// GetReceived retrieves ratings other users gave the user, each with its rater
+func (s *RatingService) GetReceived(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	return s.repo.GetReceived(ctx, userID, offset, limit)
+}
+
+// GetGiven retrieves ratings the user gave others, each with the rated user
+func (s *RatingService) GetGiven(ctx context.Context, userID string, offset, limit int) ([]*models.Rating, int, error) {
+	return s.repo.GetGiven(ctx, userID, offset, limit)
+}
+
 // GetByTransactionID retrieves ratings for a transaction
 func (s *RatingService) GetByTransactionID(ctx context.Context, transactionID string) ([]*models.Rating, error) {
 	return s.repo.GetByTransactionID(ctx, transactionID)
@@ -111,6 +170,9 @@ func (s *RatingService) ToResponse(rating *models.Rating) *dto.RatingResponse {
 	if rating.Rater != nil {
 		resp.Rater = s.profileService.ToResponse(rating.Rater)
 	}
+	if rating.Rated != nil {
+		resp.Rated = s.profileService.ToResponse(rating.Rated)
+	}
 
 	return resp
 }
@@ -131,6 +131,30 @@ func TestRatingCreate_AlreadyRated(t *testing.T) {
 	txnRepo.AssertExpectations(t)
 }
 
+func TestRatingCreate_DisputedServiceRun(t *testing.T) {
+	svc, ratingRepo, txnRepo, _, _ := newTestRatingService()
+	disputeRepo := new(mocks.MockDisputeRepository)
+	svc.SetDisputeRepository(disputeRepo)
+
+	runID := testServiceRunID
+	txn := testTransaction(testTransactionID, testProviderID, testClientID)
+	txn.TradeID = nil
+	txn.ServiceRunID = &runID
+	txnRepo.On("GetByID", mock.Anything, testTransactionID).Return(txn, nil)
+	disputeRepo.On("GetOpenByTarget", mock.Anything, models.DisputeTargetServiceRun, testServiceRunID).
+		Return(&models.Dispute{ID: "dispute-1", Status: models.DisputeStatusOpen}, nil)
+
+	req := &dto.CreateRatingRequest{
+		TransactionID: testTransactionID,
+		Stars:         1,
+	}
+	rating, err := svc.Create(context.Background(), testClientID, req)
+
+	assert.Nil(t, rating)
+	assert.ErrorIs(t, err, ErrDisputeOpen)
+	ratingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestRatingCreate_TransactionNotFound(t *testing.T) {
 	svc, _, txnRepo, _, _ := newTestRatingService()
 
@@ -324,6 +348,59 @@ func TestRatingGetByUserID_RepoError(t *testing.T) {
 	ratingRepo.AssertExpectations(t)
 }
 
+func TestRatingGetReceived(t *testing.T) {
+	svc, ratingRepo, _, _, _ := newTestRatingService()
+
+	received := testRating(testRatingID, testTransactionID, testSellerID, testBuyerID, 5)
+	received.Rater = testProfile(testSellerID)
+	ratingRepo.On("GetReceived", mock.Anything, testBuyerID, 0, 20).Return([]*models.Rating{received}, 1, nil)
+
+	ratings, total, err := svc.GetReceived(context.Background(), testBuyerID, 0, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, ratings, 1)
+	assert.Equal(t, testBuyerID, ratings[0].RatedID)
+	assert.Equal(t, testSellerID, svc.ToResponse(ratings[0]).Rater.ID)
+	ratingRepo.AssertExpectations(t)
+	ratingRepo.AssertNotCalled(t, "GetGiven", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRatingGetGiven(t *testing.T) {
+	svc, ratingRepo, _, _, _ := newTestRatingService()
+
+	given := testRating(testRatingID, testTransactionID, testBuyerID, testSellerID, 4)
+	given.Rated = testProfile(testSellerID)
+	ratingRepo.On("GetGiven", mock.Anything, testBuyerID, 0, 20).Return([]*models.Rating{given}, 1, nil)
+
+	ratings, total, err := svc.GetGiven(context.Background(), testBuyerID, 0, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, ratings, 1)
+	assert.Equal(t, testBuyerID, ratings[0].RaterID)
+
+	resp := svc.ToResponse(ratings[0])
+	assert.Nil(t, resp.Rater)
+	assert.NotNil(t, resp.Rated)
+	assert.Equal(t, testSellerID, resp.Rated.ID)
+	ratingRepo.AssertExpectations(t)
+	ratingRepo.AssertNotCalled(t, "GetReceived", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRatingGetGiven_RepoError(t *testing.T) {
+	svc, ratingRepo, _, _, _ := newTestRatingService()
+
+	ratingRepo.On("GetGiven", mock.Anything, testUserID, 0, 10).Return(nil, 0, errors.New("db error"))
+
+	ratings, total, err := svc.GetGiven(context.Background(), testUserID, 0, 10)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, total)
+	assert.Nil(t, ratings)
+	ratingRepo.AssertExpectations(t)
+}
+
 // ---------------------------------------------------------------------------
 // GetByTransactionID
 // ---------------------------------------------------------------------------
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+)
+
+// defaultGame is the game assumed when a request or filter doesn't name one
+const defaultGame = "diablo2"
+
+// normalizeRegion maps a region to the game's canonical region code, returning
+// ErrInvalidRegion for values outside the game's region set. Without a registry
+// the region is only trimmed and lowercased.
+func normalizeRegion(registry *games.Registry, game string, region string) (string, error) {
+	if registry == nil {
+		return strings.ToLower(strings.TrimSpace(region)), nil
+	}
+	if game == "" {
+		game = defaultGame
+	}
+
+	normalized, err := registry.NormalizeRegion(game, region)
+	if errors.Is(err, games.ErrUnknownRegion) {
+		return "", ErrInvalidRegion
+	}
+	return normalized, err
+}
+
+// displayRegion returns the canonical code for a stored region, falling back to
+// the stored value for legacy rows that predate region validation
+func displayRegion(registry *games.Registry, game string, region string) string {
+	normalized, err := normalizeRegion(registry, game, region)
+	if err != nil || normalized == "" {
+		return region
+	}
+	return normalized
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func newTestGameRegistry() *games.Registry {
+	registry := games.NewRegistry()
+	d2.Register(registry)
+	return registry
+}
+
+func TestNormalizeRegion_NilRegistry(t *testing.T) {
+	region, err := normalizeRegion(nil, "diablo2", " Mars ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "mars", region)
+}
+
+func TestNormalizeRegion_DefaultsToDiablo2(t *testing.T) {
+	registry := newTestGameRegistry()
+
+	region, err := normalizeRegion(registry, "", "eu")
+	assert.NoError(t, err)
+	assert.Equal(t, "europe", region)
+
+	_, err = normalizeRegion(registry, "", "mars")
+	assert.ErrorIs(t, err, ErrInvalidRegion)
+}
+
+func TestDisplayRegion_LegacyValue(t *testing.T) {
+	registry := newTestGameRegistry()
+
+	assert.Equal(t, "americas", displayRegion(registry, "diablo2", "US"))
+	assert.Equal(t, "Old Realm", displayRegion(registry, "diablo2", "Old Realm"))
+}
+
+func TestListingCreate_NormalizesRegionAlias(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+	listingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "EU",
+	}
+
+	listing, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "europe", listing.Region)
+}
+
+func TestListingCreate_UnknownRegion(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+
+	req := &dto.CreateListingRequest{
+		Name:      "Shako",
+		ItemType:  "unique",
+		Rarity:    "unique",
+		Category:  "helm",
+		Game:      "diablo2",
+		Platforms: []string{"pc"},
+		Region:    "mars",
+	}
+
+	listing, err := svc.Create(context.Background(), testSellerID, req)
+
+	assert.Nil(t, listing)
+	var verrs ValidationErrors
+	assert.ErrorAs(t, err, &verrs)
+	assert.Contains(t, verrs, "region")
+	listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestListingList_NormalizesRegionFilter(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	listingRepo.On("List", mock.Anything, mock.MatchedBy(func(f repository.ListingFilter) bool {
+		return f.Region == "americas"
+	})).Return([]*models.Listing{}, 0, nil)
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{Region: "NA"}, "")
+
+	assert.NoError(t, err)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestListingList_UnknownRegionFilter(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	_, _, _, err := svc.List(context.Background(), &dto.ListingFilterRequest{Region: "mars"}, "")
+
+	assert.ErrorIs(t, err, ErrInvalidRegion)
+	listingRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestServiceUpdate_UnknownRegion(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	serviceRepo.On("GetByID", mock.Anything, testServiceID).Return(testServiceModel(testServiceID, testProviderID), nil)
+
+	region := "mars"
+	result, err := svc.Update(context.Background(), testServiceID, testProviderID, &dto.UpdateServiceRequest{Region: &region})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrInvalidRegion)
+	serviceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestServiceListProviders_NormalizesRegionFilter(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	serviceRepo.On("ListProviders", mock.Anything, mock.MatchedBy(func(f repository.ServiceProviderFilter) bool {
+		return f.Region == "asia"
+	})).Return([]repository.ProviderWithServices{}, 0, nil)
+
+	_, _, err := svc.ListProviders(context.Background(), repository.ServiceProviderFilter{Game: "diablo2", Region: "KR"})
+
+	assert.NoError(t, err)
+	serviceRepo.AssertExpectations(t)
+}
@@ -23,8 +23,15 @@ type ServiceRunService struct {
 	serviceService      *ServiceService
 	redis               *cache.RedisClient
 	invalidator         *cache.Invalidator
+	marketEvents        *MarketEventRecorder
+	chatArchiver        *ChatArchiver
+	// disputeRepo stores disputes on runs; nil turns disputes off
+	disputeRepo repository.DisputeRepository
 }
 
+// maxDisputeReasonLength caps the reason a participant gives when opening a dispute
+const maxDisputeReasonLength = 1000
+
 // NewServiceRunService creates a new service run service
 func NewServiceRunService(
 	repo repository.ServiceRunRepository,
@@ -49,6 +56,21 @@ func NewServiceRunService(
 	}
 }
 
+// SetMarketEventRecorder sets the recorder that logs a value event for each completed service run
+func (s *ServiceRunService) SetMarketEventRecorder(recorder *MarketEventRecorder) {
+	s.marketEvents = recorder
+}
+
+// SetChatArchiver sets the archiver that moves the run's chat out of the inbox once it resolves
+func (s *ServiceRunService) SetChatArchiver(archiver *ChatArchiver) {
+	s.chatArchiver = archiver
+}
+
+// SetDisputeRepository enables disputes on service runs
+func (s *ServiceRunService) SetDisputeRepository(repo repository.DisputeRepository) {
+	s.disputeRepo = repo
+}
+
 // GetByID retrieves a service run by ID with participant check
 func (s *ServiceRunService) GetByID(ctx context.Context, id string, userID string) (*models.ServiceRun, error) {
 	run, err := s.repo.GetByIDWithRelations(ctx, id)
@@ -97,7 +119,28 @@ func (s *ServiceRunService) Complete(ctx context.Context, id string, userID stri
 		return nil, nil, err
 	}
 
-	// Create transaction for rating eligibility
+	transaction, err := s.recordTransaction(ctx, run, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.chatArchiver.ArchiveServiceRunChat(ctx, run.ID, now)
+
+	// Notify the other party - service stays active
+	var recipientID string
+	if run.ProviderID == userID {
+		recipientID = run.ClientID
+	} else {
+		recipientID = run.ProviderID
+	}
+	_ = s.notificationService.NotifyServiceRunCompleted(ctx, recipientID, run.ID, run.Service.Name)
+
+	return run, transaction, nil
+}
+
+// recordTransaction creates the transaction for a completed run, along with its market
+// event. The transaction is what makes the run rateable.
+func (s *ServiceRunService) recordTransaction(ctx context.Context, run *models.ServiceRun, now time.Time) (*models.Transaction, error) {
 	serviceRunID := run.ID
 	itemDetails, _ := json.Marshal(map[string]string{
 		"serviceType": run.Service.ServiceType,
@@ -114,20 +157,10 @@ func (s *ServiceRunService) Complete(ctx context.Context, id string, userID stri
 		CreatedAt:    now,
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return nil, nil, err
-	}
-
-	// Notify the other party - service stays active
-	var recipientID string
-	if run.ProviderID == userID {
-		recipientID = run.ClientID
-	} else {
-		recipientID = run.ProviderID
+	if err := s.marketEvents.Record(ctx, s.transactionRepo, transaction, models.MarketEventSourceServiceRun, run.Service.Game, run.Service.ServiceType); err != nil {
+		return nil, err
 	}
-	_ = s.notificationService.NotifyServiceRunCompleted(ctx, recipientID, run.ID, run.Service.Name)
-
-	return run, transaction, nil
+	return transaction, nil
 }
 
 // Cancel cancels an active service run
@@ -158,6 +191,8 @@ func (s *ServiceRunService) Cancel(ctx context.Context, id string, userID string
 		return nil, err
 	}
 
+	s.chatArchiver.ArchiveServiceRunChat(ctx, run.ID, now)
+
 	// Notify the other party - service stays active
 	var recipientID string
 	if run.ProviderID == userID {
@@ -170,8 +205,176 @@ func (s *ServiceRunService) Cancel(ctx context.Context, id string, userID string
 	return run, nil
 }
 
-// List retrieves service runs for a user
+// OpenDispute lets either participant flag an active or completed run as disputed.
+// The run stays "disputed" until an admin resolves it, which blocks completing,
+// cancelling and rating it. The other participant and all admins are notified.
+func (s *ServiceRunService) OpenDispute(ctx context.Context, id string, userID string, reason string) (*models.Dispute, error) {
+	if s.disputeRepo == nil {
+		return nil, ErrInvalidState
+	}
+
+	reason, err := cleanText("reason", reason, maxDisputeReasonLength)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.repo.GetByIDWithRelations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.ProviderID != userID && run.ClientID != userID {
+		return nil, ErrForbidden
+	}
+
+	if !run.IsActive() && !run.IsCompleted() {
+		return nil, ErrInvalidState
+	}
+
+	existing, err := s.disputeRepo.GetOpenByTarget(ctx, models.DisputeTargetServiceRun, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyExists
+	}
+
+	now := time.Now()
+	dispute := &models.Dispute{
+		ID:             uuid.New().String(),
+		TargetType:     models.DisputeTargetServiceRun,
+		TargetID:       run.ID,
+		OpenedBy:       userID,
+		Reason:         reason,
+		PreviousStatus: run.Status,
+		Status:         models.DisputeStatusOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	run.Status = "disputed"
+	run.UpdatedAt = now
+	if err := s.repo.Update(ctx, run); err != nil {
+		return nil, err
+	}
+
+	otherID := run.ProviderID
+	if otherID == userID {
+		otherID = run.ClientID
+	}
+	_ = s.notificationService.NotifyServiceRunDisputed(ctx, otherID, run.ID, run.Service.Name)
+	s.notificationService.NotifyAdminsDisputeOpened(ctx, dispute, run.Service.Name)
+
+	return dispute, nil
+}
+
+// ResolveDispute settles an open dispute on a run. The outcome decides how the run
+// ends: completed (recording its transaction if it never got one) or cancelled.
+// Both participants are notified and can rate the run again.
+func (s *ServiceRunService) ResolveDispute(ctx context.Context, disputeID string, adminID string, outcome string, note string) (*models.Dispute, *models.ServiceRun, error) {
+	if s.disputeRepo == nil {
+		return nil, nil, ErrInvalidState
+	}
+
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !dispute.IsOpen() || dispute.TargetType != models.DisputeTargetServiceRun {
+		return nil, nil, ErrInvalidState
+	}
+
+	run, err := s.repo.GetByIDWithRelations(ctx, dispute.TargetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	switch outcome {
+	case models.DisputeOutcomeCompleted:
+		run.Status = "completed"
+		if run.CompletedAt == nil {
+			run.CompletedAt = &now
+		}
+	case models.DisputeOutcomeCancelled:
+		run.Status = "cancelled"
+		run.CancelledAt = &now
+		run.CancelledBy = &adminID
+		cancelReason := "Cancelled after dispute"
+		if note != "" {
+			cancelReason = note
+		}
+		run.CancelReason = &cancelReason
+	default:
+		return nil, nil, ErrInvalidState
+	}
+	run.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, run); err != nil {
+		return nil, nil, err
+	}
+
+	// A run disputed while active never got the transaction that makes it rateable
+	if outcome == models.DisputeOutcomeCompleted && dispute.PreviousStatus != "completed" {
+		if _, err := s.recordTransaction(ctx, run, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dispute.Status = models.DisputeStatusResolved
+	dispute.Outcome = &outcome
+	if note != "" {
+		dispute.ResolutionNote = &note
+	}
+	dispute.ResolvedBy = &adminID
+	dispute.ResolvedAt = &now
+	if err := s.disputeRepo.Update(ctx, dispute); err != nil {
+		return nil, nil, err
+	}
+
+	s.chatArchiver.ArchiveServiceRunChat(ctx, run.ID, now)
+
+	for _, participantID := range []string{run.ProviderID, run.ClientID} {
+		_ = s.notificationService.NotifyServiceRunDisputeResolved(ctx, participantID, run.ID, run.Service.Name, outcome)
+	}
+
+	return dispute, run, nil
+}
+
+// ListDisputes pages disputes for the admin queue, oldest first
+func (s *ServiceRunService) ListDisputes(ctx context.Context, status, targetType string, offset, limit int) ([]*models.Dispute, int, error) {
+	if s.disputeRepo == nil {
+		return []*models.Dispute{}, 0, nil
+	}
+	return s.disputeRepo.List(ctx, status, targetType, offset, limit)
+}
+
+// ToDisputeResponse converts a dispute model to a DTO response
+func (s *ServiceRunService) ToDisputeResponse(dispute *models.Dispute) *dto.DisputeResponse {
+	return &dto.DisputeResponse{
+		ID:             dispute.ID,
+		TargetType:     dispute.TargetType,
+		TargetID:       dispute.TargetID,
+		OpenedBy:       dispute.OpenedBy,
+		Reason:         dispute.Reason,
+		Status:         dispute.Status,
+		Outcome:        dispute.GetOutcome(),
+		ResolutionNote: dispute.GetResolutionNote(),
+		CreatedAt:      dispute.CreatedAt,
+		ResolvedAt:     dispute.ResolvedAt,
+	}
+}
+
+// List retrieves service runs where the user is the client or provider.
+// Providers see their active runs by default, mirroring sellers' pending offers.
 func (s *ServiceRunService) List(ctx context.Context, userID string, role string, status string, offset, limit int) ([]*models.ServiceRun, int, error) {
+	if role == "provider" && status == "" {
+		status = "active"
+	}
+
 	filter := repository.ServiceRunFilter{
 		UserID: userID,
 		Role:   role,
@@ -252,5 +455,6 @@ func (s *ServiceRunService) ToDetailResponse(ctx context.Context, run *models.Se
 		CanComplete:        run.IsActive() && (run.ProviderID == userID || run.ClientID == userID),
 		CanCancel:          run.IsActive(),
 		CanMessage:         run.IsActive(),
+		CanDispute:         s.disputeRepo != nil && (run.IsActive() || run.IsCompleted()) && (run.ProviderID == userID || run.ClientID == userID),
 	}
 }
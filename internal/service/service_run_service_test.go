@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------- helpers ----------
+
+func newServiceRunTestService() (*ServiceRunService, *mocks.MockServiceRunRepository) {
+	runRepo := new(mocks.MockServiceRunRepository)
+	profileService := NewProfileService(nil, nil, nil)
+	serviceService := NewServiceService(nil, profileService, nil)
+	notifService := NewNotificationService(new(mocks.MockNotificationRepository), nil)
+
+	svc := NewServiceRunService(
+		runRepo,
+		new(mocks.MockTransactionRepository),
+		new(mocks.MockRatingRepository),
+		new(mocks.MockChatRepository),
+		notifService,
+		profileService,
+		serviceService,
+		nil, // redis
+	)
+	return svc, runRepo
+}
+
+// ---------- List ----------
+
+func TestListServiceRuns_ProviderDefaultsActive(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	runs := []*models.ServiceRun{testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)}
+	runRepo.On("List", ctx, mock.MatchedBy(func(f repository.ServiceRunFilter) bool {
+		return f.UserID == testProviderID && f.Role == "provider" && f.Status == "active" &&
+			f.Offset == 20 && f.Limit == 20
+	})).Return(runs, 21, nil)
+
+	result, count, err := svc.List(ctx, testProviderID, "provider", "", 20, 20)
+
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 21, count)
+}
+
+func TestListServiceRuns_ProviderExplicitStatus(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	runRepo.On("List", ctx, mock.MatchedBy(func(f repository.ServiceRunFilter) bool {
+		return f.Role == "provider" && f.Status == "completed"
+	})).Return([]*models.ServiceRun{}, 0, nil)
+
+	_, _, err := svc.List(ctx, testProviderID, "provider", "completed", 0, 20)
+
+	require.NoError(t, err)
+	runRepo.AssertExpectations(t)
+}
+
+func TestListServiceRuns_ClientNoDefault(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	runRepo.On("List", ctx, mock.MatchedBy(func(f repository.ServiceRunFilter) bool {
+		return f.UserID == testClientID && f.Role == "client" && f.Status == ""
+	})).Return([]*models.ServiceRun{}, 0, nil)
+
+	_, _, err := svc.List(ctx, testClientID, "client", "", 0, 20)
+
+	require.NoError(t, err)
+	runRepo.AssertExpectations(t)
+}
+
+func TestServiceRunToResponse_IncludesParticipantsAndService(t *testing.T) {
+	svc, _ := newServiceRunTestService()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	run.Service = &models.Service{ID: testServiceID, Name: "Baal Runs", ServiceType: "rush"}
+	run.Provider = testProfile(testProviderID)
+	run.Client = testProfile(testClientID)
+
+	resp := svc.ToResponse(run)
+
+	require.NotNil(t, resp.Service)
+	assert.Equal(t, "Baal Runs", resp.Service.Name)
+	require.NotNil(t, resp.Provider)
+	assert.Equal(t, testProviderID, resp.Provider.ID)
+	require.NotNil(t, resp.Client)
+	assert.Equal(t, testClientID, resp.Client.ID)
+}
+
+// ---------- GetByID ----------
+
+func TestServiceRunGetByID_Participants(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(run, nil)
+
+	for _, userID := range []string{testProviderID, testClientID} {
+		result, err := svc.GetByID(ctx, testServiceRunID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, testServiceRunID, result.ID)
+	}
+}
+
+func TestServiceRunGetByID_NonParticipantForbidden(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(run, nil)
+
+	result, err := svc.GetByID(ctx, testServiceRunID, testUserID)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Nil(t, result)
+}
+
+func TestServiceRunGetByID_NotFound(t *testing.T) {
+	svc, runRepo := newServiceRunTestService()
+	ctx := context.Background()
+
+	runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(nil, sql.ErrNoRows)
+
+	result, err := svc.GetByID(ctx, testServiceRunID, testProviderID)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, result)
+}
+
+// ---------- ToDetailResponse ----------
+
+func TestServiceRunToDetailResponse_ActiveRun(t *testing.T) {
+	svc, _ := newServiceRunTestService()
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID)
+	run.Chat = &models.Chat{ID: testChatID}
+
+	resp := svc.ToDetailResponse(context.Background(), run, testClientID)
+
+	assert.True(t, resp.CanComplete)
+	assert.True(t, resp.CanCancel)
+	assert.True(t, resp.CanMessage)
+	assert.False(t, resp.CanRate)
+	require.NotNil(t, resp.ChatID)
+	assert.Equal(t, testChatID, *resp.ChatID)
+}
+
+func TestServiceRunToDetailResponse_CompletedRun(t *testing.T) {
+	ctx := context.Background()
+	transactionRepo := new(mocks.MockTransactionRepository)
+	ratingRepo := new(mocks.MockRatingRepository)
+	profileService := NewProfileService(nil, nil, nil)
+	svc := NewServiceRunService(
+		new(mocks.MockServiceRunRepository),
+		transactionRepo,
+		ratingRepo,
+		new(mocks.MockChatRepository),
+		NewNotificationService(new(mocks.MockNotificationRepository), nil),
+		profileService,
+		NewServiceService(nil, profileService, nil),
+		nil, // redis
+	)
+
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID,
+		withServiceRunStatus("completed"))
+	transaction := testTransaction(testTransactionID, testProviderID, testClientID)
+	transactionRepo.On("GetByServiceRunID", ctx, testServiceRunID).Return(transaction, nil)
+	ratingRepo.On("Exists", ctx, testTransactionID, testClientID).Return(false, nil)
+	ratingRepo.On("Exists", ctx, testTransactionID, testProviderID).Return(true, nil)
+
+	clientResp := svc.ToDetailResponse(ctx, run, testClientID)
+	assert.False(t, clientResp.CanComplete)
+	assert.False(t, clientResp.CanCancel)
+	assert.False(t, clientResp.CanMessage)
+	assert.True(t, clientResp.CanRate)
+	require.NotNil(t, clientResp.TransactionID)
+	assert.Equal(t, testTransactionID, *clientResp.TransactionID)
+
+	providerResp := svc.ToDetailResponse(ctx, run, testProviderID)
+	assert.False(t, providerResp.CanRate)
+}
+
+// ---------- Disputes ----------
+
+const testDisputeAdminID = "admin-kkk"
+
+type disputeTestDeps struct {
+	runRepo         *mocks.MockServiceRunRepository
+	disputeRepo     *mocks.MockDisputeRepository
+	transactionRepo *mocks.MockTransactionRepository
+	notifRepo       *mocks.MockNotificationRepository
+}
+
+func newDisputeTestService() (*ServiceRunService, disputeTestDeps) {
+	deps := disputeTestDeps{
+		runRepo:         new(mocks.MockServiceRunRepository),
+		disputeRepo:     new(mocks.MockDisputeRepository),
+		transactionRepo: new(mocks.MockTransactionRepository),
+		notifRepo:       new(mocks.MockNotificationRepository),
+	}
+	profileService := NewProfileService(nil, nil, nil)
+	svc := NewServiceRunService(
+		deps.runRepo,
+		deps.transactionRepo,
+		new(mocks.MockRatingRepository),
+		new(mocks.MockChatRepository),
+		NewNotificationService(deps.notifRepo, nil),
+		profileService,
+		NewServiceService(nil, profileService, nil),
+		nil, // redis
+	)
+	svc.SetDisputeRepository(deps.disputeRepo)
+	deps.notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	return svc, deps
+}
+
+func disputableRun(status string) *models.ServiceRun {
+	run := testServiceRun(testServiceRunID, testServiceID, testOfferID, testProviderID, testClientID,
+		withServiceRunStatus(status))
+	run.Service = &models.Service{ID: testServiceID, Name: "Baal Runs", ServiceType: "rush", Game: "diablo2"}
+	run.Offer = &models.Offer{ID: testOfferID}
+	return run
+}
+
+func TestServiceRunOpenDispute_MarksRunDisputed(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	deps.runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(disputableRun("active"), nil)
+	deps.disputeRepo.On("GetOpenByTarget", ctx, models.DisputeTargetServiceRun, testServiceRunID).Return(nil, nil)
+	deps.disputeRepo.On("Create", ctx, mock.MatchedBy(func(d *models.Dispute) bool {
+		return d.TargetType == models.DisputeTargetServiceRun && d.TargetID == testServiceRunID &&
+			d.OpenedBy == testClientID && d.PreviousStatus == "active" && d.IsOpen()
+	})).Return(nil)
+	deps.runRepo.On("Update", ctx, mock.MatchedBy(func(r *models.ServiceRun) bool {
+		return r.Status == "disputed"
+	})).Return(nil)
+
+	dispute, err := svc.OpenDispute(ctx, testServiceRunID, testClientID, "Provider never showed up")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Provider never showed up", dispute.Reason)
+	deps.runRepo.AssertExpectations(t)
+	deps.disputeRepo.AssertExpectations(t)
+	deps.notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testProviderID && n.Type == models.NotificationTypeServiceRunDisputed
+	}))
+}
+
+func TestServiceRunOpenDispute_NonParticipantForbidden(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	deps.runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(disputableRun("active"), nil)
+
+	_, err := svc.OpenDispute(ctx, testServiceRunID, testUserID, "Not my run")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	deps.disputeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestServiceRunOpenDispute_CancelledRunRejected(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	deps.runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(disputableRun("cancelled"), nil)
+
+	_, err := svc.OpenDispute(ctx, testServiceRunID, testClientID, "Too late")
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestServiceRunResolveDispute_CompletedRecordsTransaction(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	dispute := &models.Dispute{
+		ID:             "dispute-1",
+		TargetType:     models.DisputeTargetServiceRun,
+		TargetID:       testServiceRunID,
+		PreviousStatus: "active",
+		Status:         models.DisputeStatusOpen,
+	}
+	deps.disputeRepo.On("GetByID", ctx, "dispute-1").Return(dispute, nil)
+	deps.runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(disputableRun("disputed"), nil)
+	deps.runRepo.On("Update", ctx, mock.MatchedBy(func(r *models.ServiceRun) bool {
+		return r.Status == "completed" && r.CompletedAt != nil
+	})).Return(nil)
+	deps.transactionRepo.On("Create", ctx, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.ServiceRunID != nil && *tx.ServiceRunID == testServiceRunID
+	})).Return(nil)
+	deps.disputeRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Dispute) bool {
+		return d.Status == models.DisputeStatusResolved && d.GetOutcome() == models.DisputeOutcomeCompleted
+	})).Return(nil)
+
+	_, run, err := svc.ResolveDispute(ctx, "dispute-1", testDisputeAdminID, models.DisputeOutcomeCompleted, "Run was delivered")
+
+	require.NoError(t, err)
+	assert.Equal(t, "completed", run.Status)
+	deps.transactionRepo.AssertExpectations(t)
+	deps.disputeRepo.AssertExpectations(t)
+}
+
+func TestServiceRunResolveDispute_CancelledAfterCompletionKeepsTransaction(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	dispute := &models.Dispute{
+		ID:             "dispute-1",
+		TargetType:     models.DisputeTargetServiceRun,
+		TargetID:       testServiceRunID,
+		PreviousStatus: "completed",
+		Status:         models.DisputeStatusOpen,
+	}
+	deps.disputeRepo.On("GetByID", ctx, "dispute-1").Return(dispute, nil)
+	deps.runRepo.On("GetByIDWithRelations", ctx, testServiceRunID).Return(disputableRun("disputed"), nil)
+	deps.runRepo.On("Update", ctx, mock.AnythingOfType("*models.ServiceRun")).Return(nil)
+	deps.disputeRepo.On("Update", ctx, mock.AnythingOfType("*models.Dispute")).Return(nil)
+
+	_, run, err := svc.ResolveDispute(ctx, "dispute-1", testDisputeAdminID, models.DisputeOutcomeCancelled, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", run.Status)
+	assert.Equal(t, testDisputeAdminID, run.GetCancelledBy())
+	deps.transactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestServiceRunResolveDispute_AlreadyResolved(t *testing.T) {
+	svc, deps := newDisputeTestService()
+	ctx := context.Background()
+
+	deps.disputeRepo.On("GetByID", ctx, "dispute-1").Return(&models.Dispute{
+		ID:         "dispute-1",
+		TargetType: models.DisputeTargetServiceRun,
+		Status:     models.DisputeStatusResolved,
+	}, nil)
+
+	_, _, err := svc.ResolveDispute(ctx, "dispute-1", testDisputeAdminID, models.DisputeOutcomeCompleted, "")
+
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
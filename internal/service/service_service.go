@@ -9,12 +9,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 )
 
-const maxRecentServices = 20
+const (
+	maxRecentServices  = 20
+	serviceCacheTTL    = 15 * time.Minute
+	serviceDTOCacheTTL = 1 * time.Hour
+
+	// DefaultMaxActiveServices caps active services for free providers
+	DefaultMaxActiveServices = 10
+	// DefaultMaxActiveServicesPremium caps active services for premium providers
+	DefaultMaxActiveServicesPremium = 30
+)
 
 // ServiceService handles service business logic
 type ServiceService struct {
@@ -22,20 +32,94 @@ type ServiceService struct {
 	profileService *ProfileService
 	redis          *cache.RedisClient
 	invalidator    *cache.Invalidator
+	gameRegistry   *games.Registry
+
+	maxActive        int
+	maxActivePremium int
+	textLimits       TextLimits
 }
 
 // NewServiceService creates a new service service
 func NewServiceService(repo repository.ServiceRepository, profileService *ProfileService, redis *cache.RedisClient) *ServiceService {
 	return &ServiceService{
-		repo:           repo,
-		profileService: profileService,
-		redis:          redis,
-		invalidator:    cache.NewInvalidator(redis),
+		repo:             repo,
+		profileService:   profileService,
+		redis:            redis,
+		invalidator:      cache.NewInvalidator(redis),
+		maxActive:        DefaultMaxActiveServices,
+		maxActivePremium: DefaultMaxActiveServicesPremium,
+		textLimits:       TextLimits{}.withDefaults(),
 	}
 }
 
-// Create creates a new service
+// SetTextLimits sets the maximum length of service descriptions and notes
+func (s *ServiceService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
+// SetGameRegistry sets the game registry used to validate regions
+func (s *ServiceService) SetGameRegistry(registry *games.Registry) {
+	s.gameRegistry = registry
+}
+
+// SetServiceLimits sets how many active services free and premium providers may have.
+// Values below 1 keep the defaults.
+func (s *ServiceService) SetServiceLimits(free, premium int) {
+	if free > 0 {
+		s.maxActive = free
+	}
+	if premium > 0 {
+		s.maxActivePremium = premium
+	}
+}
+
+// ServiceLimit returns the active service cap for a free or premium provider
+func (s *ServiceService) ServiceLimit(premium bool) int {
+	if premium {
+		return s.maxActivePremium
+	}
+	return s.maxActive
+}
+
+// ValidateCreate reports every field problem in a create request at once
+func (s *ServiceService) ValidateCreate(req *dto.CreateServiceRequest) ValidationErrors {
+	_, errs := s.validateCreate(req)
+	return errs
+}
+
+// validateCreate checks a create request against the game catalog and returns
+// the normalized region along with any field problems
+func (s *ServiceService) validateCreate(req *dto.CreateServiceRequest) (string, ValidationErrors) {
+	errs := ValidationErrors{}
+	validateRequired(errs, "serviceType", req.ServiceType)
+	validateRequired(errs, "name", req.Name)
+	validateRequired(errs, "game", req.Game)
+
+	game := newGameValidator(s.gameRegistry, req.Game, errs)
+	if req.ServiceType != "" && !game.validServiceType(req.ServiceType) {
+		errs.Add("serviceType", "unknown service type")
+	}
+	validatePlatforms(errs, req.Platforms, true)
+	region := validateRegion(errs, s.gameRegistry, req.Game, req.Region)
+	validateJSONField(errs, "askingFor", req.AskingFor)
+	validateText(errs, "description", req.Description, s.textLimits.Description)
+	validateText(errs, "notes", req.Notes, s.textLimits.Notes)
+
+	return region, errs
+}
+
+// Create creates a new service.
+// A repeated request with the same idempotency key returns the service created by the first.
 func (s *ServiceService) Create(ctx context.Context, providerID string, req *dto.CreateServiceRequest) (*models.Service, error) {
+	return createIdempotent(ctx, s.redis, "service", providerID, req.IdempotencyKey,
+		func() (*models.Service, error) { return s.create(ctx, providerID, req) },
+		func(svc *models.Service) string { return svc.ID },
+		func(id string) (*models.Service, error) { return s.repo.GetByID(ctx, id) },
+	)
+}
+
+// create performs the actual service creation
+func (s *ServiceService) create(ctx context.Context, providerID string, req *dto.CreateServiceRequest) (*models.Service, error) {
 	log := logger.FromContext(ctx)
 	log.Info("creating new service",
 		"provider_id", providerID,
@@ -43,6 +127,11 @@ func (s *ServiceService) Create(ctx context.Context, providerID string, req *dto
 		"game", req.Game,
 	)
 
+	region, errs := s.validateCreate(req)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check uniqueness: one service per type per provider per game
 	exists, err := s.repo.ExistsByProviderAndType(ctx, providerID, req.ServiceType, req.Game)
 	if err != nil {
@@ -52,6 +141,10 @@ func (s *ServiceService) Create(ctx context.Context, providerID string, req *dto
 		return nil, ErrAlreadyExists
 	}
 
+	if err := s.checkServiceLimit(ctx, providerID); err != nil {
+		return nil, err
+	}
+
 	// Deduplicate platforms
 	seen := make(map[string]bool)
 	var uniquePlatforms []string
@@ -73,21 +166,24 @@ func (s *ServiceService) Create(ctx context.Context, providerID string, req *dto
 		Hardcore:    req.Hardcore,
 		IsNonRotw:   req.IsNonRotw,
 		Platforms:   uniquePlatforms,
-		Region:      req.Region,
+		Region:      region,
 		Status:      "active",
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	if req.Description != "" {
-		service.Description = &req.Description
+	if description := sanitizePlainText(req.Description); description != "" {
+		service.Description = &description
 	}
 	if req.AskingPrice != "" {
 		service.AskingPrice = &req.AskingPrice
 	}
-	if req.Notes != "" {
-		service.Notes = &req.Notes
+	if notes := sanitizePlainText(req.Notes); notes != "" {
+		service.Notes = &notes
 	}
+	service.MinBuyerRating = req.MinBuyerRating
+	service.MinBuyerTrades = req.MinBuyerTrades
+	service.AllowUnratedBuyers = req.AllowUnratedBuyers
 
 	if err := s.repo.Create(ctx, service); err != nil {
 		log.Error("failed to create service", "error", err.Error())
@@ -112,6 +208,31 @@ func (s *ServiceService) Create(ctx context.Context, providerID string, req *dto
 	return service, nil
 }
 
+// checkServiceLimit returns ErrServiceLimitReached when the provider already has
+// as many active services as their plan allows. The profile is only loaded when
+// the count is between the free and premium caps.
+func (s *ServiceService) checkServiceLimit(ctx context.Context, providerID string) error {
+	count, err := s.repo.CountActiveByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if count < s.maxActive {
+		return nil
+	}
+	if count >= s.maxActivePremium {
+		return ErrServiceLimitReached
+	}
+
+	profile, err := s.profileService.GetByID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if count >= s.ServiceLimit(profile.IsPremium) {
+		return ErrServiceLimitReached
+	}
+	return nil
+}
+
 // Update updates a service
 func (s *ServiceService) Update(ctx context.Context, id string, userID string, req *dto.UpdateServiceRequest) (*models.Service, error) {
 	service, err := s.repo.GetByID(ctx, id)
@@ -127,7 +248,11 @@ func (s *ServiceService) Update(ctx context.Context, id string, userID string, r
 		service.Name = *req.Name
 	}
 	if req.Description != nil {
-		service.Description = req.Description
+		description, err := cleanText("description", *req.Description, s.textLimits.Description)
+		if err != nil {
+			return nil, err
+		}
+		service.Description = &description
 	}
 	if req.AskingPrice != nil {
 		service.AskingPrice = req.AskingPrice
@@ -136,13 +261,26 @@ func (s *ServiceService) Update(ctx context.Context, id string, userID string, r
 		service.AskingFor = req.AskingFor
 	}
 	if req.Notes != nil {
-		service.Notes = req.Notes
+		notes, err := cleanText("notes", *req.Notes, s.textLimits.Notes)
+		if err != nil {
+			return nil, err
+		}
+		service.Notes = &notes
 	}
 	if len(req.Platforms) > 0 {
 		service.Platforms = req.Platforms
 	}
 	if req.Region != nil {
-		service.Region = *req.Region
+		region, err := normalizeRegion(s.gameRegistry, service.Game, *req.Region)
+		if err != nil {
+			return nil, err
+		}
+		service.Region = region
+	}
+	service.MinBuyerRating = updatedThreshold(service.MinBuyerRating, req.MinBuyerRating)
+	service.MinBuyerTrades = updatedThreshold(service.MinBuyerTrades, req.MinBuyerTrades)
+	if req.AllowUnratedBuyers != nil {
+		service.AllowUnratedBuyers = *req.AllowUnratedBuyers
 	}
 	service.UpdatedAt = time.Now()
 
@@ -225,6 +363,10 @@ func (s *ServiceService) Resume(ctx context.Context, id string, userID string) e
 		return ErrInvalidState
 	}
 
+	if err := s.checkServiceLimit(ctx, service.ProviderID); err != nil {
+		return err
+	}
+
 	service.Status = "active"
 	service.UpdatedAt = time.Now()
 	if err := s.repo.Update(ctx, service); err != nil {
@@ -242,9 +384,41 @@ func (s *ServiceService) Resume(ctx context.Context, id string, userID string) e
 	return nil
 }
 
-// GetByID retrieves a service by ID
+// GetByID retrieves a service by ID with caching
 func (s *ServiceService) GetByID(ctx context.Context, id string) (*models.Service, error) {
-	return s.repo.GetByIDWithProvider(ctx, id)
+	// Try cache first
+	cacheKey := cache.ServiceKey(id)
+	cached, err := s.redis.Get(ctx, cacheKey)
+	if err == nil && cached != "" {
+		var service models.Service
+		if json.Unmarshal([]byte(cached), &service) == nil {
+			return &service, nil
+		}
+	}
+
+	// Fetch from database
+	service, err := s.repo.GetByIDWithProvider(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result
+	if data, err := json.Marshal(service); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), serviceCacheTTL)
+	}
+
+	// Cache DTO version for frontend direct access
+	s.cacheServiceDTO(ctx, service)
+
+	return service, nil
+}
+
+// cacheServiceDTO caches the service as a DTO (camelCase JSON) for frontend direct access
+func (s *ServiceService) cacheServiceDTO(ctx context.Context, service *models.Service) {
+	resp := s.ToServiceResponse(service)
+	if data, err := json.Marshal(resp); err == nil {
+		_ = s.redis.Set(ctx, cache.ServiceDTOKey(service.ID), string(data), serviceDTOCacheTTL)
+	}
 }
 
 // ListMyServices lists services for a provider
@@ -254,6 +428,12 @@ func (s *ServiceService) ListMyServices(ctx context.Context, providerID string,
 
 // ListProviders lists provider cards with their services
 func (s *ServiceService) ListProviders(ctx context.Context, filter repository.ServiceProviderFilter) ([]dto.ProviderCardResponse, int, error) {
+	region, err := normalizeRegion(s.gameRegistry, filter.Game, filter.Region)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.Region = region
+
 	providers, count, err := s.repo.ListProviders(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -301,11 +481,13 @@ func (s *ServiceService) ToServiceResponse(service *models.Service) *dto.Service
 		Hardcore:    service.Hardcore,
 		IsNonRotw:   service.IsNonRotw,
 		Platforms:   service.Platforms,
-		Region:      service.Region,
+		Region:      displayRegion(s.gameRegistry, service.Game, service.Region),
 		Notes:       service.GetNotes(),
 		Status:      service.Status,
 		CreatedAt:   service.CreatedAt,
 		UpdatedAt:   service.UpdatedAt,
+
+		BuyerRequirements: toBuyerRequirementsResponse(service.BuyerRequirements()),
 	}
 }
 
@@ -42,6 +42,7 @@ func TestServiceCreate_Success(t *testing.T) {
 	askingFor := json.RawMessage(`[{"name":"Ist","quantity":1}]`)
 
 	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(0, nil)
 	serviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
 	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
 
@@ -119,6 +120,95 @@ func TestServiceCreate_DuplicateTypeProviderGame(t *testing.T) {
 	serviceRepo.AssertExpectations(t)
 }
 
+func TestServiceCreate_FreeProviderAtLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+
+	ctx := context.Background()
+
+	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(DefaultMaxActiveServices, nil)
+	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
+
+	req := &dto.CreateServiceRequest{
+		ServiceType: "rush",
+		Name:        "Normal Rush",
+		Game:        "diablo2",
+		Platforms:   []string{"pc"},
+		Region:      "americas",
+	}
+
+	result, err := svc.Create(ctx, testProviderID, req)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrServiceLimitReached)
+	serviceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestServiceCreate_PremiumProviderUsesHigherLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+
+	ctx := context.Background()
+
+	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(DefaultMaxActiveServices, nil)
+	serviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
+	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID, withPremium), nil)
+
+	req := &dto.CreateServiceRequest{
+		ServiceType: "rush",
+		Name:        "Normal Rush",
+		Game:        "diablo2",
+		Platforms:   []string{"pc"},
+		Region:      "americas",
+	}
+
+	result, err := svc.Create(ctx, testProviderID, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestServiceCreate_PremiumProviderAtLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+	svc.SetServiceLimits(2, 5)
+
+	ctx := context.Background()
+
+	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(5, nil)
+
+	req := &dto.CreateServiceRequest{
+		ServiceType: "rush",
+		Name:        "Normal Rush",
+		Game:        "diablo2",
+		Platforms:   []string{"pc"},
+		Region:      "americas",
+	}
+
+	_, err := svc.Create(ctx, testProviderID, req)
+
+	assert.ErrorIs(t, err, ErrServiceLimitReached)
+	profileRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestServiceSetServiceLimits_KeepsDefaultsForZero(t *testing.T) {
+	svc := NewServiceService(new(mocks.MockServiceRepository), nil, nil)
+
+	svc.SetServiceLimits(0, 0)
+	assert.Equal(t, DefaultMaxActiveServices, svc.ServiceLimit(false))
+	assert.Equal(t, DefaultMaxActiveServicesPremium, svc.ServiceLimit(true))
+
+	svc.SetServiceLimits(3, 12)
+	assert.Equal(t, 3, svc.ServiceLimit(false))
+	assert.Equal(t, 12, svc.ServiceLimit(true))
+}
+
 func TestServiceCreate_DeduplicatesPlatforms(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	serviceRepo := new(mocks.MockServiceRepository)
@@ -127,6 +217,7 @@ func TestServiceCreate_DeduplicatesPlatforms(t *testing.T) {
 	ctx := context.Background()
 
 	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(0, nil)
 	serviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
 	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
 
@@ -155,6 +246,7 @@ func TestServiceCreate_OptionalFieldsEmpty(t *testing.T) {
 	ctx := context.Background()
 
 	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "rush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(0, nil)
 	serviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
 	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
 
@@ -188,6 +280,7 @@ func TestServiceCreate_OptionalFieldsSet(t *testing.T) {
 	ctx := context.Background()
 
 	serviceRepo.On("ExistsByProviderAndType", mock.Anything, testProviderID, "crush", "diablo2").Return(false, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(0, nil)
 	serviceRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
 	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
 
@@ -402,6 +495,62 @@ func TestServiceGetByID_NotFound(t *testing.T) {
 	serviceRepo.AssertExpectations(t)
 }
 
+func TestServiceGetByID_WritesToCache(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, redisClient)
+
+	serviceRepo.On("GetByIDWithProvider", mock.Anything, testServiceID).Return(testServiceModel(testServiceID, testProviderID), nil)
+
+	_, err := svc.GetByID(context.Background(), testServiceID)
+	assert.NoError(t, err)
+
+	assert.True(t, mr.Exists(cache.ServiceKey(testServiceID)), "service cache key should exist after GetByID")
+	assert.True(t, mr.Exists(cache.ServiceDTOKey(testServiceID)), "service DTO cache key should exist after GetByID")
+}
+
+func TestServiceGetByID_CacheHit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	redisClient, _ := newTestRedisReal(t)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, redisClient)
+
+	serviceRepo.On("GetByIDWithProvider", mock.Anything, testServiceID).Return(testServiceModel(testServiceID, testProviderID), nil).Once()
+
+	_, err := svc.GetByID(context.Background(), testServiceID)
+	assert.NoError(t, err)
+
+	result, err := svc.GetByID(context.Background(), testServiceID)
+	assert.NoError(t, err)
+	assert.Equal(t, testServiceID, result.ID)
+
+	serviceRepo.AssertNumberOfCalls(t, "GetByIDWithProvider", 1)
+}
+
+func TestServiceUpdate_InvalidatesCache(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	redisClient, mr := newTestRedisReal(t)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, redisClient)
+
+	existing := testServiceModel(testServiceID, testProviderID)
+	serviceRepo.On("GetByIDWithProvider", mock.Anything, testServiceID).Return(existing, nil)
+	serviceRepo.On("GetByID", mock.Anything, testServiceID).Return(existing, nil)
+	serviceRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
+
+	_, err := svc.GetByID(context.Background(), testServiceID)
+	assert.NoError(t, err)
+	assert.True(t, mr.Exists(cache.ServiceKey(testServiceID)))
+
+	name := "Updated"
+	_, err = svc.Update(context.Background(), testServiceID, testProviderID, &dto.UpdateServiceRequest{Name: &name})
+	assert.NoError(t, err)
+
+	assert.False(t, mr.Exists(cache.ServiceKey(testServiceID)))
+	assert.False(t, mr.Exists(cache.ServiceDTOKey(testServiceID)))
+}
+
 // ---------------------------------------------------------------------------
 // ToServiceResponse
 // ---------------------------------------------------------------------------
@@ -729,6 +878,7 @@ func TestServiceResume_Success(t *testing.T) {
 	assert.Equal(t, "paused", existing.Status, "precondition: service starts as paused")
 
 	serviceRepo.On("GetByID", mock.Anything, testServiceID).Return(existing, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(0, nil)
 	serviceRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
 	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
 
@@ -740,6 +890,25 @@ func TestServiceResume_Success(t *testing.T) {
 	serviceRepo.AssertExpectations(t)
 }
 
+func TestServiceResume_AtLimit(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+
+	ctx := context.Background()
+
+	existing := testServiceModel(testServiceID, testProviderID, withServiceStatus("paused"))
+	serviceRepo.On("GetByID", mock.Anything, testServiceID).Return(existing, nil)
+	serviceRepo.On("CountActiveByProviderID", mock.Anything, testProviderID).Return(DefaultMaxActiveServices, nil)
+	profileRepo.On("GetByID", mock.Anything, testProviderID).Return(testProfile(testProviderID), nil)
+
+	err := svc.Resume(ctx, testServiceID, testProviderID)
+
+	assert.ErrorIs(t, err, ErrServiceLimitReached)
+	assert.Equal(t, "paused", existing.Status)
+	serviceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
 func TestServiceResume_NotOwner(t *testing.T) {
 	profileRepo := new(mocks.MockProfileRepository)
 	serviceRepo := new(mocks.MockServiceRepository)
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+const (
+	// churnListingMaxAge is how young a cancelled listing must be to count as churn
+	churnListingMaxAge = time.Hour
+	// churnWindow is how far back quickly cancelled listings are counted
+	churnWindow = 24 * time.Hour
+	// delayedRecentBatchSize is how many held listings one release step claims at a time
+	delayedRecentBatchSize = 100
+)
+
+// delayRecentPush holds a shadow-throttled seller's listing back from home:recent until
+// its throttle delay has passed. ReleaseDelayedRecent pushes it once it is due.
+func (s *ListingService) delayRecentPush(ctx context.Context, listing *models.Listing) {
+	releaseAt := s.profileService.ShadowThrottleReleaseAt(listing.CreatedAt)
+	if err := s.redis.ZAdd(ctx, cache.RecentDelayedKey(), float64(releaseAt.Unix()), listing.ID); err != nil {
+		logger.FromContext(ctx).Warn("failed to delay recent listing push",
+			"error", err.Error(),
+			"listing_id", listing.ID,
+		)
+	}
+}
+
+// ReleaseDelayedRecent pushes held listings whose throttle delay has passed to
+// home:recent and returns how many were pushed. Each listing is claimed with ZREM,
+// so overlapping runs push it once; listings no longer active are dropped.
+func (s *ListingService) ReleaseDelayedRecent(ctx context.Context) (int, error) {
+	if !s.redis.IsAvailable() {
+		return 0, nil
+	}
+
+	released := 0
+	for {
+		now := float64(time.Now().Unix())
+		ids, err := s.redis.ZRangeByScoreMax(ctx, cache.RecentDelayedKey(), now, delayedRecentBatchSize)
+		if err != nil {
+			return released, err
+		}
+		if len(ids) == 0 {
+			return released, nil
+		}
+
+		for _, id := range ids {
+			claimed, err := s.redis.ZRem(ctx, cache.RecentDelayedKey(), id)
+			if err != nil {
+				return released, err
+			}
+			if claimed == 0 {
+				continue
+			}
+
+			listing, err := s.repo.GetByIDWithSeller(ctx, id)
+			if err != nil || listing.Status != "active" {
+				continue
+			}
+			s.removeFromRecentListings(ctx, listing.ID)
+			s.pushRecentCard(ctx, listing)
+			released++
+		}
+	}
+}
+
+// RunRecentReleaser releases due held listings every interval until ctx is cancelled
+func (s *ListingService) RunRecentReleaser(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.ReleaseDelayedRecent(ctx)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to release delayed recent listings",
+					"error", err.Error(),
+					"released", n,
+				)
+				continue
+			}
+			if n > 0 {
+				logger.FromContext(ctx).Debug("released delayed recent listings", "listings", n)
+			}
+		}
+	}
+}
+
+// checkListingChurn adds a point to the seller's abuse score when a listing cancelled
+// within churnListingMaxAge of its creation takes them past the daily churn limit
+func (s *ListingService) checkListingChurn(ctx context.Context, listing *models.Listing) {
+	limit := s.profileService.ListingChurnLimit()
+	if limit <= 0 || listing.UpdatedAt.Sub(listing.CreatedAt) >= churnListingMaxAge {
+		return
+	}
+
+	churned, err := s.repo.CountChurnedBySeller(ctx, listing.SellerID, time.Now().Add(-churnWindow), churnListingMaxAge)
+	if err != nil || churned <= limit {
+		return
+	}
+
+	if _, err := s.profileService.AdjustAbuseScore(ctx, listing.SellerID, 1, "listing churn"); err != nil {
+		logger.FromContext(ctx).Warn("failed to record listing churn",
+			"error", err.Error(),
+			"seller_id", listing.SellerID,
+		)
+	}
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
@@ -11,13 +14,17 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
 )
 
-const homeStatsTTL = 5 * time.Minute
+const (
+	homeStatsTTL    = 5 * time.Minute
+	priceSummaryTTL = 15 * time.Minute
+)
 
 // StatsService handles marketplace statistics business logic
 type StatsService struct {
-	repo        repository.StatsRepository
-	redis       *cache.RedisClient
-	invalidator *cache.Invalidator
+	repo            repository.StatsRepository
+	transactionRepo repository.TransactionRepository
+	redis           *cache.RedisClient
+	invalidator     *cache.Invalidator
 }
 
 // NewStatsService creates a new stats service
@@ -29,6 +36,11 @@ func NewStatsService(repo repository.StatsRepository, redis *cache.RedisClient)
 	}
 }
 
+// SetTransactionRepository sets the transaction repository used for price summaries
+func (s *StatsService) SetTransactionRepository(repo repository.TransactionRepository) {
+	s.transactionRepo = repo
+}
+
 // GetMarketplaceStats retrieves marketplace statistics, checking home:stats cache first
 func (s *StatsService) GetMarketplaceStats(ctx context.Context) (*dto.MarketplaceStatsResponse, error) {
 	// Try cache first
@@ -86,3 +98,155 @@ func (s *StatsService) RefreshHomeStats(ctx context.Context) {
 func (s *StatsService) WarmHomeStats(ctx context.Context) {
 	s.RefreshHomeStats(ctx)
 }
+
+// GetItemPriceSummary returns what an item typically sells for over the last days,
+// aggregated from completed trade transactions. Unlike the premium price history
+// it only exposes the typical bundles and the sample size.
+func (s *StatsService) GetItemPriceSummary(ctx context.Context, itemName string, game string, days int) (*dto.ItemPriceSummaryResponse, error) {
+	if days <= 0 || days > 90 {
+		days = 30
+	}
+
+	cacheKey := cache.PriceSummaryKey(game, days, strings.ToLower(itemName))
+	if cached, err := s.redis.Get(ctx, cacheKey); err == nil && cached != "" {
+		var summary dto.ItemPriceSummaryResponse
+		if json.Unmarshal([]byte(cached), &summary) == nil {
+			return &summary, nil
+		}
+	}
+
+	records, err := s.transactionRepo.GetPriceHistory(ctx, itemName, game, days)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizePrices(records)
+	summary.ItemName = itemName
+	summary.Game = game
+	summary.Days = days
+	summary.LastUpdated = time.Now()
+
+	if data, err := json.Marshal(summary); err == nil {
+		_ = s.redis.Set(ctx, cacheKey, string(data), priceSummaryTTL)
+	}
+
+	return summary, nil
+}
+
+// summarizePrices aggregates price history records into the most common offered
+// bundle and a median bundle. The median bundle holds every item offered in at
+// least half of the trades at its median quantity, or the most frequently offered
+// item when no item reaches that share.
+func summarizePrices(records []repository.PriceHistoryRecord) *dto.ItemPriceSummaryResponse {
+	type itemStats struct {
+		item       dto.OfferedItemResponse
+		quantities []int
+	}
+
+	summary := &dto.ItemPriceSummaryResponse{MedianBundle: []dto.OfferedItemResponse{}}
+
+	itemsByName := make(map[string]*itemStats)
+	var itemOrder []string
+	bundles := make(map[string]*dto.PriceSummaryBundle)
+	var bundleOrder []string
+
+	for _, rec := range records {
+		items := mergeOfferedItems(transformPriceHistoryItems(rec.OfferedItems))
+		if len(items) == 0 {
+			continue
+		}
+		summary.SampleSize++
+
+		for _, item := range items {
+			key := strings.ToLower(item.Name)
+			stats, ok := itemsByName[key]
+			if !ok {
+				stats = &itemStats{item: item}
+				itemsByName[key] = stats
+				itemOrder = append(itemOrder, key)
+			}
+			stats.quantities = append(stats.quantities, item.Quantity)
+		}
+
+		signature := bundleSignature(items)
+		bundle, ok := bundles[signature]
+		if !ok {
+			bundle = &dto.PriceSummaryBundle{Items: items}
+			bundles[signature] = bundle
+			bundleOrder = append(bundleOrder, signature)
+		}
+		bundle.Count++
+	}
+
+	if summary.SampleSize == 0 {
+		return summary
+	}
+
+	for _, signature := range bundleOrder {
+		if summary.ModeBundle == nil || bundles[signature].Count > summary.ModeBundle.Count {
+			summary.ModeBundle = bundles[signature]
+		}
+	}
+
+	var mostFrequent *itemStats
+	for _, key := range itemOrder {
+		stats := itemsByName[key]
+		if mostFrequent == nil || len(stats.quantities) > len(mostFrequent.quantities) {
+			mostFrequent = stats
+		}
+		if len(stats.quantities)*2 >= summary.SampleSize {
+			summary.MedianBundle = append(summary.MedianBundle, medianItem(stats.item, stats.quantities))
+		}
+	}
+	if len(summary.MedianBundle) == 0 {
+		summary.MedianBundle = append(summary.MedianBundle, medianItem(mostFrequent.item, mostFrequent.quantities))
+	}
+
+	return summary
+}
+
+// mergeOfferedItems combines repeated items in a single offer and sorts them by name
+func mergeOfferedItems(items []dto.OfferedItemResponse) []dto.OfferedItemResponse {
+	merged := make(map[string]*dto.OfferedItemResponse)
+	var order []string
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		if item.Quantity <= 0 {
+			item.Quantity = 1
+		}
+		key := strings.ToLower(item.Name)
+		if existing, ok := merged[key]; ok {
+			existing.Quantity += item.Quantity
+			continue
+		}
+		copied := item
+		merged[key] = &copied
+		order = append(order, key)
+	}
+
+	sort.Strings(order)
+	result := make([]dto.OfferedItemResponse, 0, len(order))
+	for _, key := range order {
+		result = append(result, *merged[key])
+	}
+	return result
+}
+
+// bundleSignature identifies a set of offered items regardless of order
+func bundleSignature(items []dto.OfferedItemResponse) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s:%d", strings.ToLower(item.Name), item.Quantity))
+	}
+	return strings.Join(parts, "|")
+}
+
+// medianItem returns the item with its lower median quantity
+func medianItem(item dto.OfferedItemResponse, quantities []int) dto.OfferedItemResponse {
+	sorted := append([]int(nil), quantities...)
+	sort.Ints(sorted)
+	item.Quantity = sorted[(len(sorted)-1)/2]
+	return item
+}
@@ -284,3 +284,78 @@ func TestNewStatsService_ReturnsValidInstance(t *testing.T) {
 	assert.NotNil(t, svc.redis)
 	assert.NotNil(t, svc.invalidator)
 }
+
+// ---------------------------------------------------------------------------
+// GetItemPriceSummary
+// ---------------------------------------------------------------------------
+
+func TestGetItemPriceSummary_AggregatesBundles(t *testing.T) {
+	txnRepo := new(mocks.MockTransactionRepository)
+	rc, mr := newTestRedisReal(t)
+	svc := NewStatsService(new(mocks.MockStatsRepository), rc)
+	svc.SetTransactionRepository(txnRepo)
+
+	records := []repository.PriceHistoryRecord{
+		{Date: "2024-01-15", OfferedItems: []byte(`[{"name":"Ist","type":"rune","quantity":2}]`)},
+		{Date: "2024-01-15", OfferedItems: []byte(`[{"name":"Ist","type":"rune","quantity":1},{"name":"Ist","type":"rune","quantity":1}]`)},
+		{Date: "2024-01-16", OfferedItems: []byte(`[{"name":"Ist","type":"rune","quantity":3},{"name":"Pul","type":"rune","quantity":1}]`)},
+		{Date: "2024-01-17", OfferedItems: []byte(`[{"name":"Um","type":"rune","quantity":4}]`)},
+		{Date: "2024-01-17", OfferedItems: []byte(`[]`)},
+	}
+	txnRepo.On("GetPriceHistory", mock.Anything, "Shako", "diablo2", 30).Return(records, nil).Once()
+
+	result, err := svc.GetItemPriceSummary(context.Background(), "Shako", "diablo2", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.SampleSize)
+	assert.Equal(t, 30, result.Days)
+
+	// Ist appears in 3 of 4 trades with quantities 2, 2, 3 => median 2
+	assert.Len(t, result.MedianBundle, 1)
+	assert.Equal(t, "Ist", result.MedianBundle[0].Name)
+	assert.Equal(t, 2, result.MedianBundle[0].Quantity)
+
+	// Two trades paid exactly 2 Ist
+	assert.NotNil(t, result.ModeBundle)
+	assert.Equal(t, 2, result.ModeBundle.Count)
+	assert.Len(t, result.ModeBundle.Items, 1)
+	assert.Equal(t, 2, result.ModeBundle.Items[0].Quantity)
+
+	assert.True(t, mr.Exists("price:summary:diablo2:30:shako"))
+
+	// Second call is served from cache
+	cached, err := svc.GetItemPriceSummary(context.Background(), "SHAKO", "diablo2", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, cached.SampleSize)
+	txnRepo.AssertExpectations(t)
+}
+
+func TestGetItemPriceSummary_NoTrades(t *testing.T) {
+	txnRepo := new(mocks.MockTransactionRepository)
+	svc := NewStatsService(new(mocks.MockStatsRepository), newTestRedis())
+	svc.SetTransactionRepository(txnRepo)
+
+	txnRepo.On("GetPriceHistory", mock.Anything, "Shako", "", 30).Return([]repository.PriceHistoryRecord{}, nil)
+
+	result, err := svc.GetItemPriceSummary(context.Background(), "Shako", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.SampleSize)
+	assert.Empty(t, result.MedianBundle)
+	assert.Nil(t, result.ModeBundle)
+}
+
+func TestSummarizePrices_FallsBackToMostOfferedItem(t *testing.T) {
+	records := []repository.PriceHistoryRecord{
+		{OfferedItems: []byte(`[{"name":"Ist","type":"rune","quantity":1}]`)},
+		{OfferedItems: []byte(`[{"name":"Mal","type":"rune","quantity":2}]`)},
+		{OfferedItems: []byte(`[{"name":"Ist","type":"rune","quantity":3}]`)},
+		{OfferedItems: []byte(`[{"name":"Gul","type":"rune","quantity":1}]`)},
+		{OfferedItems: []byte(`[{"name":"Um","type":"rune","quantity":1}]`)},
+	}
+
+	result := summarizePrices(records)
+
+	assert.Equal(t, 5, result.SampleSize)
+	assert.Len(t, result.MedianBundle, 1)
+	assert.Equal(t, "Ist", result.MedianBundle[0].Name)
+	assert.Equal(t, 1, result.MedianBundle[0].Quantity)
+}
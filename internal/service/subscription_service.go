@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
@@ -27,8 +31,17 @@ type StripeConfig struct {
 	SuccessURL      string
 	CancelURL       string
 	AllowedPriceIDs []string // List of allowed price IDs for geo-based pricing
+	// GiftExtendsPremium lets a gift be bought for a user who already has premium;
+	// the gift then starts when their current period ends. When false such gifts are refused.
+	GiftExtendsPremium bool
 }
 
+// giftTrialMinLead is the shortest trial Stripe accepts when deferring a gift's first charge
+const giftTrialMinLead = 48 * time.Hour
+
+// billingEventGiftSent is the billing event recorded for the payer of a premium gift
+const billingEventGiftSent = "premium.gift_sent"
+
 // SubscriptionService handles premium subscription logic
 type SubscriptionService struct {
 	profileRepo     repository.ProfileRepository
@@ -39,6 +52,17 @@ type SubscriptionService struct {
 	redis           *cache.RedisClient
 	invalidator     *cache.Invalidator
 	config          StripeConfig
+
+	profileService      *ProfileService
+	notificationService *NotificationService
+	// freeListingLifetime caps listing expiry when premium ends (zero uses DefaultListingLifetime)
+	freeListingLifetime time.Duration
+	// updateSubscription is subscription.Update, swappable in tests
+	updateSubscription func(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error)
+	// newCustomer and newCheckoutSession are customer.New and checkoutsession.New,
+	// swapped for fakes in sandbox mode
+	newCustomer        func(params *stripe.CustomerParams) (*stripe.Customer, error)
+	newCheckoutSession func(params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error)
 }
 
 // NewSubscriptionService creates a new subscription service
@@ -61,9 +85,59 @@ func NewSubscriptionService(
 		redis:           redis,
 		invalidator:     cache.NewInvalidator(redis),
 		config:          config,
+
+		updateSubscription: subscription.Update,
+		newCustomer:        customer.New,
+		newCheckoutSession: checkoutsession.New,
+	}
+}
+
+// SetSandboxMode replaces the Stripe API calls with fakes that never leave the process:
+// customers and subscriptions get generated IDs and checkout sessions point straight at
+// the success URL. Profiles and billing records are still written as usual.
+func (s *SubscriptionService) SetSandboxMode(enabled bool) {
+	if !enabled {
+		s.updateSubscription = subscription.Update
+		s.newCustomer = customer.New
+		s.newCheckoutSession = checkoutsession.New
+		return
+	}
+
+	s.updateSubscription = func(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+		sub := &stripe.Subscription{ID: id}
+		if params.CancelAtPeriodEnd != nil {
+			sub.CancelAtPeriodEnd = *params.CancelAtPeriodEnd
+		}
+		return sub, nil
+	}
+	s.newCustomer = func(params *stripe.CustomerParams) (*stripe.Customer, error) {
+		return &stripe.Customer{ID: "cus_sandbox_" + uuid.New().String()}, nil
+	}
+	s.newCheckoutSession = func(params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error) {
+		id := "cs_sandbox_" + uuid.New().String()
+		url := ""
+		if params.SuccessURL != nil {
+			url = *params.SuccessURL
+		}
+		return &stripe.CheckoutSession{ID: id, URL: url, Metadata: params.Metadata}, nil
 	}
 }
 
+// SetFreeListingLifetime sets the listing lifetime a seller falls back to when premium ends
+func (s *SubscriptionService) SetFreeListingLifetime(lifetime time.Duration) {
+	s.freeListingLifetime = lifetime
+}
+
+// SetProfileService sets the profile service used to resolve gift recipients
+func (s *SubscriptionService) SetProfileService(ps *ProfileService) {
+	s.profileService = ps
+}
+
+// SetNotificationService sets the notification service used to tell recipients about gifts
+func (s *SubscriptionService) SetNotificationService(ns *NotificationService) {
+	s.notificationService = ns
+}
+
 // GetSubscriptionInfo returns the user's subscription status
 func (s *SubscriptionService) GetSubscriptionInfo(ctx context.Context, userID string) (*dto.SubscriptionInfoResponse, error) {
 	profile, err := s.profileRepo.GetByID(ctx, userID)
@@ -99,39 +173,115 @@ func (s *SubscriptionService) CreateCheckoutSession(ctx context.Context, userID
 		return nil, err
 	}
 
-	// Create or reuse Stripe customer
-	var customerID string
+	customerID, err := s.ensureCustomer(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create checkout session
+	sessionParams := &stripe.CheckoutSessionParams{
+		Customer: stripe.String(customerID),
+		Mode:     stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Price:    stripe.String(effectivePriceID),
+				Quantity: stripe.Int64(1),
+			},
+		},
+		SuccessURL: stripe.String(s.config.SuccessURL),
+		CancelURL:  stripe.String(s.config.CancelURL),
+		SubscriptionData: &stripe.CheckoutSessionSubscriptionDataParams{
+			Metadata: map[string]string{
+				"user_id": userID,
+			},
+		},
+	}
+
+	sess, err := s.newCheckoutSession(sessionParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	return &dto.CheckoutResponse{
+		CheckoutURL: sess.URL,
+	}, nil
+}
+
+// ensureCustomer returns the user's Stripe customer, creating and storing one on first use
+func (s *SubscriptionService) ensureCustomer(ctx context.Context, profile *models.Profile) (string, error) {
 	if profile.StripeCustomerID != nil {
-		customerID = *profile.StripeCustomerID
-	} else {
-		// Get email from auth.users table
-		email, err := s.profileRepo.GetEmailByID(ctx, userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get user email: %w", err)
-		}
+		return *profile.StripeCustomerID, nil
+	}
+
+	// Get email from auth.users table
+	email, err := s.profileRepo.GetEmailByID(ctx, profile.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user email: %w", err)
+	}
 
-		params := &stripe.CustomerParams{
-			Params: stripe.Params{
-				Metadata: map[string]string{
-					"user_id": userID,
-				},
+	params := &stripe.CustomerParams{
+		Params: stripe.Params{
+			Metadata: map[string]string{
+				"user_id": profile.ID,
 			},
+		},
+	}
+	params.Email = stripe.String(email)
+	c, err := s.newCustomer(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to create stripe customer: %w", err)
+	}
+	customerID := c.ID
+	profile.StripeCustomerID = &customerID
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return "", err
+	}
+	_ = s.invalidator.InvalidateProfile(ctx, profile.ID)
+	_ = s.invalidator.InvalidateProfileDTO(ctx, profile.ID)
+
+	return customerID, nil
+}
+
+// CreateGiftCheckout creates a Stripe checkout session, paid by the gifter, that
+// gives the recipient one billing period of premium. A recipient who already has
+// premium is refused unless gifts are configured to extend it, in which case the
+// first charge is deferred until their current period ends.
+func (s *SubscriptionService) CreateGiftCheckout(ctx context.Context, gifterID string, recipientIdentifier string, priceID string) (*dto.CheckoutResponse, error) {
+	effectivePriceID := priceID
+	if effectivePriceID == "" {
+		effectivePriceID = s.config.PriceID
+	}
+	if !s.isAllowedPriceID(effectivePriceID) {
+		return nil, ErrInvalidPriceID
+	}
+
+	recipient, err := s.profileService.GetByIdentifier(ctx, recipientIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == gifterID {
+		return nil, ErrSelfAction
+	}
+
+	var trialEnd *int64
+	if recipient.IsPremium {
+		if !s.config.GiftExtendsPremium {
+			return nil, ErrAlreadyPremium
 		}
-		params.Email = stripe.String(email)
-		c, err := customer.New(params)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create stripe customer: %w", err)
-		}
-		customerID = c.ID
-		profile.StripeCustomerID = &customerID
-		if err := s.profileRepo.Update(ctx, profile); err != nil {
-			return nil, err
+		if end := recipient.SubscriptionCurrentPeriodEnd; end != nil && time.Until(*end) > giftTrialMinLead {
+			trialEnd = stripe.Int64(end.Unix())
 		}
-		_ = s.invalidator.InvalidateProfile(ctx, userID)
-		_ = s.invalidator.InvalidateProfileDTO(ctx, userID)
 	}
 
-	// Create checkout session
+	gifter, err := s.profileRepo.GetByID(ctx, gifterID)
+	if err != nil {
+		return nil, err
+	}
+	customerID, err := s.ensureCustomer(ctx, gifter)
+	if err != nil {
+		return nil, err
+	}
+
 	sessionParams := &stripe.CheckoutSessionParams{
 		Customer: stripe.String(customerID),
 		Mode:     stripe.String(string(stripe.CheckoutSessionModeSubscription)),
@@ -145,12 +295,17 @@ func (s *SubscriptionService) CreateCheckoutSession(ctx context.Context, userID
 		CancelURL:  stripe.String(s.config.CancelURL),
 		SubscriptionData: &stripe.CheckoutSessionSubscriptionDataParams{
 			Metadata: map[string]string{
-				"user_id": userID,
+				"user_id":   recipient.ID,
+				"gifter_id": gifterID,
+				"gift":      "true",
 			},
+			TrialEnd: trialEnd,
 		},
 	}
+	sessionParams.AddMetadata("gift_recipient_id", recipient.ID)
+	sessionParams.AddMetadata("gifter_id", gifterID)
 
-	sess, err := checkoutsession.New(sessionParams)
+	sess, err := s.newCheckoutSession(sessionParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create checkout session: %w", err)
 	}
@@ -186,7 +341,7 @@ func (s *SubscriptionService) CancelSubscription(ctx context.Context, userID str
 		return ErrNotFound
 	}
 
-	_, err = subscription.Update(*profile.StripeSubscriptionID, &stripe.SubscriptionParams{
+	_, err = s.updateSubscription(*profile.StripeSubscriptionID, &stripe.SubscriptionParams{
 		CancelAtPeriodEnd: stripe.Bool(true),
 	})
 	if err != nil {
@@ -210,6 +365,7 @@ var billingEventDisplayNames = map[string]string{
 	"customer.subscription.deleted":   "Subscription Cancelled",
 	"invoice.payment_succeeded":       "Payment Succeeded",
 	"invoice.payment_failed":          "Payment Failed",
+	billingEventGiftSent:              "Premium Gift Sent",
 }
 
 // billingEventStatuses maps Stripe event types to simple status labels
@@ -219,6 +375,7 @@ var billingEventStatuses = map[string]string{
 	"customer.subscription.deleted":   "cancelled",
 	"invoice.payment_succeeded":       "succeeded",
 	"invoice.payment_failed":          "failed",
+	billingEventGiftSent:              "completed",
 }
 
 func billingDisplayName(eventType string) string {
@@ -235,8 +392,9 @@ func billingStatus(eventType string) string {
 	return eventType
 }
 
-// GetBillingHistory returns the user's billing event history
-func (s *SubscriptionService) GetBillingHistory(ctx context.Context, userID string) (*dto.BillingHistoryResponse, error) {
+// GetBillingHistory returns the user's billing event history, with amounts
+// formatted for locale (English when empty or unknown)
+func (s *SubscriptionService) GetBillingHistory(ctx context.Context, userID string, locale string) (*dto.BillingHistoryResponse, error) {
 	events, err := s.billingRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -245,8 +403,10 @@ func (s *SubscriptionService) GetBillingHistory(ctx context.Context, userID stri
 	entries := make([]dto.BillingHistoryEntry, 0, len(events))
 	for _, e := range events {
 		amount := ""
+		currency := ""
 		if e.AmountCents != nil && e.Currency != nil {
-			amount = fmt.Sprintf("%.2f %s", float64(*e.AmountCents)/100.0, *e.Currency)
+			amount = formatCurrency(*e.AmountCents, *e.Currency, locale)
+			currency = strings.ToUpper(*e.Currency)
 		}
 		invoiceURL := ""
 		if e.InvoiceURL != nil {
@@ -257,6 +417,8 @@ func (s *SubscriptionService) GetBillingHistory(ctx context.Context, userID stri
 			Date:        e.CreatedAt.Format("2006-01-02"),
 			Description: billingDisplayName(e.EventType),
 			Amount:      amount,
+			AmountCents: e.AmountCents,
+			Currency:    currency,
 			Status:      billingStatus(e.EventType),
 			InvoiceURL:  invoiceURL,
 		})
@@ -304,6 +466,10 @@ func (s *SubscriptionService) handleCheckoutCompleted(ctx context.Context, event
 		return nil
 	}
 
+	if recipientID := sess.Metadata["gift_recipient_id"]; recipientID != "" {
+		return s.handleGiftCheckoutCompleted(ctx, event, &sess, recipientID)
+	}
+
 	userID := sess.Metadata["user_id"]
 	if userID == "" {
 		// Try to find the user by customer ID
@@ -333,6 +499,93 @@ func (s *SubscriptionService) handleCheckoutCompleted(ctx context.Context, event
 	return nil
 }
 
+// handleGiftCheckoutCompleted applies a paid gift to the recipient. The payer's
+// customer is left alone so their own premium is unaffected; they get a billing
+// event instead. A subscription the recipient already had is set to end with its
+// current period, since the gift takes over from there.
+func (s *SubscriptionService) handleGiftCheckoutCompleted(ctx context.Context, event stripe.Event, sess *stripe.CheckoutSession, recipientID string) error {
+	log := logger.FromContext(ctx)
+
+	exists, err := s.billingRepo.ExistsByStripeEventID(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	recipient, err := s.profileRepo.GetByID(ctx, recipientID)
+	if err != nil {
+		return err
+	}
+
+	previousSubID := recipient.StripeSubscriptionID
+	recipient.IsPremium = true
+	recipient.StripeSubscriptionID = &sess.Subscription.ID
+	recipient.SubscriptionStatus = "active"
+	recipient.CancelAtPeriodEnd = false
+	if err := s.profileRepo.Update(ctx, recipient); err != nil {
+		return err
+	}
+	_ = s.invalidator.InvalidateProfile(ctx, recipientID)
+	_ = s.invalidator.InvalidateProfileDTO(ctx, recipientID)
+
+	if previousSubID != nil && *previousSubID != sess.Subscription.ID {
+		if _, err := s.updateSubscription(*previousSubID, &stripe.SubscriptionParams{
+			CancelAtPeriodEnd: stripe.Bool(true),
+		}); err != nil {
+			log.Error("failed to end subscription replaced by gift",
+				"error", err.Error(),
+				"user_id", recipientID,
+				"subscription_id", *previousSubID,
+			)
+		}
+	}
+
+	gifterID := sess.Metadata["gifter_id"]
+	if gifterID == "" {
+		gifter, err := s.profileRepo.GetByStripeCustomerID(ctx, sess.Customer.ID)
+		if err != nil {
+			return fmt.Errorf("could not find gifter for customer %s: %w", sess.Customer.ID, err)
+		}
+		gifterID = gifter.ID
+	}
+
+	amountCents := int(sess.AmountTotal)
+	currency := string(sess.Currency)
+	billingEvent := &models.BillingEvent{
+		UserID:        gifterID,
+		StripeEventID: event.ID,
+		EventType:     billingEventGiftSent,
+		AmountCents:   &amountCents,
+		Currency:      &currency,
+	}
+	if err := s.billingRepo.Create(ctx, billingEvent); err != nil {
+		return err
+	}
+
+	if s.notificationService != nil {
+		gifterName := "Someone"
+		if gifter, err := s.profileRepo.GetByID(ctx, gifterID); err == nil {
+			gifterName = gifter.GetDisplayName()
+		}
+		if err := s.notificationService.NotifyPremiumGifted(ctx, recipientID, gifterID, gifterName, sess.ID); err != nil {
+			log.Error("failed to notify premium gift recipient",
+				"error", err.Error(),
+				"user_id", recipientID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// isGiftInvoice reports whether an invoice bills a gift subscription, which is
+// charged to the gifter's customer but grants premium to someone else
+func isGiftInvoice(inv *stripe.Invoice) bool {
+	return inv.SubscriptionDetails != nil && inv.SubscriptionDetails.Metadata["gift"] == "true"
+}
+
 func (s *SubscriptionService) handleSubscriptionUpdated(ctx context.Context, event stripe.Event) error {
 	var sub stripe.Subscription
 	if err := json.Unmarshal(event.Data.Raw, &sub); err != nil {
@@ -340,6 +593,10 @@ func (s *SubscriptionService) handleSubscriptionUpdated(ctx context.Context, eve
 	}
 
 	profile, err := s.profileRepo.GetByStripeSubscriptionID(ctx, sub.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A subscription replaced by a gift no longer belongs to any profile
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("could not find user for subscription %s: %w", sub.ID, err)
 	}
@@ -371,6 +628,10 @@ func (s *SubscriptionService) handleSubscriptionDeleted(ctx context.Context, eve
 	}
 
 	profile, err := s.profileRepo.GetByStripeSubscriptionID(ctx, sub.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A subscription replaced by a gift no longer belongs to any profile
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("could not find user for subscription %s: %w", sub.ID, err)
 	}
@@ -413,6 +674,23 @@ func (s *SubscriptionService) handleSubscriptionDeleted(ctx context.Context, eve
 		)
 	}
 
+	// Best-effort cleanup: listings posted with the premium lifetime fall back to the free one
+	lifetime := s.freeListingLifetime
+	if lifetime <= 0 {
+		lifetime = DefaultListingLifetime
+	}
+	if cappedCount, err := s.listingRepo.CapExpiry(ctx, profile.ID, lifetime, time.Now()); err != nil {
+		log.Error("failed to cap listing expiry on subscription cancellation",
+			"error", err.Error(),
+			"user_id", profile.ID,
+		)
+	} else if cappedCount > 0 {
+		log.Info("shortened listing expiry on subscription cancellation",
+			"user_id", profile.ID,
+			"capped_count", cappedCount,
+		)
+	}
+
 	return nil
 }
 
@@ -440,6 +718,18 @@ func (s *SubscriptionService) handleInvoicePaymentSucceeded(ctx context.Context,
 		return fmt.Errorf("could not find user for customer %s: %w", inv.Customer.ID, err)
 	}
 
+	// A gift invoice pays for someone else's premium. Once a period has actually
+	// been charged the gift subscription is set to end with it, so the gifter pays once.
+	// This runs before the billing event is recorded: if Stripe can't be updated the
+	// webhook fails and Stripe's retry tries again instead of being deduplicated.
+	if isGiftInvoice(&inv) && inv.AmountPaid > 0 && inv.Subscription != nil {
+		if _, err := s.updateSubscription(inv.Subscription.ID, &stripe.SubscriptionParams{
+			CancelAtPeriodEnd: stripe.Bool(true),
+		}); err != nil {
+			return fmt.Errorf("failed to end gift subscription %s after its paid period: %w", inv.Subscription.ID, err)
+		}
+	}
+
 	amountCents := int(inv.AmountPaid)
 	currency := string(inv.Currency)
 	invoiceURL := inv.HostedInvoiceURL
@@ -459,6 +749,10 @@ func (s *SubscriptionService) handleInvoicePaymentSucceeded(ctx context.Context,
 		return err
 	}
 
+	if isGiftInvoice(&inv) {
+		return nil
+	}
+
 	// Ensure premium is active
 	if !profile.IsPremium {
 		profile.IsPremium = true
@@ -511,6 +805,10 @@ func (s *SubscriptionService) handleInvoicePaymentFailed(ctx context.Context, ev
 		return err
 	}
 
+	if isGiftInvoice(&inv) {
+		return nil
+	}
+
 	profile.SubscriptionStatus = "past_due"
 	if err := s.profileRepo.Update(ctx, profile); err != nil {
 		return err
@@ -588,7 +886,7 @@ func (s *SubscriptionService) GetPriceHistory(ctx context.Context, userID string
 		days = 30
 	}
 
-	records, err := s.transactionRepo.GetPriceHistory(ctx, itemName, days)
+	records, err := s.transactionRepo.GetPriceHistory(ctx, itemName, "", days)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +14,8 @@ import (
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v81"
 )
 
 // ---------------------------------------------------------------------------
@@ -360,7 +366,7 @@ func TestGetPriceHistory_PremiumUser_Success(t *testing.T) {
 	}
 
 	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
-	txnRepo.On("GetPriceHistory", ctx, "Shako", 30).Return(records, nil)
+	txnRepo.On("GetPriceHistory", ctx, "Shako", "", 30).Return(records, nil)
 
 	result, err := svc.GetPriceHistory(ctx, testUserID, "Shako", 30)
 	assert.NoError(t, err)
@@ -426,7 +432,7 @@ func TestGetPriceHistory_DefaultsDays(t *testing.T) {
 
 	// When days=0, the service should default to 30
 	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil).Once()
-	txnRepo.On("GetPriceHistory", ctx, "Shako", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
+	txnRepo.On("GetPriceHistory", ctx, "Shako", "", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
 
 	result, err := svc.GetPriceHistory(ctx, testUserID, "Shako", 0)
 	assert.NoError(t, err)
@@ -435,7 +441,7 @@ func TestGetPriceHistory_DefaultsDays(t *testing.T) {
 
 	// When days>90, the service should also default to 30
 	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil).Once()
-	txnRepo.On("GetPriceHistory", ctx, "Shako", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
+	txnRepo.On("GetPriceHistory", ctx, "Shako", "", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
 
 	result, err = svc.GetPriceHistory(ctx, testUserID, "Shako", 100)
 	assert.NoError(t, err)
@@ -443,7 +449,7 @@ func TestGetPriceHistory_DefaultsDays(t *testing.T) {
 
 	// When days is negative, should also default to 30
 	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil).Once()
-	txnRepo.On("GetPriceHistory", ctx, "Shako", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
+	txnRepo.On("GetPriceHistory", ctx, "Shako", "", 30).Return([]repository.PriceHistoryRecord{}, nil).Once()
 
 	result, err = svc.GetPriceHistory(ctx, testUserID, "Shako", -5)
 	assert.NoError(t, err)
@@ -500,7 +506,7 @@ func TestGetBillingHistory_Success(t *testing.T) {
 
 	billingRepo.On("GetByUserID", ctx, testUserID).Return(events, nil)
 
-	result, err := svc.GetBillingHistory(ctx, testUserID)
+	result, err := svc.GetBillingHistory(ctx, testUserID, "")
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Len(t, result.Data, 2)
@@ -510,7 +516,9 @@ func TestGetBillingHistory_Success(t *testing.T) {
 	assert.Equal(t, "be-001", entry0.ID)
 	assert.Equal(t, "2024-06-15", entry0.Date)
 	assert.Equal(t, "Payment Succeeded", entry0.Description)
-	assert.Equal(t, "9.99 usd", entry0.Amount)
+	assert.Equal(t, "$9.99", entry0.Amount)
+	assert.Equal(t, &amountCents, entry0.AmountCents)
+	assert.Equal(t, "USD", entry0.Currency)
 	assert.Equal(t, "succeeded", entry0.Status)
 	assert.Equal(t, "https://stripe.com/invoice/123", entry0.InvoiceURL)
 
@@ -520,12 +528,36 @@ func TestGetBillingHistory_Success(t *testing.T) {
 	assert.Equal(t, "2024-06-16", entry1.Date)
 	assert.Equal(t, "Subscription Cancelled", entry1.Description)
 	assert.Equal(t, "", entry1.Amount)
+	assert.Nil(t, entry1.AmountCents)
+	assert.Equal(t, "", entry1.Currency)
 	assert.Equal(t, "cancelled", entry1.Status)
 	assert.Equal(t, "", entry1.InvoiceURL)
 
 	billingRepo.AssertExpectations(t)
 }
 
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount   int
+		currency string
+		locale   string
+		expected string
+	}{
+		{999, "usd", "", "$9.99"},
+		{123456, "usd", "en-US", "$1,234.56"},
+		{4990, "brl", "pt-BR", "R$ 49,90"},
+		{999, "eur", "de", "9,99 €"},
+		{123456, "eur", "fr_FR", "1 234,56 €"},
+		{1000, "jpy", "", "¥1,000"},
+		{1500, "chf", "", "CHF 15.00"},
+		{-505, "usd", "xx", "-$5.05"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, formatCurrency(tt.amount, tt.currency, tt.locale), tt.expected)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // billingDisplayName
 // ---------------------------------------------------------------------------
@@ -577,3 +609,271 @@ func TestBillingStatus(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Gifting premium
+// ---------------------------------------------------------------------------
+
+func newGiftTestService(
+	config StripeConfig,
+) (*SubscriptionService, *mocks.MockProfileRepository, *mocks.MockBillingEventRepository, *mocks.MockNotificationRepository) {
+	profileRepo := new(mocks.MockProfileRepository)
+	billingRepo := new(mocks.MockBillingEventRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+	svc := newTestSubscriptionService(
+		profileRepo,
+		billingRepo,
+		new(mocks.MockTransactionRepository),
+		new(mocks.MockWishlistRepository),
+		new(mocks.MockListingRepository),
+		config,
+	)
+	svc.SetProfileService(NewProfileService(profileRepo, newTestRedis(), nil))
+	svc.SetNotificationService(NewNotificationService(notifRepo, newTestRedis()))
+	return svc, profileRepo, billingRepo, notifRepo
+}
+
+func stripeEvent(t *testing.T, id string, eventType string, obj any) stripe.Event {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return stripe.Event{ID: id, Type: stripe.EventType(eventType), Data: &stripe.EventData{Raw: raw}}
+}
+
+func TestCreateGiftCheckout_InvalidPriceID(t *testing.T) {
+	svc, profileRepo, _, _ := newGiftTestService(defaultStripeConfig())
+
+	_, err := svc.CreateGiftCheckout(context.Background(), testBuyerID, "recipient", "price_unknown")
+	assert.ErrorIs(t, err, ErrInvalidPriceID)
+	profileRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+}
+
+func TestCreateGiftCheckout_RecipientNotFound(t *testing.T) {
+	svc, profileRepo, _, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+
+	profileRepo.On("GetByUsername", ctx, "nobody").Return(nil, sql.ErrNoRows)
+
+	_, err := svc.CreateGiftCheckout(ctx, testBuyerID, "nobody", "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCreateGiftCheckout_ToSelf(t *testing.T) {
+	svc, profileRepo, _, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+	gifter := testProfile(testBuyerID)
+
+	profileRepo.On("GetByUsername", ctx, gifter.Username).Return(gifter, nil)
+
+	_, err := svc.CreateGiftCheckout(ctx, testBuyerID, gifter.Username, "")
+	assert.ErrorIs(t, err, ErrSelfAction)
+}
+
+func TestCreateGiftCheckout_RecipientAlreadyPremium_Refused(t *testing.T) {
+	svc, profileRepo, _, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+	recipient := testProfile(testUserID, withPremium)
+
+	profileRepo.On("GetByUsername", ctx, recipient.Username).Return(recipient, nil)
+
+	_, err := svc.CreateGiftCheckout(ctx, testBuyerID, recipient.Username, "")
+	assert.ErrorIs(t, err, ErrAlreadyPremium)
+	profileRepo.AssertNotCalled(t, "GetByID", mock.Anything, testBuyerID)
+}
+
+func TestHandleCheckoutCompleted_Gift_AppliesPremiumToRecipient(t *testing.T) {
+	svc, profileRepo, billingRepo, notifRepo := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+
+	var endedSubs []string
+	svc.updateSubscription = func(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+		endedSubs = append(endedSubs, id)
+		assert.True(t, *params.CancelAtPeriodEnd)
+		return &stripe.Subscription{ID: id}, nil
+	}
+
+	recipient := testProfile(testUserID, withPremium)
+	recipient.StripeSubscriptionID = strPtr("sub_own")
+	gifter := testProfile(testBuyerID)
+	gifter.StripeCustomerID = strPtr("cus_gifter")
+
+	event := stripeEvent(t, "evt_gift", "checkout.session.completed", stripe.CheckoutSession{
+		Customer:     &stripe.Customer{ID: "cus_gifter"},
+		Subscription: &stripe.Subscription{ID: "sub_gift"},
+		AmountTotal:  499,
+		Currency:     stripe.CurrencyUSD,
+		Metadata: map[string]string{
+			"gift_recipient_id": testUserID,
+			"gifter_id":         testBuyerID,
+		},
+	})
+
+	billingRepo.On("ExistsByStripeEventID", ctx, "evt_gift").Return(false, nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(recipient, nil)
+	profileRepo.On("GetByID", ctx, testBuyerID).Return(gifter, nil)
+	profileRepo.On("Update", ctx, recipient).Return(nil)
+	billingRepo.On("Create", ctx, mock.MatchedBy(func(e *models.BillingEvent) bool {
+		return e.UserID == testBuyerID && e.EventType == billingEventGiftSent && *e.AmountCents == 499
+	})).Return(nil)
+	notifRepo.On("Create", ctx, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == testUserID && n.Type == models.NotificationTypePremiumGifted
+	})).Return(nil)
+
+	err := svc.handleCheckoutCompleted(ctx, event)
+	require.NoError(t, err)
+
+	assert.True(t, recipient.IsPremium)
+	assert.Equal(t, "sub_gift", *recipient.StripeSubscriptionID)
+	assert.Nil(t, recipient.StripeCustomerID, "the gifter's customer must not be attached to the recipient")
+	assert.Equal(t, []string{"sub_own"}, endedSubs)
+	assert.False(t, gifter.IsPremium)
+	profileRepo.AssertNotCalled(t, "Update", ctx, gifter)
+	billingRepo.AssertExpectations(t)
+	notifRepo.AssertExpectations(t)
+}
+
+func TestHandleInvoicePaymentSucceeded_GiftInvoice_EndsGiftAndSkipsPayer(t *testing.T) {
+	svc, profileRepo, billingRepo, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+
+	var endedSubs []string
+	svc.updateSubscription = func(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+		endedSubs = append(endedSubs, id)
+		return &stripe.Subscription{ID: id}, nil
+	}
+
+	gifter := testProfile(testBuyerID)
+	event := stripeEvent(t, "evt_inv", "invoice.payment_succeeded", stripe.Invoice{
+		Customer:     &stripe.Customer{ID: "cus_gifter"},
+		Subscription: &stripe.Subscription{ID: "sub_gift"},
+		AmountPaid:   499,
+		Currency:     stripe.CurrencyUSD,
+		SubscriptionDetails: &stripe.InvoiceSubscriptionDetails{
+			Metadata: map[string]string{"gift": "true"},
+		},
+	})
+
+	billingRepo.On("ExistsByStripeEventID", ctx, "evt_inv").Return(false, nil)
+	profileRepo.On("GetByStripeCustomerID", ctx, "cus_gifter").Return(gifter, nil)
+	billingRepo.On("Create", ctx, mock.MatchedBy(func(e *models.BillingEvent) bool {
+		return e.UserID == testBuyerID
+	})).Return(nil)
+
+	err := svc.handleInvoicePaymentSucceeded(ctx, event)
+	require.NoError(t, err)
+
+	assert.False(t, gifter.IsPremium)
+	assert.Equal(t, []string{"sub_gift"}, endedSubs)
+	profileRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestHandleInvoicePaymentSucceeded_GiftInvoice_CancelFailureRetries(t *testing.T) {
+	svc, profileRepo, billingRepo, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+
+	attempts := 0
+	svc.updateSubscription = func(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("stripe unavailable")
+		}
+		return &stripe.Subscription{ID: id}, nil
+	}
+
+	event := stripeEvent(t, "evt_inv", "invoice.payment_succeeded", stripe.Invoice{
+		Customer:     &stripe.Customer{ID: "cus_gifter"},
+		Subscription: &stripe.Subscription{ID: "sub_gift"},
+		AmountPaid:   499,
+		Currency:     stripe.CurrencyUSD,
+		SubscriptionDetails: &stripe.InvoiceSubscriptionDetails{
+			Metadata: map[string]string{"gift": "true"},
+		},
+	})
+
+	billingRepo.On("ExistsByStripeEventID", ctx, "evt_inv").Return(false, nil)
+	profileRepo.On("GetByStripeCustomerID", ctx, "cus_gifter").Return(testProfile(testBuyerID), nil)
+	billingRepo.On("Create", ctx, mock.AnythingOfType("*models.BillingEvent")).Return(nil)
+
+	// The failed cancel fails the webhook without recording it, so Stripe's retry ends the gift
+	require.Error(t, svc.handleInvoicePaymentSucceeded(ctx, event))
+	billingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	require.NoError(t, svc.handleInvoicePaymentSucceeded(ctx, event))
+	assert.Equal(t, 2, attempts)
+	billingRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestHandleSubscriptionDeleted_SupersededSubscriptionIgnored(t *testing.T) {
+	svc, profileRepo, _, _ := newGiftTestService(defaultStripeConfig())
+	ctx := context.Background()
+
+	event := stripeEvent(t, "evt_del", "customer.subscription.deleted", stripe.Subscription{ID: "sub_own"})
+	profileRepo.On("GetByStripeSubscriptionID", ctx, "sub_own").Return(nil, sql.ErrNoRows)
+
+	err := svc.handleSubscriptionDeleted(ctx, event)
+	assert.NoError(t, err)
+	profileRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestHandleSubscriptionDeleted_CapsListingExpiry(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	wishlistRepo := new(mocks.MockWishlistRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc := newTestSubscriptionService(profileRepo, new(mocks.MockBillingEventRepository), new(mocks.MockTransactionRepository), wishlistRepo, listingRepo, defaultStripeConfig())
+	svc.SetFreeListingLifetime(14 * 24 * time.Hour)
+	ctx := context.Background()
+
+	profile := testProfile(testSellerID, withPremium)
+	event := stripeEvent(t, "evt_del", "customer.subscription.deleted", stripe.Subscription{ID: "sub_own"})
+	profileRepo.On("GetByStripeSubscriptionID", ctx, "sub_own").Return(profile, nil)
+	profileRepo.On("Update", ctx, profile).Return(nil)
+	wishlistRepo.On("DeleteAllByUserID", ctx, testSellerID).Return(0, nil)
+	listingRepo.On("CancelOldestActiveListings", ctx, testSellerID, 3).Return(0, nil)
+	listingRepo.On("CapExpiry", ctx, testSellerID, 14*24*time.Hour, mock.AnythingOfType("time.Time")).Return(2, nil)
+
+	err := svc.handleSubscriptionDeleted(ctx, event)
+
+	require.NoError(t, err)
+	assert.False(t, profile.IsPremium)
+	listingRepo.AssertExpectations(t)
+}
+
+// ---------------------------------------------------------------------------
+// Sandbox mode
+// ---------------------------------------------------------------------------
+
+func TestSandbox_CreateCheckoutSession_FakesStripe(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := newTestSubscriptionService(profileRepo, new(mocks.MockBillingEventRepository), new(mocks.MockTransactionRepository), new(mocks.MockWishlistRepository), new(mocks.MockListingRepository), defaultStripeConfig())
+	svc.SetSandboxMode(true)
+	ctx := context.Background()
+
+	profile := testProfile(testUserID)
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+	profileRepo.On("GetEmailByID", ctx, testUserID).Return("user@example.com", nil)
+	profileRepo.On("Update", ctx, profile).Return(nil)
+
+	resp, err := svc.CreateCheckoutSession(ctx, testUserID, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/success", resp.CheckoutURL)
+	require.NotNil(t, profile.StripeCustomerID)
+	assert.True(t, strings.HasPrefix(*profile.StripeCustomerID, "cus_sandbox_"))
+}
+
+func TestSandbox_CancelSubscription_StillUpdatesProfile(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	svc := newTestSubscriptionService(profileRepo, new(mocks.MockBillingEventRepository), new(mocks.MockTransactionRepository), new(mocks.MockWishlistRepository), new(mocks.MockListingRepository), defaultStripeConfig())
+	svc.SetSandboxMode(true)
+	ctx := context.Background()
+
+	subID := "sub_sandbox"
+	profile := testProfile(testUserID, withPremium, func(p *models.Profile) { p.StripeSubscriptionID = &subID })
+	profileRepo.On("GetByID", ctx, testUserID).Return(profile, nil)
+	profileRepo.On("Update", ctx, profile).Return(nil)
+
+	err := svc.CancelSubscription(ctx, testUserID)
+
+	require.NoError(t, err)
+	assert.True(t, profile.CancelAtPeriodEnd)
+}
@@ -59,6 +59,11 @@ func intPtr(i int) *int {
 	return &i
 }
 
+// float64Ptr returns a pointer to a float64.
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
 // boolPtr returns a pointer to a bool.
 func boolPtr(b bool) *bool {
 	return &b
@@ -135,6 +140,14 @@ func withListingStatus(status string) func(*models.Listing) {
 	return func(l *models.Listing) { l.Status = status }
 }
 
+func withReservation(userID string, until time.Time) func(*models.Listing) {
+	return func(l *models.Listing) {
+		l.Status = "reserved"
+		l.ReservedFor = &userID
+		l.ReservedUntil = &until
+	}
+}
+
 // testOffer creates a test offer with sensible defaults (item type).
 func testOffer(id string, requesterID string, listingID *string, opts ...func(*models.Offer)) *models.Offer {
 	o := &models.Offer{
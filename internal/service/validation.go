@@ -0,0 +1,286 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
+)
+
+// maxRequestJSONBytes caps free-form JSON fields (stats, askingFor, ...) on create requests
+const maxRequestJSONBytes = 16 * 1024
+
+// Default caps for free-text fields, in characters
+const (
+	DefaultMaxNotesLength       = 500
+	DefaultMaxDescriptionLength = 2000
+	DefaultMaxCommentLength     = 500
+)
+
+// TextLimits caps free-text fields in characters, counted after sanitizing.
+// Values below 1 keep the defaults.
+type TextLimits struct {
+	Notes       int // listing and service notes
+	Description int // service descriptions
+	Comment     int // rating comments
+}
+
+// withDefaults fills unset limits with the defaults
+func (l TextLimits) withDefaults() TextLimits {
+	if l.Notes < 1 {
+		l.Notes = DefaultMaxNotesLength
+	}
+	if l.Description < 1 {
+		l.Description = DefaultMaxDescriptionLength
+	}
+	if l.Comment < 1 {
+		l.Comment = DefaultMaxCommentLength
+	}
+	return l
+}
+
+var (
+	scriptBlockPattern = regexp.MustCompile(`(?is)<script\b[^>]*>.*?</script\s*>`)
+	styleBlockPattern  = regexp.MustCompile(`(?is)<style\b[^>]*>.*?</style\s*>`)
+	// htmlTagPattern matches comments and tags, but not a bare "<" as in "<3" or "a < b"
+	htmlTagPattern = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z!][^>]*>`)
+)
+
+// sanitizePlainText turns client text into plain text: script and style blocks are
+// dropped with their content, other HTML tags and control characters are removed,
+// and line breaks are normalized to "\n" and kept. Stripping repeats until nothing
+// changes, so tags hidden inside other tags ("<<b>script>") don't survive.
+func sanitizePlainText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for {
+		stripped := scriptBlockPattern.ReplaceAllString(text, "")
+		stripped = styleBlockPattern.ReplaceAllString(stripped, "")
+		stripped = htmlTagPattern.ReplaceAllString(stripped, "")
+		if stripped == text {
+			break
+		}
+		text = stripped
+	}
+	text = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// validateText sanitizes text, recording a problem when the result still contains "<"
+// or is longer than max characters
+func validateText(errs ValidationErrors, field, text string, max int) string {
+	clean := sanitizePlainText(text)
+	if strings.Contains(clean, "<") {
+		errs.Add(field, `must not contain "<"`)
+	} else if utf8.RuneCountInString(clean) > max {
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+	return clean
+}
+
+// cleanText sanitizes a single field, returning ValidationErrors when it's too long
+func cleanText(field, text string, max int) (string, error) {
+	errs := ValidationErrors{}
+	clean := validateText(errs, field, text, max)
+	return clean, errs.Err()
+}
+
+// validPlatforms are the platforms listings, services and wishlist items can target
+var validPlatforms = map[string]bool{"pc": true, "xbox": true, "playstation": true, "switch": true}
+
+// ValidationErrors collects every field problem in a request, keyed by the
+// request's JSON field name, so clients can fix them all in one round trip
+type ValidationErrors map[string]string
+
+// Error implements error with the field problems in a stable order
+func (e ValidationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+e[field])
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Add records a problem for field, keeping the first message reported for it
+func (e ValidationErrors) Add(field, message string) {
+	if _, ok := e[field]; !ok {
+		e[field] = message
+	}
+}
+
+// Merge copies problems from other that aren't already recorded
+func (e ValidationErrors) Merge(other ValidationErrors) {
+	for field, message := range other {
+		e.Add(field, message)
+	}
+}
+
+// Err returns e as an error, or nil when no problems were recorded
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// gameValidator checks values against a game's catalog. Without a registry
+// (or for unregistered games) only the shape checks apply.
+type gameValidator struct {
+	handler games.GameHandler
+}
+
+func newGameValidator(registry *games.Registry, game string, errs ValidationErrors) gameValidator {
+	if registry == nil || game == "" {
+		return gameValidator{}
+	}
+	handler, err := registry.Get(game)
+	if err != nil {
+		errs.Add("game", "unknown game")
+		return gameValidator{}
+	}
+	return gameValidator{handler: handler}
+}
+
+// validCategory accepts a category code, its display name or one of its
+// stored subcategory values, case-insensitively
+func (v gameValidator) validCategory(category string) bool {
+	if v.handler == nil {
+		return true
+	}
+	category = strings.ToLower(strings.TrimSpace(category))
+	for _, c := range v.handler.GetCategories() {
+		if category == c.Code || category == strings.ToLower(c.Name) {
+			return true
+		}
+		for _, sub := range d2.GetSubcategories(c.Code) {
+			if category == sub {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v gameValidator) validRarity(rarity string) bool {
+	if v.handler == nil {
+		return true
+	}
+	for _, r := range v.handler.GetRarities() {
+		if strings.EqualFold(rarity, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v gameValidator) validServiceType(serviceType string) bool {
+	if v.handler == nil {
+		return true
+	}
+	for _, st := range v.handler.GetServiceTypes() {
+		if serviceType == st.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlatforms records a problem for an empty (when required) or unknown platform list
+func validatePlatforms(errs ValidationErrors, platforms []string, required bool) {
+	if len(platforms) == 0 {
+		if required {
+			errs.Add("platforms", "at least one platform is required")
+		}
+		return
+	}
+	for _, p := range platforms {
+		if !validPlatforms[p] {
+			errs.Add("platforms", fmt.Sprintf("unknown platform %q", p))
+			return
+		}
+	}
+}
+
+// validateRequired records a problem when value is blank
+func validateRequired(errs ValidationErrors, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		errs.Add(field, "is required")
+	}
+}
+
+// validateJSONField records a problem for oversize or malformed JSON
+func validateJSONField(errs ValidationErrors, field string, raw json.RawMessage) {
+	if len(raw) == 0 {
+		return
+	}
+	if len(raw) > maxRequestJSONBytes {
+		errs.Add(field, fmt.Sprintf("must be at most %d bytes", maxRequestJSONBytes))
+		return
+	}
+	if !json.Valid(raw) {
+		errs.Add(field, "must be valid JSON")
+	}
+}
+
+// validateItemList records a problem unless raw is a JSON list of named items
+// with non-negative quantities, the shape offered items are stored in
+func validateItemList(errs ValidationErrors, field string, raw json.RawMessage) {
+	if len(raw) == 0 {
+		return
+	}
+	validateJSONField(errs, field, raw)
+	if _, bad := errs[field]; bad {
+		return
+	}
+	var items []offeredItemRaw
+	if err := json.Unmarshal(raw, &items); err != nil {
+		errs.Add(field, "must be a list of items")
+		return
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.Name) == "" {
+			errs.Add(field, "every item needs a name")
+			return
+		}
+		if item.Quantity < 0 {
+			errs.Add(field, "quantities can't be negative")
+			return
+		}
+	}
+}
+
+// hasItems reports whether raw is a non-empty item list, so "[]" and "null" count as none
+func hasItems(raw json.RawMessage) bool {
+	var items []json.RawMessage
+	return json.Unmarshal(raw, &items) == nil && len(items) > 0
+}
+
+// validateRegion normalizes region, recording a problem when it's missing or unknown
+func validateRegion(errs ValidationErrors, registry *games.Registry, game, region string) string {
+	if strings.TrimSpace(region) == "" {
+		errs.Add("region", "is required")
+		return ""
+	}
+	normalized, err := normalizeRegion(registry, game, region)
+	if err != nil {
+		errs.Add("region", "unknown region")
+		return ""
+	}
+	return normalized
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func TestValidationErrors_KeepsFirstMessage(t *testing.T) {
+	errs := ValidationErrors{}
+	errs.Add("name", "is required")
+	errs.Add("name", "must be at most 100")
+	errs.Merge(ValidationErrors{"name": "other", "region": "unknown region"})
+
+	assert.Equal(t, "is required", errs["name"])
+	assert.Equal(t, "unknown region", errs["region"])
+	assert.Equal(t, "validation failed: name: is required; region: unknown region", errs.Error())
+}
+
+func TestValidationErrors_ErrNilWhenEmpty(t *testing.T) {
+	assert.NoError(t, ValidationErrors{}.Err())
+}
+
+func TestListingValidateCreate_AggregatesAllProblems(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	req := &dto.CreateListingRequest{
+		ItemType:  "unique",
+		Rarity:    "legendary",
+		Category:  "spaceship",
+		Game:      "diablo2",
+		Platforms: []string{"pc", "dreamcast"},
+		Region:    "mars",
+		Stats:     json.RawMessage(`[` + strings.Repeat(`{"code":"ac"},`, maxRequestJSONBytes/13) + `{}]`),
+	}
+
+	errs := svc.ValidateCreate(req)
+
+	assert.Equal(t, "is required", errs["name"])
+	assert.Equal(t, "unknown rarity", errs["rarity"])
+	assert.Equal(t, "unknown category", errs["category"])
+	assert.Equal(t, `unknown platform "dreamcast"`, errs["platforms"])
+	assert.Equal(t, "unknown region", errs["region"])
+	assert.Contains(t, errs["stats"], "must be at most")
+}
+
+func TestListingValidateCreate_AcceptsCategoryNamesAndAliases(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	for _, category := range []string{"helm", "Body Armor", "quest"} {
+		req := &dto.CreateListingRequest{
+			Name:      "Item",
+			ItemType:  "unique",
+			Rarity:    "superior",
+			Category:  category,
+			Game:      "diablo2",
+			Platforms: []string{"pc"},
+			Region:    "eu",
+		}
+
+		assert.Empty(t, svc.ValidateCreate(req), category)
+	}
+}
+
+func TestListingCreate_ReturnsValidationErrors(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	profileRepo.On("GetByID", mock.Anything, testSellerID).Return(testProfile(testSellerID, withPremium), nil)
+
+	_, err := svc.Create(context.Background(), testSellerID, &dto.CreateListingRequest{Name: "Shako", Game: "diablo2"})
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "itemType")
+	assert.Contains(t, errs, "platforms")
+	assert.Contains(t, errs, "region")
+	listingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestServiceCreate_ReturnsValidationErrors(t *testing.T) {
+	profileRepo := new(mocks.MockProfileRepository)
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(profileRepo, serviceRepo, newTestRedis())
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	req := &dto.CreateServiceRequest{
+		ServiceType: "boosting",
+		Name:        "Rush",
+		Game:        "diablo2",
+		Region:      "mars",
+		AskingFor:   json.RawMessage(`{not json`),
+	}
+
+	_, err := svc.Create(context.Background(), testProviderID, req)
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "unknown service type", errs["serviceType"])
+	assert.Equal(t, "at least one platform is required", errs["platforms"])
+	assert.Equal(t, "unknown region", errs["region"])
+	assert.Equal(t, "must be valid JSON", errs["askingFor"])
+	serviceRepo.AssertNotCalled(t, "ExistsByProviderAndType", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWishlistCreate_ReturnsValidationErrors(t *testing.T) {
+	svc, wishlistRepo, profileRepo, _ := newWishlistTestService()
+	svc.SetGameRegistry(newTestGameRegistry())
+
+	req := &dto.CreateWishlistItemRequest{
+		Game:      "diablo2",
+		Rarity:    strPtr("legendary"),
+		Platforms: []string{"dreamcast"},
+		StatCriteria: []dto.StatCriterionDTO{
+			{Code: "ed%", MinValue: intPtr(200), MaxValue: intPtr(100)},
+			{Name: "Enhanced Armor"},
+		},
+	}
+
+	_, err := svc.Create(context.Background(), testUserID, req)
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "is required", errs["name"])
+	assert.Equal(t, "unknown rarity", errs["rarity"])
+	assert.Contains(t, errs, "platforms")
+	assert.Contains(t, errs, "statCriteria[0].maxValue")
+	assert.Contains(t, errs, "statCriteria[1].code")
+	profileRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	wishlistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSanitizePlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text unchanged", "Trading for Ber\nor Jah", "Trading for Ber\nor Jah"},
+		{"crlf normalized", "line one\r\nline two", "line one\nline two"},
+		{"tags stripped", "<b>Perfect</b> <a href=\"x\">roll</a>", "Perfect roll"},
+		{"script dropped with content", "hi<script>alert(1)</script> there", "hi there"},
+		{"style dropped with content", "<STYLE>body{}</STYLE>ok", "ok"},
+		{"comments dropped", "a<!-- hidden -->b", "ab"},
+		{"nested tags stripped until stable", "<<b>script>alert(1)<</b>/script>ok", "ok"},
+		{"tag hidden in a tag", "x<<b>script>", "x"},
+		{"bare angle brackets kept", "price < 1 ist <3", "price < 1 ist <3"},
+		{"control chars removed", "tab\tkept\x00\x07", "tab\tkept"},
+		{"trimmed", "  \n note \n ", "note"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizePlainText(tt.in))
+		})
+	}
+}
+
+func TestListingValidateCreate_NotesTooLong(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+	svc.SetTextLimits(TextLimits{Notes: 10})
+
+	req := &dto.CreateListingRequest{Name: "Shako", ItemType: "unique", Rarity: "unique", Game: "diablo2", Platforms: []string{"pc"}, Region: "americas"}
+
+	req.Notes = "<b>short</b>"
+	assert.NotContains(t, svc.ValidateCreate(req), "notes")
+
+	req.Notes = strings.Repeat("é", 11)
+	assert.Equal(t, "must be at most 10 characters", svc.ValidateCreate(req)["notes"])
+
+	req.Notes = "<3 <<b>b"
+	assert.Equal(t, `must not contain "<"`, svc.ValidateCreate(req)["notes"])
+}
+
+func TestListingUpdate_SanitizesNotes(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+	listingRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Listing")).Return(nil)
+
+	notes := "<i>Eth</i> base\n<script>steal()</script>no trades after 9pm"
+	result, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Notes: &notes})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Eth base\nno trades after 9pm", *result.Notes)
+}
+
+func TestListingUpdate_NotesTooLong(t *testing.T) {
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(new(mocks.MockProfileRepository), listingRepo, newTestRedis())
+
+	listingRepo.On("GetByID", mock.Anything, testListingID).Return(testListing(testListingID, testSellerID), nil)
+
+	notes := strings.Repeat("a", DefaultMaxNotesLength+1)
+	_, err := svc.Update(context.Background(), testListingID, testSellerID, &dto.UpdateListingRequest{Notes: &notes})
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "notes")
+	listingRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestServiceUpdate_DescriptionTooLong(t *testing.T) {
+	serviceRepo := new(mocks.MockServiceRepository)
+	svc, _ := setupServiceService(new(mocks.MockProfileRepository), serviceRepo, newTestRedis())
+	svc.SetTextLimits(TextLimits{Description: 20})
+
+	serviceRepo.On("GetByID", mock.Anything, testServiceID).Return(testServiceModel(testServiceID, testProviderID), nil)
+
+	description := strings.Repeat("x", 21)
+	_, err := svc.Update(context.Background(), testServiceID, testProviderID, &dto.UpdateServiceRequest{Description: &description})
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "must be at most 20 characters", errs["description"])
+	serviceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRatingCreate_SanitizesComment(t *testing.T) {
+	svc, ratingRepo, txnRepo, notifRepo, _ := newTestRatingService()
+
+	txnRepo.On("GetByID", mock.Anything, testTransactionID).Return(testTransaction(testTransactionID, testSellerID, testBuyerID), nil)
+	ratingRepo.On("Exists", mock.Anything, testTransactionID, testBuyerID).Return(false, nil)
+	ratingRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Rating")).Return(nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	rating, err := svc.Create(context.Background(), testBuyerID, &dto.CreateRatingRequest{
+		TransactionID: testTransactionID,
+		Stars:         5,
+		Comment:       "<img src=x onerror=alert(1)>Fast\r\ntrade",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Fast\ntrade", *rating.Comment)
+}
+
+func TestRatingCreate_CommentTooLong(t *testing.T) {
+	svc, _, txnRepo, _, _ := newTestRatingService()
+	svc.SetTextLimits(TextLimits{Comment: 5})
+
+	_, err := svc.Create(context.Background(), testBuyerID, &dto.CreateRatingRequest{
+		TransactionID: testTransactionID,
+		Stars:         5,
+		Comment:       "too long",
+	})
+
+	var errs ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "must be at most 5 characters", errs["comment"])
+	txnRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+)
+
+// viewFlushBatchSize is how many dirty listings one flush step claims at a time
+const viewFlushBatchSize = 100
+
+// SetViewBuffering buffers listing views in Redis instead of writing each one to the
+// database. Buffered views are applied by FlushViews; run RunViewFlusher alongside.
+func (s *ListingService) SetViewBuffering(enabled bool) {
+	s.viewBuffering = enabled
+}
+
+// buffersViews reports whether views go through the Redis buffer
+func (s *ListingService) buffersViews() bool {
+	return s.viewBuffering && s.redis.IsAvailable()
+}
+
+// bufferView counts a view in Redis and marks the listing for the next flush. Once the
+// counter is bumped the view is kept even if marking fails; the next view marks it.
+func (s *ListingService) bufferView(ctx context.Context, id string) error {
+	if _, err := s.redis.Incr(ctx, cache.ViewsPendingKey(id)); err != nil {
+		return err
+	}
+	_ = s.redis.SAdd(ctx, cache.ViewsDirtyKey(), id)
+	return nil
+}
+
+// pendingViews returns the views buffered for a listing since the last flush
+func (s *ListingService) pendingViews(ctx context.Context, id string) int {
+	if !s.buffersViews() {
+		return 0
+	}
+	val, err := s.redis.Get(ctx, cache.ViewsPendingKey(id))
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(val)
+	return n
+}
+
+// withPendingViews adds the buffered views to the listing so new views show up before
+// they are flushed. Call it after the listing has been cached, never before.
+func (s *ListingService) withPendingViews(ctx context.Context, listing *models.Listing) *models.Listing {
+	listing.Views += s.pendingViews(ctx, listing.ID)
+	return listing
+}
+
+// FlushViews moves buffered views into the database and returns how many listings
+// were updated. Each listing's counter is claimed with GETDEL, so a view is applied
+// exactly once even when flushes overlap; views counted after the claim wait for the
+// next flush. A failed write puts the claimed views back into the buffer.
+func (s *ListingService) FlushViews(ctx context.Context) (int, error) {
+	if !s.redis.IsAvailable() {
+		return 0, nil
+	}
+	log := logger.FromContext(ctx)
+
+	flushed := 0
+	for {
+		ids, err := s.redis.SPopN(ctx, cache.ViewsDirtyKey(), viewFlushBatchSize)
+		if err != nil {
+			return flushed, err
+		}
+		if len(ids) == 0 {
+			return flushed, nil
+		}
+
+		for _, id := range ids {
+			key := cache.ViewsPendingKey(id)
+			val, err := s.redis.GetDel(ctx, key)
+			if err != nil {
+				_ = s.redis.SAdd(ctx, cache.ViewsDirtyKey(), id)
+				return flushed, err
+			}
+			n, _ := strconv.ParseInt(val, 10, 64)
+			if n <= 0 {
+				continue
+			}
+
+			if err := s.repo.AddViews(ctx, id, n); err != nil {
+				log.Warn("failed to flush listing views, keeping them buffered",
+					"error", err.Error(),
+					"listing_id", id,
+					"views", n,
+				)
+				_, _ = s.redis.IncrBy(ctx, key, n)
+				_ = s.redis.SAdd(ctx, cache.ViewsDirtyKey(), id)
+				continue
+			}
+			_ = s.invalidator.InvalidateListing(ctx, id)
+			_ = s.invalidator.InvalidateListingDTO(ctx, id)
+			flushed++
+		}
+	}
+}
+
+// RunViewFlusher flushes buffered views every interval until ctx is cancelled, then
+// flushes once more so views counted before shutdown aren't left behind
+func (s *ListingService) RunViewFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flushViewsAndLog(context.Background())
+			return
+		case <-ticker.C:
+			s.flushViewsAndLog(ctx)
+		}
+	}
+}
+
+// flushViewsAndLog runs one flush, logging failures instead of returning them
+func (s *ListingService) flushViewsAndLog(ctx context.Context) {
+	n, err := s.FlushViews(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to flush listing views",
+			"error", err.Error(),
+			"flushed", n,
+		)
+		return
+	}
+	if n > 0 {
+		logger.FromContext(ctx).Debug("flushed listing views", "listings", n)
+	}
+}
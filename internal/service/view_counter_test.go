@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func TestIncrementViews_BufferedCountShowsBeforeFlush(t *testing.T) {
+	redis, _ := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+	svc.SetViewBuffering(true)
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	listing.Views = 10
+	listingRepo.On("GetByIDWithSeller", mock.Anything, testListingID).Return(listing, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.IncrementViews(ctx, testListingID, ""))
+	}
+
+	got, err := svc.GetByID(ctx, testListingID)
+	require.NoError(t, err)
+	assert.Equal(t, 13, got.Views)
+
+	// The cached copy keeps the database count, so the buffer isn't added twice
+	got, err = svc.GetByID(ctx, testListingID)
+	require.NoError(t, err)
+	assert.Equal(t, 13, got.Views)
+	listingRepo.AssertNotCalled(t, "IncrementViews", mock.Anything, mock.Anything)
+}
+
+func TestFlushViews_AppliesEachViewOnce(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+	svc.SetViewBuffering(true)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.IncrementViews(ctx, "listing-a", ""))
+	}
+	require.NoError(t, svc.IncrementViews(ctx, "listing-b", ""))
+	listingRepo.On("AddViews", mock.Anything, "listing-a", int64(3)).Return(nil).Once()
+	listingRepo.On("AddViews", mock.Anything, "listing-b", int64(1)).Return(nil).Once()
+
+	n, err := svc.FlushViews(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.False(t, mr.Exists(cache.ViewsPendingKey("listing-a")))
+
+	n, err = svc.FlushViews(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	listingRepo.AssertExpectations(t)
+}
+
+func TestFlushViews_FailedWriteKeepsViewsBuffered(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+	svc.SetViewBuffering(true)
+	ctx := context.Background()
+
+	require.NoError(t, svc.IncrementViews(ctx, testListingID, ""))
+	require.NoError(t, svc.IncrementViews(ctx, testListingID, ""))
+	listingRepo.On("AddViews", mock.Anything, testListingID, int64(2)).Return(errors.New("db down")).Once()
+
+	n, err := svc.FlushViews(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	pending, err := mr.Get(cache.ViewsPendingKey(testListingID))
+	require.NoError(t, err)
+	assert.Equal(t, "2", pending)
+	dirty, err := mr.SIsMember(cache.ViewsDirtyKey(), testListingID)
+	require.NoError(t, err)
+	assert.True(t, dirty)
+}
+
+func TestIncrementViews_WithoutBufferingWritesDirectly(t *testing.T) {
+	redis, mr := newTestRedisReal(t)
+	profileRepo := new(mocks.MockProfileRepository)
+	listingRepo := new(mocks.MockListingRepository)
+	svc, _ := setupListingService(profileRepo, listingRepo, redis)
+
+	listingRepo.On("IncrementViews", mock.Anything, testListingID).Return(nil).Once()
+
+	require.NoError(t, svc.IncrementViews(context.Background(), testListingID, ""))
+	assert.False(t, mr.Exists(cache.ViewsPendingKey(testListingID)))
+	listingRepo.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository"
+)
+
+// FreeWatchLimit is how many item watches a non-premium user can keep. Premium is unlimited.
+const FreeWatchLimit = 5
+
+// WatchService handles name-only item watches, the free alternative to wishlist
+type WatchService struct {
+	repo                repository.WatchRepository
+	profileService      *ProfileService
+	notificationService *NotificationService
+}
+
+// NewWatchService creates a new watch service
+func NewWatchService(repo repository.WatchRepository, profileService *ProfileService, notificationService *NotificationService) *WatchService {
+	return &WatchService{
+		repo:                repo,
+		profileService:      profileService,
+		notificationService: notificationService,
+	}
+}
+
+// Create starts watching an item name. Watching the same name twice is rejected.
+func (s *WatchService) Create(ctx context.Context, userID string, req *dto.CreateWatchRequest) (*models.ItemWatch, error) {
+	itemName := strings.TrimSpace(req.ItemName)
+	if itemName == "" {
+		return nil, ValidationErrors{"itemName": "is required"}
+	}
+
+	exists, err := s.repo.Exists(ctx, userID, itemName, req.Game)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyExists
+	}
+
+	profile, err := s.profileService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.IsPremium {
+		count, err := s.repo.CountByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= FreeWatchLimit {
+			return nil, ErrWatchLimitReached
+		}
+	}
+
+	watch := &models.ItemWatch{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		ItemName:  itemName,
+		Game:      req.Game,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.Create(ctx, watch); err != nil {
+		return nil, err
+	}
+
+	return watch, nil
+}
+
+// List returns the user's item watches, newest first
+func (s *WatchService) List(ctx context.Context, userID string) ([]*models.ItemWatch, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+// Delete stops watching an item
+func (s *WatchService) Delete(ctx context.Context, id string, userID string) error {
+	watch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if watch.UserID != userID {
+		return ErrForbidden
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// NotifyWatchers sends an item watch notification to everyone watching the
+// listing's name in its game. Called asynchronously after listing creation.
+func (s *WatchService) NotifyWatchers(ctx context.Context, listing *models.Listing) {
+	log := logger.FromContext(ctx)
+
+	watches, err := s.repo.FindWatchersForListing(ctx, listing)
+	if err != nil {
+		log.Error("failed to find item watchers",
+			"error", err.Error(),
+			"listing_id", listing.ID,
+		)
+		return
+	}
+	if len(watches) == 0 {
+		return
+	}
+
+	notifications := make([]*models.Notification, 0, len(watches))
+	for _, watch := range watches {
+		notifications = append(notifications, itemWatchNotification(watch, listing))
+	}
+
+	sent := s.notificationService.CreateBatched(ctx, notifications)
+	log.Info("item watch notifications sent",
+		"listing_id", listing.ID,
+		"watchers", len(watches),
+		"sent", sent,
+	)
+}
+
+func itemWatchNotification(watch *models.ItemWatch, listing *models.Listing) *models.Notification {
+	refType := "listing"
+	return &models.Notification{
+		UserID:        watch.UserID,
+		Type:          models.NotificationTypeItemWatch,
+		Title:         "Watched Item Listed",
+		Body:          strPtr(fmt.Sprintf("\"%s\" was just listed.", listing.Name)),
+		ReferenceType: &refType,
+		ReferenceID:   &listing.ID,
+	}
+}
+
+// ToResponse converts an item watch to its DTO
+func (s *WatchService) ToResponse(watch *models.ItemWatch) *dto.WatchResponse {
+	return &dto.WatchResponse{
+		ID:        watch.ID,
+		ItemName:  watch.ItemName,
+		Game:      watch.Game,
+		CreatedAt: watch.CreatedAt,
+	}
+}
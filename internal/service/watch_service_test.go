@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
+)
+
+func newWatchTestService() (*WatchService, *mocks.MockWatchRepository, *mocks.MockProfileRepository, *mocks.MockNotificationRepository) {
+	watchRepo := new(mocks.MockWatchRepository)
+	profileRepo := new(mocks.MockProfileRepository)
+	notifRepo := new(mocks.MockNotificationRepository)
+
+	profileService := NewProfileService(profileRepo, nil, nil)
+	notifService := NewNotificationService(notifRepo, nil)
+
+	return NewWatchService(watchRepo, profileService, notifService), watchRepo, profileRepo, notifRepo
+}
+
+func TestWatchCreate_FreeUserUnderLimit(t *testing.T) {
+	svc, watchRepo, profileRepo, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("Exists", ctx, testUserID, "Shako", "diablo2").Return(false, nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+	watchRepo.On("CountByUserID", ctx, testUserID).Return(FreeWatchLimit-1, nil)
+	watchRepo.On("Create", ctx, mock.AnythingOfType("*models.ItemWatch")).Return(nil)
+
+	watch, err := svc.Create(ctx, testUserID, &dto.CreateWatchRequest{ItemName: "  Shako ", Game: "diablo2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Shako", watch.ItemName)
+	assert.Equal(t, testUserID, watch.UserID)
+}
+
+func TestWatchCreate_FreeUserAtLimit(t *testing.T) {
+	svc, watchRepo, profileRepo, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("Exists", ctx, testUserID, "Shako", "diablo2").Return(false, nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+	watchRepo.On("CountByUserID", ctx, testUserID).Return(FreeWatchLimit, nil)
+
+	_, err := svc.Create(ctx, testUserID, &dto.CreateWatchRequest{ItemName: "Shako", Game: "diablo2"})
+
+	assert.ErrorIs(t, err, ErrWatchLimitReached)
+	watchRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWatchCreate_PremiumUserUnlimited(t *testing.T) {
+	svc, watchRepo, profileRepo, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("Exists", ctx, testUserID, "Shako", "diablo2").Return(false, nil)
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID, withPremium), nil)
+	watchRepo.On("Create", ctx, mock.AnythingOfType("*models.ItemWatch")).Return(nil)
+
+	_, err := svc.Create(ctx, testUserID, &dto.CreateWatchRequest{ItemName: "Shako", Game: "diablo2"})
+
+	assert.NoError(t, err)
+	watchRepo.AssertNotCalled(t, "CountByUserID", mock.Anything, mock.Anything)
+}
+
+func TestWatchCreate_AlreadyWatching(t *testing.T) {
+	svc, watchRepo, _, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("Exists", ctx, testUserID, "Shako", "diablo2").Return(true, nil)
+
+	_, err := svc.Create(ctx, testUserID, &dto.CreateWatchRequest{ItemName: "Shako", Game: "diablo2"})
+
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestWatchDelete_NotOwner(t *testing.T) {
+	svc, watchRepo, _, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("GetByID", ctx, "watch-1").Return(&models.ItemWatch{ID: "watch-1", UserID: testSellerID}, nil)
+
+	err := svc.Delete(ctx, "watch-1", testUserID)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	watchRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestWatchDelete_NotFound(t *testing.T) {
+	svc, watchRepo, _, _ := newWatchTestService()
+	ctx := context.Background()
+
+	watchRepo.On("GetByID", ctx, "missing").Return(nil, sql.ErrNoRows)
+
+	assert.ErrorIs(t, svc.Delete(ctx, "missing", testUserID), sql.ErrNoRows)
+}
+
+func TestNotifyWatchers_SendsOneNotificationPerWatcher(t *testing.T) {
+	svc, watchRepo, _, notifRepo := newWatchTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	watchRepo.On("FindWatchersForListing", ctx, listing).Return([]*models.ItemWatch{
+		{ID: "w-1", UserID: testUserID},
+		{ID: "w-2", UserID: testBuyerID},
+	}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 2 &&
+			ns[0].Type == models.NotificationTypeItemWatch &&
+			*ns[0].ReferenceID == testListingID
+	})).Return(nil)
+
+	svc.NotifyWatchers(ctx, listing)
+
+	notifRepo.AssertExpectations(t)
+}
+
+func TestNotifyWatchers_NoWatchers(t *testing.T) {
+	svc, watchRepo, _, notifRepo := newWatchTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID)
+	watchRepo.On("FindWatchersForListing", ctx, listing).Return([]*models.ItemWatch{}, nil)
+
+	svc.NotifyWatchers(ctx, listing)
+
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
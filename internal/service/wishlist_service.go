@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/games/d2"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/logger"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
@@ -17,6 +21,22 @@ import (
 
 const maxActiveWishlistItems = 10
 
+// MaxWishlistImportItems caps how many items one wishlist import may carry
+const MaxWishlistImportItems = 100
+
+// DefaultWishlistMatchConcurrency is how many listings are matched against wishlists at once
+const DefaultWishlistMatchConcurrency = 4
+
+// wishlistMatchBufferTTL drops buffered matches whose flush was lost (e.g. Redis lost the due entry)
+const wishlistMatchBufferTTL = 24 * time.Hour
+
+// wishlistFlushBatchSize is how many due users one FlushDueMatches step claims at a time
+const wishlistFlushBatchSize = 100
+
+// wishlistNotifiedTTL is how long a (wishlist item, listing) pair is remembered so
+// re-running matching for a listing doesn't notify the same owner twice
+const wishlistNotifiedTTL = 7 * 24 * time.Hour
+
 // ErrWishlistLimitReached indicates a premium user has reached their wishlist item limit
 var ErrWishlistLimitReached = fmt.Errorf("wishlist limit reached")
 
@@ -28,6 +48,13 @@ type WishlistService struct {
 	repo                repository.WishlistRepository
 	profileService      *ProfileService
 	notificationService *NotificationService
+	gameRegistry        *games.Registry
+	// matchSlots bounds concurrent CheckAndNotifyMatches runs so listing bursts don't flood the DB
+	matchSlots chan struct{}
+	// redis and matchGroupWindow group a user's matches into one notification (0 sends each
+	// match); redis also remembers which matches were already notified
+	redis            *cache.RedisClient
+	matchGroupWindow time.Duration
 }
 
 // NewWishlistService creates a new wishlist service
@@ -40,11 +67,66 @@ func NewWishlistService(
 		repo:                repo,
 		profileService:      profileService,
 		notificationService: notificationService,
+		matchSlots:          make(chan struct{}, DefaultWishlistMatchConcurrency),
 	}
 }
 
+// SetGameRegistry sets the game registry used to validate categories and rarities
+func (s *WishlistService) SetGameRegistry(registry *games.Registry) {
+	s.gameRegistry = registry
+}
+
+// SetMatchGrouping collapses a user's wishlist matches within window into one
+// notification. Grouping needs Redis and is off when window is 0. The Redis client
+// is used to skip matches already notified even when grouping is off.
+func (s *WishlistService) SetMatchGrouping(redis *cache.RedisClient, window time.Duration) {
+	s.redis = redis
+	s.matchGroupWindow = window
+}
+
+// ValidateCreate reports every field problem in a create request at once
+func (s *WishlistService) ValidateCreate(req *dto.CreateWishlistItemRequest) ValidationErrors {
+	errs := ValidationErrors{}
+	validateRequired(errs, "name", req.Name)
+	validateRequired(errs, "game", req.Game)
+
+	game := newGameValidator(s.gameRegistry, req.Game, errs)
+	if req.Category != nil && *req.Category != "" && !game.validCategory(*req.Category) {
+		errs.Add("category", "unknown category")
+	}
+	if req.Rarity != nil && *req.Rarity != "" && !game.validRarity(*req.Rarity) {
+		errs.Add("rarity", "unknown rarity")
+	}
+	validatePlatforms(errs, req.Platforms, false)
+
+	for i, sc := range req.StatCriteria {
+		field := fmt.Sprintf("statCriteria[%d]", i)
+		if strings.TrimSpace(sc.Code) == "" {
+			errs.Add(field+".code", "is required")
+		}
+		if sc.MinValue != nil && sc.MaxValue != nil && *sc.MinValue > *sc.MaxValue {
+			errs.Add(field+".maxValue", "must not be less than minValue")
+		}
+	}
+
+	return errs
+}
+
+// SetMatchConcurrency sets how many listings can be matched against wishlists at once.
+// Values below 1 fall back to DefaultWishlistMatchConcurrency.
+func (s *WishlistService) SetMatchConcurrency(n int) {
+	if n < 1 {
+		n = DefaultWishlistMatchConcurrency
+	}
+	s.matchSlots = make(chan struct{}, n)
+}
+
 // Create creates a new wishlist item
 func (s *WishlistService) Create(ctx context.Context, userID string, req *dto.CreateWishlistItemRequest) (*models.WishlistItem, error) {
+	if err := s.ValidateCreate(req).Err(); err != nil {
+		return nil, err
+	}
+
 	// Check premium status
 	profile, err := s.profileService.GetByID(ctx, userID)
 	if err != nil {
@@ -63,7 +145,16 @@ func (s *WishlistService) Create(ctx context.Context, userID string, req *dto.Cr
 		return nil, ErrWishlistLimitReached
 	}
 
-	// Convert stat criteria
+	item := newWishlistItem(userID, req)
+	if err := s.repo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// newWishlistItem builds an active wishlist item from a create request
+func newWishlistItem(userID string, req *dto.CreateWishlistItemRequest) *models.WishlistItem {
 	var statCriteria []models.StatCriterion
 	for _, sc := range req.StatCriteria {
 		statCriteria = append(statCriteria, models.StatCriterion{
@@ -74,7 +165,7 @@ func (s *WishlistService) Create(ctx context.Context, userID string, req *dto.Cr
 		})
 	}
 
-	item := &models.WishlistItem{
+	return &models.WishlistItem{
 		ID:            uuid.New().String(),
 		UserID:        userID,
 		Name:          req.Name,
@@ -92,12 +183,137 @@ func (s *WishlistService) Create(ctx context.Context, userID string, req *dto.Cr
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
+}
 
-	if err := s.repo.Create(ctx, item); err != nil {
+// Per-item outcomes of a wishlist import
+const (
+	WishlistImportCreated      = "created"
+	WishlistImportDuplicate    = "duplicate"
+	WishlistImportInvalid      = "invalid"
+	WishlistImportLimitReached = "limit_reached"
+	WishlistImportFailed       = "failed"
+)
+
+// ImportBatch creates many wishlist items at once, reporting what happened to each.
+// Items go through the same validation as Create. Items matching one the user already
+// has (or an earlier item in the batch) by name and criteria are skipped, and once the
+// active limit is used up the rest are refused.
+func (s *WishlistService) ImportBatch(ctx context.Context, userID string, reqs []dto.CreateWishlistItemRequest) (*dto.WishlistImportResponse, error) {
+	if len(reqs) == 0 || len(reqs) > MaxWishlistImportItems {
+		return nil, ValidationErrors{"items": fmt.Sprintf("must contain between 1 and %d items", MaxWishlistImportItems)}
+	}
+
+	profile, err := s.profileService.GetByID(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
+	if !profile.IsPremium {
+		return nil, ErrPremiumRequired
+	}
 
-	return item, nil
+	existing, _, err := s.repo.ListByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing)+len(reqs))
+	for _, item := range existing {
+		seen[wishlistDedupKey(item)] = true
+	}
+
+	count, err := s.repo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := maxActiveWishlistItems - count
+
+	resp := &dto.WishlistImportResponse{Results: make([]dto.WishlistImportResult, 0, len(reqs))}
+	for i := range reqs {
+		req := &reqs[i]
+		result := dto.WishlistImportResult{Index: i, Name: req.Name}
+
+		if errs := s.ValidateCreate(req); len(errs) > 0 {
+			result.Status = WishlistImportInvalid
+			result.Errors = errs
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+
+		item := newWishlistItem(userID, req)
+		key := wishlistDedupKey(item)
+		switch {
+		case seen[key]:
+			result.Status = WishlistImportDuplicate
+		case remaining <= 0:
+			result.Status = WishlistImportLimitReached
+		default:
+			if err := s.repo.Create(ctx, item); err != nil {
+				logger.FromContext(ctx).Warn("failed to import wishlist item",
+					"error", err.Error(),
+					"user_id", userID,
+					"index", i,
+				)
+				result.Status = WishlistImportFailed
+				break
+			}
+			seen[key] = true
+			remaining--
+			result.Status = WishlistImportCreated
+			result.ID = &item.ID
+			resp.Created++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Skipped = len(reqs) - resp.Created
+
+	return resp, nil
+}
+
+// ExportAll returns all of the user's active and paused wishlist items, newest first
+func (s *WishlistService) ExportAll(ctx context.Context, userID string) ([]*models.WishlistItem, error) {
+	profile, err := s.profileService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.IsPremium {
+		return nil, ErrPremiumRequired
+	}
+
+	items, _, err := s.repo.ListByUserID(ctx, userID, 0, 0)
+	return items, err
+}
+
+// wishlistDedupKey identifies a wishlist item by its name and match criteria, ignoring
+// case, surrounding whitespace and the order of platforms and stat criteria
+func wishlistDedupKey(item *models.WishlistItem) string {
+	platforms := slices.Clone(item.Platforms)
+	slices.Sort(platforms)
+
+	criteria := make([]string, 0, len(item.StatCriteria))
+	for _, sc := range item.StatCriteria {
+		criteria = append(criteria, fmt.Sprintf("%s:%s:%s", strings.ToLower(sc.Code), intKey(sc.MinValue), intKey(sc.MaxValue)))
+	}
+	slices.Sort(criteria)
+
+	data, _ := json.Marshal([]any{
+		strings.ToLower(strings.TrimSpace(item.Name)),
+		item.Game,
+		item.Category,
+		item.Rarity,
+		item.Ladder,
+		item.Hardcore,
+		item.IsNonRotw,
+		platforms,
+		criteria,
+	})
+	return string(data)
+}
+
+// intKey formats an optional bound for wishlistDedupKey
+func intKey(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
 }
 
 // List retrieves wishlist items for a user
@@ -195,11 +411,13 @@ func (s *WishlistService) Delete(ctx context.Context, id string, userID string)
 	return s.repo.Delete(ctx, id)
 }
 
-// CheckAndNotifyMatches finds wishlist items matching a listing and sends notifications
+// CheckAndNotifyMatches finds wishlist items matching a listing and sends notifications.
+// Blocks until a match slot is free, so only a bounded number of listings are matched at once.
 func (s *WishlistService) CheckAndNotifyMatches(ctx context.Context, listing *models.Listing) {
 	log := logger.FromContext(ctx)
 
-	fmt.Printf("[WISHLIST] CheckAndNotifyMatches called for listing: id=%s name=%s game=%s\n", listing.ID, listing.Name, listing.Game)
+	s.matchSlots <- struct{}{}
+	defer func() { <-s.matchSlots }()
 
 	log.Info("starting wishlist matching for new listing",
 		"listing_id", listing.ID,
@@ -209,10 +427,8 @@ func (s *WishlistService) CheckAndNotifyMatches(ctx context.Context, listing *mo
 		"listing_game", listing.Game,
 	)
 
-	fmt.Printf("[WISHLIST] Calling repo.FindMatchingItems for listing: id=%s name=%s\n", listing.ID, listing.Name)
 	candidates, err := s.repo.FindMatchingItems(ctx, listing)
 	if err != nil {
-		fmt.Printf("[WISHLIST] ERROR finding matching items: %v\n", err)
 		log.Error("failed to find matching wishlist items",
 			"error", err.Error(),
 			"listing_id", listing.ID,
@@ -220,14 +436,11 @@ func (s *WishlistService) CheckAndNotifyMatches(ctx context.Context, listing *mo
 		return
 	}
 
-	fmt.Printf("[WISHLIST] Found %d candidates for listing: id=%s name=%s\n", len(candidates), listing.ID, listing.Name)
-
 	if len(candidates) == 0 {
 		log.Info("no wishlist candidates found for listing",
 			"listing_id", listing.ID,
 			"listing_name", listing.Name,
 		)
-		fmt.Printf("[WISHLIST] No candidates found - done\n")
 		return
 	}
 
@@ -238,69 +451,56 @@ func (s *WishlistService) CheckAndNotifyMatches(ctx context.Context, listing *mo
 	)
 
 	// Parse listing stats once
-	fmt.Printf("[WISHLIST] Parsing listing stats (raw length: %d bytes)\n", len(listing.Stats))
-	var listingStats []listingStat
-	if len(listing.Stats) > 0 {
-		if err := json.Unmarshal(listing.Stats, &listingStats); err != nil {
-			fmt.Printf("[WISHLIST] ERROR parsing listing stats: %v\n", err)
-			log.Error("failed to parse listing stats for wishlist matching",
-				"error", err.Error(),
-				"listing_id", listing.ID,
-			)
-			return
-		}
-		fmt.Printf("[WISHLIST] Parsed %d stats from JSON\n", len(listingStats))
-	} else {
-		fmt.Printf("[WISHLIST] Listing has no stats (empty)\n")
-	}
-
-	// Build stat lookup map: code -> value
-	statMap := make(map[string]int)
-	for _, stat := range listingStats {
-		fmt.Printf("[WISHLIST] Stat: code=%s value=%v (type=%T)\n", stat.Code, stat.Value, stat.Value)
-		if numVal := extractNumericValue(stat.Value); numVal != nil {
-			statMap[stat.Code] = *numVal
-			fmt.Printf("[WISHLIST] Added to statMap: %s = %d\n", stat.Code, *numVal)
-		} else {
-			fmt.Printf("[WISHLIST] Could not extract numeric value for code=%s\n", stat.Code)
-		}
+	statMap, err := parseListingStatMap(listing.Stats)
+	if err != nil {
+		log.Error("failed to parse listing stats for wishlist matching",
+			"error", err.Error(),
+			"listing_id", listing.ID,
+		)
+		return
 	}
 
-	fmt.Printf("[WISHLIST] Final statMap: %v\n", statMap)
-	log.Info("parsed listing stats for wishlist matching",
+	log.Debug("parsed listing stats for wishlist matching",
 		"listing_id", listing.ID,
 		"stat_count", len(statMap),
 		"stat_codes", getStatCodes(statMap),
 	)
 
-	matched := 0
+	var notifications []*models.Notification
 	for _, candidate := range candidates {
-		fmt.Printf("[WISHLIST] Evaluating candidate: id=%s user=%s name=%s criteria_count=%d\n",
-			candidate.ID, candidate.UserID, candidate.Name, len(candidate.StatCriteria))
-		for i, sc := range candidate.StatCriteria {
-			fmt.Printf("[WISHLIST] Candidate criterion %d: code=%s min=%v max=%v\n", i, sc.Code, sc.MinValue, sc.MaxValue)
-		}
-		log.Info("evaluating wishlist candidate",
+		log.Debug("evaluating wishlist candidate",
 			"listing_id", listing.ID,
 			"wishlist_id", candidate.ID,
 			"wishlist_user_id", candidate.UserID,
 			"wishlist_name", candidate.Name,
 			"stat_criteria_count", len(candidate.StatCriteria),
 		)
+		if !matchesListing(candidate, listing) {
+			log.Debug("wishlist item did NOT match listing seller, kind or game mode",
+				"listing_id", listing.ID,
+				"wishlist_id", candidate.ID,
+				"wishlist_name", candidate.Name,
+			)
+			continue
+		}
 		if s.matchesStatCriteria(candidate.StatCriteria, statMap, log) {
-			matched++
-			fmt.Printf("[WISHLIST] MATCH! Calling sendWishlistNotification for user=%s listing=%s\n", candidate.UserID, listing.ID)
-			log.Info("wishlist item MATCHED listing - sending notification",
+			if !s.claimMatchNotification(ctx, candidate.ID, listing.ID) {
+				log.Info("wishlist owner already notified about listing",
+					"listing_id", listing.ID,
+					"wishlist_id", candidate.ID,
+				)
+				continue
+			}
+			log.Info("wishlist item MATCHED listing - queueing notification",
 				"listing_id", listing.ID,
 				"listing_name", listing.Name,
 				"wishlist_id", candidate.ID,
 				"wishlist_user_id", candidate.UserID,
 				"wishlist_name", candidate.Name,
 			)
-			s.sendWishlistNotification(ctx, candidate, listing)
-			fmt.Printf("[WISHLIST] sendWishlistNotification completed for user=%s\n", candidate.UserID)
+			notifications = append(notifications, wishlistMatchNotification(candidate, listing))
 		} else {
-			log.Info("wishlist item did NOT match listing stats",
+			log.Debug("wishlist item did NOT match listing stats",
 				"listing_id", listing.ID,
 				"wishlist_id", candidate.ID,
 				"wishlist_name", candidate.Name,
@@ -308,13 +508,246 @@ func (s *WishlistService) CheckAndNotifyMatches(ctx context.Context, listing *mo
 		}
 	}
 
+	var sent int
+	if s.groupsMatches() {
+		sent = s.bufferMatches(ctx, listing, notifications)
+	} else {
+		// One insert for all matches instead of a write per candidate; users already
+		// notified within the last minute get their notification updated instead
+		sent = s.notificationService.CreateBatched(ctx, notifications)
+	}
+
 	log.Info("wishlist matching complete",
 		"listing_id", listing.ID,
 		"listing_name", listing.Name,
 		"candidates_evaluated", len(candidates),
-		"matches_found", matched,
+		"matches_found", len(notifications),
+		"notifications_sent", sent,
 	)
-	fmt.Printf("[WISHLIST] Matching complete: listing=%s candidates=%d matches=%d\n", listing.ID, len(candidates), matched)
+}
+
+// claimMatchNotification marks a wishlist item as notified about a listing and reports
+// whether this call made the claim. Without Redis every match is notified.
+func (s *WishlistService) claimMatchNotification(ctx context.Context, wishlistItemID, listingID string) bool {
+	claimed, err := s.redis.SetNX(ctx, cache.WishlistNotifiedKey(wishlistItemID, listingID), "1", wishlistNotifiedTTL)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to check wishlist notification dedupe, notifying anyway",
+			"error", err.Error(),
+			"wishlist_id", wishlistItemID,
+			"listing_id", listingID,
+		)
+		return true
+	}
+	return claimed
+}
+
+// bufferedWishlistMatch is a match waiting in a user's grouping buffer
+type bufferedWishlistMatch struct {
+	WishlistItemID string `json:"wishlistItemId,omitempty"`
+	ListingID      string `json:"listingId"`
+	ListingName    string `json:"listingName"`
+}
+
+// groupsMatches reports whether match notifications are grouped per user
+func (s *WishlistService) groupsMatches() bool {
+	return s.matchGroupWindow > 0 && s.redis.IsAvailable()
+}
+
+// bufferMatches adds each match to its recipient's buffer instead of notifying right
+// away. The first match in a window marks the user due in matchGroupWindow; later
+// matches only join the buffer. FlushDueMatches sends it once due, so a restart never
+// loses a scheduled flush. A match that can't be buffered is sent on its own. Returns
+// how many notifications were sent immediately.
+func (s *WishlistService) bufferMatches(ctx context.Context, listing *models.Listing, notifications []*models.Notification) int {
+	var unbuffered []*models.Notification
+	for _, notification := range notifications {
+		// Each entry keeps the wishlist item its notification was built for
+		var meta wishlistMatchMetadata
+		_ = json.Unmarshal(notification.Metadata, &meta)
+		entry, err := json.Marshal(bufferedWishlistMatch{
+			WishlistItemID: meta.WishlistItemID,
+			ListingID:      listing.ID,
+			ListingName:    listing.Name,
+		})
+		if err != nil {
+			unbuffered = append(unbuffered, notification)
+			continue
+		}
+
+		userID := notification.UserID
+		key := cache.WishlistMatchBufferKey(userID)
+		if err := s.redis.LPush(ctx, key, string(entry)); err != nil {
+			logger.FromContext(ctx).Warn("failed to buffer wishlist match, sending it now",
+				"error", err.Error(),
+				"listing_id", listing.ID,
+				"user_id", userID,
+			)
+			unbuffered = append(unbuffered, notification)
+			continue
+		}
+		_ = s.redis.Expire(ctx, key, wishlistMatchBufferTTL)
+
+		dueAt := time.Now().Add(s.matchGroupWindow)
+		if _, err := s.redis.ZAddNX(ctx, cache.WishlistMatchDueKey(), float64(dueAt.Unix()), userID); err != nil {
+			logger.FromContext(ctx).Warn("failed to schedule wishlist match flush",
+				"error", err.Error(),
+				"user_id", userID,
+			)
+		}
+	}
+
+	return s.notificationService.CreateBatch(ctx, unbuffered)
+}
+
+// FlushDueMatches sends the buffered matches of every user whose group window has
+// closed and returns how many users were flushed. Each user is claimed with ZREM, so
+// overlapping runs on several instances flush a buffer once.
+func (s *WishlistService) FlushDueMatches(ctx context.Context) (int, error) {
+	if !s.groupsMatches() {
+		return 0, nil
+	}
+
+	flushed := 0
+	for {
+		now := float64(time.Now().Unix())
+		userIDs, err := s.redis.ZRangeByScoreMax(ctx, cache.WishlistMatchDueKey(), now, wishlistFlushBatchSize)
+		if err != nil {
+			return flushed, err
+		}
+		if len(userIDs) == 0 {
+			return flushed, nil
+		}
+
+		for _, userID := range userIDs {
+			claimed, err := s.redis.ZRem(ctx, cache.WishlistMatchDueKey(), userID)
+			if err != nil {
+				return flushed, err
+			}
+			if claimed == 0 {
+				continue
+			}
+			s.flushMatches(ctx, userID)
+			flushed++
+		}
+	}
+}
+
+// RunMatchFlusher flushes due wishlist match buffers every interval until ctx is cancelled
+func (s *WishlistService) RunMatchFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.FlushDueMatches(ctx)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to flush wishlist matches",
+					"error", err.Error(),
+					"flushed", n,
+				)
+				continue
+			}
+			if n > 0 {
+				logger.FromContext(ctx).Debug("flushed wishlist matches", "users", n)
+			}
+		}
+	}
+}
+
+// flushMatches sends a user's buffered matches as a single notification. The user is
+// no longer due by then, so a match arriving mid-flush schedules the next one.
+func (s *WishlistService) flushMatches(ctx context.Context, userID string) {
+	entries, err := s.redis.LDrain(ctx, cache.WishlistMatchBufferKey(userID))
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to drain wishlist match buffer",
+			"error", err.Error(),
+			"user_id", userID,
+		)
+		return
+	}
+
+	// Entries are newest first; notify in match order and once per wishlist item and listing
+	seen := make(map[wishlistMatchMetadata]bool, len(entries))
+	matches := make([]bufferedWishlistMatch, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		var match bufferedWishlistMatch
+		if err := json.Unmarshal([]byte(entries[i]), &match); err != nil {
+			continue
+		}
+		pair := wishlistMatchMetadata{WishlistItemID: match.WishlistItemID, ListingID: match.ListingID}
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		matches = append(matches, match)
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	notification, err := groupedWishlistMatchNotification(userID, matches)
+	if err != nil {
+		return
+	}
+	if err := s.notificationService.Create(ctx, notification); err != nil {
+		logger.FromContext(ctx).Error("failed to send grouped wishlist notification",
+			"error", err.Error(),
+			"user_id", userID,
+			"match_count", len(matches),
+		)
+	}
+}
+
+// CountMatches returns how many distinct users have a wishlist item matching the
+// listing, without notifying anyone. Malformed stats count as no match, as they
+// would when the listing is created.
+func (s *WishlistService) CountMatches(ctx context.Context, listing *models.Listing) (int, error) {
+	candidates, err := s.repo.FindMatchingItems(ctx, listing)
+	if err != nil {
+		return 0, err
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	statMap, err := parseListingStatMap(listing.Stats)
+	if err != nil {
+		return 0, nil
+	}
+
+	log := logger.FromContext(ctx)
+	users := make(map[string]bool)
+	for _, candidate := range candidates {
+		if users[candidate.UserID] || !matchesListing(candidate, listing) {
+			continue
+		}
+		if s.matchesStatCriteria(candidate.StatCriteria, statMap, log) {
+			users[candidate.UserID] = true
+		}
+	}
+
+	return len(users), nil
+}
+
+// parseListingStatMap builds a stat code -> numeric value lookup from listing stats JSON
+func parseListingStatMap(stats json.RawMessage) (map[string]int, error) {
+	var listingStats []listingStat
+	if len(stats) > 0 {
+		if err := json.Unmarshal(stats, &listingStats); err != nil {
+			return nil, err
+		}
+	}
+
+	statMap := make(map[string]int)
+	for _, stat := range listingStats {
+		if numVal := extractNumericValue(stat.Value); numVal != nil {
+			statMap[stat.Code] = *numVal
+		}
+	}
+	return statMap, nil
 }
 
 // getStatCodes extracts stat codes from the stat map for logging
@@ -332,22 +765,58 @@ type listingStat struct {
 	Value interface{} `json:"value,omitempty"`
 }
 
+// matchesListing checks everything about a candidate except its stat criteria: it must
+// belong to someone other than the seller and ask for the listing's kind and game mode
+func matchesListing(item *models.WishlistItem, listing *models.Listing) bool {
+	return item.UserID != listing.SellerID && matchesItemKind(item, listing) && matchesGameMode(item, listing)
+}
+
+// matchesItemKind checks the listing has the rarity and category the wishlist item
+// asks for, ignoring case. Unset or empty wishlist fields match anything.
+func matchesItemKind(item *models.WishlistItem, listing *models.Listing) bool {
+	if item.Rarity != nil && *item.Rarity != "" && !strings.EqualFold(*item.Rarity, listing.Rarity) {
+		return false
+	}
+	if item.Category != nil && *item.Category != "" && !strings.EqualFold(*item.Category, listing.Category) {
+		return false
+	}
+	return true
+}
+
+// matchesGameMode checks the listing is on a ladder/hardcore/non-RotW mode and a
+// platform the wishlist item asks for. Unset wishlist fields match anything.
+func matchesGameMode(item *models.WishlistItem, listing *models.Listing) bool {
+	if item.Ladder != nil && *item.Ladder != listing.Ladder {
+		return false
+	}
+	if item.Hardcore != nil && *item.Hardcore != listing.Hardcore {
+		return false
+	}
+	if item.IsNonRotw != nil && *item.IsNonRotw != listing.IsNonRotw {
+		return false
+	}
+	if len(item.Platforms) == 0 {
+		return true
+	}
+	for _, wanted := range item.Platforms {
+		for _, platform := range listing.Platforms {
+			if strings.EqualFold(wanted, platform) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // matchesStatCriteria checks if listing stats satisfy all wishlist stat criteria
 func (s *WishlistService) matchesStatCriteria(criteria []models.StatCriterion, statMap map[string]int, log *slog.Logger) bool {
-	fmt.Printf("[WISHLIST-MATCH] Checking %d stat criteria against %d listing stats\n", len(criteria), len(statMap))
-	fmt.Printf("[WISHLIST-MATCH] Listing stats: %v\n", statMap)
-
 	if len(criteria) == 0 {
-		fmt.Printf("[WISHLIST-MATCH] No stat criteria - auto match!\n")
 		return true
 	}
 
-	for i, c := range criteria {
-		fmt.Printf("[WISHLIST-MATCH] Criterion %d: code=%s min=%v max=%v\n", i, c.Code, c.MinValue, c.MaxValue)
-
+	for _, c := range criteria {
 		// Expand the criterion code to all aliases (canonical + game codes)
 		codes := d2.ExpandStatCode(c.Code)
-		fmt.Printf("[WISHLIST-MATCH] Expanded codes to search: %v\n", codes)
 
 		var value int
 		var found bool
@@ -355,74 +824,78 @@ func (s *WishlistService) matchesStatCriteria(criteria []models.StatCriterion, s
 			if v, exists := statMap[code]; exists {
 				value = v
 				found = true
-				fmt.Printf("[WISHLIST-MATCH] Found matching stat: code=%s value=%d\n", code, v)
 				break
 			}
 		}
 
 		if !found {
-			fmt.Printf("[WISHLIST-MATCH] FAIL: Stat code %s NOT FOUND in listing (searched: %v, available: %v)\n",
-				c.Code, codes, getStatCodes(statMap))
+			log.Debug("wishlist stat not found in listing",
+				"stat_code", c.Code,
+				"searched_codes", codes,
+			)
 			return false
 		}
 
 		if c.MinValue != nil && value < *c.MinValue {
-			fmt.Printf("[WISHLIST-MATCH] FAIL: Stat %s value %d is BELOW minimum %d\n", c.Code, value, *c.MinValue)
+			log.Debug("wishlist stat below minimum", "stat_code", c.Code, "value", value, "min", *c.MinValue)
 			return false
 		}
 		if c.MaxValue != nil && value > *c.MaxValue {
-			fmt.Printf("[WISHLIST-MATCH] FAIL: Stat %s value %d is ABOVE maximum %d\n", c.Code, value, *c.MaxValue)
+			log.Debug("wishlist stat above maximum", "stat_code", c.Code, "value", value, "max", *c.MaxValue)
 			return false
 		}
-		fmt.Printf("[WISHLIST-MATCH] OK: Stat %s value %d passes (min=%v max=%v)\n", c.Code, value, c.MinValue, c.MaxValue)
 	}
 
-	fmt.Printf("[WISHLIST-MATCH] All criteria passed - MATCH!\n")
 	return true
 }
 
-func (s *WishlistService) sendWishlistNotification(ctx context.Context, wishlistItem *models.WishlistItem, listing *models.Listing) {
-	fmt.Printf("[NOTIFICATION] sendWishlistNotification called: user=%s wishlist=%s listing=%s\n",
-		wishlistItem.UserID, wishlistItem.ID, listing.ID)
+// wishlistMatchMetadata lets the frontend deep-link a match to the wishlist item and listing
+type wishlistMatchMetadata struct {
+	WishlistItemID string `json:"wishlistItemId,omitempty"`
+	ListingID      string `json:"listingId"`
+}
 
-	log := logger.FromContext(ctx)
+// wishlistMatchNotification builds the notification telling a wishlist owner about a matching listing
+func wishlistMatchNotification(wishlistItem *models.WishlistItem, listing *models.Listing) *models.Notification {
 	refType := "listing"
-	notification := &models.Notification{
+	metadata, _ := json.Marshal(wishlistMatchMetadata{WishlistItemID: wishlistItem.ID, ListingID: listing.ID})
+	return &models.Notification{
 		UserID:        wishlistItem.UserID,
 		Type:          models.NotificationTypeWishlistMatch,
 		Title:         "Wishlist Match Found",
 		Body:          strPtr(fmt.Sprintf("A new listing for \"%s\" matches your wishlist!", listing.Name)),
 		ReferenceType: &refType,
 		ReferenceID:   &listing.ID,
+		Metadata:      metadata,
 	}
+}
 
-	fmt.Printf("[NOTIFICATION] Creating notification: user_id=%s type=%s title=%s ref_type=%s ref_id=%s\n",
-		notification.UserID, notification.Type, notification.Title, refType, listing.ID)
-
-	log.Info("creating wishlist match notification",
-		"user_id", wishlistItem.UserID,
-		"wishlist_id", wishlistItem.ID,
-		"wishlist_name", wishlistItem.Name,
-		"listing_id", listing.ID,
-		"listing_name", listing.Name,
-	)
+// groupedWishlistMatchNotification builds one notification for a user's buffered
+// matches. A single match reads like an ungrouped one; several list their wishlist
+// item and listing pairs in metadata instead of a reference.
+func groupedWishlistMatchNotification(userID string, matches []bufferedWishlistMatch) (*models.Notification, error) {
+	if len(matches) == 1 {
+		wishlistItem := &models.WishlistItem{ID: matches[0].WishlistItemID, UserID: userID}
+		listing := &models.Listing{ID: matches[0].ListingID, Name: matches[0].ListingName}
+		return wishlistMatchNotification(wishlistItem, listing), nil
+	}
 
-	if err := s.notificationService.Create(ctx, notification); err != nil {
-		fmt.Printf("[NOTIFICATION] ERROR creating notification: %v\n", err)
-		log.Error("failed to send wishlist match notification",
-			"error", err.Error(),
-			"wishlist_id", wishlistItem.ID,
-			"listing_id", listing.ID,
-		)
-	} else {
-		fmt.Printf("[NOTIFICATION] SUCCESS: Notification created for user=%s, notification_id=%s\n",
-			wishlistItem.UserID, notification.ID)
-		log.Info("wishlist match notification sent successfully",
-			"user_id", wishlistItem.UserID,
-			"wishlist_id", wishlistItem.ID,
-			"listing_id", listing.ID,
-		)
+	pairs := make([]wishlistMatchMetadata, 0, len(matches))
+	for _, match := range matches {
+		pairs = append(pairs, wishlistMatchMetadata{WishlistItemID: match.WishlistItemID, ListingID: match.ListingID})
 	}
+	metadata, err := json.Marshal(map[string]any{"matches": pairs})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Notification{
+		UserID:   userID,
+		Type:     models.NotificationTypeWishlistMatch,
+		Title:    "Wishlist Matches Found",
+		Body:     strPtr(fmt.Sprintf("%d items matched your wishlist!", len(matches))),
+		Metadata: metadata,
+	}, nil
 }
 
 // ToResponse converts a wishlist item model to a DTO response
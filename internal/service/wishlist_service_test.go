@@ -3,10 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/api/dto"
+	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/cache"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/models"
 	"github.com/ruanpelissoli/lootstash-marketplace-api/internal/repository/mocks"
 	"github.com/stretchr/testify/assert"
@@ -175,6 +179,122 @@ func TestWishlistList_FreeUser(t *testing.T) {
 	assert.ErrorIs(t, err, ErrPremiumRequired)
 }
 
+// ---------- Import / Export ----------
+
+func TestWishlistImportBatch_ReportsPerItem(t *testing.T) {
+	svc, wishlistRepo, profileRepo, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID, withPremium), nil)
+	// Already on the wishlist: Shako, so " shako " is a duplicate
+	wishlistRepo.On("ListByUserID", ctx, testUserID, 0, 0).Return([]*models.WishlistItem{testWishlistItem("wl-1", testUserID)}, 1, nil)
+	wishlistRepo.On("CountActiveByUserID", ctx, testUserID).Return(8, nil)
+	wishlistRepo.On("Create", ctx, mock.AnythingOfType("*models.WishlistItem")).Return(nil)
+
+	reqs := []dto.CreateWishlistItemRequest{
+		{Name: " shako ", Game: "diablo2"},
+		{Name: "Arachnid Mesh", Game: "diablo2"},
+		{Name: "Arachnid Mesh", Game: "diablo2"},
+		{Name: "", Game: "diablo2"},
+		{Name: "Griffon's Eye", Game: "diablo2"},
+		{Name: "Death's Fathom", Game: "diablo2"},
+	}
+
+	result, err := svc.ImportBatch(ctx, testUserID, reqs)
+
+	require.NoError(t, err)
+	statuses := make([]string, 0, len(result.Results))
+	for _, r := range result.Results {
+		statuses = append(statuses, r.Status)
+	}
+	assert.Equal(t, []string{
+		WishlistImportDuplicate,
+		WishlistImportCreated,
+		WishlistImportDuplicate, // repeated within the batch
+		WishlistImportInvalid,
+		WishlistImportCreated,
+		WishlistImportLimitReached, // 8 active + 2 created hits the cap of 10
+	}, statuses)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 4, result.Skipped)
+	assert.Contains(t, result.Results[3].Errors, "name")
+	assert.NotNil(t, result.Results[1].ID)
+	wishlistRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestWishlistImportBatch_CriteriaDistinguishDuplicates(t *testing.T) {
+	svc, wishlistRepo, profileRepo, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	existing := testWishlistItem("wl-1", testUserID, withStatCriteria([]models.StatCriterion{{Code: "def", MinValue: intPtr(100)}}))
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID, withPremium), nil)
+	wishlistRepo.On("ListByUserID", ctx, testUserID, 0, 0).Return([]*models.WishlistItem{existing}, 1, nil)
+	wishlistRepo.On("CountActiveByUserID", ctx, testUserID).Return(1, nil)
+	wishlistRepo.On("Create", ctx, mock.AnythingOfType("*models.WishlistItem")).Return(nil)
+
+	reqs := []dto.CreateWishlistItemRequest{
+		{Name: "Shako", Game: "diablo2", StatCriteria: []dto.StatCriterionDTO{{Code: "def", MinValue: intPtr(100)}}},
+		{Name: "Shako", Game: "diablo2", StatCriteria: []dto.StatCriterionDTO{{Code: "def", MinValue: intPtr(140)}}},
+	}
+
+	result, err := svc.ImportBatch(ctx, testUserID, reqs)
+
+	require.NoError(t, err)
+	assert.Equal(t, WishlistImportDuplicate, result.Results[0].Status)
+	assert.Equal(t, WishlistImportCreated, result.Results[1].Status)
+}
+
+func TestWishlistImportBatch_FreeUser(t *testing.T) {
+	svc, wishlistRepo, profileRepo, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+
+	result, err := svc.ImportBatch(ctx, testUserID, []dto.CreateWishlistItemRequest{{Name: "Shako", Game: "diablo2"}})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrPremiumRequired)
+	wishlistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWishlistImportBatch_Empty(t *testing.T) {
+	svc, _, _, _ := newWishlistTestService()
+
+	_, err := svc.ImportBatch(context.Background(), testUserID, nil)
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "items")
+}
+
+func TestWishlistExportAll_PremiumUser(t *testing.T) {
+	svc, wishlistRepo, profileRepo, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID, withPremium), nil)
+	items := []*models.WishlistItem{
+		testWishlistItem("wl-1", testUserID),
+		testWishlistItem("wl-2", testUserID, func(w *models.WishlistItem) { w.Status = "paused" }),
+	}
+	wishlistRepo.On("ListByUserID", ctx, testUserID, 0, 0).Return(items, 2, nil)
+
+	result, err := svc.ExportAll(ctx, testUserID)
+
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestWishlistExportAll_FreeUser(t *testing.T) {
+	svc, _, profileRepo, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	profileRepo.On("GetByID", ctx, testUserID).Return(testProfile(testUserID), nil)
+
+	_, err := svc.ExportAll(ctx, testUserID)
+
+	assert.ErrorIs(t, err, ErrPremiumRequired)
+}
+
 // ---------- Update ----------
 
 func TestWishlistUpdate_Success(t *testing.T) {
@@ -264,7 +384,7 @@ func TestCheckAndNotifyMatches_NoCandidates(t *testing.T) {
 	svc.CheckAndNotifyMatches(ctx, listing)
 
 	wishlistRepo.AssertCalled(t, "FindMatchingItems", ctx, listing)
-	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
 }
 
 func TestCheckAndNotifyMatches_MatchesAllCriteria(t *testing.T) {
@@ -277,11 +397,11 @@ func TestCheckAndNotifyMatches_MatchesAllCriteria(t *testing.T) {
 		{Code: "ed%", MinValue: intPtr(150), MaxValue: intPtr(200)},
 	}))
 	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
-	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
 
 	svc.CheckAndNotifyMatches(ctx, listing)
 
-	notifRepo.AssertCalled(t, "Create", mock.Anything, mock.AnythingOfType("*models.Notification"))
+	notifRepo.AssertCalled(t, "CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification"))
 }
 
 func TestCheckAndNotifyMatches_FailsMinValue(t *testing.T) {
@@ -298,7 +418,7 @@ func TestCheckAndNotifyMatches_FailsMinValue(t *testing.T) {
 
 	svc.CheckAndNotifyMatches(ctx, listing)
 
-	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
 }
 
 func TestCheckAndNotifyMatches_FailsMaxValue(t *testing.T) {
@@ -315,7 +435,7 @@ func TestCheckAndNotifyMatches_FailsMaxValue(t *testing.T) {
 
 	svc.CheckAndNotifyMatches(ctx, listing)
 
-	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
 }
 
 func TestCheckAndNotifyMatches_NoCriteria_AlwaysMatches(t *testing.T) {
@@ -328,11 +448,11 @@ func TestCheckAndNotifyMatches_NoCriteria_AlwaysMatches(t *testing.T) {
 	candidate := testWishlistItem("wl-1", "user-abc")
 	candidate.StatCriteria = nil
 	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
-	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
 
 	svc.CheckAndNotifyMatches(ctx, listing)
 
-	notifRepo.AssertCalled(t, "Create", mock.Anything, mock.AnythingOfType("*models.Notification"))
+	notifRepo.AssertCalled(t, "CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification"))
 }
 
 func TestCheckAndNotifyMatches_MissingStatCode_NoMatch(t *testing.T) {
@@ -349,7 +469,7 @@ func TestCheckAndNotifyMatches_MissingStatCode_NoMatch(t *testing.T) {
 
 	svc.CheckAndNotifyMatches(ctx, listing)
 
-	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
 }
 
 func TestCheckAndNotifyMatches_MultipleMatches(t *testing.T) {
@@ -366,12 +486,440 @@ func TestCheckAndNotifyMatches_MultipleMatches(t *testing.T) {
 	}))
 
 	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate1, candidate2}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	// Both candidates should be notified with a single insert.
+	require.Len(t, notifRepo.Calls, 1)
+	batch := notifRepo.Calls[0].Arguments.Get(1).([]*models.Notification)
+	assert.Len(t, batch, 2)
+	assert.Equal(t, "user-abc", batch[0].UserID)
+	assert.Equal(t, "user-xyz", batch[1].UserID)
+}
+
+func TestCheckAndNotifyMatches_HardcoreWishlistSkipsSoftcoreListing(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+	listing.Hardcore = false
+
+	candidate := testWishlistItem("wl-1", "user-abc", func(w *models.WishlistItem) {
+		w.Hardcore = boolPtr(true)
+	})
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestCheckAndNotifyMatches_MatchingModesAndStatsNotify(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+	listing.Hardcore = true
+	listing.Ladder = true
+	listing.Platforms = []string{"pc"}
+
+	matching := testWishlistItem("wl-1", "user-abc", func(w *models.WishlistItem) {
+		w.Hardcore = boolPtr(true)
+		w.Ladder = boolPtr(true)
+		w.Platforms = []string{"xbox", "pc"}
+		w.StatCriteria = []models.StatCriterion{{Code: "ed%", MinValue: intPtr(150)}}
+	})
+	// Right modes, but the stats don't line up
+	weakStats := testWishlistItem("wl-2", "user-def", func(w *models.WishlistItem) {
+		w.Hardcore = boolPtr(true)
+		w.StatCriteria = []models.StatCriterion{{Code: "ed%", MinValue: intPtr(200)}}
+	})
+	otherPlatform := testWishlistItem("wl-3", "user-xyz", func(w *models.WishlistItem) {
+		w.Platforms = []string{"playstation"}
+	})
+	nonRotw := testWishlistItem("wl-4", "user-rotw", func(w *models.WishlistItem) {
+		w.IsNonRotw = boolPtr(true)
+	})
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{matching, weakStats, otherPlatform, nonRotw}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	require.Len(t, notifRepo.Calls, 1)
+	batch := notifRepo.Calls[0].Arguments.Get(1).([]*models.Notification)
+	require.Len(t, batch, 1)
+	assert.Equal(t, "user-abc", batch[0].UserID)
+}
+
+func TestCheckAndNotifyMatches_RarityCategoryAndSeller(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		opt    func(*models.WishlistItem)
+		notify bool
+	}{
+		{"no rarity or category is a wildcard", "user-abc", func(w *models.WishlistItem) {}, true},
+		{"empty rarity and category are wildcards", "user-abc", func(w *models.WishlistItem) {
+			w.Rarity = strPtr("")
+			w.Category = strPtr("")
+		}, true},
+		{"rarity and category match ignoring case", "user-abc", func(w *models.WishlistItem) {
+			w.Rarity = strPtr("Unique")
+			w.Category = strPtr("HELM")
+		}, true},
+		{"different rarity", "user-abc", func(w *models.WishlistItem) {
+			w.Rarity = strPtr("set")
+		}, false},
+		{"different category", "user-abc", func(w *models.WishlistItem) {
+			w.Category = strPtr("armor")
+		}, false},
+		{"seller's own wishlist", testSellerID, func(w *models.WishlistItem) {}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+			ctx := context.Background()
+
+			listing := makeListingWithStats()
+			candidate := testWishlistItem("wl-1", tt.userID, tt.opt)
+			wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+			notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
+
+			svc.CheckAndNotifyMatches(ctx, listing)
+
+			if tt.notify {
+				notifRepo.AssertCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+			} else {
+				notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCheckAndNotifyMatches_NotifiesEachMatchOnce(t *testing.T) {
+	wantMetadata := `{"wishlistItemId":"wl-1","listingId":"` + testListingID + `"}`
+
+	t.Run("immediate", func(t *testing.T) {
+		svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+		redis, _ := newTestRedisReal(t)
+		svc.SetMatchGrouping(redis, 0)
+		ctx := context.Background()
+
+		listing := makeListingWithStats()
+		candidate := testWishlistItem("wl-1", "user-abc")
+		wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+		notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
+
+		svc.CheckAndNotifyMatches(ctx, listing)
+		svc.CheckAndNotifyMatches(ctx, listing)
+
+		require.Len(t, notifRepo.Calls, 1)
+		batch := notifRepo.Calls[0].Arguments.Get(1).([]*models.Notification)
+		require.Len(t, batch, 1)
+		assert.JSONEq(t, wantMetadata, string(batch[0].Metadata))
+	})
+
+	t.Run("grouped", func(t *testing.T) {
+		svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+		redis, mr := newTestRedisReal(t)
+		svc.SetMatchGrouping(redis, time.Hour)
+		ctx := context.Background()
+
+		listing := makeListingWithStats()
+		candidate := testWishlistItem("wl-1", "user-abc")
+		wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+		notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+		svc.CheckAndNotifyMatches(ctx, listing)
+		svc.CheckAndNotifyMatches(ctx, listing)
+
+		_, err := mr.ZAdd(cache.WishlistMatchDueKey(), float64(time.Now().Add(-time.Second).Unix()), "user-abc")
+		require.NoError(t, err)
+		_, err = svc.FlushDueMatches(ctx)
+		require.NoError(t, err)
+
+		require.Len(t, notifRepo.Calls, 1)
+		n := notifRepo.Calls[0].Arguments.Get(1).(*models.Notification)
+		assert.Equal(t, testListingID, n.GetReferenceID())
+		assert.JSONEq(t, wantMetadata, string(n.Metadata))
+	})
+}
+
+func TestCheckAndNotifyMatches_BatchFailureFallsBackToSingleInserts(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+
+	candidate1 := testWishlistItem("wl-1", "user-abc")
+	candidate1.StatCriteria = nil
+	candidate2 := testWishlistItem("wl-2", "user-xyz")
+	candidate2.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate1, candidate2}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(errors.New("batch failed"))
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == "user-abc"
+	})).Return(errors.New("insert failed"))
+	notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == "user-xyz"
+	})).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	notifRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestCheckAndNotifyMatches_BoundedConcurrency(t *testing.T) {
+	svc, wishlistRepo, _, _ := newWishlistTestService()
+	svc.SetMatchConcurrency(2)
+
+	var (
+		mu       sync.Mutex
+		running  int
+		maxSeen  int
+		release  = make(chan struct{})
+		started  = make(chan struct{}, 10)
+		finished sync.WaitGroup
+	)
+	wishlistRepo.On("FindMatchingItems", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		mu.Lock()
+		running++
+		if running > maxSeen {
+			maxSeen = running
+		}
+		mu.Unlock()
+		started <- struct{}{}
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}).Return([]*models.WishlistItem{}, nil)
+
+	for i := 0; i < 5; i++ {
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			svc.CheckAndNotifyMatches(context.Background(), makeListingWithStats())
+		}()
+	}
+
+	// Two runs get a slot; the rest wait for one to free up
+	<-started
+	<-started
+	select {
+	case <-started:
+		t.Fatal("more listings matched concurrently than the limit allows")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	finished.Wait()
+
+	assert.Equal(t, 2, maxSeen)
+}
+
+func TestSetMatchConcurrency_DefaultsBelowOne(t *testing.T) {
+	svc, _, _, _ := newWishlistTestService()
+
+	svc.SetMatchConcurrency(0)
+
+	assert.Equal(t, DefaultWishlistMatchConcurrency, cap(svc.matchSlots))
+}
+
+// ---------- Match grouping ----------
+
+func TestCheckAndNotifyMatches_GroupsMatchesPerUser(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetMatchGrouping(redisClient, time.Hour)
+	ctx := context.Background()
+
+	first := makeListingWithStats()
+	second := makeListingWithStats()
+	second.ID = "listing-2"
+	candidate := testWishlistItem("wl-1", "user-abc")
+	candidate.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, mock.Anything).Return([]*models.WishlistItem{candidate}, nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, first)
+	svc.CheckAndNotifyMatches(ctx, second)
+
+	// Nothing is sent until the window closes
+	notifRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	svc.flushMatches(ctx, "user-abc")
+
+	require.Len(t, notifRepo.Calls, 1)
+	n := notifRepo.Calls[0].Arguments.Get(1).(*models.Notification)
+	assert.Equal(t, "user-abc", n.UserID)
+	assert.Equal(t, models.NotificationTypeWishlistMatch, n.Type)
+	assert.Equal(t, "2 items matched your wishlist!", n.GetBody())
+	assert.Nil(t, n.ReferenceID)
+	assert.JSONEq(t, `{"matches":[
+		{"wishlistItemId":"wl-1","listingId":"`+testListingID+`"},
+		{"wishlistItemId":"wl-1","listingId":"listing-2"}
+	]}`, string(n.Metadata))
+
+	// The buffer is emptied by the flush
+	svc.flushMatches(ctx, "user-abc")
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCheckAndNotifyMatches_GroupedSingleMatchKeepsReference(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetMatchGrouping(redisClient, time.Hour)
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+	candidate := testWishlistItem("wl-1", "user-abc")
+	candidate.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
 	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
 
 	svc.CheckAndNotifyMatches(ctx, listing)
+	svc.flushMatches(ctx, "user-abc")
+
+	require.Len(t, notifRepo.Calls, 1)
+	n := notifRepo.Calls[0].Arguments.Get(1).(*models.Notification)
+	assert.Equal(t, testListingID, n.GetReferenceID())
+	assert.Equal(t, "Wishlist Match Found", n.Title)
+	assert.JSONEq(t, `{"wishlistItemId":"wl-1","listingId":"`+testListingID+`"}`, string(n.Metadata))
+}
+
+func TestCheckAndNotifyMatches_GroupedKeepsEachWishlistItem(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	redisClient, _ := newTestRedisReal(t)
+	svc.SetMatchGrouping(redisClient, time.Hour)
+	ctx := context.Background()
 
-	// Both candidates should trigger notifications.
-	assert.Equal(t, 2, len(notifRepo.Calls))
+	listing := makeListingWithStats()
+	// Two wishlist items matching the same listing are grouped like any other matches
+	candidate1 := testWishlistItem("wl-1", "user-abc")
+	candidate1.StatCriteria = nil
+	candidate2 := testWishlistItem("wl-2", "user-abc")
+	candidate2.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate1, candidate2}, nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+	svc.flushMatches(ctx, "user-abc")
+
+	require.Len(t, notifRepo.Calls, 1)
+	n := notifRepo.Calls[0].Arguments.Get(1).(*models.Notification)
+	assert.JSONEq(t, `{"matches":[
+		{"wishlistItemId":"wl-1","listingId":"`+testListingID+`"},
+		{"wishlistItemId":"wl-2","listingId":"`+testListingID+`"}
+	]}`, string(n.Metadata))
+}
+
+func TestFlushDueMatches_FlushesOnceWindowCloses(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	redisClient, mr := newTestRedisReal(t)
+	svc.SetMatchGrouping(redisClient, time.Hour)
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+	candidate := testWishlistItem("wl-1", "user-abc")
+	candidate.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	// Still inside the window
+	n, err := svc.FlushDueMatches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// The window closes (e.g. after a restart that would have lost an in-process timer)
+	_, err = mr.ZAdd(cache.WishlistMatchDueKey(), float64(time.Now().Add(-time.Second).Unix()), "user-abc")
+	require.NoError(t, err)
+
+	n, err = svc.FlushDueMatches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	// The user is no longer due, so another run sends nothing
+	n, err = svc.FlushDueMatches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	notifRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCheckAndNotifyMatches_GroupingNeedsRedis(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	svc.SetMatchGrouping(nil, time.Hour)
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+	candidate := testWishlistItem("wl-1", "user-abc")
+	candidate.StatCriteria = nil
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+	notifRepo.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification")).Return(nil)
+
+	svc.CheckAndNotifyMatches(ctx, listing)
+
+	notifRepo.AssertCalled(t, "CreateBatch", mock.Anything, mock.AnythingOfType("[]*models.Notification"))
+}
+
+// ---------- CountMatches ----------
+
+func TestCountMatches_CountsDistinctMatchingUsers(t *testing.T) {
+	svc, wishlistRepo, _, notifRepo := newWishlistTestService()
+	ctx := context.Background()
+
+	listing := makeListingWithStats()
+
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{
+		testWishlistItem("wl-1", "user-abc", withStatCriteria([]models.StatCriterion{
+			{Code: "ed%", MinValue: intPtr(150)},
+		})),
+		// Same user, second matching item: counted once
+		testWishlistItem("wl-2", "user-abc", withStatCriteria([]models.StatCriterion{
+			{Code: "ac%", MinValue: intPtr(50)},
+		})),
+		testWishlistItem("wl-3", "user-xyz", withStatCriteria([]models.StatCriterion{
+			{Code: "ac%", MinValue: intPtr(50)},
+		})),
+		// Fails the min value
+		testWishlistItem("wl-4", "user-def", withStatCriteria([]models.StatCriterion{
+			{Code: "ed%", MinValue: intPtr(200)},
+		})),
+	}, nil)
+
+	count, err := svc.CountMatches(ctx, listing)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Empty(t, notifRepo.Calls)
+}
+
+func TestCountMatches_MalformedStatsMatchNothing(t *testing.T) {
+	svc, wishlistRepo, _, _ := newWishlistTestService()
+	ctx := context.Background()
+
+	listing := testListing(testListingID, testSellerID, withStats(json.RawMessage(`{"not":"an array"}`)))
+	candidate := testWishlistItem("wl-1", "user-abc")
+	candidate.StatCriteria = nil
+	wishlistRepo.On("FindMatchingItems", ctx, listing).Return([]*models.WishlistItem{candidate}, nil)
+
+	count, err := svc.CountMatches(ctx, listing)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
 }
 
 // ---------- matchesStatCriteria (direct, same package) ----------
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage writes files to a directory on disk. It backs sandbox mode so uploads
+// work end to end without touching the real buckets.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage rooted at dir, creating it if needed. Public URLs are
+// baseURL joined with the file path, or file:// URLs when baseURL is empty.
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	if baseURL == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve storage directory: %w", err)
+		}
+		baseURL = "file://" + filepath.ToSlash(abs)
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// UploadImage writes the image under the storage directory and returns its public URL
+func (s *LocalStorage) UploadImage(ctx context.Context, path string, data []byte, contentType string) (string, error) {
+	full, err := s.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return "", fmt.Errorf("failed to upload: %w", err)
+	}
+	if err := os.WriteFile(full, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to upload: %w", err)
+	}
+
+	return s.GetPublicURL(path), nil
+}
+
+// FileExists checks if a file exists under the storage directory
+func (s *LocalStorage) FileExists(ctx context.Context, path string) (bool, error) {
+	full, err := s.resolve(path)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(full)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check file: %w", err)
+	}
+	return true, nil
+}
+
+// GetPublicURL returns the public URL for a file path
+func (s *LocalStorage) GetPublicURL(path string) string {
+	return fmt.Sprintf("%s/%s", s.baseURL, strings.TrimPrefix(path, "/"))
+}
+
+// resolve maps a storage path to a file inside the storage directory, refusing paths
+// that would escape it
+func (s *LocalStorage) resolve(path string) (string, error) {
+	clean := filepath.Clean("/" + path)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid path: %q", path)
+	}
+	return filepath.Join(s.dir, clean), nil
+}